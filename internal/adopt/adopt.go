@@ -0,0 +1,302 @@
+// Package adopt analyzes Terraform repositories that don't follow the
+// components/bases/projects layout and plans their reorganization.
+package adopt
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/sources"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+)
+
+// Module types, matching the layout directories they are moved to
+const (
+	TypeComponent = "component"
+	TypeBase      = "base"
+	TypeProject   = "project"
+)
+
+// bundledDirs are subdirectories that belong to the module containing them
+// and move with it.
+var bundledDirs = []string{"examples", "modules", "tests", "test"}
+
+// skipDirs are never searched for modules
+var skipDirs = []string{".terraform", "node_modules", "vendor"}
+
+// Options configures Analyze.
+type Options struct {
+	TypeDirs  map[string]string // Layout directory per module type, e.g. "component" -> "components"
+	Overrides map[string]string // Target per module directory, replacing the proposal
+}
+
+// Move is the proposed new location of a module directory.
+type Move struct {
+	From   string `json:"from"` // Slash-separated, relative to the repository root
+	To     string `json:"to"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// Rewrite is a local module source that changes when modules are moved.
+type Rewrite struct {
+	File   string `json:"file"`   // File after the moves, relative to the repository root
+	Module string `json:"module"` // Name of the module block
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// Plan is the proposed reorganization of a repository.
+type Plan struct {
+	Moves    []Move    `json:"moves"`
+	Rewrites []Rewrite `json:"rewrites,omitempty"`
+}
+
+// Analyze finds the module directories under root outside the layout
+// directories, classifies them, and proposes where to move them:
+//   - project: has a backend or cloud block, or configures providers
+//   - base: composes other local modules
+//   - component: any other (reusable) module
+//
+// Subdirectories of a module named examples, modules, or tests move with it.
+func Analyze(root string, opts Options) (*Plan, error) {
+	dirs, err := findModuleDirs(root, opts.TypeDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	for _, dir := range dirs {
+		modType, reason, err := classify(filepath.Join(root, filepath.FromSlash(dir)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze %s: %w", dir, err)
+		}
+		plan.Moves = append(plan.Moves, Move{From: dir, Type: modType, Reason: reason})
+	}
+
+	if err := plan.assignTargets(root, opts); err != nil {
+		return nil, err
+	}
+	if plan.Rewrites, err = FindRewrites(root, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// findModuleDirs returns the directories under root that contain .tf or .tf.json files,
+// without descending into modules, the layout directories, or hidden directories.
+func findModuleDirs(root string, typeDirs map[string]string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") || slices.Contains(skipDirs, d.Name()) || isLayoutDir(rel, typeDirs) {
+			return filepath.SkipDir
+		}
+
+		hasTF, err := containsTerraform(p)
+		if err != nil {
+			return err
+		}
+		if hasTF {
+			dirs = append(dirs, rel)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", root, err)
+	}
+	return dirs, nil
+}
+
+// isLayoutDir reports whether rel is one of the layout directories.
+func isLayoutDir(rel string, typeDirs map[string]string) bool {
+	for _, dir := range typeDirs {
+		if rel == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTerraform reports whether dir directly contains .tf or .tf.json files.
+func containsTerraform(dir string) (bool, error) {
+	files, err := terraformFiles(dir)
+	return len(files) > 0, err
+}
+
+// terraformFiles returns the .tf and .tf.json files directly in dir.
+func terraformFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && finder.IsTerraformFile(entry.Name()) {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// classify determines the module type of dir and the reason for it.
+func classify(dir string) (string, string, error) {
+	module, diags := tfconfig.LoadModule(dir)
+	if diags.HasErrors() {
+		return "", "", diags.Err()
+	}
+
+	backend, err := hasBackend(dir)
+	if err != nil {
+		return "", "", err
+	}
+	switch {
+	case backend:
+		return TypeProject, "has a backend", nil
+	case len(module.ProviderConfigs) > 0:
+		return TypeProject, "configures providers", nil
+	}
+
+	for _, call := range module.ModuleCalls {
+		if sources.IsLocal(call.Source) {
+			return TypeBase, "composes local modules", nil
+		}
+	}
+	return TypeComponent, "reusable module", nil
+}
+
+// terraformSchema selects the terraform blocks of a configuration file
+var terraformSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{{Type: "terraform"}},
+}
+
+// backendSchema selects the backend and cloud blocks of a terraform block
+var backendSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{{Type: "backend", LabelNames: []string{"type"}}, {Type: "cloud"}},
+}
+
+// hasBackend reports whether a .tf or .tf.json file in dir has a terraform
+// block with a backend or cloud block.
+func hasBackend(dir string) (bool, error) {
+	files, err := terraformFiles(dir)
+	if err != nil {
+		return false, err
+	}
+	for _, file := range files {
+		f, err := sources.ParseFile(file)
+		if err != nil {
+			return false, err
+		}
+		content, _, _ := f.Body.PartialContent(terraformSchema)
+		for _, block := range content.Blocks {
+			nested, _, _ := block.Body.PartialContent(backendSchema)
+			if len(nested.Blocks) > 0 {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// assignTargets sets the target of each move: the override if there is one,
+// otherwise <layout dir>/<name>. Names that occur more than once in a layout
+// directory are qualified with their parent directories.
+func (p *Plan) assignTargets(root string, opts Options) error {
+	for from, to := range opts.Overrides {
+		if !slices.ContainsFunc(p.Moves, func(m Move) bool { return m.From == from }) {
+			return fmt.Errorf("--map source '%s' is not a module directory found by adopt", from)
+		}
+		if typeOfTarget(to, opts.TypeDirs) == "" {
+			return fmt.Errorf("--map target '%s' must be inside %s", to, strings.Join(sortedValues(opts.TypeDirs), ", "))
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, m := range p.Moves {
+		if _, ok := opts.Overrides[m.From]; !ok {
+			counts[m.Type+"/"+path.Base(m.From)]++
+		}
+	}
+
+	targets := make(map[string]string)
+	for i := range p.Moves {
+		m := &p.Moves[i]
+		if to, ok := opts.Overrides[m.From]; ok {
+			m.To = path.Clean(to)
+			m.Type = typeOfTarget(m.To, opts.TypeDirs)
+			m.Reason = "mapped with --map"
+		} else {
+			name := path.Base(m.From)
+			if counts[m.Type+"/"+name] > 1 {
+				name = strings.ReplaceAll(m.From, "/", "-")
+			}
+			m.To = path.Join(opts.TypeDirs[m.Type], name)
+		}
+
+		if other, ok := targets[m.To]; ok {
+			return fmt.Errorf("%s and %s would both move to %s, use --map to choose another target", other, m.From, m.To)
+		}
+		targets[m.To] = m.From
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(m.To))); err == nil {
+			return fmt.Errorf("cannot move %s: %s already exists", m.From, m.To)
+		}
+	}
+	return nil
+}
+
+// typeOfTarget returns the module type of the layout directory target is in,
+// or "" if it isn't inside one.
+func typeOfTarget(target string, typeDirs map[string]string) string {
+	target = path.Clean(target)
+	for modType, dir := range typeDirs {
+		if strings.HasPrefix(target, dir+"/") {
+			return modType
+		}
+	}
+	return ""
+}
+
+func sortedValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// MapPath returns where rel (slash-separated, relative to the repository
+// root) ends up after the moves.
+func (p *Plan) MapPath(rel string) string {
+	for _, m := range p.Moves {
+		if rel == m.From {
+			return m.To
+		}
+		if rest, ok := strings.CutPrefix(rel, m.From+"/"); ok {
+			return m.To + "/" + rest
+		}
+	}
+	return rel
+}
@@ -0,0 +1,211 @@
+package adopt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var typeDirs = map[string]string{
+	TypeComponent: "components",
+	TypeBase:      "bases",
+	TypeProject:   "projects",
+}
+
+// writeFiles creates files under root from a map of slash-separated paths to contents.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func legacyRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"modules/network/main.tf":                `variable "name" {}`,
+		"modules/storage/main.tf":                "module \"net\" {\n  source = \"../network\" # Keep this comment\n}\n",
+		"modules/storage/examples/basic/main.tf": `module "storage" { source = "../.." }`,
+		"envs/prod/main.tf":                      "terraform {\n  backend \"s3\" {}\n}\n\nmodule \"storage\" {\n  source = \"../../modules/storage\"\n}\n",
+		"envs/dev/network/main.tf":               `provider "aws" {}`,
+		"teams/network/main.tf":                  `variable "team" {}`,
+		"components/existing/main.tf":            `module "net" { source = "../../modules/network" }`,
+		".terraform/modules/cached/main.tf":      `variable "x" {}`,
+	})
+	return root
+}
+
+func TestAnalyze_Classifies(t *testing.T) {
+	plan, err := Analyze(legacyRepo(t), Options{TypeDirs: typeDirs})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	want := map[string]Move{
+		"envs/dev/network": {To: "projects/network", Type: TypeProject},
+		"envs/prod":        {To: "projects/prod", Type: TypeProject},
+		"modules/network":  {To: "components/modules-network", Type: TypeComponent}, // Name clash with teams/network
+		"modules/storage":  {To: "bases/storage", Type: TypeBase},
+		"teams/network":    {To: "components/teams-network", Type: TypeComponent},
+	}
+	if len(plan.Moves) != len(want) {
+		t.Fatalf("moves = %+v, want %d moves", plan.Moves, len(want))
+	}
+	for _, m := range plan.Moves {
+		w, ok := want[m.From]
+		if !ok {
+			t.Errorf("unexpected move of %s", m.From)
+			continue
+		}
+		if m.To != w.To || m.Type != w.Type {
+			t.Errorf("%s: got %s (%s), want %s (%s)", m.From, m.To, m.Type, w.To, w.Type)
+		}
+	}
+}
+
+func TestAnalyze_Rewrites(t *testing.T) {
+	plan, err := Analyze(legacyRepo(t), Options{TypeDirs: typeDirs})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	var got []string
+	for _, r := range plan.Rewrites {
+		got = append(got, r.File+" "+r.Module+" "+r.To)
+	}
+	want := []string{
+		"components/existing/main.tf net ../modules-network",
+		"projects/prod/main.tf storage ../../bases/storage",
+		"bases/storage/main.tf net ../../components/modules-network",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("rewrites =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAnalyze_Overrides(t *testing.T) {
+	root := legacyRepo(t)
+	plan, err := Analyze(root, Options{TypeDirs: typeDirs, Overrides: map[string]string{
+		"teams/network": "bases/team-network",
+	}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	for _, m := range plan.Moves {
+		switch m.From {
+		case "teams/network":
+			if m.To != "bases/team-network" || m.Type != TypeBase {
+				t.Errorf("override: got %s (%s)", m.To, m.Type)
+			}
+		case "modules/network":
+			if m.To != "components/network" {
+				t.Errorf("without the clash, modules/network should move to components/network, got %s", m.To)
+			}
+		}
+	}
+
+	_, err = Analyze(root, Options{TypeDirs: typeDirs, Overrides: map[string]string{"teams/network": "lib/network"}})
+	if err == nil || !strings.Contains(err.Error(), "must be inside") {
+		t.Errorf("expected target outside layout error, got %v", err)
+	}
+	_, err = Analyze(root, Options{TypeDirs: typeDirs, Overrides: map[string]string{"missing": "components/x"}})
+	if err == nil || !strings.Contains(err.Error(), "not a module directory") {
+		t.Errorf("expected unknown source error, got %v", err)
+	}
+}
+
+func TestAnalyze_TargetExists(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"modules/existing/main.tf":    `variable "x" {}`,
+		"components/existing/main.tf": `variable "x" {}`,
+	})
+	_, err := Analyze(root, Options{TypeDirs: typeDirs})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected target exists error, got %v", err)
+	}
+}
+
+func TestApplyRewrites_PreservesComments(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"bases/storage/main.tf": "module \"net\" {\n  source = \"../network\" # Keep this comment\n}\n",
+	})
+
+	err := ApplyRewrites(root, []Rewrite{{File: "bases/storage/main.tf", Module: "net", From: "../network", To: "../../components/network"}})
+	if err != nil {
+		t.Fatalf("ApplyRewrites() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "bases", "storage", "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "module \"net\" {\n  source = \"../../components/network\" # Keep this comment\n}\n"
+	if string(data) != want {
+		t.Errorf("file =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestAnalyze_JSONModules(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"modules/network/main.tf.json": `{"variable": {"name": {}}}`,
+		"modules/storage/main.tf.json": `{"module": {"net": {"source": "../network"}}}`,
+		"envs/prod/main.tf.json":       `{"terraform": {"backend": {"s3": {}}}, "module": {"storage": {"source": "../../modules/storage"}}}`,
+	})
+
+	plan, err := Analyze(root, Options{TypeDirs: typeDirs})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	want := map[string]string{
+		"envs/prod":       "projects/prod",
+		"modules/network": "components/network",
+		"modules/storage": "bases/storage",
+	}
+	if len(plan.Moves) != len(want) {
+		t.Fatalf("moves = %+v, want %d moves", plan.Moves, len(want))
+	}
+	for _, m := range plan.Moves {
+		if m.To != want[m.From] {
+			t.Errorf("%s: got %s, want %s", m.From, m.To, want[m.From])
+		}
+	}
+	if len(plan.Rewrites) != 2 {
+		t.Errorf("rewrites = %+v, want 2", plan.Rewrites)
+	}
+}
+
+func TestApplyRewrites_JSON(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"bases/storage/main.tf.json": "{\n  \"module\": {\n    \"net\": { \"source\": \"../network\" },\n    \"dns\": { \"source\": \"../dns\" }\n  }\n}\n",
+	})
+
+	err := ApplyRewrites(root, []Rewrite{
+		{File: "bases/storage/main.tf.json", Module: "net", From: "../network", To: "../../components/network"},
+		{File: "bases/storage/main.tf.json", Module: "dns", From: "../dns", To: "../../components/dns"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyRewrites() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "bases", "storage", "main.tf.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"module\": {\n    \"net\": { \"source\": \"../../components/network\" },\n    \"dns\": { \"source\": \"../../components/dns\" }\n  }\n}\n"
+	if string(data) != want {
+		t.Errorf("file =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -0,0 +1,182 @@
+package adopt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/hclform"
+	"github.com/TechnicallyJoe/terraform-motf/internal/sources"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// FindRewrites returns the local module sources in all .tf and .tf.json files under root
+// whose relative path changes when the plan's moves are applied.
+func FindRewrites(root string, plan *Plan) ([]Rewrite, error) {
+	var rewrites []Rewrite
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && (strings.HasPrefix(d.Name(), ".") || slices.Contains(skipDirs, d.Name())) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !finder.IsTerraformFile(d.Name()) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		fileRewrites, err := rewritesForFile(p, rel, plan)
+		if err != nil {
+			return err
+		}
+		rewrites = append(rewrites, fileRewrites...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rewrites, nil
+}
+
+// rewritesForFile returns the rewrites of the module sources in the file at
+// rel: each local source is resolved from the file's current directory and
+// made relative again from its new one.
+func rewritesForFile(file, rel string, plan *Plan) ([]Rewrite, error) {
+	f, err := sources.ParseFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", rel, err)
+	}
+
+	var rewrites []Rewrite
+	oldDir := path.Dir(rel)
+	newFile := plan.MapPath(rel)
+	newDir := path.Dir(newFile)
+	for _, call := range sources.ModuleCalls(f) {
+		if !sources.IsLocal(call.Source) {
+			continue
+		}
+
+		target := plan.MapPath(path.Join(oldDir, call.Source))
+		newSource, err := relativeSource(newDir, target)
+		if err != nil {
+			return nil, fmt.Errorf("%s: module %s: %w", rel, call.Name, err)
+		}
+		if newSource != call.Source {
+			rewrites = append(rewrites, Rewrite{File: newFile, Module: call.Name, From: call.Source, To: newSource})
+		}
+	}
+	return rewrites, nil
+}
+
+// relativeSource returns the local module source that refers to target from dir.
+func relativeSource(dir, target string) (string, error) {
+	rel, err := filepath.Rel(filepath.FromSlash(dir), filepath.FromSlash(target))
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel, nil
+}
+
+// ApplyRewrites updates the module sources in the files under root, after
+// the moves have been made. Formatting and comments are preserved, also in
+// .tf.json files.
+func ApplyRewrites(root string, rewrites []Rewrite) error {
+	byFile := make(map[string][]Rewrite)
+	var files []string
+	for _, r := range rewrites {
+		if _, ok := byFile[r.File]; !ok {
+			files = append(files, r.File)
+		}
+		byFile[r.File] = append(byFile[r.File], r)
+	}
+
+	for _, rel := range files {
+		file := filepath.Join(root, filepath.FromSlash(rel))
+		src, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return err
+		}
+		rewrite := rewriteHCL
+		if strings.HasSuffix(rel, ".json") {
+			rewrite = rewriteJSON
+		}
+		out, err := rewrite(file, src, byFile[rel])
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, out, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// rewriteHCL sets the sources of the rewritten module blocks in a .tf file.
+func rewriteHCL(file string, src []byte, rewrites []Rewrite) ([]byte, error) {
+	f, diags := hclwrite.ParseConfig(src, file, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse: %w", diags)
+	}
+	for _, r := range rewrites {
+		block := f.Body().FirstMatchingBlock("module", []string{r.Module})
+		if block == nil {
+			return nil, fmt.Errorf("module %s not found", r.Module)
+		}
+		block.Body().SetAttributeValue("source", cty.StringVal(r.To))
+	}
+	return f.Bytes(), nil
+}
+
+// rewriteJSON replaces the source strings of the rewritten module blocks in a
+// .tf.json file, leaving the rest of the file untouched.
+func rewriteJSON(file string, src []byte, rewrites []Rewrite) ([]byte, error) {
+	f, diags := hcljson.Parse(src, file)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse: %w", diags)
+	}
+	calls := make(map[string]sources.ModuleCall)
+	for _, call := range sources.ModuleCalls(f) {
+		calls[call.Name] = call
+	}
+
+	var edits []hclform.Edit
+	for _, r := range rewrites {
+		call, ok := calls[r.Module]
+		if !ok {
+			return nil, fmt.Errorf("module %s not found", r.Module)
+		}
+		value, err := json.Marshal(r.To)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, hclform.Edit{Start: call.SourceRange.Start.Byte, End: call.SourceRange.End.Byte, Text: value})
+	}
+	out := hclform.Splice(src, edits)
+	return out, nil
+}
@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"sync"
+)
+
+// Event names sent back to clients
+const (
+	EventOutput = "output" // a line of streamed command output
+	EventResult = "result" // the final result of a request
+	EventError  = "error"  // the request failed
+)
+
+// Request is a single newline-delimited JSON request sent by a client.
+type Request struct {
+	ID     int             `json:"id"`
+	Token  string          `json:"token"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a single newline-delimited JSON event sent to a client.
+// A request produces zero or more output events followed by exactly one
+// result or error event.
+type Response struct {
+	ID     int    `json:"id"`
+	Event  string `json:"event"`
+	Output string `json:"output,omitempty"`
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Handler executes a method. Anything written to out is streamed to the
+// client as output events. The returned value is sent as the result.
+type Handler func(params json.RawMessage, out io.Writer) (any, error)
+
+// Server serves motf operations over a stream listener (typically a unix socket).
+type Server struct {
+	token    string
+	handlers map[string]Handler
+}
+
+// NewServer creates a Server that requires every request to carry token.
+func NewServer(token string) *Server {
+	return &Server{
+		token:    token,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Handle registers a handler for the given method name.
+func (s *Server) Handle(method string, h Handler) {
+	s.handlers[method] = h
+}
+
+// Methods returns the sorted list of registered method names.
+func (s *Server) Methods() []string {
+	names := make([]string, 0, len(s.handlers))
+	for name := range s.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Serve accepts connections on l until it is closed.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn processes requests from a single connection sequentially until
+// the client disconnects.
+func (s *Server) ServeConn(conn io.ReadWriteCloser) {
+	defer func() { _ = conn.Close() }()
+
+	enc := &encoder{enc: json.NewEncoder(conn)}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			enc.send(Response{Event: EventError, Error: fmt.Sprintf("invalid request: %v", err)})
+			continue
+		}
+		s.dispatch(req, enc)
+	}
+}
+
+// dispatch authenticates and runs a single request.
+func (s *Server) dispatch(req Request, enc *encoder) {
+	if !s.authorized(req.Token) {
+		enc.send(Response{ID: req.ID, Event: EventError, Error: "unauthorized"})
+		return
+	}
+
+	h, ok := s.handlers[req.Method]
+	if !ok {
+		enc.send(Response{ID: req.ID, Event: EventError, Error: fmt.Sprintf("unknown method '%s'", req.Method)})
+		return
+	}
+
+	out := &outputWriter{id: req.ID, enc: enc}
+	result, err := h(req.Params, out)
+	out.flush()
+
+	if err != nil {
+		enc.send(Response{ID: req.ID, Event: EventError, Error: err.Error()})
+		return
+	}
+	enc.send(Response{ID: req.ID, Event: EventResult, Result: result})
+}
+
+// authorized compares tokens in constant time.
+func (s *Server) authorized(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(s.token), []byte(token)) == 1
+}
+
+// encoder serializes writes of responses to a connection.
+type encoder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (e *encoder) send(resp Response) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_ = e.enc.Encode(resp)
+}
+
+// outputWriter turns written bytes into one output event per line.
+type outputWriter struct {
+	mu  sync.Mutex
+	id  int
+	enc *encoder
+	buf []byte
+}
+
+// Write implements io.Writer.
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		w.enc.send(Response{ID: w.id, Event: EventOutput, Output: string(w.buf[:idx])})
+		w.buf = w.buf[idx+1:]
+	}
+	return len(p), nil
+}
+
+// flush sends any trailing partial line.
+func (w *outputWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.enc.send(Response{ID: w.id, Event: EventOutput, Output: string(w.buf)})
+		w.buf = nil
+	}
+}
@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+)
+
+// startConn serves a single in-memory connection and returns the client side.
+func startConn(t *testing.T, s *Server) (*json.Encoder, *bufio.Scanner) {
+	t.Helper()
+	client, server := net.Pipe()
+	go s.ServeConn(server)
+	t.Cleanup(func() { _ = client.Close() })
+	return json.NewEncoder(client), bufio.NewScanner(client)
+}
+
+// readResponse reads the next response event from the scanner.
+func readResponse(t *testing.T, scanner *bufio.Scanner) Response {
+	t.Helper()
+	if !scanner.Scan() {
+		t.Fatalf("expected a response, got EOF (err: %v)", scanner.Err())
+	}
+	var resp Response
+	if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp
+}
+
+func TestServer_RejectsInvalidToken(t *testing.T) {
+	s := NewServer("secret")
+	called := false
+	s.Handle("list", func(params json.RawMessage, out io.Writer) (any, error) {
+		called = true
+		return nil, nil
+	})
+
+	enc, scanner := startConn(t, s)
+	go func() { _ = enc.Encode(Request{ID: 1, Token: "wrong", Method: "list"}) }()
+
+	resp := readResponse(t, scanner)
+	if resp.Event != EventError || resp.Error != "unauthorized" {
+		t.Errorf("expected unauthorized error, got %+v", resp)
+	}
+	if called {
+		t.Error("handler should not run for unauthorized requests")
+	}
+}
+
+func TestServer_UnknownMethod(t *testing.T) {
+	s := NewServer("secret")
+
+	enc, scanner := startConn(t, s)
+	go func() { _ = enc.Encode(Request{ID: 7, Token: "secret", Method: "destroy"}) }()
+
+	resp := readResponse(t, scanner)
+	if resp.ID != 7 || resp.Event != EventError {
+		t.Errorf("expected error event for id 7, got %+v", resp)
+	}
+}
+
+func TestServer_StreamsOutputThenResult(t *testing.T) {
+	s := NewServer("secret")
+	s.Handle("plan", func(params json.RawMessage, out io.Writer) (any, error) {
+		_, _ = fmt.Fprint(out, "line one\nline ")
+		_, _ = fmt.Fprint(out, "two\npartial")
+		return map[string]string{"status": "ok"}, nil
+	})
+
+	enc, scanner := startConn(t, s)
+	go func() { _ = enc.Encode(Request{ID: 2, Token: "secret", Method: "plan"}) }()
+
+	wantOutput := []string{"line one", "line two", "partial"}
+	for _, want := range wantOutput {
+		resp := readResponse(t, scanner)
+		if resp.Event != EventOutput || resp.Output != want {
+			t.Errorf("expected output %q, got %+v", want, resp)
+		}
+	}
+
+	resp := readResponse(t, scanner)
+	if resp.Event != EventResult {
+		t.Fatalf("expected result event, got %+v", resp)
+	}
+	result, ok := resp.Result.(map[string]any)
+	if !ok || result["status"] != "ok" {
+		t.Errorf("unexpected result: %#v", resp.Result)
+	}
+}
+
+func TestServer_HandlerError(t *testing.T) {
+	s := NewServer("secret")
+	s.Handle("plan", func(params json.RawMessage, out io.Writer) (any, error) {
+		return nil, fmt.Errorf("plan failed")
+	})
+
+	enc, scanner := startConn(t, s)
+	go func() { _ = enc.Encode(Request{ID: 3, Token: "secret", Method: "plan"}) }()
+
+	resp := readResponse(t, scanner)
+	if resp.Event != EventError || resp.Error != "plan failed" {
+		t.Errorf("expected plan failed error, got %+v", resp)
+	}
+}
+
+func TestServer_Methods(t *testing.T) {
+	s := NewServer("secret")
+	s.Handle("plan", nil)
+	s.Handle("list", nil)
+
+	methods := s.Methods()
+	if len(methods) != 2 || methods[0] != "list" || methods[1] != "plan" {
+		t.Errorf("expected [list plan], got %v", methods)
+	}
+}
@@ -0,0 +1,232 @@
+// Package backend renders the remote state backend configuration of projects
+// from the templates in the backend section of .motf.yml, for 'motf backend
+// init'.
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// requiredSettings are the settings each supported backend type needs
+var requiredSettings = map[string][]string{
+	"azurerm": {"resource_group_name", "storage_account_name", "container_name", "key"},
+	"s3":      {"bucket", "key", "region"},
+	"gcs":     {"bucket"},
+}
+
+// Placeholders are the names that can be used as {name} in setting values
+var Placeholders = []string{"project", "path", "env"}
+
+// placeholderPattern matches {name} placeholders in setting values
+var placeholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// Types returns the supported backend types, sorted.
+func Types() []string {
+	types := make([]string, 0, len(requiredSettings))
+	for t := range requiredSettings {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Validate checks that the backend type is supported, that its required
+// settings are set, and that values are strings, numbers, or booleans with
+// known placeholders.
+func Validate(backendType string, settings map[string]any) error {
+	required, ok := requiredSettings[backendType]
+	if !ok {
+		return fmt.Errorf("unsupported type '%s'", backendType)
+	}
+	var missing []string
+	for _, name := range required {
+		if _, ok := settings[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s backend needs %s", backendType, strings.Join(missing, ", "))
+	}
+
+	for _, name := range sortedNames(settings) {
+		switch v := settings[name].(type) {
+		case string:
+			for _, m := range placeholderPattern.FindAllStringSubmatch(v, -1) {
+				if !isPlaceholder(m[1]) {
+					return fmt.Errorf("%s has unknown placeholder {%s}: must be {%s}", name, m[1], strings.Join(Placeholders, "}, {"))
+				}
+			}
+		case bool, int, float64:
+		default:
+			return fmt.Errorf("%s must be a string, number, or boolean", name)
+		}
+	}
+	return nil
+}
+
+func isPlaceholder(name string) bool {
+	for _, p := range Placeholders {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// usesPlaceholder reports whether any setting value contains {name}.
+func usesPlaceholder(settings map[string]any, name string) bool {
+	for _, v := range settings {
+		if s, ok := v.(string); ok && strings.Contains(s, "{"+name+"}") {
+			return true
+		}
+	}
+	return false
+}
+
+// Params are the values of the placeholders for a project.
+type Params struct {
+	Project string // Replaces {project}: the project name
+	Path    string // Replaces {path}: the project path relative to the root
+	Env     string // Replaces {env}: the environment
+}
+
+// lookup returns the value of a placeholder.
+func (p Params) lookup(name string) string {
+	switch name {
+	case "project":
+		return p.Project
+	case "path":
+		return p.Path
+	case "env":
+		return p.Env
+	}
+	return ""
+}
+
+// Setting is a rendered backend setting.
+type Setting struct {
+	Name  string
+	Value cty.Value
+}
+
+// Render replaces the placeholders in the settings with params, returning
+// them sorted by name. Placeholders without a value are an error.
+func Render(settings map[string]any, params Params) ([]Setting, error) {
+	var rendered []Setting
+	var errs []error
+	for _, name := range sortedNames(settings) {
+		var value cty.Value
+		switch v := settings[name].(type) {
+		case string:
+			s := placeholderPattern.ReplaceAllStringFunc(v, func(m string) string {
+				placeholder := m[1 : len(m)-1]
+				value := params.lookup(placeholder)
+				if value == "" {
+					errs = append(errs, fmt.Errorf("%s uses {%s}, which has no value", name, placeholder))
+				}
+				return value
+			})
+			value = cty.StringVal(s)
+		case bool:
+			value = cty.BoolVal(v)
+		case int:
+			value = cty.NumberIntVal(int64(v))
+		case float64:
+			value = cty.NumberFloatVal(v)
+		default:
+			return nil, fmt.Errorf("%s must be a string, number, or boolean", name)
+		}
+		rendered = append(rendered, Setting{Name: name, Value: value})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return rendered, nil
+}
+
+// header marks generated files
+const header = "# Generated by motf backend init from the backend section of .motf.yml.\n# Changes are overwritten; edit .motf.yml instead.\n\n"
+
+// BackendFile returns a backend.tf with a backend block of the type holding
+// settings. A partial configuration is completed with -backend-config.
+func BackendFile(backendType string, settings []Setting) []byte {
+	f := hclwrite.NewEmptyFile()
+	terraform := f.Body().AppendNewBlock("terraform", nil)
+	backend := terraform.Body().AppendNewBlock("backend", []string{backendType})
+	for _, s := range settings {
+		backend.Body().SetAttributeValue(s.Name, s.Value)
+	}
+	return append([]byte(header), hclwrite.Format(f.Bytes())...)
+}
+
+// ConfigFile returns a backend configuration file (.tfbackend) holding
+// settings, passed to init with -backend-config.
+func ConfigFile(settings []Setting) []byte {
+	f := hclwrite.NewEmptyFile()
+	for _, s := range settings {
+		f.Body().SetAttributeValue(s.Name, s.Value)
+	}
+	return append([]byte(header), hclwrite.Format(f.Bytes())...)
+}
+
+// Files returns the backend files of a project by path relative to the
+// project. Without environments, file holds the full backend configuration.
+// With environments, file holds the settings shared by all of them and
+// configDir holds a <env>.tfbackend file per environment with the settings
+// that use {env}.
+func Files(backendType string, settings map[string]any, params Params, envs []string, file, configDir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	if len(envs) == 0 {
+		if usesPlaceholder(settings, "env") {
+			return nil, errors.New("settings use {env}, but no environments are given")
+		}
+		rendered, err := Render(settings, params)
+		if err != nil {
+			return nil, err
+		}
+		files[file] = BackendFile(backendType, rendered)
+		return files, nil
+	}
+
+	shared := make(map[string]any)
+	perEnv := make(map[string]any)
+	for name, value := range settings {
+		if s, ok := value.(string); ok && strings.Contains(s, "{env}") {
+			perEnv[name] = value
+		} else {
+			shared[name] = value
+		}
+	}
+	rendered, err := Render(shared, params)
+	if err != nil {
+		return nil, err
+	}
+	files[file] = BackendFile(backendType, rendered)
+	for _, env := range envs {
+		envParams := params
+		envParams.Env = env
+		rendered, err := Render(perEnv, envParams)
+		if err != nil {
+			return nil, fmt.Errorf("environment %s: %w", env, err)
+		}
+		files[filepath.Join(configDir, env+".tfbackend")] = ConfigFile(rendered)
+	}
+	return files, nil
+}
+
+func sortedNames(settings map[string]any) []string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		backendType string
+		settings    map[string]any
+		wantErr     string
+	}{
+		{"azurerm", "azurerm", map[string]any{"resource_group_name": "rg", "storage_account_name": "st", "container_name": "c", "key": "{project}.tfstate"}, ""},
+		{"gcs with options", "gcs", map[string]any{"bucket": "b", "prefix": "{env}/{path}", "impersonate_service_account_delegates": false}, ""},
+		{"unsupported", "consul", nil, "unsupported type 'consul'"},
+		{"missing", "azurerm", map[string]any{"key": "k"}, "azurerm backend needs resource_group_name, storage_account_name, container_name"},
+		{"unknown placeholder", "gcs", map[string]any{"bucket": "{region}"}, "bucket has unknown placeholder {region}"},
+		{"list value", "gcs", map[string]any{"bucket": []any{"a"}}, "bucket must be a string, number, or boolean"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.backendType, tt.settings)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	settings := map[string]any{"key": "{path}/{project}-{env}.tfstate", "max_retries": 3, "use_oidc": true}
+	rendered, err := Render(settings, Params{Project: "platform", Path: "projects/platform", Env: "dev"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	got := string(ConfigFile(rendered))
+	for _, want := range []string{`key         = "projects/platform/platform-dev.tfstate"`, "max_retries = 3", "use_oidc    = true"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rendered config missing %q:\n%s", want, got)
+		}
+	}
+
+	if _, err := Render(settings, Params{Project: "platform"}); err == nil || !strings.Contains(err.Error(), "key uses {path}, which has no value") {
+		t.Errorf("expected an error for placeholders without a value, got %v", err)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	settings := map[string]any{"bucket": "tfstate-{env}", "key": "{path}/terraform.tfstate", "region": "eu-west-1"}
+	params := Params{Project: "platform", Path: "projects/platform"}
+
+	files, err := Files("s3", settings, params, nil, "backend.tf", "backends")
+	if err == nil || !strings.Contains(err.Error(), "no environments") {
+		t.Fatalf("expected an error for {env} without environments, got %v (%d files)", err, len(files))
+	}
+
+	files, err = Files("s3", settings, params, []string{"dev", "prod"}, "backend.tf", "backends")
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected backend.tf and two config files, got %d", len(files))
+	}
+	backendTF := string(files["backend.tf"])
+	if !strings.Contains(backendTF, `backend "s3" {`) || !strings.Contains(backendTF, `region = "eu-west-1"`) || strings.Contains(backendTF, "bucket") {
+		t.Errorf("backend.tf should hold the shared settings, got:\n%s", backendTF)
+	}
+	if dev := string(files[filepath.Join("backends", "dev.tfbackend")]); !strings.Contains(dev, `bucket = "tfstate-dev"`) {
+		t.Errorf("dev.tfbackend should hold the dev bucket, got:\n%s", dev)
+	}
+
+	settings["bucket"] = "tfstate"
+	files, err = Files("s3", settings, params, nil, "state.tf", "backends")
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+	if full := string(files["state.tf"]); len(files) != 1 || !strings.Contains(full, `bucket = "tfstate"`) {
+		t.Errorf("expected the full configuration in state.tf, got %v", files)
+	}
+}
@@ -0,0 +1,332 @@
+package chatops
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Supported payload formats
+const (
+	FormatSlack = "slack"
+	FormatTeams = "teams"
+)
+
+// Result status values
+const (
+	StatusSucceeded   = "succeeded"
+	StatusFailed      = "failed"
+	StatusQuarantined = "quarantined" // Failed, but the module's tests are quarantined
+	StatusFlaky       = "flaky"       // Succeeded only on retry; counted as succeeded
+	StatusSkipped     = "skipped"     // Not run, e.g. over the test cost budget
+)
+
+// DefaultMaxOutputLines is the number of trailing output lines kept per module
+const DefaultMaxOutputLines = 30
+
+// Limits of the chat services; larger payloads are rejected
+const (
+	maxBlockChars  = 3000  // Characters of a Slack section text field
+	maxHeaderChars = 150   // Characters of a Slack header text field
+	maxSlackBlocks = 50    // Blocks of a Slack message
+	maxTeamsBytes  = 28000 // Bytes of a Teams message
+)
+
+// ModuleResult is the outcome of a command run on a single module.
+type ModuleResult struct {
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	Binary   string        `json:"binary,omitempty"`
+	Status   string        `json:"status"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+	Output   string        `json:"output,omitempty"`
+}
+
+// Summary is the outcome of a command run across several modules.
+type Summary struct {
+	Command string         `json:"command"`
+	Results []ModuleResult `json:"results"`
+}
+
+// Counts returns the number of succeeded and failed modules. Quarantined
+// failures and skipped modules are counted by Quarantined and Skipped instead.
+func (s Summary) Counts() (succeeded, failed int) {
+	for _, r := range s.Results {
+		switch r.Status {
+		case StatusFailed:
+			failed++
+		case StatusQuarantined, StatusSkipped:
+		default:
+			succeeded++
+		}
+	}
+	return succeeded, failed
+}
+
+// Quarantined returns the number of failed modules whose tests are quarantined.
+func (s Summary) Quarantined() int {
+	return s.countStatus(StatusQuarantined)
+}
+
+// Skipped returns the number of modules that weren't run.
+func (s Summary) Skipped() int {
+	return s.countStatus(StatusSkipped)
+}
+
+// Flaky returns the number of modules that succeeded only on retry.
+func (s Summary) Flaky() int {
+	return s.countStatus(StatusFlaky)
+}
+
+func (s Summary) countStatus(status string) int {
+	n := 0
+	for _, r := range s.Results {
+		if r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Title returns a one-line description of the run.
+func (s Summary) Title() string {
+	succeeded, failed := s.Counts()
+	title := fmt.Sprintf("motf %s: %d succeeded, %d failed", s.Command, succeeded, failed)
+	if n := s.Flaky(); n > 0 {
+		title = fmt.Sprintf("motf %s: %d succeeded (%d flaky), %d failed", s.Command, succeeded, n, failed)
+	}
+	if n := s.Quarantined(); n > 0 {
+		title += fmt.Sprintf(", %d quarantined", n)
+	}
+	if n := s.Skipped(); n > 0 {
+		title += fmt.Sprintf(", %d skipped", n)
+	}
+	return title
+}
+
+// SupportedFormats returns the list of supported payload formats.
+func SupportedFormats() []string {
+	return []string{FormatSlack, FormatTeams}
+}
+
+// Format renders the summary in the given format.
+func Format(format string, s Summary) ([]byte, error) {
+	switch format {
+	case FormatSlack:
+		return FormatSlackPayload(s)
+	case FormatTeams:
+		return FormatTeamsPayload(s)
+	default:
+		return nil, fmt.Errorf("unknown chatops format '%s', supported: %s", format, strings.Join(SupportedFormats(), ", "))
+	}
+}
+
+// FormatSlackPayload renders the summary as a Slack Block Kit message.
+// Modules that don't fit in Slack's block limit are counted in a final block.
+func FormatSlackPayload(s Summary) ([]byte, error) {
+	blocks := []map[string]any{
+		{
+			"type": "header",
+			"text": map[string]any{"type": "plain_text", "text": truncateText(s.Title(), maxHeaderChars)},
+		},
+	}
+
+	results := sortedResults(s.Results)
+	for i, r := range results {
+		moduleBlocks := slackModuleBlocks(r)
+		// Keep a block for the modules that don't fit, unless this is the last one
+		reserved := 1
+		if i == len(results)-1 {
+			reserved = 0
+		}
+		if len(blocks)+len(moduleBlocks)+reserved > maxSlackBlocks {
+			blocks = append(blocks, slackSection(moreModules(len(results)-i)))
+			break
+		}
+		blocks = append(blocks, moduleBlocks...)
+	}
+
+	return json.MarshalIndent(map[string]any{"text": s.Title(), "blocks": blocks}, "", "  ")
+}
+
+// slackModuleBlocks returns the blocks of a module result: its status, and
+// its output if any.
+func slackModuleBlocks(r ModuleResult) []map[string]any {
+	icon := ":white_check_mark:"
+	switch r.Status {
+	case StatusFailed:
+		icon = ":x:"
+	case StatusQuarantined, StatusFlaky:
+		icon = ":warning:"
+	case StatusSkipped:
+		icon = ":fast_forward:"
+	}
+	text := fmt.Sprintf("%s *%s* (`%s`%s) %s in %s", icon, r.Name, r.Path, binarySuffix(r.Binary), r.Status, formatDuration(r.Duration))
+	if r.Error != "" {
+		text += "\n" + r.Error
+	}
+	blocks := []map[string]any{slackSection(truncateText(text, maxBlockChars))}
+	if r.Output != "" {
+		blocks = append(blocks, slackSection(codeBlock(r.Output, maxBlockChars)))
+	}
+	return blocks
+}
+
+// slackSection returns a section block with the mrkdwn text.
+func slackSection(text string) map[string]any {
+	return map[string]any{
+		"type": "section",
+		"text": map[string]any{"type": "mrkdwn", "text": text},
+	}
+}
+
+// FormatTeamsPayload renders the summary as a Microsoft Teams Adaptive Card message.
+// Modules that don't fit in Teams' message size limit are counted in a final block.
+func FormatTeamsPayload(s Summary) ([]byte, error) {
+	body := []map[string]any{
+		{"type": "TextBlock", "text": s.Title(), "weight": "Bolder", "size": "Medium", "wrap": true},
+	}
+
+	results := sortedResults(s.Results)
+	for i, r := range results {
+		withModule := append(slices.Clone(body), teamsModuleBlocks(r)...)
+		if i < len(results)-1 {
+			// Keep room for the modules that don't fit
+			withModule = append(withModule, teamsMoreBlock(len(results)-i-1))
+		}
+		payload, err := teamsPayload(withModule)
+		if err != nil {
+			return nil, err
+		}
+		if len(payload) > maxTeamsBytes {
+			body = append(body, teamsMoreBlock(len(results)-i))
+			break
+		}
+		body = append(body, teamsModuleBlocks(r)...)
+	}
+
+	return teamsPayload(body)
+}
+
+// teamsModuleBlocks returns the text blocks of a module result: its status,
+// and its error and output if any.
+func teamsModuleBlocks(r ModuleResult) []map[string]any {
+	color := "Good"
+	switch r.Status {
+	case StatusFailed:
+		color = "Attention"
+	case StatusQuarantined, StatusFlaky, StatusSkipped:
+		color = "Warning"
+	}
+	blocks := []map[string]any{{
+		"type":      "TextBlock",
+		"text":      fmt.Sprintf("%s (%s%s) %s in %s", r.Name, r.Path, binarySuffix(r.Binary), r.Status, formatDuration(r.Duration)),
+		"color":     color,
+		"weight":    "Bolder",
+		"wrap":      true,
+		"separator": true,
+	}}
+	if r.Error != "" {
+		blocks = append(blocks, map[string]any{"type": "TextBlock", "text": truncateText(r.Error, maxBlockChars), "wrap": true})
+	}
+	if r.Output != "" {
+		blocks = append(blocks, map[string]any{"type": "TextBlock", "text": tailText(r.Output, maxBlockChars), "fontType": "Monospace", "wrap": true})
+	}
+	return blocks
+}
+
+// teamsMoreBlock returns the text block that counts the n modules left out.
+func teamsMoreBlock(n int) map[string]any {
+	return map[string]any{"type": "TextBlock", "text": moreModules(n), "isSubtle": true, "wrap": true, "separator": true}
+}
+
+// teamsPayload wraps the card body in a Teams message.
+func teamsPayload(body []map[string]any) ([]byte, error) {
+	payload := map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]any{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    body,
+				},
+			},
+		},
+	}
+	return json.MarshalIndent(payload, "", "  ")
+}
+
+// moreModules describes the n modules left out of a payload.
+func moreModules(n int) string {
+	return fmt.Sprintf("%d more module(s) not shown", n)
+}
+
+// TruncateLines keeps the last maxLines lines of output, noting how many were dropped.
+func TruncateLines(output string, maxLines int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if maxLines <= 0 || len(lines) <= maxLines {
+		return strings.Join(lines, "\n")
+	}
+	dropped := len(lines) - maxLines
+	return fmt.Sprintf("... (%d lines truncated)\n%s", dropped, strings.Join(lines[dropped:], "\n"))
+}
+
+// sortedResults returns results with failures first, then by name.
+func sortedResults(results []ModuleResult) []ModuleResult {
+	sorted := append([]ModuleResult(nil), results...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		fi, fj := sorted[i].Status == StatusFailed, sorted[j].Status == StatusFailed
+		if fi != fj {
+			return fi
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// binarySuffix returns ", <binary>" for display after the module path, or "" if unset.
+func binarySuffix(binary string) string {
+	if binary == "" {
+		return ""
+	}
+	return ", " + binary
+}
+
+// codeBlock wraps output in a code fence, keeping the tail if it exceeds maxChars.
+func codeBlock(output string, maxChars int) string {
+	const fence = "```"
+	return fence + "\n" + tailText(output, maxChars-2*len(fence)-2) + "\n" + fence
+}
+
+// truncateText keeps the first maxChars characters of text, ending in "..."
+// when it's cut.
+func truncateText(text string, maxChars int) string {
+	if utf8.RuneCountInString(text) <= maxChars {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxChars-3]) + "..."
+}
+
+// tailText keeps the last maxChars characters of text, starting with "..."
+// when it's cut.
+func tailText(text string, maxChars int) string {
+	if utf8.RuneCountInString(text) <= maxChars {
+		return text
+	}
+	runes := []rune(text)
+	return "..." + string(runes[len(runes)-maxChars+3:])
+}
+
+// formatDuration rounds durations for display.
+func formatDuration(d time.Duration) string {
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,266 @@
+package chatops
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func sampleSummary() Summary {
+	return Summary{
+		Command: "plan",
+		Results: []ModuleResult{
+			{Name: "storage-account", Path: "components/azurerm/storage-account", Status: StatusSucceeded, Duration: 2 * time.Second, Output: "Plan: 1 to add, 0 to change, 0 to destroy."},
+			{Name: "prod-infra", Path: "projects/prod-infra", Status: StatusFailed, Duration: time.Second, Error: "exit status 1"},
+		},
+	}
+}
+
+func TestSummary_Title(t *testing.T) {
+	got := sampleSummary().Title()
+	want := "motf plan: 1 succeeded, 1 failed"
+	if got != want {
+		t.Errorf("Title() = %q, want %q", got, want)
+	}
+}
+
+func TestSummary_TitleWithQuarantined(t *testing.T) {
+	s := sampleSummary()
+	s.Command = "test"
+	s.Results = append(s.Results, ModuleResult{Name: "key-vault", Status: StatusQuarantined, Error: "quarantined until 2030-01-01: exit status 1"})
+
+	got := s.Title()
+	want := "motf test: 1 succeeded, 1 failed, 1 quarantined"
+	if got != want {
+		t.Errorf("Title() = %q, want %q", got, want)
+	}
+}
+
+func TestSummary_TitleWithFlaky(t *testing.T) {
+	s := sampleSummary()
+	s.Command = "test"
+	s.Results = append(s.Results, ModuleResult{Name: "key-vault", Status: StatusFlaky, Error: "flaky: passed after 1 failed attempt(s), last error: exit status 1"})
+
+	got := s.Title()
+	want := "motf test: 2 succeeded (1 flaky), 1 failed"
+	if got != want {
+		t.Errorf("Title() = %q, want %q", got, want)
+	}
+}
+
+func TestSummary_TitleWithSkipped(t *testing.T) {
+	s := sampleSummary()
+	s.Command = "test"
+	s.Results = append(s.Results, ModuleResult{Name: "key-vault", Status: StatusSkipped, Error: "skipped: estimated cost 80 exceeds the remaining budget 50 of --max-cost 50"})
+
+	got := s.Title()
+	want := "motf test: 1 succeeded, 1 failed, 1 skipped"
+	if got != want {
+		t.Errorf("Title() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatSlackPayload(t *testing.T) {
+	data, err := FormatSlackPayload(sampleSummary())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var payload struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type string `json:"type"`
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+
+	if payload.Blocks[0].Type != "header" {
+		t.Errorf("expected first block to be a header, got %s", payload.Blocks[0].Type)
+	}
+	// Failures are listed first
+	if !strings.Contains(payload.Blocks[1].Text.Text, ":x: *prod-infra*") {
+		t.Errorf("expected failed module first, got %q", payload.Blocks[1].Text.Text)
+	}
+	if !strings.Contains(string(data), "Plan: 1 to add") {
+		t.Error("expected plan output to be included")
+	}
+}
+
+func TestFormatTeamsPayload(t *testing.T) {
+	data, err := FormatTeamsPayload(sampleSummary())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var payload struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string           `json:"type"`
+				Body []map[string]any `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+
+	if payload.Type != "message" || len(payload.Attachments) != 1 {
+		t.Fatalf("unexpected payload envelope: %s", data)
+	}
+	card := payload.Attachments[0]
+	if card.ContentType != "application/vnd.microsoft.card.adaptive" || card.Content.Type != "AdaptiveCard" {
+		t.Errorf("expected an adaptive card attachment, got %s", data)
+	}
+	if card.Content.Body[1]["color"] != "Attention" {
+		t.Errorf("expected failed module to be highlighted, got %v", card.Content.Body[1])
+	}
+}
+
+func TestFormat_UnknownFormat(t *testing.T) {
+	if _, err := Format("discord", sampleSummary()); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestTruncateLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		maxLines int
+		want     string
+	}{
+		{name: "short output unchanged", output: "a\nb\n", maxLines: 5, want: "a\nb"},
+		{name: "keeps tail", output: "a\nb\nc\nd\n", maxLines: 2, want: "... (2 lines truncated)\nc\nd"},
+		{name: "zero keeps all", output: "a\nb", maxLines: 0, want: "a\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateLines(tt.output, tt.maxLines); got != tt.want {
+				t.Errorf("TruncateLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeBlock_TruncatesLongOutput(t *testing.T) {
+	got := codeBlock(strings.Repeat("x", 5000), maxBlockChars)
+	if len(got) > maxBlockChars {
+		t.Errorf("expected code block to fit in %d chars, got %d", maxBlockChars, len(got))
+	}
+}
+
+func TestCodeBlock_KeepsRunesWhole(t *testing.T) {
+	got := codeBlock(strings.Repeat("é", 5000), maxBlockChars)
+	if !utf8.ValidString(got) {
+		t.Error("expected code block to be valid UTF-8")
+	}
+	if n := utf8.RuneCountInString(got); n > maxBlockChars {
+		t.Errorf("expected code block to fit in %d chars, got %d", maxBlockChars, n)
+	}
+}
+
+// largeSummary returns a summary of n failed modules with long errors and output.
+func largeSummary(n int) Summary {
+	s := Summary{Command: "plan"}
+	for i := range n {
+		s.Results = append(s.Results, ModuleResult{
+			Name:   fmt.Sprintf("module-%02d", i),
+			Path:   fmt.Sprintf("components/module-%02d", i),
+			Status: StatusFailed,
+			Error:  strings.Repeat("ü", 4000),
+			Output: strings.Repeat("Error: something went wrong\n", 200),
+		})
+	}
+	return s
+}
+
+func TestFormatSlackPayload_Limits(t *testing.T) {
+	data, err := FormatSlackPayload(largeSummary(30))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var payload struct {
+		Blocks []struct {
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+
+	if len(payload.Blocks) != maxSlackBlocks {
+		t.Errorf("expected %d blocks, got %d", maxSlackBlocks, len(payload.Blocks))
+	}
+	for i, block := range payload.Blocks {
+		if n := utf8.RuneCountInString(block.Text.Text); n > maxBlockChars {
+			t.Errorf("block %d has %d chars, want at most %d", i, n, maxBlockChars)
+		}
+	}
+	// The header and 24 modules of 2 blocks fit, with a block for the rest
+	if last := payload.Blocks[len(payload.Blocks)-1].Text.Text; last != "6 more module(s) not shown" {
+		t.Errorf("expected the last block to count the modules left out, got %q", last)
+	}
+}
+
+func TestFormatSlackPayload_FitsWithoutMoreBlock(t *testing.T) {
+	s := largeSummary(24)
+	s.Results = append(s.Results, ModuleResult{Name: "z-last", Path: "components/z-last", Status: StatusSucceeded})
+	data, err := FormatSlackPayload(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "not shown") || !strings.Contains(string(data), "z-last") {
+		t.Errorf("expected every module to fit, got %s", data)
+	}
+}
+
+func TestFormatTeamsPayload_Limits(t *testing.T) {
+	data, err := FormatTeamsPayload(largeSummary(30))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) > maxTeamsBytes {
+		t.Errorf("expected payload to fit in %d bytes, got %d", maxTeamsBytes, len(data))
+	}
+	if !regexp.MustCompile(`"\d+ more module\(s\) not shown"`).Match(data) {
+		t.Errorf("expected a block counting the modules left out, got %s", data)
+	}
+}
+
+func TestFormat_ShowsBinary(t *testing.T) {
+	s := Summary{
+		Command: "plan",
+		Results: []ModuleResult{
+			{Name: "vnet", Path: "components/vnet", Binary: "tofu", Status: StatusSucceeded},
+			{Name: "dns", Path: "components/dns", Status: StatusSucceeded},
+		},
+	}
+
+	for _, format := range SupportedFormats() {
+		data, err := Format(format, s)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if !strings.Contains(string(data), "components/vnet`, tofu)") && !strings.Contains(string(data), "components/vnet, tofu)") {
+			t.Errorf("%s: expected binary after module path, got:\n%s", format, data)
+		}
+		if strings.Contains(string(data), "components/dns,") || strings.Contains(string(data), "components/dns`,") {
+			t.Errorf("%s: expected no binary for module without one, got:\n%s", format, data)
+		}
+	}
+}
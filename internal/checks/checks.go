@@ -0,0 +1,168 @@
+// Package checks checks Terraform modules against the repository's
+// conventions, with rules selected per module type in .motf.yml.
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/lint"
+	"github.com/TechnicallyJoe/terraform-motf/internal/sources"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Built-in rule IDs
+const (
+	RuleVariableDescriptions = "variable-descriptions"
+	RuleOutputDescriptions   = "output-descriptions"
+	RuleExamples             = "examples"
+	RuleTests                = "tests"
+	RuleNoProviderBlocks     = "no-provider-blocks"
+)
+
+// Finding is a violation of a rule by a module.
+type Finding struct {
+	Rule    string `json:"rule"`
+	File    string `json:"file,omitempty"` // Relative to the module directory
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// String returns e.g. "variables.tf:3: variable.location has no description".
+func (f Finding) String() string {
+	if f.File == "" {
+		return f.Message
+	}
+	return fmt.Sprintf("%s:%d: %s", f.File, f.Line, f.Message)
+}
+
+// Rule is a convention a module is checked against.
+type Rule struct {
+	ID          string
+	Description string
+	check       func(dir string) ([]Finding, error)
+}
+
+// rules are the built-in rules, in the order they're reported
+var rules = []Rule{
+	{RuleVariableDescriptions, "Every variable has a description", descriptions("variable")},
+	{RuleOutputDescriptions, "Every output has a description", descriptions("output")},
+	{RuleExamples, "The module has an examples/ directory", directory("examples")},
+	{RuleTests, "The module has a tests/ directory", directory("tests")},
+	{RuleNoProviderBlocks, "The module has no provider blocks, so callers configure providers", noProviderBlocks},
+}
+
+// Rules returns the built-in rules.
+func Rules() []Rule {
+	return slices.Clone(rules)
+}
+
+// Names returns the IDs of the built-in rules.
+func Names() []string {
+	names := make([]string, len(rules))
+	for i, r := range rules {
+		names[i] = r.ID
+	}
+	return names
+}
+
+// IsValid reports whether id is a built-in rule.
+func IsValid(id string) bool {
+	return slices.Contains(Names(), id)
+}
+
+// Defaults returns the rules checked for a module type (component, base, or
+// project) when .motf.yml doesn't select any. Components are reusable
+// building blocks, so they're held to every rule.
+func Defaults(moduleType string) []string {
+	if moduleType == "component" {
+		return Names()
+	}
+	return []string{RuleVariableDescriptions, RuleOutputDescriptions}
+}
+
+// Run checks the module in dir against the rules with the given IDs, and
+// returns the findings in rule order.
+func Run(dir string, ids []string) ([]Finding, error) {
+	var findings []Finding
+	for _, r := range rules {
+		if !slices.Contains(ids, r.ID) {
+			continue
+		}
+		found, err := r.check(dir)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+		for i := range found {
+			found[i].Rule = r.ID
+		}
+		findings = append(findings, found...)
+	}
+	return findings, nil
+}
+
+// descriptions reports the variables or outputs without a description.
+func descriptions(kind string) func(dir string) ([]Finding, error) {
+	return func(dir string) ([]Finding, error) {
+		missing, err := lint.MissingDescriptions(dir)
+		if err != nil {
+			return nil, err
+		}
+		var findings []Finding
+		for _, m := range missing {
+			if m.Kind == kind {
+				findings = append(findings, Finding{File: m.File, Line: m.Line, Message: fmt.Sprintf("%s.%s has no description", m.Kind, m.Name)})
+			}
+		}
+		return findings, nil
+	}
+}
+
+// directory reports a missing subdirectory of the module.
+func directory(name string) func(dir string) ([]Finding, error) {
+	return func(dir string) ([]Finding, error) {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err == nil && info.IsDir() {
+			return nil, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		return []Finding{{Message: fmt.Sprintf("has no %s/ directory", name)}}, nil
+	}
+}
+
+// providerSchema selects the provider blocks of a file
+var providerSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{{Type: "provider", LabelNames: []string{"name"}}},
+}
+
+// noProviderBlocks reports the provider blocks in the module's own files.
+func noProviderBlocks(dir string) ([]Finding, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, entry := range entries {
+		if entry.IsDir() || !finder.IsTerraformFile(entry.Name()) {
+			continue
+		}
+		f, err := sources.ParseFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		content, _, _ := f.Body.PartialContent(providerSchema)
+		for _, block := range content.Blocks {
+			findings = append(findings, Finding{
+				File:    entry.Name(),
+				Line:    block.DefRange.Start.Line,
+				Message: fmt.Sprintf("provider %q block; providers are configured by the caller", block.Labels[0]),
+			})
+		}
+	}
+	return findings, nil
+}
@@ -0,0 +1,81 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeModule creates a module directory with the given files.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"main.tf": `provider "azurerm" {
+  features {}
+}
+`,
+		"variables.tf": `variable "name" {
+  description = "Name of the resource"
+}
+
+variable "location" {}
+`,
+		"outputs.tf":             "output \"id\" {\n  value = 1\n}\n",
+		"examples/basic/main.tf": "",
+	})
+
+	findings, err := Run(dir, Names())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Rule+": "+f.String())
+	}
+	want := []string{
+		"variable-descriptions: variables.tf:5: variable.location has no description",
+		"output-descriptions: outputs.tf:1: output.id has no description",
+		"tests: has no tests/ directory",
+		`no-provider-blocks: main.tf:1: provider "azurerm" block; providers are configured by the caller`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRun_OnlySelectedRules(t *testing.T) {
+	dir := writeModule(t, map[string]string{"main.tf": "provider \"azurerm\" {}\n"})
+
+	findings, err := Run(dir, []string{RuleVariableDescriptions, RuleOutputDescriptions})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %+v", findings)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	if got := Defaults("component"); !slices.Equal(got, Names()) {
+		t.Errorf("Defaults(component) = %v, want every rule", got)
+	}
+	if got := Defaults("project"); !slices.Equal(got, []string{RuleVariableDescriptions, RuleOutputDescriptions}) {
+		t.Errorf("Defaults(project) = %v, want the description rules", got)
+	}
+}
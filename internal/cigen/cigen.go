@@ -0,0 +1,99 @@
+// Package cigen generates CI pipeline definitions that run motf on the
+// modules changed by a pull request or push.
+package cigen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Supported CI providers
+const (
+	ProviderGitHub = "github"
+	ProviderAzure  = "azure"
+	ProviderGitLab = "gitlab"
+)
+
+// providers maps each provider to its template and the path, relative to the
+// repository root, that the provider reads its pipeline from.
+var providers = map[string]struct {
+	template string
+	path     string
+}{
+	ProviderGitHub: {githubTemplate, ".github/workflows/motf.yml"},
+	ProviderAzure:  {azureTemplate, "azure-pipelines.yml"},
+	ProviderGitLab: {gitlabTemplate, ".gitlab-ci.yml"},
+}
+
+// Providers returns the supported CI providers.
+func Providers() []string {
+	return []string{ProviderGitHub, ProviderAzure, ProviderGitLab}
+}
+
+// DefaultPath returns the slash-separated path, relative to the repository
+// root, of the provider's pipeline definition.
+func DefaultPath(provider string) (string, error) {
+	p, ok := providers[provider]
+	if !ok {
+		return "", unknownProvider(provider)
+	}
+	return p.path, nil
+}
+
+// Options parameterize a generated pipeline.
+type Options struct {
+	Binary        string   // terraform or tofu
+	BinaryVersion string   // Version of Binary to install, or latest
+	TestEngine    string   // terratest, terraform, or tofu
+	Branch        string   // Branch that pushes run the pipeline on, e.g. main
+	ModuleDirs    []string // Slash-separated module directories relative to the repository root
+	ConfigFile    string   // Slash-separated path of .motf.yml relative to the repository root, if any
+}
+
+// tool is a terraform or tofu binary that the pipeline installs
+type tool struct {
+	Name    string
+	Version string
+}
+
+// templateData is the data the pipeline templates are rendered with
+type templateData struct {
+	Options
+	Tools []tool
+}
+
+// Generate renders the provider's pipeline definition. The pipeline installs
+// the binary, and the test engine when it's a different binary, checks the
+// formatting of the changed modules, and validates and tests them in parallel.
+func Generate(provider string, opts Options) ([]byte, error) {
+	p, ok := providers[provider]
+	if !ok {
+		return nil, unknownProvider(provider)
+	}
+	if opts.Binary == "" {
+		opts.Binary = "terraform"
+	}
+	if opts.BinaryVersion == "" {
+		opts.BinaryVersion = "latest"
+	}
+	data := templateData{Options: opts, Tools: []tool{{Name: opts.Binary, Version: opts.BinaryVersion}}}
+	if (opts.TestEngine == "terraform" || opts.TestEngine == "tofu") && opts.TestEngine != opts.Binary {
+		data.Tools = append(data.Tools, tool{Name: opts.TestEngine, Version: "latest"})
+	}
+
+	tmpl, err := template.New(provider).Delims("[[", "]]").Parse(p.template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s template: %w", provider, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s pipeline: %w", provider, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func unknownProvider(provider string) error {
+	return fmt.Errorf("unknown CI provider '%s': must be one of %s", provider, strings.Join(Providers(), ", "))
+}
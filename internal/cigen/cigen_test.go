@@ -0,0 +1,149 @@
+package cigen
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var testOptions = Options{
+	Binary:     "terraform",
+	TestEngine: "terratest",
+	Branch:     "main",
+	ModuleDirs: []string{"components", "bases", "projects"},
+	ConfigFile: ".motf.yml",
+}
+
+func TestGenerate_ValidYAML(t *testing.T) {
+	for _, provider := range Providers() {
+		t.Run(provider, func(t *testing.T) {
+			content, err := Generate(provider, testOptions)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			var doc map[string]any
+			if err := yaml.Unmarshal(content, &doc); err != nil {
+				t.Fatalf("generated pipeline is not valid YAML: %v\n%s", err, content)
+			}
+			for _, want := range []string{
+				"go install github.com/TechnicallyJoe/terraform-motf/cmd/motf@latest",
+				`motf val -i --changed --ref "$MOTF_REF" --parallel`,
+				`motf test --changed --ref "$MOTF_REF" --parallel`,
+				"components",
+				".motf.yml",
+			} {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("expected pipeline to contain %q, got:\n%s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_GitHub(t *testing.T) {
+	content, err := Generate(ProviderGitHub, testOptions)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	var workflow struct {
+		On struct {
+			Push struct {
+				Branches []string `yaml:"branches"`
+				Paths    []string `yaml:"paths"`
+			} `yaml:"push"`
+		} `yaml:"on"`
+		Jobs map[string]struct {
+			Steps []struct {
+				Uses string `yaml:"uses"`
+			} `yaml:"steps"`
+		} `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(content, &workflow); err != nil {
+		t.Fatalf("failed to parse workflow: %v", err)
+	}
+	if got := workflow.On.Push.Branches; len(got) != 1 || got[0] != "main" {
+		t.Errorf("push branches = %v, want [main]", got)
+	}
+	wantPaths := []string{"components/**", "bases/**", "projects/**", ".motf.yml"}
+	if got := strings.Join(workflow.On.Push.Paths, ","); got != strings.Join(wantPaths, ",") {
+		t.Errorf("push paths = %s, want %s", got, strings.Join(wantPaths, ","))
+	}
+	var uses []string
+	for _, step := range workflow.Jobs["motf"].Steps {
+		if step.Uses != "" {
+			uses = append(uses, step.Uses)
+		}
+	}
+	if got, want := strings.Join(uses, ","), "actions/checkout@v6,actions/setup-go@v6,hashicorp/setup-terraform@v4"; got != want {
+		t.Errorf("actions = %s, want %s", got, want)
+	}
+}
+
+func TestGenerate_Tools(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       Options
+		contains   []string
+		excludes   []string
+		providerID string
+	}{
+		{
+			name:       "tofu with version",
+			opts:       Options{Binary: "tofu", BinaryVersion: "1.8.0", TestEngine: "tofu", Branch: "main"},
+			providerID: ProviderGitHub,
+			contains:   []string{"opentofu/setup-opentofu@v2", "tofu_version: 1.8.0"},
+			excludes:   []string{"setup-terraform"},
+		},
+		{
+			name:       "test engine installs a second binary",
+			opts:       Options{Binary: "terraform", TestEngine: "tofu", Branch: "main"},
+			providerID: ProviderGitHub,
+			contains:   []string{"terraform_version: latest", "tofu_version: latest"},
+		},
+		{
+			name:       "gitlab pinned tofu",
+			opts:       Options{Binary: "tofu", BinaryVersion: "1.8.0", TestEngine: "terratest", Branch: "main"},
+			providerID: ProviderGitLab,
+			contains:   []string{"--opentofu-version 1.8.0"},
+			excludes:   []string{"terraform.zip"},
+		},
+		{
+			name:       "azure latest terraform",
+			opts:       Options{Branch: "master"},
+			providerID: ProviderAzure,
+			contains:   []string{"version=latest", "terraform_${version}_linux_amd64.zip", "- master"},
+			excludes:   []string{"install-opentofu"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := Generate(tt.providerID, tt.opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("expected pipeline to contain %q, got:\n%s", want, content)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(string(content), unwanted) {
+					t.Errorf("expected pipeline not to contain %q, got:\n%s", unwanted, content)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_UnknownProvider(t *testing.T) {
+	_, err := Generate("jenkins", testOptions)
+	if err == nil || !strings.Contains(err.Error(), "unknown CI provider 'jenkins'") {
+		t.Errorf("expected unknown provider error, got %v", err)
+	}
+	if _, err := DefaultPath("jenkins"); err == nil {
+		t.Error("expected DefaultPath() to fail for an unknown provider")
+	}
+}
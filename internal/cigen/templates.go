@@ -0,0 +1,196 @@
+package cigen
+
+// The templates use [[ ]] delimiters, since GitHub Actions and Azure Pipelines
+// expressions use {{ }}.
+
+const githubTemplate = `# Generated by 'motf ci generate github'. Checks, validates, and tests the
+# modules changed by a pull request or push.
+name: motf
+
+on:
+  push:
+    branches:
+      - [[ .Branch ]]
+    paths:[[ template "paths" . ]]
+  pull_request:
+    branches:
+      - [[ .Branch ]]
+    paths:[[ template "paths" . ]]
+
+permissions:
+  contents: read
+
+jobs:
+  motf:
+    name: motf
+    runs-on: ubuntu-latest
+    env:
+      # Pull requests compare with the target branch, pushes with the previous head
+      MOTF_REF: ${{ github.event_name == 'pull_request' && format('origin/{0}', github.base_ref) || github.event.before }}
+    steps:
+      - uses: actions/checkout@v6
+        with:
+          fetch-depth: 0  # Required for --changed
+
+      - name: Set up Go
+        uses: actions/setup-go@v6
+        with:
+          go-version: '1.25'
+[[- range .Tools ]]
+[[- if eq .Name "tofu" ]]
+
+      - name: Set up OpenTofu
+        uses: opentofu/setup-opentofu@v2
+        with:
+          tofu_version: [[ .Version ]]
+          tofu_wrapper: false
+[[- else ]]
+
+      - name: Set up Terraform
+        uses: hashicorp/setup-terraform@v4
+        with:
+          terraform_version: [[ .Version ]]
+          terraform_wrapper: false
+[[- end ]]
+[[- end ]]
+
+      - name: Install motf
+        run: go install github.com/TechnicallyJoe/terraform-motf/cmd/motf@latest
+
+      - name: Check formatting
+        run: motf fmt --changed --ref "$MOTF_REF" -a --check
+
+      - name: Validate modules
+        run: motf val -i --changed --ref "$MOTF_REF" --parallel
+
+      - name: Test modules
+        run: motf test --changed --ref "$MOTF_REF" --parallel
+[[- define "paths" ]]
+[[- range .ModuleDirs ]]
+      - '[[ . ]]/**'
+[[- end ]]
+[[- if .ConfigFile ]]
+      - '[[ .ConfigFile ]]'
+[[- end ]]
+[[- end ]]
+`
+
+const azureTemplate = `# Generated by 'motf ci generate azure'. Checks, validates, and tests the
+# modules changed by a pull request or push.
+trigger:
+  branches:
+    include:
+      - [[ .Branch ]]
+  paths:
+    include:[[ template "paths" . ]]
+
+pr:
+  branches:
+    include:
+      - [[ .Branch ]]
+  paths:
+    include:[[ template "paths" . ]]
+
+pool:
+  vmImage: ubuntu-latest
+
+steps:
+  - checkout: self
+    fetchDepth: 0  # Required for --changed
+
+  - task: GoTool@0
+    inputs:
+      version: '1.25.0'
+[[- range .Tools ]]
+[[- if eq .Name "tofu" ]]
+
+  - script: curl -fsSL https://get.opentofu.org/install-opentofu.sh | sh -s -- --install-method standalone[[ if ne .Version "latest" ]] --opentofu-version [[ .Version ]][[ end ]]
+    displayName: Install OpenTofu
+[[- else ]]
+
+  - script: |
+      version=[[ .Version ]]
+      if [ "$version" = latest ]; then
+        version=$(curl -fsSL https://checkpoint-api.hashicorp.com/v1/check/terraform | sed -E 's/.*"current_version":"([^"]+)".*/\1/')
+      fi
+      curl -fsSLo terraform.zip "https://releases.hashicorp.com/terraform/${version}/terraform_${version}_linux_amd64.zip"
+      sudo unzip -o terraform.zip terraform -d /usr/local/bin
+      rm terraform.zip
+    displayName: Install Terraform
+[[- end ]]
+[[- end ]]
+
+  - script: |
+      go install github.com/TechnicallyJoe/terraform-motf/cmd/motf@latest
+      echo "##vso[task.prependpath]$(go env GOPATH)/bin"
+    displayName: Install motf
+
+  - script: |
+      # Pull requests compare with the target branch, pushes with the previous commit
+      if [ "$BUILD_REASON" = PullRequest ]; then
+        ref="origin/${SYSTEM_PULLREQUEST_TARGETBRANCH#refs/heads/}"
+      else
+        ref=HEAD~1
+      fi
+      echo "##vso[task.setvariable variable=MOTF_REF]$ref"
+    displayName: Detect base ref
+
+  - script: motf fmt --changed --ref "$MOTF_REF" -a --check
+    displayName: Check formatting
+
+  - script: motf val -i --changed --ref "$MOTF_REF" --parallel
+    displayName: Validate modules
+
+  - script: motf test --changed --ref "$MOTF_REF" --parallel
+    displayName: Test modules
+[[- define "paths" ]]
+[[- range .ModuleDirs ]]
+      - [[ . ]]
+[[- end ]]
+[[- if .ConfigFile ]]
+      - [[ .ConfigFile ]]
+[[- end ]]
+[[- end ]]
+`
+
+const gitlabTemplate = `# Generated by 'motf ci generate gitlab'. Checks, validates, and tests the
+# modules changed by a merge request or push.
+motf:
+  image: golang:1.25
+  variables:
+    GIT_DEPTH: 0  # Required for --changed
+  rules:
+    - if: $CI_PIPELINE_SOURCE == "merge_request_event"
+      changes: &paths
+[[- range .ModuleDirs ]]
+        - [[ . ]]/**/*
+[[- end ]]
+[[- if .ConfigFile ]]
+        - [[ .ConfigFile ]]
+[[- end ]]
+    - if: $CI_COMMIT_BRANCH == "[[ .Branch ]]"
+      changes: *paths
+  before_script:
+[[- range .Tools ]]
+[[- if eq .Name "tofu" ]]
+    - curl -fsSL https://get.opentofu.org/install-opentofu.sh | sh -s -- --install-method standalone[[ if ne .Version "latest" ]] --opentofu-version [[ .Version ]][[ end ]]
+[[- else ]]
+    - apt-get update -qq && apt-get install -y -qq unzip
+    - |
+      version=[[ .Version ]]
+      if [ "$version" = latest ]; then
+        version=$(curl -fsSL https://checkpoint-api.hashicorp.com/v1/check/terraform | sed -E 's/.*"current_version":"([^"]+)".*/\1/')
+      fi
+      curl -fsSLo terraform.zip "https://releases.hashicorp.com/terraform/${version}/terraform_${version}_linux_amd64.zip"
+      unzip -o terraform.zip terraform -d /usr/local/bin
+      rm terraform.zip
+[[- end ]]
+[[- end ]]
+    - go install github.com/TechnicallyJoe/terraform-motf/cmd/motf@latest
+  script:
+    # Merge requests compare with the target branch, pushes with the previous head
+    - export MOTF_REF="${CI_MERGE_REQUEST_DIFF_BASE_SHA:-$CI_COMMIT_BEFORE_SHA}"
+    - motf fmt --changed --ref "$MOTF_REF" -a --check
+    - motf val -i --changed --ref "$MOTF_REF" --parallel
+    - motf test --changed --ref "$MOTF_REF" --parallel
+`
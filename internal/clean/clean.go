@@ -0,0 +1,97 @@
+// Package clean finds the files Terraform leaves behind in module
+// directories, like .terraform directories and crash logs, for `motf clean`.
+package clean
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Kinds of files that are cleaned
+const (
+	KindCache    = "cache"     // .terraform directories with providers and modules
+	KindLock     = "lock"      // Dependency lock files
+	KindState    = "state"     // Local state files
+	KindCrashLog = "crash log" // Logs written when Terraform crashes
+)
+
+// stateFiles are the names of local state files and directories
+var stateFiles = []string{"terraform.tfstate", "terraform.tfstate.backup", "terraform.tfstate.d", "errored.tfstate", ".terraform.tfstate.lock.info"}
+
+// skipDirs are never searched
+var skipDirs = []string{".git", "node_modules"}
+
+// Options selects what Find returns besides caches and crash logs.
+type Options struct {
+	Locks bool // .terraform.lock.hcl files
+	State bool // Local state files
+}
+
+// Item is a file or directory to remove.
+type Item struct {
+	Path string // Absolute
+	Kind string
+}
+
+// Find returns the items under dir, sorted by path. It doesn't descend into
+// .terraform directories, so the state they hold for the backend is removed
+// with them, not reported as local state.
+func Find(dir string, opts Options) ([]Item, error) {
+	var items []Item
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() && slices.Contains(skipDirs, name) {
+			return filepath.SkipDir
+		}
+
+		kind := kindOf(name, d.IsDir(), opts)
+		if kind == "" {
+			return nil
+		}
+		items = append(items, Item{Path: p, Kind: kind})
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", dir, err)
+	}
+	return items, nil
+}
+
+// kindOf returns the kind of the file or directory name, or "" when it
+// isn't cleaned with opts.
+func kindOf(name string, isDir bool, opts Options) string {
+	switch {
+	case isDir && name == ".terraform":
+		return KindCache
+	case opts.State && slices.Contains(stateFiles, name):
+		return KindState
+	case isDir:
+		return ""
+	case opts.Locks && name == ".terraform.lock.hcl":
+		return KindLock
+	case name == "crash.log" || (strings.HasPrefix(name, "crash.") && strings.HasSuffix(name, ".log")):
+		return KindCrashLog
+	}
+	return ""
+}
+
+// Remove removes the item, with its contents when it is a directory.
+func Remove(item Item) error {
+	if err := os.RemoveAll(item.Path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", item.Path, err)
+	}
+	return nil
+}
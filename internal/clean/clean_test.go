@@ -0,0 +1,83 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles creates files, by slash-separated path relative to root.
+func writeFiles(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, name := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root,
+		"main.tf",
+		".terraform/terraform.tfstate",
+		".terraform/providers/registry.terraform.io/hashicorp/azurerm/lock.json",
+		".terraform.lock.hcl",
+		"terraform.tfstate",
+		"terraform.tfstate.backup",
+		"terraform.tfstate.d/dev/terraform.tfstate",
+		"crash.log",
+		"crash.20260101.log",
+		"examples/basic/.terraform/modules/modules.json",
+		"examples/basic/terraform.tfstate",
+		".git/crash.log",
+	)
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{"default", Options{}, []string{".terraform", "crash.20260101.log", "crash.log", "examples/basic/.terraform"}},
+		{"locks", Options{Locks: true}, []string{".terraform", ".terraform.lock.hcl", "crash.20260101.log", "crash.log", "examples/basic/.terraform"}},
+		{"state", Options{State: true}, []string{
+			".terraform", "crash.20260101.log", "crash.log", "examples/basic/.terraform", "examples/basic/terraform.tfstate",
+			"terraform.tfstate", "terraform.tfstate.backup", "terraform.tfstate.d",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := Find(root, tt.opts)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			var got []string
+			for _, item := range items {
+				rel, _ := filepath.Rel(root, item.Path)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, ".terraform/modules/modules.json", "main.tf")
+	if err := Remove(Item{Path: filepath.Join(root, ".terraform"), Kind: KindCache}); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".terraform")); !os.IsNotExist(err) {
+		t.Error(".terraform still exists")
+	}
+	if _, err := os.Stat(filepath.Join(root, "main.tf")); err != nil {
+		t.Error("main.tf was removed")
+	}
+}
@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/adopt"
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adoptApplyFlag bool     // Execute the reorganization
+	adoptYesFlag   bool     // Skip the confirmation prompt
+	adoptMapFlags  []string // from=to overrides of proposed targets
+	adoptJSONFlag  bool     // Output the plan as JSON
+)
+
+// adoptCmd represents the adopt command
+var adoptCmd = &cobra.Command{
+	Use:   "adopt [dir]",
+	Short: "Reorganize an existing Terraform repository into components, bases, and projects",
+	Long: `Analyze a Terraform repository that doesn't use the components/bases/projects
+layout yet and propose where to move each module.
+
+Every directory with .tf or .tf.json files outside components/, bases/, and
+projects/ is a module and classified as:
+  project    has a backend or cloud block, or configures providers
+  base       composes other local modules
+  component  any other, reusable module
+
+Subdirectories named examples, modules, or tests move with their module. Use
+--map to move a module somewhere else than proposed, e.g. to nest components by
+provider.
+
+Without --apply, only the plan is shown. With --apply, after confirmation, motf
+moves the modules with 'git mv' (or a plain rename outside git), rewrites local
+module sources that point across moved directories, and creates a .motf.yml if
+there is none. Use --dry-run to see each step without executing it.`,
+	Example: `  motf adopt                                             # Show the proposed layout
+  motf adopt --map modules/vnet=components/azurerm/vnet  # Override a target
+  motf adopt --apply                                     # Reorganize after confirmation
+  motf adopt --apply --dry-run                           # Show each step without executing`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAdopt,
+}
+
+func init() {
+	adoptCmd.Flags().BoolVar(&adoptApplyFlag, "apply", false, "Move the modules and rewrite their sources")
+	adoptCmd.Flags().BoolVarP(&adoptYesFlag, "yes", "y", false, "Apply without asking for confirmation")
+	adoptCmd.Flags().StringArrayVar(&adoptMapFlags, "map", nil, "Move a module directory to another target: from=to (repeatable)")
+	adoptCmd.Flags().BoolVar(&adoptJSONFlag, "json", false, "Output the plan as JSON")
+	rootCmd.AddCommand(adoptCmd)
+}
+
+func runAdopt(cmd *cobra.Command, args []string) error {
+	root := "."
+	if len(args) > 0 {
+		root = args[0]
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	overrides, err := parseAdoptMaps(adoptMapFlags)
+	if err != nil {
+		return err
+	}
+
+	plan, err := adopt.Analyze(root, adopt.Options{
+		TypeDirs:  adoptTypeDirs(),
+		Overrides: overrides,
+	})
+	if err != nil {
+		return err
+	}
+
+	if adoptJSONFlag {
+		output, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		printAdoptPlan(root, plan)
+	}
+
+	if len(plan.Moves) == 0 || !adoptApplyFlag {
+		if len(plan.Moves) > 0 && !adoptJSONFlag {
+			fmt.Println("\nRun with --apply to reorganize the repository")
+		}
+		return nil
+	}
+
+	if !adoptYesFlag && !dryRunFlag {
+		ok, err := confirm(cmd.InOrStdin(), fmt.Sprintf("\nMove %d module(s) and rewrite %d source(s)? [y/N] ", len(plan.Moves), len(plan.Rewrites)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+	return applyAdoptPlan(root, plan)
+}
+
+// adoptTypeDirs returns the directory modules of each type are moved to: the
+// first module directory of the type, or its default directory.
+func adoptTypeDirs() map[string]string {
+	return map[string]string{
+		TypeComponent: typeDir(TypeComponent, DirComponents),
+		TypeBase:      typeDir(TypeBase, DirBases),
+		TypeProject:   typeDir(TypeProject, DirProjects),
+	}
+}
+
+// parseAdoptMaps parses --map values of the form from=to.
+func parseAdoptMaps(values []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(values))
+	for _, v := range values {
+		from, to, ok := strings.Cut(v, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid --map '%s': must be from=to", v)
+		}
+		overrides[filepath.ToSlash(filepath.Clean(from))] = filepath.ToSlash(filepath.Clean(to))
+	}
+	return overrides, nil
+}
+
+// printAdoptPlan outputs the moves as a table, followed by the source rewrites
+func printAdoptPlan(root string, plan *adopt.Plan) {
+	if len(plan.Moves) == 0 {
+		dirs := adoptTypeDirs()
+		fmt.Printf("No modules outside %s, %s, and %s found in %s\n", dirs[TypeComponent], dirs[TypeBase], dirs[TypeProject], root)
+		return
+	}
+
+	if plainFlag {
+		for i, m := range plan.Moves {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("From: %s\n", m.From)
+			fmt.Printf("To: %s\n", m.To)
+			fmt.Printf("Type: %s\n", m.Type)
+			fmt.Printf("Reason: %s\n", m.Reason)
+		}
+	} else {
+		fromWidth, toWidth, typeWidth := len("FROM"), len("TO"), len("TYPE")
+		for _, m := range plan.Moves {
+			fromWidth = max(fromWidth, len(m.From))
+			toWidth = max(toWidth, len(m.To))
+			typeWidth = max(typeWidth, len(m.Type))
+		}
+		fmt.Printf("%-*s  %-*s  %-*s  %s\n", fromWidth, "FROM", toWidth, "TO", typeWidth, "TYPE", "REASON")
+		for _, m := range plan.Moves {
+			fmt.Printf("%-*s  %-*s  %-*s  %s\n", fromWidth, m.From, toWidth, m.To, typeWidth, m.Type, m.Reason)
+		}
+	}
+
+	if len(plan.Rewrites) > 0 {
+		fmt.Printf("\nModule sources to rewrite:\n")
+		for _, r := range plan.Rewrites {
+			fmt.Printf("  %s (module.%s): %s -> %s\n", r.File, r.Module, r.From, r.To)
+		}
+	}
+}
+
+// confirm asks a yes/no question on stdout and reads the answer from in.
+func confirm(in io.Reader, question string) (bool, error) {
+	fmt.Print(question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// applyAdoptPlan moves the modules, rewrites their sources, and creates a
+// .motf.yml if there is none.
+func applyAdoptPlan(root string, plan *adopt.Plan) error {
+	for _, m := range plan.Moves {
+		from := filepath.Join(root, filepath.FromSlash(m.From))
+		to := filepath.Join(root, filepath.FromSlash(m.To))
+		if dryRunFlag {
+			fmt.Printf("[dry-run] Would move %s to %s\n", m.From, m.To)
+			continue
+		}
+		if err := moveDir(root, from, to); err != nil {
+			return fmt.Errorf("failed to move %s to %s: %w", m.From, m.To, err)
+		}
+		removeEmptyParents(root, filepath.Dir(from))
+		fmt.Printf("Moved %s to %s\n", m.From, m.To)
+	}
+
+	if dryRunFlag {
+		for _, r := range plan.Rewrites {
+			fmt.Printf("[dry-run] Would rewrite source of module.%s in %s to %s\n", r.Module, r.File, r.To)
+		}
+	} else {
+		if err := adopt.ApplyRewrites(root, plan.Rewrites); err != nil {
+			return err
+		}
+		if len(plan.Rewrites) > 0 {
+			fmt.Printf("Rewrote %d module source(s)\n", len(plan.Rewrites))
+		}
+	}
+
+	configPath := filepath.Join(root, config.ConfigFile)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		if dryRunFlag {
+			fmt.Printf("[dry-run] Would create %s\n", config.ConfigFile)
+		} else {
+			if err := os.WriteFile(configPath, []byte(adoptConfig()), 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", config.ConfigFile, err)
+			}
+			fmt.Printf("Created %s\n", config.ConfigFile)
+		}
+	}
+
+	if !dryRunFlag {
+		fmt.Println("\nUpdate paths in CI pipelines and documentation that refer to the old locations.")
+	}
+	return nil
+}
+
+// moveDir moves a directory with 'git mv' so history follows the files, or
+// with a plain rename when the directory isn't tracked by git.
+func moveDir(root, from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	gitMv := exec.Command("git", "mv", from, to)
+	gitMv.Dir = root
+	if err := gitMv.Run(); err == nil {
+		return nil
+	}
+	return os.Rename(from, to)
+}
+
+// removeEmptyParents removes dir and its parents up to root while they are empty.
+func removeEmptyParents(root, dir string) {
+	for dir != root && strings.HasPrefix(dir, root) {
+		if err := os.Remove(dir); err != nil {
+			return // Not empty
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// adoptConfig returns the .motf.yml created by adopt.
+func adoptConfig() string {
+	binary := config.DefaultConfig().Binary
+	if cfg != nil && cfg.Binary != "" {
+		binary = cfg.Binary
+	}
+	return fmt.Sprintf(`# Created by motf adopt. See https://github.com/TechnicallyJoe/terraform-motf/wiki/configuration
+binary: %s
+`, binary)
+}
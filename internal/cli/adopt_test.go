@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func resetAdoptFlags(t *testing.T) {
+	t.Helper()
+	resetFlags(t)
+	t.Cleanup(func() {
+		adoptApplyFlag = false
+		adoptYesFlag = false
+		adoptMapFlags = nil
+		adoptJSONFlag = false
+		rootCmd.SetArgs(nil)
+		rootCmd.SetIn(nil)
+	})
+}
+
+func TestAdoptCmd_Flags(t *testing.T) {
+	for _, name := range []string{"apply", "yes", "map", "json"} {
+		if adoptCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected adopt command to have --%s flag", name)
+		}
+	}
+}
+
+func TestParseAdoptMaps(t *testing.T) {
+	got, err := parseAdoptMaps([]string{"modules/vnet/=components/azurerm/vnet"})
+	if err != nil {
+		t.Fatalf("parseAdoptMaps() error = %v", err)
+	}
+	if got["modules/vnet"] != "components/azurerm/vnet" {
+		t.Errorf("parseAdoptMaps() = %v", got)
+	}
+
+	if _, err := parseAdoptMaps([]string{"modules/vnet"}); err == nil {
+		t.Error("expected error for --map without '='")
+	}
+}
+
+// createLegacyRepo creates a repository with a project and a component outside the layout.
+func createLegacyRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	createTerraformModule(t, root, filepath.Join("modules", "network"))
+	prod := filepath.Join(root, "envs", "prod")
+	if err := os.MkdirAll(prod, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "terraform {\n  backend \"local\" {}\n}\n\nmodule \"network\" {\n  source = \"../../modules/network\"\n}\n"
+	if err := os.WriteFile(filepath.Join(prod, "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestAdoptCmd_Apply(t *testing.T) {
+	resetAdoptFlags(t)
+	withConfig(t, config.DefaultConfig())
+	root := createLegacyRepo(t)
+
+	rootCmd.SetArgs([]string{"adopt", root, "--apply", "--yes"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("adopt --apply failed: %v", err)
+	}
+
+	for _, path := range []string{
+		filepath.Join(DirComponents, "network", "main.tf"),
+		filepath.Join(DirProjects, "prod", "main.tf"),
+		config.ConfigFile,
+	} {
+		if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+			t.Errorf("expected %s after adopt: %v", path, err)
+		}
+	}
+	for _, dir := range []string{"modules", "envs"} {
+		if _, err := os.Stat(filepath.Join(root, dir)); !os.IsNotExist(err) {
+			t.Errorf("expected empty directory %s to be removed", dir)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, DirProjects, "prod", "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `source = "../../components/network"`) {
+		t.Errorf("expected rewritten source, got:\n%s", data)
+	}
+}
+
+func TestAdoptCmd_DeclinedDoesNothing(t *testing.T) {
+	resetAdoptFlags(t)
+	root := createLegacyRepo(t)
+
+	rootCmd.SetArgs([]string{"adopt", root, "--apply"})
+	rootCmd.SetIn(strings.NewReader("n\n"))
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("adopt --apply failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "modules", "network", "main.tf")); err != nil {
+		t.Errorf("expected modules to stay in place: %v", err)
+	}
+}
+
+func TestAdoptCmd_DryRun(t *testing.T) {
+	resetAdoptFlags(t)
+	root := createLegacyRepo(t)
+
+	rootCmd.SetArgs([]string{"adopt", root, "--apply", "--dry-run"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("adopt --apply --dry-run failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, config.ConfigFile)); !os.IsNotExist(err) {
+		t.Error("dry run should not create a config file")
+	}
+	if _, err := os.Stat(filepath.Join(root, DirComponents)); !os.IsNotExist(err) {
+		t.Error("dry run should not move modules")
+	}
+}
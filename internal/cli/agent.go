@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/agent"
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/spf13/cobra"
+)
+
+// EnvAgentToken is the environment variable holding the agent authentication token
+const EnvAgentToken = "MOTF_AGENT_TOKEN"
+
+var (
+	agentSocketFlag    string
+	agentTokenFileFlag string
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Serve motf operations over a local socket for bots and automation",
+	Long: `Start a long-lived agent that exposes core motf operations over a unix socket.
+
+Clients send newline-delimited JSON requests and receive newline-delimited JSON
+events. Every request must carry the token configured via --token-file or the
+MOTF_AGENT_TOKEN environment variable.
+
+Request:
+  {"id": 1, "token": "...", "method": "plan", "params": {"module": "storage-account", "init": true}}
+
+Events:
+  {"id": 1, "event": "output", "output": "Running terraform plan in ..."}
+  {"id": 1, "event": "result", "result": {...}}
+  {"id": 1, "event": "error", "error": "..."}
+
+Methods:
+  list     List all modules (params: search)
+  changed  List changed modules (params: ref)
+  plan     Run plan on a module, streaming output (params: module, init, args)
+  reload   Re-walk the repository and refresh the cached module index`,
+	Example: `  MOTF_AGENT_TOKEN=secret motf agent --socket /tmp/motf.sock
+  motf agent --socket /tmp/motf.sock --token-file ~/.motf-token`,
+	Args: cobra.NoArgs,
+	RunE: runAgent,
+}
+
+func init() {
+	agentCmd.Flags().StringVar(&agentSocketFlag, "socket", "", "Path of the unix socket to listen on (required)")
+	agentCmd.Flags().StringVar(&agentTokenFileFlag, "token-file", "", "File containing the authentication token (default: $MOTF_AGENT_TOKEN)")
+	rootCmd.AddCommand(agentCmd)
+}
+
+func runAgent(cmd *cobra.Command, args []string) error {
+	if agentSocketFlag == "" {
+		return fmt.Errorf("--socket is required")
+	}
+
+	token, err := readAgentToken(agentTokenFileFlag)
+	if err != nil {
+		return err
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	server := newAgentServer(token, &moduleIndex{basePath: basePath})
+
+	// Remove a stale socket left behind by a previous run, but never another file
+	if fi, err := os.Lstat(agentSocketFlag); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s exists and is not a socket", agentSocketFlag)
+		}
+		if err := os.Remove(agentSocketFlag); err != nil {
+			return fmt.Errorf("failed to remove existing socket: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("unix", agentSocketFlag)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", agentSocketFlag, err)
+	}
+	if err := os.Chmod(agentSocketFlag, 0600); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+
+	// Close the listener on interrupt so Serve returns and the socket is cleaned up
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	go func() {
+		<-sigs
+		_ = listener.Close()
+	}()
+
+	cmd.Printf("motf agent listening on %s (methods: %s)\n", agentSocketFlag, strings.Join(server.Methods(), ", "))
+	return server.Serve(listener)
+}
+
+// readAgentToken reads the token from tokenFile, or from MOTF_AGENT_TOKEN when no file is given.
+func readAgentToken(tokenFile string) (string, error) {
+	var token string
+	if tokenFile != "" {
+		data, err := os.ReadFile(filepath.Clean(tokenFile))
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	} else {
+		token = os.Getenv(EnvAgentToken)
+	}
+
+	if token == "" {
+		return "", fmt.Errorf("an authentication token is required: use --token-file or set %s", EnvAgentToken)
+	}
+	return token, nil
+}
+
+// moduleIndex caches the discovered modules so repeated requests don't re-walk the repository.
+type moduleIndex struct {
+	mu         sync.Mutex
+	basePath   string
+	discovered []ModuleInfo // Including modules outside managed_paths
+	modules    []ModuleInfo // Within managed_paths, as the CLI sees them
+	loaded     bool
+}
+
+// all returns the cached modules within managed_paths, discovering them on first use.
+func (idx *moduleIndex) all() ([]ModuleInfo, error) {
+	_, modules, err := idx.load()
+	return modules, err
+}
+
+// load returns the cached modules, both all discovered ones and those within
+// managed_paths, discovering them on first use.
+func (idx *moduleIndex) load() (discovered, managed []ModuleInfo, err error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !idx.loaded {
+		modules, err := discoverModules(idx.basePath, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		sortModules(modules)
+		idx.discovered = modules
+		idx.modules, _ = filterManaged(slices.Clone(modules))
+		idx.loaded = true
+	}
+	return idx.discovered, idx.modules, nil
+}
+
+// reload drops the cache and re-discovers modules.
+func (idx *moduleIndex) reload() ([]ModuleInfo, error) {
+	idx.mu.Lock()
+	idx.loaded = false
+	idx.mu.Unlock()
+	return idx.all()
+}
+
+// find returns the absolute path of the module with the given name, which
+// may be qualified as for module arguments, e.g. azurerm/storage-account.
+func (idx *moduleIndex) find(name string) (string, error) {
+	ref, err := parseModuleRef(name)
+	if err != nil {
+		return "", err
+	}
+	discovered, _, err := idx.load()
+	if err != nil {
+		return "", err
+	}
+
+	// Modules outside managed_paths can only be targeted with --path, as in the CLI
+	var matches []string
+	unmanaged := false
+	for _, mod := range discovered {
+		if relPath := filepath.ToSlash(mod.Path); mod.Name == ref.Name && ref.matches(relPath) {
+			if !cfg.IsManaged(mod.Path) {
+				unmanaged = true
+				continue
+			}
+			matches = append(matches, relPath)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		if unmanaged {
+			return "", fmt.Errorf("module '%s' is outside managed_paths", name)
+		}
+		return "", fmt.Errorf("module '%s' not found in %s", name, strings.Join(moduleDirNames(), ", "))
+	case 1:
+		return filepath.Join(idx.basePath, filepath.FromSlash(matches[0])), nil
+	default:
+		return "", fmt.Errorf("multiple modules named '%s' found - name clash detected, use one of: %s", name, strings.Join(qualifiedNames(matches), ", "))
+	}
+}
+
+// changed returns the cached modules within managed_paths that have changed
+// in r. A change is attributed to the innermost module containing it, e.g. a
+// change to the tests of a module to the module.
+func (idx *moduleIndex) changed(r changeRange) ([]ModuleInfo, error) {
+	modules, err := idx.all()
+	if err != nil {
+		return nil, err
+	}
+	repoRoot, changedPaths, err := changedModuleDirs(r, idx.basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	relPaths := make([]string, len(modules))
+	for i, mod := range modules {
+		if relPaths[i], err = repoRelativePath(repoRoot, filepath.Join(idx.basePath, mod.Path)); err != nil {
+			return nil, err
+		}
+	}
+
+	changed := make([]bool, len(modules))
+	for _, changedPath := range changedPaths {
+		match := -1
+		for i, relPath := range relPaths {
+			if (changedPath == relPath || strings.HasPrefix(changedPath, relPath+"/")) &&
+				(match < 0 || len(relPath) > len(relPaths[match])) {
+				match = i
+			}
+		}
+		if match >= 0 {
+			changed[match] = true
+		}
+	}
+
+	result := []ModuleInfo{}
+	for i, mod := range modules {
+		if changed[i] {
+			result = append(result, mod)
+		}
+	}
+	return result, nil
+}
+
+// agentListParams are the parameters of the list method
+type agentListParams struct {
+	Search string `json:"search"`
+}
+
+// agentChangedParams are the parameters of the changed method
+type agentChangedParams struct {
+	Ref string `json:"ref"`
+}
+
+// agentPlanParams are the parameters of the plan method
+type agentPlanParams struct {
+	Module string   `json:"module"`
+	Init   bool     `json:"init"`
+	Args   []string `json:"args"`
+}
+
+// newAgentServer creates an agent server with all motf methods registered.
+func newAgentServer(token string, idx *moduleIndex) *agent.Server {
+	server := agent.NewServer(token)
+
+	server.Handle("list", func(raw json.RawMessage, out io.Writer) (any, error) {
+		var params agentListParams
+		if err := decodeAgentParams(raw, &params); err != nil {
+			return nil, err
+		}
+
+		modules, err := idx.all()
+		if err != nil {
+			return nil, err
+		}
+
+		result := []ModuleInfo{}
+		for _, mod := range modules {
+			if params.Search == "" || finder.MatchesWildcard(mod.Name, params.Search) {
+				result = append(result, mod)
+			}
+		}
+		return result, nil
+	})
+
+	server.Handle("changed", func(raw json.RawMessage, out io.Writer) (any, error) {
+		var params agentChangedParams
+		if err := decodeAgentParams(raw, &params); err != nil {
+			return nil, err
+		}
+
+		return idx.changed(changeRange{Ref: params.Ref, MergeBase: true})
+	})
+
+	server.Handle("plan", func(raw json.RawMessage, out io.Writer) (any, error) {
+		var params agentPlanParams
+		if err := decodeAgentParams(raw, &params); err != nil {
+			return nil, err
+		}
+		if params.Module == "" {
+			return nil, fmt.Errorf("plan requires a 'module' parameter")
+		}
+
+		modulePath, err := idx.find(params.Module)
+		if err != nil {
+			return nil, err
+		}
+
+		tfRunner, err := runnerFor(modulePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if params.Init {
+			if err := tfRunner.RunInitWithOutput(modulePath, out, out); err != nil {
+				return nil, err
+			}
+		}
+		if err := tfRunner.RunPlanWithOutput(modulePath, out, out, params.Args...); err != nil {
+			return nil, err
+		}
+		return map[string]string{"module": params.Module, "status": "succeeded"}, nil
+	})
+
+	server.Handle("reload", func(raw json.RawMessage, out io.Writer) (any, error) {
+		modules, err := idx.reload()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int{"modules": len(modules)}, nil
+	})
+
+	return server
+}
+
+// decodeAgentParams decodes optional request parameters into v.
+func decodeAgentParams(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	return nil
+}
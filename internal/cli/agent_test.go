@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestAgentCmd_HasFlags(t *testing.T) {
+	if agentCmd.Flags().Lookup("socket") == nil {
+		t.Fatal("agentCmd should have --socket flag")
+	}
+	if agentCmd.Flags().Lookup("token-file") == nil {
+		t.Fatal("agentCmd should have --token-file flag")
+	}
+}
+
+func TestReadAgentToken_FromFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("  s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := readAgentToken(tokenFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "s3cret" {
+		t.Errorf("expected token 's3cret', got %q", token)
+	}
+}
+
+func TestReadAgentToken_FromEnv(t *testing.T) {
+	t.Setenv(EnvAgentToken, "from-env")
+
+	token, err := readAgentToken("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "from-env" {
+		t.Errorf("expected token 'from-env', got %q", token)
+	}
+}
+
+func TestReadAgentToken_Missing(t *testing.T) {
+	t.Setenv(EnvAgentToken, "")
+
+	if _, err := readAgentToken(""); err == nil {
+		t.Error("expected error when no token is configured")
+	}
+}
+
+func TestModuleIndex_Find(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/azurerm/storage-account")
+	createTerraformModule(t, tmpDir, "projects/prod")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	idx := &moduleIndex{basePath: tmpDir}
+
+	path, err := idx.find("storage-account")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := filepath.Join(tmpDir, "components", "azurerm", "storage-account")
+	if path != want {
+		t.Errorf("expected %s, got %s", want, path)
+	}
+
+	if _, err := idx.find("missing"); err == nil {
+		t.Error("expected error for missing module")
+	}
+}
+
+func TestModuleIndex_FindQualified(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/azurerm/storage-account")
+	createTerraformModule(t, tmpDir, "bases/storage-account")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	idx := &moduleIndex{basePath: tmpDir}
+
+	_, err := idx.find("storage-account")
+	if err == nil || !strings.Contains(err.Error(), "use one of: bases:storage-account, components:storage-account") {
+		t.Errorf("expected name clash error with qualified names, got %v", err)
+	}
+	path, err := idx.find("bases:storage-account")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(tmpDir, "bases", "storage-account"); path != want {
+		t.Errorf("expected %s, got %s", want, path)
+	}
+}
+
+func TestModuleIndex_CachesUntilReload(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/first")
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	idx := &moduleIndex{basePath: tmpDir}
+	modules, err := idx.all()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(modules) != 1 {
+		t.Fatalf("expected 1 module, got %d", len(modules))
+	}
+
+	createTerraformModule(t, tmpDir, "components/second")
+
+	modules, _ = idx.all()
+	if len(modules) != 1 {
+		t.Errorf("expected cached result with 1 module, got %d", len(modules))
+	}
+
+	modules, err = idx.reload()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(modules) != 2 {
+		t.Errorf("expected 2 modules after reload, got %d", len(modules))
+	}
+}
+
+func TestModuleIndex_Changed(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	git("add", "-A")
+	git("commit", "-m", "initial")
+	git("tag", "base")
+
+	testsDir := filepath.Join(tmpDir, DirComponents, "storage-account", "tests")
+	if err := os.MkdirAll(testsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(testsDir, "main.tftest.hcl"), []byte("run \"plan\" {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	withWorkingDir(t, tmpDir)
+	// The agent doesn't read the command line flags
+	sinceFlag = "not a date"
+
+	idx := &moduleIndex{basePath: tmpDir}
+	modules, err := idx.changed(changeRange{Ref: "base", MergeBase: true})
+	if err != nil {
+		t.Fatalf("changed() error = %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "storage-account" {
+		t.Errorf("modules = %v, want [storage-account]", modules)
+	}
+}
+
+func TestModuleIndex_ManagedPaths(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "legacy", "dns"))
+	git("add", "-A")
+	git("commit", "-m", "initial")
+	git("tag", "base")
+
+	if err := os.WriteFile(filepath.Join(tmpDir, DirComponents, "legacy", "dns", "outputs.tf"), []byte("output \"id\" {\n  value = 1\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", ManagedPaths: []string{"components/azurerm"}})
+	withWorkingDir(t, tmpDir)
+
+	idx := &moduleIndex{basePath: tmpDir}
+	modules, err := idx.all()
+	if err != nil {
+		t.Fatalf("all() error = %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "storage-account" {
+		t.Errorf("all() = %v, want [storage-account]", modules)
+	}
+
+	if _, err := idx.find("dns"); err == nil || !strings.Contains(err.Error(), "outside managed_paths") {
+		t.Errorf("expected an unmanaged module error, got %v", err)
+	}
+
+	// Changes to modules outside managed_paths aren't reported
+	changed, err := idx.changed(changeRange{Ref: "base", MergeBase: true})
+	if err != nil {
+		t.Fatalf("changed() error = %v", err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("changed() = %v, want no modules", changed)
+	}
+}
+
+func TestRunAgent_RefusesNonSocket(t *testing.T) {
+	resetFlags(t)
+	t.Setenv(EnvAgentToken, "s3cret")
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	path := filepath.Join(tmpDir, "motf.sock")
+	if err := os.WriteFile(path, []byte("keep me"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	agentSocketFlag = path
+	t.Cleanup(func() { agentSocketFlag = "" })
+
+	err := runAgent(agentCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "is not a socket") {
+		t.Fatalf("expected an error refusing to remove the file, got %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "keep me" {
+		t.Errorf("file at the socket path was modified: %q, %v", data, err)
+	}
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/planfiles"
+	"github.com/spf13/cobra"
+)
+
+// applyFromArtifactsFlag applies the plans saved by motf plan --save
+var applyFromArtifactsFlag bool
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply --from-artifacts",
+	Short: "Apply the plans saved by motf plan --save",
+	Long: `Apply exactly the plans saved by 'motf plan --save', for two-phase flows where
+one CI job plans and a later job, after approval, applies what was reviewed.
+
+Each plan file in the plans directory (.motf/plans under the root by default)
+is applied in its module, and removed once the apply succeeds, so rerunning
+after a failure only applies the remaining plans. A plan that is stale because
+the state changed since it was saved is rejected by terraform/tofu.
+
+Examples:
+  motf plan --changed --save                # Plan and save the changed modules
+  motf plan --show storage-account          # Review a saved plan
+  motf apply --from-artifacts               # Apply the saved plans
+  motf apply --from-artifacts -i -p         # Run init then apply in parallel`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func init() {
+	applyCmd.Flags().BoolVar(&applyFromArtifactsFlag, "from-artifacts", false, "Apply the plans saved by motf plan --save")
+	applyCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
+	_ = applyCmd.MarkFlagRequired("from-artifacts")
+	addParallelFlags(applyCmd)
+	rootCmd.AddCommand(applyCmd)
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	dir := plansDir(basePath)
+	modules, err := savedPlanModules(basePath, dir)
+	if err != nil {
+		return err
+	}
+	if len(modules) == 0 {
+		fmt.Printf("No saved plans in %s (run 'motf plan --save' first)\n", dir)
+		return nil
+	}
+
+	if err := resolveModuleConfigs(basePath, modules); err != nil {
+		return err
+	}
+	if err := resolveRunOrder(basePath, modules); err != nil {
+		return err
+	}
+	if summary := binarySummary(modules); summary != "" {
+		logging.Infof("binaries: %s", summary)
+	}
+
+	return RunOnModulesParallel(modules, cfg.Parallelism, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		moduleAbsPath := filepath.Join(basePath, mod.Path)
+		tfRunner, err := runnerFor(moduleAbsPath)
+		if err != nil {
+			return err
+		}
+		if initFlag {
+			if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+				return err
+			}
+		}
+		if err := tfRunner.RunApplyWithOutput(moduleAbsPath, planfiles.Path(dir, mod.Path), stdout, stderr, argsFlag...); err != nil {
+			return err
+		}
+		if dryRunFlag {
+			return nil
+		}
+		return planfiles.Remove(dir, mod.Path)
+	})
+}
+
+// savedPlanModules returns the modules with a plan file in dir. A plan of a
+// module that no longer exists fails before anything is applied.
+func savedPlanModules(basePath, dir string) ([]ModuleInfo, error) {
+	relPaths, err := planfiles.List(dir)
+	if err != nil {
+		return nil, err
+	}
+	modules := make([]ModuleInfo, 0, len(relPaths))
+	for _, relPath := range relPaths {
+		modulePath := filepath.FromSlash(relPath)
+		moduleAbsPath := filepath.Join(basePath, modulePath)
+		if info, err := os.Stat(moduleAbsPath); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("saved plan for %s, but the module doesn't exist (remove %s)", relPath, planfiles.Path(dir, modulePath))
+		}
+		modules = append(modules, ModuleInfo{
+			Name: filepath.Base(modulePath),
+			Type: getModuleType(moduleAbsPath),
+			Path: modulePath,
+		})
+	}
+	return modules, nil
+}
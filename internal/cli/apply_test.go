@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// savePlanFile writes a plan file for the module at modulePath to the default
+// plans directory under tmpDir.
+func savePlanFile(t *testing.T, tmpDir, modulePath string) string {
+	t.Helper()
+	planFile := filepath.Join(tmpDir, ".motf", "plans", filepath.FromSlash(modulePath)+".tfplan")
+	if err := os.MkdirAll(filepath.Dir(planFile), 0755); err != nil {
+		t.Fatalf("failed to create plans directory: %v", err)
+	}
+	if err := os.WriteFile(planFile, []byte("plan"), 0644); err != nil {
+		t.Fatalf("failed to write plan: %v", err)
+	}
+	return planFile
+}
+
+func TestApplyCmd_Flags(t *testing.T) {
+	for _, name := range []string{"from-artifacts", "init", "parallel"} {
+		if applyCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected apply command to have --%s flag", name)
+		}
+	}
+	if err := applyCmd.ValidateRequiredFlags(); err == nil {
+		t.Error("expected --from-artifacts to be required")
+	}
+}
+
+func TestSavedPlanModules(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, "components/azurerm/storage")
+	createTerraformModule(t, tmpDir, "projects/prod")
+	savePlanFile(t, tmpDir, "projects/prod")
+	savePlanFile(t, tmpDir, "components/azurerm/storage")
+
+	modules, err := savedPlanModules(tmpDir, filepath.Join(tmpDir, ".motf", "plans"))
+	if err != nil {
+		t.Fatalf("savedPlanModules() error = %v", err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("savedPlanModules() returned %d modules, want 2", len(modules))
+	}
+	want := []ModuleInfo{
+		{Name: "storage", Type: TypeComponent, Path: filepath.Join("components", "azurerm", "storage")},
+		{Name: "prod", Type: TypeProject, Path: filepath.Join("projects", "prod")},
+	}
+	for i := range want {
+		if !reflect.DeepEqual(modules[i], want[i]) {
+			t.Errorf("modules[%d] = %+v, want %+v", i, modules[i], want[i])
+		}
+	}
+}
+
+func TestSavedPlanModules_MissingModule(t *testing.T) {
+	tmpDir := t.TempDir()
+	savePlanFile(t, tmpDir, "components/removed")
+
+	_, err := savedPlanModules(tmpDir, filepath.Join(tmpDir, ".motf", "plans"))
+	if err == nil || !strings.Contains(err.Error(), "components/removed") {
+		t.Fatalf("expected missing module error, got %v", err)
+	}
+}
+
+func TestRunApply_DryRunKeepsPlans(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Binary: "terraform"})
+	createTerraformModule(t, tmpDir, "components/storage")
+	planFile := savePlanFile(t, tmpDir, "components/storage")
+	dryRunFlag = true
+
+	if err := runApply(applyCmd, nil); err != nil {
+		t.Fatalf("runApply() error = %v", err)
+	}
+	if _, err := os.Stat(planFile); err != nil {
+		t.Errorf("expected --dry-run to keep the plan: %v", err)
+	}
+}
+
+func TestRunApply_NoSavedPlans(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{})
+
+	if err := runApply(applyCmd, nil); err != nil {
+		t.Errorf("runApply() without saved plans error = %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/backend"
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backendEnvFlags  []string // Environments to write, instead of backend.environments
+	backendCheckFlag bool     // Report out-of-date files instead of writing them
+)
+
+// backendCmd groups the backend commands
+var backendCmd = &cobra.Command{
+	Use:   "backend",
+	Short: "Manage the remote state backend configuration of projects",
+}
+
+var backendInitCmd = &cobra.Command{
+	Use:   "init [project]",
+	Short: "Generate the backend configuration of a project from .motf.yml",
+	Long: `Generate the remote state backend configuration (azurerm, s3, or gcs) of a
+project from the backend section of .motf.yml, so projects don't drift apart
+through copy-paste.
+
+The settings in backend.config can use the placeholders {project} (the project
+name), {path} (its path relative to the root), and {env}. Without environments,
+the full configuration is written to backend.tf. With backend.environments or
+--env, backend.tf holds the settings shared by all environments and
+backends/<env>.tfbackend the settings that use {env}; pass it to init with
+-backend-config=backends/<env>.tfbackend.
+
+With --check, nothing is written and the command fails when a file is missing
+or out of date, e.g. in CI.`,
+	Example: `  motf backend init platform                # Write the backend files of a project
+  motf backend init --all                   # Write them for all projects
+  motf backend init platform --env dev      # Only write the dev environment
+  motf backend init --all --check           # Fail when a backend file is out of date`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runBackendInit,
+}
+
+func init() {
+	backendInitCmd.Flags().StringArrayVar(&backendEnvFlags, "env", nil, "Environment to write a backend config file for, instead of backend.environments (can be specified multiple times)")
+	backendInitCmd.Flags().BoolVar(&backendCheckFlag, "check", false, "Fail when a backend file is missing or out of date instead of writing it")
+	backendInitCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all projects")
+	backendCmd.AddCommand(backendInitCmd)
+	rootCmd.AddCommand(backendCmd)
+}
+
+func runBackendInit(cmd *cobra.Command, args []string) error {
+	if cfg.Backend == nil {
+		return fmt.Errorf("no backend to generate: set backend in %s", config.ConfigFile)
+	}
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	projects, err := backendProjects(basePath, args)
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		cmd.Println("No projects found")
+		return nil
+	}
+
+	envs := backendEnvFlags
+	if len(envs) == 0 {
+		envs = cfg.Backend.GetEnvironments()
+	}
+
+	outdated := 0
+	for _, projectPath := range projects {
+		params := backend.Params{
+			Project: filepath.Base(projectPath),
+			Path:    displayPath(basePath, projectPath),
+		}
+		files, err := backend.Files(cfg.Backend.Type, cfg.Backend.Config, params, envs, cfg.Backend.GetFile(), cfg.Backend.GetConfigDir())
+		if err != nil {
+			return fmt.Errorf("failed to generate the backend of %s: %w", params.Path, err)
+		}
+		n, err := writeBackendFiles(cmd, basePath, projectPath, files)
+		if err != nil {
+			return err
+		}
+		outdated += n
+	}
+
+	if backendCheckFlag && outdated > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d backend file(s) out of date: run 'motf backend init' to update them", outdated)
+	}
+	return nil
+}
+
+// backendProjects returns the paths of the projects to generate the backend
+// of: all projects with --all, otherwise the target, which must be a project.
+func backendProjects(basePath string, args []string) ([]string, error) {
+	if !allFlag {
+		targetPath, err := resolveTargetPath(args)
+		if err != nil {
+			return nil, err
+		}
+		if moduleType := moduleDirType(targetPath); moduleType != "" && moduleType != TypeProject {
+			return nil, fmt.Errorf("%s is a %s: backends are generated for projects", displayPath(basePath, targetPath), moduleType)
+		}
+		return []string{targetPath}, nil
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("cannot use a project name with --all")
+	}
+
+	modules, err := collectModules(basePath, "")
+	if err != nil {
+		return nil, err
+	}
+	sortModules(modules)
+	var projects []string
+	for _, mod := range modules {
+		if moduleDirType(filepath.Join(basePath, mod.Path)) == TypeProject {
+			projects = append(projects, filepath.Join(basePath, mod.Path))
+		}
+	}
+	return projects, nil
+}
+
+// writeBackendFiles writes the backend files of the project at projectPath
+// that are missing or differ, and returns how many did. With --check they are
+// reported instead, and in dry-run mode printed.
+func writeBackendFiles(cmd *cobra.Command, basePath, projectPath string, files map[string][]byte) (int, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	changed := 0
+	for _, name := range names {
+		path := filepath.Join(projectPath, name)
+		shown := displayPath(basePath, path)
+		current, err := os.ReadFile(filepath.Clean(path))
+		if err == nil && bytes.Equal(current, files[name]) {
+			cmd.Printf("Up to date %s\n", shown)
+			continue
+		}
+		changed++
+
+		switch {
+		case backendCheckFlag:
+			if err != nil {
+				cmd.Printf("Missing %s\n", shown)
+			} else {
+				cmd.Printf("Out of date %s\n", shown)
+			}
+		case dryRunFlag:
+			cmd.Printf("[dry-run] Would write %s:\n%s\n", shown, files[name])
+		default:
+			if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+				return changed, fmt.Errorf("failed to create %s: %w", filepath.Dir(shown), err)
+			}
+			if err := os.WriteFile(path, files[name], 0600); err != nil {
+				return changed, fmt.Errorf("failed to write %s: %w", shown, err)
+			}
+			cmd.Printf("Wrote %s\n", shown)
+		}
+	}
+	return changed, nil
+}
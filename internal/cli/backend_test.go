@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func backendTestConfig(root string) *config.Config {
+	return &config.Config{
+		Root: root,
+		Backend: &config.BackendConfig{
+			Type:         "s3",
+			Environments: []string{"dev", "prod"},
+			Config: map[string]any{
+				"bucket": "tfstate-{env}",
+				"key":    "{path}/terraform.tfstate",
+				"region": "eu-west-1",
+			},
+		},
+	}
+}
+
+func runBackendInitCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	err := runBackendInit(cmd, args)
+	return out.String(), err
+}
+
+func TestBackendInit_WritesFiles(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, backendTestConfig(tmpDir))
+	withWorkingDir(t, tmpDir)
+	projectPath := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+
+	out, err := runBackendInitCmd(t, "platform")
+	if err != nil {
+		t.Fatalf("runBackendInit() error = %v", err)
+	}
+	for _, want := range []string{"Wrote projects/platform/backend.tf", "Wrote projects/platform/backends/dev.tfbackend", "Wrote projects/platform/backends/prod.tfbackend"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	backendTF, err := os.ReadFile(filepath.Join(projectPath, "backend.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(backendTF), `key    = "projects/platform/terraform.tfstate"`) || strings.Contains(string(backendTF), "bucket") {
+		t.Errorf("backend.tf should hold the shared settings, got:\n%s", backendTF)
+	}
+	prod, err := os.ReadFile(filepath.Join(projectPath, "backends", "prod.tfbackend"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(prod), `bucket = "tfstate-prod"`) {
+		t.Errorf("prod.tfbackend should hold the prod bucket, got:\n%s", prod)
+	}
+
+	out, err = runBackendInitCmd(t, "platform")
+	if err != nil {
+		t.Fatalf("runBackendInit() error = %v", err)
+	}
+	if strings.Contains(out, "Wrote") {
+		t.Errorf("expected up-to-date files not to be rewritten, got:\n%s", out)
+	}
+}
+
+func TestBackendInit_Check(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, backendTestConfig(tmpDir))
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "data"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+	allFlag = true
+	backendEnvFlags = []string{"dev"}
+
+	if _, err := runBackendInitCmd(t, "platform"); err == nil || !strings.Contains(err.Error(), "with --all") {
+		t.Errorf("expected an error for a project name with --all, got %v", err)
+	}
+	if _, err := runBackendInitCmd(t); err != nil {
+		t.Fatalf("runBackendInit() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, DirComponents, "dns", "backend.tf")); !os.IsNotExist(err) {
+		t.Error("expected no backend.tf for a component")
+	}
+
+	backendCheckFlag = true
+	if _, err := runBackendInitCmd(t); err != nil {
+		t.Errorf("expected up-to-date files to pass the check, got %v", err)
+	}
+	stale := filepath.Join(tmpDir, DirProjects, "data", "backends", "dev.tfbackend")
+	if err := os.WriteFile(stale, []byte(`bucket = "old"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runBackendInitCmd(t)
+	if err == nil || !strings.Contains(err.Error(), "1 backend file(s) out of date") {
+		t.Errorf("expected an out-of-date error, got %v", err)
+	}
+	if !strings.Contains(out, "Out of date projects/data/backends/dev.tfbackend") {
+		t.Errorf("expected the stale file to be reported, got:\n%s", out)
+	}
+	if data, _ := os.ReadFile(stale); string(data) != `bucket = "old"` {
+		t.Error("expected --check not to write files")
+	}
+}
+
+func TestBackendInit_Errors(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+
+	withConfig(t, &config.Config{Root: tmpDir})
+	if _, err := runBackendInitCmd(t, "dns"); err == nil || !strings.Contains(err.Error(), "set backend in .motf.yml") {
+		t.Errorf("expected an error without a backend section, got %v", err)
+	}
+
+	withConfig(t, backendTestConfig(tmpDir))
+	if _, err := runBackendInitCmd(t, "dns"); err == nil || !strings.Contains(err.Error(), "components/dns is a component") {
+		t.Errorf("expected an error for a component, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchRunsFlag int  // Number of timed runs per operation
+	benchJSONFlag bool // Output results as JSON
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Measure how long repository operations take",
+	Long: `Measure how long motf's repository operations take and report percentiles.
+
+Each operation is run --runs times:
+  discovery         walk components/, bases/, and projects/ to find modules
+  change-detection  diff against --ref and map changed files to modules
+  schema-parsing    parse variables, outputs, and providers of every module
+
+Modules that fail to parse are skipped by schema-parsing. Operations that fail
+(e.g. change-detection outside a git repository) are reported with their error.
+Track the output over time to spot regressions in motf or the effect of repo growth.`,
+	Example: `  motf bench                  # Run each operation 10 times
+  motf bench --runs 50        # More runs for stable percentiles
+  motf bench --json           # Machine-readable output for tracking over time`,
+	Args: cobra.NoArgs,
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().IntVarP(&benchRunsFlag, "runs", "n", 10, "Number of timed runs per operation")
+	benchCmd.Flags().BoolVar(&benchJSONFlag, "json", false, "Output results as JSON")
+	benchCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for change detection (default: auto-detect from origin/HEAD)")
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchOperation is a timed repository operation. run returns the number of
+// modules it processed.
+type benchOperation struct {
+	name string
+	run  func() (int, error)
+}
+
+// benchResult holds the timings of a benchmarked operation
+type benchResult struct {
+	Operation string        `json:"operation"`
+	Runs      int           `json:"runs"`
+	Modules   int           `json:"modules"`
+	Min       time.Duration `json:"min"`
+	P50       time.Duration `json:"p50"`
+	P90       time.Duration `json:"p90"`
+	P99       time.Duration `json:"p99"`
+	Max       time.Duration `json:"max"`
+	Error     string        `json:"error,omitempty"`
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if benchRunsFlag < 1 {
+		return fmt.Errorf("--runs must be at least 1")
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	results := make([]benchResult, 0, 3)
+	for _, op := range benchOperations(basePath) {
+		results = append(results, runBenchOperation(op, benchRunsFlag))
+	}
+
+	if benchJSONFlag {
+		output, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	printBenchResults(results)
+	return nil
+}
+
+// benchOperations returns the operations to benchmark for the repo at basePath.
+func benchOperations(basePath string) []benchOperation {
+	return []benchOperation{
+		{
+			name: "discovery",
+			run: func() (int, error) {
+				modules, err := collectModules(basePath, "")
+				return len(modules), err
+			},
+		},
+		{
+			name: "change-detection",
+			run: func() (int, error) {
+				modules, err := detectChangedModules(changeRangeFlags())
+				return len(modules), err
+			},
+		},
+		{
+			name: "schema-parsing",
+			run: func() (int, error) {
+				modules, err := collectModules(basePath, "")
+				if err != nil {
+					return 0, err
+				}
+				parsed := 0
+				for _, mod := range modules {
+					if _, err := terraform.LoadModuleSchema(filepath.Join(basePath, mod.Path), getRoot()); err == nil {
+						parsed++
+					}
+				}
+				return parsed, nil
+			},
+		},
+	}
+}
+
+// runBenchOperation runs op the given number of times and computes percentiles.
+// The first failure stops the operation and is recorded in the result.
+func runBenchOperation(op benchOperation, runs int) benchResult {
+	result := benchResult{Operation: op.name}
+	durations := make([]time.Duration, 0, runs)
+
+	for range runs {
+		start := time.Now()
+		items, err := op.run()
+		elapsed := time.Since(start)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.Modules = items
+		durations = append(durations, elapsed)
+	}
+
+	slices.Sort(durations)
+	result.Runs = len(durations)
+	result.Min = durations[0]
+	result.P50 = percentile(durations, 50)
+	result.P90 = percentile(durations, 90)
+	result.P99 = percentile(durations, 99)
+	result.Max = durations[len(durations)-1]
+	return result
+}
+
+// percentile returns the p-th percentile of sorted durations using the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	return sorted[max(rank, 1)-1]
+}
+
+// printBenchResults outputs the results as a table, or one labeled line per value in plain mode
+func printBenchResults(results []benchResult) {
+	if plainFlag {
+		for i, r := range results {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("Operation: %s\n", r.Operation)
+			if r.Error != "" {
+				fmt.Printf("Error: %s\n", r.Error)
+				continue
+			}
+			fmt.Printf("Runs: %d\n", r.Runs)
+			fmt.Printf("Modules: %d\n", r.Modules)
+			fmt.Printf("Min: %s\n", formatBenchDuration(r.Min))
+			fmt.Printf("P50: %s\n", formatBenchDuration(r.P50))
+			fmt.Printf("P90: %s\n", formatBenchDuration(r.P90))
+			fmt.Printf("P99: %s\n", formatBenchDuration(r.P99))
+			fmt.Printf("Max: %s\n", formatBenchDuration(r.Max))
+		}
+		return
+	}
+
+	opWidth := len("OPERATION")
+	for _, r := range results {
+		opWidth = max(opWidth, len(r.Operation))
+	}
+
+	fmt.Printf("%-*s  %5s  %7s  %9s  %9s  %9s  %9s  %9s\n", opWidth, "OPERATION", "RUNS", "MODULES", "MIN", "P50", "P90", "P99", "MAX")
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Printf("%-*s  error: %s\n", opWidth, r.Operation, r.Error)
+			continue
+		}
+		fmt.Printf("%-*s  %5d  %7d", opWidth, r.Operation, r.Runs, r.Modules)
+		for _, d := range []time.Duration{r.Min, r.P50, r.P90, r.P99, r.Max} {
+			fmt.Printf("  %s", padLeft(formatBenchDuration(d), 9))
+		}
+		fmt.Println()
+	}
+}
+
+// padLeft right-aligns s in width columns. Unlike %9s, it counts runes, so
+// durations in µs stay aligned.
+func padLeft(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
+
+// formatBenchDuration rounds durations to a readable precision.
+func formatBenchDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 10)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := []struct {
+		p    int
+		want time.Duration
+	}{
+		{p: 0, want: 1 * time.Millisecond},
+		{p: 50, want: 5 * time.Millisecond},
+		{p: 90, want: 9 * time.Millisecond},
+		{p: 99, want: 10 * time.Millisecond},
+		{p: 100, want: 10 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%d) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile of empty slice = %v, want 0", got)
+	}
+}
+
+func TestRunBenchOperation(t *testing.T) {
+	calls := 0
+	result := runBenchOperation(benchOperation{
+		name: "count",
+		run: func() (int, error) {
+			calls++
+			return 3, nil
+		},
+	}, 5)
+
+	if calls != 5 || result.Runs != 5 || result.Modules != 3 {
+		t.Errorf("unexpected result: calls=%d %+v", calls, result)
+	}
+	if result.Min > result.P50 || result.P50 > result.P90 || result.P90 > result.Max {
+		t.Errorf("expected ordered percentiles, got %+v", result)
+	}
+}
+
+func TestRunBenchOperation_Error(t *testing.T) {
+	calls := 0
+	result := runBenchOperation(benchOperation{
+		name: "broken",
+		run: func() (int, error) {
+			calls++
+			return 0, errors.New("not a git repository")
+		},
+	}, 5)
+
+	if calls != 1 {
+		t.Errorf("expected the operation to stop after the first failure, ran %d times", calls)
+	}
+	if result.Error != "not a git repository" || result.Runs != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestBenchOperations(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "prod"))
+
+	results := make(map[string]benchResult)
+	for _, op := range benchOperations(tmpDir) {
+		results[op.name] = runBenchOperation(op, 2)
+	}
+
+	if r := results["discovery"]; r.Error != "" || r.Modules != 2 || r.Runs != 2 {
+		t.Errorf("unexpected discovery result: %+v", r)
+	}
+	if r := results["schema-parsing"]; r.Error != "" || r.Modules != 2 {
+		t.Errorf("unexpected schema-parsing result: %+v", r)
+	}
+	if _, ok := results["change-detection"]; !ok {
+		t.Error("expected a change-detection result")
+	}
+}
+
+func TestBenchCmd_InvalidRuns(t *testing.T) {
+	resetFlags(t)
+	withConfig(t, config.DefaultConfig())
+	benchRunsFlag = 0
+	t.Cleanup(func() { benchRunsFlag = 10 })
+
+	if err := runBench(benchCmd, nil); err == nil {
+		t.Error("expected error for --runs 0")
+	}
+}
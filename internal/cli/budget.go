@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// skippedError marks a module that wasn't run, e.g. because its test cost
+// exceeds the --max-cost budget. It is reported but doesn't fail the run.
+type skippedError struct {
+	reason string
+}
+
+func (e *skippedError) Error() string {
+	return "skipped: " + e.reason
+}
+
+// costBudget skips module tests once their estimated costs exceed the
+// --max-cost budget of a run.
+type costBudget struct {
+	max     float64
+	skipped map[string]string // Reasons by module path
+	order   []ModuleInfo      // Skipped modules, in the order of the run
+	spent   float64           // Estimated cost of the modules that run
+}
+
+// newCostBudget returns the budget of max for modules, or nil when max is 0.
+// Modules are admitted cheapest first, so as many tests as possible fit the
+// budget; modules without a cost are always run.
+func newCostBudget(max float64, modules []ModuleInfo) *costBudget {
+	if max <= 0 {
+		return nil
+	}
+	b := &costBudget{max: max, skipped: map[string]string{}}
+	byCost := make([]ModuleInfo, len(modules))
+	copy(byCost, modules)
+	sort.SliceStable(byCost, func(i, j int) bool { return byCost[i].Cost < byCost[j].Cost })
+	for _, mod := range byCost {
+		if b.spent+mod.Cost <= max {
+			b.spent += mod.Cost
+			continue
+		}
+		b.skipped[mod.Path] = fmt.Sprintf("estimated cost %s exceeds the remaining budget %s of --max-cost %s",
+			formatCost(mod.Cost), formatCost(max-b.spent), formatCost(max))
+	}
+	for _, mod := range modules {
+		if _, ok := b.skipped[mod.Path]; ok {
+			b.order = append(b.order, mod)
+		}
+	}
+	return b
+}
+
+// wrap returns fn, skipping the modules over the budget.
+func (b *costBudget) wrap(fn ModuleRunner) ModuleRunner {
+	if b == nil || len(b.skipped) == 0 {
+		return fn
+	}
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		if reason, ok := b.skipped[mod.Path]; ok {
+			_, _ = fmt.Fprintf(stderr, "[budget] Skipped: %s\n", reason)
+			return &skippedError{reason: reason}
+		}
+		return fn(mod, stdout, stderr)
+	}
+}
+
+// report outputs the modules skipped over the budget and why on stderr.
+func (b *costBudget) report() {
+	if b == nil || len(b.order) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "\nSkipped %d module(s) over the --max-cost budget of %s (estimated cost of the run: %s):\n",
+		len(b.order), formatCost(b.max), formatCost(b.spent))
+	for _, mod := range b.order {
+		_, _ = fmt.Fprintf(os.Stderr, "  %s: %s\n", mod.Path, b.skipped[mod.Path])
+	}
+}
+
+// formatCost formats a cost without trailing zeros, e.g. 12.5 or 50.
+func formatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', -1, 64)
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNewCostBudget_NoBudget(t *testing.T) {
+	if b := newCostBudget(0, []ModuleInfo{{Path: "a", Cost: 10}}); b != nil {
+		t.Errorf("newCostBudget(0) = %+v, want nil", b)
+	}
+	// A nil budget runs every module
+	var b *costBudget
+	called := false
+	fn := b.wrap(func(ModuleInfo, io.Writer, io.Writer) error { called = true; return nil })
+	if err := fn(ModuleInfo{Path: "a"}, io.Discard, io.Discard); err != nil || !called {
+		t.Errorf("nil budget: err = %v, called = %v", err, called)
+	}
+	b.report()
+}
+
+func TestNewCostBudget_AdmitsCheapestFirst(t *testing.T) {
+	modules := []ModuleInfo{
+		{Name: "aks", Path: "components/aks", Cost: 40},
+		{Name: "vnet", Path: "components/vnet", Cost: 5},
+		{Name: "naming", Path: "components/naming"},
+		{Name: "sql", Path: "components/sql", Cost: 20},
+		{Name: "storage", Path: "components/storage", Cost: 20},
+	}
+	b := newCostBudget(50, modules)
+
+	var skipped []string
+	for _, mod := range b.order {
+		skipped = append(skipped, mod.Name)
+	}
+	// naming, vnet, sql, and the first of storage fit 45 of 50; aks doesn't
+	if got := strings.Join(skipped, ","); got != "aks" {
+		t.Errorf("skipped = %s, want aks", got)
+	}
+	if b.spent != 45 {
+		t.Errorf("spent = %g, want 45", b.spent)
+	}
+	if want := "estimated cost 40 exceeds the remaining budget 5 of --max-cost 50"; b.skipped["components/aks"] != want {
+		t.Errorf("reason = %q, want %q", b.skipped["components/aks"], want)
+	}
+}
+
+func TestCostBudget_Wrap(t *testing.T) {
+	modules := []ModuleInfo{
+		{Name: "aks", Path: "components/aks", Cost: 80},
+		{Name: "vnet", Path: "components/vnet", Cost: 5},
+	}
+	b := newCostBudget(50, modules)
+
+	var ran []string
+	fn := b.wrap(func(mod ModuleInfo, _, _ io.Writer) error {
+		ran = append(ran, mod.Name)
+		return nil
+	})
+	var stderr strings.Builder
+	for _, mod := range modules {
+		err := fn(mod, io.Discard, &stderr)
+		var skipped *skippedError
+		if mod.Name == "aks" && (!errors.As(err, &skipped) || !isNonFatal(err)) {
+			t.Errorf("expected non-fatal skipped error for aks, got %v", err)
+		}
+	}
+	if got := strings.Join(ran, ","); got != "vnet" {
+		t.Errorf("ran = %s, want vnet", got)
+	}
+	if !strings.Contains(stderr.String(), "[budget] Skipped: estimated cost 80") {
+		t.Errorf("expected budget note on stderr, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/pins"
+	"github.com/TechnicallyJoe/terraform-motf/internal/release"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bumpSourcesCheckFlag    bool // Fail when pinned references are outdated
+	bumpSourcesApplyFlag    bool // Update outdated pinned references
+	bumpSourcesJSONFlag     bool // Output the updates as JSON
+	bumpSourcesMarkdownFlag bool // Output the updates as a Markdown summary
+)
+
+// bumpSourcesCmd represents the bump-sources command
+var bumpSourcesCmd = &cobra.Command{
+	Use:   "bump-sources",
+	Short: "Update module references pinned to older releases of this repository's modules",
+	Long: `Find module blocks in components, bases, and projects that use a released
+version of another module in this repository, and update them when a newer
+release exists. Pinned references are:
+  git sources whose ref is a release tag of their subdirectory, e.g.
+    git::https://github.com/acme/infra.git//components/storage?ref=components/storage/v1.2.3
+  registry sources with a version constraint, for the hosts in sources.registries
+    (default: spacelift.io), matched to modules by directory name
+
+The latest release of a module is the higher of its module_version in
+.spacelift/config.yml and its latest release tag (see motf release). Exact
+versions and git refs are set to it; ~> constraints keep their precision and
+>= constraints are left alone.
+
+With --check, the command exits with an error when references are outdated.
+With --apply, they are updated in place, preserving formatting and comments.
+Use --markdown to print a summary for the body of an automated pull request.`,
+	Example: `  motf bump-sources --check                # Fail if pinned references are outdated
+  motf bump-sources --apply                # Update them
+  motf bump-sources --apply --markdown     # Update and print a pull request summary`,
+	Args: cobra.NoArgs,
+	RunE: runBumpSources,
+}
+
+func init() {
+	bumpSourcesCmd.Flags().BoolVar(&bumpSourcesCheckFlag, "check", false, "Exit with an error when pinned references are outdated")
+	bumpSourcesCmd.Flags().BoolVar(&bumpSourcesApplyFlag, "apply", false, "Update outdated pinned references")
+	bumpSourcesCmd.Flags().BoolVar(&bumpSourcesJSONFlag, "json", false, "Output the updates as JSON")
+	bumpSourcesCmd.Flags().BoolVar(&bumpSourcesMarkdownFlag, "markdown", false, "Output the updates as a Markdown summary")
+	rootCmd.AddCommand(bumpSourcesCmd)
+}
+
+func runBumpSources(cmd *cobra.Command, args []string) error {
+	if bumpSourcesCheckFlag == bumpSourcesApplyFlag {
+		return fmt.Errorf("bump-sources requires one of --check or --apply")
+	}
+	if bumpSourcesJSONFlag && bumpSourcesMarkdownFlag {
+		return fmt.Errorf("--json cannot be used with --markdown")
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to get git root: %w", err)
+	}
+	latest, err := latestReleases(repoRoot, basePath)
+	if err != nil {
+		return err
+	}
+	found, err := findPins(basePath)
+	if err != nil {
+		return err
+	}
+	updates := pinUpdates(found, latest)
+
+	if bumpSourcesApplyFlag && len(updates) > 0 && !dryRunFlag {
+		if err := pins.Apply(basePath, updates); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case bumpSourcesJSONFlag:
+		if updates == nil {
+			updates = []pins.Update{}
+		}
+		output, err := json.MarshalIndent(updates, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	case bumpSourcesMarkdownFlag:
+		printPinUpdatesMarkdown(updates)
+	default:
+		printPinUpdates(updates)
+	}
+
+	if bumpSourcesCheckFlag && len(updates) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d pinned module reference(s) are outdated", len(updates), len(found))
+	}
+	if !bumpSourcesJSONFlag && !bumpSourcesMarkdownFlag {
+		switch {
+		case len(updates) == 0:
+			fmt.Printf("All %d pinned module reference(s) are up to date\n", len(found))
+		case dryRunFlag:
+			fmt.Printf("[dry-run] Would update %d pinned module reference(s)\n", len(updates))
+		default:
+			fmt.Printf("Updated %d pinned module reference(s)\n", len(updates))
+		}
+	}
+	return nil
+}
+
+// latestReleases returns the latest released version of each module under
+// basePath by its path relative to repoRoot: the higher of its module_version
+// and its latest release tag. Modules without either are left out.
+func latestReleases(repoRoot, basePath string) (map[string]string, error) {
+	modules, err := discoverModules(basePath, "")
+	if err != nil {
+		return nil, err
+	}
+	tags, err := release.Git{Dir: repoRoot}.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]string)
+	for _, mod := range modules {
+		relPath, err := repoRelativePath(repoRoot, filepath.Join(basePath, mod.Path))
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", mod.Path, err)
+		}
+		version := strings.TrimPrefix(mod.Version, "v")
+		if !release.IsVersion(version) {
+			version = ""
+		}
+		if _, tagVersion := release.LatestTag(tags, relPath); release.CompareVersions(tagVersion, version) > 0 {
+			version = tagVersion
+		}
+		if version != "" {
+			latest[relPath] = version
+		}
+	}
+	return latest, nil
+}
+
+// findPins returns the pinned module references in the module directories
+// under basePath, leaving out modules outside managed_paths. File paths are
+// relative to basePath.
+func findPins(basePath string) ([]pins.Pin, error) {
+	var found []pins.Pin
+	for _, d := range searchDirs() {
+		moduleDir := d.Dir
+		searchPath := filepath.Join(basePath, moduleDir)
+
+		// Skip if directory doesn't exist
+		if _, err := os.Stat(searchPath); os.IsNotExist(err) {
+			continue
+		}
+
+		dirPins, err := pins.Find(searchPath, cfg.Sources.GetRegistries())
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", moduleDir, err)
+		}
+		for _, pin := range dirPins {
+			pin.File = path.Join(moduleDir, pin.File)
+			if cfg.IsManaged(path.Dir(pin.File)) {
+				found = append(found, pin)
+			}
+		}
+	}
+	return found, nil
+}
+
+// pinUpdates returns the updates of the pins whose module has a newer
+// release. Git pins are matched to modules by path, registry pins by
+// directory name; registry pins matching several modules and constraints that
+// can't be updated are reported on stderr.
+func pinUpdates(found []pins.Pin, latest map[string]string) []pins.Update {
+	byName := make(map[string][]string)
+	for modPath := range latest {
+		byName[path.Base(modPath)] = append(byName[path.Base(modPath)], modPath)
+	}
+
+	var updates []pins.Update
+	for _, pin := range found {
+		target := pin.Target
+		if pin.Kind == pins.KindRegistry {
+			matches := byName[pin.Target]
+			if len(matches) > 1 {
+				sort.Strings(matches)
+				logging.Warnf("%s:%d: module.%s matches several modules (%s), skipping", pin.File, pin.Line, pin.Module, strings.Join(matches, ", "))
+				continue
+			}
+			if len(matches) == 0 {
+				continue
+			}
+			target = matches[0]
+		}
+
+		version, ok := latest[target]
+		if !ok {
+			continue
+		}
+		update, outdated, err := pins.Check(pin, version)
+		if err != nil {
+			logging.Warnf("%s:%d: module.%s: %s, skipping", pin.File, pin.Line, pin.Module, err)
+			continue
+		}
+		if outdated {
+			updates = append(updates, update)
+		}
+	}
+	return updates
+}
+
+// pinChange returns the old and new value shown for an update: the versions
+// of a git ref, or the version constraints of a registry source.
+func pinChange(u pins.Update) (string, string) {
+	if u.Kind == pins.KindGit {
+		return u.Version, u.Latest
+	}
+	return u.Version, u.To
+}
+
+// printPinUpdates outputs the updates as a table
+func printPinUpdates(updates []pins.Update) {
+	if len(updates) == 0 {
+		return
+	}
+	verb := "Outdated"
+	if bumpSourcesApplyFlag {
+		verb = "Updated"
+	}
+
+	if plainFlag {
+		for i, u := range updates {
+			if i > 0 {
+				fmt.Println()
+			}
+			from, to := pinChange(u)
+			fmt.Printf("%s: %s:%d\n", verb, u.File, u.Line)
+			fmt.Printf("Module: %s\n", u.Module)
+			fmt.Printf("From: %s\n", from)
+			fmt.Printf("To: %s\n", to)
+		}
+		fmt.Println()
+		return
+	}
+
+	refWidth, moduleWidth, fromWidth := len("REFERENCE"), len("MODULE"), len("FROM")
+	for _, u := range updates {
+		from, _ := pinChange(u)
+		refWidth = max(refWidth, len(fmt.Sprintf("%s:%d", u.File, u.Line)))
+		moduleWidth = max(moduleWidth, len("module."+u.Module))
+		fromWidth = max(fromWidth, len(from))
+	}
+	fmt.Printf("%-*s  %-*s  %-*s  %s\n", refWidth, "REFERENCE", moduleWidth, "MODULE", fromWidth, "FROM", "TO")
+	for _, u := range updates {
+		from, to := pinChange(u)
+		fmt.Printf("%-*s  %-*s  %-*s  %s\n", refWidth, fmt.Sprintf("%s:%d", u.File, u.Line), moduleWidth, "module."+u.Module, fromWidth, from, to)
+	}
+}
+
+// printPinUpdatesMarkdown outputs the updates as a Markdown summary for the
+// body of a pull request
+func printPinUpdatesMarkdown(updates []pins.Update) {
+	if len(updates) == 0 {
+		fmt.Println("All pinned module references are up to date.")
+		return
+	}
+	fmt.Printf("Updates %d pinned module reference(s) to the latest releases.\n\n", len(updates))
+	fmt.Println("| Reference | Module | From | To |")
+	fmt.Println("|-----------|--------|------|----|")
+	for _, u := range updates {
+		from, to := pinChange(u)
+		fmt.Printf("| `%s:%d` | `module.%s` | `%s` | `%s` |\n", u.File, u.Line, u.Module, from, to)
+	}
+}
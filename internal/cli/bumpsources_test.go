@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func resetBumpSourcesFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		bumpSourcesCheckFlag = false
+		bumpSourcesApplyFlag = false
+		bumpSourcesJSONFlag = false
+		bumpSourcesMarkdownFlag = false
+	})
+}
+
+// setupBumpSourcesRepo creates a repository with a released storage component
+// (module_version 1.3.0) and a network component (tagged v2.1.0), and a
+// project pinning older releases of both. It returns the project's main.tf.
+func setupBumpSourcesRepo(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	git("config", "tag.gpgSign", "false")
+	git("config", "commit.gpgSign", "false")
+
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createSpaceliftModule(t, tmpDir, filepath.Join(DirComponents, "storage"), "1.3.0")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "network"))
+	project := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "prod"))
+	mainTF := filepath.Join(project, "main.tf")
+	content := `module "storage" {
+  source = "git::https://github.com/acme/infra.git//components/storage?ref=components/storage/v1.2.0"
+}
+
+module "network" {
+  source  = "spacelift.io/acme/network/azurerm"
+  version = "~> 1.0"
+}
+`
+	if err := os.WriteFile(mainTF, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+	git("add", "-A")
+	git("commit", "-m", "feat: add modules")
+	git("tag", "components/network/v2.1.0")
+	return mainTF
+}
+
+func TestBumpSourcesCmd_Flags(t *testing.T) {
+	for _, name := range []string{"check", "apply", "json", "markdown"} {
+		if bumpSourcesCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected bump-sources command to have --%s flag", name)
+		}
+	}
+}
+
+func TestBumpSources_RequiresCheckOrApply(t *testing.T) {
+	resetFlags(t)
+	resetBumpSourcesFlags(t)
+
+	err := runBumpSources(bumpSourcesCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "one of --check or --apply") {
+		t.Fatalf("expected --check/--apply error, got %v", err)
+	}
+}
+
+func TestBumpSources_Check(t *testing.T) {
+	resetFlags(t)
+	resetBumpSourcesFlags(t)
+	mainTF := setupBumpSourcesRepo(t)
+	before, _ := os.ReadFile(mainTF)
+	bumpSourcesCheckFlag = true
+
+	err := runBumpSources(bumpSourcesCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "2 of 2 pinned module reference(s) are outdated") {
+		t.Fatalf("expected outdated error, got %v", err)
+	}
+	if after, _ := os.ReadFile(mainTF); string(after) != string(before) {
+		t.Error("expected --check not to change files")
+	}
+}
+
+func TestBumpSources_Apply(t *testing.T) {
+	resetFlags(t)
+	resetBumpSourcesFlags(t)
+	mainTF := setupBumpSourcesRepo(t)
+	bumpSourcesApplyFlag = true
+
+	if err := runBumpSources(bumpSourcesCmd, nil); err != nil {
+		t.Fatalf("runBumpSources() error = %v", err)
+	}
+	data, err := os.ReadFile(mainTF)
+	if err != nil {
+		t.Fatalf("failed to read main.tf: %v", err)
+	}
+	for _, want := range []string{"?ref=components/storage/v1.3.0", `version = "~> 2.1"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected main.tf to contain %q, got:\n%s", want, data)
+		}
+	}
+
+	// Everything is up to date now
+	bumpSourcesApplyFlag = false
+	bumpSourcesCheckFlag = true
+	if err := runBumpSources(bumpSourcesCmd, nil); err != nil {
+		t.Errorf("expected no outdated references after --apply, got %v", err)
+	}
+}
+
+func TestBumpSources_ApplyDryRun(t *testing.T) {
+	resetFlags(t)
+	resetBumpSourcesFlags(t)
+	mainTF := setupBumpSourcesRepo(t)
+	before, _ := os.ReadFile(mainTF)
+	bumpSourcesApplyFlag = true
+	dryRunFlag = true
+
+	if err := runBumpSources(bumpSourcesCmd, nil); err != nil {
+		t.Fatalf("runBumpSources() error = %v", err)
+	}
+	if after, _ := os.ReadFile(mainTF); string(after) != string(before) {
+		t.Error("expected --dry-run not to change files")
+	}
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/procgroup"
+)
+
+// timeoutFlag is the maximum duration of each module in a multi-module run
+var timeoutFlag time.Duration
+
+// failFastFlag stops a multi-module run once a module fails
+var failFastFlag bool
+
+// errInterrupted is the cause of runContext once motf is interrupted
+var errInterrupted = errors.New("interrupted")
+
+// runContext is done once motf is interrupted (Ctrl+C or SIGTERM). Commands
+// run in their own process group, so they are stopped through it rather than
+// by the terminal.
+var runContext = context.Background()
+
+// handleInterrupts sets runContext to a context that is canceled on the first
+// interrupt: running commands are interrupted so terraform can release state
+// locks, and killed after the grace period. Later interrupts are ignored so
+// motf can wait for them. The returned function stops handling interrupts.
+func handleInterrupts() func() {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	runContext = ctx
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			fmt.Fprintf(os.Stderr, "\nInterrupted: stopping running commands, waiting up to %s for them to release state locks\n", procgroup.GracePeriod)
+			cancel(errInterrupted)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+		cancel(nil)
+	}
+}
+
+// moduleContexts holds the context of each running module of a multi-module
+// run with a timeout, by the module's absolute path
+var moduleContexts = struct {
+	sync.Mutex
+	byDir map[string]context.Context
+}{byDir: make(map[string]context.Context)}
+
+// moduleContext returns the context that commands run in dir stop with: the
+// context of the running module dir is in (e.g. for an example), or
+// runContext.
+func moduleContext(dir string) context.Context {
+	moduleContexts.Lock()
+	defer moduleContexts.Unlock()
+	if len(moduleContexts.byDir) == 0 {
+		return runContext
+	}
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if ctx, ok := moduleContexts.byDir[d]; ok {
+			return ctx
+		}
+		if filepath.Dir(d) == d {
+			return runContext
+		}
+	}
+}
+
+// moduleTimeoutError is the cause of a module's context once the module ran
+// longer than --timeout or parallelism.timeout.
+type moduleTimeoutError struct {
+	timeout time.Duration
+}
+
+func (e *moduleTimeoutError) Error() string {
+	return fmt.Sprintf("module timed out after %s", e.timeout)
+}
+
+// failFastError is the cause of the run's context once a module failed with
+// --fail-fast.
+type failFastError struct {
+	module ModuleInfo
+}
+
+func (e *failFastError) Error() string {
+	return fmt.Sprintf("%s failed (--fail-fast)", e.module.Name)
+}
+
+// moduleTimeouts stops the commands of each module of a multi-module run when
+// motf is interrupted, the module runs longer than timeout, or another module
+// failed with --fail-fast.
+type moduleTimeouts struct {
+	timeout  time.Duration // 0 means no limit
+	failFast bool
+	ctx      context.Context // Done once motf is interrupted, or a module failed with failFast
+	cancel   context.CancelCauseFunc
+	basePath string // Modules' paths are relative to it
+}
+
+// newModuleTimeouts creates moduleTimeouts for modules that may each run for
+// timeout, or without limit when it is 0. With failFast, the first module
+// that fails stops the others.
+func newModuleTimeouts(timeout time.Duration, failFast bool) (*moduleTimeouts, error) {
+	t := &moduleTimeouts{timeout: timeout, failFast: failFast, ctx: runContext, cancel: func(error) {}}
+	if failFast {
+		t.ctx, t.cancel = context.WithCancelCause(runContext)
+	}
+	if timeout > 0 || failFast {
+		basePath, err := getBasePath()
+		if err != nil {
+			return nil, err
+		}
+		t.basePath = basePath
+	}
+	return t, nil
+}
+
+// wrap runs fn with the module's context set, so runners created for the
+// module stop with it. Modules that haven't started when motf is interrupted
+// fail without running; with fail-fast, modules that haven't started or are
+// stopped once a module failed are reported as skipped.
+func (t *moduleTimeouts) wrap(fn ModuleRunner) ModuleRunner {
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		if err := context.Cause(t.ctx); err != nil {
+			return t.stopped(err)
+		}
+		if t.timeout <= 0 && !t.failFast {
+			return fn(mod, stdout, stderr)
+		}
+
+		ctx, cancel := t.ctx, context.CancelFunc(func() {})
+		if t.timeout > 0 {
+			ctx, cancel = context.WithTimeoutCause(t.ctx, t.timeout, &moduleTimeoutError{timeout: t.timeout})
+		}
+		defer cancel()
+		dir := filepath.Join(t.basePath, mod.Path)
+		moduleContexts.Lock()
+		moduleContexts.byDir[dir] = ctx
+		moduleContexts.Unlock()
+		defer func() {
+			moduleContexts.Lock()
+			delete(moduleContexts.byDir, dir)
+			moduleContexts.Unlock()
+		}()
+
+		err := fn(mod, stdout, stderr)
+		var timedOut *moduleTimeoutError
+		if err != nil && errors.As(context.Cause(ctx), &timedOut) && !errors.As(err, &timedOut) {
+			err = fmt.Errorf("%w: %w", timedOut, err)
+		}
+		if err != nil && context.Cause(t.ctx) != nil {
+			// Stopped because another module failed, or motf was interrupted
+			return t.stopped(err)
+		}
+		if t.failFast && err != nil && !isNonFatal(err) {
+			_, _ = fmt.Fprintln(stderr, "[fail-fast] Stopping the remaining modules")
+			t.cancel(&failFastError{module: mod})
+		}
+		return err
+	}
+}
+
+// stopped returns the error of a module that didn't run or was stopped
+// because of the cause of t.ctx: skipped when another module failed with
+// --fail-fast, otherwise err.
+func (t *moduleTimeouts) stopped(err error) error {
+	var failed *failFastError
+	if errors.As(context.Cause(t.ctx), &failed) {
+		return &skippedError{reason: failed.Error()}
+	}
+	return err
+}
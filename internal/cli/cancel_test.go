@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestModuleTimeouts_StopsModuleAfterTimeout(t *testing.T) {
+	basePath := t.TempDir()
+	timeouts := &moduleTimeouts{timeout: 100 * time.Millisecond, ctx: runContext, basePath: basePath}
+	mod := ModuleInfo{Name: "aks", Path: filepath.Join("projects", "aks")}
+
+	err := timeouts.wrap(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		// Runners created for the module or its examples stop with it
+		ctx := moduleContext(filepath.Join(basePath, mod.Path, "examples", "basic"))
+		<-ctx.Done()
+		return errors.New("terraform plan failed")
+	})(mod, io.Discard, io.Discard)
+
+	var timedOut *moduleTimeoutError
+	if !errors.As(err, &timedOut) || err.Error() != "module timed out after 100ms: terraform plan failed" {
+		t.Fatalf("expected a module timeout error, got %v", err)
+	}
+	if ctx := moduleContext(filepath.Join(basePath, mod.Path)); ctx != runContext {
+		t.Error("expected the module's context to be removed once it finished")
+	}
+}
+
+func TestModuleTimeouts_InterruptedModulesDontStart(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(errInterrupted)
+	orig := runContext
+	runContext = ctx
+	t.Cleanup(func() { runContext = orig })
+
+	timeouts := &moduleTimeouts{ctx: runContext}
+	err := timeouts.wrap(func(ModuleInfo, io.Writer, io.Writer) error {
+		t.Error("expected the module not to run after an interrupt")
+		return nil
+	})(ModuleInfo{Name: "aks", Path: "projects/aks"}, io.Discard, io.Discard)
+	if !errors.Is(err, errInterrupted) {
+		t.Errorf("expected interrupted error, got %v", err)
+	}
+}
+
+func TestModuleTimeouts_FailFast(t *testing.T) {
+	withWorkingDir(t, t.TempDir())
+	withConfig(t, &config.Config{})
+	timeouts, err := newModuleTimeouts(0, true)
+	if err != nil {
+		t.Fatalf("newModuleTimeouts() error = %v", err)
+	}
+	network := ModuleInfo{Name: "network", Path: "components/network"}
+	dns := ModuleInfo{Name: "dns", Path: "components/dns"}
+	aks := ModuleInfo{Name: "aks", Path: "projects/aks"}
+
+	// dns runs until network fails, and is stopped through its context
+	running := make(chan struct{})
+	dnsErr := make(chan error, 1)
+	go func() {
+		dnsErr <- timeouts.wrap(func(mod ModuleInfo, _, _ io.Writer) error {
+			ctx := moduleContext(filepath.Join(timeouts.basePath, mod.Path))
+			close(running)
+			<-ctx.Done()
+			return fmt.Errorf("terraform plan stopped: %w", context.Cause(ctx))
+		})(dns, io.Discard, io.Discard)
+	}()
+	<-running
+
+	var stderr bytes.Buffer
+	err = timeouts.wrap(func(ModuleInfo, io.Writer, io.Writer) error {
+		return errors.New("exit status 1")
+	})(network, io.Discard, &stderr)
+	if err == nil || isNonFatal(err) {
+		t.Fatalf("expected the failing module's error, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "[fail-fast] Stopping the remaining modules") {
+		t.Errorf("stderr = %q, want a fail-fast note", stderr.String())
+	}
+
+	var skipped *skippedError
+	if err := <-dnsErr; !errors.As(err, &skipped) || err.Error() != "skipped: network failed (--fail-fast)" {
+		t.Errorf("expected the running module to be stopped and skipped, got %v", err)
+	}
+	err = timeouts.wrap(func(ModuleInfo, io.Writer, io.Writer) error {
+		t.Error("expected queued modules not to run after a failure")
+		return nil
+	})(aks, io.Discard, io.Discard)
+	if !errors.As(err, &skipped) {
+		t.Errorf("expected the queued module to be skipped, got %v", err)
+	}
+}
+
+func TestExecCmd_FailFast(t *testing.T) {
+	resetFlags(t)
+	t.Cleanup(func() { execShellFlag, execTemplateFlag = "", false })
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "alpha"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "beta"))
+
+	// Modules run in order, so beta is skipped once alpha failed
+	rootCmd.SetArgs([]string{"exec", "--fail-fast", "--", "touch ran; exit 1"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "alpha") || strings.Contains(err.Error(), "beta") {
+		t.Fatalf("expected only alpha to fail, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(tmpDir, DirComponents, "beta", "ran")); !os.IsNotExist(statErr) {
+		t.Error("expected beta not to run after alpha failed")
+	}
+}
+
+func TestModuleContext_DefaultsToRunContext(t *testing.T) {
+	if ctx := moduleContext(t.TempDir()); ctx != runContext {
+		t.Error("expected runContext outside of a run with a timeout")
+	}
+}
+
+func TestExecCmd_Timeout(t *testing.T) {
+	resetFlags(t)
+	t.Cleanup(func() { execShellFlag, execTemplateFlag = "", false })
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "alpha"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "beta"))
+
+	rootCmd.SetArgs([]string{"exec", "-p", "--timeout", "200ms", "--", "sleep 10"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	start := time.Now()
+	err := rootCmd.Execute()
+	if err == nil || strings.Count(err.Error(), "module timed out after 200ms") != 2 {
+		t.Fatalf("expected both modules to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the modules to be stopped, took %s", elapsed)
+	}
+}
+
+func TestRootCmd_InvalidTimeout(t *testing.T) {
+	resetFlags(t)
+	rootCmd.SetArgs([]string{"val", "--all", "--timeout", "-5m"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --timeout '-5m0s': must be positive") {
+		t.Fatalf("expected invalid --timeout error, got %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/spacelift"
+	"github.com/TechnicallyJoe/terraform-motf/internal/vcs"
+	"github.com/spf13/cobra"
+)
+
+// envGithubOutput is the file GitHub Actions reads step outputs from
+const envGithubOutput = "GITHUB_OUTPUT"
+
+var (
+	changedJSONFlag         bool   // Output the changed modules as JSON
+	changedGithubMatrixFlag bool   // Output the changed modules as a GitHub Actions job matrix
+	changedShowFilesFlag    bool   // List the changed files of each module
+	changedPatchFlag        bool   // Include the diff of each module
+	changedOutputFlag       string // Output the reviewers of the changed modules instead of the modules
+)
+
+// changedCmd represents the changed command
+var changedCmd = &cobra.Command{
+	Use:   "changed",
+	Short: "List modules changed compared to a git ref",
+	Long: `List the modules with changes compared to --ref, including uncommitted changes,
+as --changed does for other commands.
+
+Use --github-matrix to output a GitHub Actions job matrix:
+  {"include":[{"module":"storage","path":"components/storage","type":"component"}]}
+Paths are relative to the repository root. When GITHUB_OUTPUT is set, the
+matrix is also written to it as the step outputs 'matrix' and 'count'.
+
+Use --show-files to list the changed files of each module as added, modified,
+or deleted, and --patch to also show each module's unified diff.
+
+Use --output reviewers (or gh) to output the CODEOWNERS owners of the changed
+modules, as 'motf list --changed' does.`,
+	Example: `  motf changed                           # List changed modules
+  motf changed --ref origin/release      # Compare with another ref
+  motf changed --type project --json     # Output changed projects as JSON
+  motf changed --github-matrix           # Output a GitHub Actions job matrix
+  motf changed --show-files              # List the changed files of each module
+  motf changed --patch --select storage  # Show the diff of the storage module
+  motf changed -o reviewers              # Output owners of changed modules from CODEOWNERS`,
+	Args: cobra.NoArgs,
+	RunE: runChanged,
+}
+
+func init() {
+	changedCmd.Flags().BoolVar(&changedJSONFlag, "json", false, "Output in JSON format")
+	changedCmd.Flags().BoolVar(&changedGithubMatrixFlag, "github-matrix", false, "Output a GitHub Actions job matrix, also written to $GITHUB_OUTPUT when set")
+	changedCmd.Flags().BoolVar(&changedShowFilesFlag, "show-files", false, "List the changed files of each module with their status")
+	changedCmd.Flags().BoolVar(&changedPatchFlag, "patch", false, "Show the unified diff of each module (implies --show-files)")
+	changedCmd.Flags().StringVarP(&changedOutputFlag, "output", "o", "", "Output the CODEOWNERS owners of the modules instead: reviewers or gh")
+	changedCmd.Flags().StringVar(&selectFlag, "select", "", "List only changed modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	changedCmd.Flags().StringVar(&typeFlag, "type", "", "List only changed modules of a type (component, base, project)")
+	changedCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref to compare against (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(changedCmd)
+	rootCmd.AddCommand(changedCmd)
+}
+
+// matrixEntry is a module in a GitHub Actions job matrix
+type matrixEntry struct {
+	Module string `json:"module"`
+	Path   string `json:"path"` // Slash-separated, relative to the repository root
+	Type   string `json:"type"`
+}
+
+// githubMatrix is a GitHub Actions job matrix with one job per module
+type githubMatrix struct {
+	Include []matrixEntry `json:"include"`
+}
+
+// changedModule is a changed module with its changed files and diff
+type changedModule struct {
+	ModuleInfo
+	Files []git.FileChange `json:"files"`
+	Patch string           `json:"patch,omitempty"`
+}
+
+func runChanged(cmd *cobra.Command, args []string) error {
+	if changedJSONFlag && changedGithubMatrixFlag {
+		return fmt.Errorf("--json cannot be used with --github-matrix")
+	}
+	if changedGithubMatrixFlag && (changedShowFilesFlag || changedPatchFlag) {
+		return fmt.Errorf("--github-matrix cannot be used with --show-files or --patch")
+	}
+	if err := validateReviewersOutput(changedOutputFlag); err != nil {
+		return err
+	}
+	if changedOutputFlag != "" && (changedJSONFlag || changedGithubMatrixFlag || changedShowFilesFlag || changedPatchFlag) {
+		return fmt.Errorf("--output cannot be used with --json, --github-matrix, --show-files, or --patch")
+	}
+	changedFlag = true
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := selectModules(basePath)
+	if err != nil {
+		return err
+	}
+
+	if changedGithubMatrixFlag {
+		return printGithubMatrix(basePath, modules)
+	}
+	if changedOutputFlag != "" {
+		return printReviewers(basePath, modules, changedOutputFlag)
+	}
+	if changedShowFilesFlag || changedPatchFlag {
+		changes, err := moduleChanges(basePath, modules)
+		if err != nil {
+			return err
+		}
+		if changedJSONFlag {
+			output, err := json.MarshalIndent(changes, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+		if len(changes) == 0 {
+			fmt.Println(noModulesMessage(selectFlag))
+			return nil
+		}
+		printModuleChanges(changes)
+		return nil
+	}
+	if changedJSONFlag {
+		if modules == nil {
+			modules = []ModuleInfo{}
+		}
+		return printModulesJSON(modules)
+	}
+	if len(modules) == 0 {
+		fmt.Println(noModulesMessage(selectFlag))
+		return nil
+	}
+
+	// Populate version info for display
+	for i := range modules {
+		modules[i].Version = spacelift.ReadModuleVersion(filepath.Join(basePath, modules[i].Path))
+	}
+	printModules(modules)
+	return nil
+}
+
+// printGithubMatrix outputs the modules as a single-line GitHub Actions job
+// matrix and, when GITHUB_OUTPUT is set, appends it to that file as the
+// outputs matrix and count.
+func printGithubMatrix(basePath string, modules []ModuleInfo) error {
+	repo, err := changesRepository()
+	if err != nil {
+		return err
+	}
+	repoRoot := repo.Root()
+
+	matrix := githubMatrix{Include: []matrixEntry{}}
+	for _, mod := range modules {
+		relPath, err := repoRelativePath(repoRoot, filepath.Join(basePath, mod.Path))
+		if err != nil {
+			return fmt.Errorf("module %s: %w", mod.Path, err)
+		}
+		matrix.Include = append(matrix.Include, matrixEntry{Module: mod.Name, Path: relPath, Type: mod.Type})
+	}
+	output, err := json.Marshal(matrix)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(output))
+
+	if outputFile := os.Getenv(envGithubOutput); outputFile != "" {
+		f, err := os.OpenFile(filepath.Clean(outputFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", envGithubOutput, err)
+		}
+		_, err = fmt.Fprintf(f, "matrix=%s\ncount=%d\n", output, len(matrix.Include))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", envGithubOutput, err)
+		}
+	}
+	return nil
+}
+
+// moduleChanges returns the changed files of each module with their status,
+// and with --patch the module's diff. Each file belongs to the module with
+// the longest path containing it.
+func moduleChanges(basePath string, modules []ModuleInfo) ([]changedModule, error) {
+	changes := make([]changedModule, len(modules))
+	if len(modules) == 0 {
+		return changes, nil
+	}
+
+	repo, err := changesRepository()
+	if err != nil {
+		return nil, err
+	}
+	if changedPatchFlag && !repo.Has(vcs.CapPatch) {
+		return nil, fmt.Errorf("--patch requires the git CLI: %s", repo.Reason())
+	}
+	repoRoot := repo.Root()
+	files, err := changedFilesIn(repo, changeRangeFlags())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changed files: %w", err)
+	}
+	if files, _, err = sparseChangedFiles(repoRoot, files); err != nil {
+		return nil, err
+	}
+	base, target, err := changeBase(repoRoot, changeRangeFlags())
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := git.FileStatuses(repoRoot, base, target, files)
+	if err != nil {
+		return nil, err
+	}
+
+	relPaths := make([]string, len(modules))
+	for i, mod := range modules {
+		changes[i] = changedModule{ModuleInfo: mod, Files: []git.FileChange{}}
+		if relPaths[i], err = repoRelativePath(repoRoot, filepath.Join(basePath, mod.Path)); err != nil {
+			return nil, fmt.Errorf("module %s: %w", mod.Path, err)
+		}
+	}
+	for _, file := range statuses {
+		owner := -1
+		for i, relPath := range relPaths {
+			if strings.HasPrefix(file.Path, relPath+"/") && (owner < 0 || len(relPath) > len(relPaths[owner])) {
+				owner = i
+			}
+		}
+		if owner >= 0 {
+			changes[owner].Files = append(changes[owner].Files, file)
+		}
+	}
+
+	if changedPatchFlag {
+		for i := range changes {
+			if changes[i].Patch, err = git.Patch(repoRoot, base, target, relPaths[i]); err != nil {
+				return nil, fmt.Errorf("module %s: %w", changes[i].Path, err)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// printModuleChanges outputs each module with its changed files, relative to
+// the module, followed by its diff with --patch
+func printModuleChanges(changes []changedModule) {
+	for i, change := range changes {
+		if i > 0 {
+			fmt.Println()
+		}
+		modulePrefix := filepath.ToSlash(change.Path) + "/"
+		if plainFlag {
+			fmt.Printf("Module: %s\n", change.Path)
+			fmt.Printf("Type: %s\n", change.Type)
+			for _, file := range change.Files {
+				fmt.Printf("File: %s (%s)\n", strings.TrimPrefix(file.Path, modulePrefix), file.Status)
+			}
+		} else {
+			fmt.Printf("%s (%s)\n", change.Path, change.Type)
+			for _, file := range change.Files {
+				fmt.Printf("  %-8s  %s\n", file.Status, strings.TrimPrefix(file.Path, modulePrefix))
+			}
+		}
+		if change.Patch != "" {
+			fmt.Println()
+			fmt.Print(change.Patch)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package cli
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
 
 func TestFmtCmd_HasChangedFlags(t *testing.T) {
 	if fmtCmd.Flags().Lookup("changed") == nil {
@@ -46,3 +50,26 @@ func TestTestCmd_HasChangedFlags(t *testing.T) {
 		t.Fatal("testCmd should have --ref flag")
 	}
 }
+
+func TestRunCommands_HaveSelectionFlags(t *testing.T) {
+	for _, cmd := range []*cobra.Command{initCmd, fmtCmd, valCmd, planCmd, testCmd, secCmd, taskCmd} {
+		for _, name := range []string{"all", "select", "type"} {
+			if cmd.Flags().Lookup(name) == nil {
+				t.Errorf("%s should have --%s flag", cmd.Name(), name)
+			}
+		}
+	}
+	if listCmd.Flags().Lookup("type") == nil {
+		t.Error("list should have --type flag")
+	}
+}
+
+func TestChangedCommands_HaveChangeRangeFlags(t *testing.T) {
+	for _, cmd := range []*cobra.Command{initCmd, fmtCmd, valCmd, planCmd, testCmd, secCmd, taskCmd, listCmd, execCmd} {
+		for _, name := range []string{"since", "from", "to", "merge-base"} {
+			if cmd.Flags().Lookup(name) == nil {
+				t.Errorf("%s should have --%s flag", cmd.Name(), name)
+			}
+		}
+	}
+}
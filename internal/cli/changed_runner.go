@@ -3,95 +3,277 @@ package cli
 import (
 	"fmt"
 	"io"
+	"maps"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
 	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/TechnicallyJoe/terraform-motf/internal/vcs"
+	"github.com/spf13/cobra"
 )
 
-// runOnChangedModules detects changed modules and runs fn on each module.
-// When parallelFlag is set, modules are processed concurrently.
+// selectingModules reports whether modules are selected with --all,
+// --changed, --select, or --type instead of a module name argument.
+func selectingModules() bool {
+	return allFlag || changedFlag || selectFlag != "" || typeFlag != ""
+}
+
+// runOnSelectedModules runs fn on each module selected by --all, --changed,
+// --select, and --type. When parallelFlag is set, modules are processed concurrently.
 // parallelFlag is a package-level CLI flag set by command-line arguments.
-// It is a no-op (success) when no changed modules are found.
+// It is a no-op (success) when no modules are selected.
 //
 // The function signature for fn receives stdout/stderr writers to support
 // prefixed output in parallel mode.
-func runOnChangedModules(fn func(mod ModuleInfo, stdout, stderr io.Writer) error) error {
+func runOnSelectedModules(fn func(mod ModuleInfo, stdout, stderr io.Writer) error) error {
+	var flag string
+	switch {
+	case allFlag:
+		flag = "--all"
+	case changedFlag:
+		flag = "--changed"
+	case selectFlag != "":
+		flag = "--select"
+	default:
+		flag = "--type"
+	}
+	if allFlag && changedFlag {
+		return fmt.Errorf("--all cannot be used with --changed")
+	}
 	if pathFlag != "" {
-		return fmt.Errorf("--changed cannot be used with --path")
+		return fmt.Errorf("%s cannot be used with --path", flag)
 	}
 	if exampleFlag != "" {
-		return fmt.Errorf("--changed cannot be used with --example")
+		return fmt.Errorf("%s cannot be used with --example", flag)
 	}
 
-	modules, err := detectChangedModules(refFlag)
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	modules, err := selectModules(basePath)
 	if err != nil {
 		return err
 	}
 	if len(modules) == 0 {
-		fmt.Println("No changed modules found")
+		fmt.Println(noModulesMessage(selectFlag))
 		return nil
 	}
 
+	if err := resolveModuleConfigs(basePath, modules); err != nil {
+		return err
+	}
+	if err := resolveRunOrder(basePath, modules); err != nil {
+		return err
+	}
+	if summary := binarySummary(modules); summary != "" {
+		logging.Infof("binaries: %s", summary)
+	}
+
 	var parallelismCfg *config.ParallelismConfig
 	if cfg != nil {
 		parallelismCfg = cfg.Parallelism
 	}
 
-	return RunOnModulesParallel(modules, parallelismCfg, fn)
+	budget := newCostBudget(testMaxCostFlag, modules)
+	err = RunOnModulesParallel(modules, parallelismCfg, budget.wrap(fn))
+	budget.report()
+	return err
 }
 
-// runOnChangedModulesWithPath is a convenience wrapper for commands that need
+// selectModules returns the changed modules with --changed, and their
+// dependents with test --dependents, otherwise all modules, keeping those
+// whose name or path matches --select and whose type is --type.
+func selectModules(basePath string) ([]ModuleInfo, error) {
+	if err := validateTypeFlag(); err != nil {
+		return nil, err
+	}
+	if err := validateChangeRange(); err != nil {
+		return nil, err
+	}
+
+	var modules []ModuleInfo
+	var err error
+	if changedFlag {
+		modules, err = detectChangedModules(changeRangeFlags())
+		if err == nil && testDependentsFlag != 0 {
+			modules, err = withDependents(basePath, modules, testDependentsFlag)
+		}
+	} else {
+		modules, err = collectModules(basePath, "")
+		if err == nil {
+			err = reportUnmanagedModules(basePath)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if selectFlag != "" {
+		modules = slices.DeleteFunc(modules, func(mod ModuleInfo) bool {
+			return !finder.MatchesWildcard(mod.Name, selectFlag) && !finder.MatchesWildcard(filepath.ToSlash(mod.Path), selectFlag)
+		})
+	}
+	modules = filterModulesByType(modules)
+	sortModules(modules)
+	logging.Debugf("selected %d module(s)", len(modules))
+	return modules, nil
+}
+
+// validateTypeFlag checks that --type, if set, is a known module type.
+func validateTypeFlag() error {
+	if types := moduleTypes(); typeFlag != "" && !slices.Contains(types, typeFlag) {
+		return fmt.Errorf("invalid --type '%s': must be %s", typeFlag, strings.Join(types, ", "))
+	}
+	return nil
+}
+
+// filterModulesByType keeps the modules of type --type, or all modules when it is not set.
+func filterModulesByType(modules []ModuleInfo) []ModuleInfo {
+	if typeFlag == "" {
+		return modules
+	}
+	return slices.DeleteFunc(modules, func(mod ModuleInfo) bool {
+		return mod.Type != typeFlag
+	})
+}
+
+// noModulesMessage describes an empty selection, e.g. "No changed modules
+// found matching '*storage*' of type component".
+func noModulesMessage(pattern string) string {
+	msg := "No modules found"
+	if changedFlag {
+		msg = "No changed modules found"
+	}
+	if pattern != "" {
+		msg += fmt.Sprintf(" matching '%s'", pattern)
+	}
+	if typeFlag != "" {
+		msg += " of type " + typeFlag
+	}
+	return msg
+}
+
+// runOnSelectedModulesWithPath is a convenience wrapper for commands that need
 // the module's absolute path. It wraps fn to provide the path from ModuleInfo.
-func runOnChangedModulesWithPath(fn func(moduleAbsPath string, stdout, stderr io.Writer) error) error {
+func runOnSelectedModulesWithPath(fn func(moduleAbsPath string, stdout, stderr io.Writer) error) error {
 	basePath, err := getBasePath()
 	if err != nil {
 		return err
 	}
 
-	return runOnChangedModules(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+	return runOnSelectedModules(func(mod ModuleInfo, stdout, stderr io.Writer) error {
 		moduleAbsPath := filepath.Join(basePath, mod.Path)
 		return fn(moduleAbsPath, stdout, stderr)
 	})
 }
 
-// detectChangedModules returns modules that have changed compared to baseRef.
-// If baseRef is empty, it auto-detects the default branch by checking origin/HEAD,
-// then falling back to origin/main or origin/master.
-func detectChangedModules(baseRef string) ([]ModuleInfo, error) {
-	// Get the git repository root
-	repoRoot, err := git.GetRepoRoot()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get git root: %w", err)
-	}
-
-	// Determine base ref
-	base := baseRef
-	if base == "" {
-		detectedBase, err := git.GetDefaultBranch()
+// resolveModuleConfigs sets the effective binary, timeout, and test cost of
+// each module from its .motf.module.yml, so a single run can mix terraform and tofu
+// modules and start heavy modules first. All module configs are loaded up
+// front so an invalid one fails the run before any module is processed.
+func resolveModuleConfigs(basePath string, modules []ModuleInfo) error {
+	for i := range modules {
+		modCfg, err := moduleConfig(filepath.Join(basePath, modules[i].Path))
 		if err != nil {
-			return nil, fmt.Errorf("could not auto-detect base branch (use --ref to specify): %w", err)
+			return fmt.Errorf("module %s: %w", modules[i].Path, err)
 		}
-		base = detectedBase
+		modules[i].Binary = terraform.NewRunner(modCfg).Binary()
+		modules[i].Timeout = modCfg.Timeout(commandName)
+		modules[i].Cost = modCfg.Test.GetCost()
 	}
+	return nil
+}
 
-	// Get changed files
-	changedFiles, err := git.GetChangedFiles(repoRoot, base)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get changed files: %w", err)
+// binarySummary returns e.g. "terraform (2), tofu (1)" when modules use more
+// than one binary, or "" when the fleet is uniform.
+func binarySummary(modules []ModuleInfo) string {
+	counts := make(map[string]int)
+	for _, mod := range modules {
+		if mod.Binary != "" {
+			counts[mod.Binary]++
+		}
 	}
-	if len(changedFiles) == 0 {
-		return nil, nil
+	if len(counts) < 2 {
+		return ""
 	}
 
-	// Get base path for module discovery
+	parts := make([]string, 0, len(counts))
+	for _, binary := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%s (%d)", binary, counts[binary]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// changeRange selects the changes that changed modules are detected in: the
+// commits since a date, the commits between From and To, or otherwise the
+// changes compared to Ref (including uncommitted changes). An empty Ref is
+// the default branch, auto-detected from origin/HEAD, then origin/main or
+// origin/master.
+type changeRange struct {
+	Ref       string
+	Since     string
+	From, To  string
+	MergeBase bool // Compare with the merge base of Ref and HEAD instead of Ref itself
+}
+
+// changeRangeFlags returns the change range selected by --ref, --since,
+// --from/--to, and --merge-base.
+func changeRangeFlags() changeRange {
+	return changeRange{Ref: refFlag, Since: sinceFlag, From: fromFlag, To: toFlag, MergeBase: mergeBaseFlag}
+}
+
+// detectChangedModules returns the modules that have changed in r.
+func detectChangedModules(r changeRange) ([]ModuleInfo, error) {
 	basePath, err := getBasePath()
 	if err != nil {
 		return nil, err
 	}
+	repoRoot, changedModulePaths, err := changedModuleDirs(r, basePath)
+	if err != nil || len(changedModulePaths) == 0 {
+		return nil, err
+	}
+
+	// Convert paths to module info with validation
+	modules := resolveChangedModules(basePath, repoRoot, changedModulePaths)
+	modules, unmanaged := filterManaged(modules)
+	reportUnmanaged(unmanaged, "changed module(s)")
+
+	return modules, nil
+}
+
+// changedModuleDirs returns the repository root and the directories in the
+// module directories under basePath with changes in r, relative to the
+// repository root. A directory may be inside a module, e.g. its tests.
+func changedModuleDirs(r changeRange, basePath string) (string, []string, error) {
+	// Get the working copy to compare
+	repo, err := changesRepository()
+	if err != nil {
+		return "", nil, err
+	}
+	repoRoot := repo.Root()
+
+	// Get changed files
+	changedFiles, err := changedFilesIn(repo, r)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get changed files: %w", err)
+	}
+	changedFiles, skipped, err := sparseChangedFiles(repoRoot, changedFiles)
+	if err != nil {
+		return "", nil, err
+	}
+	reportSparse(skipped, "changed file(s)")
+	logging.Debugf("%d changed file(s)", len(changedFiles))
+	if len(changedFiles) == 0 {
+		return repoRoot, nil, nil
+	}
 
 	// Calculate relative path from repo root to base path
 	relBasePath, err := filepath.Rel(repoRoot, basePath)
@@ -101,7 +283,8 @@ func detectChangedModules(baseRef string) ([]ModuleInfo, error) {
 
 	// Adjust module dirs to be relative to repo root
 	var adjustedModuleDirs []string
-	for _, dir := range ModuleDirs {
+	for _, d := range searchDirs() {
+		dir := d.Dir
 		if relBasePath != "" && relBasePath != "." {
 			adjustedModuleDirs = append(adjustedModuleDirs, filepath.ToSlash(filepath.Join(relBasePath, dir)))
 		} else {
@@ -110,15 +293,111 @@ func detectChangedModules(baseRef string) ([]ModuleInfo, error) {
 	}
 
 	// Map changed files to module paths
-	changedModulePaths := git.MapFilesToModules(changedFiles, adjustedModuleDirs)
-	if len(changedModulePaths) == 0 {
-		return nil, nil
+	return repoRoot, git.MapFilesToModules(changedFiles, adjustedModuleDirs, git.NewIgnore(cfg.Changed.GetIgnore())), nil
+}
+
+// addChangeRangeFlags registers the flags that select the commits --changed
+// compares: --merge-base for --ref, or --since and --from/--to instead of it.
+func addChangeRangeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "With --changed, use commits since a date (e.g., 2024-01-01, 2.weeks) instead of --ref")
+	cmd.Flags().StringVar(&fromFlag, "from", "", "With --changed, use the commits between --from and --to instead of --ref")
+	cmd.Flags().StringVar(&toFlag, "to", "", "End of the --from range (default: HEAD)")
+	cmd.Flags().BoolVar(&mergeBaseFlag, "merge-base", true, "Compare with the merge base of --ref and HEAD, like 'git diff ref...HEAD'")
+}
+
+// validateChangeRange checks that --since and --from/--to are used with
+// --changed and not combined with each other or --ref.
+func validateChangeRange() error {
+	switch {
+	case (sinceFlag != "" || fromFlag != "" || toFlag != "") && !changedFlag:
+		return fmt.Errorf("--since, --from, and --to require --changed")
+	case sinceFlag != "" && (fromFlag != "" || toFlag != ""):
+		return fmt.Errorf("--since cannot be used with --from or --to")
+	case toFlag != "" && fromFlag == "":
+		return fmt.Errorf("--to requires --from")
+	case refFlag != "" && (sinceFlag != "" || fromFlag != ""):
+		return fmt.Errorf("--ref cannot be used with --since or --from")
+	}
+	if sinceFlag != "" {
+		if _, err := git.ParseSince(sinceFlag, now()); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
 	}
+	return nil
+}
 
-	// Convert paths to module info with validation
-	modules := resolveChangedModules(basePath, repoRoot, changedModulePaths)
+// changesRepository returns the working copy that --changed compares, or an
+// error explaining why changes can't be detected, e.g. in a directory without
+// version control.
+func changesRepository() (vcs.Repository, error) {
+	repo := vcs.Detect(".")
+	if !repo.Has(vcs.CapChanges) {
+		return nil, fmt.Errorf("detecting changed modules requires a git repository: %s", repo.Reason())
+	}
+	return repo, nil
+}
 
-	return modules, nil
+// changedFilesIn returns the files changed in r.
+func changedFilesIn(repo vcs.Repository, r changeRange) ([]string, error) {
+	switch {
+	case r.Since != "":
+		since, err := git.ParseSince(r.Since, now())
+		if err != nil {
+			return nil, err
+		}
+		return repo.ChangedFiles(vcs.Range{Since: since})
+	case r.From != "":
+		return repo.ChangedFiles(vcs.Range{From: r.From, To: r.To})
+	}
+
+	base, err := resolveBaseRef(r.Ref)
+	if err != nil {
+		return nil, err
+	}
+	return repo.ChangedFiles(vcs.Range{Base: base, MergeBase: r.MergeBase})
+}
+
+// resolveBaseRef returns baseRef, or the auto-detected default branch when
+// it is empty.
+func resolveBaseRef(baseRef string) (string, error) {
+	if baseRef != "" {
+		return baseRef, nil
+	}
+	base, err := git.GetDefaultBranch()
+	if err != nil {
+		return "", fmt.Errorf("could not auto-detect base branch (use --ref to specify): %w", err)
+	}
+	return base, nil
+}
+
+// changeBase returns the commits that changedFilesIn compares for r: the
+// base commit and the target commit, where an empty target is the working
+// tree and an empty base is the start of history.
+func changeBase(repoRoot string, r changeRange) (base, target string, err error) {
+	switch {
+	case r.Since != "":
+		since, err := git.ParseSince(r.Since, now())
+		if err != nil {
+			return "", "", err
+		}
+		base, err := git.SinceBase(repoRoot, since)
+		return base, "HEAD", err
+	case r.From != "":
+		target := r.To
+		if target == "" {
+			target = "HEAD"
+		}
+		return r.From, target, nil
+	}
+
+	base, err = resolveBaseRef(r.Ref)
+	if err != nil {
+		return "", "", err
+	}
+	if r.MergeBase {
+		base, err = git.MergeBase(repoRoot, base)
+	}
+	return base, "", err
 }
 
 // resolveChangedModules validates that changed paths are actual modules with .tf files
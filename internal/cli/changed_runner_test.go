@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"bytes"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 )
@@ -130,3 +135,430 @@ func TestResolveChangedModules(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveModuleConfigs_MixedFleet(t *testing.T) {
+	resetFlags(t)
+	dryRunFlag = true
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+	vnetPath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	if err := os.WriteFile(filepath.Join(vnetPath, config.ModuleConfigFile), []byte("binary: tofu\n"), 0644); err != nil {
+		t.Fatalf("failed to write module config: %v", err)
+	}
+
+	modules := []ModuleInfo{
+		{Name: "dns", Path: filepath.Join(DirComponents, "dns")},
+		{Name: "vnet", Path: filepath.Join(DirComponents, "vnet")},
+	}
+	if err := resolveModuleConfigs(tmpDir, modules); err != nil {
+		t.Fatalf("resolveModuleConfigs returned error: %v", err)
+	}
+	if modules[0].Binary != "terraform" || modules[1].Binary != "tofu" {
+		t.Fatalf("expected terraform and tofu, got %q and %q", modules[0].Binary, modules[1].Binary)
+	}
+	if got := binarySummary(modules); got != "terraform (1), tofu (1)" {
+		t.Errorf("binarySummary() = %q", got)
+	}
+
+	// Each module runs with its own binary in a single parallel run
+	var out bytes.Buffer
+	results, err := runOnModulesWithResults(modules, true, 2, &out, &out, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		tfRunner, err := runnerFor(filepath.Join(tmpDir, mod.Path))
+		if err != nil {
+			return err
+		}
+		return tfRunner.RunPlanWithOutput(filepath.Join(tmpDir, mod.Path), stdout, stderr)
+	})
+	if err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	for _, want := range []string{"Would run terraform plan", "Would run tofu plan"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+
+	summary := buildChatopsSummary("plan", results, nil)
+	if summary.Results[0].Binary != "terraform" || summary.Results[1].Binary != "tofu" {
+		t.Errorf("expected binaries in run report, got %+v", summary.Results)
+	}
+}
+
+func TestResolveModuleConfigs_InvalidModuleConfig(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "bad"))
+	if err := os.WriteFile(filepath.Join(modulePath, config.ModuleConfigFile), []byte("binary: pulumi\n"), 0644); err != nil {
+		t.Fatalf("failed to write module config: %v", err)
+	}
+
+	modules := []ModuleInfo{{Name: "bad", Path: filepath.Join(DirComponents, "bad")}}
+	if err := resolveModuleConfigs(tmpDir, modules); err == nil {
+		t.Error("expected error for invalid module binary")
+	}
+}
+
+func TestResolveModuleConfigs_Timeout(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Timeouts: map[string]string{"default": "5m"}})
+	origCommand := commandName
+	commandName = "plan"
+	t.Cleanup(func() { commandName = origCommand })
+
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+	aksPath := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "aks"))
+	if err := os.WriteFile(filepath.Join(aksPath, config.ModuleConfigFile), []byte("timeouts:\n  plan: 15m\n"), 0644); err != nil {
+		t.Fatalf("failed to write module config: %v", err)
+	}
+
+	modules := []ModuleInfo{
+		{Name: "dns", Path: filepath.Join(DirComponents, "dns")},
+		{Name: "aks", Path: filepath.Join(DirProjects, "aks")},
+	}
+	if err := resolveModuleConfigs(tmpDir, modules); err != nil {
+		t.Fatalf("resolveModuleConfigs returned error: %v", err)
+	}
+	if modules[0].Timeout != 5*time.Minute || modules[1].Timeout != 15*time.Minute {
+		t.Errorf("timeouts = %s, %s; want 5m, 15m", modules[0].Timeout, modules[1].Timeout)
+	}
+}
+
+func TestBinarySummary_UniformFleet(t *testing.T) {
+	modules := []ModuleInfo{{Binary: "tofu"}, {Binary: "tofu"}}
+	if got := binarySummary(modules); got != "" {
+		t.Errorf("expected empty summary for uniform fleet, got %q", got)
+	}
+}
+
+func TestSelectModules_MatchesNameOrPath(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "key-vault"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "landing-zone"))
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"*storage*", "storage-account"},
+		{"projects/*", "landing-zone"},
+		{"*-*", "key-vault,storage-account,landing-zone"}, // Sorted by path,
+		{"missing*", ""},
+	}
+	for _, tt := range tests {
+		selectFlag = tt.pattern
+		modules, err := selectModules(tmpDir)
+		if err != nil {
+			t.Fatalf("selectModules(%q) error = %v", tt.pattern, err)
+		}
+		var names []string
+		for _, mod := range modules {
+			names = append(names, mod.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("selectModules(%q) = %s, want %s", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestRunOnSelectedModules_RejectsPath(t *testing.T) {
+	resetFlags(t)
+	selectFlag = "*storage*"
+	pathFlag = "./components/storage-account"
+
+	err := runOnSelectedModules(func(ModuleInfo, io.Writer, io.Writer) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "--select cannot be used with --path") {
+		t.Fatalf("expected --select/--path error, got %v", err)
+	}
+}
+
+func TestValCmd_SelectRunsOnMatchingModules(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "storage-base"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "key-vault"))
+	logDir := filepath.Join(tmpDir, "logs")
+
+	rootCmd.SetArgs([]string{"val", "--select", "*storage*", "--dry-run", "--log-dir", logDir})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("val --select failed: %v", err)
+	}
+
+	for _, name := range []string{"storage-account", "storage-base"} {
+		if _, err := os.Stat(filepath.Join(logDir, name+".log")); err != nil {
+			t.Errorf("expected %s to be validated: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(logDir, "key-vault.log")); err == nil {
+		t.Error("key-vault should not be selected")
+	}
+}
+
+func TestSelectModules_Type(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "storage-base"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "storage-prod"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "landing-zone"))
+
+	typeFlag = TypeProject
+	selectFlag = "storage*"
+	modules, err := selectModules(tmpDir)
+	if err != nil {
+		t.Fatalf("selectModules() error = %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "storage-prod" {
+		t.Errorf("modules = %v, want [storage-prod]", modules)
+	}
+}
+
+func TestSelectModules_InvalidType(t *testing.T) {
+	resetFlags(t)
+	typeFlag = "stack"
+
+	_, err := selectModules(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "invalid --type 'stack'") {
+		t.Fatalf("expected invalid type error, got %v", err)
+	}
+}
+
+func TestSelectModules_CustomModuleDirsType(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{ModuleDirs: config.ModuleDirs{{Dir: "modules", Type: "module"}, {Dir: "stacks", Type: "stack"}}})
+	createTerraformModule(t, tmpDir, filepath.Join("modules", "storage"))
+	createTerraformModule(t, tmpDir, filepath.Join("stacks", "prod"))
+	typeFlag = "stack"
+
+	modules, err := selectModules(tmpDir)
+	if err != nil {
+		t.Fatalf("selectModules() error = %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "prod" {
+		t.Errorf("modules = %v, want [prod]", modules)
+	}
+}
+
+func TestSelectModules_TypeRules(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Types: config.TypeRules{{Pattern: "components/azurerm/**", Type: "azure"}}})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "storage"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "aws", "s3"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "prod"))
+
+	typeFlag = "azure"
+	modules, err := selectModules(tmpDir)
+	if err != nil {
+		t.Fatalf("selectModules() error = %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "storage" || modules[0].Type != "azure" {
+		t.Errorf("modules = %v, want [storage (azure)]", modules)
+	}
+
+	// Modules no pattern matches keep the type of their directory
+	typeFlag = TypeComponent
+	modules, err = selectModules(tmpDir)
+	if err != nil {
+		t.Fatalf("selectModules() error = %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "s3" {
+		t.Errorf("modules = %v, want [s3]", modules)
+	}
+}
+
+func TestNoModulesMessage(t *testing.T) {
+	resetFlags(t)
+	if got := noModulesMessage(""); got != "No modules found" {
+		t.Errorf("noModulesMessage() = %q", got)
+	}
+
+	changedFlag = true
+	typeFlag = TypeComponent
+	want := "No changed modules found matching '*storage*' of type component"
+	if got := noModulesMessage("*storage*"); got != want {
+		t.Errorf("noModulesMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestRunOnSelectedModules_AllRejectsChanged(t *testing.T) {
+	resetFlags(t)
+	allFlag = true
+	changedFlag = true
+
+	err := runOnSelectedModules(func(ModuleInfo, io.Writer, io.Writer) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "--all cannot be used with --changed") {
+		t.Fatalf("expected --all/--changed error, got %v", err)
+	}
+}
+
+func TestInitCmd_AllRunsOnEveryModule(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "shared"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "landing-zone"))
+	logDir := filepath.Join(tmpDir, "logs")
+
+	rootCmd.SetArgs([]string{"init", "--all", "-p", "--dry-run", "--log-dir", logDir})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init --all failed: %v", err)
+	}
+
+	for _, name := range []string{"storage-account", "shared", "landing-zone"} {
+		if _, err := os.Stat(filepath.Join(logDir, name+".log")); err != nil {
+			t.Errorf("expected %s to be initialized: %v", name, err)
+		}
+	}
+}
+
+func TestValidateChangeRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func()
+		wantErr string
+	}{
+		{name: "ref only", set: func() { changedFlag, refFlag = true, "origin/main" }},
+		{name: "since", set: func() { changedFlag, sinceFlag = true, "2.weeks" }},
+		{name: "from and to", set: func() { changedFlag, fromFlag, toFlag = true, "v1.0.0", "v1.1.0" }},
+		{name: "without --changed", set: func() { sinceFlag = "2.weeks" }, wantErr: "require --changed"},
+		{name: "since with from", set: func() { changedFlag, sinceFlag, fromFlag = true, "2.weeks", "v1.0.0" }, wantErr: "--since cannot be used with --from"},
+		{name: "to without from", set: func() { changedFlag, toFlag = true, "v1.1.0" }, wantErr: "--to requires --from"},
+		{name: "ref with since", set: func() { changedFlag, refFlag, sinceFlag = true, "main", "2.weeks" }, wantErr: "--ref cannot be used"},
+		{name: "invalid since", set: func() { changedFlag, sinceFlag = true, "yesterday" }, wantErr: "invalid --since"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			tt.set()
+			err := validateChangeRange()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateChangeRange() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDetectChangedModules_FromTo(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage-account"))
+	git("add", "-A")
+	git("commit", "-m", "storage-account")
+	git("tag", "v1.0.0")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	git("add", "-A")
+	git("commit", "-m", "vnet")
+	git("tag", "v1.1.0")
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "landing-zone"))
+	git("add", "-A")
+	git("commit", "-m", "landing-zone")
+
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	withWorkingDir(t, tmpDir)
+	changedFlag = true
+	fromFlag, toFlag = "v1.0.0", "v1.1.0"
+
+	modules, err := detectChangedModules(changeRangeFlags())
+	if err != nil {
+		t.Fatalf("detectChangedModules() error = %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "vnet" {
+		t.Errorf("modules = %v, want [vnet]", modules)
+	}
+
+	// Without --to, the range ends at HEAD
+	toFlag = ""
+	modules, err = detectChangedModules(changeRangeFlags())
+	if err != nil {
+		t.Fatalf("detectChangedModules() error = %v", err)
+	}
+	if len(modules) != 2 {
+		t.Errorf("modules = %v, want [vnet landing-zone]", modules)
+	}
+}
+
+func TestDetectChangedModules_Ignore(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	git("add", "-A")
+	git("commit", "-m", "modules")
+	git("tag", "v1.0.0")
+	for _, file := range []string{
+		filepath.Join(DirComponents, "storage-account", "README.md"),
+		filepath.Join(DirComponents, "vnet", "variables.tf"),
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, file), []byte("# changed\n"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", file, err)
+		}
+	}
+	git("add", "-A")
+	git("commit", "-m", "changes")
+
+	withConfig(t, &config.Config{
+		Root:    tmpDir,
+		Binary:  "terraform",
+		Changed: &config.ChangedConfig{Ignore: []string{"**/*.md"}},
+	})
+	withWorkingDir(t, tmpDir)
+	changedFlag = true
+	fromFlag = "v1.0.0"
+
+	modules, err := detectChangedModules(changeRangeFlags())
+	if err != nil {
+		t.Fatalf("detectChangedModules() error = %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "vnet" {
+		t.Errorf("modules = %v, want [vnet]", modules)
+	}
+}
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func resetChangedFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		changedJSONFlag = false
+		changedGithubMatrixFlag = false
+		changedShowFilesFlag = false
+		changedPatchFlag = false
+		changedOutputFlag = ""
+	})
+}
+
+// setupChangedRepo creates a repository with a storage component committed
+// and tagged v1.0.0, followed by a commit adding a network component and a
+// prod project. It returns the repository root.
+func setupChangedRepo(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	git("add", "-A")
+	git("commit", "-m", "storage")
+	git("tag", "v1.0.0")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "network"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "prod"))
+	git("add", "-A")
+	git("commit", "-m", "network and prod")
+
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	withWorkingDir(t, tmpDir)
+	return tmpDir
+}
+
+func TestChangedCmd_Flags(t *testing.T) {
+	for _, name := range []string{"json", "github-matrix", "show-files", "patch", "output", "select", "type", "ref", "since", "from", "to", "merge-base"} {
+		if changedCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected changed command to have --%s flag", name)
+		}
+	}
+}
+
+func TestChanged_JSONWithGithubMatrix(t *testing.T) {
+	resetFlags(t)
+	resetChangedFlags(t)
+	changedJSONFlag = true
+	changedGithubMatrixFlag = true
+
+	err := runChanged(changedCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--json cannot be used with --github-matrix") {
+		t.Fatalf("expected flag conflict error, got %v", err)
+	}
+}
+
+func TestChanged_GithubMatrixWithShowFiles(t *testing.T) {
+	resetFlags(t)
+	resetChangedFlags(t)
+	changedGithubMatrixFlag = true
+	changedPatchFlag = true
+
+	err := runChanged(changedCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--github-matrix cannot be used with --show-files or --patch") {
+		t.Fatalf("expected flag conflict error, got %v", err)
+	}
+}
+
+func TestChanged_OutputValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func()
+		wantErr string
+	}{
+		{"invalid format", func() { changedOutputFlag = "owners" }, "invalid --output 'owners': must be reviewers or gh"},
+		{"with json", func() { changedOutputFlag, changedJSONFlag = outputReviewers, true }, "--output cannot be used with --json"},
+		{"with show-files", func() { changedOutputFlag, changedShowFilesFlag = outputGh, true }, "--output cannot be used with"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			resetChangedFlags(t)
+			tt.set()
+
+			err := runChanged(changedCmd, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestChanged_OutputReviewers(t *testing.T) {
+	resetFlags(t)
+	resetChangedFlags(t)
+	repoRoot := setupChangedRepo(t)
+	if err := os.MkdirAll(filepath.Join(repoRoot, ".github"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, ".github", "CODEOWNERS"), []byte("/components/ @org/platform\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	log := captureLog(t)
+	changedOutputFlag = outputReviewers
+	fromFlag = "v1.0.0"
+
+	if err := runChanged(changedCmd, nil); err != nil {
+		t.Fatalf("runChanged() error = %v", err)
+	}
+	// network is owned, prod isn't
+	if got := log.String(); !strings.Contains(got, "no CODEOWNERS owner for projects/prod") || strings.Contains(got, "components/network") {
+		t.Errorf("unexpected warnings:\n%s", got)
+	}
+}
+
+func TestChanged_GithubMatrixOutput(t *testing.T) {
+	resetFlags(t)
+	resetChangedFlags(t)
+	setupChangedRepo(t)
+	outputFile := filepath.Join(t.TempDir(), "github_output")
+	if err := os.WriteFile(outputFile, []byte("previous=1\n"), 0644); err != nil {
+		t.Fatalf("failed to write output file: %v", err)
+	}
+	t.Setenv(envGithubOutput, outputFile)
+	changedGithubMatrixFlag = true
+	fromFlag = "v1.0.0"
+
+	if err := runChanged(changedCmd, nil); err != nil {
+		t.Fatalf("runChanged() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	want := `previous=1
+matrix={"include":[{"module":"network","path":"components/network","type":"component"},{"module":"prod","path":"projects/prod","type":"project"}]}
+count=2
+`
+	if string(data) != want {
+		t.Errorf("GITHUB_OUTPUT =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestChanged_GithubMatrixEmpty(t *testing.T) {
+	resetFlags(t)
+	resetChangedFlags(t)
+	setupChangedRepo(t)
+	outputFile := filepath.Join(t.TempDir(), "github_output")
+	t.Setenv(envGithubOutput, outputFile)
+	changedGithubMatrixFlag = true
+	fromFlag = "v1.0.0"
+	typeFlag = TypeBase
+
+	if err := runChanged(changedCmd, nil); err != nil {
+		t.Fatalf("runChanged() error = %v", err)
+	}
+
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	if want := "matrix={\"include\":[]}\ncount=0\n"; string(data) != want {
+		t.Errorf("GITHUB_OUTPUT = %q, want %q", data, want)
+	}
+}
+
+func TestModuleChanges(t *testing.T) {
+	resetFlags(t)
+	resetChangedFlags(t)
+	repoDir := setupChangedRepo(t)
+	// Uncommitted changes aren't part of a --from range
+	storageMain := filepath.Join(repoDir, DirComponents, "storage", "main.tf")
+	if err := os.WriteFile(storageMain, []byte("# storage, changed\n"), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+	changedFlag = true
+	fromFlag = "v1.0.0"
+	changedPatchFlag = true
+
+	modules, err := selectModules(repoDir)
+	if err != nil {
+		t.Fatalf("selectModules() error = %v", err)
+	}
+	changes, err := moduleChanges(repoDir, modules)
+	if err != nil {
+		t.Fatalf("moduleChanges() error = %v", err)
+	}
+
+	// --from without --to compares with HEAD, so storage isn't changed
+	got := map[string][]string{}
+	for _, change := range changes {
+		for _, file := range change.Files {
+			got[change.Name] = append(got[change.Name], file.Path+" "+file.Status)
+		}
+		if change.Name == "network" && !strings.Contains(change.Patch, "+++ b/components/network/main.tf") {
+			t.Errorf("expected network patch to add main.tf, got:\n%s", change.Patch)
+		}
+	}
+	if files := got["network"]; len(files) != 1 || files[0] != "components/network/main.tf added" {
+		t.Errorf("network files = %v, want [components/network/main.tf added]", files)
+	}
+	if files := got["prod"]; len(files) != 1 || files[0] != "projects/prod/main.tf added" {
+		t.Errorf("prod files = %v, want [projects/prod/main.tf added]", files)
+	}
+	if _, ok := got["storage"]; ok {
+		t.Errorf("expected storage not to be changed, got %v", got["storage"])
+	}
+}
+
+func TestModuleChanges_WorkingTree(t *testing.T) {
+	resetFlags(t)
+	resetChangedFlags(t)
+	repoDir := setupChangedRepo(t)
+	storageMain := filepath.Join(repoDir, DirComponents, "storage", "main.tf")
+	if err := os.WriteFile(storageMain, []byte("# storage, changed\n"), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+	changedFlag = true
+	refFlag = "HEAD"
+	changedPatchFlag = true
+
+	modules, err := selectModules(repoDir)
+	if err != nil {
+		t.Fatalf("selectModules() error = %v", err)
+	}
+	changes, err := moduleChanges(repoDir, modules)
+	if err != nil {
+		t.Fatalf("moduleChanges() error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].Name != "storage" {
+		t.Fatalf("moduleChanges() = %v, want only storage", changes)
+	}
+	if files := changes[0].Files; len(files) != 1 || files[0].Status != "modified" {
+		t.Errorf("storage files = %v, want main.tf modified", files)
+	}
+	if !strings.Contains(changes[0].Patch, "+# storage, changed") {
+		t.Errorf("expected storage patch to contain the uncommitted change, got:\n%s", changes[0].Patch)
+	}
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+)
+
+var (
+	chatopsFlag     string // Payload format for multi-module run summaries (slack, teams)
+	chatopsFileFlag string // File to write the payload to, required with --chatops
+)
+
+// validateChatopsFlags checks the --chatops flags before any module runs. The
+// payload is only written to a file, since on stdout it would be mixed with
+// the output of the modules.
+func validateChatopsFlags() error {
+	if chatopsFlag == "" {
+		if chatopsFileFlag != "" {
+			return fmt.Errorf("--chatops-file requires --chatops")
+		}
+		return nil
+	}
+	if chatopsFileFlag == "" {
+		return fmt.Errorf("--chatops requires --chatops-file to write the payload to")
+	}
+	_, err := chatops.Format(chatopsFlag, chatops.Summary{})
+	return err
+}
+
+// outputCapture records the combined output of each module so it can be
+// included (truncated) in run summaries.
+type outputCapture struct {
+	enabled bool
+	mu      sync.Mutex
+	buffers map[string]*bytes.Buffer
+}
+
+// newOutputCapture creates a capture; when disabled, wrap returns fn unchanged.
+func newOutputCapture(enabled bool) *outputCapture {
+	return &outputCapture{enabled: enabled, buffers: make(map[string]*bytes.Buffer)}
+}
+
+// wrap tees the module's stdout and stderr into a per-module buffer.
+func (c *outputCapture) wrap(fn ModuleRunner) ModuleRunner {
+	if !c.enabled {
+		return fn
+	}
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		buf := &syncBuffer{}
+		c.mu.Lock()
+		c.buffers[mod.Path] = &buf.buf
+		c.mu.Unlock()
+		return fn(mod, io.MultiWriter(stdout, buf), io.MultiWriter(stderr, buf))
+	}
+}
+
+// output returns the captured output for a module.
+func (c *outputCapture) output(mod ModuleInfo) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if buf, ok := c.buffers[mod.Path]; ok {
+		return buf.String()
+	}
+	return ""
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes from stdout and stderr.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// buildChatopsSummary converts module results into a chatops summary.
+func buildChatopsSummary(command string, results []moduleResult, capture *outputCapture) chatops.Summary {
+	summary := chatops.Summary{Command: command}
+	for _, r := range results {
+		status, errText := moduleStatus(r.err)
+		res := chatops.ModuleResult{
+			Name:     r.module.Name,
+			Path:     r.module.Path,
+			Binary:   r.module.Binary,
+			Status:   status,
+			Duration: r.duration,
+			Error:    errText,
+		}
+		if capture != nil {
+			if out := capture.output(r.module); out != "" {
+				res.Output = chatops.TruncateLines(out, chatops.DefaultMaxOutputLines)
+			}
+		}
+		summary.Results = append(summary.Results, res)
+	}
+	return summary
+}
+
+// moduleStatus returns the chatops status of a module's error, and the error
+// text to report with it.
+func moduleStatus(err error) (string, string) {
+	var quarantined *quarantinedError
+	var flaky *flakyError
+	var changes *planChangesError
+	var skipped *skippedError
+	switch {
+	case err == nil, errors.As(err, &changes):
+		// Changes are the expected outcome of a plan, not a failure
+		return chatops.StatusSucceeded, ""
+	case errors.As(err, &skipped):
+		return chatops.StatusSkipped, err.Error()
+	case errors.As(err, &quarantined):
+		return chatops.StatusQuarantined, err.Error()
+	case errors.As(err, &flaky):
+		return chatops.StatusFlaky, err.Error()
+	default:
+		return chatops.StatusFailed, err.Error()
+	}
+}
+
+// writeChatopsPayload renders the results in the --chatops format and writes them
+// to --chatops-file.
+func writeChatopsPayload(results []moduleResult, capture *outputCapture) error {
+	payload, err := chatops.Format(chatopsFlag, buildChatopsSummary(commandName, results, capture))
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Clean(chatopsFileFlag), append(payload, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write chatops payload: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&chatopsFlag, "chatops", "", "Render a run summary for multi-module runs as a chat payload (slack, teams)")
+	rootCmd.PersistentFlags().StringVar(&chatopsFileFlag, "chatops-file", "", "File to write the --chatops payload to (required with --chatops)")
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+)
+
+func TestValidateChatopsFlags(t *testing.T) {
+	resetFlags(t)
+
+	tests := []struct {
+		name    string
+		format  string
+		file    string
+		wantErr bool
+	}{
+		{name: "disabled", format: "", file: "", wantErr: false},
+		{name: "slack with file", format: "slack", file: "out.json", wantErr: false},
+		{name: "slack without file", format: "slack", file: "", wantErr: true},
+		{name: "teams with file", format: "teams", file: "out.json", wantErr: false},
+		{name: "unknown format", format: "discord", file: "out.json", wantErr: true},
+		{name: "file without format", format: "", file: "out.json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chatopsFlag = tt.format
+			chatopsFileFlag = tt.file
+			err := validateChatopsFlags()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateChatopsFlags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOutputCapture_RecordsModuleOutput(t *testing.T) {
+	capture := newOutputCapture(true)
+	modules := []ModuleInfo{{Name: "mod-a", Path: "components/mod-a"}}
+
+	var out bytes.Buffer
+	results, err := runOnModulesWithResults(modules, false, 1, &out, &out, capture.wrap(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		_, _ = stdout.Write([]byte("Plan: 1 to add\n"))
+		_, _ = stderr.Write([]byte("warning\n"))
+		return errors.New("boom")
+	}))
+	if err == nil {
+		t.Fatal("expected error from failing module")
+	}
+
+	summary := buildChatopsSummary("plan", results, capture)
+	if len(summary.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(summary.Results))
+	}
+	res := summary.Results[0]
+	if res.Status != chatops.StatusFailed || res.Error != "boom" {
+		t.Errorf("expected failed result with error, got %+v", res)
+	}
+	if !strings.Contains(res.Output, "Plan: 1 to add") || !strings.Contains(res.Output, "warning") {
+		t.Errorf("expected captured stdout and stderr, got %q", res.Output)
+	}
+}
+
+func TestOutputCapture_DisabledReturnsSameRunner(t *testing.T) {
+	capture := newOutputCapture(false)
+	called := false
+	wrapped := capture.wrap(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		called = true
+		return nil
+	})
+	_ = wrapped(ModuleInfo{Name: "x"}, io.Discard, io.Discard)
+	if !called {
+		t.Error("expected wrapped runner to be called")
+	}
+	if capture.output(ModuleInfo{Name: "x"}) != "" {
+		t.Error("disabled capture should not record output")
+	}
+}
+
+func TestWriteChatopsPayload_ToFile(t *testing.T) {
+	resetFlags(t)
+	outFile := filepath.Join(t.TempDir(), "payload.json")
+	chatopsFlag = "slack"
+	chatopsFileFlag = outFile
+
+	results := []moduleResult{{module: ModuleInfo{Name: "mod-a", Path: "components/mod-a"}}}
+	if err := writeChatopsPayload(results, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("failed to read payload: %v", err)
+	}
+	if !strings.Contains(string(data), "mod-a") {
+		t.Errorf("expected payload to mention module, got %s", data)
+	}
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/checks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/sources"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkJSONFlag        bool // Output the findings of the convention checks as JSON
+	checkSourcesJSONFlag bool // Output the broken sources as JSON
+)
+
+// checkCmd checks modules against the repository conventions, and groups the
+// other repository checks
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the repository for problems before they fail in CI",
+	Long: `Check every module in components, bases, and projects against the
+repository's conventions. The rules are selected per module type in the
+checks section of .motf.yml:
+
+  variable-descriptions  Every variable has a description
+  output-descriptions    Every output has a description
+  examples               The module has an examples/ directory
+  tests                  The module has a tests/ directory
+  no-provider-blocks     The module has no provider blocks
+
+By default, components are checked against every rule, and bases and projects
+against the description rules. The command exits with code 3 when any module
+breaks a rule. The subcommands run other checks.`,
+	Example: `  motf check                  # Check modules against the conventions
+  motf check --json           # Output the findings as JSON
+  motf check sources          # Report broken local module sources`,
+	Args: cobra.NoArgs,
+	RunE: runCheck,
+}
+
+var checkSourcesCmd = &cobra.Command{
+	Use:   "sources",
+	Short: "Check that local module sources resolve to modules",
+	Long: `Check that every local module source (source = "../..") in components, bases,
+and projects, including their examples and tests, points to an existing
+directory with .tf or .tf.json files.
+
+This catches relative paths broken by moving directories right away, instead
+of when 'terraform init' fails in CI. The command exits with an error when any
+source is broken.`,
+	Example: `  motf check sources         # Report broken local module sources
+  motf check sources --json  # Output broken sources as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runCheckSources,
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkJSONFlag, "json", false, "Output the findings as JSON")
+	checkSourcesCmd.Flags().BoolVar(&checkSourcesJSONFlag, "json", false, "Output broken sources as JSON")
+	checkCmd.AddCommand(checkSourcesCmd)
+	rootCmd.AddCommand(checkCmd)
+}
+
+// moduleFinding is a convention check finding of a module
+type moduleFinding struct {
+	Module string `json:"module"` // Slash-separated path relative to the root
+	checks.Finding
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := collectModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	sortModules(modules)
+
+	findings := []moduleFinding{}
+	failed := 0
+	for _, mod := range modules {
+		// Rules are selected by module directory, not by the types section
+		modulePath := filepath.Join(basePath, mod.Path)
+		found, err := checks.Run(modulePath, cfg.Checks.GetRules(moduleDirType(modulePath)))
+		if err != nil {
+			return fmt.Errorf("failed to check module %s: %w", mod.Path, err)
+		}
+		if len(found) > 0 {
+			failed++
+		}
+		for _, f := range found {
+			findings = append(findings, moduleFinding{Module: filepath.ToSlash(mod.Path), Finding: f})
+		}
+	}
+
+	if checkJSONFlag {
+		output, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		printCheckFindings(findings)
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return findingsFailed("%d problem(s) in %d of %d module(s)", len(findings), failed, len(modules))
+	}
+	if !checkJSONFlag {
+		fmt.Printf("All %d module(s) pass the checks\n", len(modules))
+	}
+	return nil
+}
+
+// printCheckFindings prints the findings as a table, or one block per finding
+// with --plain.
+func printCheckFindings(findings []moduleFinding) {
+	if len(findings) == 0 {
+		return
+	}
+	if plainFlag {
+		for _, f := range findings {
+			fmt.Printf("Module: %s\nRule: %s\nProblem: %s\n\n", f.Module, f.Rule, f.String())
+		}
+		return
+	}
+	moduleWidth, ruleWidth := len("MODULE"), len("RULE")
+	for _, f := range findings {
+		moduleWidth = max(moduleWidth, len(f.Module))
+		ruleWidth = max(ruleWidth, len(f.Rule))
+	}
+	fmt.Printf("%-*s  %-*s  %s\n", moduleWidth, "MODULE", ruleWidth, "RULE", "PROBLEM")
+	for _, f := range findings {
+		fmt.Printf("%-*s  %-*s  %s\n", moduleWidth, f.Module, ruleWidth, f.Rule, f.String())
+	}
+	fmt.Println()
+}
+
+func runCheckSources(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	refs, err := findModuleSources(basePath)
+	if err != nil {
+		return err
+	}
+	problems := sources.Check(basePath, refs)
+
+	if checkSourcesJSONFlag {
+		if problems == nil {
+			problems = []sources.Problem{}
+		}
+		output, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		for _, p := range problems {
+			fmt.Printf("%s:%d: module.%s source %q: %s\n", p.File, p.Line, p.Module, p.Source, p.Reason)
+		}
+	}
+
+	if len(problems) > 0 {
+		cmd.SilenceUsage = true
+		return findingsFailed("%d of %d local module source(s) are broken", len(problems), len(refs))
+	}
+	if !checkSourcesJSONFlag {
+		fmt.Printf("All %d local module source(s) resolve\n", len(refs))
+	}
+	return nil
+}
+
+// findModuleSources returns the local module sources in the module
+// directories under basePath, leaving out modules outside managed_paths.
+// File paths are relative to basePath.
+func findModuleSources(basePath string) ([]sources.Reference, error) {
+	var refs []sources.Reference
+	for _, d := range searchDirs() {
+		moduleDir := d.Dir
+		searchPath := filepath.Join(basePath, moduleDir)
+
+		// Skip if directory doesn't exist
+		if _, err := os.Stat(searchPath); os.IsNotExist(err) {
+			continue
+		}
+
+		dirRefs, err := sources.Find(searchPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", moduleDir, err)
+		}
+		for _, ref := range dirRefs {
+			ref.File = path.Join(moduleDir, ref.File)
+			if cfg.IsManaged(path.Dir(ref.File)) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs, nil
+}
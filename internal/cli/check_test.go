@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestCheckCmd_ReportsConventionFindings(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	content := "checks:\n  component: [variable-descriptions, tests]\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, config.ConfigFile), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", config.ConfigFile, err)
+	}
+	withWorkingDir(t, tmpDir)
+	vnet := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	if err := os.WriteFile(filepath.Join(vnet, "variables.tf"), []byte("variable \"name\" {}\n"), 0644); err != nil {
+		t.Fatalf("failed to write variables.tf: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(vnet, DirTests), 0755); err != nil {
+		t.Fatalf("failed to create tests: %v", err)
+	}
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "naming"))
+	// Bases only get the description rules by default
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "network"))
+
+	rootCmd.SetArgs([]string{"check"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.Execute()
+	if err == nil || err.Error() != "2 problem(s) in 2 of 3 module(s)" {
+		t.Fatalf("expected 2 problems, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+}
+
+func TestCheckSourcesCmd_HasFlags(t *testing.T) {
+	if checkSourcesCmd.Flags().Lookup("json") == nil {
+		t.Error("check sources should have --json flag")
+	}
+}
+
+func TestFindModuleSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Binary: "terraform", ManagedPaths: []string{"bases"}})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	for _, dir := range []string{filepath.Join(DirBases, "network"), filepath.Join(DirProjects, "legacy")} {
+		path := createTerraformModule(t, tmpDir, dir)
+		content := "module \"vnet\" {\n  source = \"../../components/vnet\"\n}\n"
+		if err := os.WriteFile(filepath.Join(path, "vnet.tf"), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write vnet.tf: %v", err)
+		}
+	}
+
+	refs, err := findModuleSources(tmpDir)
+	if err != nil {
+		t.Fatalf("findModuleSources() error = %v", err)
+	}
+	if len(refs) != 1 || refs[0].File != "bases/network/vnet.tf" {
+		t.Errorf("refs = %+v, want only bases/network/vnet.tf", refs)
+	}
+}
+
+func TestCheckSourcesCmd_ReportsBrokenSources(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	example := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet", "examples", "basic"))
+	content := "module \"vnet\" {\n  source = \"../../../network\"\n}\n"
+	if err := os.WriteFile(filepath.Join(example, "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"check", "sources"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 of 1 local module source(s) are broken") {
+		t.Fatalf("expected a broken source error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+}
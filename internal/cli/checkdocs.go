@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/readme"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkDocsJSONFlag bool // Output the README problems as JSON
+	checkDocsFixFlag  bool // Regenerate the tables of READMEs with problems
+)
+
+var checkDocsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Check that module READMEs document every variable and output",
+	Long: `Check that the Inputs and Outputs tables in the README.md of every module in
+components, bases, and projects, as terraform-docs writes them, list the
+module's variables and outputs. A variable or output that isn't in the README
+is reported, and so is one the README lists that the module no longer
+declares. READMEs without terraform-docs markers or Inputs or Outputs
+sections, and modules without a README.md, are skipped.
+
+Use --fix to regenerate the section between the <!-- BEGIN_TF_DOCS --> and
+<!-- END_TF_DOCS --> markers of the READMEs with problems. READMEs with tables
+outside the markers can't be fixed.
+
+The command exits with an error when any README is reported and not fixed.`,
+	Example: `  motf check docs                  # Report READMEs out of date
+  motf check docs --fix            # Regenerate the tables of READMEs out of date
+  motf check docs --fix --dry-run  # Show which READMEs would be updated`,
+	Args: cobra.NoArgs,
+	RunE: runCheckDocs,
+}
+
+func init() {
+	checkDocsCmd.Flags().BoolVar(&checkDocsJSONFlag, "json", false, "Output the problems as JSON")
+	checkDocsCmd.Flags().BoolVar(&checkDocsFixFlag, "fix", false, "Regenerate the Inputs and Outputs tables of READMEs with problems")
+	checkCmd.AddCommand(checkDocsCmd)
+}
+
+// docsProblem is a problem of the README of a module
+type docsProblem struct {
+	Module string `json:"module"` // Slash-separated path relative to the root
+	readme.Finding
+	Fixed bool `json:"fixed,omitempty"`
+}
+
+func runCheckDocs(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := collectModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	sortModules(modules)
+
+	cmd.SilenceUsage = true
+	problems := []docsProblem{}
+	var updated []string
+	count, failed := 0, 0
+	for _, mod := range modules {
+		modulePath := filepath.Join(basePath, mod.Path)
+		relPath := filepath.ToSlash(mod.Path)
+		doc, err := readme.Read(modulePath)
+		if err != nil {
+			return fmt.Errorf("failed to read the README of module %s: %w", relPath, err)
+		}
+		if doc == nil || !doc.Documents() {
+			continue
+		}
+		count++
+		schema, err := terraform.LoadModuleSchema(modulePath, basePath)
+		if err != nil {
+			return fmt.Errorf("failed to parse module %s: %w", relPath, err)
+		}
+		findings := doc.Check(schema)
+		if len(findings) == 0 {
+			continue
+		}
+
+		fixed := checkDocsFixFlag && !dryRunFlag
+		if fixed {
+			if err := doc.Fix(modulePath, schema); err != nil {
+				return fmt.Errorf("module %s: %w", relPath, err)
+			}
+		}
+		if checkDocsFixFlag {
+			updated = append(updated, path.Join(relPath, readme.File))
+		} else {
+			failed++
+		}
+		for _, f := range findings {
+			problems = append(problems, docsProblem{Module: relPath, Finding: f, Fixed: fixed})
+		}
+	}
+
+	if checkDocsJSONFlag {
+		output, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		for _, p := range problems {
+			fmt.Printf("%s: %s\n", path.Join(p.Module, readme.File), p.String())
+		}
+		for _, file := range updated {
+			if dryRunFlag {
+				fmt.Printf("[dry-run] Would update %s\n", file)
+			} else {
+				fmt.Printf("Updated %s\n", file)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return findingsFailed("%d problem(s) in %d of %d README(s), run 'motf check docs --fix' to update them", len(problems), failed, count)
+	}
+	if !checkDocsJSONFlag && len(problems) == 0 {
+		fmt.Printf("All %d README(s) document their module's variables and outputs\n", count)
+	}
+	return nil
+}
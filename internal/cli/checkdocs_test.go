@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckDocsCmd_HasFlags(t *testing.T) {
+	for _, name := range []string{"json", "fix"} {
+		if checkDocsCmd.Flags().Lookup(name) == nil {
+			t.Errorf("check docs should have --%s flag", name)
+		}
+	}
+}
+
+func TestCheckDocsCmd(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	storage := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "undocumented"))
+	if err := os.WriteFile(filepath.Join(storage, "variables.tf"), []byte("variable \"name\" {\n  type = string\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	readmePath := filepath.Join(storage, "README.md")
+	if err := os.WriteFile(readmePath, []byte("# Storage\n\n<!-- BEGIN_TF_DOCS -->\n<!-- END_TF_DOCS -->\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"check", "docs"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 problem(s) in 1 of 1 README(s)") {
+		t.Fatalf("expected a docs error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+
+	rootCmd.SetArgs([]string{"check", "docs", "--fix"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("check docs --fix: %v", err)
+	}
+	data, _ := os.ReadFile(readmePath)
+	if !strings.Contains(string(data), `[name](#input\_name)`) {
+		t.Errorf("README after --fix:\n%s", data)
+	}
+
+	checkDocsFixFlag = false
+	rootCmd.SetArgs([]string{"check", "docs"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Errorf("check docs after --fix: %v", err)
+	}
+}
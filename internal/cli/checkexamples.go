@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/examples"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+// checkExamplesJSONFlag outputs the example problems as JSON
+var checkExamplesJSONFlag bool
+
+var checkExamplesCmd = &cobra.Command{
+	Use:   "examples",
+	Short: "Check that examples call their module with its current variables",
+	Long: `Check that the examples of every module in components, bases, and projects
+still call the module correctly, so they keep working as the module evolves.
+Each module block in examples/<name> whose local source points at the module
+is checked against the module's variables:
+
+  - Every required variable is set
+  - Every argument is a variable of the module, or a meta-argument like count
+
+A local source that doesn't resolve, like one left behind by a rename, is
+reported as outdated, and so is an example without a module block calling the
+module. Module blocks calling other modules are left alone. The command exits
+with an error when any example is reported.`,
+	Example: `  motf check examples         # Report examples out of sync with their module
+  motf check examples --json  # Output the problems as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runCheckExamples,
+}
+
+func init() {
+	checkExamplesCmd.Flags().BoolVar(&checkExamplesJSONFlag, "json", false, "Output the problems as JSON")
+	checkCmd.AddCommand(checkExamplesCmd)
+}
+
+// exampleProblem is a problem of an example of a module
+type exampleProblem struct {
+	Module string `json:"module"` // Slash-separated path relative to the root
+	examples.Problem
+}
+
+func runCheckExamples(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := collectModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	sortModules(modules)
+
+	problems := []exampleProblem{}
+	count, failed := 0, 0
+	for _, mod := range modules {
+		modulePath := filepath.Join(basePath, mod.Path)
+		names, err := examples.List(modulePath)
+		if err != nil {
+			return fmt.Errorf("failed to list the examples of module %s: %w", mod.Path, err)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		schema, err := terraform.LoadModuleSchema(modulePath, basePath)
+		if err != nil {
+			return fmt.Errorf("failed to parse module %s: %w", mod.Path, err)
+		}
+		for _, name := range names {
+			found, err := examples.Check(modulePath, name, schema.Variables)
+			if err != nil {
+				return fmt.Errorf("module %s: %w", mod.Path, err)
+			}
+			count++
+			if len(found) > 0 {
+				failed++
+			}
+			for _, p := range found {
+				problems = append(problems, exampleProblem{Module: filepath.ToSlash(mod.Path), Problem: p})
+			}
+		}
+	}
+
+	if checkExamplesJSONFlag {
+		output, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		for _, p := range problems {
+			p.File = path.Join(p.Module, p.File)
+			fmt.Println(p.String())
+		}
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return findingsFailed("%d problem(s) in %d of %d example(s)", len(problems), failed, count)
+	}
+	if !checkExamplesJSONFlag {
+		fmt.Printf("All %d example(s) call their module with its current variables\n", count)
+	}
+	return nil
+}
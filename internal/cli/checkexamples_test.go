@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckExamplesCmd_HasFlags(t *testing.T) {
+	if checkExamplesCmd.Flags().Lookup("json") == nil {
+		t.Error("check examples should have --json flag")
+	}
+}
+
+func TestCheckExamplesCmd(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	storage := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	if err := os.WriteFile(filepath.Join(storage, "variables.tf"), []byte("variable \"name\" {\n  type = string\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	basic := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage", DirExamples, "basic"))
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(basic, "main.tf"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("module \"storage\" {\n  source = \"../..\"\n  sku    = \"Standard\"\n}\n")
+	rootCmd.SetArgs([]string{"check", "examples"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "2 problem(s) in 1 of 1 example(s)") {
+		t.Fatalf("expected an examples error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+
+	write("module \"storage\" {\n  source = \"../..\"\n  name   = \"logs\"\n}\n")
+	if err := rootCmd.Execute(); err != nil {
+		t.Errorf("check examples of a valid example: %v", err)
+	}
+}
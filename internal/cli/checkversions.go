@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/TechnicallyJoe/terraform-motf/internal/versions"
+	"github.com/spf13/cobra"
+)
+
+// checkVersionsJSONFlag outputs the version problems as JSON
+var checkVersionsJSONFlag bool
+
+var checkVersionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "Check that modules declare compatible required_version constraints",
+	Long: `Check that every module in components, bases, and projects declares a
+required_version in its terraform block, and that the constraints are
+compatible with each other and with constraints.terraform in .motf.yml:
+
+  constraints:
+    terraform: ">= 1.6"
+
+A module is reported when it has no required_version, when no version
+satisfies both its required_version and constraints.terraform, or when no
+version satisfies both its required_version and that of another module, since
+a project calling both can't be initialized. The command exits with an error
+when any module is reported.`,
+	Example: `  motf check versions         # Report required_version problems
+  motf check versions --json  # Output the problems as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runCheckVersions,
+}
+
+func init() {
+	checkVersionsCmd.Flags().BoolVar(&checkVersionsJSONFlag, "json", false, "Output the problems as JSON")
+	checkCmd.AddCommand(checkVersionsCmd)
+}
+
+// versionProblem is a module whose required_version fails the check
+type versionProblem struct {
+	Module          string `json:"module"`                     // Slash-separated path relative to the root, empty for all modules
+	RequiredVersion string `json:"required_version,omitempty"` // Empty when not declared
+	Reason          string `json:"reason"`
+}
+
+func runCheckVersions(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := collectModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	sortModules(modules)
+	problems, err := checkRequiredVersions(basePath, modules, cfg.Constraints.GetTerraform())
+	if err != nil {
+		return err
+	}
+
+	if checkVersionsJSONFlag {
+		if problems == nil {
+			problems = []versionProblem{}
+		}
+		output, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		for _, p := range problems {
+			if p.Module == "" {
+				fmt.Println(p.Reason)
+				continue
+			}
+			fmt.Printf("%s: %s\n", p.Module, p.Reason)
+		}
+	}
+
+	if len(problems) > 0 {
+		cmd.SilenceUsage = true
+		if n := countModules(problems); n > 0 {
+			return findingsFailed("%d of %d module(s) have required_version problems", n, len(modules))
+		}
+		return findingsFailed("the required_version constraints of %d module(s) are incompatible", len(modules))
+	}
+	if !checkVersionsJSONFlag {
+		fmt.Printf("All %d module(s) declare a compatible required_version\n", len(modules))
+	}
+	return nil
+}
+
+// checkRequiredVersions returns the problems with the required_version of
+// modules, in module order, given the repository-wide constraint.
+func checkRequiredVersions(basePath string, modules []ModuleInfo, constraint string) ([]versionProblem, error) {
+	var problems []versionProblem
+	declared := map[string]string{} // Module path -> valid, compatible required_version
+	var order []string
+	for _, mod := range modules {
+		relPath := filepath.ToSlash(mod.Path)
+		schema, err := terraform.LoadModuleSchema(filepath.Join(basePath, mod.Path), basePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse module %s: %w", mod.Path, err)
+		}
+		required := strings.TrimSpace(schema.TerraformVersion)
+		if required == "" {
+			problems = append(problems, versionProblem{Module: relPath, Reason: "no required_version declared"})
+			continue
+		}
+		if err := versions.Validate(required); err != nil {
+			problems = append(problems, versionProblem{Module: relPath, RequiredVersion: required, Reason: err.Error()})
+			continue
+		}
+		if ok, err := versions.Compatible(required, constraint); err != nil {
+			return nil, err
+		} else if !ok {
+			problems = append(problems, versionProblem{
+				Module:          relPath,
+				RequiredVersion: required,
+				Reason:          fmt.Sprintf("required_version %q is incompatible with constraints.terraform %q", required, constraint),
+			})
+			continue
+		}
+		declared[relPath] = required
+		order = append(order, relPath)
+	}
+
+	// Report each module with the modules it conflicts with
+	conflicts := 0
+	for _, a := range order {
+		var with []string
+		for _, b := range order {
+			if a == b {
+				continue
+			}
+			ok, err := versions.Compatible(declared[a], declared[b], constraint)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				with = append(with, fmt.Sprintf("%q of %s", declared[b], b))
+			}
+		}
+		if len(with) > 0 {
+			conflicts++
+			problems = append(problems, versionProblem{
+				Module:          a,
+				RequiredVersion: declared[a],
+				Reason:          fmt.Sprintf("required_version %q is incompatible with %s", declared[a], strings.Join(with, ", ")),
+			})
+		}
+	}
+
+	// Constraints can be compatible in pairs but not all together
+	if conflicts == 0 && len(order) > 2 {
+		all := []string{constraint}
+		for _, relPath := range order {
+			all = append(all, declared[relPath])
+		}
+		ok, err := versions.Compatible(all...)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			problems = append(problems, versionProblem{Reason: "no version satisfies the required_version of every module together"})
+		}
+	}
+
+	sortVersionProblems(problems, modules)
+	return problems, nil
+}
+
+// sortVersionProblems sorts problems by the order of modules, keeping the
+// problem about all modules last.
+func sortVersionProblems(problems []versionProblem, modules []ModuleInfo) {
+	index := map[string]int{}
+	for i, mod := range modules {
+		index[filepath.ToSlash(mod.Path)] = i
+	}
+	position := func(p versionProblem) int {
+		if p.Module == "" {
+			return len(modules)
+		}
+		return index[p.Module]
+	}
+	slices.SortStableFunc(problems, func(a, b versionProblem) int { return position(a) - position(b) })
+}
+
+// countModules returns the number of modules with problems, leaving out the
+// problem about all modules.
+func countModules(problems []versionProblem) int {
+	seen := map[string]bool{}
+	for _, p := range problems {
+		if p.Module != "" {
+			seen[p.Module] = true
+		}
+	}
+	return len(seen)
+}
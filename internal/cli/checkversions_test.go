@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// createVersionedModule creates a module whose terraform block requires
+// version, or has no terraform block if version is empty.
+func createVersionedModule(t *testing.T, base, rel, version string) {
+	t.Helper()
+	path := createTerraformModule(t, base, rel)
+	if version == "" {
+		return
+	}
+	content := "terraform {\n  required_version = \"" + version + "\"\n}\n"
+	if err := os.WriteFile(filepath.Join(path, "versions.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write versions.tf: %v", err)
+	}
+}
+
+func TestCheckVersionsCmd_HasFlags(t *testing.T) {
+	if checkVersionsCmd.Flags().Lookup("json") == nil {
+		t.Error("check versions should have --json flag")
+	}
+}
+
+func TestCheckRequiredVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+	createVersionedModule(t, tmpDir, filepath.Join(DirComponents, "naming"), ">= 1.6")
+	createVersionedModule(t, tmpDir, filepath.Join(DirComponents, "legacy"), "~> 1.5.0")
+	createVersionedModule(t, tmpDir, filepath.Join(DirComponents, "vnet"), "")
+	createVersionedModule(t, tmpDir, filepath.Join(DirBases, "network"), "~> 1.7.0")
+	createVersionedModule(t, tmpDir, filepath.Join(DirProjects, "prod"), "~> 1.8.0")
+	modules, err := walkModules(tmpDir, "")
+	if err != nil {
+		t.Fatalf("walkModules() error = %v", err)
+	}
+	sortModules(modules)
+
+	problems, err := checkRequiredVersions(tmpDir, modules, ">= 1.6")
+	if err != nil {
+		t.Fatalf("checkRequiredVersions() error = %v", err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Module+": "+p.Reason)
+	}
+	want := []string{
+		`bases/network: required_version "~> 1.7.0" is incompatible with "~> 1.8.0" of projects/prod`,
+		`components/legacy: required_version "~> 1.5.0" is incompatible with constraints.terraform ">= 1.6"`,
+		`components/vnet: no required_version declared`,
+		`projects/prod: required_version "~> 1.8.0" is incompatible with "~> 1.7.0" of bases/network`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("problems =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheckRequiredVersions_NoRepoConstraint(t *testing.T) {
+	tmpDir := t.TempDir()
+	createVersionedModule(t, tmpDir, filepath.Join(DirComponents, "naming"), ">= 1.0")
+	createVersionedModule(t, tmpDir, filepath.Join(DirComponents, "vnet"), "~> 1.5.0")
+	modules, err := walkModules(tmpDir, "")
+	if err != nil {
+		t.Fatalf("walkModules() error = %v", err)
+	}
+
+	problems, err := checkRequiredVersions(tmpDir, modules, "")
+	if err != nil {
+		t.Fatalf("checkRequiredVersions() error = %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("expected no problems, got %+v", problems)
+	}
+}
+
+func TestCheckVersionsCmd_ReportsProblems(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	content := "constraints:\n  terraform: \">= 1.6\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, config.ConfigFile), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", config.ConfigFile, err)
+	}
+	withWorkingDir(t, tmpDir)
+	createVersionedModule(t, tmpDir, filepath.Join(DirComponents, "naming"), ">= 1.6")
+	createVersionedModule(t, tmpDir, filepath.Join(DirComponents, "legacy"), "< 1.6")
+
+	rootCmd.SetArgs([]string{"check", "versions"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 module(s) have required_version problems") {
+		t.Fatalf("expected a required_version error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+}
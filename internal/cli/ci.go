@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/cigen"
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ciOutputFlag        string // File to write the pipeline to, - for stdout
+	ciBranchFlag        string // Branch that pushes run the pipeline on
+	ciBinaryVersionFlag string // Version of terraform/tofu to install
+	ciForceFlag         bool   // Overwrite an existing pipeline file
+)
+
+// ciCmd groups the CI pipeline commands
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Set up CI pipelines that run motf",
+}
+
+var ciGenerateCmd = &cobra.Command{
+	Use:   "generate <provider>",
+	Short: "Generate a CI pipeline for changed modules",
+	Long: `Generate a pipeline definition that checks formatting of the modules changed
+by a pull request or push, and validates and tests them in parallel.
+
+Providers and the files they're written to, relative to the repository root:
+  github   .github/workflows/motf.yml
+  azure    azure-pipelines.yml
+  gitlab   .gitlab-ci.yml
+
+The pipeline installs the binary from .motf.yml, and the test engine when it's a
+different binary, and only runs when files in the module directories or
+.motf.yml change. Pushes run it on --branch, which defaults to the branch
+origin/HEAD points to.
+
+An existing file is only replaced with --force. Use --output - or --dry-run to
+print the pipeline instead.`,
+	Example: `  motf ci generate github                        # Write .github/workflows/motf.yml
+  motf ci generate gitlab --branch master        # Run on pushes to master
+  motf ci generate azure --binary-version 1.8.0  # Install a specific binary version
+  motf ci generate github -o -                   # Print the workflow`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: cigen.Providers(),
+	RunE:      runCIGenerate,
+}
+
+func init() {
+	ciGenerateCmd.Flags().StringVarP(&ciOutputFlag, "output", "o", "", "File to write the pipeline to, - for stdout (default: the provider's pipeline file)")
+	ciGenerateCmd.Flags().StringVar(&ciBranchFlag, "branch", "", "Branch that pushes run the pipeline on (default: auto-detect from origin/HEAD, else main)")
+	ciGenerateCmd.Flags().StringVar(&ciBinaryVersionFlag, "binary-version", "latest", "Version of terraform/tofu to install")
+	ciGenerateCmd.Flags().BoolVar(&ciForceFlag, "force", false, "Overwrite an existing pipeline file")
+	ciCmd.AddCommand(ciGenerateCmd)
+	rootCmd.AddCommand(ciCmd)
+}
+
+func runCIGenerate(cmd *cobra.Command, args []string) error {
+	provider := args[0]
+	defaultPath, err := cigen.DefaultPath(provider)
+	if err != nil {
+		return err
+	}
+
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to get git root: %w", err)
+	}
+	opts, err := ciOptions(repoRoot)
+	if err != nil {
+		return err
+	}
+	content, err := cigen.Generate(provider, opts)
+	if err != nil {
+		return err
+	}
+
+	if ciOutputFlag == "-" || dryRunFlag {
+		fmt.Print(string(content))
+		return nil
+	}
+	outputPath := ciOutputFlag
+	if outputPath == "" {
+		outputPath = filepath.Join(repoRoot, filepath.FromSlash(defaultPath))
+	}
+	if _, err := os.Stat(outputPath); err == nil && !ciForceFlag {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", outputPath)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to check %s: %w", outputPath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", outputPath, err)
+	}
+	if err := os.WriteFile(outputPath, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+	fmt.Printf("Wrote %s\n", outputPath)
+	return nil
+}
+
+// ciOptions returns the pipeline options from the flags and the config, with
+// paths relative to the repository root.
+func ciOptions(repoRoot string) (cigen.Options, error) {
+	opts := cigen.Options{
+		Binary:        cfg.Binary,
+		BinaryVersion: ciBinaryVersionFlag,
+		Branch:        ciBranchFlag,
+	}
+	if cfg.Test != nil {
+		opts.TestEngine = cfg.Test.Engine
+	}
+	if opts.Branch == "" {
+		opts.Branch = "main"
+		if branch, err := git.GetDefaultBranch(); err == nil {
+			opts.Branch = strings.TrimPrefix(branch, "origin/")
+		}
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return opts, err
+	}
+	for _, d := range searchDirs() {
+		relPath, err := repoRelativePath(repoRoot, filepath.Join(basePath, d.Dir))
+		if err != nil {
+			return opts, fmt.Errorf("%s: %w", d.Dir, err)
+		}
+		opts.ModuleDirs = append(opts.ModuleDirs, relPath)
+	}
+	if cfg.ConfigPath != "" {
+		if opts.ConfigFile, err = repoRelativePath(repoRoot, cfg.ConfigPath); err != nil {
+			return opts, fmt.Errorf("%s: %w", cfg.ConfigPath, err)
+		}
+	}
+	return opts, nil
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func resetCIFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		ciOutputFlag = ""
+		ciBranchFlag = ""
+		ciBinaryVersionFlag = "latest"
+		ciForceFlag = false
+	})
+}
+
+// setupCIRepo creates an empty repository with a .motf.yml in infra/ that
+// sets the root to infra. It returns the repository root.
+func setupCIRepo(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	cmd := exec.Command("git", "init")
+	cmd.Dir = tmpDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\nOutput: %s", err, output)
+	}
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{
+		Root:       "infra",
+		Binary:     "tofu",
+		Test:       &config.TestConfig{Engine: "tofu"},
+		ConfigPath: filepath.Join(tmpDir, "infra", config.ConfigFile),
+	})
+	return tmpDir
+}
+
+func TestCIGenerateCmd_Flags(t *testing.T) {
+	for _, name := range []string{"output", "branch", "binary-version", "force"} {
+		if ciGenerateCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected ci generate command to have --%s flag", name)
+		}
+	}
+}
+
+func TestCIOptions(t *testing.T) {
+	resetFlags(t)
+	resetCIFlags(t)
+	repoDir := setupCIRepo(t)
+
+	opts, err := ciOptions(repoDir)
+	if err != nil {
+		t.Fatalf("ciOptions() error = %v", err)
+	}
+	if opts.Binary != "tofu" || opts.TestEngine != "tofu" || opts.BinaryVersion != "latest" {
+		t.Errorf("ciOptions() binary = %s %s, engine = %s", opts.Binary, opts.BinaryVersion, opts.TestEngine)
+	}
+	// No origin, so the branch falls back to main
+	if opts.Branch != "main" {
+		t.Errorf("ciOptions() branch = %q, want main", opts.Branch)
+	}
+	if got, want := strings.Join(opts.ModuleDirs, ","), "infra/components,infra/bases,infra/projects"; got != want {
+		t.Errorf("ciOptions() module dirs = %s, want %s", got, want)
+	}
+	if opts.ConfigFile != "infra/.motf.yml" {
+		t.Errorf("ciOptions() config file = %q, want infra/.motf.yml", opts.ConfigFile)
+	}
+}
+
+func TestCIGenerate_WritesPipeline(t *testing.T) {
+	resetFlags(t)
+	resetCIFlags(t)
+	repoDir := setupCIRepo(t)
+	ciBranchFlag = "master"
+
+	if err := runCIGenerate(ciGenerateCmd, []string{"github"}); err != nil {
+		t.Fatalf("runCIGenerate() error = %v", err)
+	}
+	workflow := filepath.Join(repoDir, ".github", "workflows", "motf.yml")
+	data, err := os.ReadFile(workflow)
+	if err != nil {
+		t.Fatalf("failed to read workflow: %v", err)
+	}
+	for _, want := range []string{"- master", "- 'infra/components/**'", "opentofu/setup-opentofu"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected workflow to contain %q, got:\n%s", want, data)
+		}
+	}
+
+	// An existing file is only replaced with --force
+	err = runCIGenerate(ciGenerateCmd, []string{"github"})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected already exists error, got %v", err)
+	}
+	ciForceFlag = true
+	if err := runCIGenerate(ciGenerateCmd, []string{"github"}); err != nil {
+		t.Errorf("runCIGenerate() with --force error = %v", err)
+	}
+}
+
+func TestCIGenerate_DryRun(t *testing.T) {
+	resetFlags(t)
+	resetCIFlags(t)
+	repoDir := setupCIRepo(t)
+	dryRunFlag = true
+
+	if err := runCIGenerate(ciGenerateCmd, []string{"gitlab"}); err != nil {
+		t.Fatalf("runCIGenerate() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, ".gitlab-ci.yml")); !os.IsNotExist(err) {
+		t.Error("expected --dry-run not to write .gitlab-ci.yml")
+	}
+}
+
+func TestCIGenerate_UnknownProvider(t *testing.T) {
+	resetFlags(t)
+	resetCIFlags(t)
+
+	err := runCIGenerate(ciGenerateCmd, []string{"jenkins"})
+	if err == nil || !strings.Contains(err.Error(), "unknown CI provider") {
+		t.Fatalf("expected unknown provider error, got %v", err)
+	}
+}
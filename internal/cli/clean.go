@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/clean"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanLocksFlag bool // Also remove dependency lock files
+	cleanStateFlag bool // Also remove local state files
+	cleanYesFlag   bool // Remove local state without asking for confirmation
+)
+
+// cleanCmd represents the clean command
+var cleanCmd = &cobra.Command{
+	Use:   "clean [module-name]",
+	Short: "Remove .terraform directories, crash logs, and other Terraform leftovers",
+	Long: `Remove the files Terraform leaves behind in a component, base, or project, and
+its examples and tests: .terraform directories and crash logs. Select modules
+with --all, --changed, --select, or --type to clean several at once.
+
+Use --locks to also remove .terraform.lock.hcl files, so the next init selects
+provider versions again. Use --state to also remove local state files
+(terraform.tfstate, its backup, and terraform.tfstate.d); they can't be
+recovered, so motf asks for confirmation first unless --yes is set.
+
+Use --dry-run to list what would be removed.`,
+	Example: `  motf clean storage-account            # Clean a single module
+  motf clean --all                      # Clean every module
+  motf clean --changed --locks          # Also remove lock files of changed modules
+  motf clean --all --state --dry-run    # List what would be removed, including local state`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runClean,
+}
+
+func init() {
+	cleanCmd.Flags().BoolVar(&cleanLocksFlag, "locks", false, "Also remove .terraform.lock.hcl files")
+	cleanCmd.Flags().BoolVar(&cleanStateFlag, "state", false, "Also remove local state files, after confirmation")
+	cleanCmd.Flags().BoolVarP(&cleanYesFlag, "yes", "y", false, "Remove local state without asking for confirmation")
+	cleanCmd.Flags().BoolVar(&allFlag, "all", false, "Clean all modules")
+	cleanCmd.Flags().BoolVar(&changedFlag, "changed", false, "Clean modules changed compared to --ref")
+	cleanCmd.Flags().StringVar(&selectFlag, "select", "", "Clean modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	cleanCmd.Flags().StringVar(&typeFlag, "type", "", "Clean modules of a type (component, base, project)")
+	cleanCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(cleanCmd)
+	rootCmd.AddCommand(cleanCmd)
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	dirs, err := cleanDirs(cmd, basePath, args)
+	if err != nil || len(dirs) == 0 {
+		return err
+	}
+
+	cmd.SilenceUsage = true
+	out := cmd.OutOrStdout()
+	opts := clean.Options{Locks: cleanLocksFlag, State: cleanStateFlag}
+	var items []clean.Item
+	for _, dir := range dirs {
+		found, err := clean.Find(dir, opts)
+		if err != nil {
+			return err
+		}
+		for _, item := range found {
+			// Nested modules are found from each selected parent
+			if !slices.ContainsFunc(items, func(i clean.Item) bool { return i.Path == item.Path }) {
+				items = append(items, item)
+			}
+		}
+	}
+	if len(items) == 0 {
+		_, _ = fmt.Fprintln(out, "Nothing to clean")
+		return nil
+	}
+
+	if dryRunFlag {
+		for _, item := range items {
+			_, _ = fmt.Fprintf(out, "[dry-run] Would remove %s (%s)\n", displayPath(basePath, item.Path), item.Kind)
+		}
+		return nil
+	}
+
+	var states []string
+	for _, item := range items {
+		if item.Kind == clean.KindState {
+			states = append(states, displayPath(basePath, item.Path))
+		}
+	}
+	if len(states) > 0 && !cleanYesFlag {
+		_, _ = fmt.Fprintf(out, "Local state files:\n  %s\n", strings.Join(states, "\n  "))
+		ok, err := confirm(cmd.InOrStdin(), fmt.Sprintf("\nRemove %d local state file(s)? They can't be recovered. [y/N] ", len(states)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			_, _ = fmt.Fprintln(out, "Aborted")
+			return nil
+		}
+	}
+
+	for _, item := range items {
+		if err := clean.Remove(item); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "Removed %s (%s)\n", displayPath(basePath, item.Path), item.Kind)
+	}
+	return nil
+}
+
+// cleanDirs returns the directories of the modules selected by --all,
+// --changed, --select, and --type, or of the module named by args or --path.
+func cleanDirs(cmd *cobra.Command, basePath string, args []string) ([]string, error) {
+	if !selectingModules() {
+		dir, err := resolveTargetPath(args)
+		if err != nil {
+			return nil, err
+		}
+		return []string{dir}, nil
+	}
+	if len(args) > 0 {
+		return nil, cobra.MaximumNArgs(0)(cmd, args)
+	}
+	if allFlag && changedFlag {
+		return nil, fmt.Errorf("--all cannot be used with --changed")
+	}
+	if pathFlag != "" {
+		return nil, fmt.Errorf("--path cannot be used with --all, --changed, --select, or --type")
+	}
+
+	modules, err := selectModules(basePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(modules) == 0 {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), noModulesMessage(selectFlag))
+		return nil, nil
+	}
+	dirs := make([]string, 0, len(modules))
+	for _, mod := range modules {
+		dirs = append(dirs, filepath.Join(basePath, mod.Path))
+	}
+	return dirs, nil
+}
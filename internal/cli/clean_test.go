@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func runCleanCmd(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader(stdin))
+	err := runClean(cmd, args)
+	return out.String(), err
+}
+
+// cleanRepo creates a repository with Terraform leftovers in a component and
+// a project, and returns its root.
+func cleanRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	withConfig(t, &config.Config{Root: root})
+	withWorkingDir(t, root)
+	network := createTerraformModule(t, root, filepath.Join(DirComponents, "network"))
+	platform := createTerraformModule(t, root, filepath.Join(DirProjects, "platform"))
+	for _, file := range []string{
+		filepath.Join(network, ".terraform", "modules", "modules.json"),
+		filepath.Join(network, ".terraform.lock.hcl"),
+		filepath.Join(platform, ".terraform", "terraform.tfstate"),
+		filepath.Join(platform, "terraform.tfstate"),
+		filepath.Join(platform, "crash.log"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestClean_Module(t *testing.T) {
+	resetFlags(t)
+	root := cleanRepo(t)
+
+	out, err := runCleanCmd(t, "", "network")
+	if err != nil {
+		t.Fatalf("runClean() error = %v", err)
+	}
+	if out != "Removed components/network/.terraform (cache)\n" {
+		t.Errorf("output = %q", out)
+	}
+	if exists(filepath.Join(root, "components", "network", ".terraform")) {
+		t.Error(".terraform was not removed")
+	}
+	if !exists(filepath.Join(root, "components", "network", ".terraform.lock.hcl")) {
+		t.Error("the lock file was removed without --locks")
+	}
+	if !exists(filepath.Join(root, "projects", "platform", ".terraform")) {
+		t.Error("another module was cleaned")
+	}
+}
+
+func TestClean_AllDryRun(t *testing.T) {
+	resetFlags(t)
+	root := cleanRepo(t)
+	allFlag = true
+	cleanLocksFlag = true
+	cleanStateFlag = true
+	dryRunFlag = true
+
+	out, err := runCleanCmd(t, "")
+	if err != nil {
+		t.Fatalf("runClean() error = %v", err)
+	}
+	want := `[dry-run] Would remove components/network/.terraform (cache)
+[dry-run] Would remove components/network/.terraform.lock.hcl (lock)
+[dry-run] Would remove projects/platform/.terraform (cache)
+[dry-run] Would remove projects/platform/crash.log (crash log)
+[dry-run] Would remove projects/platform/terraform.tfstate (state)
+`
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+	if !exists(filepath.Join(root, "projects", "platform", "terraform.tfstate")) {
+		t.Error("dry run removed the state")
+	}
+}
+
+func TestClean_StateConfirmation(t *testing.T) {
+	resetFlags(t)
+	root := cleanRepo(t)
+	cleanStateFlag = true
+	state := filepath.Join(root, "projects", "platform", "terraform.tfstate")
+
+	out, err := runCleanCmd(t, "n\n", "platform")
+	if err != nil {
+		t.Fatalf("runClean() error = %v", err)
+	}
+	if !strings.Contains(out, "Local state files:\n  projects/platform/terraform.tfstate\n") || !strings.HasSuffix(out, "Aborted\n") {
+		t.Errorf("output = %q", out)
+	}
+	if !exists(state) || !exists(filepath.Join(root, "projects", "platform", "crash.log")) {
+		t.Error("files were removed without confirmation")
+	}
+
+	if _, err := runCleanCmd(t, "y\n", "platform"); err != nil {
+		t.Fatalf("runClean() error = %v", err)
+	}
+	if exists(state) {
+		t.Error("the state was not removed after confirmation")
+	}
+}
+
+func TestClean_Errors(t *testing.T) {
+	resetFlags(t)
+	cleanRepo(t)
+	allFlag = true
+
+	if _, err := runCleanCmd(t, "", "network"); err == nil {
+		t.Error("expected an error for a module name with --all")
+	}
+	changedFlag = true
+	if _, err := runCleanCmd(t, ""); err == nil || !strings.Contains(err.Error(), "--all cannot be used with --changed") {
+		t.Errorf("error = %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// EnvNoColor disables colored output when set to a non-empty value, following
+// https://no-color.org
+const EnvNoColor = "NO_COLOR"
+
+// noColorFlag disables colored output, e.g. for CI logs
+var noColorFlag bool
+
+// applyNoColorEnv disables colored output from NO_COLOR unless --no-color was
+// set explicitly.
+func applyNoColorEnv(cmd *cobra.Command, getenv func(string) string) {
+	if cmd.Flags().Changed("no-color") {
+		return
+	}
+	if getenv(EnvNoColor) != "" {
+		noColorFlag = true
+	}
+}
+
+// isTerminal reports whether w is a terminal. It is a variable so tests can
+// replace it.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd())) //nolint:gosec // File descriptors fit in an int
+}
+
+// colorOutput reports whether output written to w is colored: only on a
+// terminal, and not with --no-color, NO_COLOR, or --plain.
+func colorOutput(w io.Writer) bool {
+	return !noColorFlag && !plainFlag && isTerminal(w)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noColorFlag, "no-color", false, "Disable colored output (env: NO_COLOR); output is only colored on a terminal")
+}
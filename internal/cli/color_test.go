@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyNoColorEnv(t *testing.T) {
+	resetFlags(t)
+	newCmd := func() *cobra.Command {
+		noColorFlag = false
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().BoolVar(&noColorFlag, "no-color", false, "")
+		return cmd
+	}
+	env := func(value string) func(string) string {
+		return func(key string) string {
+			if key == EnvNoColor {
+				return value
+			}
+			return ""
+		}
+	}
+
+	applyNoColorEnv(newCmd(), env("1"))
+	if !noColorFlag {
+		t.Error("expected NO_COLOR=1 to disable colors")
+	}
+
+	cmd := newCmd()
+	if err := cmd.Flags().Set("no-color", "false"); err != nil {
+		t.Fatalf("failed to set flag: %v", err)
+	}
+	applyNoColorEnv(cmd, env("1"))
+	if noColorFlag {
+		t.Error("expected --no-color=false to override NO_COLOR")
+	}
+
+	applyNoColorEnv(newCmd(), env(""))
+	if noColorFlag {
+		t.Error("expected an empty NO_COLOR to keep colors enabled")
+	}
+}
+
+func TestColorOutput(t *testing.T) {
+	resetFlags(t)
+	original := isTerminal
+	t.Cleanup(func() { isTerminal = original })
+	terminal := true
+	isTerminal = func(io.Writer) bool { return terminal }
+
+	var buf bytes.Buffer
+	if !colorOutput(&buf) {
+		t.Error("expected color on a terminal")
+	}
+	terminal = false
+	if colorOutput(&buf) {
+		t.Error("expected no color when not a terminal")
+	}
+	terminal = true
+	noColorFlag = true
+	if colorOutput(&buf) {
+		t.Error("expected no color with --no-color")
+	}
+	noColorFlag = false
+	plainFlag = true
+	if colorOutput(&buf) {
+		t.Error("expected no color with --plain")
+	}
+
+	// Buffers are never terminals
+	isTerminal = original
+	plainFlag = false
+	if colorOutput(&buf) {
+		t.Error("expected no color for a buffer")
+	}
+}
+
+func TestPrefixedWriter_NoColor(t *testing.T) {
+	var buf bytes.Buffer
+	pw := newPrefixedWriter("dns", 5, 0, &buf, &sync.Mutex{}, false)
+	pw.timeFunc = func() time.Time { return time.Date(2025, 1, 31, 14, 32, 1, 123000000, time.UTC) }
+	if _, err := pw.Write([]byte("Hello\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if got, want := buf.String(), "dns   | 14:32:01.123 # Hello\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if strings.Contains(buf.String(), "\033[") {
+		t.Error("expected no color codes")
+	}
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/spf13/cobra"
+)
+
+// flagCompletions complete the values of flags shared by many commands,
+// registered on every command that has them by registerFlagCompletions
+var flagCompletions = map[string]cobra.CompletionFunc{
+	"example": completeExampleNames,
+	"type":    completeModuleTypes,
+	"task":    completeTaskNames,
+	"engine":  cobra.FixedCompletions(config.ValidTestEngineNames(), cobra.ShellCompDirectiveNoFileComp),
+}
+
+// registerFlagCompletions registers flagCompletions on cmd and its
+// subcommands. Commands register their flags in their own init, so this runs
+// once they all exist.
+func registerFlagCompletions(cmd *cobra.Command) {
+	for name, fn := range flagCompletions {
+		if cmd.Flags().Lookup(name) != nil {
+			if _, ok := cmd.GetFlagCompletionFunc(name); !ok {
+				_ = cmd.RegisterFlagCompletionFunc(name, fn)
+			}
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		registerFlagCompletions(sub)
+	}
+}
+
+// loadCompletionConfig loads the config for shell completion, which runs
+// without the root command's PersistentPreRunE.
+func loadCompletionConfig() bool {
+	if cfg != nil {
+		return true
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	cfg, err = config.Load(wd, configFlag)
+	return err == nil
+}
+
+// completeModuleNames completes the module name argument of a command with
+// the names of all modules, described by their path.
+func completeModuleNames(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 || pathFlag != "" || !loadCompletionConfig() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	modules, err := finder.ListAllModules(basePath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []cobra.Completion
+	for name, path := range modules {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, completionWithDesc(name, displayPath(basePath, path)))
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeExampleNames completes --example with the examples of the module
+// given as argument or with --path.
+func completeExampleNames(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if !loadCompletionConfig() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	modulePath, err := resolveTargetPath(args)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	entries, err := os.ReadDir(filepath.Join(modulePath, DirExamples))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []cobra.Completion
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), toComplete) && finder.HasTerraformFiles(filepath.Join(modulePath, DirExamples, e.Name())) {
+			completions = append(completions, e.Name())
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTaskNames completes --task with the tasks of .motf.yml, described
+// by their description.
+func completeTaskNames(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if !loadCompletionConfig() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []cobra.Completion
+	for name, task := range cfg.Tasks {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, completionWithDesc(name, task.Description))
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionWithDesc returns a completion described by desc, if not empty.
+func completionWithDesc(choice, desc string) cobra.Completion {
+	if desc == "" {
+		return choice
+	}
+	return cobra.CompletionWithDesc(choice, desc)
+}
+
+// completeModuleTypes completes --type with the types of the module
+// directories.
+func completeModuleTypes(*cobra.Command, []string, string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	loadCompletionConfig()
+	return moduleTypes(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteModuleNames(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "key-vault"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "k8s-argocd"))
+
+	got, directive := completeModuleNames(planCmd, nil, "")
+	want := []cobra.Completion{
+		"k8s-argocd\tbases/k8s-argocd",
+		"key-vault\tcomponents/azurerm/key-vault",
+		"storage-account\tcomponents/azurerm/storage-account",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("completeModuleNames() = %q, want %q", got, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+
+	got, _ = completeModuleNames(planCmd, nil, "k")
+	if len(got) != 2 {
+		t.Errorf("expected 2 completions for k, got %q", got)
+	}
+
+	// Only the first argument is a module name
+	if got, _ := completeModuleNames(planCmd, []string{"key-vault"}, ""); len(got) != 0 {
+		t.Errorf("expected no completions after the module name, got %q", got)
+	}
+	pathFlag = tmpDir
+	if got, _ := completeModuleNames(planCmd, nil, ""); len(got) != 0 {
+		t.Errorf("expected no completions with --path, got %q", got)
+	}
+}
+
+func TestCompleteExampleNames(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir})
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "naming"))
+	createTerraformModule(t, modulePath, filepath.Join(DirExamples, "basic"))
+	createTerraformModule(t, modulePath, filepath.Join(DirExamples, "complete"))
+	if err := os.MkdirAll(filepath.Join(modulePath, DirExamples, "docs"), 0755); err != nil {
+		t.Fatalf("failed to create docs dir: %v", err)
+	}
+
+	got, _ := completeExampleNames(valCmd, []string{"naming"}, "")
+	if want := []cobra.Completion{"basic", "complete"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("completeExampleNames() = %q, want %q", got, want)
+	}
+
+	got, _ = completeExampleNames(valCmd, []string{"naming"}, "c")
+	if want := []cobra.Completion{"complete"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("completeExampleNames(c) = %q, want %q", got, want)
+	}
+
+	pathFlag = modulePath
+	if got, _ := completeExampleNames(valCmd, nil, ""); len(got) != 2 {
+		t.Errorf("expected 2 completions with --path, got %q", got)
+	}
+
+	pathFlag = ""
+	if got, _ := completeExampleNames(valCmd, []string{"missing"}, ""); len(got) != 0 {
+		t.Errorf("expected no completions for an unknown module, got %q", got)
+	}
+}
+
+func TestCompleteTaskNames(t *testing.T) {
+	resetFlags(t)
+	withConfig(t, &config.Config{Tasks: map[string]*tasks.TaskConfig{
+		"lint": {Description: "Run tflint"},
+		"docs": {},
+	}})
+
+	got, _ := completeTaskNames(taskCmd, nil, "")
+	if want := []cobra.Completion{"docs", "lint\tRun tflint"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("completeTaskNames() = %q, want %q", got, want)
+	}
+}
+
+func TestRegisterFlagCompletions(t *testing.T) {
+	registerFlagCompletions(rootCmd)
+	// Registering twice is a no-op
+	registerFlagCompletions(rootCmd)
+
+	for _, tt := range []struct {
+		cmd  *cobra.Command
+		flag string
+	}{
+		{valCmd, "example"},
+		{planCmd, "type"},
+		{taskCmd, "task"},
+		{listCmd, "type"},
+	} {
+		if _, ok := tt.cmd.GetFlagCompletionFunc(tt.flag); !ok {
+			t.Errorf("expected %s --%s to have a completion", tt.cmd.Name(), tt.flag)
+		}
+	}
+
+	fn, _ := planCmd.GetFlagCompletionFunc("type")
+	if got, _ := fn(planCmd, nil, ""); !reflect.DeepEqual(got, ModuleTypes) {
+		t.Errorf("--type completions = %q, want %q", got, ModuleTypes)
+	}
+}
+
+func TestModuleCommandsCompleteModuleNames(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if strings.Contains(cmd.Use, "module-name") && cmd.ValidArgsFunction == nil {
+			t.Errorf("expected %q to complete module names", cmd.CommandPath())
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
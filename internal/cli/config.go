@@ -1,17 +1,44 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/metrics"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
 	"github.com/spf13/cobra"
 )
 
+// configJSONFlag outputs the effective configuration as JSON
+var configJSONFlag bool
+
 // configCmd represents the config command
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Show current configuration",
-	Long:  "Display the current configuration values, showing which config file is in use (if any) and the effective settings.",
+	Long: `Display the current configuration values, showing which config file is in use (if any) and the effective settings.
+
+With --json, every setting is output as JSON with its effective value: the
+config file in use (or the one given with --config) merged with the defaults,
+with environment variables in its values expanded. Only the names of env are
+output since its values often hold credentials.`,
+	Example: `  motf config
+  motf config --json | jq .parallelism`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if configJSONFlag {
+			output, err := json.MarshalIndent(effectiveConfig(cfg), "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+
 		if cfg.ConfigPath != "" {
 			fmt.Printf("Config file: %s\n\n", cfg.ConfigPath)
 		} else {
@@ -21,13 +48,111 @@ var configCmd = &cobra.Command{
 		fmt.Println("Settings:")
 		fmt.Printf("  root:   %s\n", valueOrDefault(cfg.Root, "(current directory)"))
 		fmt.Printf("  binary: %s\n", cfg.Binary)
+		if len(cfg.ManagedPaths) > 0 {
+			fmt.Printf("  managed_paths: %s\n", strings.Join(cfg.ManagedPaths, ", "))
+		}
+		if len(cfg.Vars) > 0 {
+			fmt.Printf("  vars:   %s\n", strings.Join(cfg.Vars, ", "))
+		}
 
 		fmt.Println("\nTest:")
 		fmt.Printf("  engine: %s\n", cfg.Test.Engine)
 		fmt.Printf("  args:   %s\n", valueOrDefault(cfg.Test.Args, "(none)"))
+		if cfg.Test.Engine == "compliance" {
+			fmt.Printf("  compliance command:  %s\n", cfg.Test.Compliance.GetCommand())
+			fmt.Printf("  compliance features: %s\n", cfg.Test.Compliance.GetFeatures())
+		}
+		if cfg.Test.Retries > 0 {
+			fmt.Printf("  retries: %d\n", cfg.Test.Retries)
+		}
+		if cfg.Test.Cost > 0 {
+			fmt.Printf("  cost:   %g\n", cfg.Test.Cost)
+		}
+		if cfg.Test.Templates != "" {
+			fmt.Printf("  templates: %s\n", cfg.Test.Templates)
+		}
+		for _, q := range cfg.Test.Quarantine {
+			status := "until"
+			if !q.Active(now()) {
+				status = "expired"
+			}
+			fmt.Printf("  quarantine: %s (%s %s)\n", q.Module, status, q.Until)
+		}
+
+		if ignore := cfg.Changed.GetIgnore(); len(ignore) > 0 {
+			fmt.Println("\nChanged:")
+			fmt.Printf("  ignore: %s\n", strings.Join(ignore, ", "))
+		}
+
+		fmt.Println("\nSecurity:")
+		fmt.Printf("  scanner: %s\n", cfg.Security.GetScanner())
+
+		if len(cfg.Timeouts) > 0 {
+			fmt.Println("\nTimeouts:")
+			for _, command := range slices.Sorted(maps.Keys(cfg.Timeouts)) {
+				fmt.Printf("  %s: %s\n", command, cfg.Timeouts[command])
+			}
+		}
+
+		fmt.Println("\nLint:")
+		fmt.Printf("  description_placeholder: %s\n", cfg.Lint.GetDescriptionPlaceholder())
+		fmt.Println("\nLock:")
+		fmt.Printf("  platforms: %s\n", strings.Join(cfg.Lock.GetPlatforms(), ", "))
+
+		fmt.Println("\nRelease:")
+		if skip := cfg.Release.GetSkip(); len(skip) > 0 {
+			fmt.Printf("  skip: %s\n", strings.Join(skip, ", "))
+		}
+		fmt.Printf("  push: %t\n", cfg.Release.GetPush())
+		fmt.Printf("  remote: %s\n", cfg.Release.GetRemote())
+		fmt.Printf("  changelog: %s\n", cfg.Release.GetChangelog())
+
+		fmt.Println("\nSources:")
+		fmt.Printf("  registries: %s\n", strings.Join(cfg.Sources.GetRegistries(), ", "))
+
+		if c, providers := cfg.Constraints.GetTerraform(), cfg.Constraints.GetProviders(); c != "" || len(providers) > 0 {
+			fmt.Println("\nConstraints:")
+			if c != "" {
+				fmt.Printf("  terraform: %s\n", c)
+			}
+			for _, name := range slices.Sorted(maps.Keys(providers)) {
+				fmt.Printf("  providers.%s: %s\n", name, providers[name])
+			}
+		}
+
+		fmt.Println("\nPlans:")
+		fmt.Printf("  dir: %s\n", cfg.Plans.GetDir())
 
 		fmt.Println("\nParallelism:")
 		fmt.Printf("  max_jobs: %d\n", cfg.Parallelism.GetMaxJobs())
+		if timeout := cfg.Parallelism.GetTimeout(); timeout > 0 {
+			fmt.Printf("  timeout: %s\n", timeout)
+		}
+		if retries := cfg.Parallelism.GetRetries(); retries > 0 {
+			fmt.Printf("  retries: %d (after %s, doubling)\n", retries, cfg.Parallelism.GetRetryDelay())
+		}
+
+		if m := cfg.Metrics; m != nil {
+			fmt.Println("\nMetrics:")
+			fmt.Printf("  format: %s\n", m.Format)
+			if m.Format == metrics.FormatStatsD {
+				fmt.Printf("  address: %s\n", m.GetAddress())
+			} else {
+				fmt.Printf("  path: %s\n", m.Path)
+			}
+			fmt.Printf("  prefix: %s\n", m.GetPrefix())
+			for _, name := range slices.Sorted(maps.Keys(m.Labels)) {
+				fmt.Printf("  label: %s=%s\n", name, m.Labels[name])
+			}
+		}
+
+		if len(cfg.Env) > 0 {
+			// Only names are shown since values often hold credentials
+			fmt.Println("\nEnv:")
+			for _, name := range slices.Sorted(maps.Keys(cfg.Env)) {
+				fmt.Printf("  %s\n", name)
+			}
+		}
 
 		if len(cfg.Tasks) > 0 {
 			fmt.Println("\nTasks:")
@@ -41,6 +166,10 @@ var configCmd = &cobra.Command{
 			}
 
 			for name, task := range cfg.Tasks {
+				if plainFlag {
+					fmt.Printf(" - %s: %s\n", name, valueOrDefault(task.Description, "(no description)"))
+					continue
+				}
 				fmt.Printf(" - %-*s %s\n", nameWidth, name, valueOrDefault(task.Description, "(no description)"))
 			}
 		}
@@ -49,6 +178,43 @@ var configCmd = &cobra.Command{
 	},
 }
 
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a single configuration value",
+	Long: `Print the effective value of a setting, by its dot-separated path in the
+output of 'motf config --json', e.g. test.engine. List items are selected by
+their index, e.g. module_dirs.0.dir.
+
+Strings, numbers, and booleans are printed as is, so scripts can use them
+directly; sections and lists are printed as JSON. An unset value prints an
+empty line, and an unknown key fails.`,
+	Example: `  motf config get binary
+  motf config get parallelism.max_jobs
+  motf config get module_dirs`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := lookupConfigKey(effectiveConfig(cfg), args[0])
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		switch v := value.(type) {
+		case nil:
+			fmt.Println()
+		case map[string]any, []any:
+			output, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(output))
+		default:
+			fmt.Println(v)
+		}
+		return nil
+	},
+}
+
 // valueOrDefault returns the value if non-empty, otherwise the default string
 func valueOrDefault(value, defaultStr string) string {
 	if value == "" {
@@ -57,6 +223,192 @@ func valueOrDefault(value, defaultStr string) string {
 	return value
 }
 
+// effectiveConfig returns the settings of c with their effective values,
+// defaults included, keyed like .motf.yml.
+func effectiveConfig(c *config.Config) map[string]any {
+	moduleDirs := []map[string]string{}
+	for _, d := range c.GetModuleDirs() {
+		moduleDirs = append(moduleDirs, map[string]string{"dir": d.Dir, "type": d.Type})
+	}
+	types := []map[string]string{}
+	for _, rule := range c.Types {
+		types = append(types, map[string]string{"pattern": rule.Pattern, "type": rule.Type})
+	}
+	quarantine := []map[string]string{}
+	for _, q := range c.Test.Quarantine {
+		quarantine = append(quarantine, map[string]string{"module": q.Module, "until": q.Until, "reason": q.Reason})
+	}
+	taskSettings := map[string]any{}
+	for name, task := range c.Tasks {
+		if task == nil {
+			continue
+		}
+		steps := []string{}
+		for _, step := range task.Steps {
+			steps = append(steps, step.String())
+		}
+		taskSettings[name] = map[string]any{
+			"description": task.Description,
+			"shell":       valueOrDefault(task.Shell, tasks.DefaultShell),
+			"command":     task.Command,
+			"env":         emptyIfNil(slices.Sorted(maps.Keys(task.Env))),
+			"depends_on":  emptyIfNil(task.DependsOn),
+			"steps":       steps,
+		}
+	}
+
+	var configFile any
+	if c.ConfigPath != "" {
+		configFile = c.ConfigPath
+	}
+	var backend any
+	if c.Backend != nil {
+		backend = map[string]any{
+			"type":         c.Backend.Type,
+			"config":       c.Backend.Config,
+			"environments": emptyIfNil(c.Backend.GetEnvironments()),
+			"file":         c.Backend.GetFile(),
+			"config_dir":   c.Backend.GetConfigDir(),
+		}
+	}
+	var metricsSettings any
+	if c.Metrics != nil {
+		metricsSettings = map[string]any{
+			"format":  c.Metrics.Format,
+			"path":    c.Metrics.Path,
+			"address": c.Metrics.GetAddress(),
+			"prefix":  c.Metrics.GetPrefix(),
+			"labels":  emptyMapIfNil(c.Metrics.Labels),
+		}
+	}
+	var timeout string
+	if d := c.Parallelism.GetTimeout(); d > 0 {
+		timeout = d.String()
+	}
+
+	return map[string]any{
+		"config_file":    configFile,
+		"root":           c.Root,
+		"binary":         c.Binary,
+		"binary_version": c.BinaryVersion,
+		"roots":          emptyIfNil(c.GetRoots()),
+		"module_dirs":    moduleDirs,
+		"types":          types,
+		"managed_paths":  emptyIfNil(c.ManagedPaths),
+		"vars":           emptyIfNil(c.Vars),
+		"test": map[string]any{
+			"engine":     c.Test.Engine,
+			"args":       c.Test.Args,
+			"retries":    c.Test.GetRetries(),
+			"cost":       c.Test.GetCost(),
+			"quarantine": quarantine,
+			"templates":  c.Test.GetTemplates(),
+			"compliance": map[string]string{
+				"command":  c.Test.Compliance.GetCommand(),
+				"features": c.Test.Compliance.GetFeatures(),
+			},
+		},
+		"tasks": taskSettings,
+		"parallelism": map[string]any{
+			"max_jobs":    c.Parallelism.GetMaxJobs(),
+			"log_dir":     c.Parallelism.GetLogDir(),
+			"timeout":     timeout,
+			"retries":     c.Parallelism.GetRetries(),
+			"retry_delay": c.Parallelism.GetRetryDelay().String(),
+		},
+		"security": map[string]any{
+			"scanner": c.Security.GetScanner(),
+			"args":    c.Security.GetArgs(),
+		},
+		"changed": map[string]any{"ignore": emptyIfNil(c.Changed.GetIgnore())},
+		"lint":    map[string]any{"description_placeholder": c.Lint.GetDescriptionPlaceholder()},
+		"lock":    map[string]any{"platforms": c.Lock.GetPlatforms()},
+		"release": map[string]any{
+			"skip":      emptyIfNil(c.Release.GetSkip()),
+			"push":      c.Release.GetPush(),
+			"remote":    c.Release.GetRemote(),
+			"changelog": c.Release.GetChangelog(),
+		},
+		"sources":     map[string]any{"registries": c.Sources.GetRegistries()},
+		"plans":       map[string]any{"dir": c.Plans.GetDir()},
+		"constraints": map[string]any{"terraform": c.Constraints.GetTerraform(), "providers": emptyMapIfNil(c.Constraints.GetProviders())},
+		"checks": map[string]any{
+			"component": c.Checks.GetRules("component"),
+			"base":      c.Checks.GetRules("base"),
+			"project":   c.Checks.GetRules("project"),
+		},
+		"policy": map[string]any{
+			"paths": emptyIfNil(c.Policy.GetPaths()),
+			"query": c.Policy.GetQuery(),
+			"plan":  c.Policy.GetPlan(),
+		},
+		"watch": map[string]any{
+			"command":  c.Watch.GetCommand(),
+			"task":     c.Watch.GetTask(),
+			"debounce": c.Watch.GetDebounce().String(),
+		},
+		"backend":  backend,
+		"metrics":  metricsSettings,
+		"env":      emptyIfNil(slices.Sorted(maps.Keys(c.Env))),
+		"timeouts": emptyMapIfNil(c.Timeouts),
+		"hooks":    emptyMapIfNil(c.Hooks),
+	}
+}
+
+// emptyIfNil returns s, or an empty slice when s is nil, so it is output as
+// [] instead of null in JSON.
+func emptyIfNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// emptyMapIfNil returns m, or an empty map when m is nil, so it is output as
+// {} instead of null in JSON.
+func emptyMapIfNil(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
+
+// lookupConfigKey returns the value at the dot-separated key in settings,
+// with sections as map[string]any and lists as []any, like decoded JSON.
+func lookupConfigKey(settings map[string]any, key string) (any, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	for i, part := range strings.Split(key, ".") {
+		parent := strings.Join(strings.Split(key, ".")[:i], ".")
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[part]
+			if !ok {
+				return nil, fmt.Errorf("unknown config key '%s'", key)
+			}
+			value = next
+		case []any:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, fmt.Errorf("invalid index '%s' of '%s': it has %d item(s)", part, parent, len(v))
+			}
+			value = v[index]
+		default:
+			return nil, fmt.Errorf("unknown config key '%s': '%s' is not a section", key, parent)
+		}
+	}
+	return value, nil
+}
+
 func init() {
+	configCmd.Flags().BoolVar(&configJSONFlag, "json", false, "Output the effective configuration as JSON")
+	configCmd.AddCommand(configGetCmd)
 	rootCmd.AddCommand(configCmd)
 }
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	c := config.DefaultConfig()
+	c.Binary = "tofu"
+	c.ModuleDirs = config.ModuleDirs{{Dir: "modules", Type: "module"}}
+	c.Env = map[string]string{"ARM_CLIENT_SECRET": "secret", "TF_LOG": "debug"}
+	c.Tasks = map[string]*tasks.TaskConfig{"lint": {Command: "tflint"}}
+
+	settings := effectiveConfig(c)
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{"binary", "tofu"},
+		{"module_dirs.0.type", "module"},
+		{"security.scanner", "trivy"},
+		{"release.remote", "origin"},
+		{"tasks.lint.shell", "sh"},
+		{"config_file", nil},
+		{"env.1", "TF_LOG"},
+	}
+	for _, tt := range tests {
+		got, err := lookupConfigKey(settings, tt.key)
+		if err != nil {
+			t.Errorf("lookupConfigKey(%q) error = %v", tt.key, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("lookupConfigKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	// Only the names of env are output, its values may be credentials
+	env, _ := lookupConfigKey(settings, "env")
+	if !slices.Equal(env.([]any), []any{"ARM_CLIENT_SECRET", "TF_LOG"}) {
+		t.Errorf("env = %v, want only the names", env)
+	}
+}
+
+func TestLookupConfigKey_Errors(t *testing.T) {
+	settings := effectiveConfig(config.DefaultConfig())
+	tests := []struct {
+		key     string
+		wantErr string
+	}{
+		{"test.nope", "unknown config key 'test.nope'"},
+		{"binary.name", "'binary' is not a section"},
+		{"module_dirs.3", "invalid index '3' of 'module_dirs': it has 3 item(s)"},
+		{"module_dirs.first", "invalid index 'first'"},
+	}
+	for _, tt := range tests {
+		_, err := lookupConfigKey(settings, tt.key)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("lookupConfigKey(%q) error = %v, want %q", tt.key, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfigGetCmd_UnknownKey(t *testing.T) {
+	resetFlags(t)
+	withConfig(t, config.DefaultConfig())
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	rootCmd.SetArgs([]string{"config", "get", "nope"})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "unknown config key 'nope'") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+}
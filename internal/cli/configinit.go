@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/configgen"
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configInitDefaultsFlag bool // Write the proposed settings without prompting
+	configInitForceFlag    bool // Overwrite an existing .motf.yml
+)
+
+// lookPath finds an executable in PATH. It is a variable so tests can replace it.
+var lookPath = exec.LookPath
+
+// configInitCmd represents the config init command
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a .motf.yml for the repository",
+	Long: `Create a commented .motf.yml at the root of the git repository, or in the
+current directory outside git.
+
+motf proposes the settings from the repository: the binary is tofu when only
+tofu is installed and terraform otherwise, the root is the directory with
+components/, bases/, or projects/, and without those, the directories holding
+Terraform modules become module_dirs. Each setting is asked for with the
+proposal as default; --defaults writes the proposals without asking.
+
+An existing .motf.yml is only replaced with --force. Use --dry-run to print the
+file instead of writing it.`,
+	Example: `  motf config init                      # Answer a few questions
+  motf config init --defaults           # Use the proposed settings
+  motf config init --defaults --dry-run # Print the file without writing it`,
+	Args: cobra.NoArgs,
+	RunE: runConfigInit,
+}
+
+func init() {
+	configInitCmd.Flags().BoolVar(&configInitDefaultsFlag, "defaults", false, "Use the proposed settings without prompting")
+	configInitCmd.Flags().BoolVar(&configInitForceFlag, "force", false, "Overwrite an existing .motf.yml")
+	configCmd.AddCommand(configInitCmd)
+}
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	dir, err := git.GetRepoRoot()
+	if err != nil {
+		if dir, err = os.Getwd(); err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+	}
+	configPath := filepath.Join(dir, config.ConfigFile)
+	if _, err := os.Stat(configPath); err == nil && !configInitForceFlag && !dryRunFlag {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", configPath)
+	}
+
+	opts, err := configgen.Detect(dir)
+	if err != nil {
+		return err
+	}
+	opts.Binary = detectBinary()
+
+	if !configInitDefaultsFlag {
+		if err := promptConfig(cmd.InOrStdin(), &opts); err != nil {
+			return err
+		}
+	}
+
+	content := configgen.Render(opts)
+	if dryRunFlag {
+		fmt.Printf("[dry-run] Would write %s:\n\n%s", configPath, content)
+		return nil
+	}
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", config.ConfigFile, err)
+	}
+	fmt.Printf("Created %s\n", configPath)
+	return nil
+}
+
+// detectBinary returns tofu when only tofu is installed, and terraform otherwise.
+func detectBinary() string {
+	if _, err := lookPath("terraform"); err != nil {
+		if _, err := lookPath("tofu"); err == nil {
+			return "tofu"
+		}
+	}
+	return "terraform"
+}
+
+// promptConfig asks for each setting of opts, with the proposal as default.
+func promptConfig(in io.Reader, opts *configgen.Options) error {
+	r := bufio.NewReader(in)
+
+	binary, err := prompt(r, fmt.Sprintf("Binary (%s)", strings.Join(config.ValidBinaryNames(), ", ")), opts.Binary)
+	if err != nil {
+		return err
+	}
+	if !config.IsValidBinary(binary) {
+		return fmt.Errorf("invalid binary '%s': must be %s", binary, strings.Join(config.ValidBinaryNames(), " or "))
+	}
+	opts.Binary = binary
+
+	root, err := prompt(r, "Root directory, relative to the repository", opts.Root)
+	if err != nil {
+		return err
+	}
+	opts.Root = strings.Trim(filepath.ToSlash(root), "/")
+	if opts.Root == "." {
+		opts.Root = ""
+	}
+
+	moduleDirs, err := prompt(r, "Module directories, comma-separated", strings.Join(opts.ModuleDirs, ", "))
+	if err != nil {
+		return err
+	}
+	opts.ModuleDirs = nil
+	for d := range strings.SplitSeq(moduleDirs, ",") {
+		if d = strings.TrimSpace(d); d != "" && !slices.Contains(opts.ModuleDirs, d) {
+			opts.ModuleDirs = append(opts.ModuleDirs, d)
+		}
+	}
+	return nil
+}
+
+// prompt asks question and returns the trimmed answer, or def when the answer
+// is empty.
+func prompt(r *bufio.Reader, question, def string) (string, error) {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", question, def)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+	answer, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
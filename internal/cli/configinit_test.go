@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func resetConfigInitFlags(t *testing.T) {
+	t.Helper()
+	resetFlags(t)
+	original := lookPath
+	t.Cleanup(func() {
+		configInitDefaultsFlag = false
+		configInitForceFlag = false
+		lookPath = original
+		rootCmd.SetArgs(nil)
+		rootCmd.SetIn(nil)
+	})
+}
+
+// configInitRepo creates a repository with modules in modules/ and live/ and
+// makes it the working directory.
+func configInitRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	createTerraformModule(t, root, filepath.Join("modules", "network"))
+	createTerraformModule(t, root, filepath.Join("live", "prod"))
+	withWorkingDir(t, root)
+	return root
+}
+
+func readConfigFile(t *testing.T, root string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, config.ConfigFile))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", config.ConfigFile, err)
+	}
+	return string(data)
+}
+
+func TestConfigInitCmd_Defaults(t *testing.T) {
+	resetConfigInitFlags(t)
+	root := configInitRepo(t)
+	lookPath = func(name string) (string, error) {
+		if name == "tofu" {
+			return "/usr/bin/tofu", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	rootCmd.SetArgs([]string{"config", "init", "--defaults"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config init --defaults failed: %v", err)
+	}
+
+	content := readConfigFile(t, root)
+	for _, want := range []string{"binary: tofu\n", "module_dirs: [live, modules]\n", "# root: \"\"\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in config, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestConfigInitCmd_Prompts(t *testing.T) {
+	resetConfigInitFlags(t)
+	root := configInitRepo(t)
+	lookPath = func(name string) (string, error) { return "/usr/bin/" + name, nil }
+
+	// Keep the proposed binary, set a root, and replace the module directories
+	rootCmd.SetArgs([]string{"config", "init"})
+	rootCmd.SetIn(strings.NewReader("\niac/\nstacks, modules, stacks\n"))
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config init failed: %v", err)
+	}
+
+	content := readConfigFile(t, root)
+	for _, want := range []string{"binary: terraform\n", "root: iac\n", "module_dirs: [stacks, modules]\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in config, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestConfigInitCmd_InvalidBinary(t *testing.T) {
+	resetConfigInitFlags(t)
+	root := configInitRepo(t)
+
+	rootCmd.SetArgs([]string{"config", "init"})
+	rootCmd.SetIn(strings.NewReader("terragrunt\n"))
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "invalid binary 'terragrunt'") {
+		t.Fatalf("expected invalid binary error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, config.ConfigFile)); !os.IsNotExist(err) {
+		t.Error("expected no config file to be written")
+	}
+}
+
+func TestConfigInitCmd_ExistingConfig(t *testing.T) {
+	resetConfigInitFlags(t)
+	root := configInitRepo(t)
+	if err := os.WriteFile(filepath.Join(root, config.ConfigFile), []byte("binary: terraform\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"config", "init", "--defaults"})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected error for an existing config, got %v", err)
+	}
+
+	rootCmd.SetArgs([]string{"config", "init", "--defaults", "--force"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config init --force failed: %v", err)
+	}
+	if content := readConfigFile(t, root); !strings.Contains(content, "module_dirs: [live, modules]") {
+		t.Errorf("expected the config to be replaced, got:\n%s", content)
+	}
+}
+
+func TestConfigInitCmd_DryRun(t *testing.T) {
+	resetConfigInitFlags(t)
+	root := configInitRepo(t)
+
+	rootCmd.SetArgs([]string{"config", "init", "--defaults", "--dry-run"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config init --dry-run failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, config.ConfigFile)); !os.IsNotExist(err) {
+		t.Error("dry run should not write a config file")
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/spf13/cobra"
+)
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check .motf.yml for errors",
+	Long: `Check the .motf.yml in use, or the file given with --config, against the
+configuration schema and report every problem with its line and column:
+unknown keys, values of the wrong type, and values that aren't allowed, such
+as an unknown shell or test engine. When the file matches the schema, the
+checks motf runs when loading it are run too, e.g. for task dependency cycles,
+and the commands of tasks that set template are checked to be valid templates.`,
+	Example: `  motf config validate
+  motf config validate --config ci/.motf.yml`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+// configSchemaCmd represents the config schema command
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON schema of .motf.yml",
+	Long: `Print the JSON schema of .motf.yml, for completion and validation in
+editors, e.g. with the YAML language server:
+
+  motf config schema > .motf.schema.json
+
+and as first line of .motf.yml:
+
+  # yaml-language-server: $schema=.motf.schema.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, _ = cmd.OutOrStdout().Write(config.Schema())
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configSchemaCmd)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	path := configFlag
+	if path == "" {
+		if path = config.FindFile(wd); path == "" {
+			return fmt.Errorf("no %s found in %s or its parents up to the repository root", config.ConfigFile, wd)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	display := path
+	if rel, err := filepath.Rel(wd, path); err == nil && filepath.IsLocal(rel) {
+		display = rel
+	}
+
+	problems, err := config.CheckSchema(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", display, err)
+	}
+	for _, p := range problems {
+		fmt.Printf("%s:%d:%d: %s\n", display, p.Line, p.Column, p.Error())
+	}
+	if len(problems) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s has %d problem(s)", display, len(problems))
+	}
+
+	loaded, err := config.Load(filepath.Dir(path), path)
+	if err == nil {
+		err = tasks.ValidateTemplates(loaded.Tasks)
+	}
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s: %w", display, err)
+	}
+	fmt.Printf("%s is valid\n", display)
+	return nil
+}
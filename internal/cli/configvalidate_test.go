@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// configValidateRepo creates a repository with content as .motf.yml and makes
+// it the working directory.
+func configValidateRepo(t *testing.T, content string) {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, config.ConfigFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	withWorkingDir(t, root)
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+}
+
+func TestConfigValidateCmd(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: "binary: tofu\n"},
+		{name: "schema problems", content: "binry: tofu\ntest:\n  engine: pytest\n", wantErr: ".motf.yml has 2 problem(s)"},
+		{name: "load problems", content: "tasks:\n  a:\n    command: x\n    depends_on: [a]\n", wantErr: "task dependency cycle"},
+		{name: "invalid template", content: "tasks:\n  docs:\n    command: echo {{ .ModuleName\n    template: true\n", wantErr: "task 'docs' has an invalid template"},
+		{name: "invalid YAML", content: "binary: [tofu\n", wantErr: "failed to parse .motf.yml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			configValidateRepo(t, tt.content)
+
+			rootCmd.SetArgs([]string{"config", "validate"})
+			err := rootCmd.Execute()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("config validate failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("config validate error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigSchemaCmd(t *testing.T) {
+	resetFlags(t)
+	configValidateRepo(t, "binary: terragrunt\n") // A broken config doesn't stop the schema from being printed
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	t.Cleanup(func() { rootCmd.SetOut(nil) })
+
+	rootCmd.SetArgs([]string{"config", "schema"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config schema failed: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("config schema printed invalid JSON: %v", err)
+	}
+	if schema["title"] != "motf configuration" {
+		t.Errorf("unexpected schema title %v", schema["title"])
+	}
+}
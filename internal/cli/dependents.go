@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/modgraph"
+)
+
+// withDependents returns modules with the modules that depend on them through
+// at most depth levels of dependents, or any number if depth is negative,
+// and notes the added modules on stderr. Dependencies are local module
+// sources, see modgraph.Build.
+func withDependents(basePath string, modules []ModuleInfo, depth int) ([]ModuleInfo, error) {
+	if len(modules) == 0 {
+		return modules, nil
+	}
+	all, err := collectModules(basePath, "")
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]ModuleInfo, len(all))
+	paths := make([]string, 0, len(all))
+	for _, mod := range all {
+		path := filepath.ToSlash(mod.Path)
+		byPath[path] = mod
+		paths = append(paths, path)
+	}
+	graph, err := modgraph.Build(basePath, paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the module graph: %w", err)
+	}
+
+	selected := make([]string, len(modules))
+	for i, mod := range modules {
+		selected[i] = filepath.ToSlash(mod.Path)
+	}
+	consumers := graph.Consumers(selected, depth)
+	if len(consumers) == 0 {
+		return modules, nil
+	}
+	for _, path := range consumers {
+		modules = append(modules, byPath[path])
+	}
+	logging.Infof("including %d module(s) that depend on changed modules: %s", len(consumers), strings.Join(consumers, ", "))
+	return modules, nil
+}
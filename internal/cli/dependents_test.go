@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// callModule makes the module at modulePath call source.
+func callModule(t *testing.T, modulePath, source string) {
+	t.Helper()
+	content := "module \"dep\" {\n  source = \"" + source + "\"\n}\n"
+	if err := os.WriteFile(filepath.Join(modulePath, "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+}
+
+func TestWithDependents(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	callModule(t, createTerraformModule(t, tmpDir, filepath.Join(DirBases, "platform")), "../../components/storage")
+	callModule(t, createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "prod")), "../../bases/platform")
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "unrelated"))
+
+	changed := []ModuleInfo{{Name: "storage", Type: TypeComponent, Path: filepath.Join(DirComponents, "storage")}}
+	tests := []struct {
+		depth int
+		want  string
+	}{
+		{1, "storage platform"},
+		{2, "storage platform prod"},
+		{-1, "storage platform prod"},
+	}
+	for _, tt := range tests {
+		modules, err := withDependents(tmpDir, changed, tt.depth)
+		if err != nil {
+			t.Fatalf("withDependents() error = %v", err)
+		}
+		var names []string
+		for _, mod := range modules {
+			names = append(names, mod.Name)
+		}
+		if got := strings.Join(names, " "); got != tt.want {
+			t.Errorf("withDependents(depth %d) = %s, want %s", tt.depth, got, tt.want)
+		}
+	}
+}
+
+func TestTest_DependentsRequiresChanged(t *testing.T) {
+	resetFlags(t)
+	testDependentsFlag = 1
+
+	err := testCmd.RunE(testCmd, []string{"storage"})
+	if err == nil || !strings.Contains(err.Error(), "--dependents requires --changed") {
+		t.Errorf("expected --dependents requires --changed error, got %v", err)
+	}
+}
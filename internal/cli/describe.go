@@ -3,13 +3,19 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
 	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
 	"github.com/spf13/cobra"
 )
 
-var describeJsonFlag bool
+var (
+	describeJsonFlag           bool
+	describeDiffFlag           string // Git ref to compare the module's interface at HEAD with
+	describeFailOnBreakingFlag bool   // Exit with an error when --diff finds breaking changes
+)
 
 var describeCmd = &cobra.Command{
 	Use:   "describe [module-name]",
@@ -17,25 +23,47 @@ var describeCmd = &cobra.Command{
 	Long: `Parse and display the inputs, outputs, and providers of a Terraform module.
 
 Shows the module's required Terraform version, provider dependencies,
-input variables (with types, defaults, and descriptions), and outputs.`,
-	Example: `  motf describe storage-account       # Describe storage-account module
-  motf describe k8s-argocd --json     # Output as JSON
-  motf describe --path ./my-module    # Describe module at explicit path`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runDescribe,
+input variables (with types, defaults, and descriptions), outputs, and the
+resources, data sources, and child modules it declares.
+
+With --diff, shows the variables and outputs that were added, removed, or
+changed between a git ref and HEAD instead, reading both versions of the
+module from git. Uncommitted changes aren't included. Changes that can break
+callers of the module are marked breaking: a removed variable or output, a
+new or newly required variable without a default, a variable whose type
+changed, and an output that became sensitive. Use --fail-on-breaking to exit
+with an error when there are any.`,
+	Example: `  motf describe storage-account                     # Describe storage-account module
+  motf describe k8s-argocd --json                   # Output as JSON
+  motf describe --path ./my-module                  # Describe module at explicit path
+  motf describe storage-account --diff origin/main  # Show interface changes since origin/main
+  motf describe storage-account --diff v1.2.0 --fail-on-breaking`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runDescribe,
 }
 
 func init() {
 	describeCmd.Flags().BoolVar(&describeJsonFlag, "json", false, "Output in JSON format")
+	describeCmd.Flags().StringVar(&describeDiffFlag, "diff", "", "Show the interface changes between a git ref (e.g., origin/main) and HEAD")
+	describeCmd.Flags().BoolVar(&describeFailOnBreakingFlag, "fail-on-breaking", false, "With --diff, exit with an error when there are breaking changes")
 	rootCmd.AddCommand(describeCmd)
 }
 
 func runDescribe(cmd *cobra.Command, args []string) error {
+	if describeFailOnBreakingFlag && describeDiffFlag == "" {
+		return fmt.Errorf("--fail-on-breaking requires --diff")
+	}
+
 	targetPath, err := resolveTargetPath(args)
 	if err != nil {
 		return err
 	}
 
+	if describeDiffFlag != "" {
+		return runDescribeDiff(cmd, targetPath, describeDiffFlag)
+	}
+
 	schema, err := terraform.LoadModuleSchema(targetPath, getRoot())
 	if err != nil {
 		return fmt.Errorf("failed to parse module: %w", err)
@@ -45,10 +73,79 @@ func runDescribe(cmd *cobra.Command, args []string) error {
 		return printSchemaJSON(cmd, schema)
 	}
 
+	if plainFlag {
+		printSchemaPlain(cmd, schema)
+		return nil
+	}
+
 	printSchema(cmd, schema)
 	return nil
 }
 
+// interfaceDiff is the JSON output of describe --diff
+type interfaceDiff struct {
+	Module  string                      `json:"module"`
+	Ref     string                      `json:"ref"`
+	Changes []terraform.InterfaceChange `json:"changes"`
+}
+
+// runDescribeDiff prints the changes to the interface of the module at
+// targetPath from ref to HEAD, both read from git.
+func runDescribeDiff(cmd *cobra.Command, targetPath, ref string) error {
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return err
+	}
+	dir, err := filepath.Rel(repoRoot, targetPath)
+	if err != nil || strings.HasPrefix(dir, "..") {
+		return fmt.Errorf("module %s is outside the git repository %s", targetPath, repoRoot)
+	}
+
+	schemas := make([]*terraform.ModuleSchema, 2)
+	for i, rev := range []string{ref, "HEAD"} {
+		files, err := git.DirFiles(repoRoot, rev, filepath.ToSlash(dir))
+		if err != nil {
+			return err
+		}
+		if schemas[i], err = terraform.LoadModuleSchemaFromFiles(files, targetPath, getRoot()); err != nil {
+			return fmt.Errorf("failed to parse module at %s: %w", rev, err)
+		}
+	}
+	module := filepath.ToSlash(schemas[1].Path)
+	changes := terraform.DiffInterface(schemas[0], schemas[1])
+	if changes == nil {
+		changes = []terraform.InterfaceChange{}
+	}
+	breaking := 0
+	for _, c := range changes {
+		if c.Breaking {
+			breaking++
+		}
+	}
+
+	if describeJsonFlag {
+		output, err := json.MarshalIndent(interfaceDiff{Module: module, Ref: ref, Changes: changes}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		cmd.Println(string(output))
+	} else if len(changes) == 0 {
+		cmd.Printf("No changes to the interface of %s since %s\n", module, ref)
+	} else {
+		cmd.Printf("Interface changes of %s since %s:\n", module, ref)
+		for _, c := range changes {
+			cmd.Printf("  %s\n", c.String())
+		}
+		cmd.Printf("\n%d change(s), %d breaking\n", len(changes), breaking)
+	}
+
+	if describeFailOnBreakingFlag && breaking > 0 {
+		cmd.SilenceUsage = true
+		return findingsFailed("%d breaking change(s) to the interface of %s since %s", breaking, module, ref)
+	}
+	return nil
+}
+
 func printSchemaJSON(cmd *cobra.Command, schema *terraform.ModuleSchema) error {
 	output, err := json.MarshalIndent(schema, "", "  ")
 	if err != nil {
@@ -131,6 +228,85 @@ func printSchema(cmd *cobra.Command, schema *terraform.ModuleSchema) {
 			}
 		}
 	}
+
+	printResources(cmd, "Resources", schema.Resources)
+	printResources(cmd, "Data Sources", schema.DataSources)
+
+	// Module calls table, with the source last since it's often a long URL
+	if len(schema.ModuleCalls) > 0 {
+		cmd.Println("\nModule Calls:")
+		cmd.Printf("  %-25s %-15s %s\n", "NAME", "VERSION", "SOURCE")
+		for _, m := range schema.ModuleCalls {
+			cmd.Printf("  %-25s %-15s %s\n", truncate(m.Name, 25), truncate(valueOrDefault(m.Version, "-"), 15), m.Source)
+		}
+	}
+}
+
+// printResources prints a table of resources or data sources under title
+func printResources(cmd *cobra.Command, title string, resources []terraform.ResourceInfo) {
+	if len(resources) == 0 {
+		return
+	}
+	cmd.Printf("\n%s:\n", title)
+	cmd.Printf("  %-40s %s\n", "TYPE", "NAME")
+	for _, r := range resources {
+		cmd.Printf("  %-40s %s\n", truncate(r.Type, 40), r.Name)
+	}
+}
+
+// printSchemaPlain prints the schema as labeled lines without tables, truncation, or wrapping.
+func printSchemaPlain(cmd *cobra.Command, schema *terraform.ModuleSchema) {
+	cmd.Printf("Module: %s\n", schema.Name)
+	cmd.Printf("Path: %s\n", schema.Path)
+	if schema.TerraformVersion != "" {
+		cmd.Printf("Terraform: %s\n", schema.TerraformVersion)
+	}
+
+	printExample(cmd, schema)
+
+	for _, p := range schema.Providers {
+		version := p.Version
+		if version == "" {
+			version = "(any)"
+		}
+		cmd.Printf("\nProvider: %s\n", p.Name)
+		cmd.Printf("Version: %s\n", version)
+	}
+
+	for _, v := range schema.Variables {
+		cmd.Printf("\nVariable: %s\n", v.Name)
+		cmd.Printf("Type: %s\n", v.Type)
+		cmd.Printf("Default: %s\n", v.DefaultString())
+		if v.Description != "" {
+			cmd.Printf("Description: %s\n", v.Description)
+		}
+	}
+
+	for _, o := range schema.Outputs {
+		cmd.Printf("\nOutput: %s\n", o.Name)
+		if o.Description != "" {
+			cmd.Printf("Description: %s\n", o.Description)
+		}
+		if o.Sensitive {
+			cmd.Println("Sensitive: Yes")
+		}
+	}
+
+	for _, r := range schema.Resources {
+		cmd.Printf("\nResource: %s.%s\n", r.Type, r.Name)
+	}
+
+	for _, r := range schema.DataSources {
+		cmd.Printf("\nData Source: %s.%s\n", r.Type, r.Name)
+	}
+
+	for _, m := range schema.ModuleCalls {
+		cmd.Printf("\nModule Call: %s\n", m.Name)
+		cmd.Printf("Source: %s\n", m.Source)
+		if m.Version != "" {
+			cmd.Printf("Version: %s\n", m.Version)
+		}
+	}
 }
 
 func printExample(cmd *cobra.Command, schema *terraform.ModuleSchema) {
@@ -146,12 +322,18 @@ func printExample(cmd *cobra.Command, schema *terraform.ModuleSchema) {
 		}
 	}
 
+	// Plain output doesn't align the "=" signs
+	width := maxLen
+	if plainFlag {
+		width = 0
+	}
+
 	// Print required variables (if any)
 	if maxLen > 0 {
 		cmd.Println()
 		for _, v := range schema.Variables {
 			if v.Required {
-				cmd.Printf("    %-*s = %s\n", maxLen, v.Name, v.EmptyValueForType())
+				cmd.Printf("    %-*s = %s\n", width, v.Name, v.EmptyValueForType())
 			}
 		}
 	}
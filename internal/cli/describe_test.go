@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
 )
 
@@ -54,6 +56,15 @@ output "id" {
   value       = "test-id"
   description = "The resource ID"
 }
+
+resource "azurerm_resource_group" "this" {}
+
+data "azurerm_client_config" "current" {}
+
+module "naming" {
+  source  = "Azure/naming/azurerm"
+  version = "~> 0.4"
+}
 `
 	if err := os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(tfContent), 0644); err != nil {
 		t.Fatalf("failed to write main.tf: %v", err)
@@ -100,6 +111,15 @@ output "id" {
 	if !strings.Contains(output, "Outputs:") {
 		t.Error("expected output to contain 'Outputs:'")
 	}
+	if !strings.Contains(output, "Resources:") || !strings.Contains(output, "azurerm_resource_group") {
+		t.Error("expected output to contain the azurerm_resource_group resource")
+	}
+	if !strings.Contains(output, "Data Sources:") || !strings.Contains(output, "azurerm_client_config") {
+		t.Error("expected output to contain the azurerm_client_config data source")
+	}
+	if !strings.Contains(output, "Module Calls:") || !strings.Contains(output, "Azure/naming/azurerm") {
+		t.Error("expected output to contain the naming module call")
+	}
 }
 
 func TestDescribeCmd_JSONOutput(t *testing.T) {
@@ -127,6 +147,10 @@ variable "name" {
 output "id" {
   value = "test"
 }
+
+module "network" {
+  source = "../network"
+}
 `
 	if err := os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(tfContent), 0644); err != nil {
 		t.Fatalf("failed to write main.tf: %v", err)
@@ -165,6 +189,9 @@ output "id" {
 	if len(schema.Outputs) != 1 {
 		t.Errorf("expected 1 output, got %d", len(schema.Outputs))
 	}
+	if len(schema.ModuleCalls) != 1 || schema.ModuleCalls[0].Source != "../network" {
+		t.Errorf("expected the network module call, got %v", schema.ModuleCalls)
+	}
 }
 
 func TestDescribeCmd_ModuleNotFound(t *testing.T) {
@@ -256,3 +283,92 @@ func TestWrapText(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribeCmd_Diff(t *testing.T) {
+	resetFlags(t)
+
+	tmpDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	git("config", "commit.gpgSign", "false")
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir})
+
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	writeTF := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(modulePath, "variables.tf"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTF("variable \"name\" {\n  type = string\n}\n\nvariable \"sku\" {\n  type    = string\n  default = \"Standard\"\n}\n")
+	git("add", "-A")
+	git("commit", "-m", "feat: add storage")
+	git("tag", "v1")
+	writeTF("variable \"name\" {\n  type = string\n}\n\nvariable \"kind\" {\n  type = string\n}\n")
+	git("add", "-A")
+	git("commit", "-m", "feat!: replace sku with kind")
+	// Uncommitted changes aren't compared
+	writeTF("variable \"name\" {\n  type = number\n}\n")
+
+	buf := new(bytes.Buffer)
+	describeCmd.SetOut(buf)
+	t.Cleanup(func() { describeCmd.SetOut(nil) })
+	describeDiffFlag = "v1"
+	if err := runDescribe(describeCmd, []string{"storage"}); err != nil {
+		t.Fatalf("describe --diff failed: %v", err)
+	}
+	want := "Interface changes of components/storage since v1:\n" +
+		"  variable kind added, breaking\n" +
+		"  variable sku removed, breaking\n" +
+		"\n2 change(s), 2 breaking\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	describeJsonFlag = true
+	describeFailOnBreakingFlag = true
+	err := runDescribe(describeCmd, []string{"storage"})
+	if err == nil || !strings.Contains(err.Error(), "2 breaking change(s) to the interface of components/storage since v1") {
+		t.Errorf("expected a breaking changes error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+	var diff interfaceDiff
+	if err := json.Unmarshal(buf.Bytes(), &diff); err != nil {
+		t.Fatalf("failed to parse JSON output: %v\nOutput: %s", err, buf.String())
+	}
+	if diff.Module != "components/storage" || diff.Ref != "v1" || len(diff.Changes) != 2 {
+		t.Errorf("unexpected JSON output: %+v", diff)
+	}
+
+	buf.Reset()
+	describeDiffFlag = "HEAD"
+	if err := runDescribe(describeCmd, []string{"storage"}); err != nil {
+		t.Fatalf("describe --diff HEAD failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"changes": []`) {
+		t.Errorf("expected no changes, got %s", buf.String())
+	}
+}
+
+func TestDescribeCmd_FailOnBreakingRequiresDiff(t *testing.T) {
+	resetFlags(t)
+	describeFailOnBreakingFlag = true
+
+	err := runDescribe(describeCmd, []string{"storage"})
+	if err == nil || !strings.Contains(err.Error(), "--fail-on-breaking requires --diff") {
+		t.Errorf("expected an error, got %v", err)
+	}
+}
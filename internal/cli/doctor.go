@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/TechnicallyJoe/terraform-motf/internal/toolchain"
+	"github.com/TechnicallyJoe/terraform-motf/internal/vcs"
+	"github.com/spf13/cobra"
+)
+
+// capabilityFeatures describes the features that need each VCS capability
+var capabilityFeatures = map[vcs.Capability]string{
+	vcs.CapChanges: "--changed, motf changed",
+	vcs.CapPatch:   "motf changed --patch",
+	vcs.CapRelease: "motf release",
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment motf runs in",
+	Long: `Report the version control system of the working directory and the motf
+features it supports, the terraform/tofu binary, and the config file in use.
+
+Git repositories support every feature. Jujutsu and Sapling working copies
+that keep a .git directory (colocated Jujutsu repositories and Sapling
+repositories cloned with --git) are read through it. In a directory without
+version control, or one motf can't read, features that compare changes fail
+with an explanation and everything else works as usual.`,
+	Example: `  motf doctor`,
+	Args:    cobra.NoArgs,
+	RunE:    runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	repo := vcs.Detect(".")
+	if repo.Root() != "" {
+		fmt.Printf("Version control: %s (%s)\n", repo.Kind(), repo.Root())
+	} else {
+		fmt.Printf("Version control: %s\n", repo.Kind())
+	}
+	for _, c := range vcs.Capabilities {
+		status := "no"
+		if repo.Has(c) {
+			status = "yes"
+		}
+		fmt.Printf("  %-8s %-4s %s\n", c, status, capabilityFeatures[c])
+	}
+	if reason := repo.Reason(); reason != "" {
+		fmt.Printf("  Note: %s\n", reason)
+	}
+
+	if binary, err := terraform.Resolve(cfg); err != nil {
+		fmt.Printf("Binary: %s (%s)\n", cfg.Binary, err)
+	} else {
+		path, _ := exec.LookPath(binary)
+		details := []string{path}
+		if cfg.Binary == config.BinaryAuto {
+			details = append(details, "binary: "+cfg.Binary)
+		}
+		if cfg.BinaryVersion != "" {
+			v, _ := toolchain.Version(binary)
+			details = append(details, fmt.Sprintf("version %s, binary_version: %s", v, cfg.BinaryVersion))
+		}
+		fmt.Printf("Binary: %s (%s)\n", strings.TrimSuffix(filepath.Base(binary), ".exe"), strings.Join(details, ", "))
+	}
+	fmt.Printf("Config file: %s\n", valueOrDefault(cfg.ConfigPath, "none (using defaults)"))
+	return nil
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/vcs"
+)
+
+func TestDoctorCmd_Registered(t *testing.T) {
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "doctor" {
+			return
+		}
+	}
+	t.Error("expected doctor command to be registered")
+}
+
+func TestDoctor_WithoutVCS(t *testing.T) {
+	resetFlags(t)
+	dir := t.TempDir()
+	if vcs.Detect(dir).Kind() != vcs.KindNone {
+		t.Skip("temporary directory is under version control")
+	}
+	withWorkingDir(t, dir)
+	withConfig(t, &config.Config{Binary: "terraform"})
+
+	if err := runDoctor(doctorCmd, nil); err != nil {
+		t.Errorf("runDoctor() error = %v", err)
+	}
+
+	// Change detection fails with an explanation instead of a git error
+	changedFlag = true
+	_, err := detectChangedModules(changeRange{Ref: "main"})
+	if err == nil || !strings.Contains(err.Error(), "requires a git repository") || !strings.Contains(err.Error(), "no version control found") {
+		t.Errorf("detectChangedModules() error = %v, want missing version control", err)
+	}
+}
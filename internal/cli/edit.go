@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/hclform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editNameFlag        string
+	editTypeFlag        string
+	editDescriptionFlag string
+	editDefaultFlag     string
+	editSensitiveFlag   bool
+)
+
+// editCmd groups the commands that edit a module's .tf files
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the variables and outputs of a module",
+}
+
+var editAddVariableCmd = &cobra.Command{
+	Use:   "add-variable <module>",
+	Short: "Add a variable to a module",
+	Long: `Add a variable block to variables.tf of a component, base, or project,
+creating the file when needed. The rest of the file is kept as written, and
+the file is formatted like 'terraform fmt'.
+
+--type is a type constraint like list(string), and --default an HCL constant
+of that type, quoted for the shell: --default '"eastus"'. A variable without
+--default is required. motf refuses to add a variable the module already
+declares in any of its files.
+
+Use --dry-run to show where the variable would be added.`,
+	Example: `  motf edit add-variable storage-account --name sku --type string --description "SKU of the account"
+  motf edit add-variable storage-account --name replicas --type 'list(string)' --description "Replica regions" --default '[]'
+  motf edit add-variable network --name admin_password --type string --description "Admin password" --sensitive`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runEditAddVariable,
+}
+
+func init() {
+	editAddVariableCmd.Flags().StringVar(&editNameFlag, "name", "", "Name of the variable")
+	editAddVariableCmd.Flags().StringVar(&editTypeFlag, "type", "", "Type constraint, e.g. string or list(string) (default: any type)")
+	editAddVariableCmd.Flags().StringVar(&editDescriptionFlag, "description", "", "Description of the variable")
+	editAddVariableCmd.Flags().StringVar(&editDefaultFlag, "default", "", "Default value as an HCL constant, e.g. '\"eastus\"' (default: required variable)")
+	editAddVariableCmd.Flags().BoolVar(&editSensitiveFlag, "sensitive", false, "Mark the variable as sensitive")
+	_ = editAddVariableCmd.MarkFlagRequired("name")
+	_ = editAddVariableCmd.MarkFlagRequired("description")
+	editCmd.AddCommand(editAddVariableCmd)
+	rootCmd.AddCommand(editCmd)
+}
+
+func runEditAddVariable(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modulePath, err := resolveTargetPath(args)
+	if err != nil {
+		return err
+	}
+
+	cmd.SilenceUsage = true
+	out := cmd.OutOrStdout()
+	file := displayPath(basePath, filepath.Join(modulePath, hclform.VariablesFile))
+	variable := hclform.Variable{
+		Name:        editNameFlag,
+		Type:        editTypeFlag,
+		Description: editDescriptionFlag,
+		Default:     editDefaultFlag,
+		Sensitive:   editSensitiveFlag,
+	}
+	if dryRunFlag {
+		if err := hclform.CheckVariable(modulePath, variable); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "[dry-run] Would add variable.%s to %s\n", editNameFlag, file)
+		return nil
+	}
+
+	if err := hclform.AddVariable(modulePath, variable); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Added variable.%s to %s\n", editNameFlag, file)
+	return nil
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func runEditAddVariableCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	err := runEditAddVariable(cmd, args)
+	return out.String(), err
+}
+
+func TestEditAddVariable(t *testing.T) {
+	resetFlags(t)
+	root := t.TempDir()
+	withConfig(t, &config.Config{Root: root})
+	withWorkingDir(t, root)
+	modulePath := createTerraformModule(t, root, filepath.Join(DirComponents, "network"))
+	editNameFlag = "address_space"
+	editTypeFlag = "list(string)"
+	editDescriptionFlag = "Address space of the network"
+	editDefaultFlag = `["10.0.0.0/16"]`
+
+	dryRunFlag = true
+	out, err := runEditAddVariableCmd(t, "network")
+	if err != nil || out != "[dry-run] Would add variable.address_space to components/network/variables.tf\n" {
+		t.Errorf("dry run = %q, %v", out, err)
+	}
+	if exists(filepath.Join(modulePath, "variables.tf")) {
+		t.Error("--dry-run wrote variables.tf")
+	}
+
+	dryRunFlag = false
+	out, err = runEditAddVariableCmd(t, "network")
+	if err != nil || out != "Added variable.address_space to components/network/variables.tf\n" {
+		t.Errorf("add = %q, %v", out, err)
+	}
+	data, err := os.ReadFile(filepath.Join(modulePath, "variables.tf"))
+	if err != nil || !strings.Contains(string(data), `default     = ["10.0.0.0/16"]`) {
+		t.Errorf("variables.tf = %q, %v", data, err)
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		dryRunFlag = dryRun
+		if _, err := runEditAddVariableCmd(t, "network"); err == nil || !strings.Contains(err.Error(), "already exists in variables.tf") {
+			t.Errorf("adding the variable again with dry run %v: error = %v", dryRun, err)
+		}
+	}
+}
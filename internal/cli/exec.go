@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/spf13/cobra"
+)
+
+var (
+	execShellFlag    string // Shell to run the command with
+	execTemplateFlag bool   // Render the command as a template
+)
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command>",
+	Short: "Run a shell command in each module directory",
+	Long: `Run an ad-hoc shell command in the directory of each selected module.
+
+By default the command runs in every module. Use --changed to only run it in
+modules changed compared to --ref, --select to select modules by name or path
+with wildcards, and --type to select components, bases, or projects. They can
+be combined.
+
+The command runs like a task, with the MOTF_* environment variables. With
+--template, it's rendered with the template variables {{ .ModuleName }},
+{{ .ModulePath }}, {{ .ModuleType }}, and {{ .Example }} first. Quote it to use
+pipes and redirects.`,
+	Example: `  motf exec --changed -- 'terraform-docs markdown . > README.md'  # Regenerate docs of changed modules
+  motf exec --select '*storage*' -- ls -la                       # List files of matching modules
+  motf exec -p -- 'tflint --init && tflint'                      # Lint all modules in parallel
+  motf exec --template -- 'echo "{{ .ModuleType }}: $PWD"'       # Use template variables`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+func init() {
+	execCmd.Flags().StringVar(&execShellFlag, "shell", "", "Shell to run the command with (sh, bash, pwsh, cmd; default: sh)")
+	execCmd.Flags().BoolVar(&execTemplateFlag, "template", false, "Render the command as a template with the module variables, e.g. {{ .ModuleName }}")
+	execCmd.Flags().StringVar(&selectFlag, "select", "", "Only run in modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	execCmd.Flags().StringVar(&typeFlag, "type", "", "Only run in modules of a type (component, base, project)")
+	execCmd.Flags().BoolVar(&changedFlag, "changed", false, "Only run in modules changed compared to --ref")
+	execCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(execCmd)
+	addParallelFlags(execCmd)
+	addRetriesFlag(execCmd)
+	rootCmd.AddCommand(execCmd)
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	if pathFlag != "" {
+		return fmt.Errorf("exec cannot be used with --path, use --select to select modules")
+	}
+
+	command := strings.Join(args, " ")
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	modules, err := selectModules(basePath)
+	if err != nil {
+		return err
+	}
+	if len(modules) == 0 {
+		fmt.Println(noModulesMessage(selectFlag))
+		return nil
+	}
+
+	// Get git root (soft fail - empty string if not in git repo)
+	gitRoot, _ := git.GetRepoRoot()
+
+	var parallelismCfg *config.ParallelismConfig
+	if cfg != nil {
+		parallelismCfg = cfg.Parallelism
+	}
+
+	return RunOnModulesParallel(modules, parallelismCfg, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		moduleAbsPath := filepath.Join(basePath, mod.Path)
+		taskRunner, err := taskRunnerFor(gitRoot, moduleAbsPath)
+		if err != nil {
+			return err
+		}
+		return taskRunner.RunCommand(&tasks.TaskConfig{Shell: execShellFlag, Command: command, Template: execTemplateFlag}, moduleAbsPath, stdout, stderr)
+	})
+}
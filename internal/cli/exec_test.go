@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestExecCmd_Flags(t *testing.T) {
+	for _, name := range []string{"shell", "select", "changed", "ref", "parallel", "max-parallel", "log-dir"} {
+		if execCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected exec command to have --%s flag", name)
+		}
+	}
+}
+
+func TestExecCmd_RejectsPath(t *testing.T) {
+	resetFlags(t)
+	pathFlag = "./components/x"
+
+	err := runExec(execCmd, []string{"ls"})
+	if err == nil || !strings.Contains(err.Error(), "--path") {
+		t.Fatalf("expected --path error, got %v", err)
+	}
+}
+
+func TestExecCmd_Select(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "key-vault"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "storage-prod"))
+
+	if execCmd.Flags().Lookup("search") != nil {
+		t.Error("expected exec to select modules with --select, like other multi-module commands")
+	}
+	selectFlag = "storage*"
+	modules, err := selectModules(tmpDir)
+	if err != nil {
+		t.Fatalf("selectModules() error = %v", err)
+	}
+
+	var names []string
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	if strings.Join(names, ",") != "storage-account,storage-prod" {
+		t.Errorf("modules = %v, want [storage-account storage-prod]", names)
+	}
+}
+
+func TestExecCmd_RunsInEachModule(t *testing.T) {
+	resetFlags(t)
+	t.Cleanup(func() { execShellFlag, execTemplateFlag = "", false })
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "alpha"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "beta"))
+	logDir := filepath.Join(tmpDir, "logs")
+
+	rootCmd.SetArgs([]string{"exec", "--template", "--log-dir", logDir, "--", "echo {{ .ModuleType }}-$MOTF_MODULE_NAME > marker.txt"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+
+	for path, want := range map[string]string{
+		filepath.Join(DirComponents, "alpha"): "component-alpha",
+		filepath.Join(DirBases, "beta"):       "base-beta",
+	} {
+		data, err := os.ReadFile(filepath.Join(tmpDir, path, "marker.txt"))
+		if err != nil {
+			t.Fatalf("expected command to run in %s: %v", path, err)
+		}
+		if got := strings.TrimSpace(string(data)); got != want {
+			t.Errorf("%s: marker = %q, want %q", path, got, want)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(logDir, lastRunReportFile))
+	if err != nil {
+		t.Fatalf("expected run report: %v", err)
+	}
+	var summary chatops.Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("invalid run report: %v", err)
+	}
+	if succeeded, failed := summary.Counts(); succeeded != 2 || failed != 0 {
+		t.Errorf("report counts = %d succeeded, %d failed, want 2, 0", succeeded, failed)
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/i18n"
+)
+
+// Exit codes of motf, so CI can tell why a run failed.
+const (
+	ExitOK             = 0 // Success
+	ExitError          = 1 // Usage or configuration error
+	ExitModuleNotFound = 2 // A module, path, or example doesn't exist
+	ExitModuleFailed   = 3 // terraform/tofu, tests, a task, or convention checks failed for one or more modules
+	ExitPlanChanges    = 4 // Plans succeeded and have changes (plan --detailed-exitcode)
+)
+
+// errModuleNotFound marks errors for targets that don't exist.
+var errModuleNotFound = errors.New("module not found")
+
+// moduleNotFound marks a target resolution error as module-not-found.
+func moduleNotFound(err *i18n.Error) *i18n.Error {
+	err.Err = errModuleNotFound
+	return err
+}
+
+// planChangesError reports that plans with -detailed-exitcode have changes.
+// For a single module it is returned directly; with multiple modules it is
+// a non-fatal module outcome and returned once all modules succeeded.
+type planChangesError struct {
+	modules int // Modules whose plan has changes
+}
+
+func (e *planChangesError) Error() string {
+	if e.modules > 1 {
+		return fmt.Sprintf("plans of %d modules have changes", e.modules)
+	}
+	return "plan has changes"
+}
+
+// findingsError reports that a check found problems in modules, like
+// convention, security or policy findings. It exits with ExitModuleFailed,
+// like other failures of modules, rather than as a usage error.
+type findingsError struct {
+	msg string
+}
+
+func (e *findingsError) Error() string {
+	return e.msg
+}
+
+// findingsFailed returns a findingsError with a formatted message.
+func findingsFailed(format string, args ...any) error {
+	return &findingsError{msg: fmt.Sprintf(format, args...)}
+}
+
+// ExitCode classifies an error returned by Execute into an exit code. Module
+// failures take precedence, since a run across modules can both fail some
+// modules and find changes in others.
+func ExitCode(err error) int {
+	var modErr *moduleError
+	var exitErr *exec.ExitError
+	var changes *planChangesError
+	var findings *findingsError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &modErr), errors.As(err, &exitErr), errors.As(err, &findings):
+		return ExitModuleFailed
+	case errors.Is(err, errModuleNotFound):
+		return ExitModuleNotFound
+	case errors.As(err, &changes):
+		return ExitPlanChanges
+	default:
+		return ExitError
+	}
+}
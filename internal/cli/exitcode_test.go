@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/i18n"
+)
+
+// exitError returns the error of a process that exited with code.
+func exitError(t *testing.T, code int) error {
+	t.Helper()
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	if err == nil {
+		t.Fatalf("expected exit %d to fail", code)
+	}
+	return err
+}
+
+func TestExitCode(t *testing.T) {
+	mod := ModuleInfo{Name: "vnet", Path: "components/vnet"}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"usage error", errors.New("--all cannot be used with --changed"), ExitError},
+		{"module not found", moduleNotFound(i18n.Errorf("module 'vnet' not found", "", "")), ExitModuleNotFound},
+		{"terraform failed", fmt.Errorf("init: %w", exitError(t, 1)), ExitModuleFailed},
+		{"module failures", errors.Join(&moduleError{module: mod, err: errors.New("boom")}), ExitModuleFailed},
+		{"plan changes", &planChangesError{modules: 2}, ExitPlanChanges},
+		{"convention checks failed", findingsFailed("%d problem(s) in %d of %d module(s)", 2, 1, 3), ExitModuleFailed},
+		{"findings with a scan error", errors.Join(errors.New("scan failed"), findingsFailed("2 security finding(s)")), ExitModuleFailed},
+		{
+			"failures win over changes",
+			errors.Join(&planChangesError{modules: 1}, &moduleError{module: mod, err: errors.New("boom")}),
+			ExitModuleFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode_ModuleNotFound(t *testing.T) {
+	withConfig(t, &config.Config{Binary: "terraform"})
+	withWorkingDir(t, t.TempDir())
+
+	_, err := findModuleInAllDirs("nonexistent")
+	if got := ExitCode(err); got != ExitModuleNotFound {
+		t.Errorf("ExitCode() = %d, want %d for %v", got, ExitModuleNotFound, err)
+	}
+
+	_, err = resolveExplicitPath("does/not/exist")
+	if got := ExitCode(err); got != ExitModuleNotFound {
+		t.Errorf("ExitCode() = %d, want %d for %v", got, ExitModuleNotFound, err)
+	}
+}
+
+func TestPlanResult(t *testing.T) {
+	resetFlags(t)
+
+	// Without -detailed-exitcode, exit code 2 is a failure
+	if err := planResult(exitError(t, 2)); ExitCode(err) != ExitModuleFailed {
+		t.Errorf("planResult() = %v, want a module failure", err)
+	}
+
+	planDetailedExitcodeFlag = true
+	if err := planResult(exitError(t, 2)); ExitCode(err) != ExitPlanChanges {
+		t.Errorf("planResult() = %v, want plan changes", err)
+	}
+	if err := planResult(exitError(t, 1)); ExitCode(err) != ExitModuleFailed {
+		t.Errorf("planResult() = %v, want a module failure", err)
+	}
+	if err := planResult(nil); err != nil {
+		t.Errorf("planResult(nil) = %v, want nil", err)
+	}
+}
+
+func TestPlanArgs_DetailedExitcode(t *testing.T) {
+	resetFlags(t)
+	argsFlag = []string{"-lock=false"}
+	planDetailedExitcodeFlag = true
+
+	got := planArgs()
+	if len(got) != 2 || got[1] != detailedExitcodeArg {
+		t.Errorf("planArgs() = %v, want [-lock=false -detailed-exitcode]", got)
+	}
+	if len(argsFlag) != 1 {
+		t.Errorf("planArgs() modified --args: %v", argsFlag)
+	}
+
+	// Passed through --args already: not added twice
+	argsFlag = []string{detailedExitcodeArg}
+	if got := planArgs(); len(got) != 1 {
+		t.Errorf("planArgs() = %v, want a single -detailed-exitcode", got)
+	}
+}
+
+func TestRunOnModules_PlanChangesAreNonFatal(t *testing.T) {
+	modules := []ModuleInfo{{Name: "a", Path: "components/a"}, {Name: "b", Path: "components/b"}, {Name: "c", Path: "components/c"}}
+	results, err := runOnModulesWithResults(modules, false, 1, io.Discard, io.Discard, func(mod ModuleInfo, _, _ io.Writer) error {
+		if mod.Name == "c" {
+			return nil
+		}
+		return &planChangesError{modules: 1}
+	})
+	if err != nil {
+		t.Fatalf("expected plan changes not to fail the run, got %v", err)
+	}
+
+	changes := planChanges(results)
+	if ExitCode(changes) != ExitPlanChanges || changes.Error() != "plans of 2 modules have changes" {
+		t.Errorf("planChanges() = %v, want changes in 2 modules", changes)
+	}
+	if err := planChanges(results[2:]); err != nil {
+		t.Errorf("planChanges() = %v, want nil without changes", err)
+	}
+}
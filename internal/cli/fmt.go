@@ -18,19 +18,24 @@ Examples:
   motf fmt storage-account              # Run fmt on storage-account module
   motf fmt storage-account -e basic     # Run fmt on the 'basic' example
   motf fmt -i storage-account -e basic  # Run init then fmt on the 'basic' example`,
-	Args: cobra.MaximumNArgs(1),
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
+		if selectingModules() {
 			if len(args) > 0 {
 				return cobra.MaximumNArgs(0)(cmd, args)
 			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+			return runOnSelectedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := runnerFor(moduleAbsPath)
+				if err != nil {
+					return err
+				}
 				if initFlag {
-					if err := runner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+					if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
 						return err
 					}
 				}
-				return runner.RunFmtWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
+				return tfRunner.RunFmtWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
 			})
 		}
 
@@ -39,23 +44,31 @@ Examples:
 			return err
 		}
 
+		tfRunner, err := runnerFor(targetPath)
+		if err != nil {
+			return err
+		}
+
 		// Run init first if flag is set
 		if initFlag {
-			if err := runner.RunInit(targetPath); err != nil {
+			if err := tfRunner.RunInit(targetPath); err != nil {
 				return err
 			}
 		}
 
-		return runner.RunFmt(targetPath, argsFlag...)
+		return tfRunner.RunFmt(targetPath, argsFlag...)
 	},
 }
 
 func init() {
 	fmtCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
 	fmtCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	fmtCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
 	fmtCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	fmtCmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	fmtCmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
 	fmtCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
-	fmtCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
-	fmtCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	addChangeRangeFlags(fmtCmd)
+	addParallelFlags(fmtCmd)
 	rootCmd.AddCommand(fmtCmd)
 }
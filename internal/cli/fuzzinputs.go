@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/fuzz"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fuzzMockFlag bool // Plan through terraform test with mock providers
+	fuzzJSONFlag bool // Output results as JSON
+)
+
+// fuzzInputsCmd represents the fuzz-inputs command
+var fuzzInputsCmd = &cobra.Command{
+	Use:   "fuzz-inputs [module-name]",
+	Short: "Plan a module with edge-case variable values to find missing validation",
+	Long: `Plan a module with generated edge-case variable values to find inputs that
+get past its type constraints and validation blocks but break the module.
+
+Cases are generated from each variable's type: null, empty strings and collections,
+whitespace, long and unicode strings, zero, negative, fractional and large numbers,
+and the same edges for each attribute of an object. Required variables get a simple
+baseline value, other variables keep their default unless they are under test.
+
+Each case is planned and classified as:
+  accepted  the module planned successfully
+  rejected  a type constraint or validation block refused the value
+  failed    the value got past validation and broke the module
+
+Plans need initialized modules (use -i) and, without --mock, working provider
+credentials. With --mock, each case runs as a terraform test with mock providers
+(terraform >= 1.7, tofu >= 1.8). The baseline case must be accepted.
+
+The command fails when any case fails.`,
+	Example: `  motf fuzz-inputs storage-account -i --mock  # Init, then fuzz with mock providers
+  motf fuzz-inputs storage-account             # Fuzz with real provider plans
+  motf fuzz-inputs storage-account --json      # Output results as JSON`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runFuzzInputs,
+}
+
+func init() {
+	fuzzInputsCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
+	fuzzInputsCmd.Flags().BoolVar(&fuzzMockFlag, "mock", false, "Plan through terraform test with mock providers")
+	fuzzInputsCmd.Flags().BoolVar(&fuzzJSONFlag, "json", false, "Output results as JSON")
+	rootCmd.AddCommand(fuzzInputsCmd)
+}
+
+func runFuzzInputs(cmd *cobra.Command, args []string) error {
+	targetPath, err := resolveTargetPath(args)
+	if err != nil {
+		return err
+	}
+
+	schema, err := terraform.LoadModuleSchema(targetPath, getRoot())
+	if err != nil {
+		return fmt.Errorf("failed to parse module: %w", err)
+	}
+	if len(schema.Variables) == 0 {
+		fmt.Printf("Module %s has no variables to fuzz\n", schema.Name)
+		return nil
+	}
+
+	cases, err := fuzz.Generate(schema.Variables)
+	if err != nil {
+		return fmt.Errorf("failed to generate cases: %w", err)
+	}
+
+	tfRunner, err := runnerFor(targetPath)
+	if err != nil {
+		return err
+	}
+
+	// Keep stdout clean for --json by writing progress to stderr
+	progress := io.Writer(os.Stdout)
+	if fuzzJSONFlag {
+		progress = os.Stderr
+	}
+
+	if initFlag {
+		if err := tfRunner.RunInitWithOutput(targetPath, progress, os.Stderr); err != nil {
+			return err
+		}
+	}
+
+	// Test files must be inside the module for terraform test to find them.
+	// A dry run only shows the commands, so it doesn't create the directory.
+	workDir := filepath.Join(targetPath, ".motf-fuzz-dry-run")
+	if !dryRunFlag {
+		workDir, err = os.MkdirTemp(targetPath, ".motf-fuzz-")
+		if err != nil {
+			return fmt.Errorf("failed to create fuzz directory: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(workDir) }()
+	}
+
+	providers := make([]string, 0, len(schema.Providers))
+	for _, p := range schema.Providers {
+		providers = append(providers, p.Name)
+	}
+
+	results := make([]fuzz.Result, 0, len(cases))
+	for i, c := range cases {
+		_, _ = fmt.Fprintf(progress, "Case %d/%d: %s\n", i+1, len(cases), c.Name)
+
+		var output bytes.Buffer
+		out := io.Writer(&output)
+		if dryRunFlag {
+			out = progress
+		}
+		runErr := runFuzzCase(tfRunner, targetPath, workDir, fmt.Sprintf("case-%03d", i), c, providers, out)
+		if dryRunFlag {
+			if runErr != nil {
+				return runErr
+			}
+			continue
+		}
+
+		outcome, detail := fuzz.Classify(runErr, output.String())
+		if c.Name == fuzz.BaselineCase && outcome != fuzz.Accepted {
+			_, _ = os.Stderr.Write(output.Bytes())
+			hint := "fix the module or its provider configuration"
+			if !fuzzMockFlag {
+				hint = "check provider credentials, or use --mock"
+			}
+			return fmt.Errorf("baseline case was %s (%s): %s", outcome, detail, hint)
+		}
+		results = append(results, fuzz.Result{Case: c.Name, Outcome: outcome, Detail: detail})
+	}
+
+	if dryRunFlag {
+		return nil
+	}
+
+	if fuzzJSONFlag {
+		output, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		printFuzzResults(results)
+	}
+
+	if failed := fuzz.Failures(results); len(failed) > 0 {
+		return findingsFailed("%d case(s) got past input validation and failed", len(failed))
+	}
+	return nil
+}
+
+// runFuzzCase writes the inputs of a case to workDir and plans the module with them,
+// either directly with a var file or through terraform test with mock providers.
+func runFuzzCase(tfRunner *terraform.Runner, modulePath, workDir, fileName string, c fuzz.Case, providers []string, out io.Writer) error {
+	if fuzzMockFlag {
+		content, err := c.TestFile(providers)
+		if err != nil {
+			return fmt.Errorf("case %s: %w", c.Name, err)
+		}
+		testFile := filepath.Join(workDir, fileName+".tftest.hcl")
+		if err := writeFuzzFile(testFile, content, out); err != nil {
+			return err
+		}
+		testDir := filepath.Base(workDir)
+		return tfRunner.RunNativeTestWithOutput(modulePath, out, out,
+			"-no-color", "-test-directory="+testDir, "-filter="+filepath.ToSlash(filepath.Join(testDir, fileName+".tftest.hcl")))
+	}
+
+	content, err := c.VarFile()
+	if err != nil {
+		return fmt.Errorf("case %s: %w", c.Name, err)
+	}
+	varFile := filepath.Join(workDir, fileName+".tfvars.json")
+	if err := writeFuzzFile(varFile, content, out); err != nil {
+		return err
+	}
+	return tfRunner.RunPlanWithOutput(modulePath, out, out,
+		append([]string{"-no-color", "-input=false", "-lock=false", "-refresh=false", "-var-file=" + varFile}, argsFlag...)...)
+}
+
+// writeFuzzFile writes the inputs of a case to path. In dry-run mode, the
+// file is printed instead.
+func writeFuzzFile(path string, content []byte, out io.Writer) error {
+	if dryRunFlag {
+		_, _ = fmt.Fprintf(out, "[dry-run] Would write %s:\n%s\n", path, content)
+		return nil
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// printFuzzResults outputs the results as a table, followed by a count per outcome
+func printFuzzResults(results []fuzz.Result) {
+	fmt.Println()
+	counts := make(map[fuzz.Outcome]int)
+	for _, r := range results {
+		counts[r.Outcome]++
+	}
+
+	if plainFlag {
+		for i, r := range results {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("Case: %s\n", r.Case)
+			fmt.Printf("Outcome: %s\n", r.Outcome)
+			if r.Detail != "" {
+				fmt.Printf("Detail: %s\n", r.Detail)
+			}
+		}
+	} else {
+		caseWidth := len("CASE")
+		for _, r := range results {
+			caseWidth = max(caseWidth, len(r.Case))
+		}
+
+		fmt.Printf("%-*s  %-8s  %s\n", caseWidth, "CASE", "OUTCOME", "DETAIL")
+		for _, r := range results {
+			fmt.Printf("%-*s  %-8s  %s\n", caseWidth, r.Case, r.Outcome, r.Detail)
+		}
+	}
+
+	var parts []string
+	for _, o := range []fuzz.Outcome{fuzz.Accepted, fuzz.Rejected, fuzz.Failed} {
+		parts = append(parts, fmt.Sprintf("%s: %d", o, counts[o]))
+	}
+	fmt.Printf("\n%d case(s) (%s)\n", len(results), strings.Join(parts, ", "))
+}
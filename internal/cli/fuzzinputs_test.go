@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/fuzz"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// resetFuzzFlags resets the fuzz-inputs command flags after the test.
+func resetFuzzFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		fuzzMockFlag = false
+		fuzzJSONFlag = false
+	})
+}
+
+func TestFuzzInputsCmd_Flags(t *testing.T) {
+	for _, name := range []string{"init", "mock", "json"} {
+		if fuzzInputsCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected fuzz-inputs command to have --%s flag", name)
+		}
+	}
+}
+
+func TestRunFuzzCase_Plan(t *testing.T) {
+	resetFlags(t)
+	resetFuzzFlags(t)
+	cfg := config.DefaultConfig()
+	runner := terraform.NewRunner(cfg)
+	runner.DryRun = true
+	workDir := t.TempDir()
+
+	c := fuzz.Case{Name: "name=empty-string", Vars: map[string]any{"name": ""}}
+	var out bytes.Buffer
+	if err := runFuzzCase(runner, "/module", workDir, "case-001", c, nil, &out); err != nil {
+		t.Fatalf("runFuzzCase() error = %v", err)
+	}
+
+	varFile := filepath.Join(workDir, "case-001.tfvars.json")
+	want := "[dry-run] Would run terraform plan -no-color -input=false -lock=false -refresh=false -var-file=" + varFile + " in /module\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+	content, err := os.ReadFile(varFile)
+	if err != nil {
+		t.Fatalf("expected var file to be written: %v", err)
+	}
+	if !strings.Contains(string(content), `"name": ""`) {
+		t.Errorf("var file = %s, want name set to an empty string", content)
+	}
+}
+
+func TestRunFuzzCase_DryRunWritesNothing(t *testing.T) {
+	resetFlags(t)
+	resetFuzzFlags(t)
+	dryRunFlag = true
+	runner := terraform.NewRunner(config.DefaultConfig())
+	runner.DryRun = true
+	workDir := filepath.Join(t.TempDir(), ".motf-fuzz-dry-run")
+
+	c := fuzz.Case{Name: "name=empty-string", Vars: map[string]any{"name": ""}}
+	var out bytes.Buffer
+	if err := runFuzzCase(runner, "/module", workDir, "case-001", c, nil, &out); err != nil {
+		t.Fatalf("runFuzzCase() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "[dry-run] Would write "+filepath.Join(workDir, "case-001.tfvars.json")) {
+		t.Errorf("output = %q, want the var file to be shown", out.String())
+	}
+	if _, err := os.Stat(workDir); !os.IsNotExist(err) {
+		t.Errorf("expected %s not to be created in a dry run, got %v", workDir, err)
+	}
+}
+
+func TestRunFuzzCase_Mock(t *testing.T) {
+	resetFlags(t)
+	resetFuzzFlags(t)
+	fuzzMockFlag = true
+	cfg := config.DefaultConfig()
+	cfg.Binary = "tofu"
+	runner := terraform.NewRunner(cfg)
+	runner.DryRun = true
+	modulePath := t.TempDir()
+	workDir, err := os.MkdirTemp(modulePath, ".motf-fuzz-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testDir := filepath.Base(workDir)
+
+	c := fuzz.Case{Name: "size=negative", Vars: map[string]any{"size": -1}}
+	var out bytes.Buffer
+	if err := runFuzzCase(runner, modulePath, workDir, "case-002", c, []string{"azurerm"}, &out); err != nil {
+		t.Fatalf("runFuzzCase() error = %v", err)
+	}
+
+	want := "[dry-run] Would run tofu test -no-color -test-directory=" + testDir + " -filter=" + testDir + "/case-002.tftest.hcl in " + modulePath + "\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+	content, err := os.ReadFile(filepath.Join(workDir, "case-002.tftest.hcl"))
+	if err != nil {
+		t.Fatalf("expected test file to be written: %v", err)
+	}
+	for _, s := range []string{`mock_provider "azurerm" {}`, "size = -1"} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("test file missing %q:\n%s", s, content)
+		}
+	}
+}
+
+func TestFuzzInputsCmd_DryRunDoesNotExecute(t *testing.T) {
+	resetFlags(t)
+	resetFuzzFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "test-module"))
+	vars := `variable "name" {
+  type = string
+}
+`
+	if err := os.WriteFile(filepath.Join(modulePath, "variables.tf"), []byte(vars), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// With an empty PATH, terraform can't be found, so the run fails unless it is a dry run
+	t.Setenv("PATH", "")
+
+	rootCmd.SetArgs([]string{"fuzz-inputs", "--dry-run", "--path", modulePath})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("expected dry run to succeed without executing terraform, got: %v", err)
+	}
+
+	entries, err := os.ReadDir(modulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".motf-fuzz-") {
+			t.Errorf("expected no fuzz directory in a dry run, found %s", e.Name())
+		}
+	}
+}
+
+func TestFuzzInputsCmd_FailuresExitModuleFailed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as terraform")
+	}
+	resetFlags(t)
+	resetFuzzFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "test-module"))
+	vars := `variable "name" {
+  type = string
+}
+`
+	if err := os.WriteFile(filepath.Join(modulePath, "variables.tf"), []byte(vars), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stand-in terraform that accepts the baseline case and fails all others
+	script := "#!/bin/sh\ncase \"$*\" in *case-000*) exit 0 ;; esac\necho 'Error: boom'\nexit 1\n"
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "terraform"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write terraform: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rootCmd.SetArgs([]string{"fuzz-inputs", "--path", modulePath})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "got past input validation and failed") {
+		t.Fatalf("expected a failed cases error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+}
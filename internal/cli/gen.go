@@ -0,0 +1,348 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/scaffold"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	genResourceFlags  []string
+	genVarFlags       []string
+	genNameFlag       string
+	genOutputFlag     string
+	genStateFileFlag  string
+	genSchemaFileFlag string
+	genSyntaxFlag     string
+	genExampleFlag    string // Name of the example generated by gen example
+	genTestFlag       string // Name of the example the tests of gen test target
+)
+
+// Configuration syntaxes for gen --syntax
+const (
+	syntaxHCL  = "hcl"  // Native syntax, .tf files
+	syntaxJSON = "json" // JSON syntax, .tf.json files
+)
+
+// genCmd groups the code generation commands
+var genCmd = &cobra.Command{
+	Use:     "gen",
+	Aliases: []string{"generate"},
+	Short:   "Generate modules from existing infrastructure, and examples and tests of modules",
+}
+
+var genFromStateCmd = &cobra.Command{
+	Use:   "from-state <project>",
+	Short: "Scaffold a component from resources in a project's state",
+	Long: `Extract resources from a project's state and scaffold a parameterized component.
+
+The state is read with 'terraform show -json' and the provider schemas with
+'terraform providers schema -json' in the project directory, so the project must
+be initialized. Use --state-file and --schema-file to read saved JSON instead.
+
+All --resource addresses must be of the same type. Attributes that differ between
+the selected instances become variables; identical attributes are written as
+literals (use --var to parameterize them anyway). Sensitive attributes always become
+variables, computed-only attributes are skipped, and id/arn become outputs. The
+attributes of nested blocks are handled the same way, with variables named after
+their block, e.g. service_principal_client_secret.
+
+The component is written to components/<name> (override with --output), in
+native syntax or, with --syntax json, as .tf.json files.`,
+	Example: `  motf gen from-state platform --resource azurerm_storage_account.logs
+  motf gen from-state platform --resource 'aws_s3_bucket.this["a"]' --resource 'aws_s3_bucket.this["b"]'
+  motf gen from-state platform --resource aws_s3_bucket.this --var bucket --name s3-bucket
+  motf gen from-state platform --resource aws_s3_bucket.this --syntax json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGenFromState,
+}
+
+var genExampleCmd = &cobra.Command{
+	Use:   "example <module>",
+	Short: "Scaffold an example that calls a module",
+	Long: `Create examples/<name>/main.tf in a component, base, or project with a
+module block that calls it, setting each required variable to a placeholder
+of its type ("" for strings, [] for lists, and so on) to fill in.
+
+The module's terraform blocks are copied without their backend, and so are
+its provider blocks. Providers the module uses without a provider block get
+an empty one. The example directory must not exist yet, or be empty.`,
+	Example: `  motf gen example storage-account               # Create examples/basic
+  motf generate example storage-account --name complete
+  motf gen example storage-account --dry-run     # Print the example instead`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runGenExample,
+}
+
+var genTestCmd = &cobra.Command{
+	Use:   "test <module>",
+	Short: "Scaffold Terratest tests for a module",
+	Long: `Create tests/go.mod and tests/<module>_test.go in a component, base, or
+project, with a Terratest test that initializes and validates an example of
+the module, examples/basic unless --example is set. Under 'motf test
+--example' or --all-examples, the test targets the example in
+MOTF_EXAMPLE_DIR instead. 'motf test' runs go test in tests/ when it has its
+own go.mod.
+
+The files are rendered from go.mod.tmpl and test.go.tmpl in the test.templates
+directory of the config, if set, or else from built-in templates. Templates
+use Go template syntax with the fields .Module, .Path, .Example, .GoModule,
+and .Func. Existing files aren't overwritten. Run 'go mod tidy' in tests/
+afterwards to resolve the dependencies.`,
+	Example: `  motf gen test storage-account                    # Test examples/basic
+  motf generate test storage-account --example complete
+  motf gen test storage-account --dry-run          # Print the files instead`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runGenTest,
+}
+
+func init() {
+	genFromStateCmd.Flags().StringArrayVar(&genResourceFlags, "resource", nil, "Resource address to lift into the component (repeatable)")
+	genFromStateCmd.Flags().StringArrayVar(&genVarFlags, "var", nil, "Attribute to turn into a variable even if identical across resources (repeatable)")
+	genFromStateCmd.Flags().StringVar(&genNameFlag, "name", "", "Component name (default: resource type without provider prefix)")
+	genFromStateCmd.Flags().StringVarP(&genOutputFlag, "output", "o", "", "Directory to write the component to (default: components/<name>)")
+	genFromStateCmd.Flags().StringVar(&genStateFileFlag, "state-file", "", "Read state from a 'terraform show -json' file")
+	genFromStateCmd.Flags().StringVar(&genSchemaFileFlag, "schema-file", "", "Read provider schemas from a 'terraform providers schema -json' file")
+	genFromStateCmd.Flags().StringVar(&genSyntaxFlag, "syntax", syntaxHCL, "Configuration syntax of the generated files: hcl (.tf) or json (.tf.json)")
+	genExampleCmd.Flags().StringVar(&genExampleFlag, "name", "basic", "Name of the example, its directory in examples/")
+	genCmd.AddCommand(genFromStateCmd)
+	genTestCmd.Flags().StringVar(&genTestFlag, "example", "basic", "Name of the example the tests target, its directory in examples/")
+	genCmd.AddCommand(genExampleCmd)
+	genCmd.AddCommand(genTestCmd)
+	rootCmd.AddCommand(genCmd)
+}
+
+func runGenFromState(cmd *cobra.Command, args []string) error {
+	if len(genResourceFlags) == 0 {
+		return fmt.Errorf("at least one --resource is required")
+	}
+	if genSyntaxFlag != syntaxHCL && genSyntaxFlag != syntaxJSON {
+		return fmt.Errorf("invalid --syntax '%s': must be %s or %s", genSyntaxFlag, syntaxHCL, syntaxJSON)
+	}
+	if genNameFlag != "" && !isDirName(genNameFlag) {
+		return fmt.Errorf("invalid --name '%s': must be a directory name", genNameFlag)
+	}
+
+	projectPath, err := resolveTargetPath(args)
+	if err != nil {
+		return err
+	}
+
+	tfRunner, err := runnerFor(projectPath)
+	if err != nil {
+		return err
+	}
+
+	stateData, err := readJSONSource(genStateFileFlag, func() ([]byte, error) { return tfRunner.ShowJSON(projectPath) })
+	if err != nil {
+		return err
+	}
+	state, err := scaffold.ParseState(stateData)
+	if err != nil {
+		return err
+	}
+
+	var resources []scaffold.StateResource
+	for _, addr := range genResourceFlags {
+		matches := state.FindResources(addr)
+		if len(matches) == 0 {
+			return fmt.Errorf("resource '%s' not found in state", addr)
+		}
+		resources = append(resources, matches...)
+	}
+
+	schemaData, err := readJSONSource(genSchemaFileFlag, func() ([]byte, error) { return tfRunner.ProvidersSchemaJSON(projectPath) })
+	if err != nil {
+		return err
+	}
+	schemas, err := scaffold.ParseProviderSchemas(schemaData)
+	if err != nil {
+		return err
+	}
+
+	component, err := scaffold.FromState(scaffold.Options{
+		Resources: resources,
+		Schemas:   schemas,
+		Variables: genVarFlags,
+		JSON:      genSyntaxFlag == syntaxJSON,
+	})
+	if err != nil {
+		return err
+	}
+
+	name := genNameFlag
+	if name == "" {
+		name = componentNameForType(resources[0].Type)
+	}
+
+	outDir := genOutputFlag
+	if outDir == "" {
+		basePath, err := getBasePath()
+		if err != nil {
+			return err
+		}
+		// With several roots, components are generated in the first
+		outDir = filepath.Join(basePath, moduleRoots()[0], typeDir(TypeComponent, DirComponents), name)
+	}
+
+	if err := writeComponent(outDir, component.Files); err != nil {
+		return err
+	}
+
+	cmd.Printf("Generated component '%s' in %s from %d resource(s)\n", name, outDir, len(resources))
+	cmd.Printf("Variables: %s\n", valueOrDefault(strings.Join(component.Variables, ", "), "(none)"))
+	cmd.Printf("Outputs:   %s\n", valueOrDefault(strings.Join(component.Outputs, ", "), "(none)"))
+	return nil
+}
+
+func runGenExample(cmd *cobra.Command, args []string) error {
+	name := genExampleFlag
+	if !isDirName(name) {
+		return fmt.Errorf("invalid --name '%s': must be a directory name", name)
+	}
+	modulePath, err := resolveTargetPath(args)
+	if err != nil {
+		return err
+	}
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	schema, err := terraform.LoadModuleSchema(modulePath, basePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse module: %w", err)
+	}
+	exampleDir := filepath.Join(modulePath, DirExamples, name)
+	source, err := filepath.Rel(exampleDir, modulePath)
+	if err != nil {
+		return err
+	}
+	main, err := scaffold.Example(modulePath, filepath.ToSlash(source), schema)
+	if err != nil {
+		return err
+	}
+
+	if err := writeComponent(exampleDir, map[string][]byte{"main.tf": main}); err != nil {
+		return err
+	}
+	if !dryRunFlag {
+		cmd.Printf("Generated example '%s' in %s\n", name, displayPath(basePath, exampleDir))
+	}
+	return nil
+}
+
+func runGenTest(cmd *cobra.Command, args []string) error {
+	example := genTestFlag
+	if !isDirName(example) {
+		return fmt.Errorf("invalid --example '%s': must be a directory name", example)
+	}
+	modulePath, err := resolveTargetPath(args)
+	if err != nil {
+		return err
+	}
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	data := scaffold.NewTerratestData(displayPath(basePath, modulePath), example)
+	files, err := scaffold.Terratest(data, cfg.Test.GetTemplates())
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(modulePath, DirExamples, example)); os.IsNotExist(err) {
+		logging.Warnf("module %s has no example '%s', create it with 'motf gen example %s --name %s'", data.Path, example, data.Module, example)
+	}
+
+	testsDir := filepath.Join(modulePath, DirTests)
+	if err := writeFiles(testsDir, files); err != nil {
+		return err
+	}
+	if !dryRunFlag {
+		cmd.Printf("Generated Terratest tests in %s\n", displayPath(basePath, testsDir))
+		cmd.Printf("Run 'go mod tidy' in %s to resolve their dependencies\n", displayPath(basePath, testsDir))
+	}
+	return nil
+}
+
+// isDirName reports whether name is a single directory name, not a path.
+func isDirName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// readJSONSource reads a file if path is set, otherwise calls fallback.
+func readJSONSource(path string, fallback func() ([]byte, error)) ([]byte, error) {
+	if path == "" {
+		return fallback()
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// componentNameForType derives a component name from a resource type,
+// e.g. "azurerm_storage_account" -> "storage-account".
+func componentNameForType(resourceType string) string {
+	if i := strings.Index(resourceType, "_"); i >= 0 {
+		resourceType = resourceType[i+1:]
+	}
+	return strings.ReplaceAll(resourceType, "_", "-")
+}
+
+// writeComponent writes the generated files to dir, which must not already contain files.
+// In dry-run mode, the files are printed instead.
+func writeComponent(dir string, files map[string][]byte) error {
+	if !dryRunFlag {
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+			return fmt.Errorf("output directory %s already exists and is not empty", dir)
+		}
+	}
+	return writeFiles(dir, files)
+}
+
+// writeFiles writes the generated files to dir, creating it if needed, but
+// fails before writing anything if one of them already exists. In dry-run
+// mode, the files are printed instead.
+func writeFiles(dir string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if dryRunFlag {
+		for _, name := range names {
+			fmt.Printf("[dry-run] Would write %s:\n%s\n", filepath.Join(dir, name), files[name])
+		}
+		return nil
+	}
+
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return fmt.Errorf("%s already exists", filepath.Join(dir, name))
+		}
+	}
+	// The files are part of the repository, so they get its usual permissions
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), files[name], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/scaffold"
+)
+
+const genTestState = `{
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "azurerm_resource_group.main",
+          "mode": "managed",
+          "type": "azurerm_resource_group",
+          "name": "main",
+          "provider_name": "registry.terraform.io/hashicorp/azurerm",
+          "values": {"id": "/subscriptions/x/resourceGroups/rg-main", "name": "rg-main", "location": "westeurope"}
+        }
+      ]
+    }
+  }
+}`
+
+const genTestSchemas = `{
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/azurerm": {
+      "resource_schemas": {
+        "azurerm_resource_group": {
+          "block": {
+            "attributes": {
+              "id": {"type": "string", "computed": true},
+              "name": {"type": "string", "required": true},
+              "location": {"type": "string", "required": true}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// resetGenFlags resets the gen command flags after the test.
+func resetGenFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		genResourceFlags = nil
+		genVarFlags = nil
+		genNameFlag = ""
+		genOutputFlag = ""
+		genStateFileFlag = ""
+		genSchemaFileFlag = ""
+		genSyntaxFlag = syntaxHCL
+		genExampleFlag = "basic"
+		genTestFlag = "basic"
+	})
+}
+
+func TestGenFromStateCmd_Flags(t *testing.T) {
+	for _, name := range []string{"resource", "var", "name", "output", "state-file", "schema-file", "syntax"} {
+		if genFromStateCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected --%s flag to be registered", name)
+		}
+	}
+}
+
+func TestComponentNameForType(t *testing.T) {
+	tests := map[string]string{
+		"azurerm_storage_account": "storage-account",
+		"aws_s3_bucket":           "s3-bucket",
+		"random":                  "random",
+	}
+	for input, want := range tests {
+		if got := componentNameForType(input); got != want {
+			t.Errorf("componentNameForType(%s) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestRunGenFromState_WritesComponent(t *testing.T) {
+	resetFlags(t)
+	resetGenFlags(t)
+
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Test: &config.TestConfig{Engine: "terratest"}})
+	projectPath := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+
+	stateFile := filepath.Join(tmpDir, "state.json")
+	schemaFile := filepath.Join(tmpDir, "schema.json")
+	if err := os.WriteFile(stateFile, []byte(genTestState), 0644); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+	if err := os.WriteFile(schemaFile, []byte(genTestSchemas), 0644); err != nil {
+		t.Fatalf("failed to write schema: %v", err)
+	}
+
+	pathFlag = projectPath
+	genResourceFlags = []string{"azurerm_resource_group.main"}
+	genVarFlags = []string{"name"}
+	genStateFileFlag = stateFile
+	genSchemaFileFlag = schemaFile
+
+	if err := runGenFromState(genFromStateCmd, nil); err != nil {
+		t.Fatalf("runGenFromState returned error: %v", err)
+	}
+
+	outDir := filepath.Join(tmpDir, DirComponents, "resource-group")
+	main, err := os.ReadFile(filepath.Join(outDir, "main.tf"))
+	if err != nil {
+		t.Fatalf("expected main.tf to be written: %v", err)
+	}
+	if !strings.Contains(string(main), "name     = var.name") || !strings.Contains(string(main), `location = "westeurope"`) {
+		t.Errorf("unexpected main.tf:\n%s", main)
+	}
+	for _, name := range []string{"variables.tf", "outputs.tf", "versions.tf"} {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			t.Errorf("expected %s to be written: %v", name, err)
+		}
+	}
+
+	// Running again must not overwrite the component
+	if err := runGenFromState(genFromStateCmd, nil); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("expected an error for an existing component, got %v", err)
+	}
+}
+
+func TestRunGenFromState_JSONSyntax(t *testing.T) {
+	resetFlags(t)
+	resetGenFlags(t)
+
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Test: &config.TestConfig{Engine: "terratest"}})
+	projectPath := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+
+	stateFile := filepath.Join(tmpDir, "state.json")
+	schemaFile := filepath.Join(tmpDir, "schema.json")
+	if err := os.WriteFile(stateFile, []byte(genTestState), 0644); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+	if err := os.WriteFile(schemaFile, []byte(genTestSchemas), 0644); err != nil {
+		t.Fatalf("failed to write schema: %v", err)
+	}
+
+	pathFlag = projectPath
+	genResourceFlags = []string{"azurerm_resource_group.main"}
+	genStateFileFlag = stateFile
+	genSchemaFileFlag = schemaFile
+	genSyntaxFlag = "yaml"
+
+	if err := runGenFromState(genFromStateCmd, nil); err == nil || !strings.Contains(err.Error(), "invalid --syntax") {
+		t.Fatalf("expected an error for an invalid syntax, got %v", err)
+	}
+
+	genSyntaxFlag = syntaxJSON
+	if err := runGenFromState(genFromStateCmd, nil); err != nil {
+		t.Fatalf("runGenFromState returned error: %v", err)
+	}
+
+	outDir := filepath.Join(tmpDir, DirComponents, "resource-group")
+	for _, name := range []string{"main.tf.json", "variables.tf.json", "outputs.tf.json", "versions.tf.json"} {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			t.Errorf("expected %s to be written: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "main.tf")); err == nil {
+		t.Error("expected no main.tf with --syntax json")
+	}
+}
+
+func TestRunGenFromState_InvalidName(t *testing.T) {
+	resetFlags(t)
+	resetGenFlags(t)
+
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	pathFlag = createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+	genResourceFlags = []string{"azurerm_resource_group.main"}
+
+	for _, name := range []string{"../../escape", "nested/name", ".."} {
+		genNameFlag = name
+		if err := runGenFromState(genFromStateCmd, nil); err == nil || !strings.Contains(err.Error(), "invalid --name") {
+			t.Errorf("expected an invalid --name error for %q, got %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "..", "escape")); err == nil {
+		t.Error("expected nothing to be written outside the components directory")
+	}
+}
+
+func TestRunGenFromState_ResourceNotFound(t *testing.T) {
+	resetFlags(t)
+	resetGenFlags(t)
+
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Test: &config.TestConfig{Engine: "terratest"}})
+	pathFlag = createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+
+	stateFile := filepath.Join(tmpDir, "state.json")
+	if err := os.WriteFile(stateFile, []byte(genTestState), 0644); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+	genResourceFlags = []string{"azurerm_resource_group.other"}
+	genStateFileFlag = stateFile
+
+	err := runGenFromState(genFromStateCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "not found in state") {
+		t.Errorf("expected resource not found error, got %v", err)
+	}
+}
+
+func TestRunGenExample(t *testing.T) {
+	resetFlags(t)
+	resetGenFlags(t)
+
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir})
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "network"))
+	if err := os.WriteFile(filepath.Join(modulePath, "variables.tf"), []byte("variable \"address_space\" {\n  type = list(string)\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runGenExample(genExampleCmd, []string{"network"}); err != nil {
+		t.Fatalf("runGenExample returned error: %v", err)
+	}
+	main, err := os.ReadFile(filepath.Join(modulePath, DirExamples, "basic", "main.tf"))
+	if err != nil {
+		t.Fatalf("expected main.tf to be written: %v", err)
+	}
+	want := "module \"network\" {\n  source = \"../..\"\n\n  address_space = []\n}\n"
+	if string(main) != want {
+		t.Errorf("main.tf = %q, want %q", main, want)
+	}
+
+	// Running again must not overwrite the example
+	if err := runGenExample(genExampleCmd, []string{"network"}); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("expected an error for an existing example, got %v", err)
+	}
+
+	genExampleFlag = "../escape"
+	if err := runGenExample(genExampleCmd, []string{"network"}); err == nil || !strings.Contains(err.Error(), "invalid --name") {
+		t.Errorf("expected an invalid name error, got %v", err)
+	}
+}
+
+func TestRunGenTest(t *testing.T) {
+	resetFlags(t)
+	resetGenFlags(t)
+
+	tmpDir := t.TempDir()
+	templates := filepath.Join(tmpDir, ".motf", "templates")
+	if err := os.MkdirAll(templates, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templates, scaffold.GoModTemplateFile), []byte("module example.com/{{.GoModule}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	withConfig(t, &config.Config{Root: tmpDir, Test: &config.TestConfig{Templates: templates}})
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage-account"))
+	// Native tests already in tests/ are left alone
+	testsDir := filepath.Join(modulePath, DirTests)
+	if err := os.MkdirAll(testsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(testsDir, "main.tftest.hcl"), []byte("run \"plan\" {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runGenTest(genTestCmd, []string{"storage-account"}); err != nil {
+		t.Fatalf("runGenTest returned error: %v", err)
+	}
+	goMod, err := os.ReadFile(filepath.Join(testsDir, "go.mod"))
+	if err != nil {
+		t.Fatalf("expected go.mod to be written: %v", err)
+	}
+	if want := "module example.com/components/storage-account/tests\n"; string(goMod) != want {
+		t.Errorf("go.mod = %q, want %q", goMod, want)
+	}
+	test, err := os.ReadFile(filepath.Join(testsDir, "storage_account_test.go"))
+	if err != nil {
+		t.Fatalf("expected storage_account_test.go to be written: %v", err)
+	}
+	if !strings.Contains(string(test), "func TestStorageAccount(t *testing.T) {") {
+		t.Errorf("unexpected test file:\n%s", test)
+	}
+
+	// Running again must not overwrite the tests
+	if err := runGenTest(genTestCmd, []string{"storage-account"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an error for existing tests, got %v", err)
+	}
+
+	genTestFlag = ".."
+	if err := runGenTest(genTestCmd, []string{"storage-account"}); err == nil || !strings.Contains(err.Error(), "invalid --example") {
+		t.Errorf("expected an invalid example error, got %v", err)
+	}
+}
@@ -28,8 +28,9 @@ Examples:
   motf get storage-account      # Get details for storage-account
   motf get --path ./my-module   # Get details for module at explicit path
   motf get storage-account --json  # Output as JSON`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runGet,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runGet,
 }
 
 func init() {
@@ -199,13 +200,14 @@ func listTestFiles(path, basePath string) []ItemInfo {
 
 // printModuleDetails outputs the module details in a formatted way
 func printModuleDetails(details *ModuleDetails) {
-	fmt.Printf("Name:                  %s\n", details.Name)
-	fmt.Printf("Type:                  %s\n", formatType(details.Type))
-	fmt.Printf("Path:                  %s\n", details.Path)
-	fmt.Printf("Spacelift Version:     %s\n", details.SpaceliftVersion)
-	fmt.Printf("Has Submodules:        %s\n", formatBool(details.HasSubmodules))
-	fmt.Printf("Has Tests:             %s\n", formatBool(details.HasTests))
-	fmt.Printf("Has Examples:          %s\n", formatBool(details.HasExamples))
+	const labelWidth = 22
+	printField("Name", labelWidth, details.Name)
+	printField("Type", labelWidth, formatType(details.Type))
+	printField("Path", labelWidth, details.Path)
+	printField("Spacelift Version", labelWidth, details.SpaceliftVersion)
+	printField("Has Submodules", labelWidth, formatBool(details.HasSubmodules))
+	printField("Has Tests", labelWidth, formatBool(details.HasTests))
+	printField("Has Examples", labelWidth, formatBool(details.HasExamples))
 
 	if len(details.Submodules) > 0 {
 		fmt.Println("\nSubmodules:")
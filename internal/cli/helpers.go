@@ -1,13 +1,19 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"slices"
 	"strings"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/i18n"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
 )
 
 // readBuildInfo is a variable for testing; defaults to debug.ReadBuildInfo
@@ -70,6 +76,25 @@ func getRoot() string {
 	return cfg.Root
 }
 
+// moduleConfig returns the effective config for a module directory: the root
+// config merged with the module's .motf.module.yml, if any.
+func moduleConfig(modulePath string) (*config.Config, error) {
+	return cfg.ForModule(modulePath)
+}
+
+// runnerFor returns a terraform runner using the effective config for a module directory.
+func runnerFor(modulePath string) (*terraform.Runner, error) {
+	modCfg, err := moduleConfig(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	r := terraform.NewRunner(modCfg)
+	r.DryRun = dryRunFlag
+	r.Hooks = hookRunner(modCfg)
+	r.Context = moduleContext(modulePath)
+	return r, nil
+}
+
 // getBasePath returns the base path for module discovery based on cfg.Root
 func getBasePath() (string, error) {
 	wd, err := os.Getwd()
@@ -88,35 +113,59 @@ func getBasePath() (string, error) {
 	return filepath.Join(wd, cfg.Root), nil
 }
 
-// getModuleType determines the module type based on its path
+// getModuleType determines the module type based on its path: the type given
+// by the types section, or else that of its module directory
 func getModuleType(path string) string {
-	switch {
-	case strings.Contains(path, "/"+DirComponents+"/") || strings.Contains(path, "\\"+DirComponents+"\\"):
-		return TypeComponent
-	case strings.Contains(path, "/"+DirBases+"/") || strings.Contains(path, "\\"+DirBases+"\\"):
-		return TypeBase
-	case strings.Contains(path, "/"+DirProjects+"/") || strings.Contains(path, "\\"+DirProjects+"\\"):
-		return TypeProject
-	default:
-		return ""
+	if cfg != nil && len(cfg.Types) > 0 {
+		if basePath, err := getBasePath(); err == nil {
+			if rel, err := filepath.Rel(basePath, path); err == nil && !strings.HasPrefix(rel, "..") {
+				return moduleTypeOf(rel, moduleDirType(path))
+			}
+		}
+	}
+	return moduleDirType(path)
+}
+
+// moduleDirType returns the type of the first module directory path is in,
+// regardless of the types section
+func moduleDirType(path string) string {
+	path = strings.ReplaceAll(path, "\\", "/")
+	for _, d := range moduleDirs() {
+		if strings.Contains(path, "/"+d.Dir+"/") {
+			return d.Type
+		}
 	}
+	return ""
 }
 
 // resolveTargetPath resolves the target path based on args and flags
 func resolveTargetPath(args []string) (string, error) {
 	// Check if both module name and --path are specified
 	if len(args) > 0 && pathFlag != "" {
-		return "", fmt.Errorf("--path is mutually exclusive with module name argument")
+		return "", errors.New(i18n.T(i18n.MsgErrPathWithName))
 	}
 
 	// Check if neither module name nor --path is specified
 	if len(args) == 0 && pathFlag == "" {
-		return "", fmt.Errorf("must specify either a module name or --path")
+		return "", i18n.Errorf(i18n.T(i18n.MsgErrNoTarget), "", i18n.T(i18n.MsgHintNoTarget))
 	}
 
-	// If explicit path is provided, use it directly
+	// If explicit path is provided, use it directly, or the module it's an
+	// example or test of
 	if pathFlag != "" {
-		return resolveExplicitPath(pathFlag)
+		absPath, err := resolveExplicitPath(pathFlag)
+		if err != nil || allowNonModuleFlag {
+			return absPath, err
+		}
+		if modulePath, dir := nonModuleParent(absPath); modulePath != "" {
+			module := modulePath
+			if basePath, err := getBasePath(); err == nil {
+				module = displayPath(basePath, modulePath)
+			}
+			logging.Infof("%s", i18n.T(i18n.MsgNoteNonModuleRedirect, pathFlag, dir, module))
+			return modulePath, nil
+		}
+		return absPath, nil
 	}
 
 	// Use the module name from args
@@ -136,22 +185,103 @@ func resolveExplicitPath(path string) (string, error) {
 
 	// Check if path exists
 	if _, err := os.Stat(absPath); os.IsNotExist(err) {
-		return "", fmt.Errorf("path does not exist: %s", path)
+		return "", &i18n.Error{Problem: i18n.T(i18n.MsgErrPathNotExist, path), Err: errModuleNotFound}
 	}
 
 	return absPath, nil
 }
 
-// findModuleInAllDirs searches for a module across all three directories (components, bases, projects)
+// nonModuleParent returns the module whose examples or tests directory
+// contains path, and the name of that directory, or "" if path isn't in one.
+// Running in an example by accident, e.g. a plan with its default values,
+// could create real resources.
+func nonModuleParent(path string) (modulePath, dir string) {
+	for d := path; filepath.Dir(d) != d; d = filepath.Dir(d) {
+		name, parent := filepath.Base(d), filepath.Dir(d)
+		if (name == DirExamples || name == DirTests) && finder.HasTerraformFiles(parent) {
+			return parent, name
+		}
+	}
+	return "", ""
+}
+
+// findModuleInAllDirs searches for a module across all module directories (components, bases, projects by default).
+// moduleName may be qualified, e.g. azurerm/storage-account or components:storage-account.
 func findModuleInAllDirs(moduleName string) (string, error) {
 	basePath, err := getBasePath()
 	if err != nil {
 		return "", err
 	}
 
+	ref, err := parseModuleRef(moduleName)
+	if err != nil {
+		return "", err
+	}
+	allMatches, err := findModuleMatches(basePath, ref)
+	if err != nil {
+		return "", err
+	}
+
+	// Modules outside managed_paths can only be targeted with --path
+	managedMatches := slices.DeleteFunc(slices.Clone(allMatches), func(match string) bool {
+		return !isManagedDir(basePath, match)
+	})
+	if len(managedMatches) == 0 && len(allMatches) > 0 {
+		return "", moduleNotFound(i18n.Errorf(
+			i18n.T(i18n.MsgErrModuleUnmanaged, moduleName),
+			i18n.T(i18n.MsgCauseModuleUnmanaged, strings.Join(cfg.ManagedPaths, ", ")),
+			i18n.T(i18n.MsgHintModuleUnmanaged),
+		))
+	}
+	allMatches = managedMatches
+
+	if len(allMatches) == 0 {
+		return "", moduleNotFound(i18n.Errorf(
+			i18n.T(i18n.MsgErrModuleNotFound, moduleName, strings.Join(moduleDirNames(), ", ")),
+			i18n.T(i18n.MsgCauseModuleNotFound, basePath),
+			i18n.T(i18n.MsgHintModuleNotFound),
+		))
+	}
+
+	if len(allMatches) > 1 {
+		// Name clash detected, list the qualified name of each module
+		relPaths := make([]string, len(allMatches))
+		for i, match := range allMatches {
+			relPaths[i] = displayPath(basePath, match)
+		}
+		var paths string
+		for i, name := range qualifiedNames(relPaths) {
+			paths += fmt.Sprintf("\n  %d. %s (%s)", i+1, name, allMatches[i])
+		}
+		return "", i18n.Errorf(i18n.T(i18n.MsgErrNameClash, moduleName), paths, i18n.T(i18n.MsgHintNameClash))
+	}
+
+	return allMatches[0], nil
+}
+
+// findModuleMatches returns the paths of the modules ref refers to in all
+// module directories, from the --index file when set
+func findModuleMatches(basePath string, ref moduleRef) ([]string, error) {
 	var allMatches []string
 
-	for _, moduleDir := range ModuleDirs {
+	if indexFlag != "" {
+		modules, err := readModuleIndex()
+		if err != nil {
+			return nil, err
+		}
+		for _, mod := range modules {
+			if mod.Name == ref.Name && ref.matches(filepath.ToSlash(mod.Path)) {
+				allMatches = append(allMatches, filepath.Join(basePath, mod.Path))
+			}
+		}
+		return allMatches, nil
+	}
+
+	for _, d := range searchDirs() {
+		moduleDir := d.Dir
+		if ref.Dir != "" && filepath.Base(moduleDir) != ref.Dir {
+			continue
+		}
 		searchPath := filepath.Join(basePath, moduleDir)
 
 		// Skip if directory doesn't exist
@@ -160,28 +290,18 @@ func findModuleInAllDirs(moduleName string) (string, error) {
 		}
 
 		// Find the module
-		matches, err := finder.FindModule(searchPath, moduleName)
+		matches, err := finder.FindModule(searchPath, ref.Name)
 		if err != nil {
-			return "", fmt.Errorf("failed to search for module in %s: %w", moduleDir, err)
+			return nil, fmt.Errorf("failed to search for module in %s: %w", moduleDir, err)
 		}
 
-		allMatches = append(allMatches, matches...)
-	}
-
-	if len(allMatches) == 0 {
-		return "", fmt.Errorf("module '%s' not found in components, bases, or projects", moduleName)
-	}
-
-	if len(allMatches) > 1 {
-		// Name clash detected across multiple directories
-		var paths string
-		for i, match := range allMatches {
-			paths += fmt.Sprintf("\n  %d. %s", i+1, match)
+		for _, match := range matches {
+			if ref.matches(displayPath(basePath, match)) {
+				allMatches = append(allMatches, match)
+			}
 		}
-		return "", fmt.Errorf("multiple modules named '%s' found - name clash detected:%s\n\nPlease use --path to specify the exact path", moduleName, paths)
 	}
-
-	return allMatches[0], nil
+	return allMatches, nil
 }
 
 // resolveTargetWithExample resolves the target path, optionally switching to an example directory
@@ -201,12 +321,16 @@ func resolveTargetWithExample(args []string, exampleName string) (string, error)
 
 	// Check if the example directory exists
 	if _, err := os.Stat(examplePath); os.IsNotExist(err) {
-		return "", fmt.Errorf("example '%s' not found in %s/%s", exampleName, modulePath, DirExamples)
+		return "", moduleNotFound(i18n.Errorf(
+			i18n.T(i18n.MsgErrExampleNotFound, exampleName, filepath.Join(modulePath, DirExamples)),
+			"",
+			i18n.T(i18n.MsgHintExampleNotFound),
+		))
 	}
 
 	// Check if it contains any .tf file (valid terraform module)
 	if !finder.HasTerraformFiles(examplePath) {
-		return "", fmt.Errorf("example '%s' is not a valid terraform module (no .tf files found)", exampleName)
+		return "", i18n.Errorf(i18n.T(i18n.MsgErrExampleNotTerraform, exampleName), i18n.T(i18n.MsgCauseNoTerraformFiles), "")
 	}
 
 	return examplePath, nil
@@ -3,6 +3,7 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
@@ -79,6 +80,42 @@ func TestGetModuleType_Component(t *testing.T) {
 	}
 }
 
+func TestGetModuleType_CustomModuleDirs(t *testing.T) {
+	withConfig(t, &config.Config{ModuleDirs: config.ModuleDirs{{Dir: "modules", Type: "component"}, {Dir: "stacks", Type: "stack"}}})
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/repo/modules/storage", TypeComponent},
+		{"C:\\repo\\stacks\\prod", "stack"},
+		{"/repo/components/storage", ""},
+	}
+
+	for _, tt := range tests {
+		if result := getModuleType(tt.path); result != tt.expected {
+			t.Errorf("getModuleType(%s) = '%s', expected '%s'", tt.path, result, tt.expected)
+		}
+	}
+}
+
+func TestGetModuleType_TypeRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Types: config.TypeRules{{Pattern: "components/azurerm/**", Type: "azure"}}})
+	withWorkingDir(t, tmpDir)
+
+	if got := getModuleType(filepath.Join(tmpDir, DirComponents, "azurerm", "storage")); got != "azure" {
+		t.Errorf("getModuleType() = '%s', expected 'azure'", got)
+	}
+	if got := getModuleType(filepath.Join(tmpDir, DirComponents, "aws", "s3")); got != TypeComponent {
+		t.Errorf("getModuleType() = '%s', expected '%s'", got, TypeComponent)
+	}
+	// The directory type is kept for commands that depend on the layout
+	if got := moduleDirType(filepath.Join(tmpDir, DirComponents, "azurerm", "storage")); got != TypeComponent {
+		t.Errorf("moduleDirType() = '%s', expected '%s'", got, TypeComponent)
+	}
+}
+
 // Tests for resolveExplicitPath
 
 func TestResolveExplicitPath_AbsolutePath(t *testing.T) {
@@ -148,6 +185,63 @@ func TestResolveTargetPath_WithExplicitPath(t *testing.T) {
 	}
 }
 
+func TestResolveTargetPath_ExamplePathRedirectsToModule(t *testing.T) {
+	tmpDir := t.TempDir()
+	resetFlags(t)
+	withConfig(t, &config.Config{Binary: "terraform"})
+	withWorkingDir(t, tmpDir)
+
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	examplePath := createTerraformModule(t, modulePath, filepath.Join(DirExamples, "basic"))
+	setupPath := createTerraformModule(t, modulePath, filepath.Join(DirTests, "setup"))
+
+	for _, path := range []string{examplePath, filepath.Join(modulePath, DirExamples), setupPath} {
+		pathFlag = path
+		result, err := resolveTargetPath(nil)
+		if err != nil {
+			t.Fatalf("resolveTargetPath(%s) error = %v", path, err)
+		}
+		if result != modulePath {
+			t.Errorf("resolveTargetPath(%s) = %s, want the module %s", path, result, modulePath)
+		}
+	}
+
+	// --allow-non-module runs in the directory itself
+	allowNonModuleFlag = true
+	pathFlag = examplePath
+	result, err := resolveTargetPath(nil)
+	if err != nil {
+		t.Fatalf("resolveTargetPath() error = %v", err)
+	}
+	if result != examplePath {
+		t.Errorf("resolveTargetPath() with --allow-non-module = %s, want %s", result, examplePath)
+	}
+}
+
+func TestNonModuleParent(t *testing.T) {
+	tmpDir := t.TempDir()
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	examplePath := createTerraformModule(t, modulePath, filepath.Join(DirExamples, "basic"))
+	// An examples directory that isn't in a module isn't redirected
+	looseExample := createTerraformModule(t, tmpDir, filepath.Join(DirExamples, "demo"))
+
+	tests := []struct {
+		path   string
+		module string
+		dir    string
+	}{
+		{examplePath, modulePath, DirExamples},
+		{modulePath, "", ""},
+		{looseExample, "", ""},
+	}
+	for _, tt := range tests {
+		module, dir := nonModuleParent(tt.path)
+		if module != tt.module || dir != tt.dir {
+			t.Errorf("nonModuleParent(%s) = %q, %q, want %q, %q", tt.path, module, dir, tt.module, tt.dir)
+		}
+	}
+}
+
 // Tests for findModuleInAllDirs
 
 func TestFindModuleInAllDirs_ComponentFound(t *testing.T) {
@@ -256,7 +350,94 @@ func TestFindModuleInAllDirs_NameClash(t *testing.T) {
 
 	_, err := findModuleInAllDirs("storage-account")
 	if err == nil {
-		t.Error("expected error for name clash")
+		t.Fatal("expected error for name clash")
+	}
+	for _, want := range []string{"1. components:storage-account (", "2. bases:storage-account ("} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected name clash error to contain %q, got:\n%v", want, err)
+		}
+	}
+}
+
+func TestFindModuleInAllDirs_QualifiedName(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: "", Binary: "terraform"})
+	withWorkingDir(t, tmpDir)
+
+	componentPath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "storage-account"))
+	basePath := createTerraformModule(t, tmpDir, filepath.Join(DirBases, "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "aws", "storage-account"))
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"azurerm/storage-account", componentPath},
+		{"components/azurerm/storage-account", componentPath},
+		{"components:azurerm/storage-account", componentPath},
+		{"bases:storage-account", basePath},
+		{"base:storage-account", basePath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := findModuleInAllDirs(tt.ref)
+			if err != nil {
+				t.Fatalf("findModuleInAllDirs() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("findModuleInAllDirs() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// Still ambiguous: two components named storage-account
+	if _, err := findModuleInAllDirs("components:storage-account"); err == nil {
+		t.Error("expected name clash error for components:storage-account")
+	}
+	if _, err := findModuleInAllDirs("gcp/storage-account"); err == nil {
+		t.Error("expected not found error for gcp/storage-account")
+	}
+}
+
+func TestFindModuleInAllDirs_Roots(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Roots: []string{"iac/platform", "iac/apps"}})
+	withWorkingDir(t, tmpDir)
+
+	platformPath := createTerraformModule(t, tmpDir, filepath.Join("iac", "platform", DirComponents, "storage"))
+	appsPath := createTerraformModule(t, tmpDir, filepath.Join("iac", "apps", DirComponents, "storage"))
+	networkPath := createTerraformModule(t, tmpDir, filepath.Join("iac", "apps", DirBases, "network"))
+
+	// Names clashing between roots are qualified with their root
+	_, err := findModuleInAllDirs("storage")
+	if err == nil {
+		t.Fatal("expected error for name clash between roots")
+	}
+	for _, want := range []string{"1. platform/components/storage (", "2. apps/components/storage ("} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected name clash error to contain %q, got:\n%v", want, err)
+		}
+	}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"platform/components/storage", platformPath},
+		{"iac/apps/components/storage", appsPath},
+		{"network", networkPath},
+		{"bases:network", networkPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := findModuleInAllDirs(tt.ref)
+			if err != nil {
+				t.Fatalf("findModuleInAllDirs() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("findModuleInAllDirs() = %s, want %s", got, tt.want)
+			}
+		})
 	}
 }
 
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+// importLogLevel is the TF_LOG level of motf import --verbose
+const importLogLevel = "INFO"
+
+// importEnvFlag is the workspace to import into, set as TF_WORKSPACE
+var importEnvFlag string
+
+var importCmd = &cobra.Command{
+	Use:   "import <module-name> <address> <id>",
+	Short: "Import an existing resource into the state of a module",
+	Long: `Run terraform/tofu import in a module without changing into its directory.
+The module is found by name like in other commands, or given with --path, in
+which case the module name is left out of the arguments.
+
+--env selects the workspace by setting TF_WORKSPACE, and -i runs init first.
+With the global --verbose flag, import also prints the resolved module and
+binary, and sets TF_LOG to INFO for init and import unless TF_LOG is already
+set. Arguments passed with
+--args/-a come before the address and ID.`,
+	Example: `  motf import my-project azurerm_resource_group.this /subscriptions/.../resourceGroups/rg-platform
+  motf import my-project 'module.storage.azurerm_storage_account.this["logs"]' /subscriptions/.../stlogs -i --env prod
+  motf import --path ./projects/platform azurerm_resource_group.this /subscriptions/.../rg-platform
+  motf import my-project aws_s3_bucket.logs my-logs -a -var-file=prod.tfvars --verbose`,
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runImport,
+}
+
+func init() {
+	importCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the import")
+	importCmd.Flags().StringVar(&importEnvFlag, "env", "", "Workspace to import into, set as TF_WORKSPACE")
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	targetPath, rest, err := stateTarget(args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 2 {
+		return fmt.Errorf("import takes an address and an ID, got %d argument(s)", len(rest))
+	}
+
+	env := workspaceEnv(importEnvFlag)
+	if verboseFlag && os.Getenv("TF_LOG") == "" {
+		if env == nil {
+			env = map[string]string{}
+		}
+		env["TF_LOG"] = importLogLevel
+	}
+	modCfg, err := moduleConfigWithEnv(targetPath, env)
+	if err != nil {
+		return err
+	}
+	tfRunner := terraform.NewRunner(modCfg)
+	tfRunner.DryRun = dryRunFlag
+	tfRunner.Hooks = hookRunner(modCfg)
+	tfRunner.Context = runContext
+
+	if verboseFlag {
+		module := targetPath
+		if basePath, err := getBasePath(); err == nil {
+			module = displayPath(basePath, targetPath)
+		}
+		fmt.Printf("Module:    %s\n", module)
+		fmt.Printf("Binary:    %s\n", modCfg.Binary)
+	}
+	if importEnvFlag != "" {
+		fmt.Printf("Workspace: %s\n", importEnvFlag)
+	}
+
+	if initFlag {
+		if err := tfRunner.RunInit(targetPath); err != nil {
+			return err
+		}
+	}
+	return tfRunner.RunImport(targetPath, append(append([]string{}, argsFlag...), rest...)...)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func resetImportFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		importEnvFlag = ""
+	})
+}
+
+func TestImportCmd_Flags(t *testing.T) {
+	for _, name := range []string{"init", "env"} {
+		if importCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected import command to have --%s flag", name)
+		}
+	}
+}
+
+// TestImportCmd_GlobalVerbose verifies that -v is the global --verbose flag,
+// not one of import's own
+func TestImportCmd_GlobalVerbose(t *testing.T) {
+	if importCmd.LocalNonPersistentFlags().Lookup("verbose") != nil {
+		t.Error("import must not shadow the global --verbose flag")
+	}
+	if flag := importCmd.Flags().ShorthandLookup("v"); flag == nil || flag != rootCmd.PersistentFlags().Lookup("verbose") {
+		t.Error("expected -v of import to be the global --verbose flag")
+	}
+}
+
+func TestImport_Arguments(t *testing.T) {
+	resetFlags(t)
+	resetImportFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	projectPath := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+
+	err := runImport(importCmd, []string{"platform", "azurerm_resource_group.this"})
+	if err == nil || !strings.Contains(err.Error(), "an address and an ID") {
+		t.Errorf("expected missing ID error, got %v", err)
+	}
+
+	// With --path, the module name is left out
+	pathFlag = projectPath
+	err = runImport(importCmd, []string{"platform", "azurerm_resource_group.this", "/subscriptions/x"})
+	if err == nil || !strings.Contains(err.Error(), "got 3 argument(s)") {
+		t.Errorf("expected too many arguments error with --path, got %v", err)
+	}
+}
+
+func TestImport_DryRun(t *testing.T) {
+	resetFlags(t)
+	resetImportFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+	dryRunFlag = true
+	initFlag = true
+	importEnvFlag = "prod"
+	verboseFlag = true
+	t.Setenv("TF_LOG", "")
+
+	if err := runImport(importCmd, []string{"platform", "azurerm_resource_group.this", "/subscriptions/x"}); err != nil {
+		t.Errorf("runImport() error = %v", err)
+	}
+	if os.Getenv("TF_WORKSPACE") != "" {
+		t.Error("runImport() must not set TF_WORKSPACE in motf's own environment")
+	}
+}
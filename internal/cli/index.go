@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// indexFormatVersion is the version of the module index file format
+const indexFormatVersion = 1
+
+// indexCmd groups the module index commands
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Share the module index between CI jobs",
+}
+
+var indexExportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Write the discovered modules to an index file",
+	Long: `Discover the modules in components, bases, and projects and write them to an
+index file, - for stdout.
+
+Pass the file to later commands with --index to skip walking the repository,
+e.g. in ephemeral CI jobs that restore it as an artifact of an earlier stage.
+Module paths in the index are relative to the root, so it works in a checkout
+at a different location. The index isn't updated when modules are added,
+moved, or removed, so export it again after such changes.`,
+	Example: `  motf index export index.json                          # Write the index
+  motf list --index index.json                          # List modules from the index
+  motf val -i --changed --parallel --index index.json   # Validate changed modules`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIndexExport,
+}
+
+func init() {
+	indexCmd.AddCommand(indexExportCmd)
+	rootCmd.AddCommand(indexCmd)
+}
+
+// indexFile is the JSON format of a module index
+type indexFile struct {
+	Version int          `json:"version"`
+	Modules []ModuleInfo `json:"modules"` // Slash-separated paths relative to the root
+}
+
+func runIndexExport(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := walkModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	sortModules(modules)
+
+	index := indexFile{Version: indexFormatVersion, Modules: []ModuleInfo{}}
+	for _, mod := range modules {
+		mod.Path = filepath.ToSlash(mod.Path)
+		index.Modules = append(index.Modules, mod)
+	}
+	output, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if args[0] == "-" {
+		fmt.Println(string(output))
+		return nil
+	}
+	if err := os.WriteFile(args[0], append(output, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	fmt.Printf("Wrote %d module(s) to %s\n", len(index.Modules), args[0])
+	return nil
+}
+
+// readModuleIndex returns the modules in the --index file, with paths in the
+// OS format. Modules outside a sparse checkout are skipped, since an index
+// exported from a full checkout lists modules that aren't checked out.
+func readModuleIndex() ([]ModuleInfo, error) {
+	data, err := os.ReadFile(filepath.Clean(indexFlag))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	var index indexFile
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse index %s: %w", indexFlag, err)
+	}
+	if index.Version != indexFormatVersion {
+		return nil, fmt.Errorf("index %s has version %d, expected %d (run 'motf index export' again)", indexFlag, index.Version, indexFormatVersion)
+	}
+
+	modules := make([]ModuleInfo, 0, len(index.Modules))
+	for _, mod := range index.Modules {
+		mod.Path = filepath.FromSlash(mod.Path)
+		modules = append(modules, mod)
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return nil, err
+	}
+	modules, skipped, err := sparseModules(basePath, modules)
+	if err != nil {
+		return nil, err
+	}
+	reportSparse(skipped, "indexed module(s)")
+	return modules, nil
+}
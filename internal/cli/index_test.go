@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// setupIndexRepo creates storage and network components and a prod project,
+// and returns the root.
+func setupIndexRepo(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "storage"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "network"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "prod"))
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	return tmpDir
+}
+
+func TestIndexExport(t *testing.T) {
+	resetFlags(t)
+	tmpDir := setupIndexRepo(t)
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+
+	if err := runIndexExport(indexExportCmd, []string{indexPath}); err != nil {
+		t.Fatalf("runIndexExport() error = %v", err)
+	}
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatalf("failed to read index: %v", err)
+	}
+	var index indexFile
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("failed to parse index: %v", err)
+	}
+	if index.Version != indexFormatVersion {
+		t.Errorf("index version = %d, want %d", index.Version, indexFormatVersion)
+	}
+	var paths []string
+	for _, mod := range index.Modules {
+		paths = append(paths, mod.Path)
+	}
+	if got, want := strings.Join(paths, ","), "components/azurerm/storage,components/network,projects/prod"; got != want {
+		t.Errorf("index paths = %s, want %s", got, want)
+	}
+
+	// Modules are read from the index, without walking the repository
+	if err := os.RemoveAll(filepath.Join(tmpDir, DirProjects)); err != nil {
+		t.Fatalf("failed to remove projects: %v", err)
+	}
+	indexFlag = indexPath
+	modules, err := discoverModules(tmpDir, "*o*")
+	if err != nil {
+		t.Fatalf("discoverModules() error = %v", err)
+	}
+	if len(modules) != 3 {
+		t.Errorf("discoverModules() = %v, want storage, network, and prod from the index", modules)
+	}
+	if modules[0].Path != filepath.Join(DirComponents, "azurerm", "storage") || modules[0].Type != TypeComponent {
+		t.Errorf("discoverModules()[0] = %+v, want the storage component", modules[0])
+	}
+}
+
+func TestFindModuleInAllDirs_Index(t *testing.T) {
+	resetFlags(t)
+	tmpDir := setupIndexRepo(t)
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	if err := runIndexExport(indexExportCmd, []string{indexPath}); err != nil {
+		t.Fatalf("runIndexExport() error = %v", err)
+	}
+	indexFlag = indexPath
+
+	path, err := findModuleInAllDirs("storage")
+	if err != nil {
+		t.Fatalf("findModuleInAllDirs() error = %v", err)
+	}
+	if want := filepath.Join(tmpDir, DirComponents, "azurerm", "storage"); path != want {
+		t.Errorf("findModuleInAllDirs() = %s, want %s", path, want)
+	}
+	if _, err := findModuleInAllDirs("missing"); err == nil {
+		t.Error("expected an error for a module not in the index")
+	}
+}
+
+func TestReadModuleIndex_Errors(t *testing.T) {
+	resetFlags(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid JSON", "{", "failed to parse index"},
+		{"other version", `{"version": 2, "modules": []}`, "has version 2, expected 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexFlag = filepath.Join(dir, "index.json")
+			if err := os.WriteFile(indexFlag, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write index: %v", err)
+			}
+			_, err := readModuleIndex()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("readModuleIndex() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	indexFlag = filepath.Join(dir, "missing.json")
+	if _, err := readModuleIndex(); err == nil || !strings.Contains(err.Error(), "failed to read index") {
+		t.Errorf("readModuleIndex() error = %v, want read error", err)
+	}
+}
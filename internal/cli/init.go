@@ -16,15 +16,21 @@ Use the --example/-e flag to run init on a specific example instead of the modul
 
 Examples:
   motf init storage-account              # Run init on storage-account module
-  motf init storage-account -e basic     # Run init on the 'basic' example`,
-	Args: cobra.MaximumNArgs(1),
+  motf init storage-account -e basic     # Run init on the 'basic' example
+  motf init --all -p                     # Run init on all modules in parallel`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
+		if selectingModules() {
 			if len(args) > 0 {
 				return cobra.MaximumNArgs(0)(cmd, args)
 			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
-				return runner.RunInitWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
+			return runOnSelectedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := runnerFor(moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				return tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
 			})
 		}
 
@@ -33,15 +39,24 @@ Examples:
 			return err
 		}
 
-		return runner.RunInit(targetPath, argsFlag...)
+		tfRunner, err := runnerFor(targetPath)
+		if err != nil {
+			return err
+		}
+
+		return tfRunner.RunInit(targetPath, argsFlag...)
 	},
 }
 
 func init() {
 	initCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	initCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
 	initCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	initCmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	initCmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
 	initCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
-	initCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
-	initCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	addChangeRangeFlags(initCmd)
+	addParallelFlags(initCmd)
+	addRetriesFlag(initCmd)
 	rootCmd.AddCommand(initCmd)
 }
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+)
+
+// injectFailureFlag makes selected modules fail in multi-module runs, for
+// testing CI failure handling. Hidden developer flag.
+var injectFailureFlag string
+
+// errInjectedFailure is returned for modules selected by --inject-failure
+var errInjectedFailure = errors.New("injected failure (--inject-failure)")
+
+// failureInjector decides which modules fail artificially
+type failureInjector struct {
+	patterns []string       // Module names or paths, * wildcards allowed
+	percent  float64        // Chance (0-100) that any module fails
+	roll     func() float64 // Returns a number in [0, 100); replaced in tests
+}
+
+// parseFailureInjection parses an --inject-failure value: either a percentage
+// ("25%") or a comma-separated list of module names or paths ("vnet,dns-*").
+// Returns nil when spec is empty.
+func parseFailureInjection(spec string) (*failureInjector, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	if value, ok := strings.CutSuffix(spec, "%"); ok {
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid --inject-failure percentage '%s': must be between 0%% and 100%%", spec)
+		}
+		return &failureInjector{percent: percent, roll: func() float64 { return rand.Float64() * 100 }}, nil
+	}
+
+	var patterns []string
+	for _, p := range strings.Split(spec, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("invalid --inject-failure value '%s': expected module names or a percentage", spec)
+	}
+	return &failureInjector{patterns: patterns}, nil
+}
+
+// selects reports whether mod should fail.
+func (f *failureInjector) selects(mod ModuleInfo) bool {
+	if f.roll != nil {
+		return f.roll() < f.percent
+	}
+	for _, pattern := range f.patterns {
+		if finder.MatchesWildcard(mod.Name, pattern) || finder.MatchesWildcard(mod.Path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap makes selected modules fail without running fn. A nil injector
+// returns fn unchanged.
+func (f *failureInjector) wrap(fn ModuleRunner) ModuleRunner {
+	if f == nil {
+		return fn
+	}
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		if !f.selects(mod) {
+			return fn(mod, stdout, stderr)
+		}
+		_, _ = fmt.Fprintf(stderr, "[inject-failure] Failing %s without running the command\n", mod.Path)
+		return errInjectedFailure
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&injectFailureFlag, "inject-failure", "", "Fail selected modules in multi-module runs: module names/paths (comma-separated) or a percentage like 25%")
+	_ = rootCmd.PersistentFlags().MarkHidden("inject-failure")
+}
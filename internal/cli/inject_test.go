@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseFailureInjection(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         string
+		wantNil      bool
+		wantErr      bool
+		wantPatterns []string
+		wantPercent  float64
+	}{
+		{name: "empty", spec: "", wantNil: true},
+		{name: "single module", spec: "vnet", wantPatterns: []string{"vnet"}},
+		{name: "module list", spec: "vnet, dns-*,", wantPatterns: []string{"vnet", "dns-*"}},
+		{name: "percentage", spec: "25%", wantPercent: 25},
+		{name: "fractional percentage", spec: "12.5%", wantPercent: 12.5},
+		{name: "percentage too high", spec: "150%", wantErr: true},
+		{name: "negative percentage", spec: "-1%", wantErr: true},
+		{name: "not a number", spec: "many%", wantErr: true},
+		{name: "only commas", spec: ",,", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFailureInjection(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFailureInjection(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("expected nil injector, got %+v", got)
+				}
+				return
+			}
+			if strings.Join(got.patterns, ",") != strings.Join(tt.wantPatterns, ",") {
+				t.Errorf("patterns = %v, want %v", got.patterns, tt.wantPatterns)
+			}
+			if got.percent != tt.wantPercent {
+				t.Errorf("percent = %v, want %v", got.percent, tt.wantPercent)
+			}
+		})
+	}
+}
+
+func TestFailureInjector_Selects(t *testing.T) {
+	injector, err := parseFailureInjection("vnet,projects/*")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		mod  ModuleInfo
+		want bool
+	}{
+		{ModuleInfo{Name: "vnet", Path: "components/azurerm/vnet"}, true},
+		{ModuleInfo{Name: "prod", Path: "projects/prod"}, true},
+		{ModuleInfo{Name: "dns", Path: "components/azurerm/dns"}, false},
+	}
+	for _, tt := range tests {
+		if got := injector.selects(tt.mod); got != tt.want {
+			t.Errorf("selects(%s) = %v, want %v", tt.mod.Path, got, tt.want)
+		}
+	}
+}
+
+func TestFailureInjector_Percentage(t *testing.T) {
+	injector, err := parseFailureInjection("50%")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rolls := []float64{10, 90}
+	injector.roll = func() float64 {
+		r := rolls[0]
+		rolls = rolls[1:]
+		return r
+	}
+
+	if !injector.selects(ModuleInfo{Name: "a"}) {
+		t.Error("expected a roll below the percentage to fail the module")
+	}
+	if injector.selects(ModuleInfo{Name: "b"}) {
+		t.Error("expected a roll above the percentage to run the module")
+	}
+}
+
+func TestFailureInjector_Wrap(t *testing.T) {
+	injector, err := parseFailureInjection("mod-b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	modules := []ModuleInfo{
+		{Name: "mod-a", Path: "components/mod-a"},
+		{Name: "mod-b", Path: "components/mod-b"},
+	}
+
+	var ran []string
+	var out bytes.Buffer
+	results, err := runOnModulesWithResults(modules, false, 1, &out, &out, injector.wrap(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		ran = append(ran, mod.Name)
+		return nil
+	}))
+
+	if !errors.Is(err, errInjectedFailure) {
+		t.Fatalf("expected injected failure, got %v", err)
+	}
+	if len(ran) != 1 || ran[0] != "mod-a" {
+		t.Errorf("expected only mod-a to run, ran %v", ran)
+	}
+	if results[0].err != nil || !errors.Is(results[1].err, errInjectedFailure) {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if !strings.Contains(out.String(), "[inject-failure] Failing components/mod-b") {
+		t.Errorf("expected injection to be announced, got %q", out.String())
+	}
+}
+
+func TestFailureInjector_NilWrap(t *testing.T) {
+	var injector *failureInjector
+	called := false
+	fn := injector.wrap(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		called = true
+		return nil
+	})
+	if err := fn(ModuleInfo{Name: "x"}, io.Discard, io.Discard); err != nil || !called {
+		t.Errorf("expected nil injector to run fn unchanged, called=%v err=%v", called, err)
+	}
+}
+
+func TestInjectFailureFlag_Hidden(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("inject-failure")
+	if flag == nil {
+		t.Fatal("expected --inject-failure flag")
+	}
+	if !flag.Hidden {
+		t.Error("expected --inject-failure to be hidden from help")
+	}
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+// fixDescriptions is the --fix value that adds missing descriptions
+const fixDescriptions = "descriptions"
+
+var (
+	lintFixFlags        []string // Problems to fix instead of only reporting them
+	lintPlaceholderFlag string   // Description inserted by --fix descriptions
+	lintJSONFlag        bool     // Output findings as JSON
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [module-name]",
+	Short: "Check that variables and outputs of a component, base, or project have descriptions",
+	Long: `Report the variables and outputs of a component, base, or project that have no
+description, or an empty one. The command fails when any are found.
+
+Use --fix descriptions to add a placeholder description to each variable and
+output without one, so a "descriptions required" policy can be rolled out
+without editing every file by hand. The placeholder is set with
+lint.description_placeholder in .motf.yml or --placeholder. Changed files are
+formatted like 'terraform fmt'. Empty descriptions and .tf.json files are
+reported but left for a human to fix.`,
+	Example: `  motf lint storage-account                        # Report missing descriptions
+  motf lint --all                                  # Report for every module
+  motf lint --all --fix descriptions               # Add placeholder descriptions
+  motf lint --changed --fix descriptions --dry-run # Show what would be added
+  motf lint --all --fix descriptions --placeholder "TODO(platform): describe"`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runLint,
+}
+
+func init() {
+	lintCmd.Flags().StringSliceVar(&lintFixFlags, "fix", nil, "Fix problems instead of only reporting them (descriptions)")
+	lintCmd.Flags().StringVar(&lintPlaceholderFlag, "placeholder", "", "Description added by --fix descriptions (default: lint.description_placeholder from config)")
+	lintCmd.Flags().BoolVar(&lintJSONFlag, "json", false, "Output findings as JSON")
+	lintCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	lintCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	lintCmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	lintCmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
+	lintCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(lintCmd)
+	addParallelFlags(lintCmd)
+	rootCmd.AddCommand(lintCmd)
+}
+
+// lintCollector gathers findings from concurrently linted modules
+type lintCollector struct {
+	mu       sync.Mutex
+	findings []lint.Finding
+}
+
+func (c *lintCollector) add(module string, findings []lint.Finding) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range findings {
+		f.Module = module
+		c.findings = append(c.findings, f)
+	}
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	for _, fix := range lintFixFlags {
+		if fix != fixDescriptions {
+			return fmt.Errorf("invalid --fix '%s': must be %s", fix, fixDescriptions)
+		}
+	}
+	fix := slices.Contains(lintFixFlags, fixDescriptions)
+	if lintPlaceholderFlag != "" && !fix {
+		return fmt.Errorf("--placeholder requires --fix %s", fixDescriptions)
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	collector := &lintCollector{}
+	check := func(modulePath string, stdout, stderr io.Writer) error {
+		var findings []lint.Finding
+		var err error
+		if fix && !dryRunFlag {
+			placeholder := lintPlaceholderFlag
+			if placeholder == "" {
+				modCfg, err := moduleConfig(modulePath)
+				if err != nil {
+					return err
+				}
+				placeholder = modCfg.Lint.GetDescriptionPlaceholder()
+			}
+			findings, err = lint.FixDescriptions(modulePath, placeholder)
+		} else {
+			findings, err = lint.MissingDescriptions(modulePath)
+		}
+		if err != nil {
+			return err
+		}
+		collector.add(displayPath(basePath, modulePath), findings)
+		return nil
+	}
+
+	var lintErr error
+	if selectingModules() {
+		if len(args) > 0 {
+			return cobra.MaximumNArgs(0)(cmd, args)
+		}
+		lintErr = runOnSelectedModulesWithPath(check)
+	} else {
+		targetPath, err := resolveTargetPath(args)
+		if err != nil {
+			return err
+		}
+		lintErr = check(targetPath, os.Stdout, os.Stderr)
+	}
+
+	findings := collector.findings
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Module < findings[j].Module })
+
+	if lintJSONFlag {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		output, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		printLintFindings(findings, fix)
+	}
+
+	remaining := 0
+	for _, f := range findings {
+		if !f.Fixed && !(dryRunFlag && fix && f.Fixable) {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		cmd.SilenceUsage = true
+		return errors.Join(lintErr, findingsFailed("%d variable(s) and output(s) without a description", remaining))
+	}
+	return lintErr
+}
+
+// printLintFindings outputs one line per finding, followed by a summary
+func printLintFindings(findings []lint.Finding, fix bool) {
+	fixed := 0
+	for _, f := range findings {
+		location := fmt.Sprintf("%s:%d", path.Join(f.Module, f.File), f.Line)
+		address := f.Kind + "." + f.Name
+		switch {
+		case f.Fixed:
+			fmt.Printf("%s: added description to %s\n", location, address)
+			fixed++
+		case fix && dryRunFlag && f.Fixable:
+			fmt.Printf("[dry-run] %s: would add description to %s\n", location, address)
+		default:
+			fmt.Printf("%s: %s has no description\n", location, address)
+		}
+	}
+
+	switch {
+	case len(findings) == 0:
+		fmt.Println("All variables and outputs have a description")
+	case fixed > 0:
+		fmt.Printf("Added %d description(s), edit the placeholders before merging\n", fixed)
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func resetLintFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		lintFixFlags = nil
+		lintPlaceholderFlag = ""
+		lintJSONFlag = false
+	})
+}
+
+// createUndescribedModule creates a module with a variable without a description
+func createUndescribedModule(t *testing.T, base, rel string) string {
+	t.Helper()
+	modulePath := createTerraformModule(t, base, rel)
+	content := "variable \"location\" {\n  type = string\n}\n"
+	if err := os.WriteFile(filepath.Join(modulePath, "variables.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write variables.tf: %v", err)
+	}
+	return modulePath
+}
+
+func TestLintCmd_Flags(t *testing.T) {
+	for _, name := range []string{"fix", "placeholder", "json", "all", "changed", "select", "type", "ref", "parallel"} {
+		if lintCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected lint command to have --%s flag", name)
+		}
+	}
+}
+
+func TestLintCmd_InvalidFix(t *testing.T) {
+	resetFlags(t)
+	resetLintFlags(t)
+
+	lintFixFlags = []string{"names"}
+	err := runLint(lintCmd, []string{"x"})
+	if err == nil || !strings.Contains(err.Error(), "invalid --fix") {
+		t.Fatalf("expected invalid --fix error, got %v", err)
+	}
+}
+
+func TestLintCmd_PlaceholderRequiresFix(t *testing.T) {
+	resetFlags(t)
+	resetLintFlags(t)
+
+	lintPlaceholderFlag = "TODO"
+	err := runLint(lintCmd, []string{"x"})
+	if err == nil || !strings.Contains(err.Error(), "--placeholder requires --fix") {
+		t.Fatalf("expected --placeholder error, got %v", err)
+	}
+}
+
+func TestLintCmd_ReportsMissingDescriptions(t *testing.T) {
+	resetFlags(t)
+	resetLintFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	pathFlag = createUndescribedModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+
+	err := runLint(lintCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "1 variable(s) and output(s) without a description") {
+		t.Fatalf("expected missing description error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+}
+
+func TestLintCmd_FixDescriptions(t *testing.T) {
+	resetFlags(t)
+	resetLintFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{
+		Root:   tmpDir,
+		Binary: "terraform",
+		Lint:   &config.LintConfig{DescriptionPlaceholder: "TODO(platform)"},
+	})
+	modulePath := createUndescribedModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	pathFlag = modulePath
+	lintFixFlags = []string{fixDescriptions}
+
+	if err := runLint(lintCmd, nil); err != nil {
+		t.Fatalf("runLint() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(modulePath, "variables.tf"))
+	if err != nil {
+		t.Fatalf("failed to read variables.tf: %v", err)
+	}
+	if !strings.Contains(string(data), `description = "TODO(platform)"`) {
+		t.Errorf("expected the configured placeholder, got:\n%s", data)
+	}
+
+	// Nothing is left to report
+	lintFixFlags = nil
+	if err := runLint(lintCmd, nil); err != nil {
+		t.Errorf("expected no findings after --fix, got %v", err)
+	}
+}
+
+func TestLintCmd_FixDryRunDoesNotWrite(t *testing.T) {
+	resetFlags(t)
+	resetLintFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	modulePath := createUndescribedModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	pathFlag = modulePath
+	lintFixFlags = []string{fixDescriptions}
+	lintPlaceholderFlag = "TODO"
+	dryRunFlag = true
+
+	if err := runLint(lintCmd, nil); err != nil {
+		t.Fatalf("runLint() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(modulePath, "variables.tf"))
+	if err != nil {
+		t.Fatalf("failed to read variables.tf: %v", err)
+	}
+	if strings.Contains(string(data), "description") {
+		t.Errorf("expected --dry-run not to write, got:\n%s", data)
+	}
+}
+
+func TestLintCmd_All(t *testing.T) {
+	resetFlags(t)
+	resetLintFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createUndescribedModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+	createUndescribedModule(t, tmpDir, filepath.Join(DirProjects, "prod"))
+	allFlag = true
+
+	err := runLint(lintCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "2 variable(s)") {
+		t.Fatalf("expected findings in both modules, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
@@ -18,15 +19,21 @@ var listJsonFlag bool
 // listNamesOnlyFlag outputs only module names (not paths)
 var listNamesOnlyFlag bool
 
+// listOutputFlag outputs the reviewers of the listed modules instead of the modules
+var listOutputFlag string
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all modules (components, bases, and projects)",
-	Long: `List all modules found in components, bases, and projects directories.
+	Long: `List all modules found in components, bases, and projects directories, or
+the directories set with module_dirs in .motf.yml.
 
 Use the --search/-s flag to filter modules using wildcards.
 Use the --changed flag to show only modules with changes compared to a git ref.
+Use the --type flag to show only modules of a type, e.g. component.
 Use the --json flag to output in JSON format for scripting.
+Use --output reviewers (or gh) to output the CODEOWNERS owners of the listed modules.
 
 Examples:
   motf list                        # List all modules
@@ -35,8 +42,13 @@ Examples:
   motf list --json                 # Output as JSON
   motf list --changed              # List only changed modules
   motf list --changed --ref HEAD~5 # List modules changed in last 5 commits
+  motf list --changed --since 2.weeks           # List modules changed in the last 2 weeks
+  motf list --changed --from v1.0.0 --to v1.1.0 # List modules changed between two tags
   motf list --changed --names      # Output only changed module names (for scripting)
-  motf list --changed -s storage   # List changed modules matching "storage"`,
+  motf list --changed -s storage   # List changed modules matching "storage"
+  motf list --type project         # List only projects
+  motf list --changed -o reviewers # Output owners of changed modules from CODEOWNERS
+  motf list --changed -o gh        # Output a gh command requesting their review`,
 	RunE: runList,
 }
 
@@ -44,12 +56,25 @@ func init() {
 	listCmd.Flags().StringVarP(&searchFlag, "search", "s", "", "Filter modules using wildcards (e.g., *storage*)")
 	listCmd.Flags().BoolVar(&listJsonFlag, "json", false, "Output in JSON format")
 	listCmd.Flags().BoolVar(&listNamesOnlyFlag, "names", false, "Output only module names (one per line)")
+	listCmd.Flags().StringVarP(&listOutputFlag, "output", "o", "", "Output the CODEOWNERS owners of the modules instead: reviewers or gh")
 	listCmd.Flags().BoolVar(&changedFlag, "changed", false, "List only modules changed compared to --ref")
+	listCmd.Flags().StringVar(&typeFlag, "type", "", "List only modules of a type (component, base, project)")
 	listCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(listCmd)
 	rootCmd.AddCommand(listCmd)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if err := validateTypeFlag(); err != nil {
+		return err
+	}
+	if err := validateListOutput(); err != nil {
+		return err
+	}
+	if err := validateChangeRange(); err != nil {
+		return err
+	}
+
 	basePath, err := getBasePath()
 	if err != nil {
 		return err
@@ -59,7 +84,7 @@ func runList(cmd *cobra.Command, args []string) error {
 
 	if changedFlag {
 		// Get changed modules
-		modules, err = detectChangedModules(refFlag)
+		modules, err = detectChangedModules(changeRangeFlags())
 		if err != nil {
 			return err
 		}
@@ -85,28 +110,31 @@ func runList(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		if err := reportUnmanagedModules(basePath); err != nil {
+			return err
+		}
 	}
 
+	modules = filterModulesByType(modules)
+
 	if len(modules) == 0 {
 		if listJsonFlag {
 			fmt.Println("[]")
 			return nil
 		}
-		if listNamesOnlyFlag {
+		if listNamesOnlyFlag || listOutputFlag != "" {
 			return nil
 		}
-		if changedFlag {
-			fmt.Println("No changed modules found")
-		} else if searchFlag != "" {
-			fmt.Printf("No modules found matching '%s'\n", searchFlag)
-		} else {
-			fmt.Println("No modules found")
-		}
+		fmt.Println(noModulesMessage(searchFlag))
 		return nil
 	}
 
 	sortModules(modules)
 
+	if listOutputFlag != "" {
+		return printReviewers(basePath, modules, listOutputFlag)
+	}
+
 	if listJsonFlag {
 		return printModulesJSON(modules)
 	}
@@ -122,12 +150,43 @@ func runList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// collectModules discovers all modules across components, bases, and projects directories
+// collectModules discovers all modules across components, bases, and projects
+// directories, leaving out modules outside managed_paths
 func collectModules(basePath, searchFilter string) ([]ModuleInfo, error) {
+	modules, err := discoverModules(basePath, searchFilter)
+	if err != nil {
+		return nil, err
+	}
+	modules, _ = filterManaged(modules)
+	return modules, nil
+}
+
+// discoverModules discovers all modules across components, bases, and projects
+// directories, including those outside managed_paths. With --index, the
+// modules are read from the index instead.
+func discoverModules(basePath, searchFilter string) ([]ModuleInfo, error) {
+	if indexFlag == "" {
+		return walkModules(basePath, searchFilter)
+	}
+	modules, err := readModuleIndex()
+	if err != nil {
+		return nil, err
+	}
+	if searchFilter != "" {
+		modules = slices.DeleteFunc(modules, func(mod ModuleInfo) bool {
+			return !finder.MatchesWildcard(mod.Name, searchFilter)
+		})
+	}
+	return modules, nil
+}
+
+// walkModules discovers all modules by walking the module directories
+// (components, bases, and projects unless module_dirs is set) of every root
+func walkModules(basePath, searchFilter string) ([]ModuleInfo, error) {
 	var allModules []ModuleInfo
 
-	for _, moduleDir := range ModuleDirs {
-		searchPath := filepath.Join(basePath, moduleDir)
+	for _, moduleDir := range searchDirs() {
+		searchPath := filepath.Join(basePath, moduleDir.Dir)
 
 		// Skip if directory doesn't exist
 		if _, err := os.Stat(searchPath); os.IsNotExist(err) {
@@ -137,7 +196,7 @@ func collectModules(basePath, searchFilter string) ([]ModuleInfo, error) {
 		// List all modules in this directory
 		modules, err := finder.ListAllModules(searchPath)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list modules in %s: %w", moduleDir, err)
+			return nil, fmt.Errorf("failed to list modules in %s: %w", moduleDir.Dir, err)
 		}
 
 		// Process each module
@@ -155,7 +214,7 @@ func collectModules(basePath, searchFilter string) ([]ModuleInfo, error) {
 
 			allModules = append(allModules, ModuleInfo{
 				Name:    name,
-				Type:    getModuleType(path),
+				Type:    moduleTypeOf(relativePath, moduleDir.Type),
 				Path:    relativePath,
 				Version: spacelift.ReadModuleVersion(path),
 			})
@@ -174,6 +233,11 @@ func sortModules(modules []ModuleInfo) {
 
 // printModules outputs the list of modules to stdout in table format
 func printModules(modules []ModuleInfo) {
+	if plainFlag {
+		printModulesPlain(modules)
+		return
+	}
+
 	// Calculate column widths
 	nameWidth := len("NAME")
 	typeWidth := len("TYPE")
@@ -208,6 +272,19 @@ func printModules(modules []ModuleInfo) {
 	}
 }
 
+// printModulesPlain outputs one labeled line per field, with a blank line between modules
+func printModulesPlain(modules []ModuleInfo) {
+	for i, mod := range modules {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("Name: %s\n", mod.Name)
+		fmt.Printf("Type: %s\n", mod.Type)
+		fmt.Printf("Path: %s\n", mod.Path)
+		fmt.Printf("Version: %s\n", valueOrDefault(mod.Version, "none"))
+	}
+}
+
 // printModulesJSON outputs the list of modules in JSON format
 func printModulesJSON(modules []ModuleInfo) error {
 	output, err := json.MarshalIndent(modules, "", "  ")
@@ -3,6 +3,7 @@ package cli
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
@@ -116,6 +117,53 @@ func TestCollectModules_WithFilter(t *testing.T) {
 	}
 }
 
+func TestCollectModules_CustomModuleDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{ModuleDirs: config.ModuleDirs{{Dir: "modules", Type: "component"}, {Dir: "live", Type: "project"}}})
+
+	createTerraformModule(t, tmpDir, filepath.Join("modules", "storage"))
+	createTerraformModule(t, tmpDir, filepath.Join("live", "prod"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "network"))
+
+	modules, err := collectModules(tmpDir, "")
+	if err != nil {
+		t.Fatalf("collectModules returned error: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, mod := range modules {
+		got[mod.Name] = mod.Type
+	}
+	want := map[string]string{"storage": TypeComponent, "prod": TypeProject}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectModules() types = %v, want %v", got, want)
+	}
+}
+
+func TestCollectModules_Roots(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Roots: []string{"iac/platform", "iac/apps"}})
+
+	createTerraformModule(t, tmpDir, filepath.Join("iac", "platform", DirComponents, "storage"))
+	createTerraformModule(t, tmpDir, filepath.Join("iac", "apps", DirProjects, "web"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "outside"))
+
+	modules, err := collectModules(tmpDir, "")
+	if err != nil {
+		t.Fatalf("collectModules returned error: %v", err)
+	}
+	sortModules(modules)
+
+	var got []string
+	for _, mod := range modules {
+		got = append(got, filepath.ToSlash(mod.Path)+" ("+mod.Type+")")
+	}
+	want := []string{"iac/apps/projects/web (project)", "iac/platform/components/storage (component)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectModules() = %v, want %v", got, want)
+	}
+}
+
 func TestCollectModules_EmptyResult(t *testing.T) {
 	tmpDir := t.TempDir()
 
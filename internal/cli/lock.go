@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"io"
+	"os"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// lockPlatformFlags are the platforms to record, replacing lock.platforms
+var lockPlatformFlags []string
+
+// lockCmd represents the lock command
+var lockCmd = &cobra.Command{
+	Use:   "lock [module-name]",
+	Short: "Record provider checksums for every platform in .terraform.lock.hcl",
+	Long: `Run terraform/tofu providers lock on a component, base, or project, so its
+.terraform.lock.hcl has the provider checksums of every platform the team uses,
+not only the one init ran on. Select modules with --all, --changed, --select,
+or --type to lock several at once.
+
+The platforms are set with lock.platforms in .motf.yml, or --platform, and
+default to linux_amd64 and darwin_arm64. Use --init to run init -backend=false
+first, which providers lock needs when the module calls other modules.
+
+Provider versions already in the lock file are kept. Use 'motf lock upgrade'
+to move to the newest versions the constraints allow.`,
+	Example: `  motf lock storage-account                           # Lock a single module
+  motf lock --all -i                                  # Init and lock every module
+  motf lock --changed --platform linux_arm64          # Lock changed modules for another platform
+  motf lock upgrade --type project                    # Upgrade the providers of all projects`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runLock(false),
+}
+
+// lockUpgradeCmd represents the lock upgrade command
+var lockUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [module-name]",
+	Short: "Upgrade providers and record their checksums for every platform",
+	Long: `Run terraform/tofu init -upgrade -backend=false on a component, base, or
+project to select the newest provider versions its constraints allow, then
+providers lock to record their checksums for every platform, like 'motf lock'.
+Select modules with --all, --changed, --select, or --type.`,
+	Example: `  motf lock upgrade storage-account   # Upgrade a single module
+  motf lock upgrade --all -p          # Upgrade every module in parallel`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runLock(true),
+}
+
+func init() {
+	lockCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init -backend=false before the command")
+	for _, cmd := range []*cobra.Command{lockCmd, lockUpgradeCmd} {
+		cmd.Flags().StringSliceVar(&lockPlatformFlags, "platform", nil, "Platform to record checksums for, e.g. linux_amd64 (repeatable; default: lock.platforms from config)")
+		cmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+		cmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+		cmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+		cmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
+		cmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+		addChangeRangeFlags(cmd)
+		addParallelFlags(cmd)
+		addRetriesFlag(cmd)
+	}
+	lockCmd.AddCommand(lockUpgradeCmd)
+	rootCmd.AddCommand(lockCmd)
+}
+
+// runLock returns the RunE of lock, or of lock upgrade when upgrade is set.
+func runLock(upgrade bool) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		platforms := lockPlatformFlags
+		if len(platforms) == 0 {
+			platforms = cfg.Lock.GetPlatforms()
+		}
+		if err := config.ValidatePlatforms(platforms); err != nil {
+			return err
+		}
+		lock := func(modulePath string, stdout, stderr io.Writer) error {
+			return lockModule(modulePath, platforms, upgrade, stdout, stderr)
+		}
+
+		if selectingModules() {
+			if len(args) > 0 {
+				return cobra.MaximumNArgs(0)(cmd, args)
+			}
+			return runOnSelectedModulesWithPath(lock)
+		}
+
+		targetPath, err := resolveTargetPath(args)
+		if err != nil {
+			return err
+		}
+		return lock(targetPath, os.Stdout, os.Stderr)
+	}
+}
+
+// lockModule records the provider checksums of platforms in the lock file of
+// the module at modulePath, after upgrading its providers with upgrade, or
+// running init with --init. Init skips the backend, so no credentials are needed.
+func lockModule(modulePath string, platforms []string, upgrade bool, stdout, stderr io.Writer) error {
+	tfRunner, err := runnerFor(modulePath)
+	if err != nil {
+		return err
+	}
+	switch {
+	case upgrade:
+		err = tfRunner.RunInitWithOutput(modulePath, stdout, stderr, "-upgrade", "-backend=false")
+	case initFlag:
+		err = tfRunner.RunInitWithOutput(modulePath, stdout, stderr, "-backend=false")
+	}
+	if err != nil {
+		return err
+	}
+	return tfRunner.RunProvidersLockWithOutput(modulePath, platforms, stdout, stderr, argsFlag...)
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestLockModule_DryRun(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+	dryRunFlag = true
+	platforms := []string{"linux_amd64", "darwin_arm64"}
+
+	tests := []struct {
+		name    string
+		upgrade bool
+		init    bool
+		want    string
+	}{
+		{"lock", false, false, ""},
+		{"init", false, true, "init -backend=false"},
+		{"upgrade", true, false, "init -upgrade -backend=false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initFlag = tt.init
+			var out bytes.Buffer
+			if err := lockModule(modulePath, platforms, tt.upgrade, &out, &out); err != nil {
+				t.Fatalf("lockModule() error = %v", err)
+			}
+			want := fmt.Sprintf("[dry-run] Would run terraform providers lock -platform=linux_amd64 -platform=darwin_arm64 in %s\n", modulePath)
+			if tt.want != "" {
+				want = fmt.Sprintf("[dry-run] Would run terraform %s in %s\n", tt.want, modulePath) + want
+			}
+			if out.String() != want {
+				t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+			}
+		})
+	}
+}
+
+func TestRunLock_InvalidPlatform(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir})
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+	lockPlatformFlags = []string{"linux/amd64"}
+
+	err := runLock(false)(lockCmd, []string{"platform"})
+	if err == nil || !strings.Contains(err.Error(), "invalid platform 'linux/amd64'") {
+		t.Errorf("runLock() error = %v, want an invalid platform", err)
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+// EnvLogFormat sets the log format unless --log-format is given
+const EnvLogFormat = "MOTF_LOG_FORMAT"
+
+var (
+	verboseFlag   bool   // Show debug messages
+	quietFlag     bool   // Hide notes, showing only warnings and errors
+	logFormatFlag string // Format of motf's messages on stderr: text or json
+)
+
+// configureLogging sets up the logger of motf's own messages from --verbose,
+// --quiet, and --log-format (or MOTF_LOG_FORMAT). Messages go to the stderr
+// of cmd. With JSON, errors are logged by Execute instead of printed by cobra,
+// so every line on stderr from motf is JSON.
+func configureLogging(cmd *cobra.Command, getenv func(string) string) error {
+	if verboseFlag && quietFlag {
+		return fmt.Errorf("--verbose cannot be used with --quiet")
+	}
+	format := logFormatFlag
+	if !cmd.Flags().Changed("log-format") && getenv(EnvLogFormat) != "" {
+		format = getenv(EnvLogFormat)
+	}
+	if !slices.Contains(logging.Formats, format) {
+		return fmt.Errorf("invalid log format '%s': must be 'text' or 'json'", format)
+	}
+
+	level := logging.LevelInfo
+	switch {
+	case verboseFlag:
+		level = logging.LevelDebug
+	case quietFlag:
+		level = logging.LevelWarn
+	}
+	logging.Configure(cmd.ErrOrStderr(), level, format)
+	cmd.Root().SilenceErrors = format == logging.FormatJSON
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show debug messages of motf")
+	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Only show warnings and errors of motf, not notes")
+	rootCmd.PersistentFlags().StringVar(&logFormatFlag, "log-format", logging.FormatText, "Format of motf's messages on stderr: text or json (env: MOTF_LOG_FORMAT)")
+}
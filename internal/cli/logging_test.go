@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+// withDefaultLogger restores the default logger when the test ends.
+func withDefaultLogger(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() { logging.Configure(os.Stderr, logging.LevelInfo, logging.FormatText) })
+}
+
+func TestConfigureLogging(t *testing.T) {
+	tests := []struct {
+		name    string
+		verbose bool
+		quiet   bool
+		format  string
+		flagSet bool // Whether --log-format is given, which takes precedence over the env
+		env     string
+		want    logging.Level
+		wantFmt string
+		wantErr string
+	}{
+		{name: "default", format: "text", want: logging.LevelInfo, wantFmt: "text"},
+		{name: "verbose", verbose: true, format: "text", want: logging.LevelDebug, wantFmt: "text"},
+		{name: "quiet", quiet: true, format: "json", want: logging.LevelWarn, wantFmt: "json"},
+		{name: "env", format: "text", env: "json", want: logging.LevelInfo, wantFmt: "json"},
+		{name: "flag over env", format: "text", flagSet: true, env: "json", want: logging.LevelInfo, wantFmt: "text"},
+		{name: "verbose and quiet", verbose: true, quiet: true, format: "text", wantErr: "--verbose cannot be used with --quiet"},
+		{name: "invalid format", format: "yaml", wantErr: "invalid log format 'yaml': must be 'text' or 'json'"},
+		{name: "invalid env", format: "text", env: "xml", wantErr: "invalid log format 'xml'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			withDefaultLogger(t)
+			// A command of its own, so the Changed state of --log-format
+			// doesn't leak between cases or from other tests
+			cmd := &cobra.Command{Use: "motf"}
+			cmd.Flags().StringVar(&logFormatFlag, "log-format", logging.FormatText, "")
+			verboseFlag, quietFlag = tt.verbose, tt.quiet
+			if err := cmd.Flags().Set("log-format", tt.format); err != nil {
+				t.Fatal(err)
+			}
+			cmd.Flags().Lookup("log-format").Changed = tt.flagSet
+			getenv := func(key string) string {
+				if key == EnvLogFormat {
+					return tt.env
+				}
+				return ""
+			}
+
+			err := configureLogging(cmd, getenv)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("configureLogging() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("configureLogging() error = %v", err)
+			}
+			l := logging.Default()
+			if l.Format() != tt.wantFmt || !l.Enabled(tt.want) || (tt.want > logging.LevelDebug && l.Enabled(tt.want-1)) {
+				t.Errorf("logger format %s, want level %s and format %s", l.Format(), tt.want, tt.wantFmt)
+			}
+			if cmd.SilenceErrors != (tt.wantFmt == logging.FormatJSON) {
+				t.Errorf("SilenceErrors = %v with format %s", cmd.SilenceErrors, tt.wantFmt)
+			}
+		})
+	}
+}
+
+func TestLogFormatJSON(t *testing.T) {
+	resetFlags(t)
+	withDefaultLogger(t)
+	t.Setenv(EnvLogFormat, "")
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	withWorkingDir(t, root)
+
+	var errOut bytes.Buffer
+	rootCmd.SetErr(&errOut)
+	rootCmd.SetArgs([]string{"config", "get", "binary", "--verbose", "--log-format", "json"})
+	t.Cleanup(func() {
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+		rootCmd.SilenceErrors = false
+		for _, name := range []string{"verbose", "log-format"} {
+			rootCmd.PersistentFlags().Lookup(name).Changed = false
+		}
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var entry struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+	}
+	line, _, _ := strings.Cut(errOut.String(), "\n")
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("stderr line %q isn't JSON: %v", line, err)
+	}
+	if entry.Level != "debug" || !strings.HasPrefix(entry.Msg, "config file: none") {
+		t.Errorf("log entry = %+v, want the config file debug message", entry)
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"path/filepath"
+	"slices"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+)
+
+// managedPathsSet reports whether managed_paths limits motf to part of the repository.
+func managedPathsSet() bool {
+	return cfg != nil && len(cfg.ManagedPaths) > 0
+}
+
+// filterManaged keeps the modules inside managed_paths and returns how many were left out.
+func filterManaged(modules []ModuleInfo) ([]ModuleInfo, int) {
+	before := len(modules)
+	modules = slices.DeleteFunc(modules, func(mod ModuleInfo) bool {
+		return !cfg.IsManaged(mod.Path)
+	})
+	return modules, before - len(modules)
+}
+
+// countUnmanagedModules returns the number of modules under basePath that
+// are outside managed_paths.
+func countUnmanagedModules(basePath string) (int, error) {
+	if !managedPathsSet() {
+		return 0, nil
+	}
+	modules, err := discoverModules(basePath, "")
+	if err != nil {
+		return 0, err
+	}
+	_, unmanaged := filterManaged(modules)
+	return unmanaged, nil
+}
+
+// reportUnmanaged notes on stderr that Terraform modules exist outside
+// managed_paths, so a migration never silently skips them.
+func reportUnmanaged(count int, what string) {
+	if count == 0 {
+		return
+	}
+	logging.Infof("%d %s outside managed_paths are not managed by motf", count, what)
+}
+
+// reportUnmanagedModules counts the modules under basePath outside
+// managed_paths and notes them on stderr.
+func reportUnmanagedModules(basePath string) error {
+	count, err := countUnmanagedModules(basePath)
+	if err != nil {
+		return err
+	}
+	reportUnmanaged(count, "module(s)")
+	return nil
+}
+
+// isManagedDir reports whether the absolute module directory is inside managed_paths.
+func isManagedDir(basePath, dir string) bool {
+	rel, err := filepath.Rel(basePath, dir)
+	if err != nil {
+		return true
+	}
+	return cfg.IsManaged(rel)
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestCollectModules_ManagedPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Binary: "terraform", ManagedPaths: []string{"components/azurerm", "projects"}})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "legacy", "vnet"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "shared"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "landing-zone"))
+
+	modules, err := collectModules(tmpDir, "")
+	if err != nil {
+		t.Fatalf("collectModules returned error: %v", err)
+	}
+	sortModules(modules)
+	if len(modules) != 2 || modules[0].Name != "storage-account" || modules[1].Name != "landing-zone" {
+		t.Errorf("modules = %v, want [storage-account landing-zone]", modules)
+	}
+
+	unmanaged, err := countUnmanagedModules(tmpDir)
+	if err != nil {
+		t.Fatalf("countUnmanagedModules returned error: %v", err)
+	}
+	if unmanaged != 2 {
+		t.Errorf("countUnmanagedModules() = %d, want 2", unmanaged)
+	}
+}
+
+func TestCountUnmanagedModules_NoManagedPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+
+	unmanaged, err := countUnmanagedModules(tmpDir)
+	if err != nil {
+		t.Fatalf("countUnmanagedModules returned error: %v", err)
+	}
+	if unmanaged != 0 {
+		t.Errorf("countUnmanagedModules() = %d, want 0", unmanaged)
+	}
+}
+
+func TestFindModuleInAllDirs_Unmanaged(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Binary: "terraform", ManagedPaths: []string{"components/azurerm"}})
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "legacy", "vnet"))
+
+	_, err := findModuleInAllDirs("vnet")
+	if err == nil || !strings.Contains(err.Error(), "module 'vnet' is outside managed_paths") {
+		t.Fatalf("expected unmanaged module error, got %v", err)
+	}
+}
+
+func TestFindModuleInAllDirs_ManagedWinsNameClash(t *testing.T) {
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Binary: "terraform", ManagedPaths: []string{"components/azurerm"}})
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "legacy", "vnet"))
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "vnet"))
+
+	result, err := findModuleInAllDirs("vnet")
+	if err != nil {
+		t.Fatalf("findModuleInAllDirs returned error: %v", err)
+	}
+	if result != modulePath {
+		t.Errorf("expected '%s', got '%s'", modulePath, result)
+	}
+}
+
+func TestInitCmd_AllSkipsUnmanagedModules(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, config.ConfigFile), []byte("managed_paths:\n  - components/azurerm\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "azurerm", "storage-account"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "legacy", "vnet"))
+	logDir := filepath.Join(tmpDir, "logs")
+
+	rootCmd.SetArgs([]string{"init", "--all", "-p", "--dry-run", "--log-dir", logDir})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init --all failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(logDir, "storage-account.log")); err != nil {
+		t.Errorf("expected storage-account to be initialized: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(logDir, "vnet.log")); err == nil {
+		t.Error("expected vnet outside managed_paths to be skipped")
+	}
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+// moduleMetrics are the outcomes of the modules the command ran, emitted
+// with the metrics of the command
+var moduleMetrics []metrics.Module
+
+// recordModuleMetrics keeps the outcomes of the modules of a run for emitMetrics.
+func recordModuleMetrics(results []moduleResult) {
+	for _, r := range buildChatopsSummary(commandName, results, nil).Results {
+		moduleMetrics = append(moduleMetrics, metrics.Module{
+			Name:     r.Name,
+			Path:     filepath.ToSlash(r.Path),
+			Status:   r.Status,
+			Duration: r.Duration,
+		})
+	}
+}
+
+// unmeasuredCommands don't emit metrics, since they don't do any work
+var unmeasuredCommands = []string{"help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+// emitMetrics emits the duration and outcome of cmd, which returned err,
+// and of the modules it ran, when metrics are configured. A failure to emit
+// them is a warning, so metrics never fail a CI job.
+func emitMetrics(cmd *cobra.Command, err error, duration time.Duration) {
+	if cfg == nil || cmd == nil || commandName == "" || dryRunFlag || slices.Contains(unmeasuredCommands, cmd.Name()) {
+		return
+	}
+	emitter := cfg.Metrics.Emitter()
+	if emitter == nil {
+		return
+	}
+
+	status := metrics.StatusSucceeded
+	var changes *planChangesError
+	if errors.As(err, &changes) {
+		status = metrics.StatusChanges
+	} else if err != nil {
+		status = metrics.StatusFailed
+	}
+	run := metrics.Run{
+		Command:  strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		Status:   status,
+		ExitCode: ExitCode(err),
+		Time:     now(),
+		Duration: duration,
+		Modules:  moduleMetrics,
+	}
+	if emitErr := emitter.Emit(run); emitErr != nil {
+		logging.Warnf("failed to emit metrics: %v", emitErr)
+		return
+	}
+	logging.Debugf("emitted %s metrics of %d module(s)", emitter.Format, len(run.Modules))
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/metrics"
+)
+
+// readMetrics returns the JSON lines of a metrics file.
+func readMetrics(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("metrics not written: %v", err)
+	}
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid metrics line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestEmitMetrics(t *testing.T) {
+	resetFlags(t)
+	commandName = "plan"
+	t.Cleanup(func() { commandName = "" })
+	path := filepath.Join(t.TempDir(), "metrics.ndjson")
+	withConfig(t, &config.Config{Metrics: &config.MetricsConfig{Format: metrics.FormatJSON, Path: path}})
+
+	recordModuleMetrics(summaryResults())
+	emitMetrics(planCmd, &planChangesError{modules: 1}, 3*time.Second)
+
+	records := readMetrics(t, path)
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 3 modules and the command", len(records))
+	}
+	command := records[3]
+	if command["command"] != "plan" || command["status"] != metrics.StatusChanges || command["exit_code"] != float64(ExitPlanChanges) || command["duration_ms"] != float64(3000) {
+		t.Errorf("command record = %v", command)
+	}
+	if records[1]["path"] != "components/dns" || records[1]["status"] != "failed" {
+		t.Errorf("module record = %v", records[1])
+	}
+}
+
+func TestEmitMetrics_Skipped(t *testing.T) {
+	resetFlags(t)
+	commandName = "plan"
+	t.Cleanup(func() { commandName = "" })
+	path := filepath.Join(t.TempDir(), "metrics.ndjson")
+	withConfig(t, &config.Config{Metrics: &config.MetricsConfig{Format: metrics.FormatJSON, Path: path}})
+
+	dryRunFlag = true
+	emitMetrics(planCmd, nil, time.Second)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("dry runs should not emit metrics")
+	}
+
+	dryRunFlag = false
+	withConfig(t, &config.Config{})
+	emitMetrics(planCmd, nil, time.Second)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("metrics should be off without a metrics section")
+	}
+}
+
+func TestEmitMetrics_FailureWarns(t *testing.T) {
+	resetFlags(t)
+	commandName = "plan"
+	t.Cleanup(func() { commandName = "" })
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	withConfig(t, &config.Config{Metrics: &config.MetricsConfig{Format: metrics.FormatJSON, Path: filepath.Join(blocker, "metrics.ndjson")}})
+	log := captureLog(t)
+
+	emitMetrics(planCmd, errors.New("exit status 1"), time.Second)
+	if !strings.Contains(log.String(), "Warning: failed to emit metrics") {
+		t.Errorf("expected a warning, got %q", log.String())
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+)
+
+// lastRunReportFile is the name of the run report written to the log directory
+const lastRunReportFile = "last-run.json"
+
+// moduleLogs writes each module's full stdout and stderr to <dir>/<module>.log
+// in addition to the console output.
+type moduleLogs struct {
+	dir   string
+	files map[string]string // module path -> log file path
+}
+
+// newModuleLogs creates the log directory and assigns a log file to each module.
+// Modules are named by their module name; if names clash within the run, the
+// module path is used instead (with "/" replaced by "_"). When dir is empty,
+// logging to files is disabled and wrap returns fn unchanged.
+func newModuleLogs(dir string, modules []ModuleInfo) (*moduleLogs, error) {
+	logs := &moduleLogs{dir: dir, files: make(map[string]string)}
+	if dir == "" {
+		return logs, nil
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	nameCount := make(map[string]int)
+	for _, mod := range modules {
+		nameCount[mod.Name]++
+	}
+	for _, mod := range modules {
+		name := mod.Name
+		if nameCount[name] > 1 {
+			name = strings.ReplaceAll(filepath.ToSlash(mod.Path), "/", "_")
+		}
+		logs.files[mod.Path] = filepath.Join(dir, name+".log")
+	}
+	return logs, nil
+}
+
+// wrap tees the module's stdout and stderr into its log file.
+func (l *moduleLogs) wrap(fn ModuleRunner) ModuleRunner {
+	if l.dir == "" {
+		return fn
+	}
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		logPath, ok := l.files[mod.Path]
+		if !ok {
+			return fn(mod, stdout, stderr)
+		}
+
+		f, err := os.OpenFile(filepath.Clean(logPath), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create log file: %w", err)
+		}
+
+		w := &syncWriter{w: f}
+		runErr := fn(mod, io.MultiWriter(stdout, w), io.MultiWriter(stderr, w))
+		if closeErr := f.Close(); closeErr != nil && runErr == nil {
+			return fmt.Errorf("failed to write log file: %w", closeErr)
+		}
+		return runErr
+	}
+}
+
+// writeReport writes the run summary to <dir>/last-run.json, replacing the
+// report of the previous run. It is a no-op when logging to files is disabled.
+func (l *moduleLogs) writeReport(summary chatops.Summary) error {
+	if l.dir == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(l.dir, lastRunReportFile), append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write run report: %w", err)
+	}
+	return nil
+}
+
+// syncWriter serializes writes from stdout and stderr to a single writer.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
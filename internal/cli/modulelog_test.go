@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+)
+
+func TestModuleLogs_Disabled(t *testing.T) {
+	logs, err := newModuleLogs("", []ModuleInfo{{Name: "mod-a", Path: "components/mod-a"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = runOnModules([]ModuleInfo{{Name: "mod-a", Path: "components/mod-a"}}, false, 1, &buf, &buf, logs.wrap(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		_, _ = stdout.Write([]byte("hello\n"))
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "hello") {
+		t.Errorf("expected console output, got %q", buf.String())
+	}
+}
+
+func TestModuleLogs_WritesPerModuleFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	modules := []ModuleInfo{
+		{Name: "mod-a", Path: "components/mod-a"},
+		{Name: "mod-b", Path: "components/mod-b"},
+	}
+
+	logs, err := newModuleLogs(dir, modules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = runOnModules(modules, true, 2, &buf, &buf, logs.wrap(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		_, _ = stdout.Write([]byte("out " + mod.Name + "\n"))
+		_, _ = stderr.Write([]byte("err " + mod.Name + "\n"))
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, mod := range modules {
+		data, err := os.ReadFile(filepath.Join(dir, mod.Name+".log"))
+		if err != nil {
+			t.Fatalf("expected log file for %s: %v", mod.Name, err)
+		}
+		content := string(data)
+		if !strings.Contains(content, "out "+mod.Name) || !strings.Contains(content, "err "+mod.Name) {
+			t.Errorf("log for %s missing output, got %q", mod.Name, content)
+		}
+		// Log files contain the raw output, without the console prefix
+		if strings.Contains(content, "["+mod.Name+"]") {
+			t.Errorf("log for %s should not contain the console prefix, got %q", mod.Name, content)
+		}
+	}
+
+	if !strings.Contains(buf.String(), "out mod-a") {
+		t.Errorf("expected output to still stream to the console, got %q", buf.String())
+	}
+}
+
+func TestModuleLogs_NameClashUsesPath(t *testing.T) {
+	dir := t.TempDir()
+	modules := []ModuleInfo{
+		{Name: "storage", Path: "components/azurerm/storage"},
+		{Name: "storage", Path: "components/aws/storage"},
+	}
+
+	logs, err := newModuleLogs(dir, modules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"components/azurerm/storage": filepath.Join(dir, "components_azurerm_storage.log"),
+		"components/aws/storage":     filepath.Join(dir, "components_aws_storage.log"),
+	}
+	for path, want := range expected {
+		if got := logs.files[path]; got != want {
+			t.Errorf("log file for %s = %s, want %s", path, got, want)
+		}
+	}
+}
+
+func TestModuleLogs_WriteReport(t *testing.T) {
+	dir := t.TempDir()
+	logs, err := newModuleLogs(dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	summary := chatops.Summary{
+		Command: "plan",
+		Results: []chatops.ModuleResult{{Name: "mod-a", Path: "components/mod-a", Status: chatops.StatusFailed, Error: "exit status 1"}},
+	}
+	if err := logs.writeReport(summary); err != nil {
+		t.Fatalf("writeReport returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, lastRunReportFile))
+	if err != nil {
+		t.Fatalf("expected run report: %v", err)
+	}
+	var got chatops.Summary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("run report is not valid JSON: %v", err)
+	}
+	if got.Command != "plan" || len(got.Results) != 1 || got.Results[0].Status != chatops.StatusFailed {
+		t.Errorf("unexpected run report: %+v", got)
+	}
+}
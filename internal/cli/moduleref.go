@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// moduleRef is a module given by name on the command line. The name can be
+// qualified with parent directories (azurerm/storage-account), a module
+// directory or type (components:storage-account), or both, to pick one of
+// several modules with the same name.
+type moduleRef struct {
+	Dir  string // Module directory (components, bases, projects by default), empty for any
+	Path string // Slash-separated name with its optional parent directories
+	Name string // Directory name of the module
+}
+
+// parseModuleRef parses a module reference. The directory before ':' may be
+// given as a module directory or a module type, which stands for the first
+// module directory of that type.
+func parseModuleRef(ref string) (moduleRef, error) {
+	var r moduleRef
+	if dir, rest, ok := strings.Cut(ref, ":"); ok {
+		dirs := moduleDirNames()
+		if !slices.Contains(dirs, dir) {
+			dir = typeDir(dir, dir)
+		}
+		if !slices.Contains(dirs, dir) {
+			return r, fmt.Errorf("invalid module directory '%s' in '%s': must be %s", dir, ref, strings.Join(dirs, ", "))
+		}
+		r.Dir, ref = dir, rest
+	}
+	r.Path = strings.Trim(path.Clean("/"+strings.ReplaceAll(ref, `\`, "/")), "/")
+	r.Name = path.Base(r.Path)
+	if r.Path == "" || r.Name == "." || r.Name == ".." {
+		return r, fmt.Errorf("invalid module name '%s'", ref)
+	}
+	return r, nil
+}
+
+// String returns the reference as given to parseModuleRef.
+func (r moduleRef) String() string {
+	if r.Dir != "" {
+		return r.Dir + ":" + r.Path
+	}
+	return r.Path
+}
+
+// matches reports whether the module at relPath, a slash-separated path
+// relative to the root such as components/azurerm/storage-account, is the
+// referenced module. With roots, relPath starts with the module's root, and
+// the module directory of the reference is matched inside it.
+func (r moduleRef) matches(relPath string) bool {
+	if _, inRoot := splitRoot(relPath); r.Dir != "" && !strings.HasPrefix(inRoot, r.Dir+"/") {
+		return false
+	}
+	return relPath == r.Path || strings.HasSuffix(relPath, "/"+r.Path)
+}
+
+// qualifiedNames returns the shortest reference that selects only that module
+// for each of relPaths, slash-separated paths relative to the root of modules
+// with the same name: the name with as few parent directories as possible,
+// qualified with its module directory if that's needed to tell them apart.
+// Modules in different roots are told apart by the directories of their
+// roots, e.g. platform/components/storage-account.
+func qualifiedNames(relPaths []string) []string {
+	unique := func(r moduleRef) bool {
+		count := 0
+		for _, relPath := range relPaths {
+			if r.matches(relPath) {
+				count++
+			}
+		}
+		return count == 1
+	}
+
+	names := make([]string, len(relPaths))
+	for i, relPath := range relPaths {
+		root, inRoot := splitRoot(relPath)
+		dir, inDir, _ := strings.Cut(inRoot, "/")
+		names[i] = dir + ":" + inDir
+		if root != "" {
+			names[i] = relPath
+		}
+		// Parents inside the module directory come first, then those of
+		// the module directory and its root
+		segments := strings.Split(relPath, "/")
+		inDirSegments := strings.Count(inDir, "/") + 1
+		for n := 1; n <= len(segments); n++ {
+			suffix := strings.Join(segments[len(segments)-n:], "/")
+			if n > inDirSegments && root == "" {
+				break
+			}
+			if r := (moduleRef{Path: suffix}); unique(r) {
+				names[i] = r.String()
+				break
+			}
+			if r := (moduleRef{Dir: dir, Path: suffix}); n <= inDirSegments && unique(r) {
+				names[i] = r.String()
+				break
+			}
+		}
+	}
+	return names
+}
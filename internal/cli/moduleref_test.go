@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestParseModuleRef(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    moduleRef
+		wantErr bool
+	}{
+		{ref: "storage-account", want: moduleRef{Path: "storage-account", Name: "storage-account"}},
+		{ref: "azurerm/storage-account", want: moduleRef{Path: "azurerm/storage-account", Name: "storage-account"}},
+		{ref: "components:storage-account", want: moduleRef{Dir: "components", Path: "storage-account", Name: "storage-account"}},
+		{ref: "project:prod/", want: moduleRef{Dir: "projects", Path: "prod", Name: "prod"}},
+		{ref: `azurerm\storage-account`, want: moduleRef{Path: "azurerm/storage-account", Name: "storage-account"}},
+		{ref: "modules:storage-account", wantErr: true},
+		{ref: "components:", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := parseModuleRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseModuleRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseModuleRef() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseModuleRef_CustomModuleDirs(t *testing.T) {
+	withConfig(t, &config.Config{ModuleDirs: config.ModuleDirs{{Dir: "modules", Type: "component"}, {Dir: "live", Type: "project"}}})
+
+	tests := []struct {
+		ref     string
+		want    moduleRef
+		wantErr bool
+	}{
+		{ref: "modules:storage", want: moduleRef{Dir: "modules", Path: "storage", Name: "storage"}},
+		{ref: "project:prod", want: moduleRef{Dir: "live", Path: "prod", Name: "prod"}},
+		{ref: "components:storage", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := parseModuleRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseModuleRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseModuleRef() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModuleRef_Matches(t *testing.T) {
+	tests := []struct {
+		ref     string
+		relPath string
+		want    bool
+	}{
+		{"storage-account", "components/azurerm/storage-account", true},
+		{"azurerm/storage-account", "components/azurerm/storage-account", true},
+		{"aws/storage-account", "components/azurerm/storage-account", false},
+		{"rm/storage-account", "components/azurerm/storage-account", false},
+		{"components:storage-account", "components/azurerm/storage-account", true},
+		{"bases:storage-account", "components/azurerm/storage-account", false},
+		{"components/azurerm/storage-account", "components/azurerm/storage-account", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref+"_"+tt.relPath, func(t *testing.T) {
+			ref, err := parseModuleRef(tt.ref)
+			if err != nil {
+				t.Fatalf("parseModuleRef() error = %v", err)
+			}
+			if got := ref.matches(tt.relPath); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualifiedNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		relPaths []string
+		want     []string
+	}{
+		{
+			name:     "parent directory",
+			relPaths: []string{"components/azurerm/storage-account", "components/aws/storage-account"},
+			want:     []string{"azurerm/storage-account", "aws/storage-account"},
+		},
+		{
+			name:     "module directory",
+			relPaths: []string{"components/storage-account", "bases/storage-account"},
+			want:     []string{"components:storage-account", "bases:storage-account"},
+		},
+		{
+			name:     "same parents",
+			relPaths: []string{"components/azurerm/storage-account", "bases/azurerm/storage-account", "bases/storage-account"},
+			want:     []string{"components:storage-account", "bases:azurerm/storage-account", "bases:storage-account"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := qualifiedNames(tt.relPaths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("qualifiedNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualifiedNames_Roots(t *testing.T) {
+	withConfig(t, &config.Config{Roots: []string{"iac/platform", "iac/apps"}})
+
+	relPaths := []string{"iac/platform/components/storage", "iac/apps/components/storage", "iac/apps/bases/storage"}
+	want := []string{"platform/components/storage", "apps/components/storage", "bases:storage"}
+	if got := qualifiedNames(relPaths); !reflect.DeepEqual(got, want) {
+		t.Errorf("qualifiedNames() = %v, want %v", got, want)
+	}
+}
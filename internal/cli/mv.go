@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/adopt"
+	"github.com/TechnicallyJoe/terraform-motf/internal/backend"
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/modgraph"
+	"github.com/spf13/cobra"
+)
+
+// mvCmd represents the mv command
+var mvCmd = &cobra.Command{
+	Use:   "mv <module> <new-path>",
+	Short: "Move or rename a module and update the sources that point at it",
+	Long: `Move a module directory to a new path, relative to the root, and rewrite the
+local module sources that point at it, like source = "../../components/network"
+in projects, bases, and examples. Sources in the moved module that point out of
+it are rewritten too; only the source values change, so formatting and comments
+are preserved.
+
+Afterwards, the projects that use the module through local sources are listed,
+since they have to run init again. Moving a project can also move its state:
+when the backend section of .motf.yml uses {path} or {project}, or the
+project's files mention its old path, the state has to be migrated, and modules
+that read its state with terraform_remote_state may need updating. These steps
+are listed, not run.
+
+Use --dry-run to show the move and the rewritten sources without changing
+anything.`,
+	Example: `  motf mv storage-account components/azurerm/storage     # Move a component
+  motf mv network projects/platform-network               # Rename a project
+  motf mv storage-account components/storage --dry-run   # Show what would change`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runMv,
+}
+
+func init() {
+	rootCmd.AddCommand(mvCmd)
+}
+
+func runMv(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modulePath, err := findModuleInAllDirs(args[0])
+	if err != nil {
+		return err
+	}
+	from := displayPath(basePath, modulePath)
+	to := path.Clean(filepath.ToSlash(args[1]))
+	if err := checkMvDestination(basePath, from, to); err != nil {
+		return err
+	}
+	if moduleDirType(filepath.Join(basePath, filepath.FromSlash(to))) == "" {
+		logging.Warnf("%s isn't in a module directory (%s), so motf won't find the module there", to, strings.Join(moduleDirNames(), ", "))
+	}
+
+	cmd.SilenceUsage = true
+	out := cmd.OutOrStdout()
+	plan := &adopt.Plan{Moves: []adopt.Move{{From: from, To: to}}}
+	rewrites, err := adopt.FindRewrites(basePath, plan)
+	if err != nil {
+		return err
+	}
+	reinit, state, err := mvFollowUps(basePath, modulePath, plan, rewrites)
+	if err != nil {
+		return err
+	}
+
+	updated := "Updated"
+	if dryRunFlag {
+		_, _ = fmt.Fprintf(out, "[dry-run] Would move %s to %s\n", from, to)
+		updated = "Would update"
+	} else {
+		dest := filepath.Join(basePath, filepath.FromSlash(to))
+		if err := moveDir(basePath, modulePath, dest); err != nil {
+			return fmt.Errorf("failed to move %s to %s: %w", from, to, err)
+		}
+		removeEmptyParents(basePath, filepath.Dir(modulePath))
+		if err := adopt.ApplyRewrites(basePath, rewrites); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "Moved %s to %s\n", from, to)
+	}
+	if len(rewrites) > 0 {
+		_, _ = fmt.Fprintf(out, "\n%s %d module source(s):\n", updated, len(rewrites))
+		for _, r := range rewrites {
+			_, _ = fmt.Fprintf(out, "  %s (module.%s): %s -> %s\n", r.File, r.Module, r.From, r.To)
+		}
+	}
+	if len(reinit) > 0 {
+		_, _ = fmt.Fprintln(out, "\nRun init again in these projects, since the sources of their modules changed:")
+		for _, project := range reinit {
+			_, _ = fmt.Fprintf(out, "  %s\n", project)
+		}
+	}
+	if len(state) > 0 {
+		_, _ = fmt.Fprintln(out, "\nState:")
+		for _, step := range state {
+			_, _ = fmt.Fprintf(out, "  %s\n", step)
+		}
+	}
+	return nil
+}
+
+// checkMvDestination returns an error if the module directory from can't be
+// moved to to: a new path inside the root, outside from.
+func checkMvDestination(basePath, from, to string) error {
+	if path.IsAbs(to) || !filepath.IsLocal(filepath.FromSlash(to)) {
+		return fmt.Errorf("invalid destination '%s': must be a path inside the root", to)
+	}
+	if to == from {
+		return fmt.Errorf("%s is already at %s", from, to)
+	}
+	if strings.HasPrefix(to, from+"/") {
+		return fmt.Errorf("can't move %s into itself", from)
+	}
+	if _, err := os.Stat(filepath.Join(basePath, filepath.FromSlash(to))); err == nil {
+		return fmt.Errorf("%s already exists", to)
+	}
+	return nil
+}
+
+// mvFollowUps returns the projects that have to run init again after the
+// module at modulePath moves as planned with rewrites, and the state steps
+// it needs.
+func mvFollowUps(basePath, modulePath string, plan *adopt.Plan, rewrites []adopt.Rewrite) (reinit, state []string, err error) {
+	from, to := plan.Moves[0].From, plan.Moves[0].To
+	modules, err := collectModules(basePath, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	paths := make([]string, 0, len(modules))
+	for _, mod := range modules {
+		paths = append(paths, filepath.ToSlash(mod.Path))
+	}
+	if !slices.Contains(paths, from) {
+		paths = append(paths, from)
+	}
+	graph, err := modgraph.Build(basePath, paths)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build the module graph: %w", err)
+	}
+
+	for _, consumer := range graph.Consumers([]string{from}, -1) {
+		if moduleDirType(filepath.Join(basePath, filepath.FromSlash(consumer))) == TypeProject {
+			reinit = append(reinit, plan.MapPath(consumer))
+		}
+	}
+
+	if moduleDirType(modulePath) != TypeProject {
+		return reinit, nil, nil
+	}
+	if slices.ContainsFunc(rewrites, func(r adopt.Rewrite) bool { return path.Dir(r.File) == to }) {
+		reinit = append(reinit, to)
+	}
+	moves, err := projectStateMoves(modulePath, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if moves != "" {
+		state = append(state, fmt.Sprintf("%s: %s, then run 'terraform init -migrate-state' in %s", to, moves, to))
+	}
+	for _, reader := range graph.StateReaders(from) {
+		state = append(state, fmt.Sprintf("%s reads the state of %s with terraform_remote_state: update its config if it refers to the old path", plan.MapPath(reader), from))
+	}
+	return reinit, state, nil
+}
+
+// projectStateMoves returns why the state of the project at projectPath
+// moves when it moves from from to to, or "" if it doesn't seem to: the
+// backend section generates different files for the new path, or the
+// project's Terraform files mention its old path, e.g. in a backend key.
+func projectStateMoves(projectPath, from, to string) (string, error) {
+	if cfg.Backend != nil {
+		files := func(p string) (map[string][]byte, error) {
+			params := backend.Params{Project: path.Base(p), Path: p}
+			return backend.Files(cfg.Backend.Type, cfg.Backend.Config, params, cfg.Backend.GetEnvironments(), cfg.Backend.GetFile(), cfg.Backend.GetConfigDir())
+		}
+		before, beforeErr := files(from)
+		after, afterErr := files(to)
+		switch {
+		case beforeErr != nil || afterErr != nil:
+			// e.g. {env} without environments; the files are compared when they can be generated
+			logging.Debugf("can't compare the backend of %s and %s: %v", from, to, errors.Join(beforeErr, afterErr))
+		case !maps.EqualFunc(before, after, bytes.Equal):
+			return fmt.Sprintf("its backend settings use its path or name, run 'motf backend init %s'", path.Base(to)), nil
+		}
+	}
+
+	entries, err := os.ReadDir(projectPath)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !finder.IsTerraformFile(entry.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(projectPath, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		if bytes.Contains(data, []byte(from)) {
+			return fmt.Sprintf("%s mentions its old path, e.g. in a backend key; update it", entry.Name()), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func runMvCmd(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	err := runMv(cmd, args)
+	return out.String(), err
+}
+
+// mvRepo creates a repository where projects/platform calls
+// components/network and projects/app reads the state of projects/platform.
+func mvRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	withConfig(t, &config.Config{Root: root})
+	withWorkingDir(t, root)
+	createTerraformModule(t, root, filepath.Join(DirComponents, "network"))
+	platform := createTerraformModule(t, root, filepath.Join(DirProjects, "platform"))
+	app := createTerraformModule(t, root, filepath.Join(DirProjects, "app"))
+	if err := os.WriteFile(filepath.Join(platform, "network.tf"), []byte("module \"network\" {\n  source = \"../../components/network\"\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(platform, "backend.tf"), []byte("terraform {\n  backend \"s3\" {\n    key = \"projects/platform/terraform.tfstate\"\n  }\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := "data \"terraform_remote_state\" \"platform\" {\n  backend = \"s3\"\n  config = {\n    key = \"projects/platform/terraform.tfstate\"\n  }\n}\n"
+	if err := os.WriteFile(filepath.Join(app, "state.tf"), []byte(state), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestMv_Component(t *testing.T) {
+	resetFlags(t)
+	root := mvRepo(t)
+
+	out, err := runMvCmd(t, "network", "components/azure/vnet")
+	if err != nil {
+		t.Fatalf("runMv() error = %v", err)
+	}
+	for _, want := range []string{
+		"Moved components/network to components/azure/vnet\n",
+		"Updated 1 module source(s):\n  projects/platform/network.tf (module.network): ../../components/network -> ../../components/azure/vnet\n",
+		"Run init again in these projects, since the sources of their modules changed:\n  projects/platform\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "State:") {
+		t.Errorf("moving a component doesn't move state:\n%s", out)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "projects", "platform", "network.tf"))
+	if err != nil || !strings.Contains(string(data), `source = "../../components/azure/vnet"`) {
+		t.Errorf("source not rewritten: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "components", "azure", "vnet", "main.tf")); err != nil {
+		t.Errorf("module not moved: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "components", "network")); !os.IsNotExist(err) {
+		t.Error("the old directory still exists")
+	}
+}
+
+func TestMv_ProjectState(t *testing.T) {
+	resetFlags(t)
+	root := mvRepo(t)
+	dryRunFlag = true
+
+	out, err := runMvCmd(t, "platform", "projects/core")
+	if err != nil {
+		t.Fatalf("runMv() error = %v", err)
+	}
+	for _, want := range []string{
+		"[dry-run] Would move projects/platform to projects/core\n",
+		"projects/core: backend.tf mentions its old path, e.g. in a backend key; update it, then run 'terraform init -migrate-state' in projects/core\n",
+		"projects/app reads the state of projects/platform with terraform_remote_state",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "module source(s)") {
+		t.Errorf("sources at the same depth don't change:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(root, "projects", "platform")); err != nil {
+		t.Error("dry run moved the project")
+	}
+}
+
+func TestMv_BackendTemplate(t *testing.T) {
+	resetFlags(t)
+	mvRepo(t)
+	cfg.Backend = &config.BackendConfig{Type: "s3", Config: map[string]any{"bucket": "tfstate", "key": "{path}/terraform.tfstate", "region": "eu-west-1"}}
+	dryRunFlag = true
+
+	out, err := runMvCmd(t, "platform", "projects/core")
+	if err != nil {
+		t.Fatalf("runMv() error = %v", err)
+	}
+	if !strings.Contains(out, "projects/core: its backend settings use its path or name, run 'motf backend init core'") {
+		t.Errorf("output is missing the backend step:\n%s", out)
+	}
+}
+
+func TestMv_Errors(t *testing.T) {
+	resetFlags(t)
+	mvRepo(t)
+
+	if _, err := runMvCmd(t, "network", "projects/platform"); err == nil || !strings.Contains(err.Error(), "projects/platform already exists") {
+		t.Errorf("error = %v, want already exists", err)
+	}
+	if _, err := runMvCmd(t, "network", "../elsewhere"); err == nil || !strings.Contains(err.Error(), "must be a path inside the root") {
+		t.Errorf("error = %v, want outside the root", err)
+	}
+	if _, err := runMvCmd(t, "missing", "components/missing-2"); err == nil {
+		t.Error("expected an error for a module that doesn't exist")
+	}
+}
@@ -38,12 +38,19 @@ func colorForIndex(index int) string {
 // and timestamp to each line of output.
 //
 // Format: <color><module-name> |</color> HH:mm:ss.SSS # <message>
+//
+// Without color (not a terminal, --no-color, or NO_COLOR) the prefix is the
+// same without the color codes. In plain mode (--plain) the prefix is
+// uncolored, unpadded, and has no timestamp:
+//
+// Format: <module-name>: <message>
 type prefixedWriter struct {
 	out        io.Writer
 	mu         *sync.Mutex
 	buf        bytes.Buffer
 	timeFunc   func() time.Time // for testing
 	linePrefix string           // cached formatted prefix without timestamp
+	plain      bool             // omit the timestamp
 }
 
 // newPrefixedWriter creates a new prefixedWriter.
@@ -52,11 +59,24 @@ type prefixedWriter struct {
 // colorIndex: index into color palette
 // out: the underlying writer
 // mu: mutex for thread-safe writing (shared across all writers)
-func newPrefixedWriter(moduleName string, maxNameLen int, colorIndex int, out io.Writer, mu *sync.Mutex) *prefixedWriter {
-	color := colorForIndex(colorIndex)
+// color: whether to color the prefix, see colorOutput
+func newPrefixedWriter(moduleName string, maxNameLen int, colorIndex int, out io.Writer, mu *sync.Mutex, color bool) *prefixedWriter {
+	if plainFlag {
+		return &prefixedWriter{
+			out:        out,
+			linePrefix: moduleName + ": ",
+			mu:         mu,
+			timeFunc:   time.Now,
+			plain:      true,
+		}
+	}
+
 	// Pad the module name to align the | character
 	paddedName := fmt.Sprintf("%-*s", maxNameLen, moduleName)
-	linePrefix := fmt.Sprintf("%s%s |%s ", color, paddedName, colorReset)
+	linePrefix := paddedName + " | "
+	if color {
+		linePrefix = fmt.Sprintf("%s%s |%s ", colorForIndex(colorIndex), paddedName, colorReset)
+	}
 
 	return &prefixedWriter{
 		out:        out,
@@ -104,8 +124,13 @@ func (w *prefixedWriter) Flush() error {
 
 // writeLine writes a single line with prefix and timestamp
 func (w *prefixedWriter) writeLine(line []byte) error {
-	timestamp := w.timeFunc().Format("15:04:05.000")
-	formatted := fmt.Sprintf("%s%s # %s", w.linePrefix, timestamp, string(line))
+	var formatted string
+	if w.plain {
+		formatted = w.linePrefix + string(line)
+	} else {
+		timestamp := w.timeFunc().Format("15:04:05.000")
+		formatted = fmt.Sprintf("%s%s # %s", w.linePrefix, timestamp, string(line))
+	}
 
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -119,11 +144,12 @@ type prefixedWriterPair struct {
 	stderr *prefixedWriter
 }
 
-// newPrefixedWriterPair creates stdout and stderr writers for a module
+// newPrefixedWriterPair creates stdout and stderr writers for a module, each
+// colored if it goes to a terminal
 func newPrefixedWriterPair(moduleName string, maxNameLen int, colorIndex int, stdout, stderr io.Writer, mu *sync.Mutex) *prefixedWriterPair {
 	return &prefixedWriterPair{
-		stdout: newPrefixedWriter(moduleName, maxNameLen, colorIndex, stdout, mu),
-		stderr: newPrefixedWriter(moduleName, maxNameLen, colorIndex, stderr, mu),
+		stdout: newPrefixedWriter(moduleName, maxNameLen, colorIndex, stdout, mu, colorOutput(stdout)),
+		stderr: newPrefixedWriter(moduleName, maxNameLen, colorIndex, stderr, mu, colorOutput(stderr)),
 	}
 }
 
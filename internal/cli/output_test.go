@@ -39,7 +39,7 @@ func TestPrefixedWriter_Write(t *testing.T) {
 
 	// Create a writer with a fixed time for testing
 	fixedTime := time.Date(2025, 1, 31, 14, 32, 1, 123000000, time.UTC)
-	pw := newPrefixedWriter("storage-account", 15, 0, &buf, mu)
+	pw := newPrefixedWriter("storage-account", 15, 0, &buf, mu, true)
 	pw.timeFunc = func() time.Time { return fixedTime }
 
 	// Write a complete line
@@ -76,7 +76,7 @@ func TestPrefixedWriter_WriteMultipleLines(t *testing.T) {
 	mu := &sync.Mutex{}
 
 	fixedTime := time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)
-	pw := newPrefixedWriter("mod", 5, 0, &buf, mu)
+	pw := newPrefixedWriter("mod", 5, 0, &buf, mu, true)
 	pw.timeFunc = func() time.Time { return fixedTime }
 
 	// Write multiple lines at once
@@ -107,7 +107,7 @@ func TestPrefixedWriter_WritePartialLines(t *testing.T) {
 	mu := &sync.Mutex{}
 
 	fixedTime := time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)
-	pw := newPrefixedWriter("test", 10, 0, &buf, mu)
+	pw := newPrefixedWriter("test", 10, 0, &buf, mu, true)
 	pw.timeFunc = func() time.Time { return fixedTime }
 
 	// Write partial content (no newline)
@@ -136,7 +136,7 @@ func TestPrefixedWriter_Flush(t *testing.T) {
 	mu := &sync.Mutex{}
 
 	fixedTime := time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)
-	pw := newPrefixedWriter("test", 10, 0, &buf, mu)
+	pw := newPrefixedWriter("test", 10, 0, &buf, mu, true)
 	pw.timeFunc = func() time.Time { return fixedTime }
 
 	// Write partial content without newline
@@ -167,10 +167,10 @@ func TestPrefixedWriter_Alignment(t *testing.T) {
 	fixedTime := time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)
 
 	// Create two writers with different name lengths but same maxNameLen
-	pw1 := newPrefixedWriter("short", 15, 0, &buf, mu)
+	pw1 := newPrefixedWriter("short", 15, 0, &buf, mu, true)
 	pw1.timeFunc = func() time.Time { return fixedTime }
 
-	pw2 := newPrefixedWriter("very-long-name", 15, 1, &buf, mu)
+	pw2 := newPrefixedWriter("very-long-name", 15, 1, &buf, mu, true)
 	pw2.timeFunc = func() time.Time { return fixedTime }
 
 	if _, err := pw1.Write([]byte("message 1\n")); err != nil {
@@ -235,7 +235,7 @@ func TestPrefixedWriter_ConcurrentWrites(t *testing.T) {
 	// Create multiple writers sharing the same mutex
 	writers := make([]*prefixedWriter, 5)
 	for i := range writers {
-		writers[i] = newPrefixedWriter(fmt.Sprintf("mod-%d", i), 5, i, &buf, mu)
+		writers[i] = newPrefixedWriter(fmt.Sprintf("mod-%d", i), 5, i, &buf, mu, true)
 		writers[i].timeFunc = func() time.Time { return time.Now() }
 	}
 
@@ -4,15 +4,38 @@ import (
 	"errors"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
 )
 
+// addParallelFlags registers the flags shared by commands that can run on
+// multiple modules (--parallel, --max-parallel, --log-dir, --timeout,
+// --fail-fast, --no-progress).
+func addParallelFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
+	cmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	cmd.Flags().StringVar(&logDirFlag, "log-dir", "", "Write each module's full output to <log-dir>/<module>.log")
+	cmd.Flags().DurationVar(&timeoutFlag, "timeout", 0, "Stop and fail each module that runs longer than this, e.g. 30m; 0 for no limit (default: parallelism.timeout)")
+	cmd.Flags().BoolVar(&failFastFlag, "fail-fast", false, "Stop the remaining modules as soon as one fails, reporting them as skipped")
+	cmd.Flags().BoolVar(&noProgressFlag, "no-progress", false, "Stream output instead of showing live progress of parallel runs on a terminal")
+}
+
 // ModuleRunner is a function that runs a command on a module
 // with the given stdout and stderr writers.
 type ModuleRunner func(mod ModuleInfo, stdout, stderr io.Writer) error
 
+// moduleResult records the outcome of running a command on a single module
+type moduleResult struct {
+	module   ModuleInfo
+	err      error
+	duration time.Duration
+}
+
 // runOnModules executes fn on each module, either sequentially or in parallel
 // based on the parallel flag. When parallel is true, it uses a worker pool
 // with bounded concurrency.
@@ -25,76 +48,209 @@ type ModuleRunner func(mod ModuleInfo, stdout, stderr io.Writer) error
 //   - errOut: error output writer (typically os.Stderr)
 //   - fn: function to run on each module
 //
-// Returns combined errors from all failed modules (does not fail fast).
+// Returns combined errors from all failed modules (does not fail fast;
+// RunOnModulesParallel stops the remaining modules with --fail-fast).
+// Non-fatal errors (quarantined failures, flaky passes, skipped modules) are
+// recorded in the results but not returned.
 func runOnModules(modules []ModuleInfo, parallel bool, maxJobs int, out, errOut io.Writer, fn ModuleRunner) error {
+	_, err := runOnModulesWithResults(modules, parallel, maxJobs, out, errOut, fn)
+	return err
+}
+
+// runOnModulesWithResults behaves like runOnModules but also returns the
+// outcome of each module, in the same order as modules.
+func runOnModulesWithResults(modules []ModuleInfo, parallel bool, maxJobs int, out, errOut io.Writer, fn ModuleRunner) ([]moduleResult, error) {
 	if len(modules) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Calculate max name length for alignment
+	labels := moduleLabels(modules)
 	maxNameLen := 0
-	for _, mod := range modules {
-		if len(mod.Name) > maxNameLen {
-			maxNameLen = len(mod.Name)
+	for _, label := range labels {
+		if len(label) > maxNameLen {
+			maxNameLen = len(label)
 		}
 	}
 
+	var results []moduleResult
 	if !parallel {
-		return runSequential(modules, maxNameLen, out, errOut, fn)
+		results = runSequential(modules, labels, maxNameLen, out, errOut, fn)
+	} else {
+		results = runParallel(modules, maxJobs, labels, maxNameLen, out, errOut, fn)
 	}
 
-	return runParallel(modules, maxJobs, maxNameLen, out, errOut, fn)
-}
-
-// runSequential runs fn on each module one at a time
-func runSequential(modules []ModuleInfo, maxNameLen int, out, errOut io.Writer, fn ModuleRunner) error {
 	var errs []error
-	mu := &sync.Mutex{} // For consistent output even in sequential mode
+	for _, r := range results {
+		if r.err != nil && !isNonFatal(r.err) {
+			errs = append(errs, &moduleError{module: r.module, err: r.err})
+		}
+	}
+	return results, errors.Join(errs...)
+}
 
+// moduleLabels returns the output prefix of each module: its name, or if
+// other modules of the run have the same name (e.g. in another root), its
+// qualified name.
+func moduleLabels(modules []ModuleInfo) []string {
+	byName := make(map[string][]int)
 	for i, mod := range modules {
-		writers := newPrefixedWriterPair(mod.Name, maxNameLen, i, out, errOut, mu)
-		if err := fn(mod, writers.stdout, writers.stderr); err != nil {
-			errs = append(errs, &moduleError{module: mod, err: err})
+		byName[mod.Name] = append(byName[mod.Name], i)
+	}
+	labels := make([]string, len(modules))
+	for name, indexes := range byName {
+		if len(indexes) == 1 {
+			labels[indexes[0]] = name
+			continue
+		}
+		relPaths := make([]string, len(indexes))
+		for i, index := range indexes {
+			relPaths[i] = filepath.ToSlash(modules[index].Path)
+		}
+		for i, qualified := range qualifiedNames(relPaths) {
+			labels[indexes[i]] = qualified
 		}
-		_ = writers.Flush()
 	}
+	return labels
+}
 
-	return errors.Join(errs...)
+// runModule runs fn on a single module with writers prefixed by label and records the outcome
+func runModule(mod ModuleInfo, label string, index, maxNameLen int, out, errOut io.Writer, mu *sync.Mutex, fn ModuleRunner) moduleResult {
+	writers := newPrefixedWriterPair(label, maxNameLen, index, out, errOut, mu)
+	start := time.Now()
+	err := fn(mod, writers.stdout, writers.stderr)
+	_ = writers.Flush()
+	return moduleResult{module: mod, err: err, duration: time.Since(start)}
 }
 
-// runParallel runs fn on modules concurrently with bounded parallelism
-func runParallel(modules []ModuleInfo, maxJobs int, maxNameLen int, out, errOut io.Writer, fn ModuleRunner) error {
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var errs []error
+// runSequential runs fn on each module one at a time, in order, except that
+// a module runs after the modules it depends on.
+func runSequential(modules []ModuleInfo, labels []string, maxNameLen int, out, errOut io.Writer, fn ModuleRunner) []moduleResult {
+	results := make([]moduleResult, len(modules))
+	mu := &sync.Mutex{} // For consistent output even in sequential mode
 
-	// Semaphore channel for bounded concurrency
-	sem := make(chan struct{}, maxJobs)
+	order := make([]int, len(modules))
+	for i := range order {
+		order[i] = i
+	}
+	graph := newRunGraph(modules, order)
+	for index, ok := graph.next(); ok; index, ok = graph.next() {
+		results[index] = runModule(modules[index], labels[index], index, maxNameLen, out, errOut, mu, fn)
+		graph.finish(index)
+	}
+
+	return results
+}
+
+// runParallel runs fn on modules concurrently with bounded parallelism.
+// A module starts once the modules it depends on finished; modules that can
+// start do so in scheduleOrder. Results keep the order of modules.
+func runParallel(modules []ModuleInfo, maxJobs int, labels []string, maxNameLen int, out, errOut io.Writer, fn ModuleRunner) []moduleResult {
+	results := make([]moduleResult, len(modules))
+	graph := newRunGraph(modules, scheduleOrder(modules))
 
 	// Shared mutex for output synchronization
 	outputMu := &sync.Mutex{}
 
+	finished := make(chan int)
+	running := 0
+	for {
+		for running < max(maxJobs, 1) {
+			index, ok := graph.next()
+			if !ok {
+				break
+			}
+			running++
+			go func() {
+				// Each module writes only its own slot, so no locking is needed
+				results[index] = runModule(modules[index], labels[index], index, maxNameLen, out, errOut, outputMu, fn)
+				finished <- index
+			}()
+		}
+		if running == 0 {
+			break
+		}
+		graph.finish(<-finished)
+		running--
+	}
+
+	return results
+}
+
+// runGraph tracks which modules of a run can start: those whose
+// dependencies in the run (DependsOn) finished.
+type runGraph struct {
+	rank       []int   // Position of each module in the start order
+	waiting    []int   // Unfinished dependencies of each module
+	dependents [][]int // Modules that depend on each module
+	ready      []int   // Modules that can start, by rank
+}
+
+// newRunGraph creates the graph of modules, which start in order when they
+// can. Dependencies on modules that aren't part of the run are ignored.
+func newRunGraph(modules []ModuleInfo, order []int) *runGraph {
+	g := &runGraph{
+		rank:       make([]int, len(modules)),
+		waiting:    make([]int, len(modules)),
+		dependents: make([][]int, len(modules)),
+	}
+	byPath := make(map[string]int, len(modules))
 	for i, mod := range modules {
-		wg.Add(1)
-		go func(index int, m ModuleInfo) {
-			defer wg.Done()
-
-			// Acquire semaphore
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			writers := newPrefixedWriterPair(m.Name, maxNameLen, index, out, errOut, outputMu)
-			if err := fn(m, writers.stdout, writers.stderr); err != nil {
-				mu.Lock()
-				errs = append(errs, &moduleError{module: m, err: err})
-				mu.Unlock()
+		byPath[mod.Path] = i
+	}
+	for i, mod := range modules {
+		for _, dep := range mod.DependsOn {
+			if d, ok := byPath[dep]; ok {
+				g.waiting[i]++
+				g.dependents[d] = append(g.dependents[d], i)
 			}
-			_ = writers.Flush()
-		}(i, mod)
+		}
+	}
+	for rank, index := range order {
+		g.rank[index] = rank
+		if g.waiting[index] == 0 {
+			g.ready = append(g.ready, index)
+		}
 	}
+	return g
+}
+
+// next returns the module to start next, if any can start.
+func (g *runGraph) next() (int, bool) {
+	if len(g.ready) == 0 {
+		return 0, false
+	}
+	index := g.ready[0]
+	g.ready = g.ready[1:]
+	return index, true
+}
+
+// finish marks a module as finished, so the modules depending on it can
+// start once their other dependencies finished too.
+func (g *runGraph) finish(index int) {
+	for _, dependent := range g.dependents[index] {
+		g.waiting[dependent]--
+		if g.waiting[dependent] == 0 {
+			g.ready = append(g.ready, dependent)
+		}
+	}
+	sort.SliceStable(g.ready, func(i, j int) bool {
+		return g.rank[g.ready[i]] < g.rank[g.ready[j]]
+	})
+}
 
-	wg.Wait()
-	return errors.Join(errs...)
+// scheduleOrder returns the indexes of modules in the order they should
+// start: longest timeout first, so a slow module doesn't start last and
+// stretch the run, then in their original order.
+func scheduleOrder(modules []ModuleInfo) []int {
+	order := make([]int, len(modules))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return modules[order[i]].Timeout > modules[order[j]].Timeout
+	})
+	return order
 }
 
 // moduleError wraps an error with module context
@@ -118,5 +274,80 @@ func (e *moduleError) Unwrap() error {
 // Note: CLI flags are merged into config during PersistentPreRunE,
 // so parallelismCfg already reflects any --max-parallel override.
 func RunOnModulesParallel(modules []ModuleInfo, parallelismCfg *config.ParallelismConfig, fn ModuleRunner) error {
-	return runOnModules(modules, parallelFlag, parallelismCfg.GetMaxJobs(), os.Stdout, os.Stderr, fn)
+	logs, err := newModuleLogs(parallelismCfg.GetLogDir(), modules)
+	if err != nil {
+		return err
+	}
+
+	injector, err := parseFailureInjection(injectFailureFlag)
+	if err != nil {
+		return err
+	}
+
+	progress, err := newProgressEvents(progressJSONFlag, modules)
+	if err != nil {
+		return err
+	}
+
+	timeouts, err := newModuleTimeouts(parallelismCfg.GetTimeout(), failFastFlag)
+	if err != nil {
+		return err
+	}
+
+	run := injector.wrap(fn)
+	if retriedCommands[commandName] {
+		run = withRunRetries(run, parallelismCfg.GetRetries(), parallelismCfg.GetRetryDelay())
+	}
+
+	dependencies := newDependencyOutcomes()
+	capture := newOutputCapture(chatopsFlag != "" || len(testReportFlags) > 0)
+	display := newProgressDisplay(progressDisplayEnabled(modules), modules, os.Stderr)
+	display.start()
+	start := now()
+	results, err := runOnModulesWithResults(modules, parallelFlag, parallelismCfg.GetMaxJobs(), os.Stdout, os.Stderr, display.wrap(progress.wrap(capture.wrap(logs.wrap(timeouts.wrap(dependencies.wrap(run)))))))
+	display.stop()
+	recordModuleMetrics(results)
+
+	if summaryErr := reportRunSummary(results, now().Sub(start)); summaryErr != nil {
+		return errors.Join(err, summaryErr)
+	}
+
+	if progressErr := progress.finish(results); progressErr != nil {
+		return errors.Join(err, progressErr)
+	}
+
+	if reportErr := logs.writeReport(buildChatopsSummary(commandName, results, nil)); reportErr != nil {
+		return errors.Join(err, reportErr)
+	}
+
+	if chatopsFlag != "" {
+		if writeErr := writeChatopsPayload(results, capture); writeErr != nil {
+			return errors.Join(err, writeErr)
+		}
+	}
+
+	if reportErr := writeTestReports(results, capture); reportErr != nil {
+		return errors.Join(err, reportErr)
+	}
+
+	if err == nil {
+		err = planChanges(results)
+	}
+	return err
+}
+
+// planChanges returns a planChangesError when plans of any of the modules
+// have changes, or nil.
+func planChanges(results []moduleResult) error {
+	count := 0
+	for _, r := range results {
+		var changes *planChangesError
+		if errors.As(r.err, &changes) {
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	return &planChangesError{modules: count}
 }
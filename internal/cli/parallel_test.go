@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
 )
 
 func TestRunOnModules_Empty(t *testing.T) {
@@ -57,6 +61,30 @@ func TestRunOnModules_Sequential(t *testing.T) {
 	}
 }
 
+func TestRunOnModules_PrefixesClashingNames(t *testing.T) {
+	withConfig(t, &config.Config{Roots: []string{"platform", "apps"}})
+	var buf bytes.Buffer
+	modules := []ModuleInfo{
+		{Name: "storage", Path: "platform/components/storage"},
+		{Name: "storage", Path: "apps/components/storage"},
+		{Name: "network", Path: "apps/bases/network"},
+	}
+
+	err := runOnModules(modules, false, 1, &buf, &buf, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		_, _ = stdout.Write([]byte("done\n"))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, want := range []string{"platform/components/storage | ", "apps/components/storage     | ", "network                     | "} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
 func TestRunOnModules_Parallel(t *testing.T) {
 	var buf bytes.Buffer
 	modules := []ModuleInfo{
@@ -200,3 +228,86 @@ func TestModuleError(t *testing.T) {
 		t.Errorf("Unwrap() should return original error")
 	}
 }
+
+func TestRunOnModulesWithResults_PreservesOrder(t *testing.T) {
+	var buf bytes.Buffer
+	modules := []ModuleInfo{
+		{Name: "slow", Path: "path/to/slow"},
+		{Name: "fast", Path: "path/to/fast"},
+	}
+
+	results, err := runOnModulesWithResults(modules, true, 2, &buf, &buf, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		if mod.Name == "slow" {
+			time.Sleep(20 * time.Millisecond)
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	if err == nil {
+		t.Fatal("expected error from failing module")
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].module.Name != "slow" || results[1].module.Name != "fast" {
+		t.Errorf("expected results in module order, got %s, %s", results[0].module.Name, results[1].module.Name)
+	}
+	if results[0].err == nil || results[1].err != nil {
+		t.Errorf("unexpected errors: %v, %v", results[0].err, results[1].err)
+	}
+	if results[0].duration < 20*time.Millisecond {
+		t.Errorf("expected duration to be recorded, got %s", results[0].duration)
+	}
+}
+
+func TestScheduleOrder_LongestTimeoutFirst(t *testing.T) {
+	modules := []ModuleInfo{
+		{Name: "dns"},
+		{Name: "aks", Timeout: 15 * time.Minute},
+		{Name: "vnet"},
+		{Name: "sql", Timeout: 5 * time.Minute},
+	}
+
+	var names []string
+	for _, i := range scheduleOrder(modules) {
+		names = append(names, modules[i].Name)
+	}
+	if got := strings.Join(names, ","); got != "aks,sql,dns,vnet" {
+		t.Errorf("scheduleOrder() = %s, want aks,sql,dns,vnet", got)
+	}
+}
+
+func TestRunOnModules_ParallelStartsHeavyModulesFirst(t *testing.T) {
+	var buf bytes.Buffer
+	modules := []ModuleInfo{
+		{Name: "dns"},
+		{Name: "vnet"},
+		{Name: "aks", Timeout: 15 * time.Minute},
+	}
+
+	var mu sync.Mutex
+	var started []string
+	_, err := runOnModulesWithResults(modules, true, 1, &buf, &buf, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		mu.Lock()
+		defer mu.Unlock()
+		started = append(started, mod.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(started, ","); got != "aks,dns,vnet" {
+		t.Errorf("start order = %s, want aks,dns,vnet", got)
+	}
+}
+
+func TestAddParallelFlags(t *testing.T) {
+	for _, cmd := range []*cobra.Command{initCmd, fmtCmd, valCmd, planCmd, testCmd, taskCmd} {
+		for _, name := range []string{"parallel", "max-parallel", "log-dir", "timeout", "fail-fast", "no-progress"} {
+			if cmd.Flags().Lookup(name) == nil {
+				t.Errorf("%s: expected --%s flag to be registered", cmd.Name(), name)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// EnvPlain enables plain output when set to a truthy value (1, true, yes, on)
+const EnvPlain = "MOTF_PLAIN"
+
+// plainFlag selects screen-reader friendly output: no color, no aligned
+// columns, and one labeled value per line.
+var plainFlag bool
+
+// applyPlainEnv enables plain output from MOTF_PLAIN unless --plain was set explicitly.
+func applyPlainEnv(cmd *cobra.Command, getenv func(string) string) {
+	if cmd.Flags().Changed("plain") {
+		return
+	}
+	if isTruthy(getenv(EnvPlain)) {
+		plainFlag = true
+	}
+}
+
+// isTruthy reports whether an environment value means "enabled".
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// printField prints a "Label: value" line. The value is aligned to width
+// columns unless plain output is enabled.
+func printField(label string, width int, value string) {
+	if plainFlag {
+		fmt.Printf("%s: %s\n", label, value)
+		return
+	}
+	fmt.Printf("%-*s %s\n", width, label+":", value)
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&plainFlag, "plain", false, "Plain output for screen readers and narrow terminals: no color, no aligned columns (env: MOTF_PLAIN)")
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+func TestIsTruthy(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"1", true},
+		{"true", true},
+		{"TRUE", true},
+		{"yes", true},
+		{"on", true},
+		{"", false},
+		{"0", false},
+		{"false", false},
+		{"no", false},
+	}
+
+	for _, tt := range tests {
+		if got := isTruthy(tt.value); got != tt.want {
+			t.Errorf("isTruthy(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestApplyPlainEnv(t *testing.T) {
+	resetFlags(t)
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().BoolVar(&plainFlag, "plain", false, "")
+		return cmd
+	}
+	env := func(value string) func(string) string {
+		return func(key string) string {
+			if key == EnvPlain {
+				return value
+			}
+			return ""
+		}
+	}
+
+	applyPlainEnv(newCmd(), env("1"))
+	if !plainFlag {
+		t.Error("expected MOTF_PLAIN=1 to enable plain output")
+	}
+
+	cmd := newCmd()
+	if err := cmd.Flags().Set("plain", "false"); err != nil {
+		t.Fatalf("failed to set flag: %v", err)
+	}
+	applyPlainEnv(cmd, env("1"))
+	if plainFlag {
+		t.Error("expected --plain=false to override MOTF_PLAIN")
+	}
+
+	applyPlainEnv(newCmd(), env(""))
+	if plainFlag {
+		t.Error("expected plain output to stay disabled without MOTF_PLAIN")
+	}
+}
+
+func TestPrefixedWriter_Plain(t *testing.T) {
+	resetFlags(t)
+	plainFlag = true
+
+	var buf bytes.Buffer
+	pw := newPrefixedWriter("storage-account", 20, 0, &buf, &sync.Mutex{}, true)
+	if _, err := pw.Write([]byte("Hello, world!\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if got, want := buf.String(), "storage-account: Hello, world!\n"; got != want {
+		t.Errorf("plain output = %q, want %q", got, want)
+	}
+}
+
+func TestPrintSchemaPlain(t *testing.T) {
+	resetFlags(t)
+	plainFlag = true
+
+	schema := &terraform.ModuleSchema{
+		Name:      "storage-account",
+		Path:      "components/azurerm/storage-account",
+		Providers: []terraform.ProviderInfo{{Name: "azurerm", Version: ">= 3.0.0"}},
+		Variables: []terraform.VariableInfo{
+			{Name: "name", Type: "string", Required: true, Description: "The name of the resource, which must be globally unique across all of Azure"},
+		},
+		Outputs:     []terraform.OutputInfo{{Name: "id", Sensitive: true}},
+		Resources:   []terraform.ResourceInfo{{Type: "azurerm_storage_account", Name: "this"}},
+		DataSources: []terraform.ResourceInfo{{Type: "azurerm_client_config", Name: "current"}},
+		ModuleCalls: []terraform.ModuleCallInfo{{Name: "naming", Source: "Azure/naming/azurerm", Version: "~> 0.4"}},
+	}
+
+	buf := new(bytes.Buffer)
+	cmd := &cobra.Command{}
+	cmd.SetOut(buf)
+	printSchemaPlain(cmd, schema)
+	output := buf.String()
+
+	for _, want := range []string{
+		"Module: storage-account\n",
+		"Provider: azurerm\nVersion: >= 3.0.0\n",
+		"Variable: name\nType: string\n",
+		// Descriptions are not wrapped or truncated
+		"Description: The name of the resource, which must be globally unique across all of Azure\n",
+		"Output: id\nSensitive: Yes\n",
+		"Resource: azurerm_storage_account.this\n",
+		"Data Source: azurerm_client_config.current\n",
+		"Module Call: naming\nSource: Azure/naming/azurerm\nVersion: ~> 0.4\n",
+		"    name = \"\"\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "NAME") {
+		t.Errorf("plain output should not contain table headers, got:\n%s", output)
+	}
+}
@@ -1,11 +1,29 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/planfiles"
 	"github.com/spf13/cobra"
 )
 
+// detailedExitcodeArg makes terraform/tofu plan exit with 2 when there are changes
+const detailedExitcodeArg = "-detailed-exitcode"
+
+var (
+	// planDetailedExitcodeFlag passes -detailed-exitcode to plan and exits with
+	// ExitPlanChanges when any plan has changes
+	planDetailedExitcodeFlag bool
+	planSaveFlag             bool // Save plan files to the plans directory for motf apply --from-artifacts
+	planShowFlag             bool // Show the saved plan of a module instead of planning
+)
+
 // planCmd represents the plan command
 var planCmd = &cobra.Command{
 	Use:   "plan [module-name]",
@@ -18,21 +36,50 @@ Examples:
   motf plan storage-account                 # Run plan on storage-account module
   motf plan storage-account -e basic        # Run plan on the 'basic' example
   motf plan storage-account --example basic # Run plan on the 'basic' example
-  motf plan -i storage-account              # Run init then plan`,
-	Args: cobra.MaximumNArgs(1),
+  motf plan -i storage-account              # Run init then plan
+  motf plan --changed --detailed-exitcode   # Exit with 4 if any plan has changes
+  motf plan --changed --save                # Save plans for motf apply --from-artifacts
+  motf plan --show storage-account          # Show the saved plan of storage-account
+
+--save writes each plan to <plans dir>/<module path>.tfplan, .motf/plans under
+the root by default. Planning several modules first removes all saved plans, so
+the plans directory holds exactly the plans of the last run.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
+		if planShowFlag {
+			return runPlanShow(args)
+		}
+		basePath, err := getBasePath()
+		if err != nil {
+			return err
+		}
+
+		if selectingModules() {
 			if len(args) > 0 {
 				return cobra.MaximumNArgs(0)(cmd, args)
 			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+			if planSaveFlag && !dryRunFlag {
+				if err := planfiles.Clear(plansDir(basePath)); err != nil {
+					return err
+				}
+			}
+			return silenceOnChanges(cmd, runOnSelectedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := runnerFor(moduleAbsPath)
+				if err != nil {
+					return err
+				}
 				if initFlag {
-					if err := runner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+					if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
 						return err
 					}
 				}
-				return runner.RunPlanWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
-			})
+				args, err := planSaveArgs(basePath, moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				return planResult(tfRunner.RunPlanWithOutput(moduleAbsPath, stdout, stderr, args...))
+			}))
 		}
 
 		targetPath, err := resolveTargetWithExample(args, exampleFlag)
@@ -40,23 +87,130 @@ Examples:
 			return err
 		}
 
+		tfRunner, err := runnerFor(targetPath)
+		if err != nil {
+			return err
+		}
+
 		// Run init first if flag is set
 		if initFlag {
-			if err := runner.RunInit(targetPath); err != nil {
+			if err := tfRunner.RunInit(targetPath); err != nil {
 				return err
 			}
 		}
 
-		return runner.RunPlan(targetPath, argsFlag...)
+		planArgs, err := planSaveArgs(basePath, targetPath)
+		if err != nil {
+			return err
+		}
+		return silenceOnChanges(cmd, planResult(tfRunner.RunPlan(targetPath, planArgs...)))
 	},
 }
 
 func init() {
 	planCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
 	planCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	planCmd.Flags().BoolVar(&planDetailedExitcodeFlag, "detailed-exitcode", false, "Pass -detailed-exitcode to plan and exit with 4 if any plan has changes")
+	planCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
 	planCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	planCmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	planCmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
 	planCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
-	planCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
-	planCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	planCmd.Flags().BoolVar(&planSaveFlag, "save", false, "Save plan files to the plans directory for motf apply --from-artifacts")
+	planCmd.Flags().BoolVar(&planShowFlag, "show", false, "Show the saved plan of the module instead of planning")
+	planCmd.MarkFlagsMutuallyExclusive("save", "show")
+	addChangeRangeFlags(planCmd)
+	addParallelFlags(planCmd)
+	addRetriesFlag(planCmd)
 	rootCmd.AddCommand(planCmd)
 }
+
+// planArgs returns the extra plan arguments, with -detailed-exitcode added
+// for --detailed-exitcode.
+func planArgs() []string {
+	if planDetailedExitcodeFlag && !slices.Contains(argsFlag, detailedExitcodeArg) {
+		return append(slices.Clone(argsFlag), detailedExitcodeArg)
+	}
+	return argsFlag
+}
+
+// planSaveArgs returns the extra plan arguments for the module at
+// moduleAbsPath, with -out=<plan file> added for --save.
+func planSaveArgs(basePath, moduleAbsPath string) ([]string, error) {
+	args := planArgs()
+	if !planSaveFlag {
+		return args, nil
+	}
+	relPath, err := filepath.Rel(basePath, moduleAbsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get module path: %w", err)
+	}
+	planFile := planfiles.Path(plansDir(basePath), relPath)
+	if !dryRunFlag {
+		if planFile, err = planfiles.Prepare(plansDir(basePath), relPath); err != nil {
+			return nil, err
+		}
+	}
+	return append(slices.Clone(args), "-out="+planFile), nil
+}
+
+// plansDir returns the directory plan files are saved to, from plans.dir
+// relative to the root.
+func plansDir(basePath string) string {
+	dir := cfg.Plans.GetDir()
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(basePath, dir)
+}
+
+// runPlanShow shows the saved plan of the module given in args, or of its
+// --example, passing --args to show (e.g. -json).
+func runPlanShow(args []string) error {
+	if selectingModules() {
+		return fmt.Errorf("--show cannot be used with --all, --changed, --select, or --type")
+	}
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	targetPath, err := resolveTargetWithExample(args, exampleFlag)
+	if err != nil {
+		return err
+	}
+	relPath, err := filepath.Rel(basePath, targetPath)
+	if err != nil {
+		return fmt.Errorf("failed to get module path: %w", err)
+	}
+
+	planFile := planfiles.Path(plansDir(basePath), relPath)
+	if _, err := os.Stat(planFile); err != nil {
+		return fmt.Errorf("no saved plan for %s (run 'motf plan --save' first): %w", filepath.ToSlash(relPath), err)
+	}
+	tfRunner, err := runnerFor(targetPath)
+	if err != nil {
+		return err
+	}
+	return tfRunner.RunShow(targetPath, planFile, argsFlag...)
+}
+
+// planResult converts exit code 2 of a plan with -detailed-exitcode, which
+// means the plan succeeded with changes, to a planChangesError.
+func planResult(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 && slices.Contains(planArgs(), detailedExitcodeArg) {
+		return &planChangesError{modules: 1}
+	}
+	return err
+}
+
+// silenceOnChanges keeps cobra from printing an error and usage when err only
+// reports plan changes: they are the expected outcome and set the exit code.
+func silenceOnChanges(cmd *cobra.Command, err error) error {
+	var changes *planChangesError
+	if errors.As(err, &changes) {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+	}
+	return err
+}
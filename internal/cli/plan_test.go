@@ -1,7 +1,13 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 )
 
 func TestPlanCmd_Flags(t *testing.T) {
@@ -23,3 +29,73 @@ func TestPlanCmd_Flags(t *testing.T) {
 		t.Errorf("example flag shorthand = %q, want %q", exampleFlagDef.Shorthand, "e")
 	}
 }
+
+func TestPlanSaveArgs(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Plans: &config.PlansConfig{Dir: "plans"}})
+	modulePath := createTerraformModule(t, tmpDir, "components/storage")
+	argsFlag = []string{"-var=env=prod"}
+
+	args, err := planSaveArgs(tmpDir, modulePath)
+	if err != nil || !reflect.DeepEqual(args, argsFlag) {
+		t.Fatalf("planSaveArgs() without --save = %v, %v; want %v", args, err, argsFlag)
+	}
+
+	planSaveFlag = true
+	planFile := filepath.Join(tmpDir, "plans", "components", "storage.tfplan")
+	if err := os.MkdirAll(filepath.Dir(planFile), 0755); err != nil {
+		t.Fatalf("failed to create plans directory: %v", err)
+	}
+	if err := os.WriteFile(planFile, []byte("old plan"), 0644); err != nil {
+		t.Fatalf("failed to write plan: %v", err)
+	}
+
+	args, err = planSaveArgs(tmpDir, modulePath)
+	if err != nil {
+		t.Fatalf("planSaveArgs() error = %v", err)
+	}
+	want := []string{"-var=env=prod", "-out=" + planFile}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("planSaveArgs() = %v, want %v", args, want)
+	}
+	if _, err := os.Stat(planFile); !os.IsNotExist(err) {
+		t.Error("expected --save to remove the old plan before planning")
+	}
+}
+
+func TestPlansDir(t *testing.T) {
+	withConfig(t, &config.Config{})
+	if got, want := plansDir("/repo"), filepath.Join("/repo", ".motf", "plans"); got != want {
+		t.Errorf("plansDir() = %s, want %s", got, want)
+	}
+
+	absDir := filepath.Join(t.TempDir(), "plans")
+	withConfig(t, &config.Config{Plans: &config.PlansConfig{Dir: absDir}})
+	if got := plansDir("/repo"); got != absDir {
+		t.Errorf("plansDir() = %s, want %s", got, absDir)
+	}
+}
+
+func TestRunPlanShow_NoSavedPlan(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{})
+	createTerraformModule(t, tmpDir, "components/storage")
+
+	err := runPlanShow([]string{"storage"})
+	if err == nil || !strings.Contains(err.Error(), "no saved plan for components/storage") {
+		t.Fatalf("expected no saved plan error, got %v", err)
+	}
+}
+
+func TestRunPlanShow_RejectsSelection(t *testing.T) {
+	resetFlags(t)
+	allFlag = true
+
+	err := runPlanShow(nil)
+	if err == nil || !strings.Contains(err.Error(), "--show cannot be used") {
+		t.Fatalf("expected --show selection error, got %v", err)
+	}
+}
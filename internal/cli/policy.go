@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/policy"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policyPathsFlag []string // Policy files or directories, instead of policy.paths
+	policyPlanFlag  bool     // Include the plan JSON in the input
+	policyJSONFlag  bool     // Output violations as JSON
+)
+
+// policyCmd groups the policy commands
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Enforce custom guardrails with Rego policies",
+}
+
+var policyEvalCmd = &cobra.Command{
+	Use:   "eval [module-name]",
+	Short: "Evaluate Rego policies against a component, base, or project",
+	Long: `Evaluate the Rego policies in policy.paths of .motf.yml against a component,
+base, or project with the opa CLI, and report the violations.
+
+The input document has the module schema, as 'motf describe --json' outputs
+it, under input.module. With --plan or policy.plan, the module is also planned
+and the plan JSON is added under input.plan; initialize the module first. The
+results of policy.query (default: data.motf.deny) are the violations: strings,
+or objects with a msg.
+
+The command fails when any module has violations.`,
+	Example: `  motf policy eval storage-account             # Evaluate the policies for a module
+  motf policy eval --changed -p                 # Evaluate them for all changed modules
+  motf policy eval --all --plan                 # Include the plan JSON in the input
+  motf policy eval vnet --policy policies/net   # Use other policies than policy.paths
+  motf policy eval --all --json                 # Output violations as JSON`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runPolicyEval,
+}
+
+func init() {
+	policyEvalCmd.Flags().StringArrayVar(&policyPathsFlag, "policy", nil, "Policy file or directory, instead of policy.paths (can be specified multiple times)")
+	policyEvalCmd.Flags().BoolVar(&policyPlanFlag, "plan", false, "Plan the module and include the plan JSON in the input")
+	policyEvalCmd.Flags().BoolVar(&policyJSONFlag, "json", false, "Output violations as JSON")
+	policyEvalCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	policyEvalCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	policyEvalCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	policyEvalCmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	policyEvalCmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
+	policyEvalCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(policyEvalCmd)
+	addParallelFlags(policyEvalCmd)
+	policyCmd.AddCommand(policyEvalCmd)
+	rootCmd.AddCommand(policyCmd)
+}
+
+// violationCollector gathers violations from concurrently evaluated modules
+type violationCollector struct {
+	mu         sync.Mutex
+	violations []policy.Violation
+}
+
+func (c *violationCollector) add(module string, violations []policy.Violation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, v := range violations {
+		v.Module = module
+		c.violations = append(c.violations, v)
+	}
+}
+
+func runPolicyEval(cmd *cobra.Command, args []string) error {
+	// opa runs in the module directory, so --policy paths are made absolute
+	var paths []string
+	for _, p := range policyPathsFlag {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return fmt.Errorf("failed to resolve policy path %s: %w", p, err)
+		}
+		paths = append(paths, abs)
+	}
+	if len(paths) == 0 {
+		paths = cfg.Policy.GetPaths()
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no policies to evaluate: set policy.paths in %s or use --policy", config.ConfigFile)
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	collector := &violationCollector{}
+	eval := func(modulePath string, stdout, stderr io.Writer) error {
+		// Keep stdout clean for --json by writing progress to stderr
+		progress := stdout
+		if policyJSONFlag {
+			progress = stderr
+		}
+		input, err := policyInput(basePath, modulePath, progress, stderr)
+		if err != nil {
+			return err
+		}
+		modCfg, err := moduleConfig(modulePath)
+		if err != nil {
+			return err
+		}
+		runner := &policy.Runner{
+			Paths:   paths,
+			Query:   modCfg.Policy.GetQuery(),
+			Env:     tasks.EnvPairs(modCfg.Env),
+			DryRun:  dryRunFlag,
+			Context: moduleContext(modulePath),
+		}
+		violations, err := runner.Eval(modulePath, input, progress, stderr)
+		if err != nil {
+			return err
+		}
+		collector.add(displayPath(basePath, modulePath), violations)
+		return nil
+	}
+
+	var evalErr error
+	if selectingModules() {
+		if len(args) > 0 {
+			return cobra.MaximumNArgs(0)(cmd, args)
+		}
+		evalErr = runOnSelectedModulesWithPath(eval)
+	} else {
+		targetPath, err := resolveTargetWithExample(args, exampleFlag)
+		if err != nil {
+			return err
+		}
+		evalErr = eval(targetPath, os.Stdout, os.Stderr)
+	}
+
+	if dryRunFlag {
+		return evalErr
+	}
+
+	violations := collector.violations
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Module < violations[j].Module })
+	if policyJSONFlag {
+		if violations == nil {
+			violations = []policy.Violation{}
+		}
+		output, err := json.MarshalIndent(violations, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		printViolations(violations)
+	}
+
+	if len(violations) > 0 {
+		cmd.SilenceUsage = true
+		return errors.Join(evalErr, findingsFailed("%d policy violation(s)", len(violations)))
+	}
+	return evalErr
+}
+
+// policyInput returns the policy input of the module at modulePath, planning
+// it for the plan JSON with --plan or policy.plan.
+func policyInput(basePath, modulePath string, stdout, stderr io.Writer) (policy.Input, error) {
+	var input policy.Input
+	schema, err := terraform.LoadModuleSchema(modulePath, basePath)
+	if err != nil {
+		return input, fmt.Errorf("failed to parse module: %w", err)
+	}
+	input.Module = schema
+
+	if !policyPlanFlag && !cfg.Policy.GetPlan() {
+		return input, nil
+	}
+	tfRunner, err := runnerFor(modulePath)
+	if err != nil {
+		return input, err
+	}
+	plan, err := tfRunner.PlanJSON(modulePath, stdout, stderr)
+	if err != nil {
+		return input, fmt.Errorf("failed to plan for the policy input: %w", err)
+	}
+	input.Plan = plan
+	return input, nil
+}
+
+// printViolations outputs violations as a table, or one block per violation
+// with --plain
+func printViolations(violations []policy.Violation) {
+	fmt.Println()
+	if len(violations) == 0 {
+		fmt.Println("No policy violations")
+		return
+	}
+	if plainFlag {
+		for _, v := range violations {
+			fmt.Printf("Module: %s\nViolation: %s\n\n", v.Module, v.Message)
+		}
+		return
+	}
+	moduleWidth := len("MODULE")
+	for _, v := range violations {
+		moduleWidth = max(moduleWidth, len(v.Module))
+	}
+	fmt.Printf("%-*s  %s\n", moduleWidth, "MODULE", "VIOLATION")
+	for _, v := range violations {
+		fmt.Printf("%-*s  %s\n", moduleWidth, v.Module, v.Message)
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/policy"
+)
+
+func TestPolicyEvalCmd_Flags(t *testing.T) {
+	for _, name := range []string{"policy", "plan", "json", "all", "changed", "select", "type", "parallel"} {
+		if policyEvalCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected policy eval command to have --%s flag", name)
+		}
+	}
+}
+
+func TestPolicyEvalCmd_RequiresPolicies(t *testing.T) {
+	resetFlags(t)
+	withConfig(t, &config.Config{Binary: "terraform"})
+
+	err := runPolicyEval(policyEvalCmd, []string{"vnet"})
+	if err == nil || !strings.Contains(err.Error(), "set policy.paths") {
+		t.Fatalf("expected a missing policies error, got %v", err)
+	}
+}
+
+func TestPolicyEvalCmd_DryRunDoesNotExecute(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+
+	// With an empty PATH, opa and terraform can't be found, so the run fails unless it is a dry run
+	t.Setenv("PATH", "")
+
+	rootCmd.SetArgs([]string{"policy", "eval", "--dry-run", "--plan", "--policy", "policies", "--path", modulePath})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("expected dry run to succeed without executing opa, got: %v", err)
+	}
+}
+
+func TestPolicyEvalCmd_ViolationsExitModuleFailed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as opa")
+	}
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+
+	// A stand-in opa that reports one violation
+	binDir := t.TempDir()
+	result := `{"result":[{"expressions":[{"value":["tags are required"]}]}]}`
+	if err := os.WriteFile(filepath.Join(binDir, "opa"), []byte("#!/bin/sh\necho '"+result+"'\n"), 0755); err != nil {
+		t.Fatalf("failed to write opa: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rootCmd.SetArgs([]string{"policy", "eval", "--policy", "policies", "--path", modulePath})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 policy violation(s)") {
+		t.Fatalf("expected a violations error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+}
+
+func TestViolationCollector_SetsModule(t *testing.T) {
+	c := &violationCollector{}
+	c.add("components/vnet", []policy.Violation{{Message: "tags are required"}})
+	if len(c.violations) != 1 || c.violations[0].Module != "components/vnet" {
+		t.Errorf("violations = %+v", c.violations)
+	}
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// progressJSONFlag is the file descriptor number or file that progress events are written to
+var progressJSONFlag string
+
+// Progress event types
+const (
+	eventModuleStarted  = "module_started"
+	eventModuleFinished = "module_finished"
+	eventRunSummary     = "run_summary"
+)
+
+// moduleEvent is a module_started or module_finished event.
+type moduleEvent struct {
+	Event      string    `json:"event"`
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	Module     string    `json:"module"`
+	Path       string    `json:"path"`
+	Index      int       `json:"index"` // 1-based position of the module in the run
+	Total      int       `json:"total"`
+	Status     string    `json:"status,omitempty"` // Set when finished
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// summaryEvent is the run_summary event, emitted once all modules finished.
+type summaryEvent struct {
+	Event       string    `json:"event"`
+	Time        time.Time `json:"time"`
+	Command     string    `json:"command"`
+	Total       int       `json:"total"`
+	Succeeded   int       `json:"succeeded"`
+	Failed      int       `json:"failed"`
+	Quarantined int       `json:"quarantined"`
+	Flaky       int       `json:"flaky"`
+	Skipped     int       `json:"skipped"`
+	DurationMs  int64     `json:"duration_ms"`
+}
+
+// progressEvents writes newline-delimited JSON events about a multi-module
+// run for --progress-json. Events are best-effort: write errors are ignored
+// so a consumer that goes away doesn't fail the run.
+type progressEvents struct {
+	mu      sync.Mutex
+	w       io.Writer
+	closer  io.Closer      // Set when motf opened the file itself
+	indexes map[string]int // module path -> 1-based index
+	start   time.Time
+}
+
+// newProgressEvents opens target, a file descriptor number (e.g. 3) or a file
+// path. When target is empty, events are disabled and wrap returns fn unchanged.
+func newProgressEvents(target string, modules []ModuleInfo) (*progressEvents, error) {
+	p := &progressEvents{indexes: make(map[string]int, len(modules)), start: now()}
+	for i, mod := range modules {
+		p.indexes[mod.Path] = i + 1
+	}
+	if target == "" {
+		return p, nil
+	}
+
+	if fd, err := strconv.Atoi(target); err == nil {
+		f := os.NewFile(uintptr(fd), "progress-json")
+		if f == nil {
+			return nil, fmt.Errorf("invalid --progress-json file descriptor %d", fd)
+		}
+		if _, err := f.Stat(); err != nil {
+			return nil, fmt.Errorf("--progress-json file descriptor %d is not open: %w", fd, err)
+		}
+		p.w = f
+		return p, nil
+	}
+
+	f, err := os.Create(filepath.Clean(target))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create --progress-json file: %w", err)
+	}
+	p.w, p.closer = f, f
+	return p, nil
+}
+
+// emit writes an event as a single line.
+func (p *progressEvents) emit(event any) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, _ = p.w.Write(append(data, '\n'))
+}
+
+// moduleEvent returns an event of type event for mod.
+func (p *progressEvents) moduleEvent(event string, mod ModuleInfo) moduleEvent {
+	return moduleEvent{
+		Event:   event,
+		Time:    now(),
+		Command: commandName,
+		Module:  mod.Name,
+		Path:    mod.Path,
+		Index:   p.indexes[mod.Path],
+		Total:   len(p.indexes),
+	}
+}
+
+// wrap emits module_started before and module_finished after each module.
+func (p *progressEvents) wrap(fn ModuleRunner) ModuleRunner {
+	if p.w == nil {
+		return fn
+	}
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		p.emit(p.moduleEvent(eventModuleStarted, mod))
+		start := time.Now()
+		err := fn(mod, stdout, stderr)
+
+		finished := p.moduleEvent(eventModuleFinished, mod)
+		finished.Status, finished.Error = moduleStatus(err)
+		finished.DurationMs = time.Since(start).Milliseconds()
+		p.emit(finished)
+		return err
+	}
+}
+
+// finish emits the run_summary event and closes the file motf opened.
+func (p *progressEvents) finish(results []moduleResult) error {
+	if p.w == nil {
+		return nil
+	}
+	summary := buildChatopsSummary(commandName, results, nil)
+	succeeded, failed := summary.Counts()
+	p.emit(summaryEvent{
+		Event:       eventRunSummary,
+		Time:        now(),
+		Command:     commandName,
+		Total:       len(results),
+		Succeeded:   succeeded,
+		Failed:      failed,
+		Quarantined: summary.Quarantined(),
+		Flaky:       summary.Flaky(),
+		Skipped:     summary.Skipped(),
+		DurationMs:  now().Sub(p.start).Milliseconds(),
+	})
+	if p.closer != nil {
+		if err := p.closer.Close(); err != nil {
+			return fmt.Errorf("failed to write --progress-json file: %w", err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&progressJSONFlag, "progress-json", "", "Write newline-delimited JSON progress events of multi-module runs to a file descriptor number or file")
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// readEvents decodes the newline-delimited JSON events in file.
+func readEvents(t *testing.T, file string) []map[string]any {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("failed to open events: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("invalid event line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestRunOnModulesParallel_ProgressJSON(t *testing.T) {
+	resetFlags(t)
+	commandName = "val"
+	t.Cleanup(func() { commandName = "" })
+	progressJSONFlag = filepath.Join(t.TempDir(), "progress.ndjson")
+
+	modules := []ModuleInfo{{Name: "a", Path: "components/a"}, {Name: "b", Path: "components/b"}}
+	err := RunOnModulesParallel(modules, &config.ParallelismConfig{}, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		if mod.Name == "b" {
+			return errors.New("validation failed")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected the failure of b to be returned")
+	}
+
+	events := readEvents(t, progressJSONFlag)
+	var types []string
+	for _, e := range events {
+		types = append(types, e["event"].(string))
+	}
+	want := "module_started module_finished module_started module_finished run_summary"
+	if got := strings.Join(types, " "); got != want {
+		t.Fatalf("events = %s, want %s", got, want)
+	}
+
+	if e := events[1]; e["module"] != "a" || e["status"] != "succeeded" || e["index"] != 1.0 || e["total"] != 2.0 {
+		t.Errorf("finished event of a = %v", e)
+	}
+	if e := events[3]; e["status"] != "failed" || e["error"] != "validation failed" || e["command"] != "val" {
+		t.Errorf("finished event of b = %v", e)
+	}
+	if e := events[4]; e["total"] != 2.0 || e["succeeded"] != 1.0 || e["failed"] != 1.0 {
+		t.Errorf("run summary = %v", e)
+	}
+}
+
+func TestNewProgressEvents(t *testing.T) {
+	p, err := newProgressEvents("", nil)
+	if err != nil {
+		t.Fatalf("newProgressEvents() error = %v", err)
+	}
+	fn := func(ModuleInfo, io.Writer, io.Writer) error { return nil }
+	if p.wrap(fn) == nil || p.finish(nil) != nil {
+		t.Error("expected disabled progress events to be a no-op")
+	}
+
+	if _, err := newProgressEvents("987", nil); err == nil || !strings.Contains(err.Error(), "not open") {
+		t.Errorf("expected an error for a closed file descriptor, got %v", err)
+	}
+	if _, err := newProgressEvents(filepath.Join(t.TempDir(), "missing", "progress.ndjson"), nil); err == nil {
+		t.Error("expected an error for a file in a missing directory")
+	}
+}
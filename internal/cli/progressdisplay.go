@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+	"golang.org/x/term"
+)
+
+// noProgressFlag disables the live progress display of parallel runs
+var noProgressFlag bool
+
+// progressRefresh is how often the progress display is redrawn
+const progressRefresh = 100 * time.Millisecond
+
+// spinnerFrames are the frames of the spinner shown for running modules
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// terminalSize returns the width and height of the terminal w, or zeros when
+// unknown. It is a variable so tests can replace it.
+var terminalSize = func(w io.Writer) (int, int) {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0, 0
+	}
+	width, height, err := term.GetSize(int(f.Fd())) //nolint:gosec // File descriptors fit in an int
+	if err != nil {
+		return 0, 0
+	}
+	return width, height
+}
+
+// progressDisplayEnabled reports whether a run on modules shows the live
+// progress display: only for parallel runs of several modules on a terminal,
+// and not with --no-progress or --plain.
+func progressDisplayEnabled(modules []ModuleInfo) bool {
+	return parallelFlag && !noProgressFlag && !plainFlag && len(modules) > 1 &&
+		isTerminal(os.Stdout) && isTerminal(os.Stderr)
+}
+
+// displayModule is the state of a module in the progress display
+type displayModule struct {
+	mod     ModuleInfo
+	running bool
+	status  string // chatops status, set once the module finished
+	start   time.Time
+	elapsed time.Duration // set once the module finished
+}
+
+// progressDisplay draws a live status line per module of a parallel run:
+// queued, running (with a spinner), or its outcome, with the elapsed time.
+// The output of a module is held back while it runs and printed above the
+// status lines once it finishes, so the output of modules doesn't interleave.
+type progressDisplay struct {
+	enabled bool
+	mu      sync.Mutex
+	out     io.Writer // The terminal the status lines are drawn on
+	color   bool
+	modules []*displayModule
+	byPath  map[string]*displayModule
+	nameLen int
+	lines   int // Number of lines drawn by the last frame
+	frame   int
+	stopped chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newProgressDisplay creates a display for modules drawn on out; when
+// disabled, wrap returns fn unchanged and start and stop do nothing.
+func newProgressDisplay(enabled bool, modules []ModuleInfo, out io.Writer) *progressDisplay {
+	d := &progressDisplay{
+		enabled: enabled,
+		out:     out,
+		color:   colorOutput(out),
+		byPath:  make(map[string]*displayModule, len(modules)),
+		stopped: make(chan struct{}),
+	}
+	for _, mod := range modules {
+		m := &displayModule{mod: mod}
+		d.modules = append(d.modules, m)
+		d.byPath[mod.Path] = m
+		d.nameLen = max(d.nameLen, len(mod.Name))
+	}
+	return d
+}
+
+// start draws the first frame and redraws the display until stop is called.
+func (d *progressDisplay) start() {
+	if !d.enabled {
+		return
+	}
+	d.mu.Lock()
+	d.draw()
+	d.mu.Unlock()
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(progressRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stopped:
+				return
+			case <-ticker.C:
+				d.mu.Lock()
+				d.frame++
+				d.redraw()
+				d.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// stop stops redrawing and leaves the final status of every module on screen.
+func (d *progressDisplay) stop() {
+	if !d.enabled {
+		return
+	}
+	close(d.stopped)
+	d.wg.Wait()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clear()
+	for _, m := range d.modules {
+		_, _ = io.WriteString(d.out, d.line(m, 0)+"\n")
+	}
+	d.lines = 0
+}
+
+// wrap marks each module as running while fn runs, and holds back its output
+// until it finishes.
+func (d *progressDisplay) wrap(fn ModuleRunner) ModuleRunner {
+	if !d.enabled {
+		return fn
+	}
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		d.mu.Lock()
+		m := d.byPath[mod.Path]
+		m.running, m.start = true, now()
+		d.redraw()
+		d.mu.Unlock()
+
+		held := &heldOutput{}
+		err := fn(mod, held.writer(stdout), held.writer(stderr))
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		m.running = false
+		m.status, _ = moduleStatus(err)
+		m.elapsed = now().Sub(m.start)
+		d.clear()
+		held.release()
+		d.draw()
+		return err
+	}
+}
+
+// redraw replaces the last frame with a new one. Callers hold d.mu.
+func (d *progressDisplay) redraw() {
+	d.clear()
+	d.draw()
+}
+
+// clear erases the last frame: the cursor moves to its first line and the
+// screen below is cleared. Callers hold d.mu.
+func (d *progressDisplay) clear() {
+	if d.lines > 0 {
+		_, _ = fmt.Fprintf(d.out, "\033[%dA\033[J", d.lines)
+		d.lines = 0
+	}
+}
+
+// draw writes a frame of status lines, as many as fit on the terminal.
+// Callers hold d.mu.
+func (d *progressDisplay) draw() {
+	// Leave a line free so the frame doesn't scroll the terminal
+	width, height := terminalSize(d.out)
+	lines := d.visibleLines(width, height-1)
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+	_, _ = io.WriteString(d.out, b.String())
+	d.lines = len(lines)
+}
+
+// visibleLines returns the status lines of a frame, cut to width and at most
+// limit lines when they are positive. When modules don't fit, succeeded and
+// skipped modules are left out first, then the rest is cut off with a count
+// of the modules not shown.
+func (d *progressDisplay) visibleLines(width, limit int) []string {
+	shown := d.modules
+	if limit > 0 && len(shown) > limit {
+		// One line is left for the count of the modules not shown
+		shown = nil
+		for _, m := range d.modules {
+			if m.status != chatops.StatusSucceeded && m.status != chatops.StatusSkipped {
+				shown = append(shown, m)
+			}
+		}
+		shown = shown[:min(len(shown), limit-1)]
+	}
+
+	lines := make([]string, 0, len(shown)+1)
+	for _, m := range shown {
+		lines = append(lines, d.line(m, width))
+	}
+	if hidden := len(d.modules) - len(shown); hidden > 0 {
+		lines = append(lines, fmt.Sprintf("  … and %d more", hidden))
+	}
+	return lines
+}
+
+// line returns the status line of m, cut to width when width is positive so
+// it doesn't wrap and throw off clear.
+func (d *progressDisplay) line(m *displayModule, width int) string {
+	icon, iconColor := "·", ""
+	text := fmt.Sprintf("%-*s  queued", d.nameLen, m.mod.Name)
+	switch {
+	case m.running:
+		icon, iconColor = spinnerFrames[d.frame%len(spinnerFrames)], colorCyan
+		text = fmt.Sprintf("%-*s  %-11s  %s", d.nameLen, m.mod.Name, "running", formatElapsed(now().Sub(m.start)))
+	case m.status != "":
+		icon, iconColor = statusIcon(m.status)
+		text = fmt.Sprintf("%-*s  %-11s  %s", d.nameLen, m.mod.Name, m.status, formatElapsed(m.elapsed))
+	}
+
+	if width > 0 {
+		if runes := []rune(text); len(runes) > width-2 {
+			text = string(runes[:max(width-2, 0)])
+		}
+	}
+	if d.color && iconColor != "" {
+		icon = iconColor + icon + colorReset
+	}
+	return icon + " " + text
+}
+
+// statusIcon returns the icon and color of a finished module's status
+func statusIcon(status string) (string, string) {
+	switch status {
+	case chatops.StatusSucceeded:
+		return "✓", colorGreen
+	case chatops.StatusFailed:
+		return "✗", colorRed
+	case chatops.StatusQuarantined, chatops.StatusFlaky:
+		return "!", colorYellow
+	default:
+		return "-", ""
+	}
+}
+
+// formatElapsed formats an elapsed time to a tenth of a second, e.g. 12.3s
+func formatElapsed(d time.Duration) string {
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// heldOutput records what a module writes to its stdout and stderr, in order,
+// so it can be written once the module finished.
+type heldOutput struct {
+	mu     sync.Mutex
+	chunks []heldChunk
+}
+
+// heldChunk is a single write to w
+type heldChunk struct {
+	w    io.Writer
+	data []byte
+}
+
+// writer returns a writer that holds back writes to w.
+func (h *heldOutput) writer(w io.Writer) io.Writer {
+	return heldWriter{held: h, w: w}
+}
+
+// release writes the held output to the writers it was meant for, and
+// flushes them so partial last lines are written too.
+func (h *heldOutput) release() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var flushers []interface{ Flush() error }
+	seen := make(map[io.Writer]bool)
+	for _, c := range h.chunks {
+		_, _ = c.w.Write(c.data)
+		if f, ok := c.w.(interface{ Flush() error }); ok && !seen[c.w] {
+			seen[c.w] = true
+			flushers = append(flushers, f)
+		}
+	}
+	for _, f := range flushers {
+		_ = f.Flush()
+	}
+	h.chunks = nil
+}
+
+// heldWriter is a writer of heldOutput
+type heldWriter struct {
+	held *heldOutput
+	w    io.Writer
+}
+
+func (w heldWriter) Write(p []byte) (int, error) {
+	w.held.mu.Lock()
+	defer w.held.mu.Unlock()
+	w.held.chunks = append(w.held.chunks, heldChunk{w: w.w, data: append([]byte(nil), p...)})
+	return len(p), nil
+}
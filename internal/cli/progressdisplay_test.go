@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTerminal makes every writer a terminal of the given size for the test.
+func fakeTerminal(t *testing.T, width, height int) {
+	t.Helper()
+	origTerminal, origSize := isTerminal, terminalSize
+	t.Cleanup(func() { isTerminal, terminalSize = origTerminal, origSize })
+	isTerminal = func(io.Writer) bool { return true }
+	terminalSize = func(io.Writer) (int, int) { return width, height }
+}
+
+func TestProgressDisplayEnabled(t *testing.T) {
+	modules := []ModuleInfo{{Name: "a", Path: "a"}, {Name: "b", Path: "b"}}
+	tests := []struct {
+		name     string
+		setup    func()
+		modules  []ModuleInfo
+		terminal bool
+		want     bool
+	}{
+		{name: "parallel on a terminal", setup: func() { parallelFlag = true }, modules: modules, terminal: true, want: true},
+		{name: "sequential", setup: func() {}, modules: modules, terminal: true, want: false},
+		{name: "not a terminal", setup: func() { parallelFlag = true }, modules: modules, terminal: false, want: false},
+		{name: "no-progress", setup: func() { parallelFlag, noProgressFlag = true, true }, modules: modules, terminal: true, want: false},
+		{name: "plain", setup: func() { parallelFlag, plainFlag = true, true }, modules: modules, terminal: true, want: false},
+		{name: "single module", setup: func() { parallelFlag = true }, modules: modules[:1], terminal: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			original := isTerminal
+			t.Cleanup(func() { isTerminal = original })
+			isTerminal = func(io.Writer) bool { return tt.terminal }
+			tt.setup()
+
+			if got := progressDisplayEnabled(tt.modules); got != tt.want {
+				t.Errorf("progressDisplayEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressDisplay_HoldsOutputUntilFinished(t *testing.T) {
+	resetFlags(t)
+	noColorFlag = true
+	fakeTerminal(t, 80, 24)
+	origNow := now
+	t.Cleanup(func() { now = origNow })
+	now = func() time.Time { return time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC) }
+
+	// Without start, no frames are drawn in the background while the test reads the screen
+	modules := []ModuleInfo{{Name: "network", Path: "components/network"}, {Name: "dns", Path: "components/dns"}}
+	var screen, stdout, stderr bytes.Buffer
+	d := newProgressDisplay(true, modules, &screen)
+
+	run := d.wrap(func(mod ModuleInfo, modOut, modErr io.Writer) error {
+		_, _ = io.WriteString(modOut, "Plan: 1 to add\n")
+		_, _ = io.WriteString(modErr, "warning")
+		if stdout.Len() != 0 || stderr.Len() != 0 {
+			t.Error("expected output to be held back while the module runs")
+		}
+		if !strings.Contains(screen.String(), "network  running") {
+			t.Errorf("expected network to be shown running, got:\n%s", screen.String())
+		}
+		return errors.New("plan failed")
+	})
+	if err := run(modules[0], &stdout, &stderr); err == nil {
+		t.Fatal("expected the module's error to be returned")
+	}
+	d.stop()
+
+	if stdout.String() != "Plan: 1 to add\n" || stderr.String() != "warning" {
+		t.Errorf("released output = %q / %q", stdout.String(), stderr.String())
+	}
+	final := screen.String()[strings.LastIndex(screen.String(), "\033[J")+len("\033[J"):]
+	want := "✗ network  failed       0s\n· dns      queued\n"
+	if final != want {
+		t.Errorf("final frame = %q, want %q", final, want)
+	}
+}
+
+func TestProgressDisplay_Disabled(t *testing.T) {
+	var screen bytes.Buffer
+	d := newProgressDisplay(false, []ModuleInfo{{Name: "a", Path: "a"}}, &screen)
+	d.start()
+	var stdout bytes.Buffer
+	err := d.wrap(func(_ ModuleInfo, modOut, _ io.Writer) error {
+		_, _ = io.WriteString(modOut, "streamed\n")
+		return nil
+	})(ModuleInfo{Name: "a", Path: "a"}, &stdout, io.Discard)
+	d.stop()
+
+	if err != nil || stdout.String() != "streamed\n" || screen.Len() != 0 {
+		t.Errorf("disabled display changed the run: err=%v stdout=%q screen=%q", err, stdout.String(), screen.String())
+	}
+}
+
+func TestProgressDisplay_VisibleLines(t *testing.T) {
+	resetFlags(t)
+	noColorFlag = true
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	origNow := now
+	t.Cleanup(func() { now = origNow })
+	now = func() time.Time { return at }
+
+	var modules []ModuleInfo
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		modules = append(modules, ModuleInfo{Name: name, Path: name})
+	}
+	d := newProgressDisplay(true, modules, io.Discard)
+	d.byPath["a"].status, d.byPath["a"].elapsed = "succeeded", 1500*time.Millisecond
+	d.byPath["b"].status, d.byPath["b"].elapsed = "failed", 2*time.Second
+	d.byPath["c"].running, d.byPath["c"].start = true, at.Add(-12340*time.Millisecond)
+
+	all := d.visibleLines(0, 0)
+	want := []string{
+		"✓ a  succeeded    1.5s",
+		"✗ b  failed       2s",
+		"⠋ c  running      12.3s",
+		"· d  queued",
+		"· e  queued",
+	}
+	if strings.Join(all, "\n") != strings.Join(want, "\n") {
+		t.Errorf("visibleLines() =\n%s\nwant\n%s", strings.Join(all, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Succeeded modules are left out first, then the rest is cut off
+	limited := d.visibleLines(0, 3)
+	want = []string{"✗ b  failed       2s", "⠋ c  running      12.3s", "  … and 3 more"}
+	if strings.Join(limited, "\n") != strings.Join(want, "\n") {
+		t.Errorf("visibleLines(limit 3) =\n%s\nwant\n%s", strings.Join(limited, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Lines are cut to the terminal width so they don't wrap
+	if got := d.visibleLines(8, 0)[0]; got != "✓ a  suc" {
+		t.Errorf("visibleLines(width 8)[0] = %q", got)
+	}
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/TechnicallyJoe/terraform-motf/internal/versions"
+	"github.com/spf13/cobra"
+)
+
+// providersJSONFlag outputs the providers as JSON
+var providersJSONFlag bool
+
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Audit the providers and version constraints of all modules",
+	Long: `List every provider in the required_providers of the modules in components,
+bases, and projects, with each version constraint and the modules using it.
+
+Constraints of a provider that no version satisfies together, e.g. azurerm
+>= 3.0 in one module and ~> 2.0 in another, are flagged, since a module calling
+both can't be initialized. The command exits with an error when any provider
+has inconsistent constraints. Modules without a constraint allow any version.`,
+	Example: `  motf providers         # Print the provider matrix
+  motf providers --json  # Output the providers as JSON`,
+	Args: cobra.NoArgs,
+	RunE: runProviders,
+}
+
+func init() {
+	providersCmd.Flags().BoolVar(&providersJSONFlag, "json", false, "Output in JSON format")
+	rootCmd.AddCommand(providersCmd)
+}
+
+// providerUsage is a provider with the version constraints modules require
+type providerUsage struct {
+	Name        string               `json:"name"`
+	Constraints []providerConstraint `json:"constraints"`
+	Consistent  bool                 `json:"consistent"`
+	Conflicts   [][2]string          `json:"conflicts,omitempty"` // Pairs of constraints no version satisfies
+}
+
+// providerConstraint is a version constraint and the modules requiring it
+type providerConstraint struct {
+	Version string   `json:"version"` // Empty for any version
+	Modules []string `json:"modules"` // Slash-separated paths relative to the root
+}
+
+func runProviders(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := collectModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	sortModules(modules)
+	usages, err := providerUsages(basePath, modules)
+	if err != nil {
+		return err
+	}
+
+	if providersJSONFlag {
+		output, err := json.MarshalIndent(usages, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	} else {
+		printProviders(usages)
+	}
+
+	inconsistent := 0
+	for _, u := range usages {
+		if !u.Consistent {
+			inconsistent++
+		}
+	}
+	if inconsistent > 0 {
+		cmd.SilenceUsage = true
+		return findingsFailed("%d of %d provider(s) have inconsistent version constraints", inconsistent, len(usages))
+	}
+	return nil
+}
+
+// providerUsages returns the providers required by modules, sorted by name,
+// with their constraints sorted and checked for consistency.
+func providerUsages(basePath string, modules []ModuleInfo) ([]providerUsage, error) {
+	byProvider := map[string]map[string][]string{} // Provider -> constraint -> modules
+	for _, mod := range modules {
+		schema, err := terraform.LoadModuleSchema(filepath.Join(basePath, mod.Path), basePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse module %s: %w", mod.Path, err)
+		}
+		for _, p := range schema.Providers {
+			if byProvider[p.Name] == nil {
+				byProvider[p.Name] = map[string][]string{}
+			}
+			version := strings.TrimSpace(p.Version)
+			byProvider[p.Name][version] = append(byProvider[p.Name][version], filepath.ToSlash(mod.Path))
+		}
+	}
+
+	usages := []providerUsage{}
+	for _, name := range slices.Sorted(maps.Keys(byProvider)) {
+		u := providerUsage{Name: name}
+		var constraints []string
+		for _, version := range slices.Sorted(maps.Keys(byProvider[name])) {
+			u.Constraints = append(u.Constraints, providerConstraint{Version: version, Modules: byProvider[name][version]})
+			if version != "" {
+				constraints = append(constraints, version)
+			}
+		}
+		for i, a := range constraints {
+			for _, b := range constraints[i+1:] {
+				ok, err := versions.Compatible(a, b)
+				if err != nil {
+					return nil, fmt.Errorf("provider %s: %w", name, err)
+				}
+				if !ok {
+					u.Conflicts = append(u.Conflicts, [2]string{a, b})
+				}
+			}
+		}
+		// Constraints can be compatible in pairs but not all together
+		ok, err := versions.Compatible(constraints...)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", name, err)
+		}
+		u.Consistent = ok
+		usages = append(usages, u)
+	}
+	return usages, nil
+}
+
+// printProviders outputs the providers as a table of constraints and the
+// modules using them, followed by the inconsistent constraints.
+func printProviders(usages []providerUsage) {
+	if len(usages) == 0 {
+		fmt.Println("No providers found")
+		return
+	}
+
+	orAny := func(version string) string { return valueOrDefault(version, "(any)") }
+	if plainFlag {
+		for _, u := range usages {
+			for _, c := range u.Constraints {
+				fmt.Printf("Provider: %s\nVersion: %s\nModules: %s\n\n", u.Name, orAny(c.Version), strings.Join(c.Modules, ", "))
+			}
+		}
+	} else {
+		nameWidth, versionWidth := len("PROVIDER"), len("VERSION")
+		for _, u := range usages {
+			nameWidth = max(nameWidth, len(u.Name))
+			for _, c := range u.Constraints {
+				versionWidth = max(versionWidth, len(orAny(c.Version)))
+			}
+		}
+		fmt.Printf("%-*s  %-*s  %s\n", nameWidth, "PROVIDER", versionWidth, "VERSION", "MODULES")
+		for _, u := range usages {
+			for i, c := range u.Constraints {
+				name := u.Name
+				if i > 0 {
+					name = ""
+				}
+				fmt.Printf("%-*s  %-*s  %s\n", nameWidth, name, versionWidth, orAny(c.Version), strings.Join(c.Modules, ", "))
+			}
+		}
+	}
+
+	header := false
+	for _, u := range usages {
+		if u.Consistent {
+			continue
+		}
+		if !header {
+			fmt.Println("\nInconsistent constraints:")
+			header = true
+		}
+		if len(u.Conflicts) == 0 {
+			fmt.Printf("  %s: no version satisfies all constraints\n", u.Name)
+		}
+		for _, c := range u.Conflicts {
+			fmt.Printf("  %s: no version satisfies both %q and %q\n", u.Name, c[0], c[1])
+		}
+	}
+}
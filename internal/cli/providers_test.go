@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// requireProviders writes a versions.tf requiring azurerm with constraint,
+// or any version if it's empty, to the module at modulePath.
+func requireProviders(t *testing.T, modulePath, constraint string) {
+	t.Helper()
+	version := ""
+	if constraint != "" {
+		version = "\n      version = \"" + constraint + "\""
+	}
+	content := `terraform {
+  required_providers {
+    azurerm = {
+      source  = "hashicorp/azurerm"` + version + `
+    }
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(modulePath, "versions.tf"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write versions.tf: %v", err)
+	}
+}
+
+func TestProviderUsages(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	requireProviders(t, createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage")), ">= 3.0")
+	requireProviders(t, createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet")), ">= 3.0")
+	requireProviders(t, createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "legacy")), "~> 2.0")
+	requireProviders(t, createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "sandbox")), "")
+
+	modules, err := collectModules(tmpDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortModules(modules)
+	usages, err := providerUsages(tmpDir, modules)
+	if err != nil {
+		t.Fatalf("providerUsages() error = %v", err)
+	}
+	want := []providerUsage{{
+		Name: "azurerm",
+		Constraints: []providerConstraint{
+			{Version: "", Modules: []string{"projects/sandbox"}},
+			{Version: ">= 3.0", Modules: []string{"components/storage", "components/vnet"}},
+			{Version: "~> 2.0", Modules: []string{"projects/legacy"}},
+		},
+		Conflicts: [][2]string{{">= 3.0", "~> 2.0"}},
+	}}
+	if !reflect.DeepEqual(usages, want) {
+		t.Errorf("providerUsages() = %+v, want %+v", usages, want)
+	}
+
+	// The command fails on inconsistent constraints
+	err = runProviders(providersCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "1 of 1 provider(s) have inconsistent version constraints") {
+		t.Errorf("expected inconsistent constraints error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+}
+
+func TestProviders_Consistent(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	requireProviders(t, createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage")), ">= 3.0")
+	requireProviders(t, createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "prod")), "~> 3.5")
+
+	if err := runProviders(providersCmd, nil); err != nil {
+		t.Errorf("runProviders() error = %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+// providersSyncCheckFlag only reports providers that differ from the table
+var providersSyncCheckFlag bool
+
+var providersSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Rewrite required_providers to match the provider constraints in .motf.yml",
+	Long: `Set the version constraint of each provider in the required_providers blocks
+of the modules in components, bases, and projects to the one in
+constraints.providers in .motf.yml, by provider local name:
+
+  constraints:
+    providers:
+      azurerm: "~> 4.0"
+      random: ">= 3.5"
+
+A provider without a version gets one. Providers that aren't in the table are
+left alone, and only the version values change; the rest of each file is kept
+as written. .tf.json files aren't changed.
+
+Use --check in CI to only report the providers that differ, exiting with an
+error when there are any, and --dry-run to show the changes without writing
+them.`,
+	Example: `  motf providers sync            # Update the modules to the table
+  motf providers sync --check    # Fail when a module differs from the table
+  motf providers sync --dry-run  # Show what would change`,
+	Args: cobra.NoArgs,
+	RunE: runProvidersSync,
+}
+
+func init() {
+	providersSyncCmd.Flags().BoolVar(&providersSyncCheckFlag, "check", false, "Only report providers that differ from constraints.providers, failing when there are any")
+	providersCmd.AddCommand(providersSyncCmd)
+}
+
+func runProvidersSync(cmd *cobra.Command, args []string) error {
+	constraints := cfg.Constraints.GetProviders()
+	if len(constraints) == 0 {
+		return fmt.Errorf("no provider constraints to sync: set constraints.providers in .motf.yml")
+	}
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := collectModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	sortModules(modules)
+
+	cmd.SilenceUsage = true
+	out := cmd.OutOrStdout()
+	type moduleDrifts struct {
+		dir    string
+		drifts []providers.Drift
+	}
+	var found []moduleDrifts
+	count := 0
+	for _, mod := range modules {
+		dir := filepath.Join(basePath, mod.Path)
+		drifts, err := providers.Find(dir, constraints)
+		if err != nil {
+			return fmt.Errorf("module %s: %w", filepath.ToSlash(mod.Path), err)
+		}
+		if len(drifts) > 0 {
+			found = append(found, moduleDrifts{dir: dir, drifts: drifts})
+			count += len(drifts)
+		}
+	}
+	if count == 0 {
+		_, _ = fmt.Fprintln(out, "All provider constraints match constraints.providers")
+		return nil
+	}
+
+	switch {
+	case providersSyncCheckFlag:
+		_, _ = fmt.Fprintf(out, "%d provider constraint(s) differ from constraints.providers:\n", count)
+	case dryRunFlag:
+		_, _ = fmt.Fprintf(out, "[dry-run] Would update %d provider constraint(s):\n", count)
+	default:
+		for _, m := range found {
+			if err := providers.Sync(m.dir, m.drifts); err != nil {
+				return fmt.Errorf("module %s: %w", displayPath(basePath, m.dir), err)
+			}
+		}
+		_, _ = fmt.Fprintf(out, "Updated %d provider constraint(s):\n", count)
+	}
+	for _, m := range found {
+		for _, d := range m.drifts {
+			from := valueOrDefault(d.From, "(any)")
+			_, _ = fmt.Fprintf(out, "  %s:%d  %s: %s -> %s\n", path.Join(displayPath(basePath, m.dir), d.File), d.Line, d.Provider, from, d.To)
+		}
+	}
+
+	if providersSyncCheckFlag {
+		return findingsFailed("%d provider constraint(s) differ from constraints.providers, run 'motf providers sync' to update them", count)
+	}
+	return nil
+}
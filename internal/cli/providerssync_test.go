@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func runProvidersSyncCmd(t *testing.T) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	err := runProvidersSync(cmd, nil)
+	return out.String(), err
+}
+
+// providersSyncRepo creates a repository where components/network requires
+// azurerm ~> 3.0 and projects/platform already requires ~> 4.0, with the
+// central table at ~> 4.0.
+func providersSyncRepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	withConfig(t, &config.Config{Root: root, Constraints: &config.ConstraintsConfig{Providers: map[string]string{"azurerm": "~> 4.0"}}})
+	withWorkingDir(t, root)
+	for dir, version := range map[string]string{
+		filepath.Join(DirComponents, "network"): "~> 3.0",
+		filepath.Join(DirProjects, "platform"):  "~> 4.0",
+	} {
+		modulePath := createTerraformModule(t, root, dir)
+		versions := "terraform {\n  required_providers {\n    azurerm = {\n      source  = \"hashicorp/azurerm\"\n      version = \"" + version + "\"\n    }\n  }\n}\n"
+		if err := os.WriteFile(filepath.Join(modulePath, "versions.tf"), []byte(versions), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestProvidersSync(t *testing.T) {
+	resetFlags(t)
+	root := providersSyncRepo(t)
+
+	out, err := runProvidersSyncCmd(t)
+	if err != nil {
+		t.Fatalf("runProvidersSync() error = %v", err)
+	}
+	want := "Updated 1 provider constraint(s):\n  components/network/versions.tf:5  azurerm: ~> 3.0 -> ~> 4.0\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "components", "network", "versions.tf"))
+	if err != nil || !strings.Contains(string(data), `version = "~> 4.0"`) {
+		t.Errorf("versions.tf = %q, %v", data, err)
+	}
+
+	out, err = runProvidersSyncCmd(t)
+	if err != nil || out != "All provider constraints match constraints.providers\n" {
+		t.Errorf("second sync = %q, %v", out, err)
+	}
+}
+
+func TestProvidersSync_Check(t *testing.T) {
+	resetFlags(t)
+	root := providersSyncRepo(t)
+	providersSyncCheckFlag = true
+
+	out, err := runProvidersSyncCmd(t)
+	if err == nil || !strings.Contains(err.Error(), "1 provider constraint(s) differ from constraints.providers") {
+		t.Errorf("runProvidersSync() error = %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+	if !strings.Contains(out, "components/network/versions.tf:5  azurerm: ~> 3.0 -> ~> 4.0") {
+		t.Errorf("output = %q", out)
+	}
+	data, _ := os.ReadFile(filepath.Join(root, "components", "network", "versions.tf"))
+	if !strings.Contains(string(data), `version = "~> 3.0"`) {
+		t.Error("--check changed the module")
+	}
+}
+
+func TestProvidersSync_NoTable(t *testing.T) {
+	resetFlags(t)
+	providersSyncRepo(t)
+	cfg.Constraints = nil
+
+	if _, err := runProvidersSyncCmd(t); err == nil || !strings.Contains(err.Error(), "set constraints.providers") {
+		t.Errorf("runProvidersSync() error = %v", err)
+	}
+}
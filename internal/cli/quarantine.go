@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// now returns the current time; replaced in tests
+var now = time.Now
+
+// quarantinedError is a test failure of a quarantined module. It is reported
+// as a warning and doesn't fail the run.
+type quarantinedError struct {
+	entry *config.QuarantineEntry
+	err   error
+}
+
+func (e *quarantinedError) Error() string {
+	return fmt.Sprintf("quarantined until %s: %v", e.entry.Until, e.err)
+}
+
+func (e *quarantinedError) Unwrap() error {
+	return e.err
+}
+
+// applyQuarantine turns a test failure of a module into a warning when the
+// module is quarantined, and notes expired quarantines. Interrupted, timed out
+// and fail-fast stopped runs aren't test failures, so they pass through.
+func applyQuarantine(name, path string, err error, stderr io.Writer) error {
+	if err == nil || cfg == nil || isNonFatal(err) || isStopped(err) {
+		return err
+	}
+
+	at := now()
+	entry := cfg.Test.Quarantined(name, path, at)
+	if entry == nil {
+		return err
+	}
+	if !entry.Active(at) {
+		_, _ = fmt.Fprintf(stderr, "[quarantine] Quarantine of %s expired after %s, failures count again\n", name, entry.Until)
+		return err
+	}
+
+	reason := ""
+	if entry.Reason != "" {
+		reason = " (" + entry.Reason + ")"
+	}
+	_, _ = fmt.Fprintf(stderr, "[quarantine] WARNING: tests of %s failed but are quarantined until %s%s: %v\n", name, entry.Until, reason, err)
+	return &quarantinedError{entry: entry, err: err}
+}
+
+// withQuarantine wraps a test runner so failures of quarantined modules are
+// reported as warnings. moduleOf returns the name and path the quarantine is
+// matched against, e.g. the parent module of an example.
+func withQuarantine(fn ModuleRunner, moduleOf func(ModuleInfo) (name, path string)) ModuleRunner {
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		name, path := moduleOf(mod)
+		return applyQuarantine(name, path, fn(mod, stdout, stderr), stderr)
+	}
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// withNow sets the current time for the test.
+func withNow(t *testing.T, at time.Time) {
+	t.Helper()
+	orig := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = orig })
+}
+
+func withQuarantineConfig(t *testing.T, entries ...config.QuarantineEntry) {
+	t.Helper()
+	c := config.DefaultConfig()
+	c.Test.Quarantine = entries
+	withConfig(t, c)
+}
+
+func TestApplyQuarantine_Active(t *testing.T) {
+	withQuarantineConfig(t, config.QuarantineEntry{Module: "key-vault", Until: "2030-01-31", Reason: "flaky purge"})
+	withNow(t, time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC))
+
+	var stderr bytes.Buffer
+	testErr := errors.New("exit status 1")
+	err := applyQuarantine("key-vault", "components/azurerm/key-vault", testErr, &stderr)
+
+	var quarantined *quarantinedError
+	if !errors.As(err, &quarantined) {
+		t.Fatalf("expected quarantined error, got %v", err)
+	}
+	if !errors.Is(err, testErr) {
+		t.Error("quarantined error should wrap the test failure")
+	}
+	if !strings.Contains(stderr.String(), "quarantined until 2030-01-31 (flaky purge)") {
+		t.Errorf("stderr = %q, want quarantine warning", stderr.String())
+	}
+}
+
+func TestApplyQuarantine_Expired(t *testing.T) {
+	withQuarantineConfig(t, config.QuarantineEntry{Module: "key-vault", Until: "2030-01-31"})
+	withNow(t, time.Date(2030, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	var stderr bytes.Buffer
+	testErr := errors.New("exit status 1")
+	if err := applyQuarantine("key-vault", "components/azurerm/key-vault", testErr, &stderr); err != testErr {
+		t.Fatalf("expected the original error after expiry, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "expired after 2030-01-31") {
+		t.Errorf("stderr = %q, want expiry note", stderr.String())
+	}
+}
+
+func TestApplyQuarantine_StoppedRunsPassThrough(t *testing.T) {
+	withQuarantineConfig(t, config.QuarantineEntry{Module: "key-vault", Until: "2030-01-31"})
+	withNow(t, time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC))
+
+	for _, stopErr := range []error{
+		fmt.Errorf("terraform test: %w", errInterrupted),
+		&moduleTimeoutError{timeout: time.Minute},
+		&failFastError{module: ModuleInfo{Name: "storage"}},
+	} {
+		var stderr bytes.Buffer
+		if err := applyQuarantine("key-vault", "components/azurerm/key-vault", stopErr, &stderr); err != stopErr {
+			t.Errorf("applyQuarantine(%v) = %v, want the original error", stopErr, err)
+		}
+		if stderr.Len() > 0 {
+			t.Errorf("expected no quarantine output for %v, got %q", stopErr, stderr.String())
+		}
+	}
+}
+
+func TestApplyQuarantine_Success(t *testing.T) {
+	withQuarantineConfig(t, config.QuarantineEntry{Module: "key-vault", Until: "2030-01-31"})
+
+	var stderr bytes.Buffer
+	if err := applyQuarantine("key-vault", "components/azurerm/key-vault", nil, &stderr); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if stderr.Len() > 0 {
+		t.Errorf("expected no output for passing tests, got %q", stderr.String())
+	}
+}
+
+func TestRunOnModules_QuarantinedFailuresDoNotFail(t *testing.T) {
+	withQuarantineConfig(t, config.QuarantineEntry{Module: "flaky", Until: "2030-01-31"})
+	withNow(t, time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC))
+
+	modules := []ModuleInfo{
+		{Name: "flaky", Path: "components/flaky"},
+		{Name: "stable", Path: "components/stable"},
+	}
+	fn := withQuarantine(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		if mod.Name == "flaky" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}, func(mod ModuleInfo) (string, string) { return mod.Name, mod.Path })
+
+	results, err := runOnModulesWithResults(modules, false, 1, io.Discard, io.Discard, fn)
+	if err != nil {
+		t.Fatalf("expected quarantined failure not to fail the run, got %v", err)
+	}
+
+	summary := buildChatopsSummary("test", results, nil)
+	if summary.Results[0].Status != chatops.StatusQuarantined {
+		t.Errorf("flaky status = %s, want %s", summary.Results[0].Status, chatops.StatusQuarantined)
+	}
+	if summary.Results[1].Status != chatops.StatusSucceeded {
+		t.Errorf("stable status = %s, want %s", summary.Results[1].Status, chatops.StatusSucceeded)
+	}
+}
@@ -0,0 +1,318 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/release"
+	"github.com/TechnicallyJoe/terraform-motf/internal/spacelift"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseMajorFlag  bool     // Increment the major version
+	releaseMinorFlag  bool     // Increment the minor version
+	releasePatchFlag  bool     // Increment the patch version (default)
+	releaseSkipFlags  []string // Release steps to skip, in addition to release.skip
+	releasePushFlag   bool     // Push the release commit and tags
+	releaseRemoteFlag string   // Remote to push to
+)
+
+// releaseCmd represents the release command
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Version, document, and tag releases of selected modules",
+	Long: `Release each selected module. Select modules with --changed, --all, --select,
+or --type. A release runs these steps:
+  bump       increment module_version in .spacelift/config.yml
+  changelog  add the commit subjects since the last release to the module's CHANGELOG.md
+  commit     commit the bumped and changelog files
+  tag        create an annotated tag <module path>/v<version>, e.g.
+             components/azurerm/storage-account/v1.2.3
+  push       push HEAD and the new tags (only with --push or release.push)
+
+Modules without a module_version are versioned by their latest release tag,
+starting from 0.0.0. Skip steps with --skip or release.skip in .motf.yml. Use
+--dry-run to show each step without executing it.`,
+	Example: `  motf release --changed                    # Release changed modules with a patch bump
+  motf release --changed --minor --push     # Minor release, pushed to origin
+  motf release --select storage-account     # Release a single module
+  motf release --changed --skip changelog   # Don't update changelogs
+  motf release --changed --dry-run          # Show the releases without executing`,
+	Args: cobra.NoArgs,
+	RunE: runRelease,
+}
+
+func init() {
+	releaseCmd.Flags().BoolVar(&releaseMajorFlag, "major", false, "Increment the major version")
+	releaseCmd.Flags().BoolVar(&releaseMinorFlag, "minor", false, "Increment the minor version")
+	releaseCmd.Flags().BoolVar(&releasePatchFlag, "patch", false, "Increment the patch version (default)")
+	releaseCmd.Flags().StringSliceVar(&releaseSkipFlags, "skip", nil, "Release steps to skip: "+strings.Join(release.Steps, ", "))
+	releaseCmd.Flags().BoolVar(&releasePushFlag, "push", false, "Push the release commit and tags")
+	releaseCmd.Flags().StringVar(&releaseRemoteFlag, "remote", "", "Remote to push to (default: release.remote or origin)")
+	releaseCmd.Flags().BoolVar(&allFlag, "all", false, "Release all modules")
+	releaseCmd.Flags().BoolVar(&changedFlag, "changed", false, "Release modules changed compared to --ref")
+	releaseCmd.Flags().StringVar(&selectFlag, "select", "", "Release modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	releaseCmd.Flags().StringVar(&typeFlag, "type", "", "Release modules of a type (component, base, project)")
+	releaseCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(releaseCmd)
+	rootCmd.AddCommand(releaseCmd)
+}
+
+// moduleRelease is the planned release of one module
+type moduleRelease struct {
+	Module    string // Display path
+	Path      string // Slash-separated, relative to the repository root
+	Dir       string // Absolute module directory
+	Old       string
+	New       string
+	Tag       string
+	PrevTag   string
+	Versioned bool // Has a module_version to bump
+}
+
+func runRelease(cmd *cobra.Command, args []string) error {
+	if !selectingModules() {
+		return fmt.Errorf("release requires --changed, --all, --select, or --type")
+	}
+	if allFlag && changedFlag {
+		return fmt.Errorf("--all cannot be used with --changed")
+	}
+	if pathFlag != "" {
+		return fmt.Errorf("release cannot be used with --path, use --select to select modules")
+	}
+	part, err := versionPart(releaseMajorFlag, releaseMinorFlag, releasePatchFlag)
+	if err != nil {
+		return err
+	}
+	steps, err := releaseSteps()
+	if err != nil {
+		return err
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := selectModules(basePath)
+	if err != nil {
+		return err
+	}
+	if len(modules) == 0 {
+		fmt.Println(noModulesMessage(selectFlag))
+		return nil
+	}
+
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to get git root: %w", err)
+	}
+	g := release.Git{Dir: repoRoot}
+	tags, err := g.Tags()
+	if err != nil {
+		return err
+	}
+
+	var releases []moduleRelease
+	for _, mod := range modules {
+		r, err := planRelease(repoRoot, basePath, mod, tags, part, steps[release.StepBump])
+		if err != nil {
+			return fmt.Errorf("%s: %w", mod.Path, err)
+		}
+		releases = append(releases, r)
+	}
+
+	var files []string
+	for _, r := range releases {
+		changed, err := prepareRelease(g, r, part, steps)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Module, err)
+		}
+		files = append(files, changed...)
+	}
+
+	if steps[release.StepCommit] && len(files) > 0 {
+		message := releaseCommitMessage(releases)
+		if dryRunFlag {
+			fmt.Printf("[dry-run] Would commit %d file(s): %s\n", len(files), message)
+		} else {
+			if err := g.Commit(message, files...); err != nil {
+				return err
+			}
+			fmt.Printf("Committed %d file(s): %s\n", len(files), message)
+		}
+	}
+
+	var newTags []string
+	if steps[release.StepTag] {
+		for _, r := range releases {
+			if dryRunFlag {
+				fmt.Printf("[dry-run] Would tag %s\n", r.Tag)
+				continue
+			}
+			if err := g.Tag(r.Tag, fmt.Sprintf("Release %s v%s", r.Path, r.New)); err != nil {
+				return err
+			}
+			fmt.Printf("Tagged %s\n", r.Tag)
+		}
+		for _, r := range releases {
+			newTags = append(newTags, r.Tag)
+		}
+	}
+
+	if steps[release.StepPush] {
+		remote := cfg.Release.GetRemote()
+		if releaseRemoteFlag != "" {
+			remote = releaseRemoteFlag
+		}
+		refs := append([]string{"HEAD"}, newTags...)
+		if dryRunFlag {
+			fmt.Printf("[dry-run] Would push %s to %s\n", strings.Join(refs, " "), remote)
+		} else {
+			if err := g.Push(remote, refs...); err != nil {
+				return err
+			}
+			fmt.Printf("Pushed %s to %s\n", strings.Join(refs, " "), remote)
+		}
+	}
+
+	fmt.Println()
+	printReleases(releases)
+	return nil
+}
+
+// releaseSteps returns which release steps run: all except those in
+// release.skip and --skip. Push only runs with --push or release.push.
+func releaseSteps() (map[string]bool, error) {
+	for _, step := range releaseSkipFlags {
+		if !slices.Contains(release.Steps, step) {
+			return nil, fmt.Errorf("invalid --skip '%s': must be one of %s", step, strings.Join(release.Steps, ", "))
+		}
+	}
+	steps := make(map[string]bool, len(release.Steps))
+	for _, step := range release.Steps {
+		steps[step] = !slices.Contains(cfg.Release.GetSkip(), step) && !slices.Contains(releaseSkipFlags, step)
+	}
+	steps[release.StepPush] = steps[release.StepPush] && (releasePushFlag || cfg.Release.GetPush())
+	return steps, nil
+}
+
+// planRelease determines the old and new version and the tag of a module's
+// release. With a module_version, that is the version bumped (or released as
+// is when the bump step is skipped); otherwise the latest release tag is.
+func planRelease(repoRoot, basePath string, mod ModuleInfo, tags []string, part string, bump bool) (moduleRelease, error) {
+	dir, err := filepath.Abs(filepath.Join(basePath, mod.Path))
+	if err != nil {
+		return moduleRelease{}, err
+	}
+	relPath, err := repoRelativePath(repoRoot, dir)
+	if err != nil {
+		return moduleRelease{}, err
+	}
+	prevTag, tagVersion := release.LatestTag(tags, relPath)
+	r := moduleRelease{Module: mod.Path, Path: relPath, Dir: dir, PrevTag: prevTag}
+
+	if moduleVersion := spacelift.ReadModuleVersion(dir); moduleVersion != "" {
+		r.Versioned = true
+		r.Old, r.New = moduleVersion, moduleVersion
+		if bump {
+			_, r.New, err = spacelift.BumpModuleVersion(dir, part, true)
+		}
+	} else {
+		r.Old = tagVersion
+		if r.Old == "" {
+			r.Old = "0.0.0"
+		}
+		r.New, err = spacelift.BumpVersion(r.Old, part)
+	}
+	if errors.Is(err, spacelift.ErrNoModuleVersion) {
+		return moduleRelease{}, fmt.Errorf("module_version in %s is not MAJOR.MINOR.PATCH", path.Join(spacelift.DirSpacelift, spacelift.FileConfig))
+	}
+	if err != nil {
+		return moduleRelease{}, err
+	}
+
+	r.Tag = release.TagName(relPath, r.New)
+	if slices.Contains(tags, r.Tag) {
+		return moduleRelease{}, fmt.Errorf("tag %s already exists", r.Tag)
+	}
+	return r, nil
+}
+
+// prepareRelease runs the bump and changelog steps of a release and returns
+// the files they changed, relative to the repository root.
+func prepareRelease(g release.Git, r moduleRelease, part string, steps map[string]bool) ([]string, error) {
+	var files []string
+	if steps[release.StepBump] && r.Versioned {
+		if dryRunFlag {
+			fmt.Printf("[dry-run] Would bump %s: %s -> %s\n", r.Module, r.Old, r.New)
+		} else {
+			if _, _, err := spacelift.BumpModuleVersion(r.Dir, part, false); err != nil {
+				return nil, err
+			}
+			fmt.Printf("Bumped %s: %s -> %s\n", r.Module, r.Old, r.New)
+		}
+		files = append(files, path.Join(r.Path, spacelift.DirSpacelift, spacelift.FileConfig))
+	}
+
+	if steps[release.StepChangelog] {
+		changelog := cfg.Release.GetChangelog()
+		subjects, err := g.Subjects(r.PrevTag, r.Path)
+		if err != nil {
+			return nil, err
+		}
+		if dryRunFlag {
+			fmt.Printf("[dry-run] Would add v%s with %d change(s) to %s\n", r.New, len(subjects), path.Join(r.Module, changelog))
+		} else {
+			section := release.ChangelogSection(r.New, now(), subjects)
+			if err := release.PrependChangelog(filepath.Join(r.Dir, changelog), section); err != nil {
+				return nil, fmt.Errorf("failed to update %s: %w", changelog, err)
+			}
+			fmt.Printf("Added v%s with %d change(s) to %s\n", r.New, len(subjects), path.Join(r.Module, changelog))
+		}
+		files = append(files, path.Join(r.Path, changelog))
+	}
+	return files, nil
+}
+
+// releaseCommitMessage returns the subject of the release commit, e.g.
+// "chore(release): storage-account v1.2.4, vnet v0.3.0".
+func releaseCommitMessage(releases []moduleRelease) string {
+	names := make([]string, len(releases))
+	for i, r := range releases {
+		names[i] = fmt.Sprintf("%s v%s", path.Base(r.Path), r.New)
+	}
+	return "chore(release): " + strings.Join(names, ", ")
+}
+
+// printReleases outputs the old and new version and the tag of each release
+func printReleases(releases []moduleRelease) {
+	if plainFlag {
+		for i, r := range releases {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("Module: %s\n", r.Module)
+			fmt.Printf("Old: %s\n", r.Old)
+			fmt.Printf("New: %s\n", r.New)
+			fmt.Printf("Tag: %s\n", r.Tag)
+		}
+		return
+	}
+
+	moduleWidth, oldWidth, newWidth := len("MODULE"), len("OLD"), len("NEW")
+	for _, r := range releases {
+		moduleWidth = max(moduleWidth, len(r.Module))
+		oldWidth = max(oldWidth, len(r.Old))
+		newWidth = max(newWidth, len(r.New))
+	}
+	fmt.Printf("%-*s  %-*s  %-*s  %s\n", moduleWidth, "MODULE", oldWidth, "OLD", newWidth, "NEW", "TAG")
+	for _, r := range releases {
+		fmt.Printf("%-*s  %-*s  %-*s  %s\n", moduleWidth, r.Module, oldWidth, r.Old, newWidth, r.New, r.Tag)
+	}
+}
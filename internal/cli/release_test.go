@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/release"
+	"github.com/TechnicallyJoe/terraform-motf/internal/spacelift"
+)
+
+func resetReleaseFlags(t *testing.T) {
+	t.Helper()
+	oldNow := now
+	now = func() time.Time { return time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() {
+		releaseMajorFlag = false
+		releaseMinorFlag = false
+		releasePatchFlag = false
+		releaseSkipFlags = nil
+		releasePushFlag = false
+		releaseRemoteFlag = ""
+		now = oldNow
+	})
+}
+
+// setupReleaseRepo creates a git repository with a committed component that
+// has a module_version and a project without one. It returns the repository
+// root and a function that runs git in it.
+func setupReleaseRepo(t *testing.T) (string, func(args ...string) string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+		return string(output)
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	git("config", "tag.gpgSign", "false")
+	git("config", "commit.gpgSign", "false")
+
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createSpaceliftModule(t, tmpDir, filepath.Join(DirComponents, "storage"), "1.2.3")
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "prod"))
+	git("add", "-A")
+	git("commit", "-m", "feat: add modules")
+	return tmpDir, git
+}
+
+func TestReleaseCmd_Flags(t *testing.T) {
+	for _, name := range []string{"major", "minor", "patch", "skip", "push", "remote", "all", "changed", "select", "type", "ref"} {
+		if releaseCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected release command to have --%s flag", name)
+		}
+	}
+}
+
+func TestRelease_RequiresSelection(t *testing.T) {
+	resetFlags(t)
+	resetReleaseFlags(t)
+
+	err := runRelease(releaseCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "requires --changed") {
+		t.Fatalf("expected selection error, got %v", err)
+	}
+}
+
+func TestRelease_InvalidSkip(t *testing.T) {
+	resetFlags(t)
+	resetReleaseFlags(t)
+	withConfig(t, &config.Config{Root: t.TempDir(), Binary: "terraform"})
+	allFlag = true
+	releaseSkipFlags = []string{"deploy"}
+
+	err := runRelease(releaseCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid --skip 'deploy'") {
+		t.Fatalf("expected invalid --skip error, got %v", err)
+	}
+}
+
+func TestReleaseSteps(t *testing.T) {
+	resetReleaseFlags(t)
+	withConfig(t, &config.Config{Release: &config.ReleaseConfig{Skip: []string{release.StepChangelog}}})
+	releaseSkipFlags = []string{release.StepCommit}
+
+	steps, err := releaseSteps()
+	if err != nil {
+		t.Fatalf("releaseSteps() error = %v", err)
+	}
+	want := map[string]bool{
+		release.StepBump:      true,
+		release.StepChangelog: false,
+		release.StepCommit:    false,
+		release.StepTag:       true,
+		release.StepPush:      false,
+	}
+	for step, enabled := range want {
+		if steps[step] != enabled {
+			t.Errorf("step %s enabled = %v, want %v", step, steps[step], enabled)
+		}
+	}
+
+	releasePushFlag = true
+	if steps, _ := releaseSteps(); !steps[release.StepPush] {
+		t.Error("expected --push to enable the push step")
+	}
+}
+
+func TestRelease_BumpsChangelogCommitsAndTags(t *testing.T) {
+	resetFlags(t)
+	resetReleaseFlags(t)
+	tmpDir, git := setupReleaseRepo(t)
+	git("tag", "projects/prod/v0.1.0")
+	allFlag = true
+
+	if err := runRelease(releaseCmd, nil); err != nil {
+		t.Fatalf("runRelease() error = %v", err)
+	}
+
+	storage := filepath.Join(tmpDir, DirComponents, "storage")
+	if got := spacelift.ReadModuleVersion(storage); got != "1.2.4" {
+		t.Errorf("module_version = %s, want 1.2.4", got)
+	}
+	changelog, err := os.ReadFile(filepath.Join(storage, release.DefaultChangelog))
+	if err != nil {
+		t.Fatalf("failed to read changelog: %v", err)
+	}
+	if want := "# Changelog\n\n## v1.2.4 (2024-05-01)\n\n- feat: add modules\n"; string(changelog) != want {
+		t.Errorf("changelog = %q, want %q", changelog, want)
+	}
+
+	tags := git("tag", "--list")
+	for _, tag := range []string{"components/storage/v1.2.4", "projects/prod/v0.1.1"} {
+		if !strings.Contains(tags, tag) {
+			t.Errorf("expected tag %s, got %s", tag, tags)
+		}
+	}
+	if subject := git("log", "-1", "--format=%s"); !strings.Contains(subject, "chore(release): ") || !strings.Contains(subject, "storage v1.2.4") {
+		t.Errorf("unexpected release commit %q", subject)
+	}
+	if status := git("status", "--porcelain"); status != "" {
+		t.Errorf("expected release files to be committed, got status:\n%s", status)
+	}
+}
+
+func TestRelease_ExistingTag(t *testing.T) {
+	resetFlags(t)
+	resetReleaseFlags(t)
+	_, git := setupReleaseRepo(t)
+	git("tag", "components/storage/v1.2.4")
+	selectFlag = "storage"
+
+	err := runRelease(releaseCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "tag components/storage/v1.2.4 already exists") {
+		t.Fatalf("expected existing tag error, got %v", err)
+	}
+}
+
+func TestRelease_DryRun(t *testing.T) {
+	resetFlags(t)
+	resetReleaseFlags(t)
+	tmpDir, git := setupReleaseRepo(t)
+	allFlag = true
+	dryRunFlag = true
+	releasePushFlag = true
+
+	if err := runRelease(releaseCmd, nil); err != nil {
+		t.Fatalf("runRelease() error = %v", err)
+	}
+	storage := filepath.Join(tmpDir, DirComponents, "storage")
+	if got := spacelift.ReadModuleVersion(storage); got != "1.2.3" {
+		t.Errorf("expected --dry-run not to bump, module_version = %s", got)
+	}
+	if _, err := os.Stat(filepath.Join(storage, release.DefaultChangelog)); !os.IsNotExist(err) {
+		t.Errorf("expected --dry-run not to write the changelog, got %v", err)
+	}
+	if tags := git("tag", "--list"); tags != "" {
+		t.Errorf("expected --dry-run not to tag, got %s", tags)
+	}
+}
+
+func TestReleaseCommitMessage(t *testing.T) {
+	releases := []moduleRelease{
+		{Path: "components/storage", New: "1.2.4"},
+		{Path: "projects/prod", New: "0.1.0"},
+	}
+	if got, want := releaseCommitMessage(releases), "chore(release): storage v1.2.4, prod v0.1.0"; got != want {
+		t.Errorf("releaseCommitMessage() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// flakeHistoryFile is the file in the log directory that accumulates flake statistics
+const flakeHistoryFile = "flake-history.json"
+
+// retriesFlag overrides parallelism.retries, or test.retries for test
+var retriesFlag int
+
+// retriedCommands are the commands whose failed modules are retried with
+// parallelism.retries. test has test.retries, which marks passes on retry
+// flaky instead. upgrade is lock upgrade, since motf upgrade runs in no modules.
+var retriedCommands = map[string]bool{"init": true, "val": true, "plan": true, "task": true, "exec": true, "lock": true, "upgrade": true}
+
+// addRetriesFlag adds --retries to a command whose failed modules are retried
+// with parallelism.retries.
+func addRetriesFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry failed modules up to N times, with backoff between attempts (default: parallelism.retries)")
+}
+
+// withRunRetries reruns fn for a failed module up to retries times, for
+// transient failures such as provider downloads or rate limits. It waits
+// delay before the first retry and twice as long before each next one.
+// Interrupted and timed out modules aren't retried, and unlike test retries
+// a pass on retry isn't reported as flaky.
+func withRunRetries(fn ModuleRunner, retries int, delay time.Duration) ModuleRunner {
+	if retries <= 0 {
+		return fn
+	}
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		wait := delay
+		for attempt := 1; ; attempt++ {
+			err := fn(mod, stdout, stderr)
+			if err == nil {
+				if attempt > 1 {
+					_, _ = fmt.Fprintf(stderr, "[retry] %s succeeded on attempt %d/%d\n", mod.Name, attempt, retries+1)
+				}
+				return nil
+			}
+			if !retryable(err) {
+				return err
+			}
+			if attempt > retries {
+				return fmt.Errorf("failed %d attempt(s): %w", attempt, err)
+			}
+			_, _ = fmt.Fprintf(stderr, "[retry] %s failed on attempt %d/%d (%v), retrying in %s\n", mod.Name, attempt, retries+1, err, wait)
+			select {
+			case <-time.After(wait):
+			case <-runContext.Done():
+				return err
+			}
+			wait *= 2
+		}
+	}
+}
+
+// retryable reports whether a module error is worth a retry: not a non-fatal
+// outcome, and not a module that was interrupted, timed out, or stopped by
+// --fail-fast.
+func retryable(err error) bool {
+	return !isNonFatal(err) && !isStopped(err)
+}
+
+// isStopped reports whether a module was interrupted, timed out, or stopped
+// by --fail-fast, rather than failing on its own.
+func isStopped(err error) bool {
+	var timedOut *moduleTimeoutError
+	var failed *failFastError
+	return errors.Is(err, errInterrupted) || errors.As(err, &timedOut) || errors.As(err, &failed)
+}
+
+// flakyError marks module tests that failed and then passed on retry. It is
+// reported but doesn't fail the run.
+type flakyError struct {
+	failures int   // Failed attempts before the pass
+	err      error // Error of the last failed attempt
+}
+
+func (e *flakyError) Error() string {
+	return fmt.Sprintf("flaky: passed after %d failed attempt(s), last error: %v", e.failures, e.err)
+}
+
+func (e *flakyError) Unwrap() error {
+	return e.err
+}
+
+// isNonFatal reports whether a module error is reported without failing the
+// run: a quarantined failure, a flaky pass, a plan with changes, or a skipped
+// module.
+func isNonFatal(err error) bool {
+	var quarantined *quarantinedError
+	var flaky *flakyError
+	var changes *planChangesError
+	var skipped *skippedError
+	return errors.As(err, &quarantined) || errors.As(err, &flaky) || errors.As(err, &changes) || errors.As(err, &skipped)
+}
+
+// flakeStats are the accumulated test outcomes of a module
+type flakeStats struct {
+	Module    string    `json:"module"` // Module path
+	Runs      int       `json:"runs"`
+	Flaky     int       `json:"flaky"`  // Runs that passed only on retry
+	Failed    int       `json:"failed"` // Runs that failed every attempt
+	LastFlaky time.Time `json:"last_flaky,omitzero"`
+}
+
+// retrier reruns failed module tests and records their outcomes.
+type retrier struct {
+	retries int
+	mu      sync.Mutex
+	stats   map[string]*flakeStats // By module path, for this run
+}
+
+// newRetrier creates a retrier that reruns failed tests up to retries times.
+func newRetrier(retries int) *retrier {
+	return &retrier{retries: retries, stats: make(map[string]*flakeStats)}
+}
+
+// wrap reruns fn on failure, up to the configured number of retries. A pass
+// after failures returns a flakyError; if every attempt fails, the last error
+// is returned. Errors that aren't retryable are returned as is, unrecorded.
+func (r *retrier) wrap(fn ModuleRunner) ModuleRunner {
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		failures := 0
+		var lastErr error
+		for {
+			err := fn(mod, stdout, stderr)
+			if err == nil {
+				break
+			}
+			if !retryable(err) {
+				// A skipped, interrupted, timed out, or fail-fast stopped module
+				// didn't finish its tests, so there's nothing to retry or record
+				return err
+			}
+			lastErr = err
+			if failures == r.retries {
+				r.record(mod, failures, false)
+				if r.retries > 0 {
+					return fmt.Errorf("failed %d attempt(s): %w", failures+1, err)
+				}
+				return err
+			}
+			failures++
+			_, _ = fmt.Fprintf(stderr, "[retry] Tests of %s failed (%v), retrying (%d/%d)\n", mod.Name, err, failures, r.retries)
+		}
+
+		r.record(mod, failures, true)
+		if failures == 0 {
+			return nil
+		}
+		_, _ = fmt.Fprintf(stderr, "[retry] Tests of %s passed on retry, marking them flaky\n", mod.Name)
+		return &flakyError{failures: failures, err: lastErr}
+	}
+}
+
+// record stores the outcome of a module's tests for this run.
+func (r *retrier) record(mod ModuleInfo, failures int, passed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats[mod.Path]
+	if s == nil {
+		s = &flakeStats{Module: mod.Path}
+		r.stats[mod.Path] = s
+	}
+	s.Runs++
+	switch {
+	case !passed:
+		s.Failed++
+	case failures > 0:
+		s.Flaky++
+		s.LastFlaky = now().UTC()
+	}
+}
+
+// saveHistory merges the outcomes of this run into <dir>/flake-history.json,
+// sorted with the flakiest modules first. It is a no-op when dir is empty.
+func (r *retrier) saveHistory(dir string) error {
+	if dir == "" || len(r.stats) == 0 {
+		return nil
+	}
+	path := filepath.Join(dir, flakeHistoryFile)
+
+	history, err := loadFlakeHistory(path)
+	if err != nil {
+		return err
+	}
+
+	merged := make(map[string]flakeStats, len(history))
+	for _, h := range history {
+		merged[h.Module] = h
+	}
+	r.mu.Lock()
+	for _, s := range r.stats {
+		h := merged[s.Module]
+		h.Module = s.Module
+		h.Runs += s.Runs
+		h.Flaky += s.Flaky
+		h.Failed += s.Failed
+		if s.LastFlaky.After(h.LastFlaky) {
+			h.LastFlaky = s.LastFlaky
+		}
+		merged[s.Module] = h
+	}
+	r.mu.Unlock()
+
+	history = slices.Collect(maps.Values(merged))
+	sort.SliceStable(history, func(i, j int) bool {
+		if history[i].Flaky != history[j].Flaky {
+			return history[i].Flaky > history[j].Flaky
+		}
+		return history[i].Module < history[j].Module
+	})
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode flake history: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write flake history: %w", err)
+	}
+	return nil
+}
+
+// loadFlakeHistory reads the flake history at path; a missing file is an empty history.
+func loadFlakeHistory(path string) ([]flakeStats, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flake history: %w", err)
+	}
+	var history []flakeStats
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse flake history %s: %w", path, err)
+	}
+	return history, nil
+}
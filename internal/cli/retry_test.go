@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+)
+
+// failingTimes returns a module runner that fails the first n calls.
+func failingTimes(n int) ModuleRunner {
+	calls := 0
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		calls++
+		if calls <= n {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}
+}
+
+func TestRetrier_FlakyPass(t *testing.T) {
+	withNow(t, time.Date(2030, 1, 10, 12, 0, 0, 0, time.UTC))
+	r := newRetrier(2)
+	mod := ModuleInfo{Name: "key-vault", Path: "components/key-vault"}
+
+	var stderr bytes.Buffer
+	err := r.wrap(failingTimes(1))(mod, io.Discard, &stderr)
+
+	var flaky *flakyError
+	if !errors.As(err, &flaky) {
+		t.Fatalf("expected flaky error, got %v", err)
+	}
+	if flaky.failures != 1 {
+		t.Errorf("failures = %d, want 1", flaky.failures)
+	}
+	if !strings.Contains(stderr.String(), "retrying (1/2)") || !strings.Contains(stderr.String(), "marking them flaky") {
+		t.Errorf("stderr = %q, want retry and flaky notes", stderr.String())
+	}
+
+	s := r.stats[mod.Path]
+	if s.Runs != 1 || s.Flaky != 1 || s.Failed != 0 {
+		t.Errorf("stats = %+v, want 1 run, 1 flaky", *s)
+	}
+}
+
+func TestRetrier_AllAttemptsFail(t *testing.T) {
+	r := newRetrier(2)
+	mod := ModuleInfo{Name: "key-vault", Path: "components/key-vault"}
+
+	err := r.wrap(failingTimes(3))(mod, io.Discard, io.Discard)
+	if err == nil || isNonFatal(err) {
+		t.Fatalf("expected a fatal error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "failed 3 attempt(s)") {
+		t.Errorf("error = %q, want attempt count", err.Error())
+	}
+	if s := r.stats[mod.Path]; s.Failed != 1 || s.Flaky != 0 {
+		t.Errorf("stats = %+v, want 1 failed", *s)
+	}
+}
+
+func TestRetrier_NoRetries(t *testing.T) {
+	r := newRetrier(0)
+	testErr := errors.New("exit status 1")
+
+	err := r.wrap(func(ModuleInfo, io.Writer, io.Writer) error { return testErr })(ModuleInfo{Name: "vnet"}, io.Discard, io.Discard)
+	if err != testErr {
+		t.Errorf("expected the original error without retries, got %v", err)
+	}
+}
+
+func TestRetrier_SkippedModulesAreNotRetried(t *testing.T) {
+	r := newRetrier(2)
+	calls := 0
+	err := r.wrap(func(ModuleInfo, io.Writer, io.Writer) error {
+		calls++
+		return &skippedError{reason: "no feature files in tests/features"}
+	})(ModuleInfo{Name: "vnet", Path: "components/vnet"}, io.Discard, io.Discard)
+
+	var skipped *skippedError
+	if !errors.As(err, &skipped) || calls != 1 {
+		t.Errorf("expected one call returning the skipped error, got %d call(s) and %v", calls, err)
+	}
+	if len(r.stats) != 0 {
+		t.Errorf("expected skipped modules not to be recorded, got %v", r.stats)
+	}
+}
+
+func TestRetrier_StoppedModulesAreNotRetried(t *testing.T) {
+	mod := ModuleInfo{Name: "vnet", Path: "components/vnet"}
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"interrupted", fmt.Errorf("terraform test: %w", errInterrupted)},
+		{"timed out", &moduleTimeoutError{timeout: time.Minute}},
+		{"fail-fast", &failFastError{module: ModuleInfo{Name: "storage"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRetrier(2)
+			calls := 0
+			err := r.wrap(func(ModuleInfo, io.Writer, io.Writer) error {
+				calls++
+				return tt.err
+			})(mod, io.Discard, io.Discard)
+
+			if err != tt.err || calls != 1 {
+				t.Errorf("expected one call returning %v, got %d call(s) and %v", tt.err, calls, err)
+			}
+			if len(r.stats) != 0 {
+				t.Errorf("expected stopped modules not to be recorded, got %v", r.stats)
+			}
+		})
+	}
+}
+
+func TestRetrier_SaveHistoryMerges(t *testing.T) {
+	dir := t.TempDir()
+	existing := `[{"module": "components/vnet", "runs": 3, "flaky": 1, "failed": 0}]`
+	if err := os.WriteFile(filepath.Join(dir, flakeHistoryFile), []byte(existing), 0600); err != nil {
+		t.Fatalf("failed to write history: %v", err)
+	}
+
+	withNow(t, time.Date(2030, 1, 10, 12, 0, 0, 0, time.UTC))
+	r := newRetrier(1)
+	r.record(ModuleInfo{Path: "components/vnet"}, 0, true)
+	r.record(ModuleInfo{Path: "components/key-vault"}, 1, true)
+	r.record(ModuleInfo{Path: "components/key-vault"}, 1, true)
+
+	if err := r.saveHistory(dir); err != nil {
+		t.Fatalf("saveHistory failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, flakeHistoryFile))
+	if err != nil {
+		t.Fatalf("failed to read history: %v", err)
+	}
+	var history []flakeStats
+	if err := json.Unmarshal(data, &history); err != nil {
+		t.Fatalf("invalid history: %v", err)
+	}
+
+	if len(history) != 2 {
+		t.Fatalf("expected 2 modules, got %d", len(history))
+	}
+	// Flakiest module first
+	if history[0].Module != "components/key-vault" || history[0].Flaky != 2 || history[0].LastFlaky.IsZero() {
+		t.Errorf("history[0] = %+v, want key-vault with 2 flaky runs", history[0])
+	}
+	if history[1].Module != "components/vnet" || history[1].Runs != 4 || history[1].Flaky != 1 {
+		t.Errorf("history[1] = %+v, want vnet merged to 4 runs", history[1])
+	}
+}
+
+func TestRetrier_SaveHistoryWithoutLogDir(t *testing.T) {
+	r := newRetrier(1)
+	r.record(ModuleInfo{Path: "components/vnet"}, 1, true)
+	if err := r.saveHistory(""); err != nil {
+		t.Errorf("expected no-op without log dir, got %v", err)
+	}
+}
+
+func TestRunOnModules_FlakyModulesDoNotFail(t *testing.T) {
+	modules := []ModuleInfo{{Name: "flaky", Path: "components/flaky"}}
+	r := newRetrier(1)
+
+	results, err := runOnModulesWithResults(modules, false, 1, io.Discard, io.Discard, r.wrap(failingTimes(1)))
+	if err != nil {
+		t.Fatalf("expected flaky module not to fail the run, got %v", err)
+	}
+
+	summary := buildChatopsSummary("test", results, nil)
+	if summary.Results[0].Status != chatops.StatusFlaky {
+		t.Errorf("status = %s, want %s", summary.Results[0].Status, chatops.StatusFlaky)
+	}
+}
+
+func TestWithRunRetries_PassOnRetry(t *testing.T) {
+	mod := ModuleInfo{Name: "network", Path: "components/network"}
+	var stderr bytes.Buffer
+	err := withRunRetries(failingTimes(2), 2, time.Millisecond)(mod, io.Discard, &stderr)
+	if err != nil {
+		t.Fatalf("expected a pass on retry to succeed, got %v", err)
+	}
+	for _, want := range []string{
+		"[retry] network failed on attempt 1/3 (exit status 1), retrying in 1ms",
+		"[retry] network failed on attempt 2/3 (exit status 1), retrying in 2ms",
+		"[retry] network succeeded on attempt 3/3",
+	} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("stderr = %q, want %q", stderr.String(), want)
+		}
+	}
+}
+
+func TestWithRunRetries_AllAttemptsFail(t *testing.T) {
+	mod := ModuleInfo{Name: "network", Path: "components/network"}
+	err := withRunRetries(failingTimes(3), 1, time.Millisecond)(mod, io.Discard, io.Discard)
+	if err == nil || err.Error() != "failed 2 attempt(s): exit status 1" {
+		t.Fatalf("expected the attempts in the error, got %v", err)
+	}
+}
+
+func TestWithRunRetries_NotRetried(t *testing.T) {
+	mod := ModuleInfo{Name: "network", Path: "components/network"}
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "skipped", err: &skippedError{reason: "no tests"}},
+		{name: "plan changes", err: &planChangesError{}},
+		{name: "interrupted", err: errInterrupted},
+		{name: "timed out", err: fmt.Errorf("terraform plan stopped: %w", &moduleTimeoutError{timeout: time.Minute})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := withRunRetries(func(ModuleInfo, io.Writer, io.Writer) error {
+				calls++
+				return tt.err
+			}, 2, time.Millisecond)(mod, io.Discard, io.Discard)
+			if calls != 1 || !errors.Is(err, tt.err) {
+				t.Errorf("calls = %d, err = %v; want a single attempt returning %v", calls, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestExecCmd_Retries(t *testing.T) {
+	resetFlags(t)
+	t.Cleanup(func() { execShellFlag, execTemplateFlag = "", false })
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte("parallelism:\n  retry_delay: 10ms\n"), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "alpha"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirBases, "beta"))
+
+	// Each module fails its first attempt only
+	rootCmd.SetArgs([]string{"exec", "--retries", "1", "--", "test -f attempted || { touch attempted; exit 1; }"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("expected the modules to pass on retry, got %v", err)
+	}
+}
+
+func TestRootCmd_InvalidRetries(t *testing.T) {
+	resetFlags(t)
+	rootCmd.SetArgs([]string{"val", "--all", "--retries", "-1"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --retries -1: must be 0 or more") {
+		t.Fatalf("expected invalid --retries error, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/codeowners"
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+)
+
+// Output formats for list and changed --output
+const (
+	outputReviewers = "reviewers" // One owner per line
+	outputGh        = "gh"        // A gh command that requests the owners' review
+)
+
+// validateReviewersOutput checks an --output format, which may be empty.
+func validateReviewersOutput(format string) error {
+	switch format {
+	case "", outputReviewers, outputGh:
+		return nil
+	}
+	return fmt.Errorf("invalid --output '%s': must be %s or %s", format, outputReviewers, outputGh)
+}
+
+// validateListOutput checks --output and that it isn't combined with other output flags.
+func validateListOutput() error {
+	if err := validateReviewersOutput(listOutputFlag); err != nil {
+		return err
+	}
+	if listOutputFlag != "" && (listJsonFlag || listNamesOnlyFlag) {
+		return fmt.Errorf("--output cannot be used with --json or --names")
+	}
+	return nil
+}
+
+// printReviewers outputs the merged CODEOWNERS owners of modules in format.
+// Modules without owners are reported on stderr.
+func printReviewers(basePath string, modules []ModuleInfo, format string) error {
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to get git root: %w", err)
+	}
+	owners, err := codeowners.Load(repoRoot)
+	if errors.Is(err, codeowners.ErrNotFound) {
+		return fmt.Errorf("%w (looked in %s)", err, strings.Join(codeowners.Locations, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	reviewers, unowned, err := moduleReviewers(owners, repoRoot, basePath, modules)
+	if err != nil {
+		return err
+	}
+	for _, mod := range unowned {
+		logging.Warnf("no CODEOWNERS owner for %s", mod.Path)
+	}
+
+	if format == outputGh {
+		if handles := ghReviewers(reviewers); len(handles) > 0 {
+			fmt.Printf("gh pr edit --add-reviewer %s\n", strings.Join(handles, ","))
+		}
+		return nil
+	}
+	for _, r := range reviewers {
+		fmt.Println(r)
+	}
+	return nil
+}
+
+// moduleReviewers returns the sorted, deduplicated owners of the module
+// directories, and the modules that have no owners.
+func moduleReviewers(owners *codeowners.File, repoRoot, basePath string, modules []ModuleInfo) ([]string, []ModuleInfo, error) {
+	absRoot, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reviewers []string
+	var unowned []ModuleInfo
+	for _, mod := range modules {
+		absPath, err := filepath.Abs(filepath.Join(basePath, mod.Path))
+		if err != nil {
+			return nil, nil, err
+		}
+		relPath, err := filepath.Rel(absRoot, absPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("module %s is outside the repository: %w", mod.Path, err)
+		}
+
+		modOwners := owners.Owners(relPath)
+		if len(modOwners) == 0 {
+			unowned = append(unowned, mod)
+		}
+		reviewers = append(reviewers, modOwners...)
+	}
+	slices.Sort(reviewers)
+	return slices.Compact(reviewers), unowned, nil
+}
+
+// ghReviewers converts CODEOWNERS owners to gh reviewer handles: "@user" and
+// "@org/team" lose the @, email owners are skipped since gh can't request them.
+func ghReviewers(reviewers []string) []string {
+	var handles []string
+	for _, r := range reviewers {
+		if handle, ok := strings.CutPrefix(r, "@"); ok {
+			handles = append(handles, handle)
+		}
+	}
+	return handles
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/codeowners"
+)
+
+func TestModuleReviewers(t *testing.T) {
+	owners, err := codeowners.Parse([]byte(`
+/infra/components/          @org/platform
+/infra/components/azurerm/  @org/azure @alice
+/infra/projects/prod-*      @org/deployments @alice
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	repoRoot := t.TempDir()
+	basePath := filepath.Join(repoRoot, "infra")
+	modules := []ModuleInfo{
+		{Name: "key-vault", Path: filepath.Join(DirComponents, "azurerm", "key-vault")},
+		{Name: "prod-eu", Path: filepath.Join(DirProjects, "prod-eu")},
+		{Name: "shared", Path: filepath.Join(DirBases, "shared")},
+	}
+
+	reviewers, unowned, err := moduleReviewers(owners, repoRoot, basePath, modules)
+	if err != nil {
+		t.Fatalf("moduleReviewers() error = %v", err)
+	}
+	if got := strings.Join(reviewers, " "); got != "@alice @org/azure @org/deployments" {
+		t.Errorf("reviewers = %q, want merged and sorted owners", got)
+	}
+	if len(unowned) != 1 || unowned[0].Name != "shared" {
+		t.Errorf("unowned = %v, want [shared]", unowned)
+	}
+}
+
+func TestGhReviewers(t *testing.T) {
+	got := ghReviewers([]string{"@alice", "@org/azure", "bob@example.com"})
+	if strings.Join(got, ",") != "alice,org/azure" {
+		t.Errorf("ghReviewers() = %v, want [alice org/azure]", got)
+	}
+}
+
+func TestValidateListOutput(t *testing.T) {
+	resetFlags(t)
+	t.Cleanup(func() {
+		listOutputFlag = ""
+		listJsonFlag = false
+	})
+
+	listOutputFlag = "owners"
+	if err := validateListOutput(); err == nil || !strings.Contains(err.Error(), "invalid --output") {
+		t.Errorf("expected invalid --output error, got %v", err)
+	}
+
+	listOutputFlag = outputGh
+	listJsonFlag = true
+	if err := validateListOutput(); err == nil || !strings.Contains(err.Error(), "--json") {
+		t.Errorf("expected --output/--json error, got %v", err)
+	}
+
+	listJsonFlag = false
+	if err := validateListOutput(); err != nil {
+		t.Errorf("validateListOutput() error = %v", err)
+	}
+}
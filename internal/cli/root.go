@@ -5,7 +5,8 @@ import (
 	"os"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
-	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/TechnicallyJoe/terraform-motf/internal/i18n"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -17,24 +18,36 @@ var (
 )
 
 var (
-	cfg    *config.Config
-	runner *terraform.Runner
+	cfg         *config.Config
+	commandName string // Name of the command being executed (e.g. "plan")
 
 	// Global flags (persistent across all commands)
 	pathFlag   string   // Explicit path to module
 	argsFlag   []string // Extra arguments passed to terraform/tofu
 	configFlag string   // Explicit path to config file
+	dryRunFlag bool     // Print resolved commands instead of executing them
+	indexFlag  string   // Module index file to read modules from instead of walking the repository
+
+	allowNonModuleFlag bool // Run in a --path inside an examples or tests directory instead of redirecting to the module
 
 	// Command-specific flags
 	// Note: These are registered per-command but share state here for simplicity.
 	// Each command that uses these flags registers them in its own init().
 	initFlag        bool   // Run init before the command (fmt, validate)
 	changedFlag     bool   // Run command against changed modules
+	allFlag         bool   // Run command against all modules
+	selectFlag      string // Run command against modules matching a wildcard pattern
+	typeFlag        string // Only include modules of a type (component, base, project)
 	refFlag         string // Ref for change detection (defaults to auto-detect)
+	sinceFlag       string // Detect changes by commits since a date instead of against a ref
+	fromFlag        string // Detect changes between two refs: start of the range
+	toFlag          string // Detect changes between two refs: end of the range (default: HEAD)
+	mergeBaseFlag   bool   // Compare with the merge base of the ref and HEAD instead of the ref itself
 	searchFlag      string // Filter pattern for list command
 	exampleFlag     string // Target a specific example instead of the module (init, fmt, validate)
 	parallelFlag    bool   // Run commands in parallel (init, fmt, validate, test, plan, task)
 	maxParallelFlag int    // Maximum parallel jobs to run (default: number of CPU cores)
+	logDirFlag      string // Directory to write per-module log files to
 )
 
 // versionTemplate returns the version string with commit and date.
@@ -47,12 +60,9 @@ func versionTemplate() string {
 // rootCmd represents the base command
 var rootCmd = &cobra.Command{
 	Use:     "motf",
-	Short:   "Terraform Monorepo Orchestrator (pronounced 'motif')",
+	Short:   i18n.T(i18n.MsgRootShort),
 	Version: version,
-	Long: `motf (Terraform Monorepo Orchestrator) is a CLI tool for working with Terraform monorepos.
-
-It supports running terraform/tofu commands on components, bases, and projects organized
-in a structured monorepo.`,
+	Long:    i18n.T(i18n.MsgRootLong),
 	Example: `  motf fmt storage-account         # Run fmt on storage-account (searches all types)
   motf val k8s-argocd              # Run validate on k8s-argocd
   motf val -i k8s-argocd           # Run init then validate on k8s-argocd
@@ -60,6 +70,20 @@ in a structured monorepo.`,
   motf fmt --path iac/components/azurerm/storage-account  # Run fmt on explicit path
   motf init storage-account -a -upgrade -a -reconfigure  # Run init with extra args`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		commandName = cmd.Name()
+		if err := configureLogging(cmd, os.Getenv); err != nil {
+			return err
+		}
+		applyPlainEnv(cmd, os.Getenv)
+		applyNoColorEnv(cmd, os.Getenv)
+
+		if err := validateChatopsFlags(); err != nil {
+			return err
+		}
+		if _, err := parseFailureInjection(injectFailureFlag); err != nil {
+			return err
+		}
+
 		// Load configuration
 		wd, err := os.Getwd()
 		if err != nil {
@@ -68,8 +92,14 @@ in a structured monorepo.`,
 
 		cfg, err = config.Load(wd, configFlag)
 		if err != nil {
-			return err
+			// These check, replace, or don't need the config file, so they
+			// work when it is broken
+			if cmd != configValidateCmd && cmd != configInitCmd && cmd != configSchemaCmd {
+				return err
+			}
+			cfg = config.DefaultConfig()
 		}
+		logging.Debugf("config file: %s, root: %s", valueOrDefault(cfg.ConfigPath, "none"), cfg.Root)
 
 		// Merge CLI flags into config (CLI takes priority)
 		// Centralize the "CLI overrides config" logic here
@@ -79,9 +109,42 @@ in a structured monorepo.`,
 			}
 			cfg.Parallelism.MaxJobs = maxParallelFlag
 		}
-
-		// Create terraform runner with config
-		runner = terraform.NewRunner(cfg)
+		if cmd.Flags().Changed("log-dir") {
+			if cfg.Parallelism == nil {
+				cfg.Parallelism = &config.ParallelismConfig{}
+			}
+			cfg.Parallelism.LogDir = logDirFlag
+		}
+		if cmd.Flags().Changed("timeout") {
+			if timeoutFlag < 0 {
+				return fmt.Errorf("invalid --timeout '%s': must be positive, e.g. 30m, or 0 for no limit", timeoutFlag)
+			}
+			if cfg.Parallelism == nil {
+				cfg.Parallelism = &config.ParallelismConfig{}
+			}
+			// 0 lifts the limit of parallelism.timeout
+			cfg.Parallelism.Timeout = ""
+			if timeoutFlag > 0 {
+				cfg.Parallelism.Timeout = timeoutFlag.String()
+			}
+		}
+		if cmd.Flags().Changed("retries") {
+			if retriesFlag < 0 {
+				return fmt.Errorf("invalid --retries %d: must be 0 or more", retriesFlag)
+			}
+			if cmd == testCmd {
+				// Tests have their own retries, which mark a pass on retry flaky
+				if cfg.Test == nil {
+					cfg.Test = &config.TestConfig{}
+				}
+				cfg.Test.Retries = retriesFlag
+			} else {
+				if cfg.Parallelism == nil {
+					cfg.Parallelism = &config.ParallelismConfig{}
+				}
+				cfg.Parallelism.Retries = retriesFlag
+			}
+		}
 
 		return nil
 	},
@@ -92,12 +155,24 @@ func init() {
 	rootCmd.SetVersionTemplate(versionTemplate())
 
 	// Add persistent flags
-	rootCmd.PersistentFlags().StringVarP(&configFlag, "config", "c", "", "Path to config file (default: searches for .motf.yml)")
-	rootCmd.PersistentFlags().StringVar(&pathFlag, "path", "", "Explicit path (mutually exclusive with module name)")
-	rootCmd.PersistentFlags().StringArrayVarP(&argsFlag, "args", "a", []string{}, "Extra arguments to pass to terraform/tofu (can be specified multiple times)")
+	rootCmd.PersistentFlags().StringVarP(&configFlag, "config", "c", "", i18n.T(i18n.MsgFlagConfig))
+	rootCmd.PersistentFlags().StringVar(&pathFlag, "path", "", i18n.T(i18n.MsgFlagPath))
+	rootCmd.PersistentFlags().StringArrayVarP(&argsFlag, "args", "a", []string{}, i18n.T(i18n.MsgFlagArgs))
+	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false, i18n.T(i18n.MsgFlagDryRun))
+	rootCmd.PersistentFlags().StringVar(&indexFlag, "index", "", i18n.T(i18n.MsgFlagIndex))
+	rootCmd.PersistentFlags().BoolVar(&allowNonModuleFlag, "allow-non-module", false, i18n.T(i18n.MsgFlagAllowNonModule))
 }
 
-// Execute runs the root command
+// Execute runs the root command. Use ExitCode to get the exit code of its error.
 func Execute() error {
-	return rootCmd.Execute()
+	registerFlagCompletions(rootCmd)
+	stop := handleInterrupts()
+	defer stop()
+	start := now()
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil && rootCmd.SilenceErrors && !cmd.SilenceErrors {
+		logging.Errorf("%s", err)
+	}
+	emitMetrics(cmd, err, now().Sub(start))
+	return err
 }
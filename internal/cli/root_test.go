@@ -213,3 +213,33 @@ func TestBasicExample(t *testing.T) {
 		t.Errorf("expected '%s', got '%s'", modulePath, result)
 	}
 }
+
+func TestDryRunFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("dry-run")
+	if flag == nil {
+		t.Fatal("expected --dry-run persistent flag to be registered")
+	}
+	if flag.DefValue != "false" {
+		t.Errorf("expected --dry-run to default to false, got %s", flag.DefValue)
+	}
+}
+
+func TestDryRunFlag_DoesNotExecute(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "test-module"))
+
+	// With an empty PATH, terraform can't be found, so the run fails unless it is a dry run
+	t.Setenv("PATH", "")
+
+	rootCmd.SetArgs([]string{"plan", "--dry-run", "-i", "--path", modulePath})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("expected dry run to succeed without executing tofu, got: %v", err)
+	}
+}
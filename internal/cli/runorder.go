@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/modgraph"
+)
+
+// orderedCommands are the commands whose modules run in dependency order
+var orderedCommands = map[string]bool{"plan": true, "apply": true}
+
+// resolveRunOrder sets the modules each module of a plan or apply run has to
+// run after (DependsOn): the modules of the run it calls or whose state it
+// reads with terraform_remote_state, see modgraph.Graph.RunDependencies. A
+// dependency cycle fails the run before any module is processed.
+func resolveRunOrder(basePath string, modules []ModuleInfo) error {
+	if !orderedCommands[commandName] || len(modules) < 2 {
+		return nil
+	}
+	all, err := collectModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(all))
+	for _, mod := range all {
+		paths = append(paths, filepath.ToSlash(mod.Path))
+	}
+	graph, err := modgraph.Build(basePath, paths)
+	if err != nil {
+		return fmt.Errorf("failed to build the module graph: %w", err)
+	}
+
+	selected := make([]string, len(modules))
+	for i, mod := range modules {
+		selected[i] = filepath.ToSlash(mod.Path)
+	}
+	deps, err := graph.RunDependencies(selected)
+	if err != nil {
+		return err
+	}
+	for i, path := range selected {
+		modules[i].DependsOn = nil
+		for _, dep := range deps[path] {
+			modules[i].DependsOn = append(modules[i].DependsOn, filepath.FromSlash(dep))
+		}
+	}
+	return nil
+}
+
+// dependencyOutcomes skips modules of a run whose dependencies (DependsOn)
+// failed or were skipped, since they would plan or apply against state that
+// isn't there.
+type dependencyOutcomes struct {
+	mu     sync.Mutex
+	failed map[string]string // What happened to each module that didn't succeed, by path
+}
+
+// newDependencyOutcomes creates an empty record of module outcomes.
+func newDependencyOutcomes() *dependencyOutcomes {
+	return &dependencyOutcomes{failed: make(map[string]string)}
+}
+
+// wrap skips a module whose dependencies didn't succeed, and otherwise runs
+// fn and records its outcome. Modules start after their dependencies
+// finished, so their outcomes are known.
+func (d *dependencyOutcomes) wrap(fn ModuleRunner) ModuleRunner {
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		var err error
+		if reason := d.skipReason(mod); reason != "" {
+			_, _ = fmt.Fprintf(stderr, "Skipped: %s\n", reason)
+			err = &skippedError{reason: reason}
+		} else {
+			err = fn(mod, stdout, stderr)
+		}
+
+		var skipped *skippedError
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		switch {
+		case errors.As(err, &skipped):
+			d.failed[mod.Path] = "was skipped"
+		case err != nil && !isNonFatal(err):
+			d.failed[mod.Path] = "failed"
+		}
+		return err
+	}
+}
+
+// skipReason returns why mod is skipped, or "" if its dependencies succeeded.
+func (d *dependencyOutcomes) skipReason(mod ModuleInfo) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, dep := range mod.DependsOn {
+		if outcome, ok := d.failed[dep]; ok {
+			return fmt.Sprintf("dependency %s %s", filepath.ToSlash(dep), outcome)
+		}
+	}
+	return ""
+}
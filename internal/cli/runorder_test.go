@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestResolveRunOrder(t *testing.T) {
+	withConfig(t, &config.Config{})
+	origCommand := commandName
+	t.Cleanup(func() { commandName = origCommand })
+	commandName = "plan"
+
+	tmpDir := t.TempDir()
+	network := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "network"))
+	app := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "app"))
+	remoteState := `data "terraform_remote_state" "network" {
+  backend = "local"
+  config = {
+    path = "../network/terraform.tfstate"
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(app, "data.tf"), []byte(remoteState), 0644); err != nil {
+		t.Fatalf("failed to write data.tf: %v", err)
+	}
+
+	modules := []ModuleInfo{
+		{Name: "app", Type: TypeProject, Path: filepath.Join(DirProjects, "app")},
+		{Name: "network", Type: TypeProject, Path: filepath.Join(DirProjects, "network")},
+	}
+	if err := resolveRunOrder(tmpDir, modules); err != nil {
+		t.Fatalf("resolveRunOrder() error = %v", err)
+	}
+	if want := []string{filepath.Join(DirProjects, "network")}; !reflect.DeepEqual(modules[0].DependsOn, want) {
+		t.Errorf("app DependsOn = %v, want %v", modules[0].DependsOn, want)
+	}
+	if len(modules[1].DependsOn) != 0 {
+		t.Errorf("network DependsOn = %v, want none", modules[1].DependsOn)
+	}
+
+	// A cycle fails the run
+	if err := os.WriteFile(filepath.Join(network, "data.tf"), []byte(strings.ReplaceAll(remoteState, "../network", "../app")), 0644); err != nil {
+		t.Fatalf("failed to write data.tf: %v", err)
+	}
+	err := resolveRunOrder(tmpDir, modules)
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle between modules: projects/app -> projects/network -> projects/app") {
+		t.Errorf("expected a dependency cycle error, got %v", err)
+	}
+
+	// Other commands don't order modules
+	commandName = "val"
+	modules[0].DependsOn = nil
+	if err := resolveRunOrder(tmpDir, modules); err != nil || modules[0].DependsOn != nil {
+		t.Errorf("expected val modules not to be ordered, got %v (err %v)", modules[0].DependsOn, err)
+	}
+}
+
+// dependencyChain returns modules network, dns (depends on network), and app
+// (depends on dns), listed with the dependents first.
+func dependencyChain() []ModuleInfo {
+	return []ModuleInfo{
+		{Name: "app", Path: "projects/app", DependsOn: []string{"projects/dns"}},
+		{Name: "dns", Path: "projects/dns", DependsOn: []string{"projects/network"}},
+		{Name: "network", Path: "projects/network"},
+		{Name: "monitoring", Path: "projects/monitoring"},
+	}
+}
+
+func TestRunOnModules_DependencyOrder(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		var mu sync.Mutex
+		var finished []string
+		_, err := runOnModulesWithResults(dependencyChain(), parallel, 4, io.Discard, io.Discard, func(mod ModuleInfo, _, _ io.Writer) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, dep := range mod.DependsOn {
+				if !strings.Contains(strings.Join(finished, ","), dep) {
+					t.Errorf("parallel=%v: %s started before %s finished", parallel, mod.Path, dep)
+				}
+			}
+			finished = append(finished, mod.Path)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("parallel=%v: unexpected error: %v", parallel, err)
+		}
+		if len(finished) != 4 {
+			t.Errorf("parallel=%v: ran %v, want all modules", parallel, finished)
+		}
+	}
+}
+
+func TestDependencyOutcomes_SkipsDependents(t *testing.T) {
+	deps := newDependencyOutcomes()
+	results, err := runOnModulesWithResults(dependencyChain(), true, 2, io.Discard, io.Discard, deps.wrap(func(mod ModuleInfo, _, _ io.Writer) error {
+		if mod.Name == "network" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}))
+	if err == nil || !strings.Contains(err.Error(), "network") {
+		t.Fatalf("expected network to fail the run, got %v", err)
+	}
+
+	want := map[string]string{
+		"app":        "skipped: dependency projects/dns was skipped",
+		"dns":        "skipped: dependency projects/network failed",
+		"network":    "exit status 1",
+		"monitoring": "",
+	}
+	for _, r := range results {
+		got := ""
+		if r.err != nil {
+			got = r.err.Error()
+		}
+		if got != want[r.module.Name] {
+			t.Errorf("%s: err = %q, want %q", r.module.Name, got, want[r.module.Name])
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/security"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/spf13/cobra"
+)
+
+var (
+	secSeverityFlag string // Minimum severity of findings to report
+	secScannerFlag  string // Scanner override (trivy, tfsec, checkov)
+	secJSONFlag     bool   // Output findings as JSON
+)
+
+// secCmd represents the sec command
+var secCmd = &cobra.Command{
+	Use:   "sec [module-name]",
+	Short: "Run a security scanner (trivy, tfsec, checkov) on a component, base, or project",
+	Long: `Run a security scanner on a component, base, or project and report its findings.
+
+The scanner is set with security.scanner in .motf.yml (default: trivy) or --scanner.
+Findings from all scanned modules are collected into one report, sorted by severity.
+Use --severity to only report findings at or above a level; findings without a
+severity (e.g. checkov without a platform API key) are always reported.
+
+The command fails when any findings are reported.`,
+	Example: `  motf sec storage-account                 # Scan a module with the configured scanner
+  motf sec storage-account --severity high  # Only report high and critical findings
+  motf sec --changed -p                     # Scan all changed modules in parallel
+  motf sec --changed --scanner checkov      # Use checkov instead of the configured scanner
+  motf sec storage-account --json           # Output findings as JSON`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runSec,
+}
+
+func init() {
+	secCmd.Flags().StringVar(&secSeverityFlag, "severity", "low", "Minimum severity to report (low, medium, high, critical)")
+	secCmd.Flags().StringVar(&secScannerFlag, "scanner", "", "Scanner to use (trivy, tfsec, checkov; default: security.scanner from config)")
+	secCmd.Flags().BoolVar(&secJSONFlag, "json", false, "Output findings as JSON")
+	secCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	secCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
+	secCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	secCmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	secCmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
+	secCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(secCmd)
+	addParallelFlags(secCmd)
+	rootCmd.AddCommand(secCmd)
+}
+
+// findingCollector gathers findings from concurrently scanned modules
+type findingCollector struct {
+	mu       sync.Mutex
+	findings []security.Finding
+}
+
+func (c *findingCollector) add(module string, findings []security.Finding) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, f := range findings {
+		f.Module = module
+		c.findings = append(c.findings, f)
+	}
+}
+
+func runSec(cmd *cobra.Command, args []string) error {
+	minSeverity, err := security.ParseSeverity(secSeverityFlag)
+	if err != nil {
+		return err
+	}
+
+	scannerName := secScannerFlag
+	if scannerName == "" {
+		scannerName = cfg.Security.GetScanner()
+	}
+	scanner, err := security.Lookup(scannerName)
+	if err != nil {
+		return err
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	collector := &findingCollector{}
+	scan := func(modulePath string, stdout, stderr io.Writer) error {
+		secRunner, err := securityRunnerFor(scanner, modulePath)
+		if err != nil {
+			return err
+		}
+		// Keep stdout clean for --json by writing progress to stderr
+		progress := stdout
+		if secJSONFlag {
+			progress = stderr
+		}
+		findings, err := secRunner.Scan(modulePath, progress, stderr)
+		if err != nil {
+			return err
+		}
+		collector.add(displayPath(basePath, modulePath), findings)
+		return nil
+	}
+
+	var scanErr error
+	if selectingModules() {
+		if len(args) > 0 {
+			return cobra.MaximumNArgs(0)(cmd, args)
+		}
+		scanErr = runOnSelectedModulesWithPath(scan)
+	} else {
+		targetPath, err := resolveTargetWithExample(args, exampleFlag)
+		if err != nil {
+			return err
+		}
+		scanErr = scan(targetPath, os.Stdout, os.Stderr)
+	}
+
+	if dryRunFlag {
+		return scanErr
+	}
+
+	findings := security.Filter(collector.findings, minSeverity)
+	security.Sort(findings)
+
+	if secJSONFlag {
+		if err := printFindingsJSON(findings); err != nil {
+			return err
+		}
+	} else {
+		printFindings(findings, minSeverity)
+	}
+
+	if len(findings) > 0 {
+		return errors.Join(scanErr, findingsFailed("%d security finding(s) at or above %s severity", len(findings), minSeverity))
+	}
+	return scanErr
+}
+
+// securityRunnerFor returns a scanner runner using the effective config for a module directory.
+func securityRunnerFor(scanner *security.Scanner, modulePath string) (*security.Runner, error) {
+	modCfg, err := moduleConfig(modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var extraArgs []string
+	if modCfg.Security != nil {
+		extraArgs = strings.Fields(modCfg.Security.Args)
+	}
+	extraArgs = append(extraArgs, argsFlag...)
+
+	return &security.Runner{
+		Scanner:   scanner,
+		ExtraArgs: extraArgs,
+		Env:       tasks.EnvPairs(modCfg.Env),
+		DryRun:    dryRunFlag,
+		Context:   moduleContext(modulePath),
+	}, nil
+}
+
+// displayPath returns path relative to basePath, or path itself if it is outside basePath.
+func displayPath(basePath, path string) string {
+	rel, err := filepath.Rel(basePath, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// printFindings outputs findings as a table, followed by a count per severity
+func printFindings(findings []security.Finding, minSeverity security.Severity) {
+	fmt.Println()
+	if len(findings) == 0 {
+		fmt.Printf("No security findings at or above %s severity\n", minSeverity)
+		return
+	}
+
+	if plainFlag {
+		for i, f := range findings {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("Severity: %s\n", f.Severity)
+			fmt.Printf("Rule: %s\n", f.RuleID)
+			fmt.Printf("Module: %s\n", f.Module)
+			fmt.Printf("Location: %s\n", valueOrDefault(f.Location(), "unknown"))
+			fmt.Printf("Resource: %s\n", valueOrDefault(f.Resource, "unknown"))
+			fmt.Printf("Title: %s\n", f.Title)
+		}
+	} else {
+		severityWidth := len("SEVERITY")
+		ruleWidth := len("RULE")
+		moduleWidth := len("MODULE")
+		locationWidth := len("LOCATION")
+		for _, f := range findings {
+			severityWidth = max(severityWidth, len(f.Severity.String()))
+			ruleWidth = max(ruleWidth, len(f.RuleID))
+			moduleWidth = max(moduleWidth, len(f.Module))
+			locationWidth = max(locationWidth, len(f.Location()))
+		}
+
+		fmt.Printf("%-*s  %-*s  %-*s  %-*s  %s\n", severityWidth, "SEVERITY", ruleWidth, "RULE", moduleWidth, "MODULE", locationWidth, "LOCATION", "TITLE")
+		for _, f := range findings {
+			fmt.Printf("%-*s  %-*s  %-*s  %-*s  %s\n", severityWidth, strings.ToUpper(f.Severity.String()), ruleWidth, f.RuleID, moduleWidth, f.Module, locationWidth, f.Location(), f.Title)
+		}
+	}
+
+	counts := make(map[security.Severity]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	var parts []string
+	for s := security.SeverityCritical; s >= security.SeverityUnknown; s-- {
+		if counts[s] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", s, counts[s]))
+		}
+	}
+	fmt.Printf("\n%d finding(s) (%s)\n", len(findings), strings.Join(parts, ", "))
+}
+
+// printFindingsJSON outputs findings in JSON format
+func printFindingsJSON(findings []security.Finding) error {
+	if findings == nil {
+		findings = []security.Finding{}
+	}
+	output, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(output))
+	return nil
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/security"
+)
+
+// resetSecFlags resets the sec command flags after the test.
+func resetSecFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		secSeverityFlag = "low"
+		secScannerFlag = ""
+		secJSONFlag = false
+	})
+}
+
+func TestSecCmd_Flags(t *testing.T) {
+	for _, name := range []string{"severity", "scanner", "json", "example", "changed", "ref", "parallel", "max-parallel", "log-dir"} {
+		if secCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected sec command to have --%s flag", name)
+		}
+	}
+}
+
+func TestSecCmd_InvalidSeverity(t *testing.T) {
+	resetFlags(t)
+	resetSecFlags(t)
+	withConfig(t, config.DefaultConfig())
+
+	secSeverityFlag = "severe"
+	if err := runSec(secCmd, nil); err == nil {
+		t.Error("expected error for invalid severity")
+	}
+}
+
+func TestSecCmd_InvalidScanner(t *testing.T) {
+	resetFlags(t)
+	resetSecFlags(t)
+	withConfig(t, config.DefaultConfig())
+
+	secScannerFlag = "snyk"
+	if err := runSec(secCmd, nil); err == nil {
+		t.Error("expected error for unknown scanner")
+	}
+}
+
+func TestSecCmd_DryRunDoesNotExecute(t *testing.T) {
+	resetFlags(t)
+	resetSecFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "test-module"))
+
+	// With an empty PATH, the scanner can't be found, so the run fails unless it is a dry run
+	t.Setenv("PATH", "")
+
+	rootCmd.SetArgs([]string{"sec", "--dry-run", "--scanner", "checkov", "--path", modulePath})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("expected dry run to succeed without executing checkov, got: %v", err)
+	}
+}
+
+func TestSecCmd_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as trivy")
+	}
+	resetFlags(t)
+	resetSecFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "alpha"))
+
+	// A stand-in trivy that never finishes
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "trivy"), []byte("#!/bin/sh\nsleep 10\n"), 0755); err != nil {
+		t.Fatalf("failed to write trivy: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rootCmd.SetArgs([]string{"sec", "--all", "--timeout", "200ms"})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	start := time.Now()
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "module timed out after 200ms") {
+		t.Fatalf("expected the scan to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the scan to be stopped, took %s", elapsed)
+	}
+}
+
+func TestSecCmd_FindingsExitModuleFailed(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as trivy")
+	}
+	resetFlags(t)
+	resetSecFlags(t)
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "alpha"))
+
+	// A stand-in trivy that reports one high severity finding
+	report := `{"Results":[{"Target":"main.tf","Misconfigurations":[{"ID":"AVD-AZU-0001","Title":"Storage is public","Severity":"HIGH","Status":"FAIL"}]}]}`
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "trivy"), []byte("#!/bin/sh\necho '"+report+"'\n"), 0755); err != nil {
+		t.Fatalf("failed to write trivy: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rootCmd.SetArgs([]string{"sec", "--path", modulePath})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 security finding(s)") {
+		t.Fatalf("expected a findings error, got %v", err)
+	}
+	if ExitCode(err) != ExitModuleFailed {
+		t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitModuleFailed)
+	}
+}
+
+func TestSecurityRunnerFor_Args(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{
+		Root:     tmpDir,
+		Binary:   "terraform",
+		Security: &config.SecurityConfig{Scanner: "tfsec", Args: "--minimum-severity MEDIUM"},
+		Env:      map[string]string{"AWS_PROFILE": "scan"},
+	})
+	argsFlag = []string{"--exclude", "aws-s3-enable-versioning"}
+
+	scanner, err := security.Lookup(cfg.Security.GetScanner())
+	if err != nil {
+		t.Fatalf("Lookup returned error: %v", err)
+	}
+	secRunner, err := securityRunnerFor(scanner, tmpDir)
+	if err != nil {
+		t.Fatalf("securityRunnerFor returned error: %v", err)
+	}
+
+	wantArgs := []string{"--minimum-severity", "MEDIUM", "--exclude", "aws-s3-enable-versioning"}
+	if !reflect.DeepEqual(secRunner.ExtraArgs, wantArgs) {
+		t.Errorf("ExtraArgs = %v, want %v", secRunner.ExtraArgs, wantArgs)
+	}
+	if !reflect.DeepEqual(secRunner.Env, []string{"AWS_PROFILE=scan"}) {
+		t.Errorf("Env = %v", secRunner.Env)
+	}
+}
+
+func TestFindingCollector_SetsModule(t *testing.T) {
+	collector := &findingCollector{}
+	collector.add("components/s3", []security.Finding{{RuleID: "a"}, {RuleID: "b"}})
+
+	if len(collector.findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(collector.findings))
+	}
+	for _, f := range collector.findings {
+		if f.Module != "components/s3" {
+			t.Errorf("expected module to be set, got %+v", f)
+		}
+	}
+}
+
+func TestDisplayPath(t *testing.T) {
+	base := filepath.Join(string(filepath.Separator), "repo")
+	if got := displayPath(base, filepath.Join(base, "components", "s3")); got != "components/s3" {
+		t.Errorf("displayPath() = %q", got)
+	}
+	outside := filepath.Join(string(filepath.Separator), "other", "s3")
+	if got := displayPath(base, outside); got != outside {
+		t.Errorf("displayPath() = %q, want %q", got, outside)
+	}
+}
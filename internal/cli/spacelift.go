@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/spacelift"
+	"github.com/spf13/cobra"
+)
+
+var spaceliftCommitFlag string // Commit to run, instead of the head of the tracked branch
+
+// spaceliftCmd represents the spacelift command
+var spaceliftCmd = &cobra.Command{
+	Use:   "spacelift",
+	Short: "Work with the Spacelift stacks of modules",
+	Long: `Work with the Spacelift stacks that deploy modules.
+
+A stack belongs to a module when its project root is the module directory,
+relative to the repository root. The Spacelift API is called with the same
+environment variables as spacectl: SPACELIFT_API_KEY_ENDPOINT, and either
+SPACELIFT_API_KEY_ID and SPACELIFT_API_KEY_SECRET or SPACELIFT_API_TOKEN.`,
+}
+
+// spaceliftTriggerCmd represents the spacelift trigger command
+var spaceliftTriggerCmd = &cobra.Command{
+	Use:   "trigger",
+	Short: "Trigger runs of the Spacelift stacks of selected modules",
+	Long: `Trigger a tracked run of each Spacelift stack whose project root is a selected
+module. Select modules with --changed, --all, --select, or --type.
+
+Runs use the head of each stack's tracked branch, or --commit. Selected modules
+without a stack are reported as warnings. With --dry-run, the stacks are looked
+up but no runs are triggered.`,
+	Example: `  motf spacelift trigger --changed                     # Trigger stacks of changed modules
+  motf spacelift trigger --changed --ref origin/release  # Compare with another ref
+  motf spacelift trigger --changed --commit $GIT_SHA     # Run a specific commit
+  motf spacelift trigger --select '*storage*' --dry-run  # Show the stacks that would run`,
+	Args: cobra.NoArgs,
+	RunE: runSpaceliftTrigger,
+}
+
+func init() {
+	spaceliftTriggerCmd.Flags().StringVar(&spaceliftCommitFlag, "commit", "", "Commit SHA to run (default: head of the stack's tracked branch)")
+	spaceliftTriggerCmd.Flags().BoolVar(&allFlag, "all", false, "Trigger stacks of all modules")
+	spaceliftTriggerCmd.Flags().BoolVar(&changedFlag, "changed", false, "Trigger stacks of modules changed compared to --ref")
+	spaceliftTriggerCmd.Flags().StringVar(&selectFlag, "select", "", "Trigger stacks of modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	spaceliftTriggerCmd.Flags().StringVar(&typeFlag, "type", "", "Trigger stacks of modules of a type (component, base, project)")
+	spaceliftTriggerCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(spaceliftTriggerCmd)
+	spaceliftCmd.AddCommand(spaceliftTriggerCmd)
+	rootCmd.AddCommand(spaceliftCmd)
+}
+
+func runSpaceliftTrigger(cmd *cobra.Command, args []string) error {
+	if !selectingModules() {
+		return fmt.Errorf("spacelift trigger requires --changed, --all, --select, or --type")
+	}
+	if allFlag && changedFlag {
+		return fmt.Errorf("--all cannot be used with --changed")
+	}
+	if pathFlag != "" {
+		return fmt.Errorf("spacelift trigger cannot be used with --path, use --select to select modules")
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := selectModules(basePath)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if len(modules) == 0 {
+		_, _ = fmt.Fprintln(out, noModulesMessage(selectFlag))
+		return nil
+	}
+
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return fmt.Errorf("failed to get git root: %w", err)
+	}
+	client, err := spacelift.ClientFromEnv(os.Getenv)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	stacks, err := client.Stacks(ctx)
+	if err != nil {
+		return err
+	}
+
+	triggered := 0
+	for _, mod := range modules {
+		relPath, err := repoRelativePath(repoRoot, filepath.Join(basePath, mod.Path))
+		if err != nil {
+			return fmt.Errorf("module %s: %w", mod.Path, err)
+		}
+		modStacks := spacelift.StacksForModule(stacks, relPath)
+		if len(modStacks) == 0 {
+			logging.Warnf("no Spacelift stack for %s", mod.Path)
+			continue
+		}
+
+		for _, stack := range modStacks {
+			if dryRunFlag {
+				_, _ = fmt.Fprintf(out, "[dry-run] Would trigger stack %s for %s\n", stack.ID, mod.Path)
+				continue
+			}
+			runID, err := client.TriggerRun(ctx, stack.ID, spaceliftCommitFlag)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(out, "Triggered run %s of stack %s for %s\n", runID, stack.ID, mod.Path)
+			triggered++
+		}
+	}
+	if !dryRunFlag {
+		_, _ = fmt.Fprintf(out, "Triggered %d run(s)\n", triggered)
+	}
+	return nil
+}
+
+// repoRelativePath returns path relative to repoRoot, slash-separated.
+func repoRelativePath(repoRoot, path string) (string, error) {
+	absRoot, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil {
+		return "", fmt.Errorf("not inside the repository: %w", err)
+	}
+	return filepath.ToSlash(rel), nil
+}
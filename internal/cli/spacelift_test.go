@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/spacelift"
+)
+
+func resetSpaceliftFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		spaceliftCommitFlag = ""
+		spaceliftTriggerCmd.SetOut(nil)
+		spaceliftTriggerCmd.SetErr(nil)
+	})
+}
+
+// setupSpaceliftRepo creates a repository with two modules and a fake
+// Spacelift API with a stack for one of them. It returns the stacks that
+// runs were triggered for, with their commit.
+func setupSpaceliftRepo(t *testing.T) *[]string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if output, err := exec.Command("git", "init", tmpDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\nOutput: %s", err, output)
+	}
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "storage"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "network"))
+
+	var triggered []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(req.Query, "runTrigger") {
+			triggered = append(triggered, req.Variables["stack"]+"@"+req.Variables["sha"])
+			_, _ = w.Write([]byte(`{"data":{"runTrigger":{"id":"run-1"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"stacks":[{"id":"storage-prod","projectRoot":"projects/storage"},{"id":"other","projectRoot":"elsewhere"}]}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(spacelift.EnvEndpoint, server.URL)
+	t.Setenv(spacelift.EnvToken, "jwt")
+	return &triggered
+}
+
+func TestSpaceliftTriggerCmd_Flags(t *testing.T) {
+	for _, name := range []string{"commit", "all", "changed", "select", "type", "ref", "since", "from", "to", "merge-base"} {
+		if spaceliftTriggerCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected spacelift trigger command to have --%s flag", name)
+		}
+	}
+}
+
+func TestSpaceliftTrigger_RequiresSelection(t *testing.T) {
+	resetFlags(t)
+	resetSpaceliftFlags(t)
+
+	err := runSpaceliftTrigger(spaceliftTriggerCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "requires --changed") {
+		t.Fatalf("expected selection error, got %v", err)
+	}
+}
+
+func TestSpaceliftTrigger_TriggersStacksOfModules(t *testing.T) {
+	resetFlags(t)
+	resetSpaceliftFlags(t)
+	triggered := setupSpaceliftRepo(t)
+
+	var out bytes.Buffer
+	spaceliftTriggerCmd.SetOut(&out)
+	errOut := captureLog(t)
+	allFlag = true
+	spaceliftCommitFlag = "abc123"
+
+	if err := runSpaceliftTrigger(spaceliftTriggerCmd, nil); err != nil {
+		t.Fatalf("runSpaceliftTrigger() error = %v", err)
+	}
+	if strings.Join(*triggered, ",") != "storage-prod@abc123" {
+		t.Errorf("triggered = %v, want [storage-prod@abc123]", *triggered)
+	}
+	if !strings.Contains(out.String(), "Triggered run run-1 of stack storage-prod") {
+		t.Errorf("output = %q, want the triggered run", out.String())
+	}
+	if !strings.Contains(errOut.String(), "no Spacelift stack for "+filepath.Join(DirProjects, "network")) {
+		t.Errorf("stderr = %q, want a warning for the module without a stack", errOut.String())
+	}
+}
+
+func TestSpaceliftTrigger_DryRun(t *testing.T) {
+	resetFlags(t)
+	resetSpaceliftFlags(t)
+	triggered := setupSpaceliftRepo(t)
+
+	var out bytes.Buffer
+	spaceliftTriggerCmd.SetOut(&out)
+	spaceliftTriggerCmd.SetErr(&bytes.Buffer{})
+	selectFlag = "storage"
+	dryRunFlag = true
+
+	if err := runSpaceliftTrigger(spaceliftTriggerCmd, nil); err != nil {
+		t.Fatalf("runSpaceliftTrigger() error = %v", err)
+	}
+	if len(*triggered) != 0 {
+		t.Errorf("expected no runs with --dry-run, got %v", *triggered)
+	}
+	if !strings.Contains(out.String(), "[dry-run] Would trigger stack storage-prod") {
+		t.Errorf("output = %q, want the dry-run message", out.String())
+	}
+}
+
+func TestSpaceliftTrigger_MissingCredentials(t *testing.T) {
+	resetFlags(t)
+	resetSpaceliftFlags(t)
+	setupSpaceliftRepo(t)
+	t.Setenv(spacelift.EnvEndpoint, "")
+	allFlag = true
+
+	err := runSpaceliftTrigger(spaceliftTriggerCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), spacelift.EnvEndpoint) {
+		t.Fatalf("expected missing endpoint error, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+)
+
+// sparseChangedFiles returns the changed files, relative to repoRoot, that are
+// in its sparse checkout, and the number of files outside it. Those files
+// can't be run on, and would otherwise be attributed to a checked out parent
+// module, since their own module directory is missing.
+func sparseChangedFiles(repoRoot string, files []string) ([]string, int, error) {
+	sparse, err := git.SparseCheckout(repoRoot)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read sparse checkout: %w", err)
+	}
+	files, skipped := sparse.Filter(files)
+	return files, skipped, nil
+}
+
+// sparseModules returns the modules that are in the sparse checkout of the
+// repository containing basePath, and the number of modules outside it. Outside
+// a repository, all modules are returned.
+func sparseModules(basePath string, modules []ModuleInfo) ([]ModuleInfo, int, error) {
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return modules, 0, nil
+	}
+	sparse, err := git.SparseCheckout(repoRoot)
+	if err != nil || sparse == nil {
+		return modules, 0, err
+	}
+
+	count := len(modules)
+	modules = slices.DeleteFunc(modules, func(mod ModuleInfo) bool {
+		relPath, err := repoRelativePath(repoRoot, filepath.Join(basePath, mod.Path))
+		return err == nil && !strings.HasPrefix(relPath, "../") && !sparse.Includes(relPath)
+	})
+	return modules, count - len(modules), nil
+}
+
+// reportSparse notes on stderr that count items outside the sparse checkout
+// are skipped.
+func reportSparse(count int, what string) {
+	if count == 0 {
+		return
+	}
+	logging.Infof("%d %s outside the sparse checkout are skipped", count, what)
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// setupSparseRepo creates components x, x/y, and x/z, changes x/z in a second
+// commit tagged change, and checks out only components/x/y, which also checks
+// out components/x/main.tf in cone mode. It returns the repository root.
+func setupSparseRepo(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "x"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "x", "y"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "x", "z"))
+	git("add", "-A")
+	git("commit", "-m", "modules")
+	git("tag", "base")
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "x", "z", "examples", "basic"))
+	git("add", "-A")
+	git("commit", "-m", "change")
+	git("sparse-checkout", "set", "--cone", "components/x/y")
+
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	return tmpDir
+}
+
+func TestDetectChangedModules_SparseCheckout(t *testing.T) {
+	resetFlags(t)
+	setupSparseRepo(t)
+
+	// The change in components/x/z would otherwise be attributed to
+	// components/x, the closest checked out module
+	modules, err := detectChangedModules(changeRange{Ref: "base", MergeBase: true})
+	if err != nil {
+		t.Fatalf("detectChangedModules() error = %v", err)
+	}
+	if len(modules) != 0 {
+		t.Errorf("detectChangedModules() = %v, want no modules", modules)
+	}
+}
+
+func TestSparseModules(t *testing.T) {
+	tmpDir := setupSparseRepo(t)
+	modules := []ModuleInfo{
+		{Name: "x", Type: TypeComponent, Path: filepath.Join(DirComponents, "x")},
+		{Name: "y", Type: TypeComponent, Path: filepath.Join(DirComponents, "x", "y")},
+		{Name: "z", Type: TypeComponent, Path: filepath.Join(DirComponents, "x", "z")},
+	}
+
+	got, skipped, err := sparseModules(tmpDir, modules)
+	if err != nil {
+		t.Fatalf("sparseModules() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "x" || got[1].Name != "y" || skipped != 1 {
+		t.Errorf("sparseModules() = %v, %d skipped; want x and y, 1 skipped", got, skipped)
+	}
+}
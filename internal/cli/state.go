@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/spf13/cobra"
+)
+
+// stateEnvFlag selects the workspace of the state commands through TF_WORKSPACE
+var stateEnvFlag string
+
+// stateCmd groups the state inspection commands
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Inspect and change the terraform/tofu state of a module",
+	Long: `Run terraform/tofu state subcommands in a module without changing into its
+directory. The module is found by name like in other commands, or given with
+--path, in which case the module name is left out of the arguments.
+
+--env selects the workspace by setting TF_WORKSPACE. Arguments passed with
+--args/-a come before the addresses, e.g. -a -dry-run for state rm.`,
+}
+
+var stateListCmd = &cobra.Command{
+	Use:   "list <module-name> [address...]",
+	Short: "List the resources in the state of a module",
+	Example: `  motf state list my-project                            # List all resources
+  motf state list my-project module.storage             # List the resources of a module call
+  motf state list my-project --env prod                 # List the resources in the prod workspace`,
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runStateCommand("list", func(addresses []string) error { return nil }),
+}
+
+var stateShowCmd = &cobra.Command{
+	Use:   "show <module-name> [address]",
+	Short: "Show a resource in the state of a module",
+	Example: `  motf state show my-project azurerm_storage_account.this
+  motf state show my-project -a 'azurerm_storage_account.this'
+  motf state show --path ./projects/platform 'module.vnet.azurerm_subnet.this["app"]'`,
+	ValidArgsFunction: completeModuleNames,
+	RunE: runStateCommand("show", func(addresses []string) error {
+		if len(addresses) > 1 {
+			return fmt.Errorf("state show takes a single address, got %d", len(addresses))
+		}
+		return nil
+	}),
+}
+
+var stateMvCmd = &cobra.Command{
+	Use:   "mv <module-name> <source> <destination>",
+	Short: "Move a resource in the state of a module",
+	Example: `  motf state mv my-project azurerm_storage_account.main azurerm_storage_account.this
+  motf state mv my-project module.old module.new --env prod
+  motf state mv my-project module.old module.new --dry-run   # Print the command only`,
+	ValidArgsFunction: completeModuleNames,
+	RunE: runStateCommand("mv", func(addresses []string) error {
+		if len(addresses) != 2 {
+			return fmt.Errorf("state mv takes a source and a destination address, got %d address(es)", len(addresses))
+		}
+		return nil
+	}),
+}
+
+var stateRmCmd = &cobra.Command{
+	Use:   "rm <module-name> <address...>",
+	Short: "Remove resources from the state of a module",
+	Example: `  motf state rm my-project azurerm_role_assignment.legacy
+  motf state rm my-project module.old -a -dry-run      # Let terraform list what would be removed`,
+	ValidArgsFunction: completeModuleNames,
+	RunE: runStateCommand("rm", func(addresses []string) error {
+		if len(addresses) == 0 {
+			return fmt.Errorf("state rm takes at least one address")
+		}
+		return nil
+	}),
+}
+
+func init() {
+	stateCmd.PersistentFlags().StringVar(&stateEnvFlag, "env", "", "Workspace to use, set as TF_WORKSPACE")
+	stateCmd.AddCommand(stateListCmd, stateShowCmd, stateMvCmd, stateRmCmd)
+	rootCmd.AddCommand(stateCmd)
+}
+
+// runStateCommand returns the RunE of the state subcommand sub, which checks
+// the addresses after the module with validate.
+func runStateCommand(sub string, validate func(addresses []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		targetPath, addresses, err := stateTarget(args)
+		if err != nil {
+			return err
+		}
+		if err := validate(addresses); err != nil {
+			return err
+		}
+
+		modCfg, err := stateConfig(targetPath)
+		if err != nil {
+			return err
+		}
+		tfRunner := terraform.NewRunner(modCfg)
+		tfRunner.DryRun = dryRunFlag
+		tfRunner.Context = runContext
+		if stateEnvFlag != "" {
+			fmt.Printf("Workspace: %s\n", stateEnvFlag)
+		}
+		stateArgs := append(append([]string{sub}, argsFlag...), addresses...)
+		return tfRunner.RunState(targetPath, stateArgs...)
+	}
+}
+
+// stateTarget resolves the module of a state command, the first argument or
+// --path, and returns it with the remaining arguments.
+func stateTarget(args []string) (string, []string, error) {
+	if pathFlag != "" || len(args) == 0 {
+		targetPath, err := resolveTargetPath(nil)
+		return targetPath, args, err
+	}
+	targetPath, err := resolveTargetPath(args[:1])
+	return targetPath, args[1:], err
+}
+
+// stateConfig returns the effective config of the module at modulePath, with
+// TF_WORKSPACE set to --env.
+func stateConfig(modulePath string) (*config.Config, error) {
+	return moduleConfigWithEnv(modulePath, workspaceEnv(stateEnvFlag))
+}
+
+// workspaceEnv returns the environment variables that select workspace, or
+// none for an empty workspace.
+func workspaceEnv(workspace string) map[string]string {
+	if workspace == "" {
+		return nil
+	}
+	return map[string]string{"TF_WORKSPACE": workspace}
+}
+
+// moduleConfigWithEnv returns the effective config of the module at
+// modulePath, with env added to its environment variables.
+func moduleConfigWithEnv(modulePath string, env map[string]string) (*config.Config, error) {
+	modCfg, err := moduleConfig(modulePath)
+	if err != nil || len(env) == 0 {
+		return modCfg, err
+	}
+	// ForModule may return the root config, which must not change
+	envCfg := *modCfg
+	envCfg.Env = make(map[string]string, len(modCfg.Env)+len(env))
+	maps.Copy(envCfg.Env, modCfg.Env)
+	maps.Copy(envCfg.Env, env)
+	return &envCfg, nil
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func resetStateFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		stateEnvFlag = ""
+	})
+}
+
+func TestStateCmd_Subcommands(t *testing.T) {
+	for _, name := range []string{"list", "show", "mv", "rm"} {
+		if cmd, _, err := stateCmd.Find([]string{name}); err != nil || cmd.Name() != name {
+			t.Errorf("expected state command to have %s subcommand", name)
+		}
+	}
+	if stateMvCmd.InheritedFlags().Lookup("env") == nil {
+		t.Error("expected state subcommands to have --env flag")
+	}
+}
+
+func TestStateTarget(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir})
+	projectPath := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+
+	targetPath, addresses, err := stateTarget([]string{"platform", "module.old", "module.new"})
+	if err != nil {
+		t.Fatalf("stateTarget() error = %v", err)
+	}
+	if targetPath != projectPath || !reflect.DeepEqual(addresses, []string{"module.old", "module.new"}) {
+		t.Errorf("stateTarget() = %s, %v", targetPath, addresses)
+	}
+
+	// With --path, all arguments are addresses
+	pathFlag = projectPath
+	targetPath, addresses, err = stateTarget([]string{"module.old"})
+	if err != nil {
+		t.Fatalf("stateTarget() with --path error = %v", err)
+	}
+	if targetPath != projectPath || !reflect.DeepEqual(addresses, []string{"module.old"}) {
+		t.Errorf("stateTarget() with --path = %s, %v", targetPath, addresses)
+	}
+
+	pathFlag = ""
+	if _, _, err := stateTarget(nil); err == nil {
+		t.Error("expected an error without a module or --path")
+	}
+}
+
+func TestStateConfig_Workspace(t *testing.T) {
+	resetFlags(t)
+	resetStateFlags(t)
+	tmpDir := t.TempDir()
+	rootCfg := &config.Config{Root: tmpDir, Binary: "terraform", Env: map[string]string{"ARM_USE_OIDC": "true"}}
+	withConfig(t, rootCfg)
+	projectPath := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+
+	stateEnvFlag = "prod"
+	modCfg, err := stateConfig(projectPath)
+	if err != nil {
+		t.Fatalf("stateConfig() error = %v", err)
+	}
+	want := map[string]string{"ARM_USE_OIDC": "true", "TF_WORKSPACE": "prod"}
+	if !reflect.DeepEqual(modCfg.Env, want) {
+		t.Errorf("stateConfig() env = %v, want %v", modCfg.Env, want)
+	}
+	if _, ok := rootCfg.Env["TF_WORKSPACE"]; ok {
+		t.Error("stateConfig() must not change the root config")
+	}
+}
+
+func TestStateCommands_ValidateAddresses(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+	dryRunFlag = true
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr string
+	}{
+		{"list without addresses", func() error { return stateListCmd.RunE(stateListCmd, []string{"platform"}) }, ""},
+		{"show one address", func() error { return stateShowCmd.RunE(stateShowCmd, []string{"platform", "a.b"}) }, ""},
+		{"show two addresses", func() error { return stateShowCmd.RunE(stateShowCmd, []string{"platform", "a.b", "c.d"}) }, "single address"},
+		{"mv", func() error { return stateMvCmd.RunE(stateMvCmd, []string{"platform", "a.b", "c.d"}) }, ""},
+		{"mv without destination", func() error { return stateMvCmd.RunE(stateMvCmd, []string{"platform", "a.b"}) }, "source and a destination"},
+		{"rm without addresses", func() error { return stateRmCmd.RunE(stateRmCmd, []string{"platform"}) }, "at least one address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
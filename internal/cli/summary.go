@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+)
+
+// summaryJSONFlag is the file the run summary of multi-module runs is written to as JSON
+var summaryJSONFlag string
+
+// runSummary is the outcome of a multi-module run, printed as a footer and
+// written by --summary-json.
+type runSummary struct {
+	Command       string          `json:"command"`
+	Total         int             `json:"total"`
+	Succeeded     int             `json:"succeeded"` // Including flaky modules
+	Failed        int             `json:"failed"`
+	Skipped       int             `json:"skipped"`
+	Quarantined   int             `json:"quarantined"`
+	Flaky         int             `json:"flaky"`
+	DurationMs    int64           `json:"duration_ms"`
+	FailedModules []string        `json:"failed_modules"` // Paths of the failed modules
+	Modules       []summaryModule `json:"modules"`
+
+	duration time.Duration
+}
+
+// summaryModule is the outcome of a module in a runSummary.
+type summaryModule struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// newRunSummary summarizes the results of a run that took duration.
+func newRunSummary(command string, results []moduleResult, duration time.Duration) runSummary {
+	summary := buildChatopsSummary(command, results, nil)
+	succeeded, failed := summary.Counts()
+	s := runSummary{
+		Command:       command,
+		Total:         len(results),
+		Succeeded:     succeeded,
+		Failed:        failed,
+		Skipped:       summary.Skipped(),
+		Quarantined:   summary.Quarantined(),
+		Flaky:         summary.Flaky(),
+		DurationMs:    duration.Milliseconds(),
+		FailedModules: []string{},
+		Modules:       make([]summaryModule, 0, len(summary.Results)),
+		duration:      duration,
+	}
+	for _, r := range summary.Results {
+		if r.Status == chatops.StatusFailed {
+			s.FailedModules = append(s.FailedModules, filepath.ToSlash(r.Path))
+		}
+		s.Modules = append(s.Modules, summaryModule{
+			Name:       r.Name,
+			Path:       filepath.ToSlash(r.Path),
+			Status:     r.Status,
+			DurationMs: r.Duration.Milliseconds(),
+			Error:      r.Error,
+		})
+	}
+	return s
+}
+
+// print writes the summary as a footer: a table, or one labeled value per
+// line with --plain. Quarantined modules are only shown when there are any.
+func (s runSummary) print(w io.Writer) {
+	type field struct{ label, value string }
+	fields := []field{
+		{"Modules", fmt.Sprint(s.Total)},
+		{"Succeeded", fmt.Sprint(s.Succeeded)},
+		{"Failed", fmt.Sprint(s.Failed)},
+		{"Skipped", fmt.Sprint(s.Skipped)},
+	}
+	if s.Quarantined > 0 {
+		fields = append(fields, field{"Quarantined", fmt.Sprint(s.Quarantined)})
+	}
+	fields = append(fields, field{"Time", formatElapsed(s.duration)})
+
+	_, _ = fmt.Fprintln(w)
+	if plainFlag {
+		for _, f := range fields {
+			_, _ = fmt.Fprintf(w, "%s: %s\n", f.label, f.value)
+		}
+	} else {
+		var header, row []string
+		for _, f := range fields {
+			width := max(len(f.label), len(f.value))
+			header = append(header, fmt.Sprintf("%-*s", width, strings.ToUpper(f.label)))
+			row = append(row, fmt.Sprintf("%-*s", width, f.value))
+		}
+		_, _ = fmt.Fprintln(w, strings.TrimRight(strings.Join(header, "  "), " "))
+		_, _ = fmt.Fprintln(w, strings.TrimRight(strings.Join(row, "  "), " "))
+	}
+	if len(s.FailedModules) > 0 {
+		_, _ = fmt.Fprintf(w, "Failed modules: %s\n", strings.Join(s.FailedModules, ", "))
+	}
+}
+
+// reportRunSummary prints the summary footer of a run on stderr, unless
+// motf's messages are JSON or hidden by --quiet, and writes it to
+// --summary-json when set.
+func reportRunSummary(results []moduleResult, duration time.Duration) error {
+	if len(results) == 0 {
+		return nil
+	}
+	summary := newRunSummary(commandName, results, duration)
+	if log := logging.Default(); log.Format() == logging.FormatText && log.Enabled(logging.LevelInfo) {
+		summary.print(os.Stderr)
+	}
+	if summaryJSONFlag == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run summary: %w", err)
+	}
+	if err := os.WriteFile(filepath.Clean(summaryJSONFlag), append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write --summary-json file: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&summaryJSONFlag, "summary-json", "", "Write the summary of multi-module runs to this file as JSON")
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+)
+
+// summaryResults returns results of a run with a succeeded, a failed, and a
+// skipped module.
+func summaryResults() []moduleResult {
+	return []moduleResult{
+		{module: ModuleInfo{Name: "storage", Path: filepath.Join("components", "storage")}, duration: 2 * time.Second},
+		{module: ModuleInfo{Name: "dns", Path: filepath.Join("components", "dns")}, err: errors.New("exit status 1"), duration: time.Second},
+		{module: ModuleInfo{Name: "prod", Path: filepath.Join("projects", "prod")}, err: &skippedError{reason: "over budget"}},
+	}
+}
+
+func TestRunSummary_Print(t *testing.T) {
+	resetFlags(t)
+	summary := newRunSummary("plan", summaryResults(), 83*time.Second)
+
+	var buf bytes.Buffer
+	summary.print(&buf)
+	want := `
+MODULES  SUCCEEDED  FAILED  SKIPPED  TIME
+3        1          1       1        1m23s
+Failed modules: components/dns
+`
+	if buf.String() != want {
+		t.Errorf("footer =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	plainFlag = true
+	buf.Reset()
+	summary.print(&buf)
+	want = `
+Modules: 3
+Succeeded: 1
+Failed: 1
+Skipped: 1
+Time: 1m23s
+Failed modules: components/dns
+`
+	if buf.String() != want {
+		t.Errorf("plain footer =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestReportRunSummary_JSON(t *testing.T) {
+	resetFlags(t)
+	commandName = "plan"
+	t.Cleanup(func() { commandName = "" })
+	summaryJSONFlag = filepath.Join(t.TempDir(), "summary.json")
+	logging.Configure(&bytes.Buffer{}, logging.LevelWarn, logging.FormatText) // No footer on stderr
+
+	if err := reportRunSummary(summaryResults(), 1500*time.Millisecond); err != nil {
+		t.Fatalf("reportRunSummary() error = %v", err)
+	}
+	data, err := os.ReadFile(summaryJSONFlag)
+	if err != nil {
+		t.Fatalf("summary not written: %v", err)
+	}
+	var got runSummary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid summary JSON: %v", err)
+	}
+	if got.Command != "plan" || got.Total != 3 || got.Succeeded != 1 || got.Failed != 1 || got.Skipped != 1 || got.DurationMs != 1500 {
+		t.Errorf("summary = %+v", got)
+	}
+	if len(got.FailedModules) != 1 || got.FailedModules[0] != "components/dns" {
+		t.Errorf("failed_modules = %v, want [components/dns]", got.FailedModules)
+	}
+	if len(got.Modules) != 3 || got.Modules[1].Status != "failed" || got.Modules[1].Error != "exit status 1" || got.Modules[0].DurationMs != 2000 {
+		t.Errorf("modules = %+v", got.Modules)
+	}
+
+	summaryJSONFlag = filepath.Join(t.TempDir(), "missing", "summary.json")
+	if err := reportRunSummary(summaryResults(), time.Second); err == nil {
+		t.Error("expected an error for a directory that doesn't exist")
+	}
+}
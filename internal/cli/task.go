@@ -3,10 +3,14 @@ package cli
 import (
 	"fmt"
 	"io"
+	"path/filepath"
 	"sort"
+	"strings"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 	"github.com/TechnicallyJoe/terraform-motf/internal/git"
 	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +25,8 @@ var taskCmd = &cobra.Command{
 	Long: `Run a custom task defined in .motf.yml on a module.
 
 Tasks are shell commands configured in your .motf.yml file under the 'tasks' section.
+Tasks that set 'template: true' can use the template variables {{ .ModuleName }},
+{{ .ModulePath }}, {{ .ModuleType }}, and {{ .Example }}.
 By default, or with --list, shows all available tasks.
 
 Examples:
@@ -32,7 +38,8 @@ Examples:
   motf task --path ./modules/x -t docs         # Run task on explicit path
   motf task -t lint --changed                  # Run 'lint' task on changed modules
   motf task -t lint --changed --parallel       # Run 'lint' task on changed modules in parallel`,
-	Args: cobra.MaximumNArgs(1),
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// If no task specified, list tasks
 		if taskFlag == "" || listTaskFlag {
@@ -42,15 +49,15 @@ Examples:
 		// Get git root (soft fail - empty string if not in git repo)
 		gitRoot, _ := git.GetRepoRoot()
 
-		if changedFlag {
-			if exampleFlag != "" {
-				return fmt.Errorf("--changed cannot be used with --example")
-			}
+		if selectingModules() {
 			if len(args) > 0 {
 				return cobra.MaximumNArgs(0)(cmd, args)
 			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
-				taskRunner := tasks.NewRunner(cfg.Tasks, buildTaskEnv(gitRoot, moduleAbsPath))
+			return runOnSelectedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+				taskRunner, err := taskRunnerFor(gitRoot, moduleAbsPath)
+				if err != nil {
+					return err
+				}
 				return taskRunner.RunWithOutput(taskFlag, moduleAbsPath, stdout, stderr)
 			})
 		}
@@ -62,7 +69,10 @@ Examples:
 		}
 
 		// Run the task
-		taskRunner := tasks.NewRunner(cfg.Tasks, buildTaskEnv(gitRoot, targetPath))
+		taskRunner, err := taskRunnerFor(gitRoot, targetPath)
+		if err != nil {
+			return err
+		}
 		return taskRunner.Run(taskFlag, targetPath)
 	},
 }
@@ -84,8 +94,14 @@ func listTasks() error {
 
 	for _, name := range names {
 		task := cfg.Tasks[name]
-		if task.Description != "" {
-			fmt.Printf("  %-20s %s\n", name, task.Description)
+		description := task.Description
+		if len(task.DependsOn) > 0 {
+			description = strings.TrimSpace(fmt.Sprintf("%s (depends on: %s)", description, strings.Join(task.DependsOn, ", ")))
+		}
+		if description != "" && plainFlag {
+			fmt.Printf("  %s: %s\n", name, description)
+		} else if description != "" {
+			fmt.Printf("  %-20s %s\n", name, description)
 		} else {
 			fmt.Printf("  %s\n", name)
 		}
@@ -93,14 +109,88 @@ func listTasks() error {
 	return nil
 }
 
+// taskRunnerFor returns a task runner using the effective config for a module directory.
+func taskRunnerFor(gitRoot, modulePath string) (*tasks.Runner, error) {
+	modCfg, err := moduleConfig(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	taskRunner := tasks.NewRunner(modCfg.Tasks, buildTaskEnv(modCfg, gitRoot, modulePath))
+	taskRunner.DryRun = dryRunFlag
+	taskRunner.Template = taskTemplateData(modulePath)
+	taskRunner.Hooks = modCfg.Hooks
+	taskRunner.Context = moduleContext(modulePath)
+
+	tfRunner := terraform.NewRunner(modCfg)
+	tfRunner.DryRun = dryRunFlag
+	tfRunner.Hooks = hookRunner(modCfg)
+	tfRunner.Context = taskRunner.Context
+	taskRunner.Steps = terraformSteps(tfRunner)
+	return taskRunner, nil
+}
+
+// hookRunner returns a HookRunner that runs the hooks of modCfg with the
+// environment and template data of tasks.
+func hookRunner(modCfg *config.Config) terraform.HookRunner {
+	return func(name, dir string, vars map[string]string, stdout, stderr io.Writer) error {
+		command := modCfg.Hook(name)
+		if command == "" {
+			return nil
+		}
+		gitRoot, _ := git.GetRepoRoot()
+		runner := tasks.NewRunner(nil, buildTaskEnv(modCfg, gitRoot, dir))
+		runner.DryRun = dryRunFlag
+		runner.Template = taskTemplateData(dir)
+		runner.Context = moduleContext(dir)
+		return runner.RunHook(name, command, dir, vars, stdout, stderr)
+	}
+}
+
+// terraformSteps returns a StepRunner that runs built-in task steps with tfRunner.
+func terraformSteps(tfRunner *terraform.Runner) tasks.StepRunner {
+	return func(step string, args []string, dir string, stdout, stderr io.Writer) error {
+		switch step {
+		case "init":
+			return tfRunner.RunInitWithOutput(dir, stdout, stderr, args...)
+		case "fmt":
+			return tfRunner.RunFmtWithOutput(dir, stdout, stderr, args...)
+		case "validate":
+			return tfRunner.RunValidateWithOutput(dir, stdout, stderr, args...)
+		case "plan":
+			return tfRunner.RunPlanWithOutput(dir, stdout, stderr, args...)
+		case "test":
+			return tfRunner.RunTestWithOutput(dir, stdout, stderr, args...)
+		default:
+			return fmt.Errorf("unknown step '%s'", step)
+		}
+	}
+}
+
+// taskTemplateData returns the template variables for a task run in targetPath.
+// For an example directory (<module>/examples/<name>), the module fields
+// describe the parent module and Example is set.
+func taskTemplateData(targetPath string) tasks.TemplateData {
+	modulePath, example := targetPath, ""
+	if filepath.Base(filepath.Dir(targetPath)) == DirExamples {
+		modulePath, example = filepath.Dir(filepath.Dir(targetPath)), filepath.Base(targetPath)
+	}
+	return tasks.TemplateData{
+		ModuleName: tasks.ModuleNameFromPath(modulePath),
+		ModulePath: modulePath,
+		ModuleType: getModuleType(modulePath),
+		Example:    example,
+	}
+}
+
 // buildTaskEnv creates the environment variables for task execution.
-func buildTaskEnv(gitRoot, modulePath string) []string {
+func buildTaskEnv(c *config.Config, gitRoot, modulePath string) []string {
 	return tasks.NewEnvBuilder().
+		WithEnv(c.Env).
 		WithGitRoot(gitRoot).
 		WithModulePath(modulePath).
 		WithModuleName(tasks.ModuleNameFromPath(modulePath)).
-		WithConfigPath(cfg.ConfigPath).
-		WithBinary(cfg.Binary).
+		WithConfigPath(c.ConfigPath).
+		WithBinary(terraform.NewRunner(c).Binary()).
 		Build()
 }
 
@@ -108,9 +198,13 @@ func init() {
 	taskCmd.Flags().StringVarP(&taskFlag, "task", "t", "", "Task name to run")
 	taskCmd.Flags().BoolVarP(&listTaskFlag, "list", "l", false, "List available tasks")
 	taskCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	taskCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
 	taskCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	taskCmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	taskCmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
 	taskCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
-	taskCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
-	taskCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	addChangeRangeFlags(taskCmd)
+	addParallelFlags(taskCmd)
+	addRetriesFlag(taskCmd)
 	rootCmd.AddCommand(taskCmd)
 }
@@ -1,7 +1,14 @@
 package cli
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
 )
 
 func TestTaskCmd_Flags(t *testing.T) {
@@ -29,3 +36,131 @@ func TestTaskCmd_Flags(t *testing.T) {
 		t.Errorf("example flag shorthand = %q, want %q", exampleFlagDef.Shorthand, "e")
 	}
 }
+
+func TestTaskRunnerFor_ModuleConfigOverrides(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{
+		Root:   tmpDir,
+		Binary: "terraform",
+		Test:   &config.TestConfig{Engine: "terratest"},
+		Tasks: map[string]*tasks.TaskConfig{
+			"hello": {Command: "echo root"},
+		},
+	})
+
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "test-module"))
+	moduleConfig := `env:
+  GREETING: module
+tasks:
+  hello:
+    command: echo "$GREETING from $MOTF_BINARY"
+binary: tofu
+`
+	if err := os.WriteFile(filepath.Join(modulePath, config.ModuleConfigFile), []byte(moduleConfig), 0644); err != nil {
+		t.Fatalf("failed to write module config: %v", err)
+	}
+
+	taskRunner, err := taskRunnerFor("", modulePath)
+	if err != nil {
+		t.Fatalf("taskRunnerFor returned error: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := taskRunner.RunWithOutput("hello", modulePath, &stdout, &stderr); err != nil {
+		t.Fatalf("task failed: %v (stderr: %s)", err, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "module from tofu") {
+		t.Errorf("expected module task, env, and binary overrides to apply, got %q", stdout.String())
+	}
+}
+
+func TestTaskRunnerFor_BuiltinSteps(t *testing.T) {
+	resetFlags(t)
+	dryRunFlag = true
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{
+		Root:   tmpDir,
+		Binary: "tofu",
+		Test:   &config.TestConfig{Engine: "tofu"},
+		Tasks: map[string]*tasks.TaskConfig{
+			"ci": {Steps: []tasks.Step{
+				{Terraform: "init", Args: []string{"-backend=false"}},
+				{Terraform: "validate"},
+				{Run: "tflint"},
+			}},
+		},
+	})
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "test-module"))
+
+	taskRunner, err := taskRunnerFor("", modulePath)
+	if err != nil {
+		t.Fatalf("taskRunnerFor returned error: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := taskRunner.RunWithOutput("ci", modulePath, &stdout, &stderr); err != nil {
+		t.Fatalf("task failed: %v", err)
+	}
+	for _, want := range []string{
+		"[dry-run] Would run tofu init -backend=false in " + modulePath,
+		"[dry-run] Would run tofu validate in " + modulePath,
+		"$ sh -c tflint",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestTaskTemplateData(t *testing.T) {
+	modulePath := filepath.Join("/repo", DirComponents, "azurerm", "storage-account")
+
+	tests := []struct {
+		name       string
+		targetPath string
+		want       tasks.TemplateData
+	}{
+		{
+			name:       "module",
+			targetPath: modulePath,
+			want:       tasks.TemplateData{ModuleName: "storage-account", ModulePath: modulePath, ModuleType: TypeComponent},
+		},
+		{
+			name:       "example",
+			targetPath: filepath.Join(modulePath, DirExamples, "basic"),
+			want:       tasks.TemplateData{ModuleName: "storage-account", ModulePath: modulePath, ModuleType: TypeComponent, Example: "basic"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taskTemplateData(tt.targetPath); got != tt.want {
+				t.Errorf("taskTemplateData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHookRunner(t *testing.T) {
+	resetFlags(t)
+	dryRunFlag = true
+	tmpDir := t.TempDir()
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+	modCfg := &config.Config{Root: tmpDir, Binary: "terraform", Hooks: map[string]string{"pre_plan": "./login.sh {{ .ModuleName }}"}}
+	withConfig(t, modCfg)
+
+	tfRunner, err := runnerFor(modulePath)
+	if err != nil {
+		t.Fatalf("runnerFor() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := tfRunner.RunPlanWithOutput(modulePath, &out, &out); err != nil {
+		t.Fatalf("RunPlanWithOutput() error = %v", err)
+	}
+
+	want := "[dry-run] Would run pre_plan hook in " + modulePath + "\n$ sh -c \"./login.sh dns\"\n[dry-run] Would run terraform plan in " + modulePath + "\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -1,33 +1,139 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
 	"github.com/spf13/cobra"
 )
 
+// Environment variables identifying the example under test with --all-examples
+const (
+	EnvExample    = "MOTF_EXAMPLE"
+	EnvExampleDir = "MOTF_EXAMPLE_DIR"
+)
+
+var (
+	testAllExamplesFlag bool     // Run the tests once per example of the module
+	testEngineFlag      string   // Test engine, instead of test.engine
+	testReportFlags     []string // Reports to write, as format=path
+
+	// testDependentsFlag also tests the modules that depend on changed modules,
+	// through this many levels of dependents, or all levels if negative
+	testDependentsFlag int
+
+	// testMaxCostFlag skips module tests whose estimated costs (test.cost)
+	// exceed this budget, 0 for no budget
+	testMaxCostFlag float64
+)
+
 // testCmd represents the test command
 var testCmd = &cobra.Command{
 	Use:   "test [module-name]",
 	Short: "Run tests on a component, base, or project",
 	Long: `Run tests on a component, base, or project using the configured test engine.
 
-The test engine (e.g., terratest, terraform, tofu) is configured in .motf.yml under the 'test' section.
-By default, terratest is used, which runs 'go test ./...' in the module directory.
+The test engine (e.g., terratest, terraform, tofu) is configured in .motf.yml under the 'test' section,
+or set per run with --engine. By default, terratest is used, which runs 'go test ./...' in the module
+directory.
+
+The terraform and tofu engines run 'terraform test' in modules with *.tftest.hcl
+files in the module or its tests/ directory. Modules without them are skipped.
+
+The compliance engine plans the module, converts the plan to JSON, and runs
+terraform-compliance (or the tool in test.compliance.command) against it with
+the feature files in tests/features/. Initialize the module first, e.g. with
+'motf init'. In multi-module runs, modules without feature files are skipped.
+
+Use -e to test an example in the module's examples/ directory, or --all-examples
+to run the tests once per example. The terraform, tofu, and compliance engines
+run in the example directory, so examples can be validated without terratest.
+terratest runs in the module directory with MOTF_EXAMPLE set to the example name
+and MOTF_EXAMPLE_DIR to its absolute path, so tests don't need to enumerate the
+examples themselves. With --all-examples, results are reported per example, and
+--parallel runs the examples concurrently.
+
+With --changed, --dependents also tests the modules that call changed modules
+through local sources, since a component's own tests can pass while the
+modules using it break. --dependents tests direct dependents, --dependents=2
+their dependents too, and --dependents=-1 all of them.
+
+--report writes the results of the run as a JUnit XML or JSON report, for CI
+systems to ingest: the tests of each module are parsed from the go test (-v)
+or terraform/tofu test output, with a test case per module when none are
+found.
+
+--max-cost sets a budget for the cloud costs of a run: modules are admitted
+cheapest first by their estimated test.cost from .motf.module.yml, and tests
+that no longer fit the budget are skipped and reported at the end. Modules
+without a cost always run.
 
 Examples:
   motf test storage-account                    # Run tests on storage-account module
   motf test storage-account -a -v              # Run tests with verbose output
-  motf test storage-account -a -timeout=30m    # Run tests with custom timeout`,
-	Args: cobra.MaximumNArgs(1),
+  motf test storage-account -a -timeout=30m    # Run tests with custom timeout
+  motf test --all --engine tofu -p             # Run native tests with tofu in modules that have them
+  motf test storage-account -e basic           # Run tests of the basic example
+  motf test storage-account --all-examples -p  # Run tests once per example, in parallel
+  motf test --changed --dependents -p          # Test changed modules and their dependents
+  motf test --changed --max-cost 50 -p         # Skip tests over a cost budget of 50
+  motf test --all -p --report junit=junit.xml  # Write a JUnit report of all tests`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
+		if testEngineFlag != "" && !config.IsValidTestEngine(testEngineFlag) {
+			return fmt.Errorf("invalid --engine '%s': must be one of: %s", testEngineFlag, strings.Join(config.ValidTestEngineNames(), ", "))
+		}
+		if _, err := parseReportFlags(testReportFlags); err != nil {
+			return err
+		}
+		if testAllExamplesFlag {
+			if selectingModules() {
+				return fmt.Errorf("--all-examples cannot be used with --all, --changed, --select, or --type")
+			}
+			if exampleFlag != "" {
+				return fmt.Errorf("--all-examples cannot be used with --example")
+			}
+			return runTestAllExamples(args)
+		}
+		if exampleFlag != "" && !selectingModules() {
+			return runTestExample(args)
+		}
+
+		if testDependentsFlag != 0 && !changedFlag {
+			return fmt.Errorf("--dependents requires --changed")
+		}
+		if testMaxCostFlag < 0 {
+			return fmt.Errorf("invalid --max-cost %g: must be 0 or more", testMaxCostFlag)
+		}
+		if testMaxCostFlag > 0 && !selectingModules() {
+			return fmt.Errorf("--max-cost requires --all, --changed, --select, or --type")
+		}
+		if selectingModules() {
 			if len(args) > 0 {
 				return cobra.MaximumNArgs(0)(cmd, args)
 			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
-				return runner.RunTestWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
-			})
+			basePath, err := getBasePath()
+			if err != nil {
+				return err
+			}
+			run, retry := withTestPolicies(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+				moduleAbsPath := filepath.Join(basePath, mod.Path)
+				tfRunner, err := testRunnerFor(moduleAbsPath)
+				if err != nil {
+					return err
+				}
+				return skipUntested(tfRunner.RunTestWithOutput(moduleAbsPath, stdout, stderr, argsFlag...), stderr)
+			}, func(mod ModuleInfo) (string, string) { return mod.Name, mod.Path })
+			return errors.Join(runOnSelectedModules(run), retry.saveHistory(testLogDir()))
 		}
 
 		targetPath, err := resolveTargetPath(args)
@@ -35,14 +141,225 @@ Examples:
 			return err
 		}
 
-		return runner.RunTest(targetPath, argsFlag...)
+		tfRunner, err := testRunnerFor(targetPath)
+		if err != nil {
+			return err
+		}
+
+		name, path, err := quarantineKey(targetPath)
+		if err != nil {
+			return err
+		}
+		run, retry := withTestPolicies(func(_ ModuleInfo, stdout, stderr io.Writer) error {
+			return skipUntested(tfRunner.RunTestWithOutput(targetPath, stdout, stderr, argsFlag...), stderr)
+		}, func(ModuleInfo) (string, string) { return name, path })
+		return runSingleTest(ModuleInfo{Name: name, Path: path}, run, retry)
 	},
 }
 
+// runSingleTest runs the tests of a single module or example, writing the
+// --report files and the flake history.
+func runSingleTest(mod ModuleInfo, run ModuleRunner, retry *retrier) error {
+	capture := newOutputCapture(len(testReportFlags) > 0)
+	start := time.Now()
+	err := capture.wrap(run)(mod, os.Stdout, os.Stderr)
+	reportErr := writeTestReports([]moduleResult{{module: mod, err: err, duration: time.Since(start)}}, capture)
+	if isNonFatal(err) {
+		err = nil
+	}
+	return errors.Join(err, retry.saveHistory(testLogDir()), reportErr)
+}
+
+// runTestExample runs the tests of the module's --example.
+func runTestExample(args []string) error {
+	examplePath, err := resolveTargetWithExample(args, exampleFlag)
+	if err != nil {
+		return err
+	}
+	modulePath := filepath.Dir(filepath.Dir(examplePath))
+
+	tfRunner, dir, err := exampleTest(modulePath, exampleFlag, examplePath)
+	if err != nil {
+		return err
+	}
+
+	// Examples are quarantined with their module
+	name, path, err := quarantineKey(modulePath)
+	if err != nil {
+		return err
+	}
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	run, retry := withTestPolicies(func(_ ModuleInfo, stdout, stderr io.Writer) error {
+		return skipUntested(tfRunner.RunTestWithOutput(dir, stdout, stderr, argsFlag...), stderr)
+	}, func(ModuleInfo) (string, string) { return name, path })
+	return runSingleTest(ModuleInfo{Name: exampleFlag, Type: getModuleType(modulePath), Path: displayPath(basePath, examplePath)}, run, retry)
+}
+
+// testRunnerFor returns a runner for the tests of the module at modulePath,
+// with the test engine of --engine if set.
+func testRunnerFor(modulePath string) (*terraform.Runner, error) {
+	modCfg, err := moduleConfig(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	r := terraform.NewRunner(withTestEngine(modCfg))
+	r.DryRun = dryRunFlag
+	r.Hooks = hookRunner(modCfg)
+	r.Context = moduleContext(modulePath)
+	return r, nil
+}
+
+// withTestEngine returns modCfg, or a copy of it with the test engine of
+// --engine.
+func withTestEngine(modCfg *config.Config) *config.Config {
+	if testEngineFlag == "" {
+		return modCfg
+	}
+	engineCfg := *modCfg
+	test := config.TestConfig{}
+	if modCfg.Test != nil {
+		test = *modCfg.Test
+	}
+	test.Engine = testEngineFlag
+	engineCfg.Test = &test
+	return &engineCfg
+}
+
+// skipUntested turns the error of a module without tests for the engine
+// (feature files, or *.tftest.hcl files) into a skip reported on stderr, so
+// it doesn't fail the run.
+func skipUntested(err error, stderr io.Writer) error {
+	if errors.Is(err, terraform.ErrNoFeatures) || errors.Is(err, terraform.ErrNoTests) {
+		_, _ = fmt.Fprintf(stderr, "Skipped: %s\n", err)
+		return &skippedError{reason: err.Error()}
+	}
+	return err
+}
+
+// withTestPolicies applies test.retries and test.quarantine to a module test
+// runner. moduleOf returns the name and path quarantine entries are matched
+// against. The returned retrier records flakes for saveHistory.
+func withTestPolicies(fn ModuleRunner, moduleOf func(ModuleInfo) (name, path string)) (ModuleRunner, *retrier) {
+	retries := 0
+	if cfg != nil {
+		retries = cfg.Test.GetRetries()
+	}
+	retry := newRetrier(retries)
+	return withQuarantine(retry.wrap(fn), moduleOf), retry
+}
+
+// testLogDir returns the log directory the flake history is kept in, or "" if disabled.
+func testLogDir() string {
+	if cfg == nil {
+		return ""
+	}
+	return cfg.Parallelism.GetLogDir()
+}
+
+// runTestAllExamples runs the tests of a module once per example, with the
+// example identified by environment variables.
+func runTestAllExamples(args []string) error {
+	modulePath, err := resolveTargetPath(args)
+	if err != nil {
+		return err
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	examples := listItems(filepath.Join(modulePath, DirExamples), basePath)
+	if len(examples) == 0 {
+		return fmt.Errorf("module %s has no examples in %s/", filepath.Base(modulePath), DirExamples)
+	}
+
+	modType := getModuleType(modulePath)
+	modules := make([]ModuleInfo, len(examples))
+	for i, ex := range examples {
+		modules[i] = ModuleInfo{Name: ex.Name, Type: modType, Path: ex.Path}
+	}
+	fmt.Printf("Testing %d example(s) of %s\n", len(modules), filepath.Base(modulePath))
+
+	var parallelismCfg *config.ParallelismConfig
+	if cfg != nil {
+		parallelismCfg = cfg.Parallelism
+	}
+
+	// Examples are quarantined with their module
+	name, path := filepath.Base(modulePath), displayPath(basePath, modulePath)
+
+	run, retry := withTestPolicies(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		tfRunner, dir, err := exampleTest(modulePath, mod.Name, filepath.Join(basePath, mod.Path))
+		if err != nil {
+			return err
+		}
+		return skipUntested(tfRunner.RunTestWithOutput(dir, stdout, stderr, argsFlag...), stderr)
+	}, func(ModuleInfo) (string, string) { return name, path })
+	return errors.Join(RunOnModulesParallel(modules, parallelismCfg, run), retry.saveHistory(testLogDir()))
+}
+
+// quarantineKey returns the name and path (relative to the base path) that
+// test.quarantine entries are matched against for a module directory.
+func quarantineKey(modulePath string) (name, path string, err error) {
+	basePath, err := getBasePath()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Base(modulePath), displayPath(basePath, modulePath), nil
+}
+
+// exampleTest returns a runner for the tests of an example of the module, in
+// the example's environment, and the directory to run them in: the module
+// directory for terratest, whose tests target the example through
+// MOTF_EXAMPLE_DIR, otherwise the example directory.
+func exampleTest(modulePath, exampleName, exampleDir string) (*terraform.Runner, string, error) {
+	modCfg, err := moduleConfig(modulePath)
+	if err != nil {
+		return nil, "", err
+	}
+	exCfg := exampleConfig(withTestEngine(modCfg), exampleName, exampleDir)
+	r := terraform.NewRunner(exCfg)
+	r.DryRun = dryRunFlag
+	r.Hooks = hookRunner(exCfg)
+	r.Context = moduleContext(exampleDir)
+
+	dir := exampleDir
+	if exCfg.Test.Engine == "terratest" {
+		dir = modulePath
+	}
+	return r, dir, nil
+}
+
+// exampleConfig returns a copy of modCfg with the example name and directory
+// added to the environment.
+func exampleConfig(modCfg *config.Config, exampleName, exampleDir string) *config.Config {
+	exCfg := *modCfg
+	exCfg.Env = make(map[string]string, len(modCfg.Env)+2)
+	maps.Copy(exCfg.Env, modCfg.Env)
+	exCfg.Env[EnvExample] = exampleName
+	exCfg.Env[EnvExampleDir] = exampleDir
+	return &exCfg
+}
+
 func init() {
+	testCmd.Flags().StringVar(&testEngineFlag, "engine", "", "Test engine, instead of test.engine (terratest, terraform, tofu, compliance)")
+	testCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run the tests of a specific example of the module")
+	testCmd.Flags().StringArrayVar(&testReportFlags, "report", nil, "Write a test report as format=path, with format junit or json (can be specified multiple times)")
+	testCmd.Flags().BoolVar(&testAllExamplesFlag, "all-examples", false, "Run the tests once per example, with MOTF_EXAMPLE set")
+	testCmd.Flags().IntVar(&testDependentsFlag, "dependents", 0, "With --changed, also test modules that depend on changed modules, through this many levels (-1 for all)")
+	testCmd.Flags().Lookup("dependents").NoOptDefVal = "1"
+	testCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Rerun failed module tests up to N times; a pass on retry is reported flaky (default: test.retries)")
+	testCmd.Flags().Float64Var(&testMaxCostFlag, "max-cost", 0, "Skip module tests once their estimated costs (test.cost) exceed this budget")
+	testCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
 	testCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	testCmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	testCmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
 	testCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
-	testCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
-	testCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	addChangeRangeFlags(testCmd)
+	addParallelFlags(testCmd)
 	rootCmd.AddCommand(testCmd)
 }
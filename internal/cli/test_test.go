@@ -1,13 +1,278 @@
 package cli
 
 import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
 )
 
-func TestTestCmd_NoExampleFlag(t *testing.T) {
-	// test command doesn't support --example flag (tests run on module directly)
+func TestTestCmd_HasExampleFlag(t *testing.T) {
 	flag := testCmd.Flags().Lookup("example")
-	if flag != nil {
-		t.Error("testCmd should not have --example flag")
+	if flag == nil {
+		t.Fatal("testCmd should have --example flag")
+	}
+	if flag.Shorthand != "e" {
+		t.Errorf("expected shorthand 'e', got '%s'", flag.Shorthand)
+	}
+}
+
+func TestTestCmd_ExampleRejectsAllExamples(t *testing.T) {
+	resetFlags(t)
+	t.Cleanup(func() { testAllExamplesFlag = false })
+	exampleFlag = "basic"
+	testAllExamplesFlag = true
+
+	err := testCmd.RunE(testCmd, []string{"storage-account"})
+	if err == nil || !strings.Contains(err.Error(), "--all-examples cannot be used with --example") {
+		t.Fatalf("expected --all-examples/--example error, got %v", err)
+	}
+}
+
+func TestExampleTest_Directory(t *testing.T) {
+	tests := []struct {
+		engine  string
+		wantDir string
+	}{
+		{"terratest", "module"},
+		{"terraform", "example"},
+		{"tofu", "example"},
+		{"compliance", "example"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.engine, func(t *testing.T) {
+			resetFlags(t)
+			tmpDir := t.TempDir()
+			withWorkingDir(t, tmpDir)
+			withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Test: &config.TestConfig{Engine: "terratest"}})
+			modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+			exampleDir := createTerraformModule(t, modulePath, filepath.Join(DirExamples, "basic"))
+			testEngineFlag = tt.engine
+
+			_, dir, err := exampleTest(modulePath, "basic", exampleDir)
+			if err != nil {
+				t.Fatalf("exampleTest() error = %v", err)
+			}
+			want := map[string]string{"module": modulePath, "example": exampleDir}[tt.wantDir]
+			if dir != want {
+				t.Errorf("exampleTest() dir = %s, want %s", dir, want)
+			}
+		})
+	}
+}
+
+func TestRunTestExample_NativeTests(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Test: &config.TestConfig{Engine: "terraform"}})
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	createTerraformModule(t, modulePath, filepath.Join(DirExamples, "basic"))
+	reportPath := filepath.Join(tmpDir, "report.json")
+	exampleFlag = "basic"
+	testReportFlags = []string{"json=" + reportPath}
+
+	// The example has no test files, so it is skipped rather than failed
+	if err := runTestExample([]string{"vnet"}); err != nil {
+		t.Fatalf("runTestExample() error = %v", err)
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("expected a report: %v", err)
+	}
+	if !strings.Contains(string(data), `"path": "components/vnet/examples/basic"`) || !strings.Contains(string(data), `"status": "skipped"`) {
+		t.Errorf("expected the example to be reported as skipped, got:\n%s", data)
+	}
+
+	exampleFlag = "complete"
+	if err := runTestExample([]string{"vnet"}); err == nil || !strings.Contains(err.Error(), "example 'complete' not found") {
+		t.Errorf("expected an example not found error, got %v", err)
+	}
+}
+
+func TestTestCmd_HasParallelFlags(t *testing.T) {
+	flag := testCmd.Flags().Lookup("parallel")
+	if flag == nil {
+		t.Fatal("testCmd should have --parallel flag")
+	}
+	if flag.Shorthand != "p" {
+		t.Errorf("expected shorthand 'p', got '%s'", flag.Shorthand)
+	}
+	if testCmd.Flags().Lookup("max-parallel") == nil {
+		t.Fatal("testCmd should have --max-parallel flag")
+	}
+}
+
+func TestTestCmd_ChangedRejectsModuleArg(t *testing.T) {
+	resetFlags(t)
+	changedFlag = true
+
+	err := testCmd.RunE(testCmd, []string{"storage-account"})
+	if err == nil {
+		t.Fatal("expected error when combining --changed with a module name")
+	}
+}
+
+func TestTestCmd_AllExamplesRejectsChanged(t *testing.T) {
+	resetFlags(t)
+	t.Cleanup(func() { testAllExamplesFlag = false })
+	changedFlag = true
+	testAllExamplesFlag = true
+
+	err := testCmd.RunE(testCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--all-examples") {
+		t.Fatalf("expected --all-examples/--changed error, got %v", err)
+	}
+}
+
+func TestRunTestAllExamples_NoExamples(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "test-module"))
+
+	pathFlag = modulePath
+	err := runTestAllExamples(nil)
+	if err == nil || !strings.Contains(err.Error(), "no examples") {
+		t.Fatalf("expected no examples error, got %v", err)
+	}
+}
+
+func TestRunTestAllExamples_ReportsPerExample(t *testing.T) {
+	resetFlags(t)
+	t.Cleanup(func() { testAllExamplesFlag = false })
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git: %v", err)
+	}
+	withWorkingDir(t, tmpDir)
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "test-module"))
+	createTerraformModule(t, modulePath, filepath.Join(DirExamples, "basic"))
+	createTerraformModule(t, modulePath, filepath.Join(DirExamples, "complete"))
+	// Directories without .tf files are not examples
+	if err := os.MkdirAll(filepath.Join(modulePath, DirExamples, "README"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	logDir := filepath.Join(tmpDir, "logs")
+
+	// With an empty PATH, go can't be found, so the run fails unless it is a dry run
+	t.Setenv("PATH", "")
+
+	rootCmd.SetArgs([]string{"test", "--dry-run", "--all-examples", "--log-dir", logDir, "--path", modulePath})
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("expected dry run to succeed, got: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(logDir, lastRunReportFile))
+	if err != nil {
+		t.Fatalf("expected run report: %v", err)
+	}
+	var summary chatops.Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("invalid run report: %v", err)
+	}
+	var names []string
+	for _, r := range summary.Results {
+		names = append(names, r.Name)
+	}
+	if strings.Join(names, ",") != "basic,complete" {
+		t.Errorf("report modules = %v, want [basic complete]", names)
+	}
+}
+
+func TestExampleConfig_SetsEnv(t *testing.T) {
+	modCfg := config.DefaultConfig()
+	modCfg.Env = map[string]string{"ARM_SUBSCRIPTION_ID": "123"}
+
+	exCfg := exampleConfig(modCfg, "basic", "/repo/components/vnet/examples/basic")
+
+	want := map[string]string{
+		"ARM_SUBSCRIPTION_ID": "123",
+		EnvExample:            "basic",
+		EnvExampleDir:         "/repo/components/vnet/examples/basic",
+	}
+	if !reflect.DeepEqual(exCfg.Env, want) {
+		t.Errorf("Env = %v, want %v", exCfg.Env, want)
+	}
+	if _, ok := modCfg.Env[EnvExample]; ok {
+		t.Error("exampleConfig should not modify the module config")
+	}
+}
+
+func TestTestCmd_InvalidEngine(t *testing.T) {
+	resetFlags(t)
+	testEngineFlag = "pytest"
+
+	err := testCmd.RunE(testCmd, []string{"storage-account"})
+	if err == nil || !strings.Contains(err.Error(), "invalid --engine 'pytest'") {
+		t.Fatalf("expected an invalid --engine error, got %v", err)
+	}
+}
+
+func TestWithTestEngine(t *testing.T) {
+	resetFlags(t)
+	modCfg := config.DefaultConfig()
+	modCfg.Test = &config.TestConfig{Engine: "terratest", Args: "-v"}
+
+	if got := withTestEngine(modCfg); got != modCfg {
+		t.Error("expected the module config without --engine")
+	}
+
+	testEngineFlag = "tofu"
+	got := withTestEngine(modCfg)
+	if got.Test.Engine != "tofu" || got.Test.Args != "-v" {
+		t.Errorf("Test = %+v, want engine tofu with the module's args", got.Test)
+	}
+	if modCfg.Test.Engine != "terratest" {
+		t.Error("withTestEngine should not modify the module config")
+	}
+}
+
+func TestTestCmd_SkipsModulesWithoutNativeTests(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Test: &config.TestConfig{Engine: "terratest"}})
+	tested := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "tested"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "untested"))
+	if err := os.WriteFile(filepath.Join(tested, "main.tftest.hcl"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	allFlag = true
+	dryRunFlag = true
+	testEngineFlag = "terraform"
+
+	modules, err := collectModules(tmpDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr strings.Builder
+	results, err := runOnModulesWithResults(modules, false, 1, &stdout, &stderr, func(mod ModuleInfo, out, errOut io.Writer) error {
+		tfRunner, err := testRunnerFor(filepath.Join(tmpDir, mod.Path))
+		if err != nil {
+			return err
+		}
+		return skipUntested(tfRunner.RunTestWithOutput(filepath.Join(tmpDir, mod.Path), out, errOut), errOut)
+	})
+	if err != nil {
+		t.Fatalf("expected modules without tests not to fail the run, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !strings.Contains(stdout.String(), "Would run terraform test in "+tested) {
+		t.Errorf("expected the tested module to run, got:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "untested") || !strings.Contains(stderr.String(), "Skipped: no test files") {
+		t.Errorf("expected the untested module to be skipped, got:\n%s", stderr.String())
 	}
 }
@@ -1,11 +1,13 @@
 package cli
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
 )
 
 // resetFlags resets all package-level flags to their default values.
@@ -19,12 +21,84 @@ func resetFlags(t *testing.T) {
 		searchFlag = ""
 		exampleFlag = ""
 		changedFlag = false
+		allFlag = false
+		selectFlag = ""
+		typeFlag = ""
 		parallelFlag = false
 		maxParallelFlag = 0
+		logDirFlag = ""
+		plainFlag = false
+		dryRunFlag = false
+		indexFlag = ""
+		allowNonModuleFlag = false
+		testDependentsFlag = 0
+		testMaxCostFlag = 0
+		policyPathsFlag = nil
+		policyPlanFlag = false
+		policyJSONFlag = false
+		checkJSONFlag = false
+		taskFlag = ""
+		watchCommandFlag = ""
+		watchDebounceFlag = 0
+		backendEnvFlags = nil
+		backendCheckFlag = false
+		testEngineFlag = ""
+		testReportFlags = nil
+		noColorFlag = false
+		noProgressFlag = false
+		timeoutFlag = 0
+		retriesFlag = 0
+		failFastFlag = false
 		refFlag = ""
+		chatopsFlag = ""
+		chatopsFileFlag = ""
+		injectFailureFlag = ""
+		planDetailedExitcodeFlag = false
+		planSaveFlag = false
+		planShowFlag = false
+		applyFromArtifactsFlag = false
+		sinceFlag = ""
+		fromFlag = ""
+		toFlag = ""
+		mergeBaseFlag = true
+		progressJSONFlag = ""
+		summaryJSONFlag = ""
+		moduleMetrics = nil
+		upgradeCheckFlag = false
+		cleanLocksFlag = false
+		cleanStateFlag = false
+		cleanYesFlag = false
+		lockPlatformFlags = nil
+		providersSyncCheckFlag = false
+		editNameFlag = ""
+		editTypeFlag = ""
+		editDescriptionFlag = ""
+		editDefaultFlag = ""
+		editSensitiveFlag = false
+		checkExamplesJSONFlag = false
+		checkDocsJSONFlag = false
+		checkDocsFixFlag = false
+		describeJsonFlag = false
+		describeDiffFlag = ""
+		describeFailOnBreakingFlag = false
+		verboseFlag = false
+		quietFlag = false
+		logFormatFlag = logging.FormatText
+		logging.Configure(os.Stderr, logging.LevelInfo, logging.FormatText)
+		rootCmd.SilenceErrors = false
 	})
 }
 
+// captureLog makes motf's messages of the test go to the returned buffer, as
+// text with notes and warnings.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	logging.Configure(&buf, logging.LevelInfo, logging.FormatText)
+	t.Cleanup(func() { logging.Configure(os.Stderr, logging.LevelInfo, logging.FormatText) })
+	return &buf
+}
+
 // withConfig sets the global cfg for the duration of the test.
 // It will be reset to nil after the test completes.
 func withConfig(t *testing.T, c *config.Config) {
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/testreport"
+)
+
+// reportTarget is a test report to write: a format and a file
+type reportTarget struct {
+	format string
+	path   string
+}
+
+// parseReportFlags parses --report values of the form format=path.
+func parseReportFlags(values []string) ([]reportTarget, error) {
+	var targets []reportTarget
+	for _, value := range values {
+		format, path, ok := strings.Cut(value, "=")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("invalid --report '%s': expected format=path, e.g. junit=report.xml", value)
+		}
+		if !slices.Contains(testreport.Formats, format) {
+			return nil, fmt.Errorf("invalid --report format '%s': must be %s", format, strings.Join(testreport.Formats, " or "))
+		}
+		targets = append(targets, reportTarget{format: format, path: path})
+	}
+	return targets, nil
+}
+
+// writeTestReports writes the --report files of a test run from the results
+// and captured output of its modules.
+func writeTestReports(results []moduleResult, capture *outputCapture) error {
+	targets, err := parseReportFlags(testReportFlags)
+	if err != nil || len(targets) == 0 {
+		return err
+	}
+
+	suites := make([]testreport.Suite, 0, len(results))
+	for _, r := range results {
+		status, errText := moduleStatus(r.err)
+		suites = append(suites, testreport.NewSuite(r.module.Name, filepath.ToSlash(r.module.Path), status, errText, r.duration, capture.output(r.module)))
+	}
+	report := testreport.New(suites)
+
+	for _, target := range targets {
+		if dryRunFlag {
+			fmt.Printf("[dry-run] Would write %s report to %s\n", target.format, target.path)
+			continue
+		}
+		data, err := report.Format(target.format)
+		if err != nil {
+			return err
+		}
+		if dir := filepath.Dir(target.path); dir != "." {
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				return fmt.Errorf("failed to create report directory: %w", err)
+			}
+		}
+		if err := os.WriteFile(target.path, data, 0600); err != nil {
+			return fmt.Errorf("failed to write %s report: %w", target.format, err)
+		}
+		fmt.Printf("Wrote %s report to %s (%d passed, %d failed, %d skipped)\n", target.format, target.path, report.Passed, report.Failed, report.Skipped)
+	}
+	return nil
+}
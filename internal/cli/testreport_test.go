@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/testreport"
+)
+
+func TestParseReportFlags(t *testing.T) {
+	targets, err := parseReportFlags([]string{"junit=out/junit.xml", "json=report.json"})
+	if err != nil {
+		t.Fatalf("parseReportFlags() error = %v", err)
+	}
+	want := []reportTarget{{format: "junit", path: "out/junit.xml"}, {format: "json", path: "report.json"}}
+	if len(targets) != len(want) || targets[0] != want[0] || targets[1] != want[1] {
+		t.Errorf("parseReportFlags() = %v, want %v", targets, want)
+	}
+
+	for value, wantErr := range map[string]string{
+		"junit.xml":      "expected format=path",
+		"junit=":         "expected format=path",
+		"html=index.htm": "invalid --report format 'html'",
+	} {
+		if _, err := parseReportFlags([]string{value}); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("parseReportFlags(%q) error = %v, want %q", value, err, wantErr)
+		}
+	}
+}
+
+func TestWriteTestReports(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	reportPath := filepath.Join(tmpDir, "reports", "report.json")
+	testReportFlags = []string{"json=" + reportPath}
+
+	capture := newOutputCapture(true)
+	modules := []ModuleInfo{{Name: "dns", Path: "components/dns"}, {Name: "vnet", Path: "components/vnet"}}
+	var out bytes.Buffer
+	results, _ := runOnModulesWithResults(modules, false, 1, &out, &out, capture.wrap(func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		if mod.Name == "dns" {
+			_, _ = stdout.Write([]byte("--- PASS: TestDNS (0.50s)\n--- FAIL: TestRecords (0.25s)\n"))
+			return errors.New("exit status 1")
+		}
+		return &skippedError{reason: "no test files"}
+	}))
+
+	if err := writeTestReports(results, capture); err != nil {
+		t.Fatalf("writeTestReports() error = %v", err)
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("expected the report to be written: %v", err)
+	}
+	var report testreport.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid report: %v", err)
+	}
+	if report.Tests != 3 || report.Passed != 1 || report.Failed != 1 || report.Skipped != 1 {
+		t.Errorf("totals = %d tests, %d passed, %d failed, %d skipped, want 3, 1, 1, 1", report.Tests, report.Passed, report.Failed, report.Skipped)
+	}
+	if len(report.Suites) != 2 || report.Suites[0].Path != "components/dns" || report.Suites[1].Status != "skipped" {
+		t.Errorf("unexpected suites: %+v", report.Suites)
+	}
+}
+
+func TestWriteTestReports_DryRun(t *testing.T) {
+	resetFlags(t)
+	reportPath := filepath.Join(t.TempDir(), "junit.xml")
+	testReportFlags = []string{"junit=" + reportPath}
+	dryRunFlag = true
+
+	if err := writeTestReports(nil, newOutputCapture(true)); err != nil {
+		t.Fatalf("writeTestReports() error = %v", err)
+	}
+	if _, err := os.Stat(reportPath); !os.IsNotExist(err) {
+		t.Error("expected no report to be written in dry-run mode")
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// outputJSONFlag prints the outputs as JSON
+var outputJSONFlag bool
+
+// outputCmd represents the output command
+var outputCmd = &cobra.Command{
+	Use:   "output [module-name] [name]",
+	Short: "Print the outputs of a component, base, or project",
+	Long: `Run terraform/tofu output in a module and print its outputs, or a single
+output given by name.
+
+A single output is printed on its own so it can be piped into other commands:
+strings without quotes, other values as JSON. Like terraform output with a
+name, this includes sensitive values. Use --json for the JSON of terraform
+output -json. Outputs are read from the state, so the module needs to be
+initialized.
+
+Use the --example/-e flag to read the outputs of a specific example instead of
+the module itself.
+
+Examples:
+  motf output network                            # Print all outputs
+  motf output network vnet_id                    # Print the vnet_id output
+  motf output network --json                     # Print all outputs as JSON
+  motf output storage-account -e basic           # Print the outputs of the 'basic' example
+  motf plan app -a -var="vnet_id=$(motf output network vnet_id)"`,
+	Args:              cobra.MaximumNArgs(2),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runOutput,
+}
+
+func init() {
+	outputCmd.Flags().BoolVar(&outputJSONFlag, "json", false, "Output in JSON format")
+	outputCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	rootCmd.AddCommand(outputCmd)
+}
+
+func runOutput(cmd *cobra.Command, args []string) error {
+	// With --path, the only argument is the output name
+	moduleArgs, names := args, []string(nil)
+	if pathFlag != "" {
+		moduleArgs, names = nil, args
+	} else if len(args) > 0 {
+		moduleArgs, names = args[:1], args[1:]
+	}
+	if len(names) > 1 {
+		return fmt.Errorf("output takes a single output name, got %d", len(names))
+	}
+
+	targetPath, err := resolveTargetWithExample(moduleArgs, exampleFlag)
+	if err != nil {
+		return err
+	}
+	tfRunner, err := runnerFor(targetPath)
+	if err != nil {
+		return err
+	}
+
+	outputArgs := argsFlag
+	if outputJSONFlag || len(names) == 1 {
+		outputArgs = append([]string{"-json"}, outputArgs...)
+	}
+	outputArgs = append(outputArgs, names...)
+	out, err := tfRunner.Outputs(targetPath, outputArgs...)
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 1 && !outputJSONFlag {
+		if out, err = formatOutputValue(out); err != nil {
+			return fmt.Errorf("failed to read output %s: %w", names[0], err)
+		}
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
+
+// formatOutputValue formats the JSON of a single output for piping: a string
+// without quotes, and other values as JSON, ending in a newline.
+func formatOutputValue(data []byte) ([]byte, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	if s, ok := value.(string); ok {
+		return []byte(s + "\n"), nil
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(data), "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestOutputCmd_Flags(t *testing.T) {
+	for _, name := range []string{"json", "example"} {
+		if outputCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected output command to have --%s flag", name)
+		}
+	}
+	if flag := outputCmd.Flags().Lookup("example"); flag != nil && flag.Shorthand != "e" {
+		t.Errorf("example flag shorthand = %q, want %q", flag.Shorthand, "e")
+	}
+}
+
+func TestFormatOutputValue(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"string", `"/subscriptions/abc/vnets/hub"` + "\n", "/subscriptions/abc/vnets/hub\n"},
+		{"number", "3\n", "3\n"},
+		{"bool", "true", "true\n"},
+		{"list", `["a","b"]`, "[\n  \"a\",\n  \"b\"\n]\n"},
+		{"object", `{"id":"x"}`, "{\n  \"id\": \"x\"\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formatOutputValue([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("formatOutputValue() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("formatOutputValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := formatOutputValue([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestRunOutput_Arguments(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withWorkingDir(t, tmpDir)
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "network"))
+
+	err := runOutput(outputCmd, []string{"missing"})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected module not found error, got %v", err)
+	}
+
+	// With --path, the first argument is the output name, so two are too many
+	pathFlag = filepath.Join(tmpDir, DirComponents, "network")
+	err = runOutput(outputCmd, []string{"vnet_id", "subnet_ids"})
+	if err == nil || !strings.Contains(err.Error(), "single output name") {
+		t.Errorf("expected single output name error, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/toolchain"
+	"github.com/spf13/cobra"
+)
+
+// newInstaller returns the installer of toolchain install. It is a variable
+// so tests can replace it.
+var newInstaller = toolchain.NewInstaller
+
+// toolchainCmd represents the toolchain command
+var toolchainCmd = &cobra.Command{
+	Use:   "toolchain",
+	Short: "Manage the terraform/tofu releases motf runs",
+	Long: `Manage the terraform/tofu releases motf runs.
+
+With binary_version set in .motf.yml, motf runs the newest release installed
+by 'motf toolchain install' that satisfies it, or else the binary on PATH when
+its version (from version -json) does. Commands fail when neither does.
+
+Releases are installed in $MOTF_TOOLCHAIN_DIR, or motf/toolchain in the user
+cache directory.`,
+}
+
+// toolchainInstallCmd represents the toolchain install command
+var toolchainInstallCmd = &cobra.Command{
+	Use:   "install [constraint]",
+	Short: "Install the terraform/tofu release matching binary_version",
+	Long: `Download the newest stable terraform/tofu release that satisfies a version
+constraint into the toolchain directory, unless it's installed already. The
+constraint defaults to binary_version in .motf.yml.
+
+terraform releases come from releases.hashicorp.com and tofu releases from the
+OpenTofu GitHub releases, depending on binary (terraform for auto). Archives are
+verified against the SHA256SUMS of the release. Use --dry-run to print the
+release that would be installed.`,
+	Example: `  motf toolchain install            # Install the release matching binary_version
+  motf toolchain install 1.9.5      # Install a specific version
+  motf toolchain install "~> 1.8.0" # Install the newest 1.8 release`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runToolchainInstall,
+}
+
+func init() {
+	toolchainCmd.AddCommand(toolchainInstallCmd)
+	rootCmd.AddCommand(toolchainCmd)
+}
+
+func runToolchainInstall(cmd *cobra.Command, args []string) error {
+	constraint := cfg.BinaryVersion
+	if len(args) == 1 {
+		constraint = args[0]
+	}
+	if constraint == "" {
+		return fmt.Errorf("no version to install: set binary_version in %s, or pass a version constraint", config.ConfigFile)
+	}
+	binary := cfg.Binary
+	if binary == config.BinaryAuto {
+		binary = "terraform"
+	}
+
+	dir, err := toolchain.Dir()
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	installer := newInstaller(dir)
+	out := cmd.OutOrStdout()
+	ctx := context.Background()
+
+	if dryRunFlag {
+		releases, err := installer.Releases(ctx, binary, constraint)
+		if err != nil {
+			return err
+		}
+		if len(releases) == 0 {
+			return fmt.Errorf("no %s release matches '%s'", binary, constraint)
+		}
+		_, _ = fmt.Fprintf(out, "[dry-run] Would install %s %s to %s\n", binary, releases[0], toolchain.Path(dir, binary, releases[0]))
+		return nil
+	}
+
+	release, err := installer.Install(ctx, binary, constraint)
+	if err != nil {
+		return err
+	}
+	if release.Cached {
+		_, _ = fmt.Fprintf(out, "%s %s is already installed at %s\n", release.Binary, release.Version, release.Path)
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "Installed %s %s to %s\n", release.Binary, release.Version, release.Path)
+	return nil
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/toolchain"
+)
+
+// fakeTransport serves the files of a terraform 1.9.5 release by URL.
+type fakeTransport map[string][]byte
+
+func (f fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, ok := f[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(bytes.NewReader(body))}, nil
+}
+
+// withFakeReleases makes toolchain install download from a fake terraform
+// release index with 1.8.5 and 1.9.5, and install into a temporary directory.
+func withFakeReleases(t *testing.T) string {
+	t.Helper()
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, err := zw.Create("terraform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("#!/bin/sh\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(archive.Bytes())
+	base := "https://releases.hashicorp.com/terraform/"
+	transport := fakeTransport{
+		base + "index.json":                            []byte(`{"versions": {"1.8.5": {}, "1.9.5": {}}}`),
+		base + "1.9.5/terraform_1.9.5_linux_amd64.zip": archive.Bytes(),
+		base + "1.9.5/terraform_1.9.5_SHA256SUMS":      []byte(hex.EncodeToString(sum[:]) + "  terraform_1.9.5_linux_amd64.zip\n"),
+	}
+
+	dir := t.TempDir()
+	t.Setenv(toolchain.EnvDir, dir)
+	original := newInstaller
+	newInstaller = func(dir string) *toolchain.Installer {
+		i := original(dir)
+		i.OS, i.Arch = "linux", "amd64"
+		i.HTTPClient = &http.Client{Transport: transport}
+		return i
+	}
+	t.Cleanup(func() { newInstaller = original })
+	return dir
+}
+
+// toolchainRepo creates a repository with .motf.yml content and makes it the
+// working directory.
+func toolchainRepo(t *testing.T, content string) {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".motf.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	withWorkingDir(t, root)
+}
+
+func runToolchain(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(args)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+	})
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func TestToolchainInstallCmd(t *testing.T) {
+	resetFlags(t)
+	dir := withFakeReleases(t)
+	toolchainRepo(t, "binary: auto\nbinary_version: \"~> 1.9.0\"\n")
+	path := toolchain.Path(dir, "terraform", "1.9.5")
+
+	out, err := runToolchain(t, "toolchain", "install", "--dry-run")
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if out != "[dry-run] Would install terraform 1.9.5 to "+path+"\n" {
+		t.Errorf("unexpected dry run output: %q", out)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("dry run installed the binary")
+	}
+	dryRunFlag = false
+
+	out, err = runToolchain(t, "toolchain", "install")
+	if err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	if out != "Installed terraform 1.9.5 to "+path+"\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("binary not installed: %v", err)
+	}
+
+	out, err = runToolchain(t, "toolchain", "install", "1.9.5")
+	if err != nil || !strings.Contains(out, "terraform 1.9.5 is already installed") {
+		t.Errorf("second install = %q, %v, want already installed", out, err)
+	}
+}
+
+func TestToolchainInstallCmd_Errors(t *testing.T) {
+	resetFlags(t)
+	withFakeReleases(t)
+
+	toolchainRepo(t, "binary: terraform\n")
+	if _, err := runToolchain(t, "toolchain", "install"); err == nil || !strings.Contains(err.Error(), "set binary_version in .motf.yml") {
+		t.Errorf("expected an error without a constraint, got %v", err)
+	}
+	if _, err := runToolchain(t, "toolchain", "install", "~> 2.0"); err == nil || !strings.Contains(err.Error(), "no terraform release matches '~> 2.0'") {
+		t.Errorf("expected an error without a matching release, got %v", err)
+	}
+}
@@ -1,5 +1,14 @@
 package cli
 
+import (
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
 // Module directory constants
 const (
 	DirComponents = "components"
@@ -27,7 +36,11 @@ const (
 	TypeProject   = "project"
 )
 
-// ModuleDirs contains all module directory names
+// ModuleTypes contains the default module types, in ModuleDirs order
+var ModuleTypes = []string{TypeComponent, TypeBase, TypeProject}
+
+// ModuleDirs contains the default module directory names, used unless
+// module_dirs is set in .motf.yml
 var ModuleDirs = []string{DirComponents, DirBases, DirProjects}
 
 // ModuleTypeOrder defines the sorting order for module types
@@ -43,4 +56,105 @@ type ModuleInfo struct {
 	Type    string `json:"type"`
 	Path    string `json:"path"`
 	Version string `json:"version,omitempty"`
+	Binary  string `json:"binary,omitempty"` // Effective terraform/tofu binary, set for multi-module runs
+
+	// Timeout of the command in the module, set for multi-module runs. Modules
+	// with longer timeouts are expected to take longer and start first.
+	Timeout time.Duration `json:"-"`
+
+	// Estimated cloud cost of testing the module, set for multi-module runs
+	Cost float64 `json:"-"`
+
+	// Paths of the modules of the run that have to finish before the module
+	// starts, set for plan and apply runs
+	DependsOn []string `json:"-"`
+}
+
+// moduleDirs returns the module directories of the config (module_dirs), or
+// the defaults.
+func moduleDirs() []config.ModuleDir {
+	return cfg.GetModuleDirs()
+}
+
+// moduleDirNames returns the names of the module directories, in order.
+func moduleDirNames() []string {
+	dirs := moduleDirs()
+	names := make([]string, len(dirs))
+	for i, d := range dirs {
+		names[i] = d.Dir
+	}
+	return names
+}
+
+// moduleRoots returns the module roots (roots), slash-separated paths
+// relative to the base path, or just "" when modules are in the base path.
+func moduleRoots() []string {
+	if roots := cfg.GetRoots(); len(roots) > 0 {
+		return roots
+	}
+	return []string{""}
+}
+
+// searchDirs returns the module directories of every root, with their paths
+// relative to the base path, e.g. iac/platform/components.
+func searchDirs() []config.ModuleDir {
+	var dirs []config.ModuleDir
+	for _, root := range moduleRoots() {
+		for _, d := range moduleDirs() {
+			dirs = append(dirs, config.ModuleDir{Dir: path.Join(root, d.Dir), Type: d.Type})
+		}
+	}
+	return dirs
+}
+
+// splitRoot splits relPath, a slash-separated path relative to the base
+// path, into its module root and the path inside it.
+func splitRoot(relPath string) (root, inRoot string) {
+	for _, r := range cfg.GetRoots() {
+		if strings.HasPrefix(relPath, r+"/") && len(r) > len(root) {
+			root = r
+		}
+	}
+	if root == "" {
+		return "", relPath
+	}
+	return root, strings.TrimPrefix(relPath, root+"/")
+}
+
+// moduleTypes returns the types of the modules in the module directories,
+// then those of the types section, each once, in order.
+func moduleTypes() []string {
+	var types []string
+	for _, d := range moduleDirs() {
+		if !slices.Contains(types, d.Type) {
+			types = append(types, d.Type)
+		}
+	}
+	for _, t := range cfg.RuleTypes() {
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// moduleTypeOf returns the type of the module at relPath (relative to the
+// root) given by the types section, or dirType, the type of its module
+// directory.
+func moduleTypeOf(relPath, dirType string) string {
+	if t := cfg.ModuleType(relPath); t != "" {
+		return t
+	}
+	return dirType
+}
+
+// typeDir returns the first module directory of modules of type moduleType,
+// or fallback if there is none.
+func typeDir(moduleType, fallback string) string {
+	for _, d := range moduleDirs() {
+		if d.Type == moduleType {
+			return d.Dir
+		}
+	}
+	return fallback
 }
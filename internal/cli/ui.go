@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// uiActions are the commands that can be run from the UI
+var uiActions = []tui.Action{
+	{Key: 'f', Name: "fmt"},
+	{Key: 'v', Name: "validate"},
+	{Key: 'p', Name: "plan"},
+}
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Browse modules and run commands in an interactive terminal UI",
+	Long: `Open an interactive terminal UI listing all modules.
+
+The side panel shows the interface of the module under the cursor, as
+'motf describe' does. Select modules with space (or all with 'a') and run
+fmt, validate, or plan on them with 'f', 'v', or 'p'; without a selection the
+command runs on the module under the cursor. Output is streamed into the
+output pane, prefixed with the module name. Press 'q' to quit.`,
+	Example: `  motf ui        # Open the UI
+  motf ui -p     # Run commands on selected modules in parallel`,
+	Args: cobra.NoArgs,
+	RunE: runUI,
+}
+
+func init() {
+	uiCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands on selected modules in parallel")
+	uiCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	rootCmd.AddCommand(uiCmd)
+}
+
+func runUI(cmd *cobra.Command, args []string) error {
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	modules, err := collectModules(basePath, "")
+	if err != nil {
+		return err
+	}
+	sortModules(modules)
+
+	return tui.Run(os.Stdin, os.Stdout, uiOptions(basePath, modules))
+}
+
+// uiOptions returns the UI options for modules under basePath.
+func uiOptions(basePath string, modules []ModuleInfo) tui.Options {
+	byPath := make(map[string]ModuleInfo, len(modules))
+	items := make([]tui.Item, len(modules))
+	for i, mod := range modules {
+		byPath[mod.Path] = mod
+		items[i] = tui.Item{Name: mod.Name, Type: mod.Type, Path: mod.Path}
+	}
+
+	return tui.Options{
+		Items:   items,
+		Actions: uiActions,
+		Details: func(item tui.Item) []string {
+			return uiDetails(filepath.Join(basePath, item.Path))
+		},
+		Run: func(action string, items []tui.Item, out io.Writer) error {
+			selected := make([]ModuleInfo, len(items))
+			for i, item := range items {
+				selected[i] = byPath[item.Path]
+			}
+			return runUIAction(basePath, action, selected, out)
+		},
+	}
+}
+
+// uiDetails returns the lines of the details panel for the module at path:
+// its interface as printed by 'motf describe --plain'.
+func uiDetails(modulePath string) []string {
+	schema, err := terraform.LoadModuleSchema(modulePath, getRoot())
+	if err != nil {
+		return []string{fmt.Sprintf("failed to parse module: %v", err)}
+	}
+
+	var buf bytes.Buffer
+	c := &cobra.Command{}
+	c.SetOut(&buf)
+	printSchemaPlain(c, schema)
+	return strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+}
+
+// runUIAction runs action on modules, writing prefixed output to out.
+func runUIAction(basePath, action string, modules []ModuleInfo, out io.Writer) error {
+	if err := resolveModuleConfigs(basePath, modules); err != nil {
+		return err
+	}
+
+	var parallelismCfg *config.ParallelismConfig
+	if cfg != nil {
+		parallelismCfg = cfg.Parallelism
+	}
+
+	return runOnModules(modules, parallelFlag, parallelismCfg.GetMaxJobs(), out, out, func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		moduleAbsPath := filepath.Join(basePath, mod.Path)
+		tfRunner, err := runnerFor(moduleAbsPath)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case "fmt":
+			return tfRunner.RunFmtWithOutput(moduleAbsPath, stdout, stderr)
+		case "validate":
+			return tfRunner.RunValidateWithOutput(moduleAbsPath, stdout, stderr)
+		case "plan":
+			return tfRunner.RunPlanWithOutput(moduleAbsPath, stdout, stderr)
+		}
+		return fmt.Errorf("unknown action %q", action)
+	})
+}
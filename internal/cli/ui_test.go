@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tui"
+)
+
+func TestUICmd_Flags(t *testing.T) {
+	for _, name := range []string{"parallel", "max-parallel"} {
+		if uiCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected ui to have a --%s flag", name)
+		}
+	}
+	if err := uiCmd.Args(uiCmd, []string{"storage"}); err == nil {
+		t.Error("expected ui to reject arguments")
+	}
+}
+
+func TestUIOptions(t *testing.T) {
+	resetFlags(t)
+	dryRunFlag = true
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+
+	dnsPath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	if err := os.WriteFile(filepath.Join(dnsPath, "variables.tf"), []byte(`variable "zone" {
+  type        = string
+  description = "DNS zone name"
+}
+`), 0644); err != nil {
+		t.Fatalf("failed to write variables.tf: %v", err)
+	}
+
+	modules, err := collectModules(tmpDir, "")
+	if err != nil {
+		t.Fatalf("collectModules returned error: %v", err)
+	}
+	sortModules(modules)
+	opts := uiOptions(tmpDir, modules)
+
+	if len(opts.Items) != 2 || opts.Items[0].Name != "dns" || opts.Items[1].Name != "vnet" {
+		t.Fatalf("unexpected items: %+v", opts.Items)
+	}
+
+	details := strings.Join(opts.Details(opts.Items[0]), "\n")
+	for _, want := range []string{"Module: dns", "Variable: zone", "Description: DNS zone name"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("expected details to contain %q, got:\n%s", want, details)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := opts.Run("validate", opts.Items, &out); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	for _, want := range []string{"dns  |", "vnet |", "[dry-run] Would run terraform validate in " + dnsPath} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+
+	if err := opts.Run("apply", opts.Items[:1], &out); err == nil || !strings.Contains(err.Error(), `unknown action "apply"`) {
+		t.Errorf("expected unknown action error, got %v", err)
+	}
+}
+
+func TestUIDetails_InvalidModule(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir})
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.tf"), []byte("variable {"), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+
+	lines := uiDetails(tmpDir)
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "failed to parse module") {
+		t.Errorf("expected a parse error line, got %v", lines)
+	}
+}
+
+func TestRunUI_NotTerminal(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+
+	// Test stdin isn't a terminal
+	if err := runUI(uiCmd, nil); !errors.Is(err, tui.ErrNotTerminal) {
+		t.Errorf("expected ErrNotTerminal, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+// upgradeCheckFlag only reports whether a newer release is available
+var upgradeCheckFlag bool
+
+// newUpdater returns the updater of upgrade, and executablePath the
+// executable it replaces. They are variables so tests can replace them.
+var (
+	newUpdater     = selfupdate.NewUpdater
+	executablePath = os.Executable
+)
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade motf to the latest release",
+	Long: `Replace the running motf executable with the latest release from GitHub,
+when it is newer than this version.
+
+The archive for this OS and architecture is verified against the checksums of
+the release before the executable is replaced. Set $GITHUB_TOKEN to avoid the
+rate limit of anonymous GitHub API requests. Use --check to only report whether
+a newer release is available.
+
+Development builds can't be upgraded, since their version can't be compared;
+install a release instead.`,
+	Example: `  motf upgrade          # Upgrade to the latest release
+  motf upgrade --check  # Only check for a newer release`,
+	Args: cobra.NoArgs,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeCheckFlag, "check", false, "Only check whether a newer release is available")
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	out := cmd.OutOrStdout()
+	current, _, _ := effectiveVersion()
+	ctx := context.Background()
+
+	updater := newUpdater()
+	latest, err := updater.Latest(ctx)
+	if err != nil {
+		return err
+	}
+	newer, err := selfupdate.Newer(current, latest.Version)
+	if errors.Is(err, selfupdate.ErrUnknownVersion) {
+		if upgradeCheckFlag {
+			_, _ = fmt.Fprintf(out, "motf %s is a development build; the latest release is %s\n", current, latest.Version)
+			return nil
+		}
+		return fmt.Errorf("can't upgrade development build '%s': install a release from https://github.com/%s/releases", current, selfupdate.Repository)
+	}
+	if err != nil {
+		return err
+	}
+	if !newer {
+		_, _ = fmt.Fprintf(out, "motf %s is up to date\n", current)
+		return nil
+	}
+	if upgradeCheckFlag {
+		_, _ = fmt.Fprintf(out, "motf %s is available (current: %s), run 'motf upgrade' to install it\n", latest.Version, current)
+		return nil
+	}
+
+	path, err := executablePath()
+	if err != nil {
+		return fmt.Errorf("failed to find the motf executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if dryRunFlag {
+		_, _ = fmt.Fprintf(out, "[dry-run] Would upgrade motf %s to %s at %s\n", current, latest.Version, path)
+		return nil
+	}
+
+	binary, err := updater.Download(ctx, latest)
+	if err != nil {
+		return err
+	}
+	if err := selfupdate.Replace(path, binary); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	_, _ = fmt.Fprintf(out, "Upgraded motf %s to %s at %s\n", current, latest.Version, path)
+	return nil
+}
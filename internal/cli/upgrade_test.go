@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/selfupdate"
+)
+
+// withFakeUpgrade makes upgrade find a fake 1.4.0 release on GitHub,
+// running as version current from a temporary executable, whose path it
+// returns.
+func withFakeUpgrade(t *testing.T, current string) string {
+	t.Helper()
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	body := []byte("motf 1.4.0")
+	if err := tw.WriteHeader(&tar.Header{Name: "motf", Mode: 0o755, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = tw.Write(body)
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(archive.Bytes())
+	transport := fakeTransport{
+		"https://api.github.com/repos/TechnicallyJoe/terraform-motf/releases/latest": []byte(`{"tag_name": "v1.4.0", "assets": [
+			{"name": "motf_1.4.0_linux_amd64.tar.gz", "browser_download_url": "https://dl.test/motf_1.4.0_linux_amd64.tar.gz"},
+			{"name": "checksums.txt", "browser_download_url": "https://dl.test/checksums.txt"}
+		]}`),
+		"https://dl.test/motf_1.4.0_linux_amd64.tar.gz": archive.Bytes(),
+		"https://dl.test/checksums.txt":                 []byte(hex.EncodeToString(sum[:]) + "  motf_1.4.0_linux_amd64.tar.gz\n"),
+	}
+
+	exe := filepath.Join(t.TempDir(), "motf")
+	if err := os.WriteFile(exe, []byte("motf "+current), 0o755); err != nil { //nolint:gosec // test executable
+		t.Fatal(err)
+	}
+	origUpdater, origExecutable, origVersion := newUpdater, executablePath, version
+	newUpdater = func() *selfupdate.Updater {
+		u := origUpdater()
+		u.OS, u.Arch = "linux", "amd64"
+		u.HTTPClient = &http.Client{Transport: transport}
+		return u
+	}
+	executablePath = func() (string, error) { return exe, nil }
+	version = current
+	t.Cleanup(func() { newUpdater, executablePath, version = origUpdater, origExecutable, origVersion })
+	return exe
+}
+
+// executableContent returns the content of the executable at path.
+func executableContent(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestUpgradeCmd(t *testing.T) {
+	resetFlags(t)
+	exe := withFakeUpgrade(t, "1.3.0")
+	withWorkingDir(t, t.TempDir())
+
+	out, err := runToolchain(t, "upgrade", "--check")
+	if err != nil {
+		t.Fatalf("upgrade --check failed: %v", err)
+	}
+	if out != "motf 1.4.0 is available (current: 1.3.0), run 'motf upgrade' to install it\n" {
+		t.Errorf("unexpected check output: %q", out)
+	}
+	upgradeCheckFlag = false
+
+	out, err = runToolchain(t, "upgrade", "--dry-run")
+	if err != nil || !strings.HasPrefix(out, "[dry-run] Would upgrade motf 1.3.0 to 1.4.0 at ") {
+		t.Errorf("dry run = %q, %v", out, err)
+	}
+	if executableContent(t, exe) != "motf 1.3.0" {
+		t.Fatal("dry run replaced the executable")
+	}
+	dryRunFlag = false
+
+	out, err = runToolchain(t, "upgrade")
+	if err != nil {
+		t.Fatalf("upgrade failed: %v", err)
+	}
+	if !strings.HasPrefix(out, "Upgraded motf 1.3.0 to 1.4.0 at ") {
+		t.Errorf("unexpected output: %q", out)
+	}
+	if executableContent(t, exe) != "motf 1.4.0" {
+		t.Error("the executable was not replaced")
+	}
+}
+
+func TestUpgradeCmd_UpToDate(t *testing.T) {
+	resetFlags(t)
+	exe := withFakeUpgrade(t, "v1.4.0")
+	withWorkingDir(t, t.TempDir())
+
+	out, err := runToolchain(t, "upgrade")
+	if err != nil || out != "motf v1.4.0 is up to date\n" {
+		t.Errorf("upgrade = %q, %v, want up to date", out, err)
+	}
+	if executableContent(t, exe) != "motf v1.4.0" {
+		t.Error("an up to date executable was replaced")
+	}
+}
+
+func TestUpgradeCmd_DevelopmentBuild(t *testing.T) {
+	resetFlags(t)
+	withFakeUpgrade(t, "dev")
+	withWorkingDir(t, t.TempDir())
+	origReadBuildInfo := readBuildInfo
+	readBuildInfo = mockBuildInfo(nil, false)
+	t.Cleanup(func() { readBuildInfo = origReadBuildInfo })
+
+	if _, err := runToolchain(t, "upgrade"); err == nil || !strings.Contains(err.Error(), "can't upgrade development build 'dev'") {
+		t.Errorf("upgrade error = %v, want development build", err)
+	}
+	out, err := runToolchain(t, "upgrade", "--check")
+	if err != nil || out != "motf dev is a development build; the latest release is 1.4.0\n" {
+		t.Errorf("upgrade --check = %q, %v", out, err)
+	}
+}
@@ -18,20 +18,26 @@ Use the --example/-e flag to run validate on a specific example instead of the m
 Examples:
   motf val storage-account              # Run validate on storage-account module
   motf val storage-account -e basic     # Run validate on the 'basic' example
-  motf val -i storage-account -e basic  # Run init then validate on the 'basic' example`,
-	Args: cobra.MaximumNArgs(1),
+  motf val -i storage-account -e basic  # Run init then validate on the 'basic' example
+  motf val -i --all -p                  # Run init then validate on all modules in parallel`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if changedFlag {
+		if selectingModules() {
 			if len(args) > 0 {
 				return cobra.MaximumNArgs(0)(cmd, args)
 			}
-			return runOnChangedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+			return runOnSelectedModulesWithPath(func(moduleAbsPath string, stdout, stderr io.Writer) error {
+				tfRunner, err := runnerFor(moduleAbsPath)
+				if err != nil {
+					return err
+				}
 				if initFlag {
-					if err := runner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
+					if err := tfRunner.RunInitWithOutput(moduleAbsPath, stdout, stderr); err != nil {
 						return err
 					}
 				}
-				return runner.RunValidateWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
+				return tfRunner.RunValidateWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
 			})
 		}
 
@@ -40,23 +46,32 @@ Examples:
 			return err
 		}
 
+		tfRunner, err := runnerFor(targetPath)
+		if err != nil {
+			return err
+		}
+
 		// Run init first if flag is set
 		if initFlag {
-			if err := runner.RunInit(targetPath); err != nil {
+			if err := tfRunner.RunInit(targetPath); err != nil {
 				return err
 			}
 		}
 
-		return runner.RunValidate(targetPath, argsFlag...)
+		return tfRunner.RunValidate(targetPath, argsFlag...)
 	},
 }
 
 func init() {
 	valCmd.Flags().BoolVarP(&initFlag, "init", "i", false, "Run init before the command")
 	valCmd.Flags().StringVarP(&exampleFlag, "example", "e", "", "Run on a specific example instead of the module")
+	valCmd.Flags().BoolVar(&allFlag, "all", false, "Run on all modules")
 	valCmd.Flags().BoolVar(&changedFlag, "changed", false, "Run on modules changed compared to --ref")
+	valCmd.Flags().StringVar(&selectFlag, "select", "", "Run on modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	valCmd.Flags().StringVar(&typeFlag, "type", "", "Run on modules of a type (component, base, project)")
 	valCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
-	valCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run commands in parallel")
-	valCmd.Flags().IntVar(&maxParallelFlag, "max-parallel", 0, "Maximum parallel jobs (default: number of CPU cores)")
+	addChangeRangeFlags(valCmd)
+	addParallelFlags(valCmd)
+	addRetriesFlag(valCmd)
 	rootCmd.AddCommand(valCmd)
 }
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tfvars"
+	"github.com/spf13/cobra"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+var (
+	varsEnvFlag    string   // Replaces {env} in layer paths
+	varsRegionFlag string   // Replaces {region} in layer paths
+	varsVarFlags   []string // -var values applied on top of all layers
+	varsJSONFlag   bool     // Output the layers and values as JSON
+)
+
+// varsCmd groups the variable file commands
+var varsCmd = &cobra.Command{
+	Use:   "vars",
+	Short: "Work with layered variable files of projects",
+}
+
+var varsRenderCmd = &cobra.Command{
+	Use:   "render [module-name]",
+	Short: "Print the effective variable values of a project",
+	Long: `Merge the variable file layers of a project and print the effective values,
+to review them before running plan or apply.
+
+The layers are the files listed in 'vars' in .motf.yml or the project's
+.motf.module.yml, relative to the project and lowest precedence first. {env}
+and {region} in their paths are replaced with --env and --region; layers whose
+placeholders aren't given, and files that don't exist, are skipped. --var
+values are applied last. As with -var-file, a variable set in a higher layer
+replaces its whole value.
+
+The output is a valid .tfvars file with a comment naming the layer that set
+each value.`,
+	Example: `  motf vars render platform --env prod                   # base < prod
+  motf vars render platform --env prod --region weu      # base < prod < prod/weu
+  motf vars render platform --env dev --var sku=Basic    # -var on top of all layers
+  motf vars render platform --env prod --json            # Output as JSON`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runVarsRender,
+}
+
+func init() {
+	varsRenderCmd.Flags().StringVar(&varsEnvFlag, "env", "", "Environment that replaces {env} in layer paths")
+	varsRenderCmd.Flags().StringVar(&varsRegionFlag, "region", "", "Region that replaces {region} in layer paths")
+	varsRenderCmd.Flags().StringArrayVar(&varsVarFlags, "var", nil, "Set a variable on top of all layers: name=value (repeatable)")
+	varsRenderCmd.Flags().BoolVar(&varsJSONFlag, "json", false, "Output the layers and values as JSON")
+	varsCmd.AddCommand(varsRenderCmd)
+	rootCmd.AddCommand(varsCmd)
+}
+
+// varsValueJSON is a value in the JSON output of vars render
+type varsValueJSON struct {
+	Value  ctyjson.SimpleJSONValue `json:"value"`
+	Source string                  `json:"source"`
+}
+
+// varsRenderJSON is the JSON output of vars render
+type varsRenderJSON struct {
+	Module string                   `json:"module"`
+	Layers []tfvars.Layer           `json:"layers"`
+	Values map[string]varsValueJSON `json:"values"`
+}
+
+func runVarsRender(cmd *cobra.Command, args []string) error {
+	targetPath, err := resolveTargetPath(args)
+	if err != nil {
+		return err
+	}
+	modCfg, err := moduleConfig(targetPath)
+	if err != nil {
+		return err
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modulePath := displayPath(basePath, targetPath)
+	if len(modCfg.Vars) == 0 {
+		return fmt.Errorf("no variable file layers for %s: list them in 'vars' in %s or the module's %s", modulePath, config.ConfigFile, config.ModuleConfigFile)
+	}
+
+	result, err := tfvars.Resolve(targetPath, modCfg.Vars, tfvars.Params{Env: varsEnvFlag, Region: varsRegionFlag}, varsVarFlags)
+	if err != nil {
+		return err
+	}
+
+	// Write to stdout, so the output can be redirected to a .tfvars file
+	out := cmd.OutOrStdout()
+	if varsJSONFlag {
+		rendered := varsRenderJSON{Module: modulePath, Layers: result.Layers, Values: make(map[string]varsValueJSON, len(result.Values))}
+		if rendered.Layers == nil {
+			rendered.Layers = []tfvars.Layer{}
+		}
+		for _, v := range result.Values {
+			rendered.Values[v.Name] = varsValueJSON{Value: ctyjson.SimpleJSONValue{Value: v.Value}, Source: v.Source}
+		}
+		output, err := json.MarshalIndent(rendered, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(output))
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "# Effective values of %s\n", modulePath)
+	_, _ = fmt.Fprintln(out, "# Layers, lowest precedence first:")
+	for _, layer := range result.Layers {
+		if layer.Found {
+			_, _ = fmt.Fprintf(out, "#   %s\n", layer.File)
+		} else {
+			_, _ = fmt.Fprintf(out, "#   %s (not found)\n", layer.File)
+		}
+	}
+	if len(result.Values) == 0 {
+		_, _ = fmt.Fprintln(out, "# No values set")
+		return nil
+	}
+	_, _ = fmt.Fprintln(out)
+	_, _ = out.Write(tfvars.Render(result.Values))
+	return nil
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// resetVarsFlags resets the vars command flags after the test.
+func resetVarsFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		varsEnvFlag = ""
+		varsRegionFlag = ""
+		varsVarFlags = nil
+		varsJSONFlag = false
+		varsRenderCmd.SetOut(nil)
+	})
+}
+
+// createLayeredProject creates a project with base and prod variable files.
+func createLayeredProject(t *testing.T, tmpDir string) {
+	t.Helper()
+	projectPath := createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+	files := map[string]string{
+		"base.tfvars": "location = \"westeurope\"\nsku      = \"Standard\"\n",
+		"prod.tfvars": "sku = \"Premium\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(projectPath, "vars", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVarsRenderCmd_Flags(t *testing.T) {
+	for _, name := range []string{"env", "region", "var", "json"} {
+		if varsRenderCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected --%s flag to be registered", name)
+		}
+	}
+}
+
+func TestRunVarsRender(t *testing.T) {
+	resetFlags(t)
+	resetVarsFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Vars: []string{"vars/base.tfvars", "vars/{env}.tfvars", "vars/{env}/{region}.tfvars"}})
+	createLayeredProject(t, tmpDir)
+
+	var out bytes.Buffer
+	varsRenderCmd.SetOut(&out)
+	varsEnvFlag = "prod"
+	varsRegionFlag = "weu"
+	varsVarFlags = []string{"replicas=3"}
+
+	if err := runVarsRender(varsRenderCmd, []string{"platform"}); err != nil {
+		t.Fatalf("runVarsRender() error = %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"# Effective values of projects/platform\n",
+		"#   vars/prod/weu.tfvars (not found)\n",
+		"# From vars/base.tfvars\nlocation = \"westeurope\"\n",
+		"# From vars/prod.tfvars\nsku = \"Premium\"\n",
+		"# From -var\nreplicas = \"3\"\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunVarsRender_JSON(t *testing.T) {
+	resetFlags(t)
+	resetVarsFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform", Vars: []string{"vars/base.tfvars", "vars/{env}.tfvars"}})
+	createLayeredProject(t, tmpDir)
+
+	var out bytes.Buffer
+	varsRenderCmd.SetOut(&out)
+	varsEnvFlag = "prod"
+	varsJSONFlag = true
+
+	if err := runVarsRender(varsRenderCmd, []string{"platform"}); err != nil {
+		t.Fatalf("runVarsRender() error = %v", err)
+	}
+
+	var rendered struct {
+		Layers []struct {
+			File  string `json:"file"`
+			Found bool   `json:"found"`
+		} `json:"layers"`
+		Values map[string]struct {
+			Value  any    `json:"value"`
+			Source string `json:"source"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &rendered); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if len(rendered.Layers) != 2 {
+		t.Errorf("layers = %+v, want 2", rendered.Layers)
+	}
+	if sku := rendered.Values["sku"]; sku.Value != "Premium" || sku.Source != "vars/prod.tfvars" {
+		t.Errorf("sku = %+v, want Premium from vars/prod.tfvars", sku)
+	}
+}
+
+func TestRunVarsRender_NoLayers(t *testing.T) {
+	resetFlags(t)
+	resetVarsFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+
+	err := runVarsRender(varsRenderCmd, []string{"platform"})
+	if err == nil || !strings.Contains(err.Error(), "no variable file layers") {
+		t.Errorf("expected an error without layers, got %v", err)
+	}
+}
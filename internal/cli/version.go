@@ -1,9 +1,29 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/spacelift"
 	"github.com/spf13/cobra"
 )
 
+// fileVersion is the plain-text version file updated by version bump --version-file
+const fileVersion = "VERSION"
+
+var (
+	versionBumpMajorFlag bool // Increment the major version
+	versionBumpMinorFlag bool // Increment the minor version
+	versionBumpPatchFlag bool // Increment the patch version (default)
+	versionBumpFileFlag  bool // Also write the new version to VERSION
+)
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
@@ -13,6 +33,172 @@ var versionCmd = &cobra.Command{
 	},
 }
 
+// versionBumpCmd represents the version bump command
+var versionBumpCmd = &cobra.Command{
+	Use:   "bump [module-name]",
+	Short: "Increment the Spacelift module_version of a module",
+	Long: `Increment the module_version in a module's .spacelift/config.yml. The patch
+version is incremented by default; --minor and --major reset the parts after
+them, e.g. 1.2.3 becomes 1.3.0 with --minor. Only the version is rewritten, so
+comments and other settings in the file are preserved.
+
+With --changed, --all, --select, or --type, every selected module with a
+module_version is bumped and a summary of old and new versions is printed.
+Selected modules without one are skipped. Use --version-file to also write the
+new version to a VERSION file in each module, and --dry-run to only show the
+new versions.`,
+	Example: `  motf version bump storage-account          # 1.2.3 -> 1.2.4
+  motf version bump storage-account --minor  # 1.2.3 -> 1.3.0
+  motf version bump --changed                # Bump every changed module
+  motf version bump --changed --dry-run      # Show the new versions`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runVersionBump,
+}
+
 func init() {
+	versionBumpCmd.Flags().BoolVar(&versionBumpMajorFlag, "major", false, "Increment the major version")
+	versionBumpCmd.Flags().BoolVar(&versionBumpMinorFlag, "minor", false, "Increment the minor version")
+	versionBumpCmd.Flags().BoolVar(&versionBumpPatchFlag, "patch", false, "Increment the patch version (default)")
+	versionBumpCmd.Flags().BoolVar(&versionBumpFileFlag, "version-file", false, "Also write the new version to a VERSION file in the module")
+	versionBumpCmd.Flags().BoolVar(&allFlag, "all", false, "Bump all modules")
+	versionBumpCmd.Flags().BoolVar(&changedFlag, "changed", false, "Bump modules changed compared to --ref")
+	versionBumpCmd.Flags().StringVar(&selectFlag, "select", "", "Bump modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	versionBumpCmd.Flags().StringVar(&typeFlag, "type", "", "Bump modules of a type (component, base, project)")
+	versionBumpCmd.Flags().StringVar(&refFlag, "ref", "", "Git ref for --changed (default: auto-detect from origin/HEAD)")
+	addChangeRangeFlags(versionBumpCmd)
+	versionCmd.AddCommand(versionBumpCmd)
 	rootCmd.AddCommand(versionCmd)
 }
+
+// versionBump is the outcome of bumping one module
+type versionBump struct {
+	Module string
+	Old    string
+	New    string
+}
+
+func runVersionBump(cmd *cobra.Command, args []string) error {
+	part, err := versionPart(versionBumpMajorFlag, versionBumpMinorFlag, versionBumpPatchFlag)
+	if err != nil {
+		return err
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+
+	if !selectingModules() {
+		targetPath, err := resolveTargetPath(args)
+		if err != nil {
+			return err
+		}
+		bump, err := bumpModuleVersion(basePath, targetPath, part)
+		if err != nil {
+			return fmt.Errorf("%s: %w", displayPath(basePath, targetPath), err)
+		}
+		printVersionBumps([]versionBump{bump})
+		return nil
+	}
+	if len(args) > 0 {
+		return cobra.MaximumNArgs(0)(cmd, args)
+	}
+
+	var mu sync.Mutex
+	var bumps []versionBump
+	var skipped []string
+	err = runOnSelectedModulesWithPath(func(modulePath string, stdout, stderr io.Writer) error {
+		bump, err := bumpModuleVersion(basePath, modulePath, part)
+		mu.Lock()
+		defer mu.Unlock()
+		if errors.Is(err, spacelift.ErrNoModuleVersion) {
+			skipped = append(skipped, displayPath(basePath, modulePath))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		bumps = append(bumps, bump)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(skipped)
+	for _, mod := range skipped {
+		logging.Warnf("skipped %s: %s", mod, spacelift.ErrNoModuleVersion)
+	}
+	sort.Slice(bumps, func(i, j int) bool { return bumps[i].Module < bumps[j].Module })
+	printVersionBumps(bumps)
+	return nil
+}
+
+// versionPart returns the version part selected by --major, --minor, or
+// --patch, defaulting to patch.
+func versionPart(major, minor, patch bool) (string, error) {
+	var parts []string
+	if major {
+		parts = append(parts, spacelift.PartMajor)
+	}
+	if minor {
+		parts = append(parts, spacelift.PartMinor)
+	}
+	if patch {
+		parts = append(parts, spacelift.PartPatch)
+	}
+	switch len(parts) {
+	case 0:
+		return spacelift.PartPatch, nil
+	case 1:
+		return parts[0], nil
+	default:
+		return "", fmt.Errorf("only one of --major, --minor, and --patch can be used")
+	}
+}
+
+// bumpModuleVersion bumps the module_version of the module at modulePath and,
+// with --version-file, writes the new version to its VERSION file.
+func bumpModuleVersion(basePath, modulePath, part string) (versionBump, error) {
+	oldVersion, newVersion, err := spacelift.BumpModuleVersion(modulePath, part, dryRunFlag)
+	if err != nil {
+		return versionBump{}, err
+	}
+	if versionBumpFileFlag && !dryRunFlag {
+		if err := os.WriteFile(filepath.Join(modulePath, fileVersion), []byte(newVersion+"\n"), 0644); err != nil {
+			return versionBump{}, fmt.Errorf("failed to write %s: %w", fileVersion, err)
+		}
+	}
+	return versionBump{Module: displayPath(basePath, modulePath), Old: oldVersion, New: newVersion}, nil
+}
+
+// printVersionBumps outputs the old and new version of each bumped module
+func printVersionBumps(bumps []versionBump) {
+	if len(bumps) == 0 {
+		fmt.Println("No module versions bumped")
+		return
+	}
+
+	verb := "Bumped"
+	if dryRunFlag {
+		verb = "[dry-run] Would bump"
+	}
+	if plainFlag || len(bumps) == 1 {
+		for _, b := range bumps {
+			fmt.Printf("%s %s: %s -> %s\n", verb, b.Module, b.Old, b.New)
+		}
+		return
+	}
+
+	moduleWidth, oldWidth := len("MODULE"), len("OLD")
+	for _, b := range bumps {
+		moduleWidth = max(moduleWidth, len(b.Module))
+		oldWidth = max(oldWidth, len(b.Old))
+	}
+	fmt.Printf("%-*s  %-*s  %s\n", moduleWidth, "MODULE", oldWidth, "OLD", "NEW")
+	for _, b := range bumps {
+		fmt.Printf("%-*s  %-*s  %s\n", moduleWidth, b.Module, oldWidth, b.Old, b.New)
+	}
+	fmt.Printf("%s %d module version(s)\n", verb, len(bumps))
+}
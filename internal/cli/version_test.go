@@ -2,9 +2,14 @@ package cli
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/spacelift"
 )
 
 func TestVersionCmd_Output(t *testing.T) {
@@ -276,3 +281,120 @@ func TestEffectiveVersion_NoBuildInfo(t *testing.T) {
 		t.Errorf("date: got %q, want %q", d, "unknown")
 	}
 }
+
+func resetVersionBumpFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		versionBumpMajorFlag = false
+		versionBumpMinorFlag = false
+		versionBumpPatchFlag = false
+		versionBumpFileFlag = false
+	})
+}
+
+// createSpaceliftModule creates a module with a .spacelift/config.yml module_version
+func createSpaceliftModule(t *testing.T, base, rel, version string) string {
+	t.Helper()
+	modulePath := createTerraformModule(t, base, rel)
+	dir := filepath.Join(modulePath, spacelift.DirSpacelift)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create .spacelift: %v", err)
+	}
+	content := "version: 1\nmodule_version: " + version + "\n"
+	if err := os.WriteFile(filepath.Join(dir, spacelift.FileConfig), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config.yml: %v", err)
+	}
+	return modulePath
+}
+
+func TestVersionPart(t *testing.T) {
+	if part, err := versionPart(false, false, false); err != nil || part != spacelift.PartPatch {
+		t.Errorf("default part = %q, %v; want patch", part, err)
+	}
+	if part, err := versionPart(false, true, false); err != nil || part != spacelift.PartMinor {
+		t.Errorf("part = %q, %v; want minor", part, err)
+	}
+	if _, err := versionPart(true, true, false); err == nil || !strings.Contains(err.Error(), "only one of") {
+		t.Errorf("expected error for --major with --minor, got %v", err)
+	}
+}
+
+func TestVersionBump_Module(t *testing.T) {
+	resetFlags(t)
+	resetVersionBumpFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	modulePath := createSpaceliftModule(t, tmpDir, filepath.Join(DirComponents, "storage"), "1.2.3")
+	pathFlag = modulePath
+	versionBumpMinorFlag = true
+	versionBumpFileFlag = true
+
+	if err := runVersionBump(versionBumpCmd, nil); err != nil {
+		t.Fatalf("runVersionBump() error = %v", err)
+	}
+	if got := spacelift.ReadModuleVersion(modulePath); got != "1.3.0" {
+		t.Errorf("module_version = %s, want 1.3.0", got)
+	}
+	data, err := os.ReadFile(filepath.Join(modulePath, fileVersion))
+	if err != nil {
+		t.Fatalf("failed to read VERSION: %v", err)
+	}
+	if string(data) != "1.3.0\n" {
+		t.Errorf("VERSION = %q, want 1.3.0", data)
+	}
+}
+
+func TestVersionBump_ModuleWithoutVersion(t *testing.T) {
+	resetFlags(t)
+	resetVersionBumpFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	pathFlag = createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "storage"))
+
+	err := runVersionBump(versionBumpCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "no module_version") {
+		t.Fatalf("expected no module_version error, got %v", err)
+	}
+}
+
+func TestVersionBump_SelectedModules(t *testing.T) {
+	resetFlags(t)
+	resetVersionBumpFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	storage := createSpaceliftModule(t, tmpDir, filepath.Join(DirComponents, "storage"), "1.2.3")
+	network := createSpaceliftModule(t, tmpDir, filepath.Join(DirComponents, "network"), "v0.4.0")
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "prod"))
+	allFlag = true
+
+	if err := runVersionBump(versionBumpCmd, nil); err != nil {
+		t.Fatalf("runVersionBump() error = %v", err)
+	}
+	if got := spacelift.ReadModuleVersion(storage); got != "1.2.4" {
+		t.Errorf("storage module_version = %s, want 1.2.4", got)
+	}
+	if got := spacelift.ReadModuleVersion(network); got != "v0.4.1" {
+		t.Errorf("network module_version = %s, want v0.4.1", got)
+	}
+}
+
+func TestVersionBump_DryRun(t *testing.T) {
+	resetFlags(t)
+	resetVersionBumpFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	modulePath := createSpaceliftModule(t, tmpDir, filepath.Join(DirComponents, "storage"), "1.2.3")
+	pathFlag = modulePath
+	versionBumpFileFlag = true
+	dryRunFlag = true
+
+	if err := runVersionBump(versionBumpCmd, nil); err != nil {
+		t.Fatalf("runVersionBump() error = %v", err)
+	}
+	if got := spacelift.ReadModuleVersion(modulePath); got != "1.2.3" {
+		t.Errorf("expected --dry-run not to write, module_version = %s", got)
+	}
+	if _, err := os.Stat(filepath.Join(modulePath, fileVersion)); !os.IsNotExist(err) {
+		t.Errorf("expected --dry-run not to write VERSION, got %v", err)
+	}
+}
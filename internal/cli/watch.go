@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchCommandFlag  string        // Command to run on changes: fmt, validate, or task
+	watchDebounceFlag time.Duration // Quiet period after a change before running
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [module-name]",
+	Short: "Re-run a command on modules whenever their files change",
+	Long: `Watch module directories and re-run a command on each module whose files
+change, for a fast feedback loop while developing modules.
+
+Watches all modules by default, or a single module given by name or --path,
+or the modules matched by --select and --type. Changes to .tf, .tf.json,
+.tfvars, .tftest.hcl, and .go files trigger a run once --debounce passed
+without further changes, so saving several files runs once. Output is
+prefixed with the module name.
+
+The command is validate by default, or watch.command in .motf.yml: fmt,
+validate, or task with --task/-t. Press Ctrl+C to stop.`,
+	Example: `  motf watch                          # Validate any module whose files change
+  motf watch storage-account          # Validate storage-account on changes
+  motf watch --command fmt            # Format changed modules
+  motf watch -t lint --type component # Run the 'lint' task on changed components`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeModuleNames,
+	RunE:              runWatch,
+}
+
+func init() {
+	watchCmd.Flags().StringVar(&watchCommandFlag, "command", "", "Command to run on changes: fmt, validate, or task (default: watch.command, or validate)")
+	watchCmd.Flags().StringVarP(&taskFlag, "task", "t", "", "Task to run on changes, implies --command task")
+	watchCmd.Flags().DurationVar(&watchDebounceFlag, "debounce", 0, "Quiet period after a change before running (default: watch.debounce, or 300ms)")
+	watchCmd.Flags().StringVar(&selectFlag, "select", "", "Watch modules whose name or path matches a wildcard pattern (e.g., *storage*)")
+	watchCmd.Flags().StringVar(&typeFlag, "type", "", "Watch modules of a type (component, base, project)")
+	watchCmd.Flags().BoolVarP(&parallelFlag, "parallel", "p", false, "Run on changed modules in parallel")
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	command, task, err := watchCommand()
+	if err != nil {
+		return err
+	}
+	debounce := cfg.Watch.GetDebounce()
+	if watchDebounceFlag < 0 {
+		return fmt.Errorf("--debounce must be positive, got %s", watchDebounceFlag)
+	}
+	if watchDebounceFlag > 0 {
+		debounce = watchDebounceFlag
+	}
+
+	basePath, err := getBasePath()
+	if err != nil {
+		return err
+	}
+	modules, err := watchModules(basePath, args)
+	if err != nil {
+		return err
+	}
+	if len(modules) == 0 {
+		fmt.Println(noModulesMessage(selectFlag))
+		return nil
+	}
+	if err := resolveModuleConfigs(basePath, modules); err != nil {
+		return err
+	}
+
+	byDir := make(map[string]ModuleInfo, len(modules))
+	dirs := make([]string, len(modules))
+	for i, mod := range modules {
+		dirs[i] = filepath.Join(basePath, mod.Path)
+		byDir[dirs[i]] = mod
+	}
+
+	watcher, err := watch.New(dirs, debounce)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = watcher.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fn := watchRunner(basePath, command, task)
+	name := command
+	if task != "" {
+		name = "task " + task
+	}
+	fmt.Printf("[watch] Watching %d module(s), running %s on changes (Ctrl+C to stop)\n", len(modules), name)
+
+	maxJobs := cfg.Parallelism.GetMaxJobs()
+	return watcher.Run(ctx, func(dirs []string) {
+		changed := make([]ModuleInfo, len(dirs))
+		names := make([]string, len(dirs))
+		for i, dir := range dirs {
+			changed[i] = byDir[dir]
+			names[i] = changed[i].Name
+		}
+		fmt.Printf("[watch] %s Changed: %s\n", time.Now().Format("15:04:05"), strings.Join(names, ", "))
+
+		start := time.Now()
+		if err := runOnModules(changed, parallelFlag, maxJobs, os.Stdout, os.Stderr, fn); err != nil {
+			fmt.Fprintf(os.Stderr, "[watch] %s failed after %s: %v\n", name, time.Since(start).Round(time.Millisecond), err)
+			return
+		}
+		fmt.Printf("[watch] %s passed in %s\n", name, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// watchCommand returns the command to run on changes, and its task, from the
+// flags or watch config.
+func watchCommand() (command, task string, err error) {
+	command, task = cfg.Watch.GetCommand(), cfg.Watch.GetTask()
+	if taskFlag != "" {
+		command, task = "task", taskFlag
+	}
+	if watchCommandFlag != "" {
+		command = watchCommandFlag
+	}
+
+	if !slices.Contains(config.WatchCommands, command) {
+		return "", "", fmt.Errorf("invalid --command '%s': must be %s", command, strings.Join(config.WatchCommands, ", "))
+	}
+	if command != "task" {
+		if taskFlag != "" {
+			return "", "", fmt.Errorf("--task cannot be used with --command %s", command)
+		}
+		return command, "", nil
+	}
+	if task == "" {
+		return "", "", fmt.Errorf("--command task needs a task: use --task/-t")
+	}
+	if _, ok := cfg.Tasks[task]; !ok {
+		return "", "", fmt.Errorf("task '%s' not found in .motf.yml", task)
+	}
+	return command, task, nil
+}
+
+// watchModules returns the module given by name or --path, or all modules
+// matched by --select and --type.
+func watchModules(basePath string, args []string) ([]ModuleInfo, error) {
+	if len(args) == 0 && pathFlag == "" {
+		modules, err := selectModules(basePath)
+		if err != nil {
+			return nil, err
+		}
+		sortModules(modules)
+		return modules, nil
+	}
+	if selectFlag != "" || typeFlag != "" {
+		return nil, fmt.Errorf("--select and --type cannot be used with a module name or --path")
+	}
+
+	modulePath, err := resolveTargetPath(args)
+	if err != nil {
+		return nil, err
+	}
+	relPath, err := filepath.Rel(basePath, modulePath)
+	if err != nil {
+		relPath = modulePath
+	}
+	return []ModuleInfo{{
+		Name: filepath.Base(modulePath),
+		Type: getModuleType(modulePath),
+		Path: relPath,
+	}}, nil
+}
+
+// watchRunner returns the function running command, or task, on a module.
+func watchRunner(basePath, command, task string) ModuleRunner {
+	gitRoot, _ := git.GetRepoRoot()
+	return func(mod ModuleInfo, stdout, stderr io.Writer) error {
+		moduleAbsPath := filepath.Join(basePath, mod.Path)
+		if command == "task" {
+			taskRunner, err := taskRunnerFor(gitRoot, moduleAbsPath)
+			if err != nil {
+				return err
+			}
+			return taskRunner.RunWithOutput(task, moduleAbsPath, stdout, stderr)
+		}
+
+		tfRunner, err := runnerFor(moduleAbsPath)
+		if err != nil {
+			return err
+		}
+		if command == "fmt" {
+			return tfRunner.RunFmtWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
+		}
+		return tfRunner.RunValidateWithOutput(moduleAbsPath, stdout, stderr, argsFlag...)
+	}
+}
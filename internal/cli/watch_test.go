@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+)
+
+func TestWatchCmd_Flags(t *testing.T) {
+	for _, name := range []string{"command", "task", "debounce", "select", "type", "parallel"} {
+		if watchCmd.Flags().Lookup(name) == nil {
+			t.Errorf("expected watch to have a --%s flag", name)
+		}
+	}
+}
+
+func TestWatchCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		watch       *config.WatchConfig
+		commandFlag string
+		taskFlag    string
+		wantCommand string
+		wantTask    string
+		wantErr     string
+	}{
+		{name: "default", wantCommand: "validate"},
+		{name: "config", watch: &config.WatchConfig{Command: "fmt"}, wantCommand: "fmt"},
+		{name: "config task", watch: &config.WatchConfig{Task: "lint"}, wantCommand: "task", wantTask: "lint"},
+		{name: "flag over config", watch: &config.WatchConfig{Task: "lint"}, commandFlag: "fmt", wantCommand: "fmt"},
+		{name: "task flag", taskFlag: "lint", wantCommand: "task", wantTask: "lint"},
+		{name: "invalid command", commandFlag: "plan", wantErr: "invalid --command 'plan'"},
+		{name: "task without name", commandFlag: "task", wantErr: "needs a task"},
+		{name: "unknown task", taskFlag: "docs", wantErr: "task 'docs' not found"},
+		{name: "task with fmt", commandFlag: "fmt", taskFlag: "lint", wantErr: "--task cannot be used with --command fmt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t)
+			withConfig(t, &config.Config{
+				Watch: tt.watch,
+				Tasks: map[string]*tasks.TaskConfig{"lint": {Command: "tflint"}},
+			})
+			watchCommandFlag = tt.commandFlag
+			taskFlag = tt.taskFlag
+
+			command, task, err := watchCommand()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("watchCommand() error = %v", err)
+			}
+			if command != tt.wantCommand || task != tt.wantTask {
+				t.Errorf("watchCommand() = %q, %q, want %q, %q", command, task, tt.wantCommand, tt.wantTask)
+			}
+		})
+	}
+}
+
+func TestWatchModules(t *testing.T) {
+	resetFlags(t)
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir})
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "vnet"))
+	createTerraformModule(t, tmpDir, filepath.Join(DirProjects, "platform"))
+
+	modules, err := watchModules(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("watchModules() error = %v", err)
+	}
+	if len(modules) != 3 {
+		t.Errorf("expected all 3 modules, got %+v", modules)
+	}
+
+	typeFlag = "component"
+	modules, err = watchModules(tmpDir, nil)
+	if err != nil || len(modules) != 2 {
+		t.Errorf("expected 2 components, got %+v, %v", modules, err)
+	}
+
+	if _, err := watchModules(tmpDir, []string{"dns"}); err == nil || !strings.Contains(err.Error(), "cannot be used with a module name") {
+		t.Errorf("expected error for --type with a module name, got %v", err)
+	}
+
+	typeFlag = ""
+	modules, err = watchModules(tmpDir, []string{"platform"})
+	if err != nil {
+		t.Fatalf("watchModules(platform) error = %v", err)
+	}
+	want := ModuleInfo{Name: "platform", Type: "project", Path: filepath.Join(DirProjects, "platform")}
+	if len(modules) != 1 || !reflect.DeepEqual(modules[0], want) {
+		t.Errorf("watchModules(platform) = %+v, want %+v", modules, want)
+	}
+}
+
+func TestWatchRunner(t *testing.T) {
+	resetFlags(t)
+	dryRunFlag = true
+	tmpDir := t.TempDir()
+	withConfig(t, &config.Config{Root: tmpDir, Binary: "terraform"})
+	modulePath := createTerraformModule(t, tmpDir, filepath.Join(DirComponents, "dns"))
+	mod := ModuleInfo{Name: "dns", Path: filepath.Join(DirComponents, "dns")}
+
+	for command, want := range map[string]string{
+		"fmt":      "Would run terraform fmt",
+		"validate": "Would run terraform validate",
+	} {
+		var out bytes.Buffer
+		if err := watchRunner(tmpDir, command, "")(mod, &out, &out); err != nil {
+			t.Fatalf("%s: runner error = %v", command, err)
+		}
+		if !strings.Contains(out.String(), want) || !strings.Contains(out.String(), modulePath) {
+			t.Errorf("%s: expected %q in %s, got %q", command, want, modulePath, out.String())
+		}
+	}
+}
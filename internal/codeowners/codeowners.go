@@ -0,0 +1,134 @@
+// Package codeowners parses GitHub CODEOWNERS files and resolves the owners
+// of repository paths.
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Locations are the paths GitHub looks for a CODEOWNERS file in, relative to
+// the repository root and in order of precedence.
+var Locations = []string{
+	filepath.Join(".github", "CODEOWNERS"),
+	"CODEOWNERS",
+	filepath.Join("docs", "CODEOWNERS"),
+}
+
+// ErrNotFound is returned by Load when the repository has no CODEOWNERS file.
+var ErrNotFound = errors.New("no CODEOWNERS file found")
+
+// rule is a CODEOWNERS line: a path pattern and its owners
+type rule struct {
+	re     *regexp.Regexp
+	owners []string
+}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	Path  string // Path of the file that was loaded
+	rules []rule
+}
+
+// Load reads the first CODEOWNERS file found in Locations under repoRoot.
+func Load(repoRoot string) (*File, error) {
+	for _, loc := range Locations {
+		path := filepath.Join(repoRoot, loc)
+		data, err := os.ReadFile(filepath.Clean(path))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		f, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		f.Path = path
+		return f, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Parse parses the contents of a CODEOWNERS file. Blank lines and comments
+// are skipped; a pattern without owners clears the owners of its paths.
+func Parse(data []byte) (*File, error) {
+	f := &File{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		re, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern '%s': %w", lineNum, fields[0], err)
+		}
+		f.rules = append(f.rules, rule{re: re, owners: fields[1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Owners returns the owners of path (slash-separated, relative to the
+// repository root). As in GitHub, the last matching pattern wins. A pattern
+// that matches a directory also matches everything below it.
+func (f *File) Owners(path string) []string {
+	path = strings.Trim(filepath.ToSlash(path), "/")
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if f.rules[i].re.MatchString(path) {
+			return f.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// compilePattern converts a gitignore-style CODEOWNERS pattern to a regular
+// expression. Patterns with a leading or inner slash are anchored to the
+// repository root; others match at any depth.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	trimmed := strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(trimmed, "/")
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch c := trimmed[i]; {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	// Matching a directory matches its contents
+	b.WriteString("(/.*)?$")
+	return regexp.Compile(b.String())
+}
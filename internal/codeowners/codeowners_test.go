@@ -0,0 +1,73 @@
+package codeowners
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sample = `# Default owners
+*                        @org/platform
+
+/components/azurerm/     @org/azure-team   # Azure components
+/components/aws/**/vpc   @org/network
+projects/                @org/deployments
+storage-*                @alice alice@example.com
+/bases/legacy            # No owners
+`
+
+func TestOwners(t *testing.T) {
+	f, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"components/azurerm/key-vault", "@org/azure-team"},
+		{"components/azurerm/storage-account", "@alice alice@example.com"}, // Last match wins
+		{"components/aws/eu/west/vpc", "@org/network"},
+		{"components/aws/vpc", "@org/network"},
+		{"components/aws/s3", "@org/platform"},
+		{"projects/landing-zone", "@org/deployments"},
+		{"nested/projects/app", "@org/deployments"}, // Unanchored patterns match at any depth
+		{"bases/legacy", ""},
+		{"bases/legacy/submodule", ""},
+		{"bases/legacy-v2", "@org/platform"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(f.Owners(tt.path), " "); got != tt.want {
+			t.Errorf("Owners(%s) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	repoRoot := t.TempDir()
+	if _, err := Load(repoRoot); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// .github/CODEOWNERS takes precedence over the root file
+	if err := os.WriteFile(filepath.Join(repoRoot, "CODEOWNERS"), []byte("* @root\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoRoot, ".github"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoRoot, ".github", "CODEOWNERS"), []byte("* @github\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Load(repoRoot)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := f.Owners("components/x"); len(got) != 1 || got[0] != "@github" {
+		t.Errorf("Owners() = %v, want [@github]", got)
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/backend"
+)
+
+// BackendConfig represents the backend section, the template 'motf backend
+// init' generates the remote state backend configuration of projects from
+type BackendConfig struct {
+	Type         string         `yaml:"type"`         // azurerm, s3, or gcs
+	Config       map[string]any `yaml:"config"`       // Backend settings, with {project}, {path}, and {env} placeholders
+	Environments []string       `yaml:"environments"` // Environments to write a backend config file for
+	File         string         `yaml:"file"`         // Backend file in the project
+	ConfigDir    string         `yaml:"config_dir"`   // Directory of the backend config files in the project
+}
+
+// Backend defaults
+const (
+	DefaultBackendFile      = "backend.tf"
+	DefaultBackendConfigDir = "backends"
+)
+
+// GetFile returns the backend file in a project, defaulting to backend.tf.
+func (b *BackendConfig) GetFile() string {
+	if b == nil || b.File == "" {
+		return DefaultBackendFile
+	}
+	return b.File
+}
+
+// GetConfigDir returns the directory of the backend config files in a
+// project, defaulting to backends.
+func (b *BackendConfig) GetConfigDir() string {
+	if b == nil || b.ConfigDir == "" {
+		return DefaultBackendConfigDir
+	}
+	return b.ConfigDir
+}
+
+// GetEnvironments returns the environments to write a backend config file for.
+func (b *BackendConfig) GetEnvironments() []string {
+	if b == nil {
+		return nil
+	}
+	return b.Environments
+}
+
+// validate checks the type, its settings, the environments, and that the
+// files stay inside the project.
+func (b *BackendConfig) validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.Type == "" {
+		return fmt.Errorf("type is required: must be %s", quotedJoin(backend.Types()))
+	}
+	if !slices.Contains(backend.Types(), b.Type) {
+		return fmt.Errorf("invalid type '%s': must be %s", b.Type, quotedJoin(backend.Types()))
+	}
+	if err := backend.Validate(b.Type, b.Config); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	for i, env := range b.Environments {
+		if env == "" || env != filepath.Base(env) || env == "." || env == ".." {
+			return fmt.Errorf("invalid environment '%s': must be a plain name", env)
+		}
+		if slices.Contains(b.Environments[:i], env) {
+			return fmt.Errorf("duplicate environment '%s'", env)
+		}
+	}
+	for _, p := range []string{b.File, b.ConfigDir} {
+		if p != "" && (filepath.IsAbs(p) || !filepath.IsLocal(p)) {
+			return fmt.Errorf("invalid path '%s': must be relative to the project", p)
+		}
+	}
+	return nil
+}
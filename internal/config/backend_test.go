@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackendConfig_Defaults(t *testing.T) {
+	var b *BackendConfig
+	if b.GetFile() != DefaultBackendFile || b.GetConfigDir() != DefaultBackendConfigDir || b.GetEnvironments() != nil {
+		t.Errorf("unexpected defaults: %q, %q, %v", b.GetFile(), b.GetConfigDir(), b.GetEnvironments())
+	}
+}
+
+func TestLoad_Backend(t *testing.T) {
+	azurerm := "backend:\n  type: azurerm\n  config:\n    resource_group_name: rg\n    storage_account_name: st\n    container_name: tfstate\n    key: \"{path}.tfstate\"\n"
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", azurerm, ""},
+		{"environments", azurerm + "  environments: [dev, prod]\n  file: state.tf\n", ""},
+		{"missing type", "backend:\n  config:\n    bucket: b\n", "type is required"},
+		{"unknown type", "backend:\n  type: consul\n", "invalid type 'consul': must be 'azurerm', 'gcs', or 's3'"},
+		{"missing settings", "backend:\n  type: s3\n  config:\n    bucket: b\n", "s3 backend needs key, region"},
+		{"unknown placeholder", "backend:\n  type: gcs\n  config:\n    bucket: \"{team}\"\n", "bucket has unknown placeholder {team}"},
+		{"nested setting", "backend:\n  type: gcs\n  config:\n    bucket: b\n    encryption:\n      key: k\n", "encryption must be a string, number, or boolean"},
+		{"duplicate environment", azurerm + "  environments: [dev, dev]\n", "duplicate environment 'dev'"},
+		{"environment path", azurerm + "  environments: [../dev]\n", "invalid environment '../dev'"},
+		{"file outside project", azurerm + "  file: ../backend.tf\n", "invalid path '../backend.tf'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+				t.Fatalf("failed to create .git directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create config file: %v", err)
+			}
+
+			_, err := Load(tmpDir, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,20 +2,35 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/TechnicallyJoe/terraform-motf/internal/checks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+	"github.com/TechnicallyJoe/terraform-motf/internal/lint"
+	"github.com/TechnicallyJoe/terraform-motf/internal/release"
+	"github.com/TechnicallyJoe/terraform-motf/internal/security"
 	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tfvars"
+	"github.com/TechnicallyJoe/terraform-motf/internal/versions"
 	"gopkg.in/yaml.v3"
 )
 
+// BinaryAuto is the binary value that runs terraform, or tofu when only
+// tofu is installed
+const BinaryAuto = "auto"
+
 // validBinaryNames is the single source of truth for allowed binary values.
-var validBinaryNames = []string{"terraform", "tofu"}
+var validBinaryNames = []string{"terraform", "tofu", BinaryAuto}
 
 // validTestEngineNames is the single source of truth for allowed test engine values.
-var validTestEngineNames = []string{"terratest", "terraform", "tofu"}
+var validTestEngineNames = []string{"terratest", "terraform", "tofu", "compliance"}
 
 // toSet converts a string slice to a set for O(1) lookups.
 func toSet(values []string) map[string]struct{} {
@@ -65,6 +80,10 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("invalid binary '%s' in config: must be %s", cfg.Binary, quotedJoin(ValidBinaryNames()))
 	}
 
+	if err := versions.Validate(cfg.BinaryVersion); err != nil {
+		return fmt.Errorf("invalid binary_version in config: %w", err)
+	}
+
 	if cfg.Test == nil {
 		cfg.Test = &TestConfig{Engine: "terratest", Args: ""}
 	} else if cfg.Test.Engine == "" {
@@ -75,19 +94,518 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("invalid test engine '%s' in config: must be %s", cfg.Test.Engine, quotedJoin(ValidTestEngineNames()))
 	}
 
+	if cfg.Test.Retries < 0 {
+		return fmt.Errorf("invalid test retries %d in config: must be 0 or more", cfg.Test.Retries)
+	}
+
+	if cfg.Test.Cost < 0 {
+		return fmt.Errorf("invalid test cost %g in config: must be 0 or more", cfg.Test.Cost)
+	}
+
+	if err := validateCompliance(cfg.Test.Compliance); err != nil {
+		return fmt.Errorf("invalid test.compliance in config: %w", err)
+	}
+
+	if err := validateQuarantine(cfg.Test.Quarantine); err != nil {
+		return fmt.Errorf("invalid test quarantine in config: %w", err)
+	}
+
+	if err := validateManagedPaths(cfg.ManagedPaths); err != nil {
+		return fmt.Errorf("invalid managed_paths in config: %w", err)
+	}
+
+	if err := validateVars(cfg.Vars); err != nil {
+		return fmt.Errorf("invalid vars in config: %w", err)
+	}
+
+	if err := validateRoots(cfg.Roots); err != nil {
+		return fmt.Errorf("invalid roots in config: %w", err)
+	}
+
+	if err := cfg.ModuleDirs.validate(); err != nil {
+		return fmt.Errorf("invalid module_dirs in config: %w", err)
+	}
+
+	if err := cfg.Types.validate(); err != nil {
+		return fmt.Errorf("invalid types in config: %w", err)
+	}
+
+	if err := validateIgnorePatterns(cfg.Changed.GetIgnore()); err != nil {
+		return fmt.Errorf("invalid changed.ignore in config: %w", err)
+	}
+
+	if err := validateTimeouts(cfg.Timeouts); err != nil {
+		return fmt.Errorf("invalid timeouts in config: %w", err)
+	}
+
+	if err := cfg.Parallelism.validate(); err != nil {
+		return fmt.Errorf("invalid parallelism in config: %w", err)
+	}
+
+	if err := validateHooks(cfg.Hooks); err != nil {
+		return fmt.Errorf("invalid hooks in config: %w", err)
+	}
+
+	if err := cfg.Checks.validate(); err != nil {
+		return fmt.Errorf("invalid checks in config: %w", err)
+	}
+
+	if err := versions.Validate(cfg.Constraints.GetTerraform()); err != nil {
+		return fmt.Errorf("invalid constraints.terraform in config: %w", err)
+	}
+
+	providers := cfg.Constraints.GetProviders()
+	for _, name := range slices.Sorted(maps.Keys(providers)) {
+		if strings.TrimSpace(providers[name]) == "" {
+			return fmt.Errorf("invalid constraints.providers.%s in config: a version constraint is required", name)
+		}
+		if err := versions.Validate(providers[name]); err != nil {
+			return fmt.Errorf("invalid constraints.providers.%s in config: %w", name, err)
+		}
+	}
+
+	if err := cfg.Lock.validate(); err != nil {
+		return fmt.Errorf("invalid lock in config: %w", err)
+	}
+
+	for _, step := range cfg.Release.GetSkip() {
+		if !slices.Contains(release.Steps, step) {
+			return fmt.Errorf("invalid release.skip step '%s' in config: must be %s", step, quotedJoin(release.Steps))
+		}
+	}
+
+	if err := tasks.ValidateTasks(cfg.Tasks); err != nil {
+		return fmt.Errorf("invalid tasks in config: %w", err)
+	}
+
+	if err := cfg.Watch.validate(cfg.Tasks); err != nil {
+		return fmt.Errorf("invalid watch in config: %w", err)
+	}
+
+	if err := cfg.Backend.validate(); err != nil {
+		return fmt.Errorf("invalid backend in config: %w", err)
+	}
+
+	if err := cfg.Metrics.validate(); err != nil {
+		return fmt.Errorf("invalid metrics in config: %w", err)
+	}
+
+	if cfg.Security != nil && cfg.Security.Scanner != "" {
+		if _, err := security.Lookup(cfg.Security.Scanner); err != nil {
+			return fmt.Errorf("invalid security scanner '%s' in config: must be %s", cfg.Security.Scanner, quotedJoin(security.Names()))
+		}
+	}
+
 	return nil
 }
 
 // TestConfig represents the test configuration section
 type TestConfig struct {
-	Engine string `yaml:"engine"`
-	Args   string `yaml:"args"`
+	Engine     string            `yaml:"engine"`
+	Args       string            `yaml:"args"`
+	Retries    int               `yaml:"retries"`    // Times to rerun a failed module test; a pass on retry marks it flaky
+	Quarantine []QuarantineEntry `yaml:"quarantine"` // Modules whose test failures are reported as warnings
+	Cost       float64           `yaml:"cost"`       // Estimated cloud cost of one test run, for test --max-cost
+	Compliance *ComplianceConfig `yaml:"compliance"` // Behavior test tool of the compliance engine
+	Templates  string            `yaml:"templates"`  // Directory of templates overriding the ones of gen test
+}
+
+// ComplianceConfig configures the compliance test engine, which runs a BDD
+// tool such as terraform-compliance against the plan JSON of a module
+type ComplianceConfig struct {
+	// Command runs the tool, with {plan} replaced by the plan JSON file and
+	// {features} by the feature directory
+	Command  string `yaml:"command"`
+	Features string `yaml:"features"` // Feature file directory, relative to the module
+}
+
+// Compliance engine defaults
+const (
+	DefaultComplianceCommand  = "terraform-compliance -p {plan} -f {features}"
+	DefaultComplianceFeatures = "tests/features"
+)
+
+// GetCommand returns the command of the compliance test tool, defaulting to
+// terraform-compliance.
+func (c *ComplianceConfig) GetCommand() string {
+	if c == nil || c.Command == "" {
+		return DefaultComplianceCommand
+	}
+	return c.Command
+}
+
+// GetFeatures returns the feature file directory relative to the module,
+// defaulting to tests/features.
+func (c *ComplianceConfig) GetFeatures() string {
+	if c == nil || c.Features == "" {
+		return DefaultComplianceFeatures
+	}
+	return c.Features
+}
+
+// GetCost returns the estimated cloud cost of one test run, 0 if unknown.
+func (t *TestConfig) GetCost() float64 {
+	if t == nil {
+		return 0
+	}
+	return t.Cost
+}
+
+// GetTemplates returns the directory of the gen test template overrides, or "" if none
+func (t *TestConfig) GetTemplates() string {
+	if t == nil {
+		return ""
+	}
+	return t.Templates
+}
+
+// validateCompliance checks that the compliance command can be run and that
+// the feature directory stays inside the module.
+func validateCompliance(c *ComplianceConfig) error {
+	if c == nil {
+		return nil
+	}
+	if c.Command != "" && strings.TrimSpace(c.Command) == "" {
+		return fmt.Errorf("command must not be blank")
+	}
+	if f := c.Features; f != "" && (filepath.IsAbs(f) || !filepath.IsLocal(f)) {
+		return fmt.Errorf("features '%s' must be a path inside the module", f)
+	}
+	return nil
+}
+
+// GetRetries returns the number of times a failed module test is rerun.
+func (t *TestConfig) GetRetries() int {
+	if t == nil {
+		return 0
+	}
+	return t.Retries
+}
+
+// SecurityConfig represents the security section
+type SecurityConfig struct {
+	Scanner string `yaml:"scanner"` // trivy, tfsec, or checkov
+	Args    string `yaml:"args"`    // Extra arguments passed to the scanner
+}
+
+// GetScanner returns the configured scanner, defaulting to trivy.
+func (s *SecurityConfig) GetScanner() string {
+	if s == nil || s.Scanner == "" {
+		return security.DefaultScanner
+	}
+	return s.Scanner
+}
+
+// GetArgs returns the extra arguments passed to the scanner.
+func (s *SecurityConfig) GetArgs() string {
+	if s == nil {
+		return ""
+	}
+	return s.Args
+}
+
+// LintConfig represents the lint section
+type LintConfig struct {
+	DescriptionPlaceholder string `yaml:"description_placeholder"` // Inserted by 'motf lint --fix descriptions'
+}
+
+// GetDescriptionPlaceholder returns the description inserted for variables
+// and outputs without one.
+func (l *LintConfig) GetDescriptionPlaceholder() string {
+	if l == nil || l.DescriptionPlaceholder == "" {
+		return lint.DefaultDescriptionPlaceholder
+	}
+	return l.DescriptionPlaceholder
+}
+
+// ReleaseConfig represents the release section
+type ReleaseConfig struct {
+	Skip      []string `yaml:"skip"`      // Release steps that don't run, e.g. changelog
+	Push      bool     `yaml:"push"`      // Push the release commit and tags
+	Remote    string   `yaml:"remote"`    // Remote to push to
+	Changelog string   `yaml:"changelog"` // Changelog file name in each module
+}
+
+// GetSkip returns the release steps that are skipped.
+func (r *ReleaseConfig) GetSkip() []string {
+	if r == nil {
+		return nil
+	}
+	return r.Skip
+}
+
+// GetPush reports whether releases are pushed.
+func (r *ReleaseConfig) GetPush() bool {
+	return r != nil && r.Push
+}
+
+// GetRemote returns the remote releases are pushed to, defaulting to origin.
+func (r *ReleaseConfig) GetRemote() string {
+	if r == nil || r.Remote == "" {
+		return "origin"
+	}
+	return r.Remote
+}
+
+// GetChangelog returns the changelog file name, defaulting to CHANGELOG.md.
+func (r *ReleaseConfig) GetChangelog() string {
+	if r == nil || r.Changelog == "" {
+		return release.DefaultChangelog
+	}
+	return r.Changelog
+}
+
+// SourcesConfig represents the sources section
+type SourcesConfig struct {
+	Registries []string `yaml:"registries"` // Module registry hosts that publish this repository's modules
+}
+
+// GetRegistries returns the registry hosts whose module sources 'motf
+// bump-sources' updates, defaulting to the Spacelift registry.
+func (s *SourcesConfig) GetRegistries() []string {
+	if s == nil || len(s.Registries) == 0 {
+		return []string{"spacelift.io"}
+	}
+	return s.Registries
+}
+
+// PlansConfig represents the plans section, configuring saved plan files
+type PlansConfig struct {
+	Dir string `yaml:"dir"` // Directory plan files are saved to, relative to the root
+}
+
+// DefaultPlansDir is the directory plan files are saved to by default
+const DefaultPlansDir = ".motf/plans"
+
+// GetDir returns the directory 'motf plan --save' saves plan files to,
+// relative to the root, defaulting to .motf/plans.
+func (p *PlansConfig) GetDir() string {
+	if p == nil || p.Dir == "" {
+		return DefaultPlansDir
+	}
+	return p.Dir
+}
+
+// ConstraintsConfig represents the constraints section, the version
+// constraints every module must be compatible with
+type ConstraintsConfig struct {
+	Terraform string            `yaml:"terraform"` // Terraform/OpenTofu version constraint, e.g. ">= 1.6"
+	Providers map[string]string `yaml:"providers"` // Version constraint per provider local name, synced by 'motf providers sync'
+}
+
+// GetTerraform returns the repository-wide terraform version constraint, or
+// empty for none.
+func (c *ConstraintsConfig) GetTerraform() string {
+	if c == nil {
+		return ""
+	}
+	return c.Terraform
+}
+
+// GetProviders returns the version constraint of each provider, by local
+// name, that 'motf providers sync' writes to the modules.
+func (c *ConstraintsConfig) GetProviders() map[string]string {
+	if c == nil {
+		return nil
+	}
+	return c.Providers
+}
+
+// PolicyConfig represents the policy section, configuring the Rego policies
+// 'motf policy eval' evaluates
+type PolicyConfig struct {
+	Paths []string `yaml:"paths"` // Policy files or directories, relative to the config file
+	Query string   `yaml:"query"` // Rule whose results are violations
+	Plan  bool     `yaml:"plan"`  // Include the plan JSON in the input, like --plan
+}
+
+// DefaultPolicyQuery is the rule whose results are policy violations by default
+const DefaultPolicyQuery = "data.motf.deny"
+
+// GetPaths returns the policy files and directories.
+func (p *PolicyConfig) GetPaths() []string {
+	if p == nil {
+		return nil
+	}
+	return p.Paths
+}
+
+// GetQuery returns the rule whose results are violations, defaulting to
+// data.motf.deny.
+func (p *PolicyConfig) GetQuery() string {
+	if p == nil || p.Query == "" {
+		return DefaultPolicyQuery
+	}
+	return p.Query
+}
+
+// GetPlan reports whether the plan JSON is part of the policy input.
+func (p *PolicyConfig) GetPlan() bool {
+	return p != nil && p.Plan
+}
+
+// WatchConfig represents the watch section, configuring what 'motf watch'
+// runs on a module when its files change
+type WatchConfig struct {
+	Command  string `yaml:"command"`  // fmt, validate, or task
+	Task     string `yaml:"task"`     // Task to run with command: task
+	Debounce string `yaml:"debounce"` // Quiet period after a change before running, e.g. 500ms
+}
+
+// WatchCommands are the commands 'motf watch' can run
+var WatchCommands = []string{"fmt", "validate", "task"}
+
+// Watch defaults
+const (
+	DefaultWatchCommand  = "validate"
+	DefaultWatchDebounce = 300 * time.Millisecond
+)
+
+// GetCommand returns the command to run on changes, defaulting to validate,
+// or task when only a task is set.
+func (w *WatchConfig) GetCommand() string {
+	switch {
+	case w == nil:
+		return DefaultWatchCommand
+	case w.Command != "":
+		return w.Command
+	case w.Task != "":
+		return "task"
+	}
+	return DefaultWatchCommand
+}
+
+// GetTask returns the task to run with command: task.
+func (w *WatchConfig) GetTask() string {
+	if w == nil {
+		return ""
+	}
+	return w.Task
+}
+
+// GetDebounce returns the quiet period after a change before running,
+// defaulting to 300ms.
+func (w *WatchConfig) GetDebounce() time.Duration {
+	if w == nil || w.Debounce == "" {
+		return DefaultWatchDebounce
+	}
+	d, _ := time.ParseDuration(w.Debounce)
+	return d
+}
+
+// validate checks the command, its task, and the debounce duration.
+func (w *WatchConfig) validate(defined map[string]*tasks.TaskConfig) error {
+	if w == nil {
+		return nil
+	}
+	if w.Command != "" && !slices.Contains(WatchCommands, w.Command) {
+		return fmt.Errorf("invalid command '%s': must be %s", w.Command, quotedJoin(WatchCommands))
+	}
+	if w.GetCommand() == "task" {
+		if w.Task == "" {
+			return fmt.Errorf("command 'task' needs a task")
+		}
+		if _, ok := defined[w.Task]; !ok {
+			return fmt.Errorf("unknown task '%s'", w.Task)
+		}
+	} else if w.Task != "" {
+		return fmt.Errorf("task is only used with command 'task', not '%s'", w.Command)
+	}
+	if w.Debounce != "" {
+		if d, err := time.ParseDuration(w.Debounce); err != nil || d <= 0 {
+			return fmt.Errorf("invalid debounce '%s', expected e.g. 500ms or 1s", w.Debounce)
+		}
+	}
+	return nil
+}
+
+// ChecksConfig represents the checks section, selecting the rules 'motf
+// check' runs per module type. A type without a list gets the default rules;
+// an empty list disables the checks for that type.
+type ChecksConfig struct {
+	Component []string `yaml:"component"`
+	Base      []string `yaml:"base"`
+	Project   []string `yaml:"project"`
+}
+
+// GetRules returns the rules checked for modules of a type.
+func (c *ChecksConfig) GetRules(moduleType string) []string {
+	var selected []string
+	if c != nil {
+		switch moduleType {
+		case "component":
+			selected = c.Component
+		case "base":
+			selected = c.Base
+		case "project":
+			selected = c.Project
+		}
+	}
+	if selected == nil {
+		return checks.Defaults(moduleType)
+	}
+	return selected
+}
+
+// validate checks that every selected rule exists.
+func (c *ChecksConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, rules := range [][]string{c.Component, c.Base, c.Project} {
+		for _, rule := range rules {
+			if !checks.IsValid(rule) {
+				return fmt.Errorf("unknown rule '%s': must be %s", rule, quotedJoin(checks.Names()))
+			}
+		}
+	}
+	return nil
+}
+
+// ChangedConfig represents the changed section, configuring --changed
+type ChangedConfig struct {
+	Ignore []string `yaml:"ignore"` // Gitignore-style patterns of files that don't mark their module as changed
+}
+
+// GetIgnore returns the patterns of files ignored by change detection.
+func (c *ChangedConfig) GetIgnore() []string {
+	if c == nil {
+		return nil
+	}
+	return c.Ignore
+}
+
+// validateVars checks the variable file layers.
+func validateVars(patterns []string) error {
+	for i, p := range patterns {
+		if err := tfvars.ValidatePattern(p); err != nil {
+			return fmt.Errorf("entry %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// validateIgnorePatterns checks that no ignore pattern is empty.
+func validateIgnorePatterns(patterns []string) error {
+	for i, p := range patterns {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("entry %d: pattern is required", i+1)
+		}
+	}
+	return nil
 }
 
 type ParallelismConfig struct {
-	MaxJobs int `yaml:"max_jobs"`
+	MaxJobs    int    `yaml:"max_jobs"`
+	LogDir     string `yaml:"log_dir"`
+	Timeout    string `yaml:"timeout"`     // Maximum duration of each module in a multi-module run, e.g. 30m
+	Retries    int    `yaml:"retries"`     // Times to rerun a failed module of init, val, plan, task, exec and lock runs
+	RetryDelay string `yaml:"retry_delay"` // Wait before the first retry, doubled for each next one
 }
 
+// DefaultRetryDelay is the wait before the first retry of a failed module
+const DefaultRetryDelay = 10 * time.Second
+
 // GetMaxJobs returns the maximum number of parallel jobs to run.
 // If MaxJobs is not set or is less than or equal to zero, it defaults to the number of CPU cores.
 func (p *ParallelismConfig) GetMaxJobs() int {
@@ -97,14 +615,165 @@ func (p *ParallelismConfig) GetMaxJobs() int {
 	return p.MaxJobs
 }
 
+// GetLogDir returns the directory per-module log files are written to,
+// or an empty string if logging to files is disabled.
+func (p *ParallelismConfig) GetLogDir() string {
+	if p == nil {
+		return ""
+	}
+	return p.LogDir
+}
+
+// GetTimeout returns how long each module of a multi-module run may take,
+// or 0 for no limit.
+func (p *ParallelismConfig) GetTimeout() time.Duration {
+	if p == nil {
+		return 0
+	}
+	// The timeout is validated when the config is loaded
+	d, _ := time.ParseDuration(p.Timeout)
+	return d
+}
+
+// GetRetries returns the number of times a failed module is rerun.
+func (p *ParallelismConfig) GetRetries() int {
+	if p == nil {
+		return 0
+	}
+	return p.Retries
+}
+
+// GetRetryDelay returns the wait before the first retry of a failed module,
+// defaulting to DefaultRetryDelay.
+func (p *ParallelismConfig) GetRetryDelay() time.Duration {
+	if p == nil || p.RetryDelay == "" {
+		return DefaultRetryDelay
+	}
+	// The delay is validated when the config is loaded
+	d, _ := time.ParseDuration(p.RetryDelay)
+	return d
+}
+
+// validate checks that the timeout and retry delay, if set, are positive Go
+// durations and that retries isn't negative.
+func (p *ParallelismConfig) validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.Timeout != "" {
+		if d, err := time.ParseDuration(p.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("timeout: invalid duration '%s', expected e.g. 15m or 1h30m", p.Timeout)
+		}
+	}
+	if p.Retries < 0 {
+		return fmt.Errorf("retries: %d must be 0 or more", p.Retries)
+	}
+	if p.RetryDelay != "" {
+		if d, err := time.ParseDuration(p.RetryDelay); err != nil || d <= 0 {
+			return fmt.Errorf("retry_delay: invalid duration '%s', expected e.g. 10s or 1m", p.RetryDelay)
+		}
+	}
+	return nil
+}
+
+// resolveConfigPaths resolves relative paths in the config against the config file directory.
+func resolveConfigPaths(cfg *Config, configDir string) {
+	if cfg.Parallelism != nil && cfg.Parallelism.LogDir != "" && !filepath.IsAbs(cfg.Parallelism.LogDir) {
+		cfg.Parallelism.LogDir = filepath.Join(configDir, cfg.Parallelism.LogDir)
+	}
+	if cfg.Metrics != nil && cfg.Metrics.Path != "" && !filepath.IsAbs(cfg.Metrics.Path) {
+		cfg.Metrics.Path = filepath.Join(configDir, cfg.Metrics.Path)
+	}
+	if cfg.Test != nil && cfg.Test.Templates != "" && !filepath.IsAbs(cfg.Test.Templates) {
+		cfg.Test.Templates = filepath.Join(configDir, cfg.Test.Templates)
+	}
+	if cfg.Policy != nil {
+		for i, p := range cfg.Policy.Paths {
+			if !filepath.IsAbs(p) {
+				cfg.Policy.Paths[i] = filepath.Join(configDir, p)
+			}
+		}
+	}
+}
+
+// envRefPattern matches ${VAR} references in env values
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandEnv replaces ${VAR} references in value with values from getenv.
+// Unset variables expand to an empty string; a bare $ is left untouched.
+func ExpandEnv(value string, getenv func(string) string) string {
+	return envRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		return getenv(ref[2 : len(ref)-1])
+	})
+}
+
+// expandConfigEnv expands ${VAR} references in global and per-task env values in place.
+func expandConfigEnv(env map[string]string, taskConfigs map[string]*tasks.TaskConfig, getenv func(string) string) {
+	for key, value := range env {
+		env[key] = ExpandEnv(value, getenv)
+	}
+	for _, task := range taskConfigs {
+		if task == nil {
+			continue
+		}
+		for key, value := range task.Env {
+			task.Env[key] = ExpandEnv(value, getenv)
+		}
+	}
+}
+
+// ConfigFile is the name of the repository config file
+const ConfigFile = ".motf.yml"
+
 // Config represents the .motf.yml configuration file
 type Config struct {
-	Root        string                       `yaml:"root"`
-	Binary      string                       `yaml:"binary"`
-	Test        *TestConfig                  `yaml:"test"`
-	Tasks       map[string]*tasks.TaskConfig `yaml:"tasks"`
-	Parallelism *ParallelismConfig           `yaml:"parallelism"`
-	ConfigPath  string                       `yaml:"-"` // Path to the config file, if found
+	Root          string                       `yaml:"root"`
+	Binary        string                       `yaml:"binary"`
+	BinaryVersion string                       `yaml:"binary_version"` // Version constraint on the binary, e.g. "~> 1.9.0"
+	Test          *TestConfig                  `yaml:"test"`
+	Tasks         map[string]*tasks.TaskConfig `yaml:"tasks"`
+	Parallelism   *ParallelismConfig           `yaml:"parallelism"`
+	Security      *SecurityConfig              `yaml:"security"`
+	Changed       *ChangedConfig               `yaml:"changed"`
+	Lint          *LintConfig                  `yaml:"lint"`
+	Lock          *LockConfig                  `yaml:"lock"`
+	Release       *ReleaseConfig               `yaml:"release"`
+	Sources       *SourcesConfig               `yaml:"sources"`
+	Plans         *PlansConfig                 `yaml:"plans"`
+	Constraints   *ConstraintsConfig           `yaml:"constraints"`
+	Checks        *ChecksConfig                `yaml:"checks"`
+	Policy        *PolicyConfig                `yaml:"policy"`
+	Watch         *WatchConfig                 `yaml:"watch"`
+	Backend       *BackendConfig               `yaml:"backend"`
+	Metrics       *MetricsConfig               `yaml:"metrics"`
+	Env           map[string]string            `yaml:"env"`      // Extra environment for terraform/tofu and task subprocesses
+	Timeouts      map[string]string            `yaml:"timeouts"` // Maximum duration per command (or default), e.g. plan: 15m
+	Hooks         map[string]string            `yaml:"hooks"`    // Shell commands run before or after commands, e.g. pre_plan
+	ConfigPath    string                       `yaml:"-"`        // Path to the config file, if found
+
+	// Roots are directories under Root, each with its own module
+	// directories, for repositories with several monorepos. Empty means
+	// modules are discovered in Root itself.
+	Roots []string `yaml:"roots"`
+
+	// ModuleDirs are the directories under Root (or each of Roots) modules are discovered in,
+	// with the type of their modules. Empty means DefaultModuleDirs.
+	ModuleDirs ModuleDirs `yaml:"module_dirs"`
+
+	// Types overrides the type of the modules matching a path pattern; the
+	// first matching pattern wins.
+	Types TypeRules `yaml:"types"`
+
+	// ManagedPaths limits motf to these paths relative to Root, so it can
+	// coexist with other tooling during a migration. Empty means everything.
+	ManagedPaths []string `yaml:"managed_paths"`
+
+	// Vars are the variable files layered by 'motf vars render', relative to
+	// each module and lowest precedence first. They may contain {env} and
+	// {region} placeholders.
+	Vars []string `yaml:"vars"`
+
+	ModuleConfigPath string `yaml:"-"` // Path to the merged .motf.module.yml, if any
 }
 
 // DefaultConfig returns a Config with default values
@@ -122,15 +791,13 @@ func DefaultConfig() *Config {
 	}
 }
 
-// isGitRoot checks if the given directory is the root of a Git repository
+// isGitRoot checks if the given directory is the root of a Git repository.
+// .git can be a directory (regular repo) or a file pointing to the git
+// directory (worktree/submodule); a .git file that doesn't point to one
+// doesn't make dir a root.
 func isGitRoot(dir string) bool {
-	gitPath := filepath.Join(dir, ".git")
-	info, err := os.Stat(gitPath)
-	if err != nil {
-		return false
-	}
-	// .git can be a directory (regular repo) or a file (worktree/submodule)
-	return info.IsDir() || info.Mode().IsRegular()
+	_, ok := git.GitDir(dir)
+	return ok
 }
 
 // findGitRoot finds the root of the Git repository starting from startDir
@@ -162,56 +829,68 @@ func Load(startDir string, configPath string) (*Config, error) {
 		return loadConfigFile(cfg, configPath, gitRoot)
 	}
 
-	// Walk up the directory tree looking for .motf.yml
-	dir := startDir
-	for {
-		configPath := filepath.Join(dir, ".motf.yml")
-		if _, err := os.Stat(configPath); err == nil {
-			// Found config file
-			data, err := os.ReadFile(configPath) //nolint:gosec // configPath is constructed from known directory traversal
-			if err != nil {
-				return nil, fmt.Errorf("failed to read config file: %w", err)
-			}
+	configPath = FindFile(startDir)
+	if configPath == "" {
+		// No config file found, set Root to git root and return defaults
+		cfg.Root = gitRoot
+		return cfg, nil
+	}
 
-			if err := yaml.Unmarshal(data, cfg); err != nil {
-				return nil, fmt.Errorf("failed to parse config file: %w", err)
-			}
+	data, err := os.ReadFile(configPath) //nolint:gosec // configPath is constructed from known directory traversal
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
 
-			if err := validateConfig(cfg); err != nil {
-				return nil, err
-			}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
 
-			// Store the config file path
-			cfg.ConfigPath = configPath
+	// Store the config file path
+	cfg.ConfigPath = configPath
 
-			// If Root is not set in config, default to git root
-			// If Root is set and is relative, resolve it relative to the config file directory
-			if cfg.Root == "" {
-				cfg.Root = gitRoot
-			} else if !filepath.IsAbs(cfg.Root) {
-				cfg.Root = filepath.Join(dir, cfg.Root)
-			}
+	// If Root is not set in config, default to git root
+	// If Root is set and is relative, resolve it relative to the config file directory
+	dir := filepath.Dir(configPath)
+	if cfg.Root == "" {
+		cfg.Root = gitRoot
+	} else if !filepath.IsAbs(cfg.Root) {
+		cfg.Root = filepath.Join(dir, cfg.Root)
+	}
+	resolveConfigPaths(cfg, dir)
+	expandConfigEnv(cfg.Env, cfg.Tasks, os.Getenv)
+	cfg.Metrics.expandLabels(os.Getenv)
+
+	return cfg, nil
+}
 
-			return cfg, nil
+// FindFile returns the path of the .motf.yml that applies to startDir,
+// searching from startDir up to the Git repository root, or empty if there
+// is none.
+func FindFile(startDir string) string {
+	dir := startDir
+	for {
+		configPath := filepath.Join(dir, ConfigFile)
+		if _, err := os.Stat(configPath); err == nil {
+			return configPath
 		}
 
 		// Stop if we've reached the Git repository root
 		if isGitRoot(dir) {
-			break
+			return ""
 		}
 
 		// Move up one directory
 		parent := filepath.Dir(dir)
 		if parent == dir {
 			// Reached filesystem root, no config file found
-			break
+			return ""
 		}
 		dir = parent
 	}
-
-	// No config file found, set Root to git root and return defaults
-	cfg.Root = gitRoot
-	return cfg, nil
 }
 
 // loadConfigFile loads and validates a config file at the given path.
@@ -264,6 +943,9 @@ func loadConfigFile(cfg *Config, configPath string, gitRoot string) (*Config, er
 	} else if !filepath.IsAbs(cfg.Root) {
 		cfg.Root = filepath.Clean(filepath.Join(dir, cfg.Root))
 	}
+	resolveConfigPaths(cfg, dir)
+	expandConfigEnv(cfg.Env, cfg.Tasks, os.Getenv)
+	cfg.Metrics.expandLabels(os.Getenv)
 
 	return cfg, nil
 }
@@ -4,7 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoad_WithConfigFileWithoutValues(t *testing.T) {
@@ -225,6 +227,38 @@ root: /outside/root
 	}
 }
 
+func TestLoad_SubmoduleCheckout(t *testing.T) {
+	// A submodule checkout is its own repository: its .git file points to
+	// the git directory in the parent repository, and the parent's config
+	// isn't used
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte("binary: tofu\n"), 0644); err != nil {
+		t.Fatalf("failed to create parent config file: %v", err)
+	}
+	moduleGitDir := filepath.Join(tmpDir, ".git", "modules", "infra")
+	if err := os.MkdirAll(moduleGitDir, 0755); err != nil {
+		t.Fatalf("failed to create submodule git directory: %v", err)
+	}
+	submodule := filepath.Join(tmpDir, "infra")
+	if err := os.MkdirAll(filepath.Join(submodule, "components"), 0755); err != nil {
+		t.Fatalf("failed to create submodule: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(submodule, ".git"), []byte("gitdir: ../.git/modules/infra\n"), 0644); err != nil {
+		t.Fatalf("failed to create .git file: %v", err)
+	}
+
+	cfg, err := Load(filepath.Join(submodule, "components"), "")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if cfg.Binary != "terraform" {
+		t.Errorf("expected Binary to be 'terraform' (default), got '%s'", cfg.Binary)
+	}
+	if cfg.Root != submodule {
+		t.Errorf("expected Root to be '%s' (submodule root), got '%s'", submodule, cfg.Root)
+	}
+}
+
 func TestLoad_ConfigInSubdirectory(t *testing.T) {
 	// Create a temp directory structure:
 	// tmpDir/
@@ -289,8 +323,12 @@ func TestIsGitRoot(t *testing.T) {
 
 	t.Run("with .git file (worktree)", func(t *testing.T) {
 		tmpDir := t.TempDir()
+		worktreeGitDir := filepath.Join(t.TempDir(), ".git", "worktrees", "feature")
+		if err := os.MkdirAll(worktreeGitDir, 0755); err != nil {
+			t.Fatalf("failed to create worktree git directory: %v", err)
+		}
 		gitFile := filepath.Join(tmpDir, ".git")
-		if err := os.WriteFile(gitFile, []byte("gitdir: /some/path"), 0644); err != nil {
+		if err := os.WriteFile(gitFile, []byte("gitdir: "+worktreeGitDir+"\n"), 0644); err != nil {
 			t.Fatalf("failed to create .git file: %v", err)
 		}
 
@@ -299,6 +337,18 @@ func TestIsGitRoot(t *testing.T) {
 		}
 	})
 
+	t.Run("with .git file pointing nowhere", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		gitFile := filepath.Join(tmpDir, ".git")
+		if err := os.WriteFile(gitFile, []byte("gitdir: /some/path"), 0644); err != nil {
+			t.Fatalf("failed to create .git file: %v", err)
+		}
+
+		if isGitRoot(tmpDir) {
+			t.Error("expected isGitRoot to return false for a .git file without a git directory")
+		}
+	})
+
 	t.Run("without .git", func(t *testing.T) {
 		tmpDir := t.TempDir()
 
@@ -388,6 +438,239 @@ func TestLoad_InvalidTestEngine(t *testing.T) {
 	}
 }
 
+func TestLoad_NegativeTestRetries(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	configContent := `test:
+  retries: -1
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	_, err := Load(tmpDir, "")
+	if err == nil || !strings.Contains(err.Error(), "invalid test retries") {
+		t.Errorf("expected invalid test retries error, got %v", err)
+	}
+}
+
+func TestLoad_SecurityConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create .git directory
+	gitDir := filepath.Join(tmpDir, ".git")
+	if err := os.Mkdir(gitDir, 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	configContent := `security:
+  scanner: checkov
+  args: "--skip-check CKV_AWS_18"
+`
+	configPath := filepath.Join(tmpDir, ".motf.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Security.GetScanner() != "checkov" {
+		t.Errorf("expected scanner 'checkov', got '%s'", cfg.Security.GetScanner())
+	}
+	if cfg.Security.Args != "--skip-check CKV_AWS_18" {
+		t.Errorf("expected scanner args, got '%s'", cfg.Security.Args)
+	}
+}
+
+func TestLoad_InvalidSecurityScanner(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create .git directory
+	gitDir := filepath.Join(tmpDir, ".git")
+	if err := os.Mkdir(gitDir, 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	configContent := `security:
+  scanner: snyk
+`
+	configPath := filepath.Join(tmpDir, ".motf.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	_, err := Load(tmpDir, "")
+	if err == nil {
+		t.Error("expected error for invalid security scanner, got nil")
+	}
+}
+
+func TestSecurityConfig_GetScannerDefault(t *testing.T) {
+	var s *SecurityConfig
+	if got := s.GetScanner(); got != "trivy" {
+		t.Errorf("expected default scanner 'trivy', got '%s'", got)
+	}
+}
+
+func TestLoad_LintConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create .git directory
+	gitDir := filepath.Join(tmpDir, ".git")
+	if err := os.Mkdir(gitDir, 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	configContent := `lint:
+  description_placeholder: "TODO(platform): describe"
+`
+	configPath := filepath.Join(tmpDir, ".motf.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := cfg.Lint.GetDescriptionPlaceholder(); got != "TODO(platform): describe" {
+		t.Errorf("expected configured placeholder, got '%s'", got)
+	}
+}
+
+func TestLintConfig_GetDescriptionPlaceholderDefault(t *testing.T) {
+	var l *LintConfig
+	if got := l.GetDescriptionPlaceholder(); got != "TODO: add a description" {
+		t.Errorf("expected default placeholder, got '%s'", got)
+	}
+}
+
+func TestLoad_ReleaseConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create .git directory
+	gitDir := filepath.Join(tmpDir, ".git")
+	if err := os.Mkdir(gitDir, 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	configContent := `release:
+  skip: [changelog]
+  push: true
+  remote: upstream
+  changelog: CHANGES.md
+`
+	configPath := filepath.Join(tmpDir, ".motf.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := cfg.Release.GetSkip(); len(got) != 1 || got[0] != "changelog" {
+		t.Errorf("expected skip [changelog], got %v", got)
+	}
+	if !cfg.Release.GetPush() {
+		t.Error("expected push to be enabled")
+	}
+	if got := cfg.Release.GetRemote(); got != "upstream" {
+		t.Errorf("expected remote 'upstream', got '%s'", got)
+	}
+	if got := cfg.Release.GetChangelog(); got != "CHANGES.md" {
+		t.Errorf("expected changelog 'CHANGES.md', got '%s'", got)
+	}
+}
+
+func TestLoad_InvalidReleaseSkip(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create .git directory
+	gitDir := filepath.Join(tmpDir, ".git")
+	if err := os.Mkdir(gitDir, 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	configPath := filepath.Join(tmpDir, ".motf.yml")
+	if err := os.WriteFile(configPath, []byte("release:\n  skip: [deploy]\n"), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	_, err := Load(tmpDir, "")
+	if err == nil || !strings.Contains(err.Error(), "deploy") {
+		t.Fatalf("expected invalid release.skip error, got %v", err)
+	}
+}
+
+func TestReleaseConfig_Defaults(t *testing.T) {
+	var r *ReleaseConfig
+	if got := r.GetRemote(); got != "origin" {
+		t.Errorf("expected default remote 'origin', got '%s'", got)
+	}
+	if got := r.GetChangelog(); got != "CHANGELOG.md" {
+		t.Errorf("expected default changelog 'CHANGELOG.md', got '%s'", got)
+	}
+	if r.GetPush() || len(r.GetSkip()) != 0 {
+		t.Error("expected no push and no skipped steps by default")
+	}
+}
+
+func TestPlansConfig_GetDir(t *testing.T) {
+	var p *PlansConfig
+	if got := p.GetDir(); got != ".motf/plans" {
+		t.Errorf("expected default dir '.motf/plans', got '%s'", got)
+	}
+	p = &PlansConfig{Dir: "artifacts/plans"}
+	if got := p.GetDir(); got != "artifacts/plans" {
+		t.Errorf("expected configured dir, got '%s'", got)
+	}
+}
+
+func TestSourcesConfig_GetRegistries(t *testing.T) {
+	var s *SourcesConfig
+	if got := s.GetRegistries(); len(got) != 1 || got[0] != "spacelift.io" {
+		t.Errorf("expected default registries [spacelift.io], got %v", got)
+	}
+	s = &SourcesConfig{Registries: []string{"app.terraform.io"}}
+	if got := s.GetRegistries(); len(got) != 1 || got[0] != "app.terraform.io" {
+		t.Errorf("expected configured registries, got %v", got)
+	}
+}
+
+func TestLoad_TaskDependencyCycle(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create .git directory
+	gitDir := filepath.Join(tmpDir, ".git")
+	if err := os.Mkdir(gitDir, 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	configContent := `tasks:
+  fmt:
+    command: terraform fmt
+    depends_on: [docs]
+  docs:
+    command: terraform-docs .
+    depends_on: [fmt]
+`
+	configPath := filepath.Join(tmpDir, ".motf.yml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	_, err := Load(tmpDir, "")
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected dependency cycle error, got %v", err)
+	}
+}
+
 func TestLoad_TestConfigDefaults(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -414,6 +697,17 @@ func TestLoad_TestConfigDefaults(t *testing.T) {
 	}
 }
 
+func TestComplianceConfig_Defaults(t *testing.T) {
+	var c *ComplianceConfig
+	if c.GetCommand() != DefaultComplianceCommand || c.GetFeatures() != DefaultComplianceFeatures {
+		t.Errorf("nil compliance config = %q, %q", c.GetCommand(), c.GetFeatures())
+	}
+	c = &ComplianceConfig{Command: "behave {features}", Features: "features"}
+	if c.GetCommand() != "behave {features}" || c.GetFeatures() != "features" {
+		t.Errorf("compliance config = %q, %q", c.GetCommand(), c.GetFeatures())
+	}
+}
+
 func TestLoad_TestConfigFromFile(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -619,3 +913,418 @@ func TestLoad_ExplicitConfigPath_RelativePath(t *testing.T) {
 		t.Errorf("expected ConfigPath to be absolute, got '%s'", cfg.ConfigPath)
 	}
 }
+
+func TestLoad_ParallelismLogDirRelativeToConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	configContent := `parallelism:
+  log_dir: .motf/logs
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	expected := filepath.Join(tmpDir, ".motf", "logs")
+	if cfg.Parallelism.GetLogDir() != expected {
+		t.Errorf("expected Parallelism.LogDir to be '%s', got '%s'", expected, cfg.Parallelism.GetLogDir())
+	}
+}
+
+func TestLoad_TestTemplatesRelativeToConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+
+	configContent := `test:
+  templates: .motf/templates
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	expected := filepath.Join(tmpDir, ".motf", "templates")
+	if cfg.Test.GetTemplates() != expected {
+		t.Errorf("expected Test.Templates to be '%s', got '%s'", expected, cfg.Test.GetTemplates())
+	}
+}
+
+func TestParallelismConfig_GetLogDir_Nil(t *testing.T) {
+	var p *ParallelismConfig
+	if p.GetLogDir() != "" {
+		t.Errorf("expected empty log dir for nil config, got '%s'", p.GetLogDir())
+	}
+}
+
+func TestParallelismConfig_GetTimeout(t *testing.T) {
+	var p *ParallelismConfig
+	if p.GetTimeout() != 0 {
+		t.Errorf("expected no timeout for nil config, got %s", p.GetTimeout())
+	}
+	p = &ParallelismConfig{Timeout: "1h30m"}
+	if p.GetTimeout() != 90*time.Minute {
+		t.Errorf("expected timeout 1h30m, got %s", p.GetTimeout())
+	}
+}
+
+func TestLoad_InvalidParallelismTimeout(t *testing.T) {
+	for _, timeout := range []string{"soon", "-5m", "0s"} {
+		tmpDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte("parallelism:\n  timeout: "+timeout+"\n"), 0644); err != nil {
+			t.Fatalf("failed to create config file: %v", err)
+		}
+
+		_, err := Load(tmpDir, "")
+		if err == nil || !strings.Contains(err.Error(), "invalid parallelism in config: timeout: invalid duration") {
+			t.Errorf("timeout %q: expected invalid duration error, got %v", timeout, err)
+		}
+	}
+}
+
+func TestParallelismConfig_GetRetries(t *testing.T) {
+	var p *ParallelismConfig
+	if p.GetRetries() != 0 || p.GetRetryDelay() != DefaultRetryDelay {
+		t.Errorf("expected no retries after %s for nil config, got %d after %s", DefaultRetryDelay, p.GetRetries(), p.GetRetryDelay())
+	}
+	p = &ParallelismConfig{Retries: 2, RetryDelay: "30s"}
+	if p.GetRetries() != 2 || p.GetRetryDelay() != 30*time.Second {
+		t.Errorf("expected 2 retries after 30s, got %d after %s", p.GetRetries(), p.GetRetryDelay())
+	}
+}
+
+func TestLoad_InvalidParallelismRetries(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{"retries: -1", "invalid parallelism in config: retries: -1 must be 0 or more"},
+		{"retry_delay: later", "invalid parallelism in config: retry_delay: invalid duration 'later'"},
+		{"retry_delay: 0s", "invalid parallelism in config: retry_delay: invalid duration '0s'"},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte("parallelism:\n  "+tt.config+"\n"), 0644); err != nil {
+			t.Fatalf("failed to create config file: %v", err)
+		}
+
+		_, err := Load(tmpDir, "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.config, tt.want, err)
+		}
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	getenv := func(key string) string {
+		return map[string]string{"ARM_CLIENT_ID": "abc", "HOME": "/home/ci"}[key]
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"${ARM_CLIENT_ID}", "abc"},
+		{"prefix-${ARM_CLIENT_ID}-${HOME}", "prefix-abc-/home/ci"},
+		{"${UNSET}", ""},
+		{"$HOME", "$HOME"}, // only ${VAR} is expanded
+		{"p@$$word", "p@$$word"},
+		{"plain", "plain"},
+	}
+
+	for _, tt := range tests {
+		if got := ExpandEnv(tt.value, getenv); got != tt.want {
+			t.Errorf("ExpandEnv(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_EnvSection(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+	t.Setenv("MOTF_TEST_CLIENT_ID", "client-123")
+
+	configContent := `env:
+  ARM_CLIENT_ID: ${MOTF_TEST_CLIENT_ID}
+  ARM_USE_OIDC: "true"
+tasks:
+  lint:
+    command: tflint
+    env:
+      TFLINT_LOG: debug
+      CLIENT: id-${MOTF_TEST_CLIENT_ID}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	if cfg.Env["ARM_CLIENT_ID"] != "client-123" {
+		t.Errorf("expected ARM_CLIENT_ID to be expanded, got '%s'", cfg.Env["ARM_CLIENT_ID"])
+	}
+	if cfg.Env["ARM_USE_OIDC"] != "true" {
+		t.Errorf("expected ARM_USE_OIDC to be 'true', got '%s'", cfg.Env["ARM_USE_OIDC"])
+	}
+	if cfg.Tasks["lint"].Env["CLIENT"] != "id-client-123" {
+		t.Errorf("expected task env to be expanded, got '%s'", cfg.Tasks["lint"].Env["CLIENT"])
+	}
+	if cfg.Tasks["lint"].Env["TFLINT_LOG"] != "debug" {
+		t.Errorf("expected task env TFLINT_LOG to be 'debug', got '%s'", cfg.Tasks["lint"].Env["TFLINT_LOG"])
+	}
+}
+
+func TestLoad_ChangedIgnore(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: "changed:\n  ignore:\n    - \"**/*.md\"\n    - \"**/docs/**\"\n"},
+		{name: "empty entry", content: "changed:\n  ignore:\n    - \"\"\n", wantErr: "pattern is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+				t.Fatalf("failed to create .git directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create config file: %v", err)
+			}
+
+			cfg, err := Load(tmpDir, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := cfg.Changed.GetIgnore(); len(got) != 2 {
+				t.Errorf("Changed.Ignore = %v, want 2 patterns", got)
+			}
+		})
+	}
+}
+
+func TestChangedConfig_GetIgnore_Nil(t *testing.T) {
+	var c *ChangedConfig
+	if got := c.GetIgnore(); got != nil {
+		t.Errorf("expected no ignore patterns, got %v", got)
+	}
+}
+
+func TestLoad_InvalidVars(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte("vars:\n  - /etc/base.tfvars\n"), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	_, err := Load(tmpDir, "")
+	if err == nil || !strings.Contains(err.Error(), "invalid vars in config") {
+		t.Errorf("expected an error for an absolute vars path, got %v", err)
+	}
+}
+
+func TestLoad_Constraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr string
+	}{
+		{"not set", "binary: terraform\n", "", ""},
+		{"terraform", "constraints:\n  terraform: \">= 1.6\"\n", ">= 1.6", ""},
+		{"invalid", "constraints:\n  terraform: \"latest\"\n", "", "invalid constraints.terraform in config"},
+		{"providers", "constraints:\n  providers:\n    azurerm: \"~> 4.0\"\n", "", ""},
+		{"empty provider", "constraints:\n  providers:\n    azurerm: \"\"\n", "", "a version constraint is required"},
+		{"invalid provider", "constraints:\n  providers:\n    azurerm: \"4.x\"\n", "", "invalid constraints.providers.azurerm in config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+				t.Fatalf("failed to create .git directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create config file: %v", err)
+			}
+
+			cfg, err := Load(tmpDir, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := cfg.Constraints.GetTerraform(); got != tt.want {
+				t.Errorf("GetTerraform() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_BinaryVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+	configPath := filepath.Join(tmpDir, ".motf.yml")
+	if err := os.WriteFile(configPath, []byte("binary_version: \"~> 1.9.0\"\n"), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.BinaryVersion != "~> 1.9.0" {
+		t.Errorf("BinaryVersion = %q, want %q", cfg.BinaryVersion, "~> 1.9.0")
+	}
+
+	if err := os.WriteFile(configPath, []byte("binary_version: latest\n"), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+	if _, err := Load(tmpDir, ""); err == nil || !strings.Contains(err.Error(), "invalid binary_version in config") {
+		t.Errorf("expected an error for an invalid binary_version, got %v", err)
+	}
+}
+
+func TestLoad_Checks(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+	content := "checks:\n  base: [tests]\n  project: []\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Checks.GetRules("base"); len(got) != 1 || got[0] != "tests" {
+		t.Errorf("GetRules(base) = %v, want [tests]", got)
+	}
+	if got := cfg.Checks.GetRules("project"); len(got) != 0 {
+		t.Errorf("GetRules(project) = %v, want none", got)
+	}
+	if got := cfg.Checks.GetRules("component"); len(got) != 5 {
+		t.Errorf("GetRules(component) = %v, want the defaults", got)
+	}
+}
+
+func TestLoad_InvalidChecks(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte("checks:\n  component: [readme]\n"), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	_, err := Load(tmpDir, "")
+	if err == nil || !strings.Contains(err.Error(), "unknown rule 'readme'") {
+		t.Errorf("expected unknown rule error, got %v", err)
+	}
+}
+
+func TestLoad_PolicyPathsRelativeToConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte("policy:\n  paths: [policies]\n"), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Policy.GetPaths(); len(got) != 1 || got[0] != filepath.Join(tmpDir, "policies") {
+		t.Errorf("GetPaths() = %v, want policies relative to the config file", got)
+	}
+	if cfg.Policy.GetQuery() != DefaultPolicyQuery || cfg.Policy.GetPlan() {
+		t.Errorf("expected the default query without the plan, got %q, %t", cfg.Policy.GetQuery(), cfg.Policy.GetPlan())
+	}
+}
+
+func TestWatchConfig_Defaults(t *testing.T) {
+	var w *WatchConfig
+	if w.GetCommand() != DefaultWatchCommand || w.GetTask() != "" || w.GetDebounce() != DefaultWatchDebounce {
+		t.Errorf("unexpected defaults: %q, %q, %s", w.GetCommand(), w.GetTask(), w.GetDebounce())
+	}
+	// A task alone implies command: task
+	w = &WatchConfig{Task: "lint"}
+	if w.GetCommand() != "task" {
+		t.Errorf("GetCommand() = %q, want task", w.GetCommand())
+	}
+}
+
+func TestLoad_Watch(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", "watch:\n  command: fmt\n  debounce: 1s\n", ""},
+		{"task", "tasks:\n  lint:\n    command: tflint\nwatch:\n  task: lint\n", ""},
+		{"unknown command", "watch:\n  command: plan\n", "invalid command 'plan'"},
+		{"task without name", "watch:\n  command: task\n", "command 'task' needs a task"},
+		{"unknown task", "watch:\n  task: lint\n", "unknown task 'lint'"},
+		{"task with fmt", "tasks:\n  lint:\n    command: tflint\nwatch:\n  command: fmt\n  task: lint\n", "task is only used with command 'task'"},
+		{"invalid debounce", "watch:\n  debounce: soon\n", "invalid debounce 'soon'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+				t.Fatalf("failed to create .git directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create config file: %v", err)
+			}
+
+			cfg, err := Load(tmpDir, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v (config %+v)", tt.wantErr, err, cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// HookNames are the hooks that can be set: shell commands run before (pre_)
+// or after (post_) a command in a module
+var HookNames = []string{
+	"pre_init", "post_init",
+	"pre_plan", "post_plan",
+	"pre_apply", "post_apply",
+	"pre_task", "post_task",
+}
+
+// Hook returns the shell command of the hook name (e.g. "pre_plan"), or
+// empty if it isn't set.
+func (c *Config) Hook(name string) string {
+	return c.Hooks[name]
+}
+
+// validateHooks checks that each hook has a known name and a command.
+func validateHooks(hooks map[string]string) error {
+	names := make([]string, 0, len(hooks))
+	for name := range hooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !slices.Contains(HookNames, name) {
+			return fmt.Errorf("unknown hook '%s': must be %s", name, quotedJoin(HookNames))
+		}
+		if hooks[name] == "" {
+			return fmt.Errorf("%s: command must not be empty", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   map[string]string
+		wantErr string
+	}{
+		{"valid", map[string]string{"pre_plan": "az login", "post_task": "notify"}, ""},
+		{"unknown hook", map[string]string{"pre_destroy": "echo"}, "unknown hook 'pre_destroy'"},
+		{"empty command", map[string]string{"post_apply": ""}, "post_apply: command must not be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHooks(tt.hooks)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateHooks() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateHooks() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ForModule_Hooks(t *testing.T) {
+	root := t.TempDir()
+	moduleDir := filepath.Join(root, "projects", "aks")
+	writeModuleConfig(t, moduleDir, "hooks:\n  pre_plan: ./login.sh aks\n")
+
+	cfg := &Config{Root: root, Binary: "terraform", Hooks: map[string]string{"pre_plan": "./login.sh", "post_plan": "./notify.sh"}}
+	modCfg, err := cfg.ForModule(moduleDir)
+	if err != nil {
+		t.Fatalf("ForModule() error = %v", err)
+	}
+	if got := modCfg.Hook("pre_plan"); got != "./login.sh aks" {
+		t.Errorf("pre_plan = %q, want the module's hook", got)
+	}
+	if got := modCfg.Hook("post_plan"); got != "./notify.sh" {
+		t.Errorf("post_plan = %q, want the root hook", got)
+	}
+	if got := modCfg.Hook("pre_init"); got != "" {
+		t.Errorf("pre_init = %q, want none", got)
+	}
+	if cfg.Hooks["pre_plan"] != "./login.sh" {
+		t.Errorf("expected the root config to be unchanged, got %v", cfg.Hooks)
+	}
+}
+
+func TestLoadModuleConfig_InvalidHooks(t *testing.T) {
+	path := writeModuleConfig(t, t.TempDir(), "hooks:\n  before_plan: echo\n")
+
+	_, err := LoadModuleConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "invalid hooks") {
+		t.Fatalf("expected invalid hooks error, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// DefaultLockPlatforms are the platforms 'motf lock' records provider
+// checksums for without a lock section: CI runners and Apple silicon laptops.
+var DefaultLockPlatforms = []string{"linux_amd64", "darwin_arm64"}
+
+// platformPattern matches Terraform platform names, e.g. linux_amd64
+var platformPattern = regexp.MustCompile(`^[a-z0-9]+_[a-z0-9]+$`)
+
+// LockConfig represents the lock section
+type LockConfig struct {
+	Platforms []string `yaml:"platforms"` // Platforms recorded in .terraform.lock.hcl, e.g. linux_amd64
+}
+
+// GetPlatforms returns the platforms provider checksums are recorded for,
+// defaulting to DefaultLockPlatforms.
+func (l *LockConfig) GetPlatforms() []string {
+	if l == nil || len(l.Platforms) == 0 {
+		return DefaultLockPlatforms
+	}
+	return l.Platforms
+}
+
+// validate checks that the platforms are Terraform platform names, each
+// listed once.
+func (l *LockConfig) validate() error {
+	if l == nil {
+		return nil
+	}
+	return ValidatePlatforms(l.Platforms)
+}
+
+// ValidatePlatforms checks that platforms are Terraform platform names, like
+// linux_amd64, each listed once.
+func ValidatePlatforms(platforms []string) error {
+	for i, platform := range platforms {
+		if !platformPattern.MatchString(platform) {
+			return fmt.Errorf("invalid platform '%s': must be os_arch, e.g. linux_amd64", platform)
+		}
+		if slices.Contains(platforms[:i], platform) {
+			return fmt.Errorf("platform '%s' is listed twice", platform)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLockConfig_GetPlatforms(t *testing.T) {
+	var l *LockConfig
+	if got := l.GetPlatforms(); !reflect.DeepEqual(got, DefaultLockPlatforms) {
+		t.Errorf("GetPlatforms() = %v, want the defaults", got)
+	}
+	l = &LockConfig{Platforms: []string{"windows_amd64"}}
+	if got := l.GetPlatforms(); !reflect.DeepEqual(got, []string{"windows_amd64"}) {
+		t.Errorf("GetPlatforms() = %v", got)
+	}
+}
+
+func TestLoad_Lock(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"platforms", "lock:\n  platforms: [linux_amd64, linux_arm64, darwin_arm64]\n", ""},
+		{"slash", "lock:\n  platforms: [linux/amd64]\n", "invalid platform 'linux/amd64': must be os_arch, e.g. linux_amd64"},
+		{"duplicate", "lock:\n  platforms: [linux_amd64, linux_amd64]\n", "platform 'linux_amd64' is listed twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+				t.Fatalf("failed to create .git directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create config file: %v", err)
+			}
+
+			_, err := Load(tmpDir, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
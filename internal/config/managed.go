@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+)
+
+// IsManaged reports whether the module at modulePath (relative to Root) is
+// managed by motf: it is, or is inside, one of ManagedPaths. Entries may
+// contain * wildcards. Everything is managed when ManagedPaths is empty.
+func (c *Config) IsManaged(modulePath string) bool {
+	if c == nil || len(c.ManagedPaths) == 0 {
+		return true
+	}
+	modulePath = path.Clean(filepath.ToSlash(modulePath))
+	for _, managed := range c.ManagedPaths {
+		managed = path.Clean(managed)
+		// Match the module directory itself or any of its parents
+		for dir := modulePath; dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if finder.MatchesWildcard(dir, managed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateManagedPaths checks that every managed path is a relative path inside Root.
+func validateManagedPaths(paths []string) error {
+	for i, p := range paths {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("entry %d: path is required", i+1)
+		}
+		clean := path.Clean(filepath.ToSlash(p))
+		if path.IsAbs(clean) || filepath.IsAbs(p) {
+			return fmt.Errorf("'%s' must be relative to root", p)
+		}
+		if clean == ".." || strings.HasPrefix(clean, "../") {
+			return fmt.Errorf("'%s' must be inside root", p)
+		}
+	}
+	return nil
+}
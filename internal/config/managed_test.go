@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfig_IsManaged(t *testing.T) {
+	cfg := &Config{ManagedPaths: []string{"components/azurerm", "projects/*-prod/"}}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"components/azurerm", true},
+		{"components/azurerm/storage-account", true},
+		{filepath.Join("components", "azurerm", "vnet"), true},
+		{"components/azurerm-legacy/vnet", false},
+		{"components/aws/s3", false},
+		{"projects/web-prod", true},
+		{"projects/web-prod/examples/basic", true},
+		{"projects/web-dev", false},
+	}
+	for _, tt := range tests {
+		if got := cfg.IsManaged(tt.path); got != tt.want {
+			t.Errorf("IsManaged(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	var nilConfig *Config
+	if !nilConfig.IsManaged("components/aws/s3") || !(&Config{}).IsManaged("components/aws/s3") {
+		t.Error("expected every module to be managed without managed_paths")
+	}
+}
+
+func TestLoad_ManagedPaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: "managed_paths:\n  - components/azurerm\n  - projects\n"},
+		{name: "empty entry", content: "managed_paths:\n  - \"\"\n", wantErr: "path is required"},
+		{name: "absolute", content: "managed_paths:\n  - /components\n", wantErr: "must be relative to root"},
+		{name: "outside root", content: "managed_paths:\n  - ../other\n", wantErr: "must be inside root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+				t.Fatalf("failed to create .git directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create config file: %v", err)
+			}
+
+			cfg, err := Load(tmpDir, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(cfg.ManagedPaths) != 2 {
+				t.Errorf("ManagedPaths = %v, want 2 entries", cfg.ManagedPaths)
+			}
+		})
+	}
+}
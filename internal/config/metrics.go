@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/metrics"
+)
+
+// MetricsConfig represents the metrics section: where the duration and
+// outcome of each command and its modules are emitted. Metrics are off
+// unless the section is set.
+type MetricsConfig struct {
+	Format  string            `yaml:"format"`  // prometheus, statsd, or json
+	Path    string            `yaml:"path"`    // File of the prometheus and json formats, relative to the config file
+	Address string            `yaml:"address"` // host:port of the statsd server
+	Prefix  string            `yaml:"prefix"`  // Prefix of metric names
+	Labels  map[string]string `yaml:"labels"`  // Extra labels added to every metric, with ${VAR} references
+}
+
+// GetAddress returns the address of the statsd server, defaulting to
+// metrics.DefaultStatsDAddress.
+func (m *MetricsConfig) GetAddress() string {
+	if m == nil || m.Address == "" {
+		return metrics.DefaultStatsDAddress
+	}
+	return m.Address
+}
+
+// GetPrefix returns the prefix of metric names, defaulting to metrics.DefaultPrefix.
+func (m *MetricsConfig) GetPrefix() string {
+	if m == nil || m.Prefix == "" {
+		return metrics.DefaultPrefix
+	}
+	return m.Prefix
+}
+
+// Emitter returns the emitter of the configured metrics, or nil when
+// metrics are off.
+func (m *MetricsConfig) Emitter() *metrics.Emitter {
+	if m == nil {
+		return nil
+	}
+	return &metrics.Emitter{
+		Format:  m.Format,
+		Path:    m.Path,
+		Address: m.GetAddress(),
+		Prefix:  m.GetPrefix(),
+		Labels:  m.Labels,
+	}
+}
+
+// validate checks the format, that the file or address it needs is set,
+// and the prefix and label names.
+func (m *MetricsConfig) validate() error {
+	if m == nil {
+		return nil
+	}
+	if m.Format == "" {
+		return fmt.Errorf("format is required: must be %s", quotedJoin(metrics.Formats))
+	}
+	if !slices.Contains(metrics.Formats, m.Format) {
+		return fmt.Errorf("invalid format '%s': must be %s", m.Format, quotedJoin(metrics.Formats))
+	}
+	if m.Format == metrics.FormatStatsD {
+		if m.Path != "" {
+			return fmt.Errorf("path can't be used with format statsd, set address instead")
+		}
+		if _, _, err := net.SplitHostPort(m.GetAddress()); err != nil {
+			return fmt.Errorf("invalid address '%s': expected host:port, e.g. %s", m.Address, metrics.DefaultStatsDAddress)
+		}
+	} else {
+		if m.Path == "" {
+			return fmt.Errorf("path is required with format %s", m.Format)
+		}
+		if m.Address != "" {
+			return fmt.Errorf("address can only be used with format statsd")
+		}
+	}
+	if !metrics.ValidPrefix(m.GetPrefix()) {
+		return fmt.Errorf("invalid prefix '%s': must be letters, digits, and underscores", m.Prefix)
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Labels)) {
+		if !metrics.ValidLabel(name) {
+			return fmt.Errorf("invalid label '%s': must be letters, digits, and underscores, and not %s", name, quotedJoin(metrics.ReservedLabels))
+		}
+	}
+	return nil
+}
+
+// expandLabels expands ${VAR} references in label values in place.
+func (m *MetricsConfig) expandLabels(getenv func(string) string) {
+	if m == nil {
+		return
+	}
+	for name, value := range m.Labels {
+		m.Labels[name] = ExpandEnv(value, getenv)
+	}
+}
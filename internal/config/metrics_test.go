@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/metrics"
+)
+
+func TestMetricsConfig_Defaults(t *testing.T) {
+	var m *MetricsConfig
+	if m.Emitter() != nil {
+		t.Error("metrics should be off without a metrics section")
+	}
+	if m.GetAddress() != metrics.DefaultStatsDAddress || m.GetPrefix() != metrics.DefaultPrefix {
+		t.Errorf("unexpected defaults: %q, %q", m.GetAddress(), m.GetPrefix())
+	}
+}
+
+func TestLoad_Metrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"prometheus", "metrics:\n  format: prometheus\n  path: metrics/motf.prom\n", ""},
+		{"statsd", "metrics:\n  format: statsd\n  address: statsd.internal:8125\n  prefix: ci\n", ""},
+		{"json with labels", "metrics:\n  format: json\n  path: metrics.ndjson\n  labels:\n    pipeline: pr\n", ""},
+		{"missing format", "metrics:\n  path: m.prom\n", "format is required"},
+		{"unknown format", "metrics:\n  format: influx\n", "invalid format 'influx': must be 'prometheus', 'statsd', or 'json'"},
+		{"missing path", "metrics:\n  format: json\n", "path is required with format json"},
+		{"address with file format", "metrics:\n  format: json\n  path: m.ndjson\n  address: localhost:8125\n", "address can only be used with format statsd"},
+		{"path with statsd", "metrics:\n  format: statsd\n  path: m.prom\n", "path can't be used with format statsd"},
+		{"invalid address", "metrics:\n  format: statsd\n  address: localhost\n", "invalid address 'localhost'"},
+		{"invalid prefix", "metrics:\n  format: statsd\n  prefix: ci.motf\n", "invalid prefix 'ci.motf'"},
+		{"reserved label", "metrics:\n  format: statsd\n  labels:\n    status: x\n", "invalid label 'status'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+				t.Fatalf("failed to create .git directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create config file: %v", err)
+			}
+
+			_, err := Load(tmpDir, "")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_MetricsPathAndLabels(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create .git directory: %v", err)
+	}
+	content := "metrics:\n  format: json\n  path: .motf/metrics.ndjson\n  labels:\n    branch: ${MOTF_TEST_BRANCH}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+	t.Setenv("MOTF_TEST_BRANCH", "main")
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := filepath.Join(tmpDir, ".motf", "metrics.ndjson"); cfg.Metrics.Path != want {
+		t.Errorf("path = %q, want %q", cfg.Metrics.Path, want)
+	}
+	if cfg.Metrics.Labels["branch"] != "main" {
+		t.Errorf("branch label = %q, want %q", cfg.Metrics.Labels["branch"], "main")
+	}
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/versions"
+	"gopkg.in/yaml.v3"
+)
+
+// ModuleConfigFile is the name of the optional per-module config file
+const ModuleConfigFile = ".motf.module.yml"
+
+// ModuleConfig represents a .motf.module.yml file, which overrides parts of the
+// root config for a single module. Empty fields inherit the root value.
+type ModuleConfig struct {
+	Binary        string                       `yaml:"binary"`
+	BinaryVersion string                       `yaml:"binary_version"` // Replaces the root binary version constraint
+	Test          *TestConfig                  `yaml:"test"`
+	Tasks         map[string]*tasks.TaskConfig `yaml:"tasks"`
+	Env           map[string]string            `yaml:"env"`
+	Vars          []string                     `yaml:"vars"`     // Replaces the root variable file layers
+	Timeouts      map[string]string            `yaml:"timeouts"` // Merged over the root timeouts, per command
+	Hooks         map[string]string            `yaml:"hooks"`    // Merged over the root hooks, per hook
+	Path          string                       `yaml:"-"`        // Path to the module config file
+}
+
+// FindModuleConfig returns the path of the .motf.module.yml that applies to dir.
+// It searches dir and its parents up to (but not including) root, so examples
+// inherit the config of their module. An empty root is the working directory,
+// the base path modules are discovered in without a root.
+// Returns "" if there is none.
+func FindModuleConfig(dir, root string) string {
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return ""
+		}
+		root = wd
+	}
+	root = filepath.Clean(root)
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for dir != root {
+		path := filepath.Join(dir, ModuleConfigFile)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		// Stop at the filesystem root, or if dir is not below root
+		if parent == dir || !isWithin(parent, root) {
+			return ""
+		}
+		dir = parent
+	}
+	return ""
+}
+
+// isWithin reports whether path is root or a directory below it.
+func isWithin(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// LoadModuleConfig reads and validates the module config file at path.
+func LoadModuleConfig(path string) (*ModuleConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module config file: %w", err)
+	}
+
+	var mc ModuleConfig
+	if err := yaml.Unmarshal(data, &mc); err != nil {
+		return nil, fmt.Errorf("failed to parse module config file %s: %w", path, err)
+	}
+	mc.Path = path
+	expandConfigEnv(mc.Env, mc.Tasks, os.Getenv)
+
+	if mc.Binary != "" && !IsValidBinary(mc.Binary) {
+		return nil, fmt.Errorf("invalid binary '%s' in %s: must be %s", mc.Binary, path, quotedJoin(ValidBinaryNames()))
+	}
+	if err := versions.Validate(mc.BinaryVersion); err != nil {
+		return nil, fmt.Errorf("invalid binary_version in %s: %w", path, err)
+	}
+	if mc.Test != nil && mc.Test.Engine != "" && !IsValidTestEngine(mc.Test.Engine) {
+		return nil, fmt.Errorf("invalid test engine '%s' in %s: must be %s", mc.Test.Engine, path, quotedJoin(ValidTestEngineNames()))
+	}
+	if mc.Test != nil && mc.Test.Cost < 0 {
+		return nil, fmt.Errorf("invalid test cost %g in %s: must be 0 or more", mc.Test.Cost, path)
+	}
+	if mc.Test != nil {
+		if err := validateCompliance(mc.Test.Compliance); err != nil {
+			return nil, fmt.Errorf("invalid test.compliance in %s: %w", path, err)
+		}
+	}
+	if err := validateVars(mc.Vars); err != nil {
+		return nil, fmt.Errorf("invalid vars in %s: %w", path, err)
+	}
+	if err := validateTimeouts(mc.Timeouts); err != nil {
+		return nil, fmt.Errorf("invalid timeouts in %s: %w", path, err)
+	}
+	if err := validateHooks(mc.Hooks); err != nil {
+		return nil, fmt.Errorf("invalid hooks in %s: %w", path, err)
+	}
+
+	return &mc, nil
+}
+
+// ForModule returns the effective config for the module at dir: the root config
+// merged with the module's .motf.module.yml, if any. The receiver is not modified;
+// when there is no module config, the receiver itself is returned.
+func (c *Config) ForModule(dir string) (*Config, error) {
+	path := FindModuleConfig(dir, c.Root)
+	if path == "" {
+		return c, nil
+	}
+
+	mc, err := LoadModuleConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Merge(mc), nil
+}
+
+// Merge returns a copy of the config with the module config applied on top.
+// Tasks, env vars, timeouts, and hooks are merged by name, with module values taking priority;
+// variable file layers are replaced as a whole.
+func (c *Config) Merge(mc *ModuleConfig) *Config {
+	merged := *c
+	merged.ModuleConfigPath = mc.Path
+
+	if mc.Binary != "" {
+		merged.Binary = mc.Binary
+	}
+	if mc.BinaryVersion != "" {
+		merged.BinaryVersion = mc.BinaryVersion
+	}
+
+	if mc.Test != nil {
+		test := TestConfig{}
+		if c.Test != nil {
+			test = *c.Test
+		}
+		if mc.Test.Engine != "" {
+			test.Engine = mc.Test.Engine
+		}
+		if mc.Test.Args != "" {
+			test.Args = mc.Test.Args
+		}
+		if mc.Test.Cost != 0 {
+			test.Cost = mc.Test.Cost
+		}
+		if mc.Test.Compliance != nil {
+			test.Compliance = mc.Test.Compliance
+		}
+		merged.Test = &test
+	}
+
+	if len(mc.Tasks) > 0 {
+		merged.Tasks = make(map[string]*tasks.TaskConfig, len(c.Tasks)+len(mc.Tasks))
+		maps.Copy(merged.Tasks, c.Tasks)
+		maps.Copy(merged.Tasks, mc.Tasks)
+	}
+
+	if len(mc.Vars) > 0 {
+		merged.Vars = mc.Vars
+	}
+
+	if len(mc.Env) > 0 {
+		merged.Env = make(map[string]string, len(c.Env)+len(mc.Env))
+		maps.Copy(merged.Env, c.Env)
+		maps.Copy(merged.Env, mc.Env)
+	}
+
+	if len(mc.Timeouts) > 0 {
+		merged.Timeouts = make(map[string]string, len(c.Timeouts)+len(mc.Timeouts))
+		maps.Copy(merged.Timeouts, c.Timeouts)
+		maps.Copy(merged.Timeouts, mc.Timeouts)
+	}
+
+	if len(mc.Hooks) > 0 {
+		merged.Hooks = make(map[string]string, len(c.Hooks)+len(mc.Hooks))
+		maps.Copy(merged.Hooks, c.Hooks)
+		maps.Copy(merged.Hooks, mc.Hooks)
+	}
+
+	return &merged
+}
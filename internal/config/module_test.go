@@ -0,0 +1,202 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+)
+
+// writeModuleConfig writes a .motf.module.yml into dir.
+func writeModuleConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	path := filepath.Join(dir, ModuleConfigFile)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write module config: %v", err)
+	}
+	return path
+}
+
+func TestFindModuleConfig(t *testing.T) {
+	root := t.TempDir()
+	moduleDir := filepath.Join(root, "components", "storage-account")
+	exampleDir := filepath.Join(moduleDir, "examples", "basic")
+	otherDir := filepath.Join(root, "components", "other")
+	for _, dir := range []string{exampleDir, otherDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+	}
+	path := writeModuleConfig(t, moduleDir, "binary: tofu\n")
+
+	// A module config at the root is not a module config
+	writeModuleConfig(t, root, "binary: tofu\n")
+
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{"module directory", moduleDir, path},
+		{"example inherits module config", exampleDir, path},
+		{"module without config", otherDir, ""},
+		{"root itself", root, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindModuleConfig(tt.dir, root); got != tt.want {
+				t.Errorf("FindModuleConfig(%s) = %q, want %q", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindModuleConfig_EmptyRoot(t *testing.T) {
+	base := t.TempDir()
+	moduleDir := filepath.Join(base, "components", "storage-account")
+	exampleDir := filepath.Join(moduleDir, "examples", "basic")
+	if err := os.MkdirAll(exampleDir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	path := writeModuleConfig(t, moduleDir, "binary: tofu\n")
+
+	// Configs in and above the working directory are not module configs
+	workDir := filepath.Join(base, "work")
+	writeModuleConfig(t, base, "binary: tofu\n")
+	writeModuleConfig(t, workDir, "binary: tofu\n")
+	otherDir := filepath.Join(workDir, "components", "other")
+	if err := os.MkdirAll(otherDir, 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		wd   string
+		dir  string
+		want string
+	}{
+		{"absolute module directory", base, moduleDir, path},
+		{"absolute example", base, exampleDir, path},
+		{"relative module directory", base, filepath.Join("components", "storage-account"), path},
+		{"relative example", base, filepath.Join("components", "storage-account", "examples", "basic"), path},
+		{"relative without config", base, "components", ""},
+		{"config in the working directory", workDir, filepath.Join("components", "other"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(tt.wd)
+			if got := FindModuleConfig(tt.dir, ""); got != tt.want {
+				t.Errorf("FindModuleConfig(%s, \"\") = %q, want %q", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadModuleConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid binary", "binary: terragrunt\n", "invalid binary 'terragrunt'"},
+		{"invalid test engine", "test:\n  engine: pytest\n", "invalid test engine 'pytest'"},
+		{"invalid yaml", "binary: [\n", "failed to parse module config file"},
+		{"invalid vars", "vars:\n  - vars/{stage}.tfvars\n", "unknown placeholder {stage}"},
+		{"negative test cost", "test:\n  cost: -5\n", "invalid test cost -5"},
+		{"compliance features outside module", "test:\n  compliance:\n    features: ../features\n", "must be a path inside the module"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeModuleConfig(t, t.TempDir(), tt.content)
+			_, err := LoadModuleConfig(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig_Merge(t *testing.T) {
+	root := &Config{
+		Binary: "terraform",
+		Test:   &TestConfig{Engine: "terratest", Args: "-v"},
+		Tasks: map[string]*tasks.TaskConfig{
+			"lint": {Command: "tflint"},
+			"docs": {Command: "terraform-docs ."},
+		},
+	}
+
+	root.Vars = []string{"base.tfvars"}
+	merged := root.Merge(&ModuleConfig{
+		Binary:        "tofu",
+		BinaryVersion: "~> 1.8.0",
+		Vars:          []string{"vars/base.tfvars", "vars/{env}.tfvars"},
+		Test:          &TestConfig{Args: "-timeout=30m", Cost: 12.5},
+		Tasks:         map[string]*tasks.TaskConfig{"lint": {Command: "tflint --module"}},
+		Env:           map[string]string{"ARM_USE_OIDC": "true"},
+		Path:          "/repo/components/x/.motf.module.yml",
+	})
+
+	if merged.Binary != "tofu" {
+		t.Errorf("expected binary override 'tofu', got '%s'", merged.Binary)
+	}
+	if merged.BinaryVersion != "~> 1.8.0" {
+		t.Errorf("expected binary_version override '~> 1.8.0', got '%s'", merged.BinaryVersion)
+	}
+	if merged.Test.Engine != "terratest" || merged.Test.Args != "-timeout=30m" || merged.Test.GetCost() != 12.5 {
+		t.Errorf("expected engine inherited and args overridden, got %+v", merged.Test)
+	}
+	if merged.Tasks["lint"].Command != "tflint --module" {
+		t.Errorf("expected module task to override root task, got '%s'", merged.Tasks["lint"].Command)
+	}
+	if merged.Tasks["docs"] == nil {
+		t.Error("expected root task to be inherited")
+	}
+	if merged.Env["ARM_USE_OIDC"] != "true" {
+		t.Errorf("expected module env var, got %v", merged.Env)
+	}
+	if len(merged.Vars) != 2 || merged.Vars[1] != "vars/{env}.tfvars" {
+		t.Errorf("expected module vars to replace the root layers, got %v", merged.Vars)
+	}
+	if merged.ModuleConfigPath != "/repo/components/x/.motf.module.yml" {
+		t.Errorf("expected ModuleConfigPath to be set, got '%s'", merged.ModuleConfigPath)
+	}
+
+	// The root config is not modified
+	if root.Binary != "terraform" || root.Test.Args != "-v" || root.Tasks["lint"].Command != "tflint" {
+		t.Errorf("root config was modified: %+v", root)
+	}
+}
+
+func TestConfig_ForModule(t *testing.T) {
+	root := t.TempDir()
+	moduleDir := filepath.Join(root, "components", "storage-account")
+	writeModuleConfig(t, moduleDir, "binary: tofu\n")
+
+	cfg := DefaultConfig()
+	cfg.Root = root
+
+	modCfg, err := cfg.ForModule(moduleDir)
+	if err != nil {
+		t.Fatalf("ForModule returned error: %v", err)
+	}
+	if modCfg.Binary != "tofu" {
+		t.Errorf("expected binary 'tofu', got '%s'", modCfg.Binary)
+	}
+
+	otherCfg, err := cfg.ForModule(filepath.Join(root, "components", "other"))
+	if err != nil {
+		t.Fatalf("ForModule returned error: %v", err)
+	}
+	if otherCfg != cfg {
+		t.Error("expected the root config to be returned for a module without overrides")
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ModuleDir is a directory under Root that modules are discovered in, and
+// the type reported for its modules.
+type ModuleDir struct {
+	Dir  string
+	Type string
+}
+
+// DefaultModuleDirs are the module directories used when module_dirs isn't set
+var DefaultModuleDirs = []ModuleDir{
+	{Dir: "components", Type: "component"},
+	{Dir: "bases", Type: "base"},
+	{Dir: "projects", Type: "project"},
+}
+
+// ModuleDirs is the module_dirs section: a list of directory names, or a
+// mapping of directory names to module types, in order.
+type ModuleDirs []ModuleDir
+
+// UnmarshalYAML reads module_dirs as a list, where each directory's type is
+// its default type or its name without a trailing s (stacks -> stack), or
+// as a mapping of directories to types.
+func (m *ModuleDirs) UnmarshalYAML(node *yaml.Node) error {
+	var dirs ModuleDirs
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var names []string
+		if err := node.Decode(&names); err != nil {
+			return err
+		}
+		for _, name := range names {
+			dirs = append(dirs, ModuleDir{Dir: name, Type: defaultModuleType(name)})
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			var dir ModuleDir
+			if err := node.Content[i].Decode(&dir.Dir); err != nil {
+				return err
+			}
+			if err := node.Content[i+1].Decode(&dir.Type); err != nil {
+				return err
+			}
+			dirs = append(dirs, dir)
+		}
+	default:
+		return fmt.Errorf("line %d: module_dirs must be a list of directories or a mapping of directories to types", node.Line)
+	}
+	*m = dirs
+	return nil
+}
+
+// defaultModuleType returns the type of the default module directory dir,
+// or dir without a trailing s.
+func defaultModuleType(dir string) string {
+	for _, d := range DefaultModuleDirs {
+		if d.Dir == dir {
+			return d.Type
+		}
+	}
+	return strings.TrimSuffix(dir, "s")
+}
+
+// GetModuleDirs returns the module directories, DefaultModuleDirs if
+// module_dirs isn't set.
+func (c *Config) GetModuleDirs() []ModuleDir {
+	if c == nil || len(c.ModuleDirs) == 0 {
+		return DefaultModuleDirs
+	}
+	return c.ModuleDirs
+}
+
+// validModuleType reports whether t can be used as a module type, in --type
+// and in module references like component:storage-account.
+func validModuleType(t string) bool {
+	return t != "" && !strings.ContainsAny(t, ": \t")
+}
+
+// validate checks that every module directory is a single, distinct
+// directory name with a type that can be used in module references.
+func (m ModuleDirs) validate() error {
+	seen := make(map[string]bool, len(m))
+	for _, d := range m {
+		if d.Dir == "" || d.Dir == "." || d.Dir == ".." || strings.ContainsAny(d.Dir, `/\:`) {
+			return fmt.Errorf("'%s' must be a directory name under root, e.g. modules", d.Dir)
+		}
+		if seen[d.Dir] {
+			return fmt.Errorf("'%s' is listed twice", d.Dir)
+		}
+		seen[d.Dir] = true
+		if !validModuleType(d.Type) {
+			return fmt.Errorf("'%s' has invalid type '%s': must be a word, e.g. component", d.Dir, d.Type)
+		}
+	}
+	return nil
+}
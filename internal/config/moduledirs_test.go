@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad_ModuleDirs(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []ModuleDir
+	}{
+		{
+			name:   "list",
+			config: "module_dirs: [modules, stacks, components]\n",
+			want:   []ModuleDir{{Dir: "modules", Type: "module"}, {Dir: "stacks", Type: "stack"}, {Dir: "components", Type: "component"}},
+		},
+		{
+			name:   "mapping keeps order",
+			config: "module_dirs:\n  modules: component\n  live: project\n",
+			want:   []ModuleDir{{Dir: "modules", Type: "component"}, {Dir: "live", Type: "project"}},
+		},
+		{
+			name:   "not set",
+			config: "root: infra\n",
+			want:   DefaultModuleDirs,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.config), 0644); err != nil {
+				t.Fatalf("failed to create config file: %v", err)
+			}
+
+			cfg, err := Load(tmpDir, "")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := cfg.GetModuleDirs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetModuleDirs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_GetModuleDirs_Nil(t *testing.T) {
+	var c *Config
+	if got := c.GetModuleDirs(); !reflect.DeepEqual(got, DefaultModuleDirs) {
+		t.Errorf("GetModuleDirs() = %v, want the defaults", got)
+	}
+}
+
+func TestLoad_InvalidModuleDirs(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{"module_dirs: modules", "module_dirs must be a list of directories or a mapping"},
+		{"module_dirs: [infra/modules]", "invalid module_dirs in config: 'infra/modules' must be a directory name under root"},
+		{"module_dirs: ['..']", "invalid module_dirs in config: '..' must be a directory name under root"},
+		{"module_dirs: [modules, modules]", "invalid module_dirs in config: 'modules' is listed twice"},
+		{"module_dirs: {modules: 'my module'}", "invalid module_dirs in config: 'modules' has invalid type 'my module'"},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.config+"\n"), 0644); err != nil {
+			t.Fatalf("failed to create config file: %v", err)
+		}
+
+		_, err := Load(tmpDir, "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.config, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TypeRule gives the modules whose path matches Pattern the type Type.
+type TypeRule struct {
+	Pattern string
+	Type    string
+}
+
+// TypeRules is the types section: an ordered mapping of glob patterns,
+// relative to Root, to module types.
+type TypeRules []TypeRule
+
+// UnmarshalYAML reads the types section as a mapping, keeping its order.
+func (t *TypeRules) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: types must be a mapping of path patterns to types", node.Line)
+	}
+	var rules TypeRules
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var rule TypeRule
+		if err := node.Content[i].Decode(&rule.Pattern); err != nil {
+			return err
+		}
+		if err := node.Content[i+1].Decode(&rule.Type); err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+	*t = rules
+	return nil
+}
+
+// ModuleType returns the type of the first rule in Types matching
+// modulePath (relative to Root), or "" if none does.
+func (c *Config) ModuleType(modulePath string) string {
+	if c == nil {
+		return ""
+	}
+	modulePath = path.Clean(filepath.ToSlash(modulePath))
+	for _, rule := range c.Types {
+		if matchGlob(rule.Pattern, modulePath) {
+			return rule.Type
+		}
+	}
+	return ""
+}
+
+// RuleTypes returns the types of the rules in Types, each once, in order.
+func (c *Config) RuleTypes() []string {
+	if c == nil {
+		return nil
+	}
+	var types []string
+	seen := make(map[string]bool)
+	for _, rule := range c.Types {
+		if !seen[rule.Type] {
+			seen[rule.Type] = true
+			types = append(types, rule.Type)
+		}
+	}
+	return types
+}
+
+// matchGlob reports whether the slash-separated name matches pattern. *, ?
+// and [...] match within a path element as in path.Match, and a ** element
+// matches any number of elements.
+func matchGlob(pattern, name string) bool {
+	return matchElems(strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(name, "/"))
+}
+
+// matchElems matches the path elements of a name against those of a pattern.
+func matchElems(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchElems(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// validate checks that every pattern is a valid glob relative to Root, and
+// every type can be used as a module type.
+func (t TypeRules) validate() error {
+	for _, rule := range t {
+		p := filepath.ToSlash(rule.Pattern)
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("pattern is required for type '%s'", rule.Type)
+		}
+		if path.IsAbs(p) || filepath.IsAbs(rule.Pattern) || p == ".." || strings.HasPrefix(p, "../") {
+			return fmt.Errorf("'%s' must be relative to root", rule.Pattern)
+		}
+		for _, elem := range strings.Split(p, "/") {
+			if _, err := path.Match(elem, ""); err != nil {
+				return fmt.Errorf("'%s' is not a valid pattern: %w", rule.Pattern, err)
+			}
+		}
+		if !validModuleType(rule.Type) {
+			return fmt.Errorf("'%s' has invalid type '%s': must be a word, e.g. azure", rule.Pattern, rule.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"modules/azure/**", "modules/azure/storage", true},
+		{"modules/azure/**", "modules/azure/network/vnet", true},
+		{"modules/azure/**", "modules/aws/s3", false},
+		{"modules/*/storage", "modules/azure/storage", true},
+		{"modules/*/storage", "modules/azure/x/storage", false},
+		{"**/storage-*", "components/azurerm/storage-account", true},
+		{"**/storage-*", "components/azurerm/key-vault", false},
+		{"projects/prod-?", "projects/prod-1", true},
+		{"components", "components/storage", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_Types(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := "types:\n  modules/azure/**: azure\n  modules/**: shared\n  live/*: azure\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(config), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// The first matching pattern wins
+	tests := map[string]string{
+		"modules/azure/storage":  "azure",
+		"modules/aws/s3":         "shared",
+		"live/prod":              "azure",
+		"components/storage-acc": "",
+	}
+	for modulePath, want := range tests {
+		if got := cfg.ModuleType(modulePath); got != want {
+			t.Errorf("ModuleType(%q) = %q, want %q", modulePath, got, want)
+		}
+	}
+	if got := cfg.RuleTypes(); !reflect.DeepEqual(got, []string{"azure", "shared"}) {
+		t.Errorf("RuleTypes() = %v, want [azure shared]", got)
+	}
+}
+
+func TestConfig_ModuleType_Nil(t *testing.T) {
+	var c *Config
+	if c.ModuleType("modules/storage") != "" || c.RuleTypes() != nil {
+		t.Error("expected no types for nil config")
+	}
+}
+
+func TestLoad_InvalidTypes(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{"types: [modules]", "types must be a mapping of path patterns to types"},
+		{"types: {'/modules/**': azure}", "invalid types in config: '/modules/**' must be relative to root"},
+		{"types: {'../modules/**': azure}", "invalid types in config: '../modules/**' must be relative to root"},
+		{"types: {'modules/[a': azure}", "invalid types in config: 'modules/[a' is not a valid pattern"},
+		{"types: {'modules/**': 'azure:rm'}", "invalid types in config: 'modules/**' has invalid type 'azure:rm'"},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.config+"\n"), 0644); err != nil {
+			t.Fatalf("failed to create config file: %v", err)
+		}
+
+		_, err := Load(tmpDir, "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.config, tt.want, err)
+		}
+	}
+}
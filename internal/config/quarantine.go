@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+)
+
+// QuarantineDateLayout is the format of quarantine expiry dates
+const QuarantineDateLayout = "2006-01-02"
+
+// QuarantineEntry marks the tests of a module as flaky until an expiry date.
+// Failures of quarantined tests are reported as warnings instead of failing the run.
+type QuarantineEntry struct {
+	Module string `yaml:"module"` // Module name or path, * wildcards allowed
+	Until  string `yaml:"until"`  // Last day of the quarantine (YYYY-MM-DD)
+	Reason string `yaml:"reason"` // Why the tests are quarantined, e.g. a ticket link
+}
+
+// Expiry returns the moment the quarantine ends: the start of the day after Until, in UTC.
+func (q QuarantineEntry) Expiry() (time.Time, error) {
+	until, err := time.Parse(QuarantineDateLayout, q.Until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s', expected YYYY-MM-DD", q.Until)
+	}
+	return until.AddDate(0, 0, 1), nil
+}
+
+// Active reports whether the quarantine still applies at now.
+func (q QuarantineEntry) Active(now time.Time) bool {
+	expiry, err := q.Expiry()
+	return err == nil && now.Before(expiry)
+}
+
+// Quarantined returns the quarantine entry matching a module by name or path,
+// or nil if its tests are not quarantined. An entry active at now is preferred;
+// without one, the first expired entry is returned so callers can note the
+// expiry. Use Active to check it.
+func (t *TestConfig) Quarantined(name, path string, now time.Time) *QuarantineEntry {
+	if t == nil {
+		return nil
+	}
+	var expired *QuarantineEntry
+	for i, q := range t.Quarantine {
+		if !finder.MatchesWildcard(name, q.Module) && !finder.MatchesWildcard(path, q.Module) {
+			continue
+		}
+		if q.Active(now) {
+			return &t.Quarantine[i]
+		}
+		if expired == nil {
+			expired = &t.Quarantine[i]
+		}
+	}
+	return expired
+}
+
+// validateQuarantine checks that every quarantine entry names a module and has a valid expiry date.
+func validateQuarantine(entries []QuarantineEntry) error {
+	for i, q := range entries {
+		if q.Module == "" {
+			return fmt.Errorf("entry %d: module is required", i+1)
+		}
+		if q.Until == "" {
+			return fmt.Errorf("module '%s': until is required, quarantines must expire", q.Module)
+		}
+		if _, err := q.Expiry(); err != nil {
+			return fmt.Errorf("module '%s': %w", q.Module, err)
+		}
+	}
+	return nil
+}
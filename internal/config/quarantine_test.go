@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuarantineEntry_Active(t *testing.T) {
+	q := QuarantineEntry{Module: "vnet", Until: "2030-06-15"}
+
+	tests := []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2030, 6, 14, 12, 0, 0, 0, time.UTC), true},
+		{time.Date(2030, 6, 15, 23, 59, 0, 0, time.UTC), true}, // The until date is the last quarantined day
+		{time.Date(2030, 6, 16, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		if got := q.Active(tt.now); got != tt.want {
+			t.Errorf("Active(%s) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestTestConfig_Quarantined(t *testing.T) {
+	tc := &TestConfig{Quarantine: []QuarantineEntry{
+		{Module: "key-vault", Until: "2030-01-01"},
+		{Module: "projects/*", Until: "2030-01-01"},
+	}}
+
+	tests := []struct {
+		name, path string
+		want       bool
+	}{
+		{"key-vault", "components/azurerm/key-vault", true},
+		{"prod-infra", "projects/prod-infra", true},
+		{"storage-account", "components/azurerm/storage-account", false},
+	}
+	for _, tt := range tests {
+		if got := tc.Quarantined(tt.name, tt.path, time.Date(2029, 6, 1, 0, 0, 0, 0, time.UTC)) != nil; got != tt.want {
+			t.Errorf("Quarantined(%s, %s) = %v, want %v", tt.name, tt.path, got, tt.want)
+		}
+	}
+
+	var nilConfig *TestConfig
+	if nilConfig.Quarantined("vnet", "components/vnet", time.Now()) != nil {
+		t.Error("nil TestConfig should not quarantine anything")
+	}
+}
+
+func TestTestConfig_QuarantinedPrefersActiveEntries(t *testing.T) {
+	tc := &TestConfig{Quarantine: []QuarantineEntry{
+		{Module: "key-vault", Until: "2030-01-31", Reason: "old"},
+		{Module: "components/*", Until: "2030-03-31", Reason: "current"},
+	}}
+
+	if got := tc.Quarantined("key-vault", "components/key-vault", time.Date(2030, 2, 10, 0, 0, 0, 0, time.UTC)); got == nil || got.Reason != "current" {
+		t.Errorf("Quarantined() = %+v, want the active entry", got)
+	}
+	// With every entry expired, the first one is returned for the expiry note
+	if got := tc.Quarantined("key-vault", "components/key-vault", time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)); got == nil || got.Reason != "old" {
+		t.Errorf("Quarantined() = %+v, want the first expired entry", got)
+	}
+}
+
+func TestLoad_TestQuarantine(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			content: `test:
+  quarantine:
+    - module: key-vault
+      until: 2030-01-31
+      reason: "flaky soft-delete purge, see #123"
+`,
+		},
+		{
+			name: "missing until",
+			content: `test:
+  quarantine:
+    - module: key-vault
+`,
+			wantErr: "until is required",
+		},
+		{
+			name: "invalid date",
+			content: `test:
+  quarantine:
+    - module: key-vault
+      until: 31/01/2030
+`,
+			wantErr: "invalid date",
+		},
+		{
+			name: "missing module",
+			content: `test:
+  quarantine:
+    - until: 2030-01-31
+`,
+			wantErr: "module is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+				t.Fatalf("failed to create .git directory: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create config file: %v", err)
+			}
+
+			cfg, err := Load(tmpDir, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(cfg.Test.Quarantine) != 1 || cfg.Test.Quarantine[0].Reason == "" {
+				t.Errorf("Quarantine = %+v, want one entry with a reason", cfg.Test.Quarantine)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// GetRoots returns the module roots, slash-separated paths relative to Root,
+// or nil if roots isn't set and modules are discovered in Root itself.
+func (c *Config) GetRoots() []string {
+	if c == nil {
+		return nil
+	}
+	return c.Roots
+}
+
+// validateRoots checks that every root is a distinct directory inside Root
+// and that no root is inside another, so every module has a single root.
+func validateRoots(roots []string) error {
+	if err := validateManagedPaths(roots); err != nil {
+		return err
+	}
+	for i, root := range roots {
+		clean := path.Clean(filepath.ToSlash(root))
+		if strings.Contains(clean, "*") {
+			return fmt.Errorf("'%s' must be a directory, not a pattern", root)
+		}
+		if clean == "." {
+			return fmt.Errorf("'%s' is root itself: list the directories below it", root)
+		}
+		for _, other := range roots[:i] {
+			other = path.Clean(filepath.ToSlash(other))
+			switch {
+			case other == clean:
+				return fmt.Errorf("'%s' is listed twice", root)
+			case strings.HasPrefix(clean, other+"/") || strings.HasPrefix(other, clean+"/"):
+				return fmt.Errorf("'%s' and '%s' are nested: roots must not contain each other", other, clean)
+			}
+		}
+		roots[i] = clean
+	}
+	return nil
+}
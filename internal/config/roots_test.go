@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad_Roots(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte("roots: [iac/platform, ./iac/apps/]\n"), 0644); err != nil {
+		t.Fatalf("failed to create config file: %v", err)
+	}
+
+	cfg, err := Load(tmpDir, "")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.GetRoots(); !reflect.DeepEqual(got, []string{"iac/platform", "iac/apps"}) {
+		t.Errorf("GetRoots() = %v, want [iac/platform iac/apps]", got)
+	}
+	if (*Config)(nil).GetRoots() != nil {
+		t.Error("expected no roots for nil config")
+	}
+}
+
+func TestLoad_InvalidRoots(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{"roots: [/iac]", "invalid roots in config: '/iac' must be relative to root"},
+		{"roots: [../iac]", "invalid roots in config: '../iac' must be inside root"},
+		{"roots: ['.']", "invalid roots in config: '.' is root itself"},
+		{"roots: ['iac/*']", "invalid roots in config: 'iac/*' must be a directory, not a pattern"},
+		{"roots: [iac, iac/]", "invalid roots in config: 'iac/' is listed twice"},
+		{"roots: [iac, iac/apps]", "invalid roots in config: 'iac' and 'iac/apps' are nested"},
+	}
+	for _, tt := range tests {
+		tmpDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmpDir, ".motf.yml"), []byte(tt.config+"\n"), 0644); err != nil {
+			t.Fatalf("failed to create config file: %v", err)
+		}
+
+		_, err := Load(tmpDir, "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.config, tt.want, err)
+		}
+	}
+}
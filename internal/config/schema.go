@@ -0,0 +1,263 @@
+package config
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaJSON is the JSON schema of .motf.yml
+//
+//go:embed schema.json
+var schemaJSON []byte
+
+// Schema returns the JSON schema of .motf.yml, for editor integration.
+func Schema() []byte {
+	return slices.Clone(schemaJSON)
+}
+
+// schema is the subset of JSON schema used by schema.json
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Enum                 []string           `json:"enum"`
+	Minimum              *float64           `json:"minimum"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"` // false, or the schema of other properties
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	AnyOf                []*schema          `json:"anyOf"`
+	Defs                 map[string]*schema `json:"$defs"`
+}
+
+// SchemaError is a problem with a value in a config file
+type SchemaError struct {
+	Line    int
+	Column  int
+	Path    string // Key path of the value, e.g. test.engine; empty for the top level
+	Message string
+}
+
+// Error returns e.g. "test.engine: must be 'terratest' or 'terraform', got 'foo'".
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// CheckSchema checks the YAML of a config file against the schema and
+// returns every problem found, ordered by position. Empty values are
+// accepted anywhere, like a key that isn't set. It returns an error when
+// data isn't valid YAML.
+func CheckSchema(data []byte) ([]SchemaError, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	var root schema
+	if err := json.Unmarshal(schemaJSON, &root); err != nil {
+		return nil, fmt.Errorf("invalid embedded schema: %w", err)
+	}
+	v := schemaValidator{defs: root.Defs}
+	errs := v.check(doc.Content[0], &root, "")
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs, nil
+}
+
+// schemaValidator checks YAML nodes against a schema
+type schemaValidator struct {
+	defs map[string]*schema
+}
+
+// resolve returns the schema s refers to, or s itself.
+func (v schemaValidator) resolve(s *schema) *schema {
+	for s.Ref != "" {
+		def, ok := v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			panic(fmt.Sprintf("schema.json: unknown $ref '%s'", s.Ref))
+		}
+		s = def
+	}
+	return s
+}
+
+// check returns the problems of node, at path, with schema s.
+func (v schemaValidator) check(node *yaml.Node, s *schema, path string) []SchemaError {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+	s = v.resolve(s)
+	fail := func(format string, args ...any) []SchemaError {
+		return []SchemaError{{Line: node.Line, Column: node.Column, Path: path, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if len(s.AnyOf) > 0 {
+		return v.checkAnyOf(node, s, path)
+	}
+	if len(s.Enum) > 0 {
+		if node.Kind != yaml.ScalarNode || !slices.Contains(s.Enum, node.Value) {
+			return fail("must be %s, got %s", quotedJoin(s.Enum), describeNode(node))
+		}
+		return nil
+	}
+	if s.Type != "" && !nodeHasType(node, s.Type) {
+		return fail("must be %s, got %s", typeNames[s.Type], describeNode(node))
+	}
+	if s.Minimum != nil {
+		if n, err := strconv.ParseFloat(node.Value, 64); err == nil && n < *s.Minimum {
+			return fail("must be %g or more, got %s", *s.Minimum, node.Value)
+		}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		return v.checkMapping(node, s, path)
+	case yaml.SequenceNode:
+		if s.Items == nil {
+			return nil
+		}
+		var errs []SchemaError
+		for i, item := range node.Content {
+			errs = append(errs, v.check(item, s.Items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	}
+	return nil
+}
+
+// checkMapping returns the problems of the keys and values of a mapping.
+func (v schemaValidator) checkMapping(node *yaml.Node, s *schema, path string) []SchemaError {
+	var additional *schema
+	allowAdditional := true
+	if len(s.AdditionalProperties) > 0 {
+		if string(s.AdditionalProperties) == "false" {
+			allowAdditional = false
+		} else if err := json.Unmarshal(s.AdditionalProperties, &additional); err != nil {
+			panic(fmt.Sprintf("schema.json: invalid additionalProperties at %s: %v", path, err))
+		}
+	}
+
+	var errs []SchemaError
+	seen := make(map[string]bool, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		seen[key.Value] = true
+		keyPath := key.Value
+		if path != "" {
+			keyPath = path + "." + key.Value
+		}
+
+		switch prop, ok := s.Properties[key.Value]; {
+		case ok:
+			errs = append(errs, v.check(value, prop, keyPath)...)
+		case additional != nil:
+			errs = append(errs, v.check(value, additional, keyPath)...)
+		case !allowAdditional:
+			errs = append(errs, SchemaError{Line: key.Line, Column: key.Column, Path: path,
+				Message: fmt.Sprintf("unknown key '%s'", key.Value)})
+		}
+	}
+	for _, name := range s.Required {
+		if !seen[name] {
+			errs = append(errs, SchemaError{Line: node.Line, Column: node.Column, Path: path,
+				Message: fmt.Sprintf("missing required key '%s'", name)})
+		}
+	}
+	return errs
+}
+
+// checkAnyOf returns nothing when node matches one of the alternatives of s,
+// and otherwise the problems of the closest alternative of the node's type.
+func (v schemaValidator) checkAnyOf(node *yaml.Node, s *schema, path string) []SchemaError {
+	var closest []SchemaError
+	var expected []string
+	for _, alt := range s.AnyOf {
+		alt = v.resolve(alt)
+		errs := v.check(node, alt, path)
+		if len(errs) == 0 {
+			return nil
+		}
+		if !nodeHasType(node, schemaType(alt)) {
+			expected = append(expected, typeNames[schemaType(alt)])
+			continue
+		}
+		if closest == nil || len(errs) < len(closest) {
+			closest = errs
+		}
+	}
+	if closest != nil {
+		return closest
+	}
+	return []SchemaError{{Line: node.Line, Column: node.Column, Path: path,
+		Message: fmt.Sprintf("must be %s, got %s", strings.Join(slices.Compact(expected), " or "), describeNode(node))}}
+}
+
+// schemaType returns the type of s; a schema with an enum is a string.
+func schemaType(s *schema) string {
+	if s.Type == "" && len(s.Enum) > 0 {
+		return "string"
+	}
+	return s.Type
+}
+
+// typeNames are the descriptions of schema types in messages, in YAML terms
+var typeNames = map[string]string{
+	"object":  "a mapping",
+	"array":   "a list",
+	"string":  "a string",
+	"integer": "a whole number",
+	"number":  "a number",
+	"boolean": "true or false",
+}
+
+// nodeHasType reports whether node is a value of the schema type t.
+func nodeHasType(node *yaml.Node, t string) bool {
+	switch t {
+	case "":
+		return true
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	case "string":
+		// Unquoted dates like 2024-06-30 are read as strings too
+		return node.Kind == yaml.ScalarNode && (node.Tag == "!!str" || node.Tag == "!!timestamp")
+	case "integer":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!int"
+	case "number":
+		return node.Kind == yaml.ScalarNode && (node.Tag == "!!int" || node.Tag == "!!float")
+	case "boolean":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!bool"
+	}
+	return false
+}
+
+// describeNode returns node for a message, e.g. 'foo' or a list.
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	return "'" + node.Value + "'"
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/backend"
+	"github.com/TechnicallyJoe/terraform-motf/internal/checks"
+	"github.com/TechnicallyJoe/terraform-motf/internal/release"
+	"github.com/TechnicallyJoe/terraform-motf/internal/security"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
+)
+
+func TestCheckSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []string // line:column: error
+	}{
+		{
+			name: "valid",
+			yaml: `root: iac
+binary: tofu
+module_dirs: {modules: component, stacks: project}
+test:
+  engine: terraform
+  retries: 2
+  quarantine:
+    - {module: network, until: 2024-06-30}
+tasks:
+  lint:
+    shell: bash
+    steps:
+      - init
+      - terraform: validate
+        args: [-no-color]
+      - run: tflint
+parallelism:
+  max_jobs: 4
+timeouts:
+  plan: 15m
+`,
+		},
+		{
+			name: "empty values",
+			yaml: "test:\ntasks:\nmodule_dirs:\n",
+		},
+		{
+			name: "unknown keys",
+			yaml: "binry: tofu\ntest:\n  engin: terraform\n",
+			want: []string{"1:1: unknown key 'binry'", "3:3: test: unknown key 'engin'"},
+		},
+		{
+			name: "wrong types",
+			yaml: "root: [iac]\nparallelism:\n  max_jobs: many\nrelease:\n  push: yes please\n",
+			want: []string{
+				"1:7: root: must be a string, got a list",
+				"3:13: parallelism.max_jobs: must be a whole number, got 'many'",
+				"5:9: release.push: must be true or false, got 'yes please'",
+			},
+		},
+		{
+			name: "invalid engine and shell",
+			yaml: "test:\n  engine: pytest\ntasks:\n  lint:\n    shell: zsh\n    command: tflint\n",
+			want: []string{
+				"2:11: test.engine: must be 'terratest', 'terraform', 'tofu', or 'compliance', got 'pytest'",
+				"5:12: tasks.lint.shell: must be 'bash', 'cmd', 'pwsh', or 'sh', got 'zsh'",
+			},
+		},
+		{
+			name: "invalid steps",
+			yaml: "tasks:\n  ci:\n    steps:\n      - deploy\n      - run: tflint\n        shel: bash\n",
+			want: []string{
+				"4:9: tasks.ci.steps[0]: must be 'init', 'fmt', 'validate', 'plan', or 'test', got 'deploy'",
+				"6:9: tasks.ci.steps[1]: unknown key 'shel'",
+			},
+		},
+		{
+			name: "list or mapping",
+			yaml: "module_dirs: modules\n",
+			want: []string{"1:14: module_dirs: must be a list or a mapping, got 'modules'"},
+		},
+		{
+			name: "missing required key and minimum",
+			yaml: "test:\n  retries: -1\n  quarantine:\n    - module: network\n",
+			want: []string{
+				"2:12: test.retries: must be 0 or more, got -1",
+				"4:7: test.quarantine[0]: missing required key 'until'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := CheckSchema([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("CheckSchema() error = %v", err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, formatSchemaError(e))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("CheckSchema() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func formatSchemaError(e SchemaError) string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Error())
+}
+
+func TestCheckSchema_InvalidYAML(t *testing.T) {
+	if _, err := CheckSchema([]byte("binary: [tofu\n")); err == nil {
+		t.Error("expected an error for invalid YAML")
+	}
+}
+
+// loadSchema returns schema.json, with $refs resolved by its validator.
+func loadSchema(t *testing.T) (*schema, schemaValidator) {
+	t.Helper()
+	var root schema
+	if err := json.Unmarshal(Schema(), &root); err != nil {
+		t.Fatalf("schema.json is not valid JSON: %v", err)
+	}
+	return &root, schemaValidator{defs: root.Defs}
+}
+
+// TestSchema_CoversConfig checks that every key of Config and its sections
+// is in the schema, so the schema doesn't fall behind new settings.
+func TestSchema_CoversConfig(t *testing.T) {
+	root, v := loadSchema(t)
+
+	var walk func(typ reflect.Type, s *schema, path string)
+	walk = func(typ reflect.Type, s *schema, path string) {
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if key == "" || key == "-" {
+				continue
+			}
+			prop, ok := s.Properties[key]
+			if !ok {
+				t.Errorf("schema.json has no property %s%s", path, key)
+				continue
+			}
+			prop = v.resolve(prop)
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && len(prop.Properties) > 0 {
+				walk(ft, prop, path+key+".")
+			}
+		}
+	}
+	walk(reflect.TypeOf(Config{}), root, "")
+	walk(reflect.TypeOf(tasks.TaskConfig{}), v.resolve(&schema{Ref: "#/$defs/task"}), "tasks.<name>.")
+}
+
+// TestSchema_EnumsMatchCode checks that the allowed values in the schema are
+// the ones motf accepts.
+func TestSchema_EnumsMatchCode(t *testing.T) {
+	root, v := loadSchema(t)
+	prop := func(path ...string) *schema {
+		s := root
+		for _, p := range path {
+			if p == "[]" {
+				s = v.resolve(s.Items)
+				continue
+			}
+			s = v.resolve(s.Properties[p])
+		}
+		return s
+	}
+	keys := func(s *schema) []string {
+		var names []string
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		return names
+	}
+	steps := v.resolve(&schema{Ref: "#/$defs/step"})
+
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"binary", prop("binary").Enum, ValidBinaryNames()},
+		{"test.engine", prop("test", "engine").Enum, ValidTestEngineNames()},
+		{"security.scanner", prop("security", "scanner").Enum, security.Names()},
+		{"release.skip", prop("release", "skip", "[]").Enum, release.Steps},
+		{"checks", prop("checks", "component", "[]").Enum, checks.Names()},
+		{"watch.command", prop("watch", "command").Enum, WatchCommands},
+		{"backend.type", prop("backend", "type").Enum, backend.Types()},
+		{"tasks shell", v.resolve(&schema{Ref: "#/$defs/shell"}).Enum, tasks.SupportedShells()},
+		{"tasks steps", steps.AnyOf[0].Enum, tasks.BuiltinSteps()},
+		{"hooks", keys(prop("hooks")), HookNames},
+		{"timeouts", keys(prop("timeouts")), append([]string{TimeoutDefault}, TimeoutCommands...)},
+	}
+	for _, tt := range tests {
+		got, want := slices.Sorted(slices.Values(tt.got)), slices.Sorted(slices.Values(tt.want))
+		if !slices.Equal(got, want) {
+			t.Errorf("schema.json %s = %v, want %v", tt.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// TimeoutDefault is the timeouts key that applies to commands without their own
+const TimeoutDefault = "default"
+
+// TimeoutCommands are the commands a timeout can be set for, besides default
+var TimeoutCommands = []string{"init", "fmt", "validate", "plan", "apply", "test"}
+
+// Timeout returns how long command (e.g. "plan") may run in a module: its
+// timeouts entry, or otherwise the default entry. It returns 0, meaning no
+// limit, when neither is set.
+func (c *Config) Timeout(command string) time.Duration {
+	value, ok := c.Timeouts[command]
+	if !ok {
+		value = c.Timeouts[TimeoutDefault]
+	}
+	// Timeouts are validated when the config is loaded
+	d, _ := time.ParseDuration(value)
+	return d
+}
+
+// MaxTimeout returns the longest timeout of any command, used as a hint of
+// how heavy a module is.
+func (c *Config) MaxTimeout() time.Duration {
+	var longest time.Duration
+	for _, value := range c.Timeouts {
+		d, _ := time.ParseDuration(value)
+		longest = max(longest, d)
+	}
+	return longest
+}
+
+// validateTimeouts checks that each timeout is for a known command and is a
+// positive Go duration, e.g. "15m" or "1h30m".
+func validateTimeouts(timeouts map[string]string) error {
+	keys := make([]string, 0, len(timeouts))
+	for key := range timeouts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key != TimeoutDefault && !slices.Contains(TimeoutCommands, key) {
+			return fmt.Errorf("unknown command '%s': must be %s", key, quotedJoin(append([]string{TimeoutDefault}, TimeoutCommands...)))
+		}
+		d, err := time.ParseDuration(timeouts[key])
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%s: invalid duration '%s', expected e.g. 15m or 1h30m", key, timeouts[key])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig_Timeout(t *testing.T) {
+	cfg := &Config{Timeouts: map[string]string{"default": "5m", "plan": "15m"}}
+
+	tests := []struct {
+		command string
+		want    time.Duration
+	}{
+		{"plan", 15 * time.Minute},
+		{"init", 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := cfg.Timeout(tt.command); got != tt.want {
+			t.Errorf("Timeout(%q) = %s, want %s", tt.command, got, tt.want)
+		}
+	}
+	if got := cfg.MaxTimeout(); got != 15*time.Minute {
+		t.Errorf("MaxTimeout() = %s, want 15m", got)
+	}
+
+	if got := (&Config{}).Timeout("plan"); got != 0 {
+		t.Errorf("expected no timeout without config, got %s", got)
+	}
+}
+
+func TestValidateTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts map[string]string
+		wantErr  string
+	}{
+		{"valid", map[string]string{"default": "5m", "plan": "1h30m", "test": "45m"}, ""},
+		{"unknown command", map[string]string{"destroy": "5m"}, "unknown command 'destroy'"},
+		{"not a duration", map[string]string{"plan": "15"}, "plan: invalid duration '15'"},
+		{"zero", map[string]string{"default": "0s"}, "default: invalid duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTimeouts(tt.timeouts)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateTimeouts() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateTimeouts() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ForModule_Timeouts(t *testing.T) {
+	root := t.TempDir()
+	moduleDir := filepath.Join(root, "projects", "aks")
+	writeModuleConfig(t, moduleDir, "timeouts:\n  plan: 15m\n")
+
+	cfg := &Config{Root: root, Binary: "terraform", Timeouts: map[string]string{"default": "5m", "plan": "5m"}}
+	modCfg, err := cfg.ForModule(moduleDir)
+	if err != nil {
+		t.Fatalf("ForModule() error = %v", err)
+	}
+	if got := modCfg.Timeout("plan"); got != 15*time.Minute {
+		t.Errorf("plan timeout = %s, want the module's 15m", got)
+	}
+	if got := modCfg.Timeout("init"); got != 5*time.Minute {
+		t.Errorf("init timeout = %s, want the root default 5m", got)
+	}
+	if cfg.Timeouts["plan"] != "5m" {
+		t.Errorf("expected the root config to be unchanged, got %v", cfg.Timeouts)
+	}
+}
+
+func TestLoadModuleConfig_InvalidTimeouts(t *testing.T) {
+	path := writeModuleConfig(t, t.TempDir(), "timeouts:\n  plan: soon\n")
+
+	_, err := LoadModuleConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "invalid timeouts") {
+		t.Fatalf("expected invalid timeouts error, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+// Package configgen proposes and renders a .motf.yml for a repository, for
+// `motf config init`.
+package configgen
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// defaultModuleDirs are the names of config.DefaultModuleDirs
+var defaultModuleDirs = func() []string {
+	var names []string
+	for _, d := range config.DefaultModuleDirs {
+		names = append(names, d.Dir)
+	}
+	return names
+}()
+
+// skipDirs are never searched for modules
+var skipDirs = []string{".terraform", "node_modules", "vendor"}
+
+// maxDepth is how deep below the repository root the default module
+// directories are looked for
+const maxDepth = 3
+
+// Options are the settings written to the config file.
+type Options struct {
+	Root       string   // Slash-separated, relative to the config file; "" for its directory
+	Binary     string   // terraform or tofu
+	ModuleDirs []string // Empty for the default module directories
+}
+
+// Detect proposes the root and module directories of the repository at
+// repoRoot from its layout. The shallowest directory with components/,
+// bases/, or projects/ becomes the root. Otherwise the directories with
+// Terraform files become module directories, in the directory the modules
+// are in; a repository without Terraform files gets the defaults.
+func Detect(repoRoot string) (Options, error) {
+	if root, ok, err := findDefaultLayout(repoRoot); err != nil || ok {
+		return Options{Root: root}, err
+	}
+
+	// Descend through directories that only hold other directories with
+	// modules, e.g. terraform/ in terraform/modules and terraform/live
+	dir, rel := repoRoot, ""
+	for {
+		if hasTerraformFiles(dir) {
+			return Options{}, nil
+		}
+		dirs, err := terraformDirs(dir)
+		if err != nil {
+			return Options{}, err
+		}
+		switch len(dirs) {
+		case 0:
+			return Options{}, nil
+		case 1:
+		default:
+			return Options{Root: rel, ModuleDirs: dirs}, nil
+		}
+		dir, rel = filepath.Join(dir, dirs[0]), path.Join(rel, dirs[0])
+	}
+}
+
+// findDefaultLayout returns the shallowest directory below repoRoot, up to
+// maxDepth, that contains one of defaultModuleDirs, relative to repoRoot.
+func findDefaultLayout(repoRoot string) (string, bool, error) {
+	level := []string{""}
+	for depth := 0; depth < maxDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, rel := range level {
+			entries, err := os.ReadDir(filepath.Join(repoRoot, filepath.FromSlash(rel)))
+			if err != nil {
+				return "", false, fmt.Errorf("failed to read %s: %w", filepath.Join(repoRoot, rel), err)
+			}
+			for _, e := range entries {
+				if !e.IsDir() || skipped(e.Name()) {
+					continue
+				}
+				if slices.Contains(defaultModuleDirs, e.Name()) {
+					return rel, true, nil
+				}
+				next = append(next, path.Join(rel, e.Name()))
+			}
+		}
+		level = next
+	}
+	return "", false, nil
+}
+
+// terraformDirs returns the names of the directories in dir that contain
+// Terraform files at any depth, sorted.
+func terraformDirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	var dirs []string
+	for _, e := range entries {
+		if !e.IsDir() || skipped(e.Name()) {
+			continue
+		}
+		found := false
+		err := filepath.WalkDir(filepath.Join(dir, e.Name()), func(p string, d os.DirEntry, err error) error {
+			switch {
+			case err != nil:
+				return err
+			case d.IsDir() && skipped(d.Name()):
+				return filepath.SkipDir
+			case !d.IsDir() && isTerraformFile(d.Name()):
+				found = true
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", e.Name(), err)
+		}
+		if found {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	return dirs, nil
+}
+
+// hasTerraformFiles reports whether dir directly contains Terraform files.
+func hasTerraformFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !e.IsDir() && isTerraformFile(e.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTerraformFile reports whether name is a .tf or .tf.json file
+func isTerraformFile(name string) bool {
+	return strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")
+}
+
+// skipped reports whether the directory name is never searched: hidden
+// directories and skipDirs.
+func skipped(name string) bool {
+	return strings.HasPrefix(name, ".") || slices.Contains(skipDirs, name)
+}
+
+// Render returns a commented .motf.yml with opts, and the other common
+// settings commented out with their defaults.
+func Render(opts Options) string {
+	var b strings.Builder
+	b.WriteString("# motf configuration. See https://github.com/TechnicallyJoe/terraform-motf/wiki/configuration\n\n")
+
+	b.WriteString("# Directory containing the module directories, relative to this file\n")
+	if opts.Root == "" {
+		b.WriteString("# root: \"\"\n\n")
+	} else {
+		fmt.Fprintf(&b, "root: %s\n\n", opts.Root)
+	}
+
+	b.WriteString("# Terraform binary to use: \"terraform\", \"tofu\", or \"auto\" for whichever is installed\n")
+	fmt.Fprintf(&b, "binary: %s\n\n", opts.Binary)
+
+	b.WriteString("# Directories under root that modules are discovered in\n")
+	if len(opts.ModuleDirs) == 0 || slices.Equal(opts.ModuleDirs, defaultModuleDirs) {
+		fmt.Fprintf(&b, "# module_dirs: [%s]\n\n", strings.Join(defaultModuleDirs, ", "))
+	} else {
+		fmt.Fprintf(&b, "module_dirs: [%s]\n\n", strings.Join(opts.ModuleDirs, ", "))
+	}
+
+	b.WriteString(`# Maximum parallel jobs of --parallel runs; 0 is the number of CPU cores
+# parallelism:
+#   max_jobs: 0
+
+# Custom tasks run with 'motf task <name>'
+# tasks:
+#   lint:
+#     description: "Run tflint on the module"
+#     command: "tflint --init && tflint"
+`)
+	return b.String()
+}
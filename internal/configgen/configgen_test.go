@@ -0,0 +1,120 @@
+package configgen
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// writeModules creates a main.tf in each slash-separated directory under root.
+func writeModules(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		path := filepath.Join(root, filepath.FromSlash(dir))
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "main.tf"), []byte(`variable "name" {}`), 0644); err != nil {
+			t.Fatalf("failed to write main.tf: %v", err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		modules []string
+		want    Options
+	}{
+		{
+			name:    "default layout at the repository root",
+			modules: []string{"components/network", "projects/prod"},
+			want:    Options{},
+		},
+		{
+			name:    "default layout below the repository root",
+			modules: []string{"iac/components/network", "iac/bases/platform"},
+			want:    Options{Root: "iac"},
+		},
+		{
+			name:    "module directories",
+			modules: []string{"modules/network", "live/prod", ".terraform/modules/cached"},
+			want:    Options{ModuleDirs: []string{"live", "modules"}},
+		},
+		{
+			name:    "module directories below a single directory",
+			modules: []string{"terraform/modules/network", "terraform/stacks/prod"},
+			want:    Options{Root: "terraform", ModuleDirs: []string{"modules", "stacks"}},
+		},
+		{
+			name:    "module at the repository root",
+			modules: []string{"", "modules/network"},
+			want:    Options{},
+		},
+		{
+			name: "no modules",
+			want: Options{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeModules(t, root, tt.modules...)
+
+			got, err := Detect(root)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if got.Root != tt.want.Root || !slices.Equal(got.ModuleDirs, tt.want.ModuleDirs) {
+				t.Errorf("Detect() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_LoadsAsConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           Options
+		wantModuleDirs []string
+	}{
+		{name: "defaults", opts: Options{Binary: "terraform"}, wantModuleDirs: []string{"components", "bases", "projects"}},
+		{name: "custom", opts: Options{Root: "terraform", Binary: "tofu", ModuleDirs: []string{"modules", "stacks"}}, wantModuleDirs: []string{"modules", "stacks"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			content := Render(tt.opts)
+			if !strings.HasPrefix(content, "# motf configuration.") {
+				t.Errorf("expected a header comment, got:\n%s", content)
+			}
+			if err := os.WriteFile(filepath.Join(dir, config.ConfigFile), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := config.Load(dir, "")
+			if err != nil {
+				t.Fatalf("rendered config doesn't load: %v\n%s", err, content)
+			}
+			if want := filepath.Join(dir, tt.opts.Root); cfg.Root != want || cfg.Binary != tt.opts.Binary {
+				t.Errorf("loaded root %q and binary %q, want %q and %q", cfg.Root, cfg.Binary, want, tt.opts.Binary)
+			}
+			var dirs []string
+			for _, d := range cfg.GetModuleDirs() {
+				dirs = append(dirs, d.Dir)
+			}
+			if !slices.Equal(dirs, tt.wantModuleDirs) {
+				t.Errorf("loaded module_dirs %v, want %v", dirs, tt.wantModuleDirs)
+			}
+		})
+	}
+}
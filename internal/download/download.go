@@ -0,0 +1,31 @@
+// Package download holds what downloading release archives from the internet
+// needs everywhere, e.g. for `motf upgrade` and `motf toolchain install`:
+// checking an archive against the checksums published with it.
+package download
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Verify checks the SHA-256 of archive against its line in sums, a
+// SHA256SUMS file with a "<hex sum>  <file name>" line per file.
+func Verify(archive, sums []byte, name string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[1] != name {
+			continue
+		}
+		sum := sha256.Sum256(archive)
+		if hex.EncodeToString(sum[:]) != fields[0] {
+			return fmt.Errorf("checksum mismatch of %s", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum for %s", name)
+}
@@ -0,0 +1,39 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	archive := []byte("archive")
+	sum := sha256.Sum256(archive)
+	sums := []byte("0000  other.zip\n" + hex.EncodeToString(sum[:]) + "  motf.zip\n")
+
+	tests := []struct {
+		name    string
+		archive []byte
+		file    string
+		wantErr string
+	}{
+		{name: "matches", archive: archive, file: "motf.zip"},
+		{name: "mismatch", archive: []byte("tampered"), file: "motf.zip", wantErr: "checksum mismatch of motf.zip"},
+		{name: "missing", archive: archive, file: "motf.tar.gz", wantErr: "no checksum for motf.tar.gz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.archive, sums, tt.file)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Verify() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+// Package downloadtest fakes the servers downloads are made from in tests.
+package downloadtest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// roundTripFunc serves HTTP requests without a network.
+type roundTripFunc func(*http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req), nil }
+
+// Client returns an HTTP client whose requests are answered from files, by
+// URL; other URLs are not found. Each request is passed to seen first, if set.
+func Client(files map[string][]byte, seen func(*http.Request)) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) *http.Response {
+		if seen != nil {
+			seen(req)
+		}
+		body, ok := files[req.URL.String()]
+		if !ok {
+			return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: io.NopCloser(strings.NewReader(""))}
+		}
+		return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(bytes.NewReader(body))}
+	})}
+}
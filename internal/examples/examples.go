@@ -0,0 +1,177 @@
+// Package examples checks that the examples of a module still call it
+// correctly: with a source that points at it, every required variable, and
+// no variables it doesn't declare, for `motf check examples`.
+package examples
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/sources"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Dir is the directory of a module that holds its examples
+const Dir = "examples"
+
+// metaArguments are module block arguments that aren't variables
+var metaArguments = []string{"source", "version", "providers", "count", "for_each", "depends_on"}
+
+// moduleSchema selects the module blocks of a file
+var moduleSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{{Type: "module", LabelNames: []string{"name"}}},
+}
+
+// Problem is a way an example is out of sync with its module.
+type Problem struct {
+	File   string `json:"file"` // Slash-separated, relative to the module directory; the example directory when Module is empty
+	Line   int    `json:"line,omitempty"`
+	Module string `json:"module,omitempty"` // Name of the module block, empty when no block calls the module
+	Reason string `json:"reason"`
+}
+
+// String returns e.g. "examples/basic/main.tf:3: module.storage: unknown variable sku".
+func (p Problem) String() string {
+	if p.Module == "" {
+		return fmt.Sprintf("%s: %s", p.File, p.Reason)
+	}
+	return fmt.Sprintf("%s:%d: module.%s: %s", p.File, p.Line, p.Module, p.Reason)
+}
+
+// List returns the names of the examples of the module at moduleDir: the
+// directories in its examples directory with .tf or .tf.json files, sorted.
+func List(moduleDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(moduleDir, Dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && finder.HasTerraformFiles(filepath.Join(moduleDir, Dir, entry.Name())) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Check returns the problems of the example name of the module at
+// moduleDir, whose variables are given. Module blocks with a local source
+// that points at the module are checked for missing required variables and
+// unknown variables. Local sources that don't resolve are outdated, and an
+// example without any block calling the module is reported. Module blocks
+// calling other modules are left alone.
+func Check(moduleDir, name string, variables []terraform.VariableInfo) ([]Problem, error) {
+	exampleDir := filepath.Join(moduleDir, Dir, name)
+	want, err := filepath.Rel(exampleDir, moduleDir)
+	if err != nil {
+		return nil, err
+	}
+	want = filepath.ToSlash(want)
+
+	entries, err := os.ReadDir(exampleDir)
+	if err != nil {
+		return nil, err
+	}
+	var problems []Problem
+	calls := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !finder.IsTerraformFile(entry.Name()) {
+			continue
+		}
+		file := path.Join(Dir, name, entry.Name())
+		f, err := sources.ParseFile(filepath.Join(exampleDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+
+		content, _, _ := f.Body.PartialContent(moduleSchema)
+		for _, block := range content.Blocks {
+			attrs, _ := block.Body.JustAttributes()
+			source, ok := stringValue(attrs["source"])
+			if !ok || !sources.IsLocal(source) {
+				continue
+			}
+			target := filepath.Join(exampleDir, filepath.FromSlash(source))
+			switch {
+			case target == filepath.Clean(moduleDir):
+				calls++
+				problems = append(problems, checkCall(file, block, attrs, variables)...)
+			case !finder.HasTerraformFiles(target):
+				problems = append(problems, Problem{
+					File:   file,
+					Line:   attrs["source"].Range.Start.Line,
+					Module: block.Labels[0],
+					Reason: fmt.Sprintf("source %q doesn't resolve, the module is at %q", source, want),
+				})
+				calls++
+			}
+		}
+	}
+
+	if calls == 0 {
+		problems = append(problems, Problem{
+			File:   path.Join(Dir, name),
+			Reason: fmt.Sprintf("no module block calls the module with source = %q", want),
+		})
+	}
+	return problems, nil
+}
+
+// checkCall returns the required variables that the module block doesn't
+// set, and the arguments it sets that aren't variables.
+func checkCall(file string, block *hcl.Block, attrs hcl.Attributes, variables []terraform.VariableInfo) []Problem {
+	var problems []Problem
+	declared := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		declared[v.Name] = true
+	}
+
+	var unknown []*hcl.Attribute
+	for argName, attr := range attrs {
+		if !declared[argName] && !slices.Contains(metaArguments, argName) {
+			unknown = append(unknown, attr)
+		}
+	}
+	slices.SortFunc(unknown, func(a, b *hcl.Attribute) int { return a.Range.Start.Byte - b.Range.Start.Byte })
+	for _, attr := range unknown {
+		problems = append(problems, Problem{
+			File:   file,
+			Line:   attr.Range.Start.Line,
+			Module: block.Labels[0],
+			Reason: fmt.Sprintf("unknown variable %s", attr.Name),
+		})
+	}
+
+	for _, v := range variables {
+		if _, ok := attrs[v.Name]; v.Required && !ok {
+			problems = append(problems, Problem{
+				File:   file,
+				Line:   block.DefRange.Start.Line,
+				Module: block.Labels[0],
+				Reason: fmt.Sprintf("missing required variable %s", v.Name),
+			})
+		}
+	}
+	return problems
+}
+
+// stringValue returns the value of attr if it's a literal string.
+func stringValue(attr *hcl.Attribute) (string, bool) {
+	if attr == nil {
+		return "", false
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.IsNull() || value.Type() != cty.String {
+		return "", false
+	}
+	return value.AsString(), true
+}
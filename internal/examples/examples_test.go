@@ -0,0 +1,101 @@
+package examples
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+var variables = []terraform.VariableInfo{
+	{Name: "location", Required: true},
+	{Name: "name", Required: true},
+	{Name: "sku"},
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "examples", "complete", "main.tf"), "")
+	writeFile(t, filepath.Join(dir, "examples", "basic", "main.tf.json"), "{}")
+	writeFile(t, filepath.Join(dir, "examples", "README.md"), "")
+	if err := os.MkdirAll(filepath.Join(dir, "examples", "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := List(dir)
+	if err != nil || strings.Join(names, ",") != "basic,complete" {
+		t.Errorf("List() = %v, %v", names, err)
+	}
+	if names, err := List(t.TempDir()); err != nil || names != nil {
+		t.Errorf("List() without examples = %v, %v", names, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.tf"), "")
+	writeFile(t, filepath.Join(dir, "examples", "basic", "main.tf"), `module "storage" {
+  source   = "../.."
+  location = "westeurope"
+  replicas = 2
+  count    = 1
+}
+
+module "naming" {
+  source = "../../../naming"
+}
+
+module "registry" {
+  source = "acme/storage/azurerm"
+}
+`)
+	writeFile(t, filepath.Join(dir, "examples", "old", "main.tf.json"), `{"module": {"storage": {"source": "../../../storage-account", "location": "x", "name": "y"}}}`)
+	writeFile(t, filepath.Join(dir, "examples", "valid", "main.tf"), `module "storage" {
+  source   = "../../"
+  location = "westeurope"
+  name     = "logs"
+}
+`)
+	writeFile(t, filepath.Join(dir, "examples", "remote", "main.tf"), `module "storage" {
+  source = "acme/storage/azurerm"
+}
+`)
+
+	tests := map[string][]string{
+		"basic": {
+			"examples/basic/main.tf:4: module.storage: unknown variable replicas",
+			"examples/basic/main.tf:1: module.storage: missing required variable name",
+			`examples/basic/main.tf:9: module.naming: source "../../../naming" doesn't resolve, the module is at "../.."`,
+		},
+		"old":    {`examples/old/main.tf.json:1: module.storage: source "../../../storage-account" doesn't resolve, the module is at "../.."`},
+		"valid":  nil,
+		"remote": {`examples/remote: no module block calls the module with source = "../.."`},
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			problems, err := Check(dir, name, variables)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			var got []string
+			for _, p := range problems {
+				got = append(got, p.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
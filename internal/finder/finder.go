@@ -32,8 +32,8 @@ func FindModule(searchPath, moduleName string) ([]string, error) {
 			return nil
 		}
 
-		// Skip excluded directories
-		if skipDirs[d.Name()] {
+		// Skip excluded directories, but not the search path itself
+		if path != searchPath && skipDirs[d.Name()] {
 			return filepath.SkipDir
 		}
 
@@ -55,6 +55,12 @@ func FindModule(searchPath, moduleName string) ([]string, error) {
 	return matches, nil
 }
 
+// IsTerraformFile reports whether a file name is a Terraform configuration
+// file in native (.tf) or JSON (.tf.json) syntax.
+func IsTerraformFile(name string) bool {
+	return filepath.Ext(name) == ".tf" || strings.HasSuffix(name, ".tf.json")
+}
+
 // HasTerraformFiles checks if a directory contains any .tf or .tf.json files
 func HasTerraformFiles(dir string) bool {
 	entries, err := os.ReadDir(dir)
@@ -67,8 +73,7 @@ func HasTerraformFiles(dir string) bool {
 			continue
 		}
 
-		name := entry.Name()
-		if filepath.Ext(name) == ".tf" || strings.HasSuffix(name, ".tf.json") {
+		if IsTerraformFile(entry.Name()) {
 			return true
 		}
 	}
@@ -90,8 +95,8 @@ func ListAllModules(searchPath string) (map[string]string, error) {
 			return nil
 		}
 
-		// Skip excluded directories
-		if skipDirs[d.Name()] {
+		// Skip excluded directories, but not the search path itself
+		if path != searchPath && skipDirs[d.Name()] {
 			return filepath.SkipDir
 		}
 
@@ -440,3 +440,32 @@ func TestFindModule_SkipsGitDir(t *testing.T) {
 		t.Errorf("expected match to be '%s', got '%s'", validModule, matches[0])
 	}
 }
+
+func TestListAllModules_SearchPathNamedLikeSkippedDir(t *testing.T) {
+	// A module directory configured as e.g. "modules" is searched, while
+	// nested modules directories are still skipped
+	searchPath := filepath.Join(t.TempDir(), "modules")
+	for _, path := range []string{
+		filepath.Join(searchPath, "storage"),
+		filepath.Join(searchPath, "storage", "modules", "nested"),
+	} {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatalf("failed to create module directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "main.tf"), []byte("# terraform"), 0644); err != nil {
+			t.Fatalf("failed to create main.tf: %v", err)
+		}
+	}
+
+	modules, err := ListAllModules(searchPath)
+	if err != nil {
+		t.Fatalf("ListAllModules returned error: %v", err)
+	}
+
+	if len(modules) != 1 {
+		t.Fatalf("expected 1 module, got %d: %v", len(modules), modules)
+	}
+	if _, exists := modules["storage"]; !exists {
+		t.Error("expected to find 'storage'")
+	}
+}
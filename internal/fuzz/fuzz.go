@@ -0,0 +1,298 @@
+// Package fuzz generates edge-case inputs for module variables and
+// classifies how a module responds to them.
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// BaselineCase is the name of the case that uses baseline values only
+const BaselineCase = "baseline"
+
+// longStringLength is the length of the generated long string
+const longStringLength = 4096
+
+// manyElements is the number of elements in the generated large collection
+const manyElements = 100
+
+// Case is a set of variable values to run the module with.
+type Case struct {
+	Name     string         `json:"name"`               // e.g. "name=empty-string" or "settings.size=negative"
+	Variable string         `json:"variable,omitempty"` // Variable under test; empty for the baseline
+	Vars     map[string]any `json:"vars"`               // Values for the module variables
+}
+
+// edge is a named edge-case value.
+type edge struct {
+	label string
+	value any
+}
+
+// Generate returns the baseline case followed by one case per variable and
+// edge value. Required variables get a baseline value that satisfies their
+// type; optional variables keep their default unless they are under test.
+func Generate(vars []terraform.VariableInfo) ([]Case, error) {
+	types := make(map[string]cty.Type, len(vars))
+	baseline := make(map[string]any)
+	for _, v := range vars {
+		ty, err := ParseType(v.Type)
+		if err != nil {
+			return nil, fmt.Errorf("variable '%s': %w", v.Name, err)
+		}
+		types[v.Name] = ty
+		if v.Required {
+			baseline[v.Name] = baselineValue(ty)
+		}
+	}
+
+	cases := []Case{{Name: BaselineCase, Vars: baseline}}
+	for _, v := range vars {
+		for _, e := range edges(types[v.Name], baselineValue(types[v.Name])) {
+			caseVars := make(map[string]any, len(baseline)+1)
+			for name, value := range baseline {
+				caseVars[name] = value
+			}
+			caseVars[v.Name] = e.value
+			cases = append(cases, Case{
+				Name:     caseName(v.Name, e.label),
+				Variable: v.Name,
+				Vars:     caseVars,
+			})
+		}
+	}
+	return cases, nil
+}
+
+// caseName joins a variable name and an edge label. Labels of nested
+// attributes ("size=negative") extend the variable path.
+func caseName(variable, label string) string {
+	if strings.Contains(label, "=") {
+		return variable + "." + label
+	}
+	return variable + "=" + label
+}
+
+// ParseType parses a variable type constraint such as "list(string)".
+// An empty type means any type.
+func ParseType(typeExpr string) (cty.Type, error) {
+	if strings.TrimSpace(typeExpr) == "" {
+		return cty.DynamicPseudoType, nil
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(typeExpr), "type", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilType, fmt.Errorf("invalid type '%s': %w", typeExpr, diags)
+	}
+	ty, _, diags := typeexpr.TypeConstraintWithDefaults(expr)
+	if diags.HasErrors() {
+		return cty.NilType, fmt.Errorf("invalid type '%s': %w", typeExpr, diags)
+	}
+	return ty, nil
+}
+
+// baselineValue returns an ordinary, valid-looking value of type ty.
+func baselineValue(ty cty.Type) any {
+	switch {
+	case ty == cty.String, ty == cty.DynamicPseudoType:
+		return "fuzz"
+	case ty == cty.Number:
+		return 1
+	case ty == cty.Bool:
+		return true
+	case ty.IsListType(), ty.IsSetType():
+		return []any{baselineValue(ty.ElementType())}
+	case ty.IsMapType():
+		return map[string]any{"key": baselineValue(ty.ElementType())}
+	case ty.IsObjectType():
+		obj := make(map[string]any)
+		for name, attrType := range ty.AttributeTypes() {
+			obj[name] = baselineValue(attrType)
+		}
+		return obj
+	case ty.IsTupleType():
+		elems := ty.TupleElementTypes()
+		tuple := make([]any, len(elems))
+		for i, elemType := range elems {
+			tuple[i] = baselineValue(elemType)
+		}
+		return tuple
+	default:
+		return nil
+	}
+}
+
+// edges returns the edge-case values for type ty. For objects, the edges of
+// each attribute are applied to the baseline object one at a time.
+func edges(ty cty.Type, baseline any) []edge {
+	result := []edge{{"null", nil}}
+	switch {
+	case ty == cty.String:
+		result = append(result,
+			edge{"empty-string", ""},
+			edge{"whitespace", "   "},
+			edge{"long-string", strings.Repeat("a", longStringLength)},
+			edge{"unicode", "ünïcødé-✓"},
+		)
+	case ty == cty.Number:
+		result = append(result,
+			edge{"zero", 0},
+			edge{"negative", -1},
+			edge{"fractional", 0.5},
+			edge{"large", int64(2147483648)}, // Overflows 32-bit integers
+		)
+	case ty == cty.Bool:
+		result = append(result, edge{"false", false})
+	case ty.IsListType(), ty.IsSetType():
+		result = append(result,
+			edge{"empty-list", []any{}},
+			edge{"many-elements", manyOf(ty.ElementType())},
+		)
+	case ty.IsMapType():
+		result = append(result,
+			edge{"empty-map", map[string]any{}},
+			edge{"empty-key", map[string]any{"": baselineValue(ty.ElementType())}},
+		)
+	case ty.IsObjectType():
+		obj, _ := baseline.(map[string]any)
+		attrTypes := ty.AttributeTypes()
+		names := make([]string, 0, len(attrTypes))
+		for name := range attrTypes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, e := range edges(attrTypes[name], obj[name]) {
+				variant := make(map[string]any, len(obj))
+				for k, v := range obj {
+					variant[k] = v
+				}
+				variant[name] = e.value
+				result = append(result, edge{caseName(name, e.label), variant})
+			}
+		}
+	case ty == cty.DynamicPseudoType:
+		result = append(result,
+			edge{"empty-string", ""},
+			edge{"zero", 0},
+			edge{"empty-list", []any{}},
+			edge{"empty-map", map[string]any{}},
+		)
+	}
+	return result
+}
+
+// manyOf returns a collection of manyElements distinct baseline elements.
+func manyOf(elemType cty.Type) []any {
+	elems := make([]any, manyElements)
+	for i := range elems {
+		switch {
+		case elemType == cty.String, elemType == cty.DynamicPseudoType:
+			elems[i] = fmt.Sprintf("fuzz-%d", i)
+		case elemType == cty.Number:
+			elems[i] = i
+		default:
+			elems[i] = baselineValue(elemType)
+		}
+	}
+	return elems
+}
+
+// VarFile encodes the case's variables as a .tfvars.json file.
+func (c Case) VarFile() ([]byte, error) {
+	return json.MarshalIndent(c.Vars, "", "  ")
+}
+
+// TestFile renders a terraform test file that plans the module with the
+// case's variables, using mock providers so no credentials are needed.
+func (c Case) TestFile(providers []string) ([]byte, error) {
+	var b strings.Builder
+	for _, p := range providers {
+		fmt.Fprintf(&b, "mock_provider %q {}\n\n", p)
+	}
+
+	names := make([]string, 0, len(c.Vars))
+	for name := range c.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString("run \"fuzz\" {\n  command = plan\n")
+	if len(names) > 0 {
+		b.WriteString("\n  variables {\n")
+		for _, name := range names {
+			// JSON values are valid HCL expressions
+			value, err := json.Marshal(c.Vars[name])
+			if err != nil {
+				return nil, fmt.Errorf("variable '%s': %w", name, err)
+			}
+			fmt.Fprintf(&b, "    %s = %s\n", name, value)
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	return []byte(b.String()), nil
+}
+
+// Outcome is how a module responded to a case
+type Outcome string
+
+// Outcomes, from best to worst
+const (
+	// Accepted means the module planned successfully with the inputs
+	Accepted Outcome = "accepted"
+	// Rejected means a type constraint or validation block refused the inputs
+	Rejected Outcome = "rejected"
+	// Failed means the inputs got past validation and broke the module
+	Failed Outcome = "failed"
+)
+
+var (
+	// rejectedPattern matches terraform/tofu errors for refused variable values
+	rejectedPattern = regexp.MustCompile(`Invalid value for (input )?variable|Required variable not set`)
+	// errorPattern matches the summary line of an error diagnostic
+	errorPattern = regexp.MustCompile(`Error: (.+)`)
+)
+
+// Classify determines the outcome of a case from the command's error and
+// combined output, with the first error summary as detail.
+func Classify(runErr error, output string) (Outcome, string) {
+	if runErr == nil {
+		return Accepted, ""
+	}
+
+	detail := runErr.Error()
+	if m := errorPattern.FindStringSubmatch(output); m != nil {
+		detail = strings.TrimSpace(m[1])
+	}
+	if rejectedPattern.MatchString(output) {
+		return Rejected, detail
+	}
+	return Failed, detail
+}
+
+// Result is the outcome of running a case
+type Result struct {
+	Case    string  `json:"case"`
+	Outcome Outcome `json:"outcome"`
+	Detail  string  `json:"detail,omitempty"`
+}
+
+// Failures returns the results with the Failed outcome.
+func Failures(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Outcome == Failed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,162 @@
+package fuzz
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+func caseNames(cases []Case) []string {
+	names := make([]string, len(cases))
+	for i, c := range cases {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func TestGenerate_Baseline(t *testing.T) {
+	cases, err := Generate([]terraform.VariableInfo{
+		{Name: "name", Type: "string", Required: true},
+		{Name: "tags", Type: "map(string)", Default: map[string]any{}},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if cases[0].Name != BaselineCase {
+		t.Fatalf("first case = %s, want %s", cases[0].Name, BaselineCase)
+	}
+	if cases[0].Vars["name"] != "fuzz" {
+		t.Errorf("baseline name = %v, want fuzz", cases[0].Vars["name"])
+	}
+	if _, ok := cases[0].Vars["tags"]; ok {
+		t.Error("optional variables should keep their default in the baseline")
+	}
+
+	// Cases for tags keep the baseline value of the required variable
+	for _, c := range cases[1:] {
+		if c.Variable == "tags" && c.Vars["name"] != "fuzz" {
+			t.Errorf("case %s: name = %v, want baseline value", c.Name, c.Vars["name"])
+		}
+	}
+}
+
+func TestGenerate_EdgesByType(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want []string
+	}{
+		{"string", []string{"v=null", "v=empty-string", "v=whitespace", "v=long-string", "v=unicode"}},
+		{"number", []string{"v=null", "v=zero", "v=negative", "v=fractional", "v=large"}},
+		{"bool", []string{"v=null", "v=false"}},
+		{"list(string)", []string{"v=null", "v=empty-list", "v=many-elements"}},
+		{"map(number)", []string{"v=null", "v=empty-map", "v=empty-key"}},
+		{"object({ enabled = bool })", []string{"v=null", "v.enabled=null", "v.enabled=false"}},
+		{"", []string{"v=null", "v=empty-string", "v=zero", "v=empty-list", "v=empty-map"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			cases, err := Generate([]terraform.VariableInfo{{Name: "v", Type: tt.typ, Required: true}})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			got := caseNames(cases[1:])
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("cases = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_ObjectAttributeKeepsSiblings(t *testing.T) {
+	cases, err := Generate([]terraform.VariableInfo{
+		{Name: "settings", Type: "object({ name = string, size = optional(number) })", Required: true},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, c := range cases {
+		if c.Name != "settings.size=negative" {
+			continue
+		}
+		obj := c.Vars["settings"].(map[string]any)
+		if obj["size"] != -1 || obj["name"] != "fuzz" {
+			t.Errorf("settings = %v, want size -1 and baseline name", obj)
+		}
+		return
+	}
+	t.Errorf("missing settings.size=negative case in %v", caseNames(cases))
+}
+
+func TestGenerate_InvalidType(t *testing.T) {
+	if _, err := Generate([]terraform.VariableInfo{{Name: "v", Type: "list(strang)"}}); err == nil {
+		t.Error("expected error for invalid type")
+	}
+}
+
+func TestCase_TestFile(t *testing.T) {
+	c := Case{Name: "tags=empty-key", Vars: map[string]any{"name": "fuzz", "tags": map[string]any{"": "fuzz"}}}
+	content, err := c.TestFile([]string{"aws", "random"})
+	if err != nil {
+		t.Fatalf("TestFile() error = %v", err)
+	}
+
+	want := `mock_provider "aws" {}
+
+mock_provider "random" {}
+
+run "fuzz" {
+  command = plan
+
+  variables {
+    name = "fuzz"
+    tags = {"":"fuzz"}
+  }
+}
+`
+	if string(content) != want {
+		t.Errorf("TestFile() =\n%s\nwant\n%s", content, want)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	runErr := errors.New("exit status 1")
+	tests := []struct {
+		name       string
+		err        error
+		output     string
+		wantResult Outcome
+		wantDetail string
+	}{
+		{"success", nil, "No changes.", Accepted, ""},
+		{"validation", runErr, "Error: Invalid value for variable\n\n  on variables.tf line 1", Rejected, "Invalid value for variable"},
+		{"type", runErr, "Error: Invalid value for input variable\n", Rejected, "Invalid value for input variable"},
+		{"broken", runErr, "Error: Invalid index\n\n  on main.tf line 3", Failed, "Invalid index"},
+		{"no diagnostics", runErr, "", Failed, "exit status 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, detail := Classify(tt.err, tt.output)
+			if outcome != tt.wantResult || detail != tt.wantDetail {
+				t.Errorf("Classify() = %s, %q, want %s, %q", outcome, detail, tt.wantResult, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestFailures(t *testing.T) {
+	results := []Result{
+		{Case: "a", Outcome: Accepted},
+		{Case: "b", Outcome: Failed},
+		{Case: "c", Outcome: Rejected},
+	}
+	failed := Failures(results)
+	if len(failed) != 1 || failed[0].Case != "b" {
+		t.Errorf("Failures() = %v, want only case b", failed)
+	}
+}
@@ -8,12 +8,17 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 // GetChangedFiles returns a list of files that have changed between the base ref and HEAD,
 // including any uncommitted changes in the working directory.
-func GetChangedFiles(repoRoot, base string) ([]string, error) {
-	repo, err := git.PlainOpen(repoRoot)
+//
+// With mergeBase, HEAD is compared with the merge base of base and HEAD, like
+// 'git diff base...HEAD', so changes that landed on base after HEAD branched
+// off are not reported. Otherwise the trees of base and HEAD are compared directly.
+func GetChangedFiles(repoRoot, base string, mergeBase bool) ([]string, error) {
+	repo, err := openRepository(repoRoot, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
@@ -21,7 +26,7 @@ func GetChangedFiles(repoRoot, base string) ([]string, error) {
 	fileSet := make(map[string]bool)
 
 	// Get committed changes between base and HEAD
-	committedFiles, err := getCommittedChanges(repo, base)
+	committedFiles, err := getCommittedChanges(repo, base, mergeBase)
 	if err != nil {
 		// If we can't get committed changes (e.g., base doesn't exist), continue with uncommitted only
 		// This allows the command to work even on initial commits
@@ -51,8 +56,9 @@ func GetChangedFiles(repoRoot, base string) ([]string, error) {
 	return files, nil
 }
 
-// getCommittedChanges returns files changed between base ref and HEAD.
-func getCommittedChanges(repo *git.Repository, base string) ([]string, error) {
+// getCommittedChanges returns files changed between base ref (or its merge
+// base with HEAD) and HEAD.
+func getCommittedChanges(repo *git.Repository, base string, mergeBase bool) ([]string, error) {
 	// Resolve base reference
 	baseHash, err := repo.ResolveRevision(plumbing.Revision(base))
 	if err != nil {
@@ -76,21 +82,37 @@ func getCommittedChanges(repo *git.Repository, base string) ([]string, error) {
 		return nil, fmt.Errorf("failed to get HEAD commit: %w", err)
 	}
 
+	if mergeBase {
+		bases, err := baseCommit.MergeBase(headCommit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute merge base of %s and HEAD: %w", base, err)
+		}
+		if len(bases) == 0 {
+			return nil, fmt.Errorf("%s and HEAD have no common history, use --merge-base=false to compare them directly", base)
+		}
+		baseCommit = bases[0]
+	}
+
+	return diffCommits(baseCommit, headCommit)
+}
+
+// diffCommits returns the files that differ between the trees of two commits.
+func diffCommits(from, to *object.Commit) ([]string, error) {
 	// Get trees
-	baseTree, err := baseCommit.Tree()
+	baseTree, err := from.Tree()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get base tree: %w", err)
+		return nil, fmt.Errorf("failed to get base tree: %w", missingObjectHint(err))
 	}
 
-	headTree, err := headCommit.Tree()
+	headTree, err := to.Tree()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get HEAD tree: %w", err)
+		return nil, fmt.Errorf("failed to get HEAD tree: %w", missingObjectHint(err))
 	}
 
 	// Compute diff
 	changes, err := baseTree.Diff(headTree)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compute diff: %w", err)
+		return nil, fmt.Errorf("failed to compute diff: %w", missingObjectHint(err))
 	}
 
 	var files []string
@@ -107,6 +129,15 @@ func getCommittedChanges(repo *git.Repository, base string) ([]string, error) {
 	return files, nil
 }
 
+// missingObjectHint explains a missing object, which is expected in a
+// treeless partial clone: go-git can't fetch objects on demand like git does.
+func missingObjectHint(err error) error {
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return fmt.Errorf("%w (a treeless partial clone lacks the trees to compare, clone with --filter=blob:none instead)", err)
+	}
+	return err
+}
+
 // getUncommittedChanges returns files with uncommitted changes (staged + unstaged).
 func getUncommittedChanges(repo *git.Repository) ([]string, error) {
 	worktree, err := repo.Worktree()
@@ -133,9 +164,7 @@ func getUncommittedChanges(repo *git.Repository) ([]string, error) {
 // GetRepoRoot returns the root directory of the git repository.
 func GetRepoRoot() (string, error) {
 	// Start from current directory and walk up to find .git
-	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{
-		DetectDotGit: true,
-	})
+	repo, err := openRepository(".", true)
 	if err != nil {
 		return "", fmt.Errorf("not a git repository: %w", err)
 	}
@@ -151,9 +180,7 @@ func GetRepoRoot() (string, error) {
 // GetDefaultBranch attempts to determine the default branch of the repository.
 // It checks origin/HEAD first, then falls back to common defaults (main, master).
 func GetDefaultBranch() (string, error) {
-	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{
-		DetectDotGit: true,
-	})
+	repo, err := openRepository(".", true)
 	if err != nil {
 		return "", fmt.Errorf("not a git repository: %w", err)
 	}
@@ -182,13 +209,17 @@ func GetDefaultBranch() (string, error) {
 
 // MapFilesToModules takes a list of changed files and returns a list of module directories
 // that contain those files. It filters to only include paths that are within the given
-// module directories (e.g., components/, bases/, projects/).
-func MapFilesToModules(changedFiles []string, moduleDirs []string) []string {
+// module directories (e.g., components/, bases/, projects/). Files matched by ignore
+// don't mark their module as changed.
+func MapFilesToModules(changedFiles []string, moduleDirs []string, ignore *Ignore) []string {
 	moduleSet := make(map[string]bool)
 
 	for _, file := range changedFiles {
 		// Normalize path separators
 		file = filepath.ToSlash(file)
+		if ignore.Match(file) {
+			continue
+		}
 
 		// Check if file is within any of the module directories
 		for _, moduleDir := range moduleDirs {
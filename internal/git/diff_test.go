@@ -63,7 +63,7 @@ func TestGetChangedFiles_CommittedChanges(t *testing.T) {
 	runGit(t, repoDir, "commit", "-m", "add storage component")
 
 	// Get changed files
-	files, err := GetChangedFiles(repoDir, "base")
+	files, err := GetChangedFiles(repoDir, "base", true)
 	if err != nil {
 		t.Fatalf("GetChangedFiles failed: %v", err)
 	}
@@ -94,7 +94,7 @@ func TestGetChangedFiles_UncommittedChanges(t *testing.T) {
 	writeFile(t, filepath.Join(repoDir, "unstaged.tf"), "# unstaged")
 
 	// Get changed files (compare HEAD to HEAD, so only uncommitted show)
-	files, err := GetChangedFiles(repoDir, "HEAD")
+	files, err := GetChangedFiles(repoDir, "HEAD", true)
 	if err != nil {
 		t.Fatalf("GetChangedFiles failed: %v", err)
 	}
@@ -107,6 +107,46 @@ func TestGetChangedFiles_UncommittedChanges(t *testing.T) {
 	}
 }
 
+func TestGetChangedFiles_MergeBase(t *testing.T) {
+	repoDir := setupTestRepo(t)
+
+	writeFile(t, filepath.Join(repoDir, "initial.txt"), "initial content")
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "initial commit")
+	runGit(t, repoDir, "branch", "-M", "main")
+
+	// Branch off, then advance main with a change the branch doesn't have
+	runGit(t, repoDir, "checkout", "-b", "feature")
+	writeFile(t, filepath.Join(repoDir, "components", "storage", "main.tf"), "# storage module")
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "add storage component")
+	runGit(t, repoDir, "checkout", "main")
+	writeFile(t, filepath.Join(repoDir, "components", "network", "main.tf"), "# network module")
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "add network component")
+	runGit(t, repoDir, "checkout", "feature")
+
+	// Three-dot semantics: only the branch's own change
+	files, err := GetChangedFiles(repoDir, "main", true)
+	if err != nil {
+		t.Fatalf("GetChangedFiles failed: %v", err)
+	}
+	if !reflect.DeepEqual(files, []string{"components/storage/main.tf"}) {
+		t.Errorf("expected only the branch's change, got %v", files)
+	}
+
+	// Direct diff: main's newer change shows up as a (false) change too
+	files, err = GetChangedFiles(repoDir, "main", false)
+	if err != nil {
+		t.Fatalf("GetChangedFiles failed: %v", err)
+	}
+	sort.Strings(files)
+	expected := []string{"components/network/main.tf", "components/storage/main.tf"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+}
+
 func TestGetChangedFiles_InvalidRef(t *testing.T) {
 	repoDir := setupTestRepo(t)
 
@@ -117,7 +157,7 @@ func TestGetChangedFiles_InvalidRef(t *testing.T) {
 
 	// Try to get changes against non-existent ref
 	// Should not error, just return uncommitted changes only
-	_, err := GetChangedFiles(repoDir, "nonexistent-branch")
+	_, err := GetChangedFiles(repoDir, "nonexistent-branch", true)
 	if err != nil {
 		t.Errorf("expected no error for missing ref, got: %v", err)
 	}
@@ -126,7 +166,7 @@ func TestGetChangedFiles_InvalidRef(t *testing.T) {
 func TestGetChangedFiles_InvalidRepo(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	_, err := GetChangedFiles(tmpDir, "HEAD")
+	_, err := GetChangedFiles(tmpDir, "HEAD", true)
 	if err == nil {
 		t.Error("expected error for non-git directory")
 	}
@@ -307,7 +347,7 @@ func TestMapFilesToModules(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := MapFilesToModules(tt.changedFiles, tt.moduleDirs)
+			got := MapFilesToModules(tt.changedFiles, tt.moduleDirs, nil)
 			sort.Strings(got)
 			sort.Strings(tt.want)
 			if !reflect.DeepEqual(got, tt.want) {
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DirFiles returns the contents of the files directly in dir, slash-separated
+// and relative to the repository root, in the commit ref resolves to, keyed
+// by name. Subdirectories and symlinks are left out. A dir that doesn't exist
+// at ref has no files.
+func DirFiles(repoRoot, ref, dir string) (map[string][]byte, error) {
+	repo, err := openRepository(repoRoot, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	tree, err := commitTree(repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	if dir = path.Clean(dir); dir != "." {
+		tree, err = tree.Tree(dir)
+		if errors.Is(err, object.ErrDirectoryNotFound) {
+			return map[string][]byte{}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s at %s: %w", dir, ref, missingObjectHint(err))
+		}
+	}
+
+	files := map[string][]byte{}
+	for _, entry := range tree.Entries {
+		if entry.Mode != filemode.Regular && entry.Mode != filemode.Executable {
+			continue
+		}
+		file, err := tree.TreeEntryFile(&entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s at %s: %w", path.Join(dir, entry.Name), ref, missingObjectHint(err))
+		}
+		contents, err := file.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s at %s: %w", path.Join(dir, entry.Name), ref, missingObjectHint(err))
+		}
+		files[entry.Name] = []byte(contents)
+	}
+	return files, nil
+}
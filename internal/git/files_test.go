@@ -0,0 +1,44 @@
+package git
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDirFiles(t *testing.T) {
+	repoDir := setupStatusRepo(t)
+	writeFile(t, filepath.Join(repoDir, "mod", "sub", "nested.tf"), "# nested\n")
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "nested")
+
+	tests := []struct {
+		ref  string
+		dir  string
+		want map[string][]byte
+	}{
+		{"base", "mod", map[string][]byte{"keep.tf": []byte("# keep\n"), "gone.tf": []byte("# gone\n")}},
+		// Subdirectories are left out
+		{"HEAD", "mod", map[string][]byte{"keep.tf": []byte("# keep, changed\n"), "new.tf": []byte("# new\n")}},
+		{"HEAD", "mod/sub/", map[string][]byte{"nested.tf": []byte("# nested\n")}},
+		{"base", "mod/sub", map[string][]byte{}},
+		{"HEAD", "missing", map[string][]byte{}},
+	}
+	for _, tt := range tests {
+		got, err := DirFiles(repoDir, tt.ref, tt.dir)
+		if err != nil {
+			t.Fatalf("DirFiles(%s, %s) error = %v", tt.ref, tt.dir, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DirFiles(%s, %s) = %q, want %q", tt.ref, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestDirFiles_UnknownRef(t *testing.T) {
+	repoDir := setupStatusRepo(t)
+	if _, err := DirFiles(repoDir, "nope", "mod"); err == nil || !strings.Contains(err.Error(), "ref 'nope' not found") {
+		t.Errorf("expected a ref not found error, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// GitDir returns the git directory of the working tree at dir: dir/.git when
+// it's a directory, or the directory a .git file points to with a
+// "gitdir: <path>" line, as in linked worktrees and submodule checkouts. It
+// reports false when dir has no .git, or its .git file doesn't point to an
+// existing directory.
+func GitDir(dir string) (string, bool) {
+	dotGit := filepath.Join(dir, git.GitDirName)
+	info, err := os.Stat(dotGit)
+	switch {
+	case err != nil:
+		return "", false
+	case info.IsDir():
+		return dotGit, true
+	case !info.Mode().IsRegular():
+		return "", false
+	}
+
+	data, err := os.ReadFile(dotGit) //nolint:gosec // .git in a directory motf runs in
+	if err != nil {
+		return "", false
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(line), "gitdir:")
+	if !ok {
+		return "", false
+	}
+	gitDir = filepath.FromSlash(strings.TrimSpace(gitDir))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+		return "", false
+	}
+	return gitDir, true
+}
+
+// commonDir returns the directory with the objects and refs shared by the
+// worktrees of gitDir: the one its commondir file points to, or gitDir
+// itself for the main worktree and submodules.
+func commonDir(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir")) //nolint:gosec // File in a git directory
+	if err != nil {
+		return gitDir
+	}
+	dir := filepath.FromSlash(strings.TrimSpace(string(data)))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gitDir, dir)
+	}
+	return filepath.Clean(dir)
+}
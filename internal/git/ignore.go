@@ -0,0 +1,37 @@
+package git
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// Ignore matches changed files against gitignore-style patterns, so edits to
+// them (e.g. documentation) don't mark their module as changed.
+type Ignore struct {
+	matcher gitignore.Matcher
+}
+
+// NewIgnore returns an Ignore for patterns relative to the repository root.
+// As in .gitignore, patterns without a slash match at any depth, ** matches
+// any number of directories, and a leading ! re-includes files ignored by an
+// earlier pattern. It returns nil, which ignores nothing, if there are no patterns.
+func NewIgnore(patterns []string) *Ignore {
+	if len(patterns) == 0 {
+		return nil
+	}
+	parsed := make([]gitignore.Pattern, len(patterns))
+	for i, p := range patterns {
+		parsed[i] = gitignore.ParsePattern(p, nil)
+	}
+	return &Ignore{matcher: gitignore.NewMatcher(parsed)}
+}
+
+// Match reports whether file (relative to the repository root) is ignored.
+func (i *Ignore) Match(file string) bool {
+	if i == nil {
+		return false
+	}
+	return i.matcher.Match(strings.Split(filepath.ToSlash(file), "/"), false)
+}
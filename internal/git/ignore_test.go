@@ -0,0 +1,56 @@
+package git
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestIgnore_Match(t *testing.T) {
+	ignore := NewIgnore([]string{"**/*.md", "**/docs/**", "!**/CHANGELOG.md"})
+
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{file: "components/azurerm/storage-account/README.md", want: true},
+		{file: "README.md", want: true},
+		{file: "components/azurerm/storage-account/docs/usage.tf", want: true},
+		{file: "components/azurerm/storage-account/CHANGELOG.md", want: false},
+		{file: "components/azurerm/storage-account/main.tf", want: false},
+		{file: "components/azurerm/docs-site/main.tf", want: false},
+	}
+	for _, tt := range tests {
+		if got := ignore.Match(tt.file); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestNewIgnore_NoPatterns(t *testing.T) {
+	ignore := NewIgnore(nil)
+	if ignore != nil {
+		t.Fatalf("NewIgnore(nil) = %v, want nil", ignore)
+	}
+	if ignore.Match("components/vnet/README.md") {
+		t.Error("expected a nil Ignore to match nothing")
+	}
+}
+
+func TestMapFilesToModules_Ignore(t *testing.T) {
+	changedFiles := []string{
+		"components/azurerm/storage-account/README.md",
+		"components/azurerm/storage-account/docs/diagram.png",
+		"components/azurerm/vnet/README.md",
+		"components/azurerm/vnet/main.tf",
+		"projects/prod-infra/main.tf",
+	}
+	ignore := NewIgnore([]string{"**/*.md", "**/docs/**"})
+
+	got := MapFilesToModules(changedFiles, []string{"components", "bases", "projects"}, ignore)
+	sort.Strings(got)
+	want := []string{"components/azurerm/vnet", "projects/prod-infra"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapFilesToModules() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/filesystem/dotgit"
+)
+
+// readOnlyExtensions are repository extensions that go-git refuses to open
+// repositories with, but that don't change how motf reads them:
+// worktreeConfig, enabled by 'git sparse-checkout', only moves settings to a
+// per-worktree config file, and partialClone only marks objects that may be
+// missing until fetched.
+var readOnlyExtensions = []string{"worktreeConfig", "partialClone"}
+
+// openRepository opens the repository at path, or with detect, the repository
+// containing path. Linked worktrees read the objects and refs of their main
+// repository. Sparse checkouts and partial clones are opened ignoring
+// readOnlyExtensions, since motf never writes to the repository.
+func openRepository(path string, detect bool) (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: detect, EnableDotGitCommonDir: true})
+	if !errors.Is(err, git.ErrUnknownExtension) && !errors.Is(err, git.ErrUnsupportedExtensionRepositoryFormatVersion) {
+		return repo, err
+	}
+
+	root, gitDir, ok := worktreeRoot(path, detect)
+	if !ok {
+		return nil, err
+	}
+	fs := dotgit.NewRepositoryFilesystem(osfs.New(gitDir), osfs.New(commonDir(gitDir)))
+	storage := filesystem.NewStorage(fs, cache.NewObjectLRUDefault())
+	return git.Open(readOnlyStorage{storage}, osfs.New(root))
+}
+
+// worktreeRoot returns the directory with a .git directory or file at path,
+// or with detect, at path or one of its parents, and its git directory.
+func worktreeRoot(path string, detect bool) (string, string, bool) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", false
+	}
+	for {
+		if gitDir, ok := GitDir(dir); ok {
+			return dir, gitDir, true
+		}
+		parent := filepath.Dir(dir)
+		if !detect || parent == dir {
+			return "", "", false
+		}
+		dir = parent
+	}
+}
+
+// readOnlyStorage hides readOnlyExtensions from the repository config, so
+// go-git opens the repository.
+type readOnlyStorage struct {
+	*filesystem.Storage
+}
+
+// Config returns the repository config without readOnlyExtensions.
+func (s readOnlyStorage) Config() (*config.Config, error) {
+	cfg, err := s.Storage.Config()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository config: %w", err)
+	}
+	if cfg.Raw.HasSection("extensions") {
+		section := cfg.Raw.Section("extensions")
+		for _, name := range readOnlyExtensions {
+			section.RemoveOption(name)
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,90 @@
+package git
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenRepository_ReadOnlyExtensions(t *testing.T) {
+	repoDir := setupStatusRepo(t)
+	runGit(t, repoDir, "config", "core.repositoryformatversion", "1")
+	runGit(t, repoDir, "config", "extensions.partialClone", "origin")
+	runGit(t, repoDir, "config", "extensions.worktreeConfig", "true")
+
+	if _, err := openRepository(repoDir, false); err != nil {
+		t.Fatalf("openRepository() error = %v", err)
+	}
+	// With detect, the repository is found from a subdirectory
+	if _, err := openRepository(filepath.Join(repoDir, "mod"), true); err != nil {
+		t.Fatalf("openRepository() from a subdirectory error = %v", err)
+	}
+	files, err := FileStatuses(repoDir, "base", "HEAD", []string{"mod/keep.tf"})
+	if err != nil || len(files) != 1 {
+		t.Errorf("FileStatuses() = %v, %v; want mod/keep.tf", files, err)
+	}
+}
+
+func TestOpenRepository_UnknownExtension(t *testing.T) {
+	repoDir := setupStatusRepo(t)
+	runGit(t, repoDir, "config", "core.repositoryformatversion", "1")
+	runGit(t, repoDir, "config", "extensions.objectFormat", "sha256")
+
+	if _, err := openRepository(repoDir, false); err == nil {
+		t.Error("expected an error for an extension that changes how the repository is read")
+	}
+}
+
+func TestOpenRepository_Worktree(t *testing.T) {
+	repoDir := setupStatusRepo(t)
+	worktreeDir := filepath.Join(t.TempDir(), "worktree")
+	runGit(t, repoDir, "worktree", "add", "-b", "feature", worktreeDir, "HEAD")
+
+	// The refs and objects of a linked worktree are in the main repository
+	files, err := FileStatuses(worktreeDir, "base", "HEAD", []string{"mod/keep.tf"})
+	if err != nil || len(files) != 1 {
+		t.Errorf("FileStatuses() = %v, %v; want mod/keep.tf", files, err)
+	}
+	if _, err := openRepository(filepath.Join(worktreeDir, "mod"), true); err != nil {
+		t.Errorf("openRepository() from a subdirectory error = %v", err)
+	}
+
+	// Worktrees of sparse checkouts often enable worktreeConfig
+	runGit(t, repoDir, "config", "core.repositoryformatversion", "1")
+	runGit(t, repoDir, "config", "extensions.worktreeConfig", "true")
+	files, err = FileStatuses(worktreeDir, "base", "HEAD", []string{"mod/keep.tf"})
+	if err != nil || len(files) != 1 {
+		t.Errorf("FileStatuses() with worktreeConfig = %v, %v; want mod/keep.tf", files, err)
+	}
+}
+
+func TestGitDir(t *testing.T) {
+	repoDir := setupTestRepo(t)
+	if got, ok := GitDir(repoDir); !ok || got != filepath.Join(repoDir, ".git") {
+		t.Errorf("GitDir() = %q, %v; want the .git directory", got, ok)
+	}
+
+	// A .git file points to the git directory, relative to its directory
+	checkout := t.TempDir()
+	rel, err := filepath.Rel(checkout, filepath.Join(repoDir, ".git"))
+	if err != nil {
+		t.Fatalf("failed to get relative path: %v", err)
+	}
+	writeFile(t, filepath.Join(checkout, ".git"), "gitdir: "+filepath.ToSlash(rel)+"\n")
+	if got, ok := GitDir(checkout); !ok || got != filepath.Join(repoDir, ".git") {
+		t.Errorf("GitDir() of a .git file = %q, %v; want %q", got, ok, filepath.Join(repoDir, ".git"))
+	}
+
+	for name, content := range map[string]string{
+		"no gitdir line": "not a pointer\n",
+		"missing gitdir": "gitdir: /does/not/exist\n",
+	} {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, ".git"), content)
+		if got, ok := GitDir(dir); ok {
+			t.Errorf("%s: GitDir() = %q, want none", name, got)
+		}
+	}
+	if _, ok := GitDir(t.TempDir()); ok {
+		t.Error("expected no git directory without .git")
+	}
+}
@@ -0,0 +1,143 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// sinceDateLayouts are the absolute date formats accepted by ParseSince
+var sinceDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// relativeSinceRe matches git-style relative dates such as "2.weeks",
+// "3 days", or "1.month.ago"
+var relativeSinceRe = regexp.MustCompile(`^(\d+)[. ](minute|hour|day|week|month|year)s?(?:[. ]ago)?$`)
+
+// ParseSince parses a date like "2024-01-01" or a git-style relative date like
+// "2.weeks" (meaning two weeks before now). Dates without a time zone are in
+// now's location.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range sinceDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	m := relativeSinceRe.FindStringSubmatch(strings.ToLower(value))
+	if m == nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s': use YYYY-MM-DD or a relative date like 2.weeks", value)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s': %w", value, err)
+	}
+	switch m[2] {
+	case "minute":
+		return now.Add(-time.Duration(n) * time.Minute), nil
+	case "hour":
+		return now.Add(-time.Duration(n) * time.Hour), nil
+	case "day":
+		return now.AddDate(0, 0, -n), nil
+	case "week":
+		return now.AddDate(0, 0, -7*n), nil
+	case "month":
+		return now.AddDate(0, -n, 0), nil
+	default:
+		return now.AddDate(-n, 0, 0), nil
+	}
+}
+
+// GetChangedFilesBetween returns the files that changed between two commits,
+// e.g. two release tags. Uncommitted changes are not included.
+func GetChangedFilesBetween(repoRoot, from, to string) ([]string, error) {
+	repo, err := openRepository(repoRoot, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	fromCommit, err := resolveCommit(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := resolveCommit(repo, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffCommits(fromCommit, toCommit)
+}
+
+// GetChangedFilesSince returns the files changed by commits on HEAD that were
+// committed at or after since, following first parents so merged branches
+// count with their merge commit. Uncommitted changes are not included.
+func GetChangedFilesSince(repoRoot string, since time.Time) ([]string, error) {
+	repo, err := openRepository(repoRoot, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	head, err := resolveCommit(repo, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	base, err := commitBefore(head, since)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		// The whole history is in the window
+		return treeFiles(head)
+	}
+	return diffCommits(base, head)
+}
+
+// commitBefore returns the last first-parent ancestor of head (or head
+// itself) committed before since; everything after it is in the window. It
+// returns nil when the whole history is after since.
+func commitBefore(head *object.Commit, since time.Time) (*object.Commit, error) {
+	base := head
+	for !base.Committer.When.Before(since) {
+		if base.NumParents() == 0 {
+			return nil, nil
+		}
+		parent, err := base.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent of %s: %w", base.Hash, err)
+		}
+		base = parent
+	}
+	return base, nil
+}
+
+// resolveCommit resolves a ref, tag, or hash to its commit.
+func resolveCommit(repo *git.Repository, ref string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("ref '%s' not found: %w", ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit of '%s': %w", ref, err)
+	}
+	return commit, nil
+}
+
+// treeFiles returns all files in the tree of a commit.
+func treeFiles(commit *object.Commit) ([]string, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+	var files []string
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files = append(files, f.Name)
+		return nil
+	})
+	return files, err
+}
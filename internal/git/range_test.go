@@ -0,0 +1,119 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// commitAt commits all changes in dir with the given committer date.
+func commitAt(t *testing.T, dir, message, date string) {
+	t.Helper()
+	runGit(t, dir, "add", "-A")
+	cmd := exec.Command("git", "commit", "-m", message)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(), "GIT_COMMITTER_DATE="+date, "GIT_AUTHOR_DATE="+date)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %v\nOutput: %s", err, output)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-01-01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-01T08:30:00Z", time.Date(2024, 1, 1, 8, 30, 0, 0, time.UTC)},
+		{"2.weeks", now.AddDate(0, 0, -14)},
+		{"3 days", now.AddDate(0, 0, -3)},
+		{"1.month.ago", now.AddDate(0, -1, 0)},
+		{"6.hours", now.Add(-6 * time.Hour)},
+		{"1.year", now.AddDate(-1, 0, 0)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseSince(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "yesterday", "2.fortnights", "01/02/2024"} {
+		if _, err := ParseSince(value, now); err == nil {
+			t.Errorf("ParseSince(%q) expected an error", value)
+		}
+	}
+}
+
+func TestGetChangedFilesSince(t *testing.T) {
+	repoDir := setupTestRepo(t)
+
+	writeFile(t, filepath.Join(repoDir, "old.txt"), "old")
+	commitAt(t, repoDir, "old", "2024-01-01T12:00:00Z")
+	writeFile(t, filepath.Join(repoDir, "middle.txt"), "middle")
+	commitAt(t, repoDir, "middle", "2024-02-01T12:00:00Z")
+	writeFile(t, filepath.Join(repoDir, "new.txt"), "new")
+	commitAt(t, repoDir, "new", "2024-03-01T12:00:00Z")
+
+	files, err := GetChangedFilesSince(repoDir, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetChangedFilesSince() error = %v", err)
+	}
+	sort.Strings(files)
+	if len(files) != 2 || files[0] != "middle.txt" || files[1] != "new.txt" {
+		t.Errorf("files = %v, want [middle.txt new.txt]", files)
+	}
+
+	// A window covering the whole history returns every file
+	files, err = GetChangedFilesSince(repoDir, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetChangedFilesSince() error = %v", err)
+	}
+	if len(files) != 3 {
+		t.Errorf("files = %v, want all 3 files", files)
+	}
+
+	// A window after the last commit is empty
+	files, err = GetChangedFilesSince(repoDir, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetChangedFilesSince() error = %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("files = %v, want none", files)
+	}
+}
+
+func TestGetChangedFilesBetween(t *testing.T) {
+	repoDir := setupTestRepo(t)
+
+	writeFile(t, filepath.Join(repoDir, "a.txt"), "a")
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "a")
+	runGit(t, repoDir, "tag", "v1.0.0")
+	writeFile(t, filepath.Join(repoDir, "b.txt"), "b")
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "b")
+	runGit(t, repoDir, "tag", "v1.1.0")
+	writeFile(t, filepath.Join(repoDir, "c.txt"), "c")
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "c")
+
+	files, err := GetChangedFilesBetween(repoDir, "v1.0.0", "v1.1.0")
+	if err != nil {
+		t.Fatalf("GetChangedFilesBetween() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != "b.txt" {
+		t.Errorf("files = %v, want [b.txt]", files)
+	}
+
+	if _, err := GetChangedFilesBetween(repoDir, "v0.1.0", "HEAD"); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Sparse describes which files of a sparse checkout are in the worktree.
+// Files outside the sparse checkout are tracked in the index but marked
+// skip-worktree, so a nil *Sparse, for a full checkout, includes everything.
+type Sparse struct {
+	checkedOut map[string]bool // Files in the worktree and their parent directories
+	tracked    map[string]bool // All files in the index and their parent directories
+}
+
+// SparseCheckout returns the sparse checkout of the repository at repoRoot,
+// or nil when all files are checked out. It works for cone and non-cone mode
+// since it reads the skip-worktree flags that both set in the index.
+func SparseCheckout(repoRoot string) (*Sparse, error) {
+	repo, err := openRepository(repoRoot, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	s := &Sparse{checkedOut: make(map[string]bool), tracked: make(map[string]bool)}
+	sparse := false
+	for _, entry := range idx.Entries {
+		// A sparse index has a single entry, ending in /, per directory outside
+		name := strings.TrimSuffix(entry.Name, "/")
+		addWithParents(s.tracked, name)
+		if entry.SkipWorktree {
+			sparse = true
+			continue
+		}
+		addWithParents(s.checkedOut, name)
+	}
+	if !sparse {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// addWithParents adds file and its parent directories to set.
+func addWithParents(set map[string]bool, file string) {
+	for p := file; p != "." && p != "/" && !set[p]; p = path.Dir(p) {
+		set[p] = true
+	}
+}
+
+// Includes reports whether the file or directory at p, slash-separated and
+// relative to the repository root, is in the sparse checkout. A directory is
+// included when any file in it is. Paths the index doesn't know, like new or
+// deleted files, are included unless their directory is outside.
+func (s *Sparse) Includes(p string) bool {
+	if s == nil {
+		return true
+	}
+	for p = path.Clean(p); p != "." && p != "/"; p = path.Dir(p) {
+		if s.checkedOut[p] {
+			return true
+		}
+		if s.tracked[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the files, slash-separated and relative to the repository
+// root, that are in the sparse checkout, and the number of files outside it.
+func (s *Sparse) Filter(files []string) ([]string, int) {
+	if s == nil {
+		return files, 0
+	}
+	var included []string
+	for _, file := range files {
+		if s.Includes(file) {
+			included = append(included, file)
+		}
+	}
+	return included, len(files) - len(included)
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// setupSparseRepo creates a repository with modules components/a,
+// components/x, components/x/y, and components/x/z, and a second commit that
+// changes components/x/z and components/a, then checks out only components/x/y
+// in cone mode. Cone mode also checks out components/x/main.tf, since it's in
+// a parent directory of the cone.
+func setupSparseRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := setupTestRepo(t)
+	for _, module := range []string{"components/a", "components/x", "components/x/y", "components/x/z"} {
+		writeFile(t, filepath.Join(repoDir, module, "main.tf"), "# "+module+"\n")
+	}
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "base")
+	runGit(t, repoDir, "tag", "base")
+
+	writeFile(t, filepath.Join(repoDir, "components", "x", "z", "main.tf"), "# changed\n")
+	writeFile(t, filepath.Join(repoDir, "components", "a", "main.tf"), "# changed\n")
+	runGit(t, repoDir, "commit", "-am", "change")
+	runGit(t, repoDir, "sparse-checkout", "set", "--cone", "components/x/y")
+	return repoDir
+}
+
+func TestSparseCheckout(t *testing.T) {
+	repoDir := setupSparseRepo(t)
+
+	sparse, err := SparseCheckout(repoDir)
+	if err != nil {
+		t.Fatalf("SparseCheckout() error = %v", err)
+	}
+	if sparse == nil {
+		t.Fatal("SparseCheckout() = nil, want a sparse checkout")
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"components/x/y/main.tf", true},
+		{"components/x/y", true},
+		{"components/x/main.tf", true},
+		{"components/x", true},
+		{"components/x/z/main.tf", false},
+		{"components/x/z", false},
+		{"components/a/main.tf", false},
+		{"components/x/z/deleted.tf", false}, // Unknown, but in a directory outside
+		{"components/new/main.tf", true},     // New directories are included
+		{"components/x/y/new.tf", true},
+	}
+	for _, tt := range tests {
+		if got := sparse.Includes(tt.path); got != tt.want {
+			t.Errorf("Includes(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSparseCheckout_FullCheckout(t *testing.T) {
+	repoDir := setupStatusRepo(t)
+
+	sparse, err := SparseCheckout(repoDir)
+	if err != nil {
+		t.Fatalf("SparseCheckout() error = %v", err)
+	}
+	if sparse != nil {
+		t.Error("SparseCheckout() of a full checkout should be nil")
+	}
+	// A nil Sparse includes everything
+	files, skipped := sparse.Filter([]string{"mod/keep.tf", "other/main.tf"})
+	if len(files) != 2 || skipped != 0 {
+		t.Errorf("Filter() = %v, %d; want all files", files, skipped)
+	}
+}
+
+func TestSparse_Filter(t *testing.T) {
+	repoDir := setupSparseRepo(t)
+	sparse, err := SparseCheckout(repoDir)
+	if err != nil {
+		t.Fatalf("SparseCheckout() error = %v", err)
+	}
+
+	// Opening the repository works despite the worktreeConfig extension that
+	// sparse-checkout enables
+	changed, err := GetChangedFiles(repoDir, "base", false)
+	if err != nil {
+		t.Fatalf("GetChangedFiles() error = %v", err)
+	}
+	files, skipped := sparse.Filter(changed)
+	if len(files) != 0 || skipped != 2 {
+		t.Errorf("Filter(%v) = %v, %d; want no files and 2 skipped", changed, files, skipped)
+	}
+
+	writeFile(t, filepath.Join(repoDir, "components", "x", "y", "main.tf"), "# local change\n")
+	changed, err = GetChangedFiles(repoDir, "base", false)
+	if err != nil {
+		t.Fatalf("GetChangedFiles() error = %v", err)
+	}
+	files, _ = sparse.Filter(changed)
+	sort.Strings(files)
+	if want := []string{"components/x/y/main.tf"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("Filter() = %v, want %v", files, want)
+	}
+}
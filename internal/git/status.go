@@ -0,0 +1,177 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Statuses of a changed file
+const (
+	StatusAdded    = "added"
+	StatusModified = "modified"
+	StatusDeleted  = "deleted"
+)
+
+// emptyTree is the hash of git's empty tree, the base of changes when the
+// whole history is compared.
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// FileChange is a changed file and how it changed.
+type FileChange struct {
+	Path   string `json:"path"` // Slash-separated, relative to the repository root
+	Status string `json:"status"`
+}
+
+// MergeBase returns the hash of the merge base of base and HEAD. When base
+// can't be resolved (e.g. on an initial commit) it returns HEAD, matching
+// GetChangedFiles, which then only reports uncommitted changes.
+func MergeBase(repoRoot, base string) (string, error) {
+	repo, err := openRepository(repoRoot, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	baseCommit, err := resolveCommit(repo, base)
+	if err != nil {
+		return "HEAD", nil
+	}
+	head, err := resolveCommit(repo, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	bases, err := baseCommit.MergeBase(head)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute merge base of %s and HEAD: %w", base, err)
+	}
+	if len(bases) == 0 {
+		return "", fmt.Errorf("%s and HEAD have no common history, use --merge-base=false to compare them directly", base)
+	}
+	return bases[0].Hash.String(), nil
+}
+
+// SinceBase returns the hash of the last commit on HEAD before since, the
+// base GetChangedFilesSince compares HEAD with. It returns "" when the whole
+// history is after since.
+func SinceBase(repoRoot string, since time.Time) (string, error) {
+	repo, err := openRepository(repoRoot, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+	head, err := resolveCommit(repo, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	base, err := commitBefore(head, since)
+	if err != nil || base == nil {
+		return "", err
+	}
+	return base.Hash.String(), nil
+}
+
+// FileStatuses returns how each of files changed from the base commit to the
+// target commit, sorted by path. An empty base compares with an empty tree;
+// an empty target compares with the working tree. Files that exist in
+// neither are left out.
+func FileStatuses(repoRoot, base, target string, files []string) ([]FileChange, error) {
+	repo, err := openRepository(repoRoot, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	baseTree, err := commitTree(repo, base)
+	if err != nil {
+		return nil, err
+	}
+	targetTree, err := commitTree(repo, target)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := func(tree *object.Tree, file string) (bool, error) {
+		if tree == nil {
+			return false, nil
+		}
+		_, err := tree.File(file)
+		if errors.Is(err, object.ErrFileNotFound) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	var changes []FileChange
+	for _, file := range files {
+		file = filepath.ToSlash(file)
+		inBase, err := exists(baseTree, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s: %w", file, err)
+		}
+		var inTarget bool
+		if target == "" {
+			_, statErr := os.Lstat(filepath.Join(repoRoot, filepath.FromSlash(file)))
+			inTarget = statErr == nil
+		} else if inTarget, err = exists(targetTree, file); err != nil {
+			return nil, fmt.Errorf("failed to look up %s: %w", file, err)
+		}
+
+		switch {
+		case inBase && inTarget:
+			changes = append(changes, FileChange{Path: file, Status: StatusModified})
+		case inTarget:
+			changes = append(changes, FileChange{Path: file, Status: StatusAdded})
+		case inBase:
+			changes = append(changes, FileChange{Path: file, Status: StatusDeleted})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// commitTree returns the tree of the commit ref resolves to, or nil for "".
+func commitTree(repo *git.Repository, ref string) (*object.Tree, error) {
+	if ref == "" {
+		return nil, nil
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("ref '%s' not found: %w", ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit of '%s': %w", ref, err)
+	}
+	return commit.Tree()
+}
+
+// Patch returns the unified diff of paths from the base commit to the target
+// commit, with the same meaning of empty base and target as FileStatuses.
+// go-git can't diff the working tree, so the git CLI is used. Untracked files
+// aren't included.
+func Patch(repoRoot, base, target string, paths ...string) (string, error) {
+	if base == "" {
+		base = emptyTree
+	}
+	args := []string{"diff", "--no-color", "--no-ext-diff", base}
+	if target != "" {
+		args = append(args, target)
+	}
+	args = append(append(args, "--"), paths...)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git diff failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
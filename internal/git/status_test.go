@@ -0,0 +1,109 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// setupStatusRepo creates a repository with a commit tagged base that has
+// keep.tf and gone.tf, and a second commit that modifies keep.tf, deletes
+// gone.tf, and adds new.tf.
+func setupStatusRepo(t *testing.T) string {
+	t.Helper()
+	repoDir := setupTestRepo(t)
+	writeFile(t, filepath.Join(repoDir, "mod", "keep.tf"), "# keep\n")
+	writeFile(t, filepath.Join(repoDir, "mod", "gone.tf"), "# gone\n")
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "base")
+	runGit(t, repoDir, "tag", "base")
+
+	writeFile(t, filepath.Join(repoDir, "mod", "keep.tf"), "# keep, changed\n")
+	writeFile(t, filepath.Join(repoDir, "mod", "new.tf"), "# new\n")
+	if err := os.Remove(filepath.Join(repoDir, "mod", "gone.tf")); err != nil {
+		t.Fatalf("failed to remove gone.tf: %v", err)
+	}
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "change")
+	return repoDir
+}
+
+func TestFileStatuses(t *testing.T) {
+	repoDir := setupStatusRepo(t)
+	files := []string{"mod/new.tf", "mod/keep.tf", "mod/gone.tf"}
+	want := []FileChange{
+		{Path: "mod/gone.tf", Status: StatusDeleted},
+		{Path: "mod/keep.tf", Status: StatusModified},
+		{Path: "mod/new.tf", Status: StatusAdded},
+	}
+
+	got, err := FileStatuses(repoDir, "base", "HEAD", files)
+	if err != nil {
+		t.Fatalf("FileStatuses() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FileStatuses() = %v, want %v", got, want)
+	}
+
+	// Against the working tree, a file deleted there is deleted
+	if err := os.Remove(filepath.Join(repoDir, "mod", "keep.tf")); err != nil {
+		t.Fatalf("failed to remove keep.tf: %v", err)
+	}
+	got, err = FileStatuses(repoDir, "base", "", files)
+	if err != nil {
+		t.Fatalf("FileStatuses() error = %v", err)
+	}
+	if got[1].Status != StatusDeleted {
+		t.Errorf("keep.tf status = %s, want deleted", got[1].Status)
+	}
+
+	// Without a base, everything is added
+	got, err = FileStatuses(repoDir, "", "HEAD", []string{"mod/keep.tf"})
+	if err != nil {
+		t.Fatalf("FileStatuses() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Status != StatusAdded {
+		t.Errorf("FileStatuses() without base = %v, want added", got)
+	}
+}
+
+func TestMergeBase_UnknownRef(t *testing.T) {
+	repoDir := setupStatusRepo(t)
+
+	base, err := MergeBase(repoDir, "origin/main")
+	if err != nil || base != "HEAD" {
+		t.Errorf("MergeBase() = %q, %v; want HEAD", base, err)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	repoDir := setupStatusRepo(t)
+	writeFile(t, filepath.Join(repoDir, "other.tf"), "# other\n")
+	runGit(t, repoDir, "add", "-A")
+	runGit(t, repoDir, "commit", "-m", "other")
+
+	patch, err := Patch(repoDir, "base", "HEAD", "mod")
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	for _, want := range []string{"diff --git a/mod/keep.tf b/mod/keep.tf", "+# keep, changed", "deleted file mode", "+# new"} {
+		if !strings.Contains(patch, want) {
+			t.Errorf("expected patch to contain %q, got:\n%s", want, patch)
+		}
+	}
+	if strings.Contains(patch, "other.tf") {
+		t.Errorf("expected patch to be limited to mod, got:\n%s", patch)
+	}
+
+	// Uncommitted changes are included without a target
+	writeFile(t, filepath.Join(repoDir, "mod", "keep.tf"), "# keep, uncommitted\n")
+	patch, err = Patch(repoDir, "HEAD", "", "mod")
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	if !strings.Contains(patch, "+# keep, uncommitted") {
+		t.Errorf("expected patch of the working tree, got:\n%s", patch)
+	}
+}
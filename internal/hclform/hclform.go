@@ -0,0 +1,309 @@
+// Package hclform edits the variables and outputs of a module's .tf files,
+// keeping the rest of each file as written and formatting it like
+// 'terraform fmt', for `motf edit`. Splice replaces byte ranges of a file
+// without reformatting it, for commands that only change a few values.
+package hclform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/sources"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// Files new variables and outputs are added to
+const (
+	VariablesFile = "variables.tf"
+	OutputsFile   = "outputs.tf"
+)
+
+// reservedVariableNames can't be used as variable names in Terraform
+var reservedVariableNames = []string{"count", "depends_on", "for_each", "lifecycle", "locals", "providers", "source", "version"}
+
+// Variable is a variable block added by AddVariable.
+type Variable struct {
+	Name        string
+	Type        string // Type constraint, e.g. list(string); empty for any type
+	Description string
+	Default     string // Expression, e.g. "eastus" with quotes; empty for a required variable
+	Sensitive   bool
+}
+
+// Output is an output block added by AddOutput.
+type Output struct {
+	Name        string
+	Value       string // Expression, e.g. azurerm_storage_account.this.id
+	Description string
+	Sensitive   bool
+}
+
+// blocksSchema selects the variable and output blocks of a file
+var blocksSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "output", LabelNames: []string{"name"}},
+	},
+}
+
+// typeSchema selects the type of a variable
+var typeSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "type"}},
+}
+
+// AddVariable appends a variable block for v to variables.tf in the module
+// at dir, creating the file when needed. The default must be a constant of
+// the variable's type. It returns an error when the module already declares
+// the variable in any of its files.
+func AddVariable(dir string, v Variable) error {
+	typeTokens, defaultTokens, err := checkVariable(dir, v)
+	if err != nil {
+		return err
+	}
+	return appendBlock(filepath.Join(dir, VariablesFile), "variable", v.Name, func(body *hclwrite.Body) {
+		if typeTokens != nil {
+			body.SetAttributeRaw("type", typeTokens)
+		}
+		if v.Description != "" {
+			body.SetAttributeValue("description", cty.StringVal(v.Description))
+		}
+		if defaultTokens != nil {
+			body.SetAttributeRaw("default", defaultTokens)
+		}
+		if v.Sensitive {
+			body.SetAttributeValue("sensitive", cty.True)
+		}
+	})
+}
+
+// CheckVariable returns the error AddVariable would return for v, without
+// changing the module at dir.
+func CheckVariable(dir string, v Variable) error {
+	_, _, err := checkVariable(dir, v)
+	return err
+}
+
+// checkVariable validates v and returns the tokens of its type and default,
+// nil when they're empty.
+func checkVariable(dir string, v Variable) (typeTokens, defaultTokens hclwrite.Tokens, err error) {
+	if !hclsyntax.ValidIdentifier(v.Name) {
+		return nil, nil, fmt.Errorf("invalid variable name '%s'", v.Name)
+	}
+	if slices.Contains(reservedVariableNames, v.Name) {
+		return nil, nil, fmt.Errorf("invalid variable name '%s': reserved by Terraform", v.Name)
+	}
+	ty, typeTokens, err := parseType(v.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	if v.Default != "" {
+		if defaultTokens, err = parseDefault(v.Default, ty); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := checkNotDeclared(dir, "variable", v.Name); err != nil {
+		return nil, nil, err
+	}
+	return typeTokens, defaultTokens, nil
+}
+
+// AddOutput appends an output block for o to outputs.tf in the module at
+// dir, creating the file when needed. It returns an error when the module
+// already declares the output in any of its files.
+func AddOutput(dir string, o Output) error {
+	if !hclsyntax.ValidIdentifier(o.Name) {
+		return fmt.Errorf("invalid output name '%s'", o.Name)
+	}
+	valueTokens, err := parseExpression(o.Value)
+	if err != nil {
+		return fmt.Errorf("invalid value '%s': %w", o.Value, err)
+	}
+	if err := checkNotDeclared(dir, "output", o.Name); err != nil {
+		return err
+	}
+
+	return appendBlock(filepath.Join(dir, OutputsFile), "output", o.Name, func(body *hclwrite.Body) {
+		if o.Description != "" {
+			body.SetAttributeValue("description", cty.StringVal(o.Description))
+		}
+		body.SetAttributeRaw("value", valueTokens)
+		if o.Sensitive {
+			body.SetAttributeValue("sensitive", cty.True)
+		}
+	})
+}
+
+// SetDefault sets the default of the variable name in the module at dir to
+// the expression value, replacing the current default, and returns the name
+// of the file it changed. The value must be a constant of the variable's
+// type. Variables in .tf.json files can't be changed.
+func SetDefault(dir, name, value string) (string, error) {
+	file, block, err := findBlock(dir, "variable", name)
+	if err != nil {
+		return "", err
+	}
+	if block == nil {
+		return "", fmt.Errorf("variable '%s' not found", name)
+	}
+	if strings.HasSuffix(file, ".json") {
+		return "", fmt.Errorf("variable '%s' is declared in %s: .tf.json files can't be edited", name, file)
+	}
+
+	ty := cty.DynamicPseudoType
+	content, _, _ := block.Body.PartialContent(typeSchema)
+	if attr, ok := content.Attributes["type"]; ok {
+		var diags hcl.Diagnostics
+		if ty, _, diags = typeexpr.TypeConstraintWithDefaults(attr.Expr); diags.HasErrors() {
+			return "", fmt.Errorf("%s: variable '%s' has an invalid type: %w", file, name, diags)
+		}
+	}
+	tokens, err := parseDefault(value, ty)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, file)
+	f, err := parseWritable(path)
+	if err != nil {
+		return "", err
+	}
+	f.Body().FirstMatchingBlock("variable", []string{name}).Body().SetAttributeRaw("default", tokens)
+	return file, save(path, f)
+}
+
+// parseType returns the type constraint typeExpr and its tokens, or any
+// type and no tokens when it's empty.
+func parseType(typeExpr string) (cty.Type, hclwrite.Tokens, error) {
+	if strings.TrimSpace(typeExpr) == "" {
+		return cty.DynamicPseudoType, nil, nil
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(typeExpr), "type", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilType, nil, fmt.Errorf("invalid type '%s': %w", typeExpr, diags)
+	}
+	ty, _, diags := typeexpr.TypeConstraintWithDefaults(expr)
+	if diags.HasErrors() {
+		return cty.NilType, nil, fmt.Errorf("invalid type '%s': %w", typeExpr, diags)
+	}
+	tokens, err := parseExpression(typeExpr)
+	return ty, tokens, err
+}
+
+// parseDefault returns the tokens of the default value, which must be a
+// constant that converts to ty.
+func parseDefault(value string, ty cty.Type) (hclwrite.Tokens, error) {
+	expr, diags := hclsyntax.ParseExpression([]byte(value), "default", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid default '%s': %w", value, diags)
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("invalid default '%s': must be a constant, like \"text\", 3, or [\"a\", \"b\"]", value)
+	}
+	if _, err := convert.Convert(val, ty); err != nil {
+		return nil, fmt.Errorf("invalid default '%s': %w", value, err)
+	}
+	return parseExpression(value)
+}
+
+// parseExpression returns the tokens of the expression src, as written.
+func parseExpression(src string) (hclwrite.Tokens, error) {
+	if _, diags := hclsyntax.ParseExpression([]byte(src), "expression", hcl.InitialPos); diags.HasErrors() {
+		return nil, diags
+	}
+	f, diags := hclwrite.ParseConfig([]byte("x = "+src+"\n"), "expression", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return f.Body().GetAttribute("x").Expr().BuildTokens(nil), nil
+}
+
+// checkNotDeclared returns an error when the module at dir declares the
+// variable or output name.
+func checkNotDeclared(dir, blockType, name string) error {
+	file, block, err := findBlock(dir, blockType, name)
+	if err != nil {
+		return err
+	}
+	if block != nil {
+		return fmt.Errorf("%s '%s' already exists in %s", blockType, name, file)
+	}
+	return nil
+}
+
+// findBlock returns the file and block declaring the variable or output name
+// in the .tf and .tf.json files of dir, or a nil block if there is none.
+func findBlock(dir, blockType, name string) (string, *hcl.Block, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !finder.IsTerraformFile(entry.Name()) {
+			continue
+		}
+		f, err := sources.ParseFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return "", nil, err
+		}
+		content, _, _ := f.Body.PartialContent(blocksSchema)
+		for _, block := range content.Blocks {
+			if block.Type == blockType && block.Labels[0] == name {
+				return entry.Name(), block, nil
+			}
+		}
+	}
+	return "", nil, nil
+}
+
+// appendBlock appends a block with the label name, filled by fill, to the
+// .tf file at path, creating it when it doesn't exist.
+func appendBlock(path, blockType, name string, fill func(body *hclwrite.Body)) error {
+	f := hclwrite.NewEmptyFile()
+	if _, err := os.Stat(path); err == nil {
+		if f, err = parseWritable(path); err != nil {
+			return err
+		}
+	}
+	body := f.Body()
+	if len(strings.TrimSpace(string(f.Bytes()))) > 0 {
+		body.AppendNewline()
+	}
+	fill(body.AppendNewBlock(blockType, []string{name}).Body())
+	return save(path, f)
+}
+
+// parseWritable parses the .tf file at path for editing.
+func parseWritable(path string) (*hclwrite.File, error) {
+	src, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	f, diags := hclwrite.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return f, nil
+}
+
+// save writes f to path, formatted like 'terraform fmt', keeping the
+// mode of an existing file.
+func save(path string, f *hclwrite.File) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, hclwrite.Format(f.Bytes()), mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
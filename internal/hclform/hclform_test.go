@@ -0,0 +1,155 @@
+package hclform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestAddVariable(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "variables.tf", `# Inputs of the storage account
+variable "name" {
+  type        = string
+  description = "Name of the account"
+}
+`)
+
+	err := AddVariable(dir, Variable{Name: "replicas", Type: "list(string)", Description: "Replica regions", Default: `["eastus"]`, Sensitive: true})
+	if err != nil {
+		t.Fatalf("AddVariable() error = %v", err)
+	}
+	want := `# Inputs of the storage account
+variable "name" {
+  type        = string
+  description = "Name of the account"
+}
+
+variable "replicas" {
+  type        = list(string)
+  description = "Replica regions"
+  default     = ["eastus"]
+  sensitive   = true
+}
+`
+	if got := readFile(t, dir, "variables.tf"); got != want {
+		t.Errorf("variables.tf:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAddVariable_CreatesFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.tf", "")
+
+	if err := AddVariable(dir, Variable{Name: "location"}); err != nil {
+		t.Fatalf("AddVariable() error = %v", err)
+	}
+	if got, want := readFile(t, dir, "variables.tf"), "variable \"location\" {\n}\n"; got != want {
+		t.Errorf("variables.tf = %q, want %q", got, want)
+	}
+}
+
+func TestAddVariable_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.tf.json", `{"variable": {"location": {"type": "string"}}}`)
+
+	tests := []struct {
+		name    string
+		v       Variable
+		wantErr string
+	}{
+		{"declared", Variable{Name: "location"}, "variable 'location' already exists in main.tf.json"},
+		{"invalid name", Variable{Name: "1st"}, "invalid variable name '1st'"},
+		{"reserved name", Variable{Name: "count"}, "reserved by Terraform"},
+		{"invalid type", Variable{Name: "x", Type: "strin"}, "invalid type 'strin'"},
+		{"default of another type", Variable{Name: "x", Type: "number", Default: `"many"`}, `invalid default '"many"'`},
+		{"default not constant", Variable{Name: "x", Default: "var.y"}, "must be a constant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AddVariable(dir, tt.v)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("AddVariable() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(dir, VariablesFile)); !os.IsNotExist(err) {
+		t.Error("variables.tf was created for an invalid variable")
+	}
+}
+
+func TestAddOutput(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := AddOutput(dir, Output{Name: "id", Value: "azurerm_storage_account.this.id", Description: "ID of the account"}); err != nil {
+		t.Fatalf("AddOutput() error = %v", err)
+	}
+	if err := AddOutput(dir, Output{Name: "id", Value: "x"}); err == nil || !strings.Contains(err.Error(), "already exists in outputs.tf") {
+		t.Errorf("AddOutput() of a declared output error = %v", err)
+	}
+	want := `output "id" {
+  description = "ID of the account"
+  value       = azurerm_storage_account.this.id
+}
+`
+	if got := readFile(t, dir, "outputs.tf"); got != want {
+		t.Errorf("outputs.tf:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.tf", `variable "sku" {
+  type    = string
+  default = "Standard"
+}
+
+variable "size" {
+  type = number
+}
+`)
+
+	for name, value := range map[string]string{"sku": `"Premium"`, "size": "3"} {
+		file, err := SetDefault(dir, name, value)
+		if err != nil || file != "main.tf" {
+			t.Fatalf("SetDefault(%s) = %q, %v", name, file, err)
+		}
+	}
+	want := `variable "sku" {
+  type    = string
+  default = "Premium"
+}
+
+variable "size" {
+  type    = number
+  default = 3
+}
+`
+	if got := readFile(t, dir, "main.tf"); got != want {
+		t.Errorf("main.tf:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := SetDefault(dir, "size", `"large"`); err == nil {
+		t.Error("SetDefault() with a default of another type succeeded")
+	}
+	if _, err := SetDefault(dir, "missing", "1"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("SetDefault() of a missing variable error = %v", err)
+	}
+}
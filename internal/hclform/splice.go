@@ -0,0 +1,25 @@
+package hclform
+
+import (
+	"slices"
+	"sort"
+)
+
+// Edit replaces the bytes from Start to End of a file with Text.
+type Edit struct {
+	Start, End int
+	Text       []byte
+}
+
+// Splice returns src with edits applied, leaving every other byte as
+// written. Edits must not overlap; src isn't modified.
+func Splice(src []byte, edits []Edit) []byte {
+	// Replace from the end so earlier byte offsets stay valid
+	edits = slices.Clone(edits)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Start > edits[j].Start })
+	out := slices.Clone(src)
+	for _, e := range edits {
+		out = slices.Replace(out, e.Start, e.End, e.Text...)
+	}
+	return out
+}
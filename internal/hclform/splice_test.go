@@ -0,0 +1,21 @@
+package hclform
+
+import "testing"
+
+func TestSplice(t *testing.T) {
+	src := []byte(`source = "a"` + "\n" + `version = "1.0" # pinned` + "\n")
+	edits := []Edit{
+		{Start: 9, End: 12, Text: []byte(`"b/c"`)},
+		{Start: 23, End: 28, Text: []byte(`"2.0"`)},
+		{Start: 37, End: 37, Text: []byte("\nextra = true")},
+	}
+
+	got := Splice(src, edits)
+	want := `source = "b/c"` + "\n" + `version = "2.0" # pinned` + "\n" + "extra = true\n"
+	if string(got) != want {
+		t.Errorf("Splice() =\n%s\nwant\n%s", got, want)
+	}
+	if string(src) != `source = "a"`+"\n"+`version = "1.0" # pinned`+"\n" {
+		t.Errorf("Splice() modified src: %s", src)
+	}
+}
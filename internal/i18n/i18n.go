@@ -0,0 +1,152 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is used when no supported language is detected
+const DefaultLanguage = "en"
+
+// EnvLanguage overrides the language detected from the locale
+const EnvLanguage = "MOTF_LANG"
+
+// localeEnvVars are checked in order of precedence to detect the language
+var localeEnvVars = []string{EnvLanguage, "LC_ALL", "LC_MESSAGES", "LANG"}
+
+// Catalog maps message IDs to fmt-style format strings
+type Catalog map[string]string
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]Catalog{DefaultLanguage: english}
+	current  = DetectLanguage(os.Getenv)
+)
+
+// Register adds (or replaces) the catalog for a language. Messages missing from
+// the catalog fall back to English.
+func Register(lang string, c Catalog) {
+	mu.Lock()
+	defer mu.Unlock()
+	catalogs[normalizeLanguage(lang)] = c
+}
+
+// Languages returns the sorted list of languages with a registered catalog.
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// SetLanguage selects the active language. Unknown languages fall back to English.
+func SetLanguage(lang string) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = normalizeLanguage(lang)
+}
+
+// Language returns the active language.
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// DetectLanguage determines the language from MOTF_LANG, LC_ALL, LC_MESSAGES, and LANG.
+// The "C" and "POSIX" locales map to English.
+func DetectLanguage(getenv func(string) string) string {
+	for _, key := range localeEnvVars {
+		if value := getenv(key); value != "" {
+			return normalizeLanguage(value)
+		}
+	}
+	return DefaultLanguage
+}
+
+// normalizeLanguage reduces a locale such as "de_DE.UTF-8" to its language code ("de").
+func normalizeLanguage(locale string) string {
+	lang := locale
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if i := strings.IndexAny(lang, "_-"); i >= 0 {
+		lang = lang[:i]
+	}
+	lang = strings.ToLower(lang)
+
+	if lang == "" || lang == "c" || lang == "posix" {
+		return DefaultLanguage
+	}
+	return lang
+}
+
+// T returns the localized message for id, formatted with args.
+// It falls back to English, then to the message ID itself.
+func T(id string, args ...any) string {
+	mu.RLock()
+	format, ok := catalogs[current][id]
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][id]
+	}
+	mu.RUnlock()
+
+	if !ok {
+		format = id
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Error is a user-facing error that states the problem, its cause, and how to fix it.
+//
+// Format:
+//
+//	<problem>: <cause>
+//
+//	<remediation>
+type Error struct {
+	Problem     string
+	Cause       string
+	Remediation string
+	Err         error // optional underlying error
+}
+
+// Errorf creates an Error from already-localized parts. Empty parts are omitted.
+func Errorf(problem, cause, remediation string) *Error {
+	return &Error{Problem: problem, Cause: cause, Remediation: remediation}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Problem)
+	if e.Cause != "" {
+		// Multi-line causes (such as lists) start on their own line
+		if strings.HasPrefix(e.Cause, "\n") {
+			b.WriteString(":")
+		} else {
+			b.WriteString(": ")
+		}
+		b.WriteString(e.Cause)
+	}
+	if e.Remediation != "" {
+		b.WriteString("\n\n")
+		b.WriteString(e.Remediation)
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
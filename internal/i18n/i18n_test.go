@@ -0,0 +1,148 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+)
+
+// withLanguage sets the active language for the duration of the test.
+func withLanguage(t *testing.T, lang string) {
+	t.Helper()
+	previous := Language()
+	SetLanguage(lang)
+	t.Cleanup(func() { SetLanguage(previous) })
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "no locale", env: map[string]string{}, want: "en"},
+		{name: "C locale", env: map[string]string{"LANG": "C"}, want: "en"},
+		{name: "POSIX locale", env: map[string]string{"LC_ALL": "POSIX"}, want: "en"},
+		{name: "C.UTF-8 locale", env: map[string]string{"LANG": "C.UTF-8"}, want: "en"},
+		{name: "territory and encoding stripped", env: map[string]string{"LANG": "de_DE.UTF-8"}, want: "de"},
+		{name: "LC_ALL wins over LANG", env: map[string]string{"LC_ALL": "fr_FR", "LANG": "de_DE"}, want: "fr"},
+		{name: "MOTF_LANG wins over everything", env: map[string]string{EnvLanguage: "ja", "LC_ALL": "fr_FR"}, want: "ja"},
+		{name: "modifier stripped", env: map[string]string{"LANG": "sr_RS@latin"}, want: "sr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectLanguage(func(key string) string { return tt.env[key] })
+			if got != tt.want {
+				t.Errorf("DetectLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT_English(t *testing.T) {
+	withLanguage(t, "en")
+
+	got := T(MsgErrModuleNotFound, "storage-account", "components, bases, projects")
+	want := "module 'storage-account' not found in components, bases, projects"
+	if got != want {
+		t.Errorf("T() = %q, want %q", got, want)
+	}
+}
+
+func TestT_RegisteredTranslationWithFallback(t *testing.T) {
+	Register("xx", Catalog{MsgErrNoTarget: "translated"})
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(catalogs, "xx")
+		mu.Unlock()
+	})
+	withLanguage(t, "xx_YY.UTF-8")
+
+	if got := T(MsgErrNoTarget); got != "translated" {
+		t.Errorf("expected translated message, got %q", got)
+	}
+	// Missing translations fall back to English
+	if got := T(MsgHintNameClash); got != english[MsgHintNameClash] {
+		t.Errorf("expected English fallback, got %q", got)
+	}
+}
+
+func TestT_UnknownID(t *testing.T) {
+	if got := T("no.such.message"); got != "no.such.message" {
+		t.Errorf("expected message ID as fallback, got %q", got)
+	}
+}
+
+func TestLanguages_IncludesEnglish(t *testing.T) {
+	found := false
+	for _, lang := range Languages() {
+		if lang == "en" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected 'en' to be registered")
+	}
+}
+
+func TestError_Format(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{
+			name: "problem only",
+			err:  Errorf("something failed", "", ""),
+			want: "something failed",
+		},
+		{
+			name: "problem and cause",
+			err:  Errorf("module not found", "no .tf files", ""),
+			want: "module not found: no .tf files",
+		},
+		{
+			name: "all parts",
+			err:  Errorf("module not found", "no .tf files", "Run 'motf list'."),
+			want: "module not found: no .tf files\n\nRun 'motf list'.",
+		},
+		{
+			name: "multi-line cause",
+			err:  Errorf("name clash", "\n  1. a\n  2. b", "Use --path."),
+			want: "name clash:\n  1. a\n  2. b\n\nUse --path.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError_Unwrap(t *testing.T) {
+	inner := errors.New("inner")
+	err := &Error{Problem: "outer", Err: inner}
+	if !errors.Is(err, inner) {
+		t.Error("expected errors.Is to find the wrapped error")
+	}
+}
+
+func TestEnglishCatalog_HasAllMessages(t *testing.T) {
+	ids := []string{
+		MsgRootShort, MsgRootLong, MsgFlagConfig, MsgFlagPath, MsgFlagArgs, MsgFlagIndex,
+		MsgErrPathWithName, MsgErrNoTarget, MsgHintNoTarget, MsgErrPathNotExist,
+		MsgErrModuleNotFound, MsgCauseModuleNotFound, MsgHintModuleNotFound,
+		MsgErrModuleUnmanaged, MsgCauseModuleUnmanaged, MsgHintModuleUnmanaged,
+		MsgErrNameClash, MsgHintNameClash, MsgErrExampleNotFound, MsgHintExampleNotFound,
+		MsgErrExampleNotTerraform, MsgCauseNoTerraformFiles, MsgFlagAllowNonModule,
+		MsgNoteNonModuleRedirect,
+	}
+	for _, id := range ids {
+		if english[id] == "" {
+			t.Errorf("english catalog is missing message %q", id)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package i18n
+
+// Message IDs for user-facing strings.
+//
+// To add a translation, create a Catalog with the same IDs (any subset) and
+// call Register with its language code, e.g. Register("de", german).
+const (
+	// Root command help
+	MsgRootShort = "root.short"
+	MsgRootLong  = "root.long"
+
+	// Global flag usage
+	MsgFlagConfig = "flag.config"
+	MsgFlagPath   = "flag.path"
+	MsgFlagArgs   = "flag.args"
+	MsgFlagDryRun = "flag.dry_run"
+	MsgFlagIndex  = "flag.index"
+
+	MsgFlagAllowNonModule = "flag.allow_non_module"
+
+	// Target resolution errors
+	MsgErrPathWithName        = "error.path_with_name"
+	MsgErrNoTarget            = "error.no_target"
+	MsgHintNoTarget           = "hint.no_target"
+	MsgErrPathNotExist        = "error.path_not_exist"
+	MsgErrModuleNotFound      = "error.module_not_found"
+	MsgCauseModuleNotFound    = "cause.module_not_found"
+	MsgHintModuleNotFound     = "hint.module_not_found"
+	MsgErrModuleUnmanaged     = "error.module_unmanaged"
+	MsgCauseModuleUnmanaged   = "cause.module_unmanaged"
+	MsgHintModuleUnmanaged    = "hint.module_unmanaged"
+	MsgErrNameClash           = "error.name_clash"
+	MsgHintNameClash          = "hint.name_clash"
+	MsgErrExampleNotFound     = "error.example_not_found"
+	MsgHintExampleNotFound    = "hint.example_not_found"
+	MsgErrExampleNotTerraform = "error.example_not_terraform"
+	MsgCauseNoTerraformFiles  = "cause.no_terraform_files"
+	MsgNoteNonModuleRedirect  = "note.non_module_redirect"
+)
+
+// english is the default catalog, also used for the C and POSIX locales.
+var english = Catalog{
+	MsgRootShort: "Terraform Monorepo Orchestrator (pronounced 'motif')",
+	MsgRootLong: `motf (Terraform Monorepo Orchestrator) is a CLI tool for working with Terraform monorepos.
+
+It supports running terraform/tofu commands on components, bases, and projects organized
+in a structured monorepo.`,
+
+	MsgFlagConfig: "Path to config file (default: searches for .motf.yml)",
+	MsgFlagPath:   "Explicit path (mutually exclusive with module name)",
+	MsgFlagArgs:   "Extra arguments to pass to terraform/tofu (can be specified multiple times)",
+	MsgFlagDryRun: "Print each resolved command and working directory instead of executing it",
+	MsgFlagIndex:  "Read modules from a 'motf index export' file instead of walking the repository",
+
+	MsgFlagAllowNonModule: "Run in a --path inside a module's examples or tests directory instead of the module",
+
+	MsgErrPathWithName:        "--path is mutually exclusive with module name argument",
+	MsgErrNoTarget:            "must specify either a module name or --path",
+	MsgHintNoTarget:           "Run 'motf list' to see available modules.",
+	MsgErrPathNotExist:        "path does not exist: %s",
+	MsgErrModuleNotFound:      "module '%s' not found in %s",
+	MsgCauseModuleNotFound:    "no directory with that name contains .tf files under %s",
+	MsgHintModuleNotFound:     "Run 'motf list' to see available modules, or use --path to target a directory directly.",
+	MsgErrModuleUnmanaged:     "module '%s' is outside managed_paths",
+	MsgCauseModuleUnmanaged:   "motf only manages modules in: %s",
+	MsgHintModuleUnmanaged:    "Add its path to managed_paths in .motf.yml, or use --path to target it directly.",
+	MsgErrNameClash:           "multiple modules named '%s' found - name clash detected",
+	MsgHintNameClash:          "Use a qualified name from the list, or --path to specify the exact path",
+	MsgErrExampleNotFound:     "example '%s' not found in %s",
+	MsgHintExampleNotFound:    "Run 'motf get <module>' to list the module's examples.",
+	MsgErrExampleNotTerraform: "example '%s' is not a valid terraform module",
+	MsgCauseNoTerraformFiles:  "no .tf files found",
+	MsgNoteNonModuleRedirect:  "%s is in the %s directory of module %s, running on the module instead (use -e to target an example, or --allow-non-module to run in the directory)",
+}
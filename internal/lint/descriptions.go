@@ -0,0 +1,209 @@
+// Package lint checks Terraform modules against the repository's
+// conventions and fixes violations where it can.
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/sources"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// DefaultDescriptionPlaceholder is the description inserted by FixDescriptions
+// when none is configured.
+const DefaultDescriptionPlaceholder = "TODO: add a description"
+
+// Finding is a variable or output without a description.
+type Finding struct {
+	Module  string `json:"module,omitempty"` // Set by the caller
+	File    string `json:"file"`             // Relative to the module directory
+	Line    int    `json:"line"`
+	Kind    string `json:"kind"` // variable or output
+	Name    string `json:"name"`
+	Fixable bool   `json:"fixable"` // Whether FixDescriptions can add the description
+	Fixed   bool   `json:"fixed,omitempty"`
+}
+
+// String returns e.g. "variables.tf:3: variable.location has no description".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s.%s has no description", f.File, f.Line, f.Kind, f.Name)
+}
+
+// describedSchema selects the variable and output blocks of a file
+var describedSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "variable", LabelNames: []string{"name"}},
+		{Type: "output", LabelNames: []string{"name"}},
+	},
+}
+
+// descriptionSchema selects the description of a variable or output
+var descriptionSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "description"}},
+}
+
+// MissingDescriptions returns the variables and outputs in the .tf and
+// .tf.json files of dir that have no description, or an empty one.
+func MissingDescriptions(dir string) ([]Finding, error) {
+	files, err := terraformFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, file := range files {
+		f, err := sources.ParseFile(filepath.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+		content, _, _ := f.Body.PartialContent(describedSchema)
+		for _, block := range content.Blocks {
+			described, present := hasDescription(block)
+			if described {
+				continue
+			}
+			findings = append(findings, Finding{
+				File:    file,
+				Line:    block.DefRange.Start.Line,
+				Kind:    block.Type,
+				Name:    block.Labels[0],
+				Fixable: !present && !strings.HasSuffix(file, ".json"),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// hasDescription reports whether block sets a non-empty description, and
+// whether it has a description argument at all. A description that isn't a
+// literal string, e.g. a template, counts as set.
+func hasDescription(block *hcl.Block) (described, present bool) {
+	content, _, _ := block.Body.PartialContent(descriptionSchema)
+	attr, ok := content.Attributes["description"]
+	if !ok {
+		return false, false
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !value.IsKnown() || value.IsNull() || value.Type() != cty.String {
+		return true, true
+	}
+	return strings.TrimSpace(value.AsString()) != "", true
+}
+
+// FixDescriptions adds a description with the placeholder text to the
+// fixable findings: variables and outputs in .tf files without a description
+// argument. It returns all findings with the fixed ones marked. Changed files
+// are formatted like 'terraform fmt'. Empty descriptions and .tf.json files
+// are left for a human.
+func FixDescriptions(dir, placeholder string) ([]Finding, error) {
+	if placeholder == "" {
+		placeholder = DefaultDescriptionPlaceholder
+	}
+	findings, err := MissingDescriptions(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byFile := make(map[string][]int)
+	for i, f := range findings {
+		if f.Fixable {
+			byFile[f.File] = append(byFile[f.File], i)
+		}
+	}
+	for file, indexes := range byFile {
+		path := filepath.Join(dir, file)
+		fixed, err := insertDescriptions(path, placeholder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fix %s: %w", file, err)
+		}
+		for _, i := range indexes {
+			findings[i].Fixed = slices.Contains(fixed, findings[i].Kind+"."+findings[i].Name)
+		}
+	}
+	return findings, nil
+}
+
+// insertDescriptions adds the placeholder description as the first argument
+// of each variable and output block in the .tf file that has no description
+// argument, and returns the addresses (e.g. "variable.location") it fixed.
+func insertDescriptions(path, placeholder string) ([]string, error) {
+	src, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	f, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	type edit struct {
+		offset int
+		text   string
+	}
+	var edits []edit
+	var fixed []string
+	description := "description = " + string(hclwrite.TokensForValue(cty.StringVal(placeholder)).Bytes())
+	for _, block := range f.Body.(*hclsyntax.Body).Blocks {
+		if (block.Type != "variable" && block.Type != "output") || len(block.Labels) != 1 {
+			continue
+		}
+		if _, ok := block.Body.Attributes["description"]; ok {
+			continue
+		}
+		openEnd, closeStart := block.OpenBraceRange.End.Byte, block.CloseBraceRange.Start.Byte
+		if block.OpenBraceRange.Start.Line != block.CloseBraceRange.Start.Line {
+			edits = append(edits, edit{offset: openEnd, text: "\n" + description})
+		} else {
+			// A single-line block is split so each argument is on its own line
+			edits = append(edits, edit{offset: openEnd, text: "\n" + description + "\n"})
+			if len(bytes.TrimSpace(src[openEnd:closeStart])) > 0 {
+				edits = append(edits, edit{offset: closeStart, text: "\n"})
+			}
+		}
+		fixed = append(fixed, block.Type+"."+block.Labels[0])
+	}
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	// Insert from the end so earlier byte offsets stay valid
+	sort.Slice(edits, func(i, j int) bool { return edits[i].offset > edits[j].offset })
+	out := slices.Clone(src)
+	for _, e := range edits {
+		out = slices.Insert(out, e.offset, []byte(e.text)...)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, hclwrite.Format(out), info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return fixed, nil
+}
+
+// terraformFiles returns the names of the .tf and .tf.json files in dir, sorted.
+func terraformFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && finder.IsTerraformFile(entry.Name()) {
+			files = append(files, entry.Name())
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,164 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestMissingDescriptions(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "variables.tf", `variable "name" {
+  description = "Name of the account"
+  type        = string
+}
+
+variable "location" {
+  type = string
+
+  validation {
+    condition     = length(var.location) > 0
+    error_message = "Location is required."
+  }
+}
+
+variable "tags" {
+  description = " "
+}
+`)
+	writeFile(t, dir, "outputs.tf.json", `{"output": {"id": {"value": "x"}, "name": {"value": "y", "description": "Name"}}}`)
+
+	findings, err := MissingDescriptions(dir)
+	if err != nil {
+		t.Fatalf("MissingDescriptions() error = %v", err)
+	}
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"outputs.tf.json:1: output.id has no description",
+		"variables.tf:6: variable.location has no description",
+		"variables.tf:15: variable.tags has no description",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for i, fixable := range []bool{false, true, false} {
+		if findings[i].Fixable != fixable {
+			t.Errorf("%s: Fixable = %v, want %v", findings[i], findings[i].Fixable, fixable)
+		}
+	}
+}
+
+func TestFixDescriptions(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "main.tf", `# Inputs
+variable "location" {
+  type    = string
+  default = "westeurope"
+}
+
+variable "empty" {}
+
+variable "inline" { type = string }
+
+variable "blank" {
+  description = ""
+}
+
+output "id" {
+  value = "x" # The ID
+}
+`)
+	writeFile(t, dir, "extra.tf.json", `{"variable": {"sku": {}}}`)
+
+	findings, err := FixDescriptions(dir, "TODO: describe ${var}")
+	if err != nil {
+		t.Fatalf("FixDescriptions() error = %v", err)
+	}
+
+	fixed := map[string]bool{}
+	for _, f := range findings {
+		fixed[f.Kind+"."+f.Name] = f.Fixed
+	}
+	for address, want := range map[string]bool{
+		"variable.location": true,
+		"variable.empty":    true,
+		"variable.inline":   true,
+		"output.id":         true,
+		"variable.blank":    false,
+		"variable.sku":      false,
+	} {
+		if got, ok := fixed[address]; !ok || got != want {
+			t.Errorf("%s: fixed = %v (found %v), want %v", address, got, ok, want)
+		}
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		t.Fatalf("failed to read main.tf: %v", err)
+	}
+	want := `# Inputs
+variable "location" {
+  description = "TODO: describe $${var}"
+  type        = string
+  default     = "westeurope"
+}
+
+variable "empty" {
+  description = "TODO: describe $${var}"
+}
+
+variable "inline" {
+  description = "TODO: describe $${var}"
+  type        = string
+}
+
+variable "blank" {
+  description = ""
+}
+
+output "id" {
+  description = "TODO: describe $${var}"
+  value       = "x" # The ID
+}
+`
+	if string(got) != want {
+		t.Errorf("main.tf:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Fixed files have no findings left
+	remaining, err := MissingDescriptions(dir)
+	if err != nil {
+		t.Fatalf("MissingDescriptions() error = %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("expected the empty and .tf.json descriptions to remain, got %v", remaining)
+	}
+}
+
+func TestFixDescriptions_DefaultPlaceholder(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "variables.tf", "variable \"name\" {\n  type = string\n}\n")
+
+	if _, err := FixDescriptions(dir, ""); err != nil {
+		t.Fatalf("FixDescriptions() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "variables.tf"))
+	if err != nil {
+		t.Fatalf("failed to read variables.tf: %v", err)
+	}
+	if !strings.Contains(string(got), `description = "`+DefaultDescriptionPlaceholder+`"`) {
+		t.Errorf("expected the default placeholder, got:\n%s", got)
+	}
+}
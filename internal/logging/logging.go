@@ -0,0 +1,150 @@
+// Package logging writes motf's own diagnostics, such as notes, warnings, and
+// debug messages, to stderr with a level, separate from terraform/tofu output.
+// Text lines keep the "Note:" and "Warning:" prefixes people read; JSON lines
+// are for CI to parse.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a message.
+type Level int
+
+// Levels, from the most to the least verbose
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the name of the level, as written in JSON lines.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// textPrefixes are the prefixes of text lines by level
+var textPrefixes = map[Level]string{
+	LevelDebug: "Debug: ",
+	LevelInfo:  "Note: ",
+	LevelWarn:  "Warning: ",
+	LevelError: "Error: ",
+}
+
+// Log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats are the valid log formats
+var Formats = []string{FormatText, FormatJSON}
+
+// Logger writes messages at or above its level to a writer.
+type Logger struct {
+	mu     sync.Mutex
+	out    io.Writer
+	level  Level
+	format string
+	now    func() time.Time
+}
+
+// New returns a logger writing messages at or above level to out in format.
+func New(out io.Writer, level Level, format string) *Logger {
+	return &Logger{out: out, level: level, format: format, now: time.Now}
+}
+
+// Enabled reports whether messages at level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+// Format returns the format of the logger.
+func (l *Logger) Format() string {
+	return l.format
+}
+
+// Logf writes a message at level, formatted like fmt.Sprintf. A trailing
+// newline is dropped; other lines of a multi-line message are written as is
+// in text, or as part of the message in JSON.
+func (l *Logger) Logf(level Level, format string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+	msg := strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+
+	var line []byte
+	if l.format == FormatJSON {
+		line, _ = json.Marshal(struct {
+			Time  string `json:"time"`
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}{l.now().UTC().Format(time.RFC3339), level.String(), msg})
+	} else {
+		line = []byte(textPrefixes[level] + msg)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(append(line, '\n'))
+}
+
+// Debugf writes a debug message, shown with --verbose.
+func (l *Logger) Debugf(format string, args ...any) { l.Logf(LevelDebug, format, args...) }
+
+// Infof writes a note, hidden by --quiet.
+func (l *Logger) Infof(format string, args ...any) { l.Logf(LevelInfo, format, args...) }
+
+// Warnf writes a warning.
+func (l *Logger) Warnf(format string, args ...any) { l.Logf(LevelWarn, format, args...) }
+
+// Errorf writes an error.
+func (l *Logger) Errorf(format string, args ...any) { l.Logf(LevelError, format, args...) }
+
+var (
+	stdMu sync.RWMutex
+	std   = New(os.Stderr, LevelInfo, FormatText)
+)
+
+// Configure replaces the default logger, which writes notes, warnings, and
+// errors to stderr as text.
+func Configure(out io.Writer, level Level, format string) {
+	stdMu.Lock()
+	defer stdMu.Unlock()
+	std = New(out, level, format)
+}
+
+// Default returns the default logger.
+func Default() *Logger {
+	stdMu.RLock()
+	defer stdMu.RUnlock()
+	return std
+}
+
+// Debugf writes a debug message with the default logger.
+func Debugf(format string, args ...any) { Default().Logf(LevelDebug, format, args...) }
+
+// Infof writes a note with the default logger.
+func Infof(format string, args ...any) { Default().Logf(LevelInfo, format, args...) }
+
+// Warnf writes a warning with the default logger.
+func Warnf(format string, args ...any) { Default().Logf(LevelWarn, format, args...) }
+
+// Errorf writes an error with the default logger.
+func Errorf(format string, args ...any) { Default().Logf(LevelError, format, args...) }
@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLogger_Text(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, LevelInfo, FormatText)
+	l.Debugf("hidden %d", 1)
+	l.Infof("%d module(s) are skipped", 2)
+	l.Warnf("no owner for %s\n", "components/dns")
+	l.Errorf("failed")
+
+	want := "Note: 2 module(s) are skipped\nWarning: no owner for components/dns\nError: failed\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestLogger_Levels(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  string
+	}{
+		{LevelDebug, "Debug: d\nNote: i\nWarning: w\n"},
+		{LevelInfo, "Note: i\nWarning: w\n"},
+		{LevelWarn, "Warning: w\n"},
+		{LevelError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			l := New(&buf, tt.level, FormatText)
+			l.Debugf("d")
+			l.Infof("i")
+			l.Warnf("w")
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, LevelDebug, FormatJSON)
+	l.now = func() time.Time { return time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC) }
+	l.Warnf("module %q has no \"owner\"", "dns")
+	l.Debugf("line 1\nline 2")
+
+	want := `{"time":"2026-10-16T08:30:00Z","level":"warn","msg":"module \"dns\" has no \"owner\""}` + "\n" +
+		`{"time":"2026-10-16T08:30:00Z","level":"debug","msg":"line 1\nline 2"}` + "\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	original := Default()
+	t.Cleanup(func() { std = original })
+
+	var buf bytes.Buffer
+	Configure(&buf, LevelWarn, FormatText)
+	Infof("hidden")
+	Warnf("shown")
+	if buf.String() != "Warning: shown\n" {
+		t.Errorf("output = %q, want the warning only", buf.String())
+	}
+}
@@ -0,0 +1,310 @@
+// Package metrics records the duration and outcome of motf commands and the
+// modules they ran, and emits them as a Prometheus textfile, StatsD metrics,
+// or JSON lines, to track CI performance over time.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats metrics are emitted in
+const (
+	FormatPrometheus = "prometheus" // Textfile for the node_exporter textfile collector, replaced on each run
+	FormatStatsD     = "statsd"     // Timers and counters sent over UDP
+	FormatJSON       = "json"       // One line per command and module, appended to a file
+)
+
+// Formats lists the valid formats.
+var Formats = []string{FormatPrometheus, FormatStatsD, FormatJSON}
+
+// DefaultPrefix is the prefix of metric names
+const DefaultPrefix = "motf"
+
+// DefaultStatsDAddress is the address StatsD metrics are sent to
+const DefaultStatsDAddress = "127.0.0.1:8125"
+
+// Command statuses
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusChanges   = "changes" // A plan had changes
+)
+
+// ReservedLabels are labels motf sets itself, which can't be used as extra labels.
+var ReservedLabels = []string{"command", "module", "path", "status"}
+
+// labelPattern matches valid label and metric names
+var labelPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ValidLabel reports whether name can be used as an extra label.
+func ValidLabel(name string) bool {
+	return labelPattern.MatchString(name) && !strings.HasPrefix(name, "__") && !slices.Contains(ReservedLabels, name)
+}
+
+// ValidPrefix reports whether prefix can start metric names.
+func ValidPrefix(prefix string) bool {
+	return labelPattern.MatchString(prefix)
+}
+
+// Run is the outcome of a command.
+type Run struct {
+	Command  string
+	Status   string // StatusSucceeded, StatusFailed, or StatusChanges
+	ExitCode int
+	Time     time.Time // When the command finished
+	Duration time.Duration
+	Modules  []Module // Modules of a multi-module run
+}
+
+// Module is the outcome of a module in a run.
+type Module struct {
+	Name     string
+	Path     string // Slash-separated, relative to the root
+	Status   string // succeeded, failed, skipped, quarantined, or flaky
+	Duration time.Duration
+}
+
+// Emitter emits runs in one of the Formats.
+type Emitter struct {
+	Format  string
+	Path    string            // File of the prometheus and json formats
+	Address string            // host:port of the statsd format
+	Prefix  string            // Prefix of metric names
+	Labels  map[string]string // Extra labels added to every metric
+}
+
+// Emit writes the metrics of run.
+func (e Emitter) Emit(run Run) error {
+	switch e.Format {
+	case FormatPrometheus:
+		return e.writePrometheusFile(run)
+	case FormatJSON:
+		return e.appendJSON(run)
+	case FormatStatsD:
+		return e.sendStatsD(run)
+	default:
+		return fmt.Errorf("unknown metrics format '%s'", e.Format)
+	}
+}
+
+// prefix returns the prefix of metric names, defaulting to DefaultPrefix.
+func (e Emitter) prefix() string {
+	if e.Prefix == "" {
+		return DefaultPrefix
+	}
+	return e.Prefix
+}
+
+// writePrometheusFile replaces the textfile with the metrics of run. The
+// file is written next to it first and renamed, so the collector never
+// reads a partial file.
+func (e Emitter) writePrometheusFile(run Run) error {
+	dir := filepath.Dir(e.Path)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(e.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if err := WritePrometheus(tmp, e.prefix(), e.Labels, run); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), e.Path); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// appendJSON appends the metrics of run to the file as JSON lines.
+func (e Emitter) appendJSON(run Run) error {
+	if err := os.MkdirAll(filepath.Dir(e.Path), 0750); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+	f, err := os.OpenFile(e.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := WriteJSON(f, e.Labels, run); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// sendStatsD sends the metrics of run to the StatsD server, one datagram per metric.
+func (e Emitter) sendStatsD(run Run) error {
+	address := e.Address
+	if address == "" {
+		address = DefaultStatsDAddress
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to statsd at %s: %w", address, err)
+	}
+	defer func() { _ = conn.Close() }()
+	for _, line := range StatsDLines(e.prefix(), e.Labels, run) {
+		if _, err := io.WriteString(conn, line); err != nil {
+			return fmt.Errorf("failed to send metrics to statsd at %s: %w", address, err)
+		}
+	}
+	return nil
+}
+
+// WritePrometheus writes run in the Prometheus text exposition format.
+func WritePrometheus(w io.Writer, prefix string, labels map[string]string, run Run) error {
+	var b strings.Builder
+	commandLabels := map[string]string{"command": run.Command}
+	withStatus := map[string]string{"command": run.Command, "status": run.Status}
+
+	writeHeader(&b, prefix+"_command_duration_seconds", "Duration of the last run of the command.")
+	writeSample(&b, prefix+"_command_duration_seconds", labels, withStatus, seconds(run.Duration))
+	writeHeader(&b, prefix+"_command_exit_code", "Exit code of the last run of the command.")
+	writeSample(&b, prefix+"_command_exit_code", labels, commandLabels, strconv.Itoa(run.ExitCode))
+	writeHeader(&b, prefix+"_command_last_run_timestamp_seconds", "Time the last run of the command finished.")
+	writeSample(&b, prefix+"_command_last_run_timestamp_seconds", labels, commandLabels, strconv.FormatInt(run.Time.Unix(), 10))
+
+	if len(run.Modules) > 0 {
+		writeHeader(&b, prefix+"_module_duration_seconds", "Duration of each module in the last run of the command.")
+		for _, m := range run.Modules {
+			writeSample(&b, prefix+"_module_duration_seconds", labels, moduleLabels(run, m), seconds(m.Duration))
+		}
+		writeHeader(&b, prefix+"_modules", "Modules of the last run of the command by status.")
+		for _, status := range moduleStatuses(run.Modules) {
+			count := 0
+			for _, m := range run.Modules {
+				if m.Status == status {
+					count++
+				}
+			}
+			writeSample(&b, prefix+"_modules", labels, map[string]string{"command": run.Command, "status": status}, strconv.Itoa(count))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeHeader writes the HELP and TYPE lines of a gauge.
+func writeHeader(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// writeSample writes a sample with the extra labels and the labels of motf,
+// sorted by name.
+func writeSample(b *strings.Builder, name string, extra, labels map[string]string, value string) {
+	merged := make(map[string]string, len(extra)+len(labels))
+	maps.Copy(merged, extra)
+	maps.Copy(merged, labels)
+	pairs := make([]string, 0, len(merged))
+	for _, k := range slices.Sorted(maps.Keys(merged)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, strconv.Quote(merged[k])))
+	}
+	fmt.Fprintf(b, "%s{%s} %s\n", name, strings.Join(pairs, ","), value)
+}
+
+// moduleLabels returns the labels of a module sample.
+func moduleLabels(run Run, m Module) map[string]string {
+	return map[string]string{"command": run.Command, "module": m.Name, "path": m.Path, "status": m.Status}
+}
+
+// moduleStatuses returns the statuses of the modules, sorted.
+func moduleStatuses(modules []Module) []string {
+	var statuses []string
+	for _, m := range modules {
+		if !slices.Contains(statuses, m.Status) {
+			statuses = append(statuses, m.Status)
+		}
+	}
+	slices.Sort(statuses)
+	return statuses
+}
+
+// seconds formats d as seconds with millisecond precision.
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+// jsonRecord is a line of the json format, for a command or, with Module
+// set, one of its modules.
+type jsonRecord struct {
+	Time       string            `json:"time"`
+	Command    string            `json:"command"`
+	Module     string            `json:"module,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Status     string            `json:"status"`
+	ExitCode   *int              `json:"exit_code,omitempty"`
+	DurationMs int64             `json:"duration_ms"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// WriteJSON writes run as JSON lines: one per module, then one for the command.
+func WriteJSON(w io.Writer, labels map[string]string, run Run) error {
+	timestamp := run.Time.UTC().Format(time.RFC3339)
+	enc := json.NewEncoder(w)
+	for _, m := range run.Modules {
+		record := jsonRecord{Time: timestamp, Command: run.Command, Module: m.Name, Path: m.Path, Status: m.Status, DurationMs: m.Duration.Milliseconds(), Labels: labels}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+	exitCode := run.ExitCode
+	return enc.Encode(jsonRecord{Time: timestamp, Command: run.Command, Status: run.Status, ExitCode: &exitCode, DurationMs: run.Duration.Milliseconds(), Labels: labels})
+}
+
+// statsdUnsafe matches characters that can't be used in StatsD metric names
+var statsdUnsafe = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// StatsDLines returns the StatsD metrics of run: a timer and a counter of
+// the status of the command and of each module, e.g.
+// motf.plan.duration:12300|ms and motf.plan.module.network.failed:1|c.
+// Extra labels are added as DogStatsD tags.
+func StatsDLines(prefix string, labels map[string]string, run Run) []string {
+	var tags string
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels))
+		for _, k := range slices.Sorted(maps.Keys(labels)) {
+			pairs = append(pairs, k+":"+labels[k])
+		}
+		tags = "|#" + strings.Join(pairs, ",")
+	}
+	command := prefix + "." + statsdName(run.Command)
+	lines := []string{
+		fmt.Sprintf("%s.duration:%d|ms%s", command, run.Duration.Milliseconds(), tags),
+		fmt.Sprintf("%s.%s:1|c%s", command, run.Status, tags),
+	}
+	for _, m := range run.Modules {
+		module := command + ".module." + statsdName(m.Name)
+		lines = append(lines,
+			fmt.Sprintf("%s.duration:%d|ms%s", module, m.Duration.Milliseconds(), tags),
+			fmt.Sprintf("%s.%s:1|c%s", module, m.Status, tags),
+		)
+	}
+	return lines
+}
+
+// statsdName replaces the characters of name StatsD can't use with underscores.
+func statsdName(name string) string {
+	return statsdUnsafe.ReplaceAllString(name, "_")
+}
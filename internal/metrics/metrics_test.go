@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testRun returns a plan run of two modules, one of which failed.
+func testRun() Run {
+	return Run{
+		Command:  "plan",
+		Status:   StatusFailed,
+		ExitCode: 1,
+		Time:     time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC),
+		Duration: 12300 * time.Millisecond,
+		Modules: []Module{
+			{Name: "network", Path: "components/network", Status: "failed", Duration: 3200 * time.Millisecond},
+			{Name: "dns", Path: "components/dns", Status: "succeeded", Duration: 1500 * time.Millisecond},
+		},
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf, "motf", map[string]string{"pipeline": "pr"}, testRun()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# TYPE motf_command_duration_seconds gauge\n",
+		`motf_command_duration_seconds{command="plan",pipeline="pr",status="failed"} 12.300` + "\n",
+		`motf_command_exit_code{command="plan",pipeline="pr"} 1` + "\n",
+		`motf_command_last_run_timestamp_seconds{command="plan",pipeline="pr"} 1792139400` + "\n",
+		`motf_module_duration_seconds{command="plan",module="network",path="components/network",pipeline="pr",status="failed"} 3.200` + "\n",
+		`motf_modules{command="plan",pipeline="pr",status="failed"} 1` + "\n",
+		`motf_modules{command="plan",pipeline="pr",status="succeeded"} 1` + "\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, nil, testRun()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+	want := `{"time":"2026-10-16T08:30:00Z","command":"plan","module":"network","path":"components/network","status":"failed","duration_ms":3200}`
+	if lines[0] != want {
+		t.Errorf("module line = %s, want %s", lines[0], want)
+	}
+	want = `{"time":"2026-10-16T08:30:00Z","command":"plan","status":"failed","exit_code":1,"duration_ms":12300}`
+	if lines[2] != want {
+		t.Errorf("command line = %s, want %s", lines[2], want)
+	}
+}
+
+func TestStatsDLines(t *testing.T) {
+	run := testRun()
+	run.Modules[0].Name = "key.vault"
+	got := StatsDLines("motf", map[string]string{"team": "platform"}, run)
+	want := []string{
+		"motf.plan.duration:12300|ms|#team:platform",
+		"motf.plan.failed:1|c|#team:platform",
+		"motf.plan.module.key_vault.duration:3200|ms|#team:platform",
+		"motf.plan.module.key_vault.failed:1|c|#team:platform",
+		"motf.plan.module.dns.duration:1500|ms|#team:platform",
+		"motf.plan.module.dns.succeeded:1|c|#team:platform",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("StatsDLines() = %q, want %q", got, want)
+	}
+}
+
+func TestEmitter_Prometheus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "textfile", "motf.prom")
+	e := Emitter{Format: FormatPrometheus, Path: path}
+	if err := e.Emit(testRun()); err != nil {
+		t.Fatal(err)
+	}
+	run := testRun()
+	run.Command = "val"
+	run.Modules = nil
+	if err := e.Emit(run); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `command="val"`) || strings.Contains(string(data), `command="plan"`) {
+		t.Errorf("textfile should only hold the last run:\n%s", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temporary files are left behind: %v", entries)
+	}
+}
+
+func TestEmitter_JSONAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.ndjson")
+	e := Emitter{Format: FormatJSON, Path: path, Labels: map[string]string{"pipeline": "main"}}
+	for range 2 {
+		if err := e.Emit(testRun()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("got %d lines, want 6", len(lines))
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[5]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if labels, _ := record["labels"].(map[string]any); labels["pipeline"] != "main" {
+		t.Errorf("labels = %v, want pipeline: main", record["labels"])
+	}
+}
+
+func TestEmitter_StatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on UDP: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	e := Emitter{Format: FormatStatsD, Address: conn.LocalAddr().String(), Prefix: "ci"}
+	if err := e.Emit(testRun()); err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 512)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); got != "ci.plan.duration:12300|ms" {
+		t.Errorf("first datagram = %q, want %q", got, "ci.plan.duration:12300|ms")
+	}
+}
+
+func TestValidLabel(t *testing.T) {
+	for name, want := range map[string]bool{
+		"pipeline":   true,
+		"team_name":  true,
+		"status":     false,
+		"__internal": false,
+		"1st":        false,
+		"with-dash":  false,
+	} {
+		if got := ValidLabel(name); got != want {
+			t.Errorf("ValidLabel(%q) = %t, want %t", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Package modgraph builds the dependency graph of the modules in a
+// repository from the local sources of their module blocks and the states
+// their terraform_remote_state data sources read.
+package modgraph
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+	"github.com/TechnicallyJoe/terraform-motf/internal/sources"
+)
+
+// Graph is the dependency graph of modules, identified by their
+// slash-separated paths relative to the root.
+type Graph struct {
+	dependencies map[string][]string // Modules each module calls
+	dependents   map[string][]string // Modules that call each module
+	states       map[string][]string // Modules whose state each module reads
+	readers      map[string][]string // Modules that read the state of each module
+}
+
+// stateSuffixes are stripped from remote state keys before they are matched
+// against module paths and names, longest first
+var stateSuffixes = []string{"/terraform.tfstate", "/default.tfstate", ".terraform.tfstate", ".tfstate"}
+
+// Build returns the graph of modules, slash-separated paths relative to root.
+// A module depends on the modules that the .tf and .tf.json files in its
+// directory call with local sources; a source in a subdirectory of a module
+// counts as a call of that module. Examples and tests of a module are not
+// part of it, so they don't add dependencies.
+//
+// The states a module reads with terraform_remote_state data sources are
+// recorded separately, see StateDependencies and StateReaders.
+func Build(root string, modules []string) (*Graph, error) {
+	g := &Graph{dependencies: map[string][]string{}, dependents: map[string][]string{}, states: map[string][]string{}, readers: map[string][]string{}}
+	for _, module := range modules {
+		calls, states, err := references(filepath.Join(root, filepath.FromSlash(module)))
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module, err)
+		}
+		for _, source := range calls {
+			target := owner(modules, path.Join(module, source))
+			if target == "" || target == module || slices.Contains(g.dependencies[module], target) {
+				continue
+			}
+			g.dependencies[module] = append(g.dependencies[module], target)
+			g.dependents[target] = append(g.dependents[target], module)
+		}
+		for _, state := range states {
+			target := stateOwner(modules, module, state)
+			if target == "" || target == module || slices.Contains(g.states[module], target) {
+				continue
+			}
+			g.states[module] = append(g.states[module], target)
+			g.readers[target] = append(g.readers[target], module)
+		}
+	}
+	for _, edges := range []map[string][]string{g.dependencies, g.dependents, g.states, g.readers} {
+		for _, modules := range edges {
+			slices.Sort(modules)
+		}
+	}
+	return g, nil
+}
+
+// references returns the local sources of the module blocks and the
+// terraform_remote_state data sources in the Terraform files directly in dir.
+func references(dir string) ([]string, []sources.RemoteState, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var calls []string
+	var states []sources.RemoteState
+	for _, entry := range entries {
+		if entry.IsDir() || !finder.IsTerraformFile(entry.Name()) {
+			continue
+		}
+		f, err := sources.ParseFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+		}
+		for _, call := range sources.ModuleCalls(f) {
+			if sources.IsLocal(call.Source) {
+				calls = append(calls, call.Source)
+			}
+		}
+		states = append(states, sources.RemoteStates(f)...)
+	}
+	return calls, states, nil
+}
+
+// stateOwner returns the module whose state a terraform_remote_state data
+// source of module reads, or "" if it can't be told. The path of a local
+// backend is resolved against module; the key (s3, azurerm) or prefix (gcs)
+// of other backends, without a .tfstate suffix, is matched against module
+// paths, and then its last element against module names if only one module
+// has that name, e.g. prod/network.tfstate for components/network.
+func stateOwner(modules []string, module string, state sources.RemoteState) string {
+	if state.Backend == "local" {
+		statePath := filepath.ToSlash(state.Config["path"])
+		if statePath == "" || path.IsAbs(statePath) {
+			return ""
+		}
+		return owner(modules, path.Join(module, path.Dir(statePath)))
+	}
+
+	key := state.Config["key"]
+	if key == "" {
+		key = state.Config["prefix"]
+	}
+	for _, suffix := range stateSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			key = strings.TrimSuffix(key, suffix)
+			break
+		}
+	}
+	key = strings.Trim(key, "/")
+	if key == "" {
+		return ""
+	}
+	if slices.Contains(modules, key) {
+		return key
+	}
+	var found string
+	for _, m := range modules {
+		if path.Base(m) == path.Base(key) {
+			if found != "" {
+				return ""
+			}
+			found = m
+		}
+	}
+	return found
+}
+
+// owner returns the module that is target or contains it, the one with the
+// longest path if modules are nested, or "" if there is none.
+func owner(modules []string, target string) string {
+	var found string
+	for _, module := range modules {
+		if (target == module || strings.HasPrefix(target, module+"/")) && len(module) > len(found) {
+			found = module
+		}
+	}
+	return found
+}
+
+// Dependencies returns the modules that module calls, sorted.
+func (g *Graph) Dependencies(module string) []string {
+	return g.dependencies[module]
+}
+
+// Dependents returns the modules that call module, sorted.
+func (g *Graph) Dependents(module string) []string {
+	return g.dependents[module]
+}
+
+// StateDependencies returns the modules whose state module reads with
+// terraform_remote_state data sources, sorted.
+func (g *Graph) StateDependencies(module string) []string {
+	return g.states[module]
+}
+
+// StateReaders returns the modules that read the state of module with
+// terraform_remote_state data sources, sorted.
+func (g *Graph) StateReaders(module string) []string {
+	return g.readers[module]
+}
+
+// RunDependencies returns, for each of modules, the modules among them it
+// has to run after: the modules it calls or whose state it reads, directly
+// or through modules that aren't among modules. Modules without such
+// dependencies are left out. A dependency cycle between modules is an error.
+func (g *Graph) RunDependencies(modules []string) (map[string][]string, error) {
+	selected := map[string]bool{}
+	for _, module := range modules {
+		selected[module] = true
+	}
+
+	deps := map[string][]string{}
+	for _, module := range modules {
+		seen := map[string]bool{module: true}
+		next := g.prerequisites(module)
+		for len(next) > 0 {
+			current := next[0]
+			next = next[1:]
+			if seen[current] {
+				continue
+			}
+			seen[current] = true
+			if selected[current] {
+				// Its own dependencies run before it, so they don't need to be followed
+				deps[module] = append(deps[module], current)
+				continue
+			}
+			next = append(next, g.prerequisites(current)...)
+		}
+		slices.Sort(deps[module])
+	}
+
+	if cycle := cycleIn(modules, deps); len(cycle) > 0 {
+		return nil, fmt.Errorf("dependency cycle between modules: %s", strings.Join(cycle, " -> "))
+	}
+	return deps, nil
+}
+
+// prerequisites returns the modules that module calls or whose state it reads.
+func (g *Graph) prerequisites(module string) []string {
+	return append(slices.Clone(g.dependencies[module]), g.states[module]...)
+}
+
+// cycleIn returns a cycle of deps as the modules on it, starting and ending
+// with the same module, or nil if deps has no cycle.
+func cycleIn(modules []string, deps map[string][]string) []string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var stack []string
+	var visit func(module string) []string
+	visit = func(module string) []string {
+		state[module] = visiting
+		stack = append(stack, module)
+		for _, dep := range deps[module] {
+			switch state[dep] {
+			case visiting:
+				start := slices.Index(stack, dep)
+				return append(slices.Clone(stack[start:]), dep)
+			case 0:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[module] = done
+		return nil
+	}
+	for _, module := range modules {
+		if state[module] == 0 {
+			if cycle := visit(module); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// Consumers returns the modules that depend on any of modules, directly or
+// through at most depth levels of dependents, or any number of levels if
+// depth is negative. modules themselves are left out. The result is sorted.
+func (g *Graph) Consumers(modules []string, depth int) []string {
+	seen := map[string]bool{}
+	for _, module := range modules {
+		seen[module] = true
+	}
+	var consumers []string
+	level := modules
+	for n := 0; len(level) > 0 && (depth < 0 || n < depth); n++ {
+		var next []string
+		for _, module := range level {
+			for _, dependent := range g.dependents[module] {
+				if !seen[dependent] {
+					seen[dependent] = true
+					next = append(next, dependent)
+				}
+			}
+		}
+		consumers = append(consumers, next...)
+		level = next
+	}
+	slices.Sort(consumers)
+	return consumers
+}
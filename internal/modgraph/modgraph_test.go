@@ -0,0 +1,170 @@
+package modgraph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeModule writes a main.tf with module blocks calling sources to
+// root/module.
+func writeModule(t *testing.T, root, module string, sources ...string) {
+	t.Helper()
+	dir := filepath.Join(root, filepath.FromSlash(module))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := ""
+	for i, source := range sources {
+		content += "module \"m" + string(rune('a'+i)) + "\" {\n  source = \"" + source + "\"\n}\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupRepo creates components naming and storage, base platform using both,
+// and projects prod, using platform, and sandbox, using storage.
+func setupRepo(t *testing.T) (string, []string) {
+	t.Helper()
+	root := t.TempDir()
+	writeModule(t, root, "components/naming")
+	writeModule(t, root, "components/storage", "../naming", "registry.example.com/x/y/z")
+	writeModule(t, root, "bases/platform", "../../components/storage", "../../components/naming", "../../components/storage")
+	writeModule(t, root, "projects/prod", "../../bases/platform")
+	writeModule(t, root, "projects/sandbox", "../../components/storage/modules/container")
+	// Examples call their module, but aren't consumers of it
+	writeModule(t, root, "components/storage/examples/basic", "../..")
+	return root, []string{"bases/platform", "components/naming", "components/storage", "projects/prod", "projects/sandbox"}
+}
+
+func TestBuild(t *testing.T) {
+	root, modules := setupRepo(t)
+	g, err := Build(root, modules)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if got, want := g.Dependencies("bases/platform"), []string{"components/naming", "components/storage"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies(bases/platform) = %v, want %v", got, want)
+	}
+	// A source in a subdirectory of a module is a call of the module
+	if got, want := g.Dependents("components/storage"), []string{"bases/platform", "projects/sandbox"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependents(components/storage) = %v, want %v", got, want)
+	}
+	if got := g.Dependents("projects/prod"); len(got) != 0 {
+		t.Errorf("Dependents(projects/prod) = %v, want none", got)
+	}
+}
+
+func TestConsumers(t *testing.T) {
+	root, modules := setupRepo(t)
+	g, err := Build(root, modules)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	tests := []struct {
+		modules []string
+		depth   int
+		want    []string
+	}{
+		{[]string{"components/naming"}, 1, []string{"bases/platform", "components/storage"}},
+		{[]string{"components/naming"}, 2, []string{"bases/platform", "components/storage", "projects/prod", "projects/sandbox"}},
+		{[]string{"components/storage"}, -1, []string{"bases/platform", "projects/prod", "projects/sandbox"}},
+		// Modules that are already selected aren't consumers
+		{[]string{"components/storage", "bases/platform"}, 1, []string{"projects/prod", "projects/sandbox"}},
+		{[]string{"components/storage"}, 0, nil},
+	}
+	for _, tt := range tests {
+		if got := g.Consumers(tt.modules, tt.depth); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Consumers(%v, %d) = %v, want %v", tt.modules, tt.depth, got, tt.want)
+		}
+	}
+}
+
+// writeRemoteState writes a data.tf to root/module reading a remote state
+// with backend and config.
+func writeRemoteState(t *testing.T, root, module, backend, config string) {
+	t.Helper()
+	content := "data \"terraform_remote_state\" \"state\" {\n  backend = \"" + backend + "\"\n  config = {\n    " + config + "\n  }\n}\n"
+	if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(module), "data.tf"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild_RemoteStates(t *testing.T) {
+	root, modules := setupRepo(t)
+	writeModule(t, root, "projects/dns")
+	writeModule(t, root, "projects/network")
+	writeModule(t, root, "projects/app")
+	modules = append(modules, "projects/app", "projects/dns", "projects/network")
+	writeRemoteState(t, root, "projects/app", "local", `path = "../network/terraform.tfstate"`)
+	writeRemoteState(t, root, "projects/dns", "s3", `key = "prod/network.tfstate"`)
+	writeRemoteState(t, root, "projects/prod", "azurerm", `key = "projects/sandbox/terraform.tfstate"`)
+	writeRemoteState(t, root, "projects/sandbox", "gcs", `prefix = "unknown"`)
+
+	g, err := Build(root, modules)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	tests := []struct {
+		module string
+		want   []string
+	}{
+		{"projects/app", []string{"projects/network"}},  // Local path
+		{"projects/dns", []string{"projects/network"}},  // Module name
+		{"projects/prod", []string{"projects/sandbox"}}, // Module path
+		{"projects/sandbox", nil},                       // No such module
+		{"projects/network", nil},
+	}
+	for _, tt := range tests {
+		if got := g.StateDependencies(tt.module); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("StateDependencies(%s) = %v, want %v", tt.module, got, tt.want)
+		}
+	}
+	if got, want := g.StateReaders("projects/network"), []string{"projects/app", "projects/dns"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StateReaders(projects/network) = %v, want %v", got, want)
+	}
+	// Reading a state isn't a call, so it doesn't make consumers
+	if got := g.Dependents("projects/network"); len(got) != 0 {
+		t.Errorf("Dependents(projects/network) = %v, want none", got)
+	}
+}
+
+func TestRunDependencies(t *testing.T) {
+	root, modules := setupRepo(t)
+	writeRemoteState(t, root, "projects/sandbox", "local", `path = "../prod/terraform.tfstate"`)
+	g, err := Build(root, modules)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// prod depends on storage through platform, which doesn't run
+	got, err := g.RunDependencies([]string{"components/storage", "projects/prod", "projects/sandbox"})
+	if err != nil {
+		t.Fatalf("RunDependencies() error = %v", err)
+	}
+	want := map[string][]string{
+		"projects/prod":    {"components/storage"},
+		"projects/sandbox": {"components/storage", "projects/prod"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RunDependencies() = %v, want %v", got, want)
+	}
+}
+
+func TestRunDependencies_Cycle(t *testing.T) {
+	root, modules := setupRepo(t)
+	writeRemoteState(t, root, "components/naming", "local", `path = "../../projects/prod/terraform.tfstate"`)
+	g, err := Build(root, modules)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	_, err = g.RunDependencies([]string{"components/naming", "projects/prod"})
+	if err == nil || err.Error() != "dependency cycle between modules: components/naming -> projects/prod -> components/naming" {
+		t.Errorf("expected a dependency cycle error, got %v", err)
+	}
+}
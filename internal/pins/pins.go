@@ -0,0 +1,110 @@
+// Package pins finds module references pinned to released versions of the
+// repository's own modules and updates them to newer releases.
+package pins
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/release"
+	"github.com/TechnicallyJoe/terraform-motf/internal/sources"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Kinds of pinned references
+const (
+	KindGit      = "git"      // git source whose ?ref= is a module release tag
+	KindRegistry = "registry" // registry source with a version constraint
+)
+
+// Pin is a module block pinned to a version of a module.
+type Pin struct {
+	File    string `json:"file"` // Slash-separated, relative to the searched root
+	Line    int    `json:"line"`
+	Module  string `json:"module"` // Name of the module block
+	Kind    string `json:"kind"`
+	Source  string `json:"source"`
+	Target  string `json:"target"`  // git: module path in the repository; registry: module name
+	Version string `json:"version"` // git: version of the release tag; registry: version constraint
+
+	valueRange hcl.Range // Range of the value an update replaces
+}
+
+// Find returns the pinned module blocks in all .tf and .tf.json files under
+// root, in file and line order:
+//   - git sources with a release tag ref, e.g.
+//     git::https://github.com/acme/infra.git//components/storage?ref=components/storage/v1.2.3
+//   - sources in one of registries (hostnames, also matching their
+//     subdomains) with a version argument, e.g. spacelift.io/acme/storage/azurerm
+func Find(root string, registries []string) ([]Pin, error) {
+	var pins []Pin
+	err := sources.WalkFiles(root, func(file, rel string) error {
+		f, err := sources.ParseFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", rel, err)
+		}
+		for _, call := range sources.ModuleCalls(f) {
+			pin := Pin{File: rel, Line: call.Line, Module: call.Name, Source: call.Source}
+			if target, version, ok := parseGitRef(call.Source); ok {
+				pin.Kind, pin.Target, pin.Version, pin.valueRange = KindGit, target, version, call.SourceRange
+			} else if name, ok := parseRegistrySource(call.Source, registries); ok && call.Version != "" {
+				pin.Kind, pin.Target, pin.Version, pin.valueRange = KindRegistry, name, call.Version, call.VersionRange
+			} else {
+				continue
+			}
+			pins = append(pins, pin)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pins, nil
+}
+
+// parseGitRef returns the module path and version of a git source whose
+// subdirectory is released with the tag in its ref, as created by motf
+// release.
+func parseGitRef(source string) (target, version string, ok bool) {
+	addr := strings.TrimPrefix(source, "git::")
+	if _, rest, found := strings.Cut(addr, "://"); found {
+		addr = rest
+	}
+	_, subdir, found := strings.Cut(addr, "//")
+	if !found {
+		return "", "", false
+	}
+	subdir, query, _ := strings.Cut(subdir, "?")
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", "", false
+	}
+
+	ref := values.Get("ref")
+	i := strings.LastIndex(ref, "/v")
+	if i < 0 {
+		return "", "", false
+	}
+	target, version = ref[:i], ref[i+2:]
+	if target != strings.Trim(subdir, "/") || !release.IsVersion(version) {
+		return "", "", false
+	}
+	return target, version, true
+}
+
+// parseRegistrySource returns the module name of a source
+// <host>/<namespace>/<name>/<provider> whose host is one of registries.
+func parseRegistrySource(source string, registries []string) (string, bool) {
+	addr, _, _ := strings.Cut(source, "//")
+	parts := strings.Split(addr, "/")
+	if len(parts) != 4 {
+		return "", false
+	}
+	host := parts[0]
+	if !slices.ContainsFunc(registries, func(r string) bool { return host == r || strings.HasSuffix(host, "."+r) }) {
+		return "", false
+	}
+	return parts[2], true
+}
@@ -0,0 +1,213 @@
+package pins
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles creates files with content under root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
+
+const consumerTF = `module "storage" {
+  source = "git::https://github.com/acme/infra.git//components/storage?ref=components/storage/v1.2.3"
+}
+
+module "network" {
+  source  = "spacelift.io/acme/network/azurerm"
+  version = "~> 1.2" # Pessimistic
+}
+
+module "public" {
+  source  = "hashicorp/consul/aws"
+  version = "0.1.0"
+}
+
+module "branch" {
+  source = "git::https://github.com/acme/infra.git//components/storage?ref=main"
+}
+`
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"projects/prod/main.tf": consumerTF,
+		"projects/dev/main.tf.json": `{"module": {"vnet": {
+  "source": "acme.app.spacelift.io/acme/vnet/azurerm", "version": "2.0.0"
+}}}`,
+	})
+
+	pins, err := Find(root, []string{"spacelift.io"})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(pins) != 3 {
+		t.Fatalf("expected 3 pins, got %d: %+v", len(pins), pins)
+	}
+
+	want := []Pin{
+		{File: "projects/dev/main.tf.json", Module: "vnet", Kind: KindRegistry, Target: "vnet", Version: "2.0.0"},
+		{File: "projects/prod/main.tf", Line: 2, Module: "storage", Kind: KindGit, Target: "components/storage", Version: "1.2.3"},
+		{File: "projects/prod/main.tf", Line: 6, Module: "network", Kind: KindRegistry, Target: "network", Version: "~> 1.2"},
+	}
+	for i, w := range want {
+		got := pins[i]
+		if got.File != w.File || got.Module != w.Module || got.Kind != w.Kind || got.Target != w.Target || got.Version != w.Version {
+			t.Errorf("pin %d = %+v, want %+v", i, got, w)
+		}
+		if w.Line != 0 && got.Line != w.Line {
+			t.Errorf("pin %d line = %d, want %d", i, got.Line, w.Line)
+		}
+	}
+}
+
+func TestParseGitRef(t *testing.T) {
+	tests := []struct {
+		source  string
+		target  string
+		version string
+		ok      bool
+	}{
+		{"git::https://github.com/acme/infra.git//components/storage?ref=components/storage/v1.2.3", "components/storage", "1.2.3", true},
+		{"github.com/acme/infra//bases/app?ref=bases/app/v0.1.0&depth=1", "bases/app", "0.1.0", true},
+		{"git::ssh://git@github.com/acme/infra.git//components/storage?ref=components/network/v1.0.0", "", "", false},
+		{"git::https://github.com/acme/infra.git//components/storage?ref=v1.0.0", "", "", false},
+		{"git::https://github.com/acme/infra.git?ref=components/storage/v1.0.0", "", "", false},
+	}
+	for _, tt := range tests {
+		target, version, ok := parseGitRef(tt.source)
+		if target != tt.target || version != tt.version || ok != tt.ok {
+			t.Errorf("parseGitRef(%q) = %q, %q, %v; want %q, %q, %v", tt.source, target, version, ok, tt.target, tt.version, tt.ok)
+		}
+	}
+}
+
+func TestCheck_Git(t *testing.T) {
+	pin := Pin{Kind: KindGit, Target: "components/storage", Version: "1.2.3",
+		Source: "git::https://github.com/acme/infra.git//components/storage?ref=components/storage/v1.2.3"}
+
+	update, ok, err := Check(pin, "v1.4.0")
+	if err != nil || !ok {
+		t.Fatalf("Check() = %v, %v; want an update", ok, err)
+	}
+	if want := "git::https://github.com/acme/infra.git//components/storage?ref=components/storage/v1.4.0"; update.To != want {
+		t.Errorf("To = %s, want %s", update.To, want)
+	}
+	if update.Latest != "1.4.0" {
+		t.Errorf("Latest = %s, want 1.4.0", update.Latest)
+	}
+
+	if _, ok, _ := Check(pin, "1.2.3"); ok {
+		t.Error("expected no update when the pin is the latest release")
+	}
+}
+
+func TestCheck_RegistryConstraints(t *testing.T) {
+	tests := []struct {
+		constraint string
+		latest     string
+		to         string // Empty when no update is expected
+	}{
+		{"1.2.3", "1.3.0", "1.3.0"},
+		{"= 1.2.3", "2.0.0", "= 2.0.0"},
+		{"1.3.0", "1.3.0", ""},
+		{"~> 1.2", "1.9.0", ""},
+		{"~> 1.2", "2.0.1", "~> 2.0"},
+		{"~> 1.2.0", "1.2.9", ""},
+		{"~> 1.2.0", "1.3.1", "~> 1.3.1"},
+		{">= 1.0", "5.0.0", ""},
+	}
+	for _, tt := range tests {
+		pin := Pin{Kind: KindRegistry, Target: "network", Version: tt.constraint}
+		update, ok, err := Check(pin, tt.latest)
+		if err != nil {
+			t.Errorf("Check(%q, %s) error = %v", tt.constraint, tt.latest, err)
+			continue
+		}
+		if ok != (tt.to != "") || update.To != tt.to {
+			t.Errorf("Check(%q, %s) = %q, %v; want %q", tt.constraint, tt.latest, update.To, ok, tt.to)
+		}
+	}
+
+	_, _, err := Check(Pin{Kind: KindRegistry, Version: ">= 1.0, < 2.0"}, "2.1.0")
+	if !errors.Is(err, ErrUnsupportedConstraint) {
+		t.Errorf("expected ErrUnsupportedConstraint, got %v", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"projects/prod/main.tf": consumerTF,
+		"projects/dev/main.tf.json": `{"module": {"vnet": {
+  "source": "spacelift.io/acme/vnet/azurerm", "version": "2.0.0"
+}}}`,
+	})
+	pins, err := Find(root, []string{"spacelift.io"})
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	var updates []Update
+	for _, pin := range pins {
+		update, ok, err := Check(pin, "2.1.0")
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if ok {
+			updates = append(updates, update)
+		}
+	}
+	if err := Apply(root, updates); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	tf, err := os.ReadFile(filepath.Join(root, "projects", "prod", "main.tf"))
+	if err != nil {
+		t.Fatalf("failed to read main.tf: %v", err)
+	}
+	want := `module "storage" {
+  source = "git::https://github.com/acme/infra.git//components/storage?ref=components/storage/v2.1.0"
+}
+
+module "network" {
+  source  = "spacelift.io/acme/network/azurerm"
+  version = "~> 2.1" # Pessimistic
+}
+
+module "public" {
+  source  = "hashicorp/consul/aws"
+  version = "0.1.0"
+}
+
+module "branch" {
+  source = "git::https://github.com/acme/infra.git//components/storage?ref=main"
+}
+`
+	if string(tf) != want {
+		t.Errorf("main.tf =\n%s\nwant\n%s", tf, want)
+	}
+
+	tfJSON, err := os.ReadFile(filepath.Join(root, "projects", "dev", "main.tf.json"))
+	if err != nil {
+		t.Fatalf("failed to read main.tf.json: %v", err)
+	}
+	wantJSON := `{"module": {"vnet": {
+  "source": "spacelift.io/acme/vnet/azurerm", "version": "2.1.0"
+}}}`
+	if string(tfJSON) != wantJSON {
+		t.Errorf("main.tf.json =\n%s\nwant\n%s", tfJSON, wantJSON)
+	}
+}
@@ -0,0 +1,137 @@
+package pins
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/hclform"
+	"github.com/TechnicallyJoe/terraform-motf/internal/release"
+)
+
+// ErrUnsupportedConstraint is returned by Check for registry version
+// constraints it can't update, e.g. ones with several conditions.
+var ErrUnsupportedConstraint = errors.New("unsupported version constraint")
+
+// constraintPattern matches a single version constraint with =, ~>, or >=
+var constraintPattern = regexp.MustCompile(`^\s*(=|~>|>=)?\s*(\d+(?:\.\d+){0,2})\s*$`)
+
+// Update is the change of a pin to a newer release.
+type Update struct {
+	Pin
+	Latest string `json:"latest"`
+	To     string `json:"to"` // git: updated source; registry: updated version constraint
+}
+
+// Check returns the update of pin to version latest (MAJOR.MINOR.PATCH), and
+// false when the pin already allows it:
+//   - git refs and exact constraints are set to latest
+//   - pessimistic constraints (~>) keep their precision, e.g. ~> 1.2 becomes
+//     ~> 2.0 for latest 2.0.1
+//   - minimum constraints (>=) always allow newer releases
+func Check(pin Pin, latest string) (Update, bool, error) {
+	latest = strings.TrimPrefix(latest, "v")
+	switch pin.Kind {
+	case KindGit:
+		if release.CompareVersions(latest, pin.Version) <= 0 {
+			return Update{}, false, nil
+		}
+		oldTag := release.TagName(pin.Target, pin.Version)
+		i := strings.LastIndex(pin.Source, oldTag)
+		to := pin.Source[:i] + release.TagName(pin.Target, latest) + pin.Source[i+len(oldTag):]
+		return Update{Pin: pin, Latest: latest, To: to}, true, nil
+	case KindRegistry:
+		to, ok, err := updateConstraint(pin.Version, latest)
+		if err != nil || !ok {
+			return Update{}, false, err
+		}
+		return Update{Pin: pin, Latest: latest, To: to}, true, nil
+	default:
+		return Update{}, false, fmt.Errorf("unknown pin kind '%s'", pin.Kind)
+	}
+}
+
+// updateConstraint returns constraint changed to allow latest, and false if it
+// already does.
+func updateConstraint(constraint, latest string) (string, bool, error) {
+	m := constraintPattern.FindStringSubmatch(constraint)
+	if m == nil {
+		return "", false, fmt.Errorf("%w '%s'", ErrUnsupportedConstraint, constraint)
+	}
+	op, version := m[1], m[2]
+	parts := strings.Split(version, ".")
+	full := version + strings.Repeat(".0", 3-len(parts))
+	if release.CompareVersions(latest, full) <= 0 || op == ">=" {
+		return "", false, nil
+	}
+
+	latestParts := strings.Split(latest, ".")
+	newVersion := latest
+	if op == "~>" {
+		// ~> allows changes of the last given part only
+		if len(parts) == 1 || slices.Equal(parts[:len(parts)-1], latestParts[:len(parts)-1]) {
+			return "", false, nil
+		}
+		newVersion = strings.Join(latestParts[:len(parts)], ".")
+	}
+	i := strings.LastIndex(constraint, version)
+	return constraint[:i] + newVersion + constraint[i+len(version):], true, nil
+}
+
+// Apply writes the updates to the files under root, replacing only the
+// updated values so formatting and comments are preserved.
+func Apply(root string, updates []Update) error {
+	byFile := make(map[string][]Update)
+	var files []string
+	for _, u := range updates {
+		if _, ok := byFile[u.File]; !ok {
+			files = append(files, u.File)
+		}
+		byFile[u.File] = append(byFile[u.File], u)
+	}
+
+	for _, rel := range files {
+		file := filepath.Join(root, filepath.FromSlash(rel))
+		src, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return err
+		}
+
+		var edits []hclform.Edit
+		for _, u := range byFile[rel] {
+			value, err := quote(u.To)
+			if err != nil {
+				return err
+			}
+			edits = append(edits, hclform.Edit{Start: u.valueRange.Start.Byte, End: u.valueRange.End.Byte, Text: value})
+		}
+		out := hclform.Splice(src, edits)
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, out, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// quote returns s as a quoted string that is valid in both native and JSON
+// syntax, without escaping characters like > that don't need it.
+func quote(s string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
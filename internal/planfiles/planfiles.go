@@ -0,0 +1,96 @@
+// Package planfiles manages saved terraform/tofu plan files, stored in an
+// artifacts directory by module path so that a later job can apply exactly
+// the plans that were reviewed.
+package planfiles
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Ext is the extension of saved plan files
+const Ext = ".tfplan"
+
+// Path returns the plan file of the module at modulePath, relative to the
+// root, in dir: <dir>/<module path>.tfplan.
+func Path(dir, modulePath string) string {
+	return filepath.Join(dir, filepath.Clean(modulePath)+Ext)
+}
+
+// Prepare creates the directory of the module's plan file and removes a plan
+// saved earlier, so a failed plan doesn't leave a stale one to be applied. It
+// returns the plan file.
+func Prepare(dir, modulePath string) (string, error) {
+	file := Path(dir, modulePath)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return "", fmt.Errorf("failed to create plan directory: %w", err)
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to remove old plan: %w", err)
+	}
+	return file, nil
+}
+
+// List returns the slash-separated paths of the modules with a plan file in
+// dir, sorted. A missing dir has no plans.
+func List(dir string) ([]string, error) {
+	var modules []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == dir {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), Ext) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		modules = append(modules, filepath.ToSlash(strings.TrimSuffix(rel, Ext)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans in %s: %w", dir, err)
+	}
+	sort.Strings(modules)
+	return modules, nil
+}
+
+// Clear removes all plan files in dir, and the directories left empty.
+func Clear(dir string) error {
+	modules, err := List(dir)
+	if err != nil {
+		return err
+	}
+	for _, mod := range modules {
+		if err := Remove(dir, mod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove removes the plan file of the module at modulePath, and the
+// directories in dir that it leaves empty.
+func Remove(dir, modulePath string) error {
+	file := Path(dir, modulePath)
+	if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove plan: %w", err)
+	}
+	cleanDir := filepath.Clean(dir)
+	for parent := filepath.Dir(file); parent != cleanDir && strings.HasPrefix(parent, cleanDir); parent = filepath.Dir(parent) {
+		// Fails when the directory isn't empty, which ends the cleanup
+		if os.Remove(parent) != nil {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package planfiles
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// savePlan writes a plan file for the module at modulePath.
+func savePlan(t *testing.T, dir, modulePath string) string {
+	t.Helper()
+	file, err := Prepare(dir, modulePath)
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if err := os.WriteFile(file, []byte("plan"), 0644); err != nil {
+		t.Fatalf("failed to write plan: %v", err)
+	}
+	return file
+}
+
+func TestPath(t *testing.T) {
+	got := Path(filepath.Join("root", ".motf", "plans"), filepath.Join("components", "storage"))
+	want := filepath.Join("root", ".motf", "plans", "components", "storage.tfplan")
+	if got != want {
+		t.Errorf("Path() = %s, want %s", got, want)
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "plans")
+
+	modules, err := List(dir)
+	if err != nil || len(modules) != 0 {
+		t.Fatalf("List() of a missing dir = %v, %v; want no plans", modules, err)
+	}
+
+	savePlan(t, dir, "projects/prod")
+	savePlan(t, dir, "components/azurerm/storage")
+	savePlan(t, dir, "components/azurerm")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("notes"), 0644); err != nil {
+		t.Fatalf("failed to write README.md: %v", err)
+	}
+
+	modules, err = List(dir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []string{"components/azurerm", "components/azurerm/storage", "projects/prod"}
+	if !reflect.DeepEqual(modules, want) {
+		t.Errorf("List() = %v, want %v", modules, want)
+	}
+}
+
+func TestPrepare_RemovesOldPlan(t *testing.T) {
+	dir := t.TempDir()
+	file := savePlan(t, dir, "projects/prod")
+
+	if _, err := Prepare(dir, "projects/prod"); err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Error("expected Prepare() to remove the old plan")
+	}
+}
+
+func TestRemoveAndClear(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "plans")
+	savePlan(t, dir, "components/azurerm/storage")
+	savePlan(t, dir, "components/network")
+	savePlan(t, dir, "projects/prod")
+
+	if err := Remove(dir, "components/azurerm/storage"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "components", "azurerm")); !os.IsNotExist(err) {
+		t.Error("expected Remove() to remove the empty azurerm directory")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "components")); err != nil {
+		t.Errorf("expected components to remain for network: %v", err)
+	}
+
+	if err := Clear(dir); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if modules, _ := List(dir); len(modules) != 0 {
+		t.Errorf("expected no plans after Clear(), got %v", modules)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("expected Clear() to keep the plans directory: %v", err)
+	}
+}
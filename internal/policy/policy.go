@@ -0,0 +1,157 @@
+// Package policy evaluates Rego policies against the schema and plan of a
+// module with the opa CLI.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/procgroup"
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// DefaultBinary is the OPA CLI policies are evaluated with
+const DefaultBinary = "opa"
+
+// Input is the input document of a policy evaluation.
+type Input struct {
+	Module *terraform.ModuleSchema `json:"module"`
+	Plan   json.RawMessage         `json:"plan,omitempty"` // terraform/tofu show -json of a plan
+}
+
+// Violation is a result of the policy query for a module.
+type Violation struct {
+	Module  string `json:"module,omitempty"` // Set by the caller
+	Message string `json:"message"`
+}
+
+// Runner evaluates policies with the opa CLI.
+type Runner struct {
+	Binary string   // opa binary, DefaultBinary if empty
+	Paths  []string // Policy files or directories, passed as --data
+	Query  string   // Rule whose results are violations, e.g. data.motf.deny
+	Env    []string // Extra KEY=VALUE pairs added to the environment
+	DryRun bool     // Print the command instead of running it
+
+	// Context stops opa when done, e.g. at the module's timeout; nil means
+	// never. Like terraform commands, it runs in its own process group.
+	Context context.Context
+}
+
+// Eval evaluates the query for input in dir and returns the violations,
+// sorted by message. An undefined query has no violations.
+func (r *Runner) Eval(dir string, input Input, stdout, stderr io.Writer) ([]Violation, error) {
+	binary := r.Binary
+	if binary == "" {
+		binary = DefaultBinary
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp("", "motf-policy-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create input directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	inputFile := filepath.Join(tmpDir, "input.json")
+	if err := os.WriteFile(inputFile, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write policy input: %w", err)
+	}
+
+	args := []string{"eval", "--format", "json", "--input", inputFile}
+	for _, p := range r.Paths {
+		args = append(args, "--data", p)
+	}
+	args = append(args, r.Query)
+
+	if r.DryRun {
+		_, _ = fmt.Fprintf(stdout, "[dry-run] Would run %s %s in %s\n", binary, strings.Join(args, " "), dir)
+		return nil, nil
+	}
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%s eval stopped: %w", binary, context.Cause(ctx))
+	}
+	_, _ = fmt.Fprintf(stdout, "Running %s eval %s in %s\n", binary, r.Query, dir)
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...) //nolint:gosec // opa is run with the configured policies
+	cmd.Dir = dir
+	cmd.Stdout = &out
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(), r.Env...)
+	if err := procgroup.Run(cmd, procgroup.GracePeriod); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s eval stopped: %w", binary, context.Cause(ctx))
+		}
+		return nil, fmt.Errorf("%s eval failed: %w", binary, err)
+	}
+	return ParseResult(out.Bytes())
+}
+
+// evalResult is the JSON output of opa eval
+type evalResult struct {
+	Result []struct {
+		Expressions []struct {
+			Value any `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+// ParseResult returns the violations in the JSON output of opa eval. The
+// query may evaluate to a set or array of strings, of objects with a msg, or
+// of other values, which are reported as JSON.
+func ParseResult(data []byte) ([]Violation, error) {
+	var result evalResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+
+	var violations []Violation
+	for _, r := range result.Result {
+		for _, e := range r.Expressions {
+			switch value := e.Value.(type) {
+			case []any:
+				for _, v := range value {
+					violations = append(violations, Violation{Message: message(v)})
+				}
+			case nil, bool:
+				// A boolean query, e.g. data.motf.allow, has no messages
+				if value == false {
+					violations = append(violations, Violation{Message: "policy denied the module"})
+				}
+			default:
+				violations = append(violations, Violation{Message: message(value)})
+			}
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Message < violations[j].Message })
+	return violations, nil
+}
+
+// message returns the message of a violation value.
+func message(v any) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case map[string]any:
+		if msg, ok := value["msg"].(string); ok {
+			return msg
+		}
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+func TestParseResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"undefined", `{}`, nil},
+		{"empty set", `{"result": [{"expressions": [{"value": []}]}]}`, nil},
+		{"strings", `{"result": [{"expressions": [{"value": ["tags are required", "location must be westeurope"]}]}]}`, []string{"location must be westeurope", "tags are required"}},
+		{"objects with msg", `{"result": [{"expressions": [{"value": [{"msg": "no public IPs"}]}]}]}`, []string{"no public IPs"}},
+		{"other values", `{"result": [{"expressions": [{"value": [{"rule": "tags"}]}]}]}`, []string{`{"rule":"tags"}`}},
+		{"allow false", `{"result": [{"expressions": [{"value": false}]}]}`, []string{"policy denied the module"}},
+		{"allow true", `{"result": [{"expressions": [{"value": true}]}]}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := ParseResult([]byte(tt.output))
+			if err != nil {
+				t.Fatalf("ParseResult() error = %v", err)
+			}
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.Message)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("ParseResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResult_InvalidJSON(t *testing.T) {
+	if _, err := ParseResult([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid output")
+	}
+}
+
+func TestRunner_Eval(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as opa")
+	}
+	dir := t.TempDir()
+	// A stand-in opa that denies modules without variables, reading the input file
+	script := `#!/bin/sh
+while [ "$1" != "--input" ]; do shift; done
+if grep -q '"variables":\[\]' "$2" || ! grep -q '"variables"' "$2"; then
+  echo '{"result": [{"expressions": [{"value": ["module has no variables"]}]}]}'
+else
+  echo '{"result": [{"expressions": [{"value": []}]}]}'
+fi
+`
+	opa := filepath.Join(dir, "opa")
+	if err := os.WriteFile(opa, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write opa: %v", err)
+	}
+	runner := &Runner{Binary: opa, Paths: []string{"policies"}, Query: "data.motf.deny"}
+
+	var stdout, stderr bytes.Buffer
+	violations, err := runner.Eval(dir, Input{Module: &terraform.ModuleSchema{Name: "vnet"}}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Eval() error = %v (stderr: %s)", err, stderr.String())
+	}
+	if len(violations) != 1 || violations[0].Message != "module has no variables" {
+		t.Errorf("Eval() = %+v, want one violation", violations)
+	}
+}
+
+func TestRunner_EvalContextCanceled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as opa")
+	}
+	dir := t.TempDir()
+	opa := filepath.Join(dir, "opa")
+	if err := os.WriteFile(opa, []byte("#!/bin/sh\nsleep 10\n"), 0755); err != nil {
+		t.Fatalf("failed to write opa: %v", err)
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(100*time.Millisecond, func() { cancel(errors.New("interrupted")) })
+	runner := &Runner{Binary: opa, Query: "data.motf.deny", Context: ctx}
+
+	start := time.Now()
+	var stdout, stderr bytes.Buffer
+	_, err := runner.Eval(dir, Input{}, &stdout, &stderr)
+	if err == nil || err.Error() != opa+" eval stopped: interrupted" {
+		t.Fatalf("expected opa to be stopped, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected opa to be stopped, took %s", elapsed)
+	}
+}
+
+func TestRunner_DryRun(t *testing.T) {
+	runner := &Runner{Paths: []string{"/repo/policies"}, Query: "data.motf.deny", DryRun: true}
+
+	var stdout bytes.Buffer
+	violations, err := runner.Eval("/repo/components/vnet", Input{Plan: json.RawMessage(`{}`)}, &stdout, &stdout)
+	if err != nil || violations != nil {
+		t.Fatalf("expected no violations and no error in dry-run, got %v, %v", violations, err)
+	}
+	if !strings.HasPrefix(stdout.String(), "[dry-run] Would run opa eval --format json --input ") ||
+		!strings.HasSuffix(stdout.String(), "--data /repo/policies data.motf.deny in /repo/components/vnet\n") {
+		t.Errorf("unexpected dry-run output %q", stdout.String())
+	}
+}
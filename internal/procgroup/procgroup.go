@@ -0,0 +1,44 @@
+// Package procgroup runs commands in their own process group, so a command
+// and the processes it starts (e.g. terraform providers) can be stopped
+// together when the command is canceled.
+package procgroup
+
+import (
+	"os"
+	"os/exec"
+	"time"
+)
+
+// GracePeriod is how long a canceled command may take to stop after being
+// interrupted, e.g. to release a state lock, before its process group is killed
+const GracePeriod = 30 * time.Second
+
+// pollInterval is how often a canceled command is checked for having stopped
+const pollInterval = 100 * time.Millisecond
+
+// Run runs cmd, created with exec.CommandContext, in its own process group.
+// When the context is done the group is interrupted, so terraform can release
+// its state lock, and killed once the command stopped or grace passed, so
+// processes it started don't keep running or keep its output open.
+//
+// A command in its own process group doesn't receive the terminal's Ctrl+C:
+// callers cancel the context on interrupt instead.
+func Run(cmd *exec.Cmd, grace time.Duration) error {
+	setGroup(cmd)
+	cmd.Cancel = func() error {
+		go killGroupWhenStopped(cmd.Process, grace)
+		return interruptGroup(cmd.Process)
+	}
+	cmd.WaitDelay = grace
+	return cmd.Run()
+}
+
+// killGroupWhenStopped kills the process group led by p once p exited, or
+// after grace.
+func killGroupWhenStopped(p *os.Process, grace time.Duration) {
+	deadline := time.Now().Add(grace)
+	for !exited(p) && time.Now().Before(deadline) {
+		time.Sleep(pollInterval)
+	}
+	_ = killGroup(p)
+}
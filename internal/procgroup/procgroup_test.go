@@ -0,0 +1,63 @@
+//go:build linux
+
+package procgroup
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// running reports whether the process pid is running, i.e. exists and isn't a zombie.
+func running(pid string) bool {
+	stat, err := os.ReadFile(filepath.Join("/proc", pid, "stat"))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[strings.LastIndex(string(stat), ")")+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+func TestRun_StopsTheProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// Background jobs of a shell ignore interrupts, so the child outlives the
+	// shell, holding its output open, and is only stopped by killing the group
+	cmd := exec.CommandContext(ctx, "sh", "-c", `sleep 30 & echo $! > "$0"; wait`, pidFile)
+	var out strings.Builder
+	cmd.Stdout = &out
+	start := time.Now()
+	if err := Run(cmd, 10*time.Second); err == nil {
+		t.Fatal("expected the canceled command to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the command to be stopped, took %s", elapsed)
+	}
+
+	pid, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("failed to read child pid: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for running(strings.TrimSpace(string(pid))) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the child process to be killed with the group")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestRun_CompletesWithoutCancel(t *testing.T) {
+	var out strings.Builder
+	cmd := exec.CommandContext(context.Background(), "sh", "-c", "echo done")
+	cmd.Stdout = &out
+	if err := Run(cmd, time.Second); err != nil || strings.TrimSpace(out.String()) != "done" {
+		t.Fatalf("Run() = %q, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package procgroup
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setGroup makes cmd the leader of a new process group
+func setGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// interruptGroup sends an interrupt to the process group led by p
+func interruptGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGINT)
+}
+
+// exited reports whether p exited and was waited for
+func exited(p *os.Process) bool {
+	return errors.Is(syscall.Kill(p.Pid, 0), syscall.ESRCH)
+}
+
+// killGroup kills the process group led by p
+func killGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}
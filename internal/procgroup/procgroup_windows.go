@@ -0,0 +1,27 @@
+//go:build windows
+
+package procgroup
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setGroup does nothing: Windows can't interrupt a process group, so only the
+// command itself is stopped
+func setGroup(*exec.Cmd) {}
+
+// interruptGroup kills p, since Windows doesn't support sending an interrupt
+func interruptGroup(p *os.Process) error {
+	return p.Kill()
+}
+
+// exited reports true: p is killed right away, so there is nothing to wait for
+func exited(*os.Process) bool {
+	return true
+}
+
+// killGroup kills p
+func killGroup(p *os.Process) error {
+	return p.Kill()
+}
@@ -0,0 +1,245 @@
+// Package providers finds the version constraints in the required_providers
+// blocks of a module that differ from a central table, and rewrites them,
+// for `motf providers sync`.
+package providers
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/hclform"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Drift is a provider whose version constraint in a module differs from the
+// central table.
+type Drift struct {
+	File     string `json:"file"` // Name of the .tf file in the module directory
+	Line     int    `json:"line"`
+	Provider string `json:"provider"` // Local name in required_providers
+	From     string `json:"from"`     // Empty when the provider has no version
+	To       string `json:"to"`
+
+	edits []hclform.Edit
+}
+
+// Find returns the providers in the required_providers blocks of the .tf
+// files in dir whose version constraint differs from constraints, a version
+// constraint per provider local name, sorted by file and line. Providers
+// that aren't in constraints are left alone, and constraints that only
+// differ in spacing are equal. .tf.json files aren't read.
+func Find(dir string, constraints map[string]string) ([]Drift, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var drifts []Drift
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".tf" {
+			continue
+		}
+		fileDrifts, err := findInFile(filepath.Join(dir, entry.Name()), constraints)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		drifts = append(drifts, fileDrifts...)
+	}
+	return drifts, nil
+}
+
+// findInFile returns the drifts of the required_providers blocks in the .tf file at path.
+func findInFile(path string, constraints map[string]string) ([]Drift, error) {
+	src, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	f, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var drifts []Drift
+	for _, block := range f.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "terraform" {
+			continue
+		}
+		for _, inner := range block.Body.Blocks {
+			if inner.Type != "required_providers" {
+				continue
+			}
+			attrs := slices.SortedFunc(maps.Values(inner.Body.Attributes), func(a, b *hclsyntax.Attribute) int {
+				return a.SrcRange.Start.Byte - b.SrcRange.Start.Byte
+			})
+			for _, attr := range attrs {
+				want, ok := constraints[attr.Name]
+				if !ok {
+					continue
+				}
+				drift, ok, err := check(src, attr, want)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: provider %s: %w", attr.SrcRange.Start.Line, attr.Name, err)
+				}
+				if ok {
+					drift.File = filepath.Base(path)
+					drifts = append(drifts, drift)
+				}
+			}
+		}
+	}
+	return drifts, nil
+}
+
+// check returns the drift of the required_providers entry attr in src from
+// the constraint want, and false if its version already is want. The entry
+// is either an object with source and version, or a version string.
+func check(src []byte, attr *hclsyntax.Attribute, want string) (Drift, bool, error) {
+	drift := Drift{Line: attr.SrcRange.Start.Line, Provider: attr.Name, To: want}
+	value := hclwrite.TokensForValue(cty.StringVal(want)).Bytes()
+
+	obj, isObject := attr.Expr.(*hclsyntax.ObjectConsExpr)
+	if !isObject {
+		from, err := stringValue(attr.Expr)
+		if err != nil {
+			return Drift{}, false, err
+		}
+		rng := attr.Expr.Range()
+		drift.From = from
+		drift.edits = []hclform.Edit{{Start: rng.Start.Byte, End: rng.End.Byte, Text: value}}
+		return drift, !sameConstraint(from, want), nil
+	}
+
+	for _, item := range obj.Items {
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || key.Type() != cty.String || key.AsString() != "version" {
+			continue
+		}
+		from, err := stringValue(item.ValueExpr)
+		if err != nil {
+			return Drift{}, false, err
+		}
+		rng := item.ValueExpr.Range()
+		drift.Line = rng.Start.Line
+		drift.From = from
+		drift.edits = []hclform.Edit{{Start: rng.Start.Byte, End: rng.End.Byte, Text: value}}
+		return drift, !sameConstraint(from, want), nil
+	}
+
+	drift.edits = addVersion(src, attr, obj, value)
+	return drift, true, nil
+}
+
+// addVersion returns the edits that add a version item with value to obj,
+// the object of the required_providers entry attr in src, on its own line
+// after the last item. The equals signs of the items on the lines right
+// above are aligned with it, like 'terraform fmt' does.
+func addVersion(src []byte, attr *hclsyntax.Attribute, obj *hclsyntax.ObjectConsExpr, value []byte) []hclform.Edit {
+	if len(obj.Items) == 0 {
+		indent := lineIndent(src, attr.SrcRange.Start.Byte)
+		text := "\n" + indent + "  version = " + string(value)
+		start, end := obj.OpenRange.End.Byte, obj.SrcRange.End.Byte-1
+		if len(bytes.TrimSpace(src[start:end])) > 0 {
+			// Keep comments, after the new line
+			end = start
+		} else {
+			text += "\n" + indent
+		}
+		return []hclform.Edit{{Start: start, End: end, Text: []byte(text)}}
+	}
+
+	// The items on consecutive single lines up to the last one
+	group := obj.Items[len(obj.Items)-1:]
+	for i := len(obj.Items) - 2; i >= 0; i-- {
+		item, next := obj.Items[i], group[0]
+		if item.KeyExpr.Range().Start.Line != item.ValueExpr.Range().End.Line ||
+			next.KeyExpr.Range().Start.Line != item.ValueExpr.Range().End.Line+1 {
+			break
+		}
+		group = obj.Items[i:]
+	}
+	last := group[len(group)-1]
+	if last.KeyExpr.Range().Start.Line != last.ValueExpr.Range().End.Line {
+		group = nil
+	}
+
+	width := len("version")
+	for _, item := range group {
+		width = max(width, item.KeyExpr.Range().End.Byte-item.KeyExpr.Range().Start.Byte)
+	}
+	var edits []hclform.Edit
+	for _, item := range group {
+		keyRange := item.KeyExpr.Range()
+		pad := strings.Repeat(" ", width-(keyRange.End.Byte-keyRange.Start.Byte))
+		edits = append(edits, hclform.Edit{Start: keyRange.End.Byte, End: item.ValueExpr.Range().Start.Byte, Text: []byte(pad + " = ")})
+	}
+
+	lastItem := obj.Items[len(obj.Items)-1]
+	indent := lineIndent(src, lastItem.KeyExpr.Range().Start.Byte)
+	text := "\n" + indent + "version" + strings.Repeat(" ", width-len("version")) + " = " + string(value)
+	at := lastItem.ValueExpr.Range().End.Byte
+	return append(edits, hclform.Edit{Start: at, End: at, Text: []byte(text)})
+}
+
+// lineIndent returns the spaces and tabs at the start of the line in src
+// that contains offset.
+func lineIndent(src []byte, offset int) string {
+	start := bytes.LastIndexByte(src[:offset], '\n') + 1
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end])
+}
+
+// stringValue returns the value of a literal string expression.
+func stringValue(expr hclsyntax.Expression) (string, error) {
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || value.Type() != cty.String || value.IsNull() {
+		return "", fmt.Errorf("version isn't a literal string")
+	}
+	return value.AsString(), nil
+}
+
+// sameConstraint reports whether the version constraints a and b only
+// differ in spacing.
+func sameConstraint(a, b string) bool {
+	return strings.Join(strings.Fields(a), "") == strings.Join(strings.Fields(b), "")
+}
+
+// Sync writes the constraints of drifts, found by Find in dir, to their
+// files, replacing only the changed values so the rest of each file is kept
+// as written.
+func Sync(dir string, drifts []Drift) error {
+	byFile := make(map[string][]hclform.Edit)
+	var files []string
+	for _, d := range drifts {
+		if _, ok := byFile[d.File]; !ok {
+			files = append(files, d.File)
+		}
+		byFile[d.File] = append(byFile[d.File], d.edits...)
+	}
+
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		src, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, hclform.Splice(src, byFile[name]), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const versionsTF = `terraform {
+  required_version = ">= 1.6"
+
+  required_providers {
+    azurerm = {
+      source  = "hashicorp/azurerm"
+      version = "~> 3.0" # pinned for now
+    }
+    random = {
+      source = "hashicorp/random"
+    }
+    tls  = "~>4.0"
+    null = "~> 3.2"
+    time = {
+      source  = "hashicorp/time"
+      version = ">= 0.9"
+    }
+  }
+}
+`
+
+// constraints is the central table of the tests; time isn't in it.
+var constraints = map[string]string{
+	"azurerm": "~> 4.0",
+	"random":  ">= 3.5",
+	"tls":     "~> 4.0",
+	"null":    "~> 3.3",
+}
+
+func writeModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "versions.tf"), []byte(versionsTF), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte("resource \"null_resource\" \"this\" {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestFind(t *testing.T) {
+	dir := writeModule(t)
+	drifts, err := Find(dir, constraints)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	var got []string
+	for _, d := range drifts {
+		got = append(got, fmt.Sprintf("%s:%d %s %q -> %q", d.File, d.Line, d.Provider, d.From, d.To))
+	}
+	want := []string{
+		`versions.tf:7 azurerm "~> 3.0" -> "~> 4.0"`,
+		`versions.tf:9 random "" -> ">= 3.5"`,
+		`versions.tf:13 null "~> 3.2" -> "~> 3.3"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Find() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSync(t *testing.T) {
+	dir := writeModule(t)
+	drifts, err := Find(dir, constraints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Sync(dir, drifts); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "versions.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `terraform {
+  required_version = ">= 1.6"
+
+  required_providers {
+    azurerm = {
+      source  = "hashicorp/azurerm"
+      version = "~> 4.0" # pinned for now
+    }
+    random = {
+      source  = "hashicorp/random"
+      version = ">= 3.5"
+    }
+    tls  = "~>4.0"
+    null = "~> 3.3"
+    time = {
+      source  = "hashicorp/time"
+      version = ">= 0.9"
+    }
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("versions.tf =\n%s\nwant\n%s", data, want)
+	}
+
+	// Synced modules have no drift left
+	if drifts, err := Find(dir, constraints); err != nil || len(drifts) != 0 {
+		t.Errorf("Find() after Sync() = %v, %v", drifts, err)
+	}
+}
+
+func TestSync_KeepsUnchangedLines(t *testing.T) {
+	dir := t.TempDir()
+	src := `terraform {
+  required_version=">= 1.6"
+  required_providers {
+    random = {}
+    tls = {
+      source = "hashicorp/tls" # signing
+      version = "~> 3.0"
+    }
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "versions.tf"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	drifts, err := Find(dir, constraints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Sync(dir, drifts); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "versions.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `terraform {
+  required_version=">= 1.6"
+  required_providers {
+    random = {
+      version = ">= 3.5"
+    }
+    tls = {
+      source = "hashicorp/tls" # signing
+      version = "~> 4.0"
+    }
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("versions.tf =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestFind_NotLiteral(t *testing.T) {
+	dir := t.TempDir()
+	src := "terraform {\n  required_providers {\n    azurerm = {\n      source  = \"hashicorp/azurerm\"\n      version = var.azurerm_version\n    }\n  }\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "versions.tf"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Find(dir, constraints); err == nil || !strings.Contains(err.Error(), "versions.tf: line 3: provider azurerm: version isn't a literal string") {
+		t.Errorf("Find() error = %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+// Package readme checks that the Inputs and Outputs tables in a module's
+// README, in the style terraform-docs writes them, list the module's
+// variables and outputs, and regenerates them, for `motf check docs`.
+package readme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+// File is the name of a module's README
+const File = "README.md"
+
+// Markers around the section terraform-docs and Fix generate
+const (
+	BeginMarker = "<!-- BEGIN_TF_DOCS -->"
+	EndMarker   = "<!-- END_TF_DOCS -->"
+)
+
+// headingPattern matches a Markdown heading and captures its text
+var headingPattern = regexp.MustCompile(`^#+\s+(.*?)\s*#*$`)
+
+// linkPattern captures the text of a Markdown link
+var linkPattern = regexp.MustCompile(`\[([^\]]*)\]\(`)
+
+// Finding is a variable or output that the README doesn't document, or that
+// it documents but the module doesn't declare.
+type Finding struct {
+	Kind       string `json:"kind"` // variable or output
+	Name       string `json:"name"`
+	Documented bool   `json:"documented"` // Whether it's in the README, and so missing from the module
+}
+
+// String returns e.g. "variable location isn't documented".
+func (f Finding) String() string {
+	if f.Documented {
+		return fmt.Sprintf("%s %s is documented but doesn't exist", f.Kind, f.Name)
+	}
+	return fmt.Sprintf("%s %s isn't documented", f.Kind, f.Name)
+}
+
+// Document is a parsed README.
+type Document struct {
+	content   string
+	variables []string // Names in the Inputs table
+	outputs   []string // Names in the Outputs table
+	tables    bool     // Whether it has an Inputs or Outputs section
+}
+
+// Read parses the README of the module at dir, or returns nil if it has none.
+func Read(dir string) (*Document, error) {
+	data, err := os.ReadFile(filepath.Join(dir, File))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{content: string(data)}
+	var section *[]string
+	for line := range strings.Lines(doc.content) {
+		line = strings.TrimSpace(line)
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			switch strings.ToLower(m[1]) {
+			case "inputs":
+				section, doc.tables = &doc.variables, true
+			case "outputs":
+				section, doc.tables = &doc.outputs, true
+			default:
+				section = nil
+			}
+			continue
+		}
+		if section == nil || !strings.HasPrefix(line, "|") {
+			continue
+		}
+		if name := rowName(line); name != "" && !slices.Contains(*section, name) {
+			*section = append(*section, name)
+		}
+	}
+	return doc, nil
+}
+
+// rowName returns the name in the first cell of a table row, or "" for the
+// header and separator rows.
+func rowName(row string) string {
+	cells := strings.Split(strings.Trim(row, "|"), "|")
+	cell := strings.TrimSpace(cells[0])
+	if m := linkPattern.FindStringSubmatch(cell); m != nil {
+		cell = m[1]
+	}
+	cell = strings.ReplaceAll(strings.Trim(cell, "`"), `\_`, "_")
+	if cell == "Name" || strings.Trim(cell, "-: ") == "" {
+		return ""
+	}
+	return cell
+}
+
+// Documents reports whether the README documents the module's interface:
+// it has terraform-docs markers, or an Inputs or Outputs section.
+func (d *Document) Documents() bool {
+	return d.tables || strings.Contains(d.content, BeginMarker)
+}
+
+// Check returns the variables and outputs of schema that the README doesn't
+// list, followed by the ones it lists that schema doesn't declare.
+func (d *Document) Check(schema *terraform.ModuleSchema) []Finding {
+	var variables, outputs []string
+	for _, v := range schema.Variables {
+		variables = append(variables, v.Name)
+	}
+	for _, o := range schema.Outputs {
+		outputs = append(outputs, o.Name)
+	}
+	findings := diff("variable", variables, d.variables)
+	return append(findings, diff("output", outputs, d.outputs)...)
+}
+
+// diff returns the findings of kind between the declared and documented names.
+func diff(kind string, declared, documented []string) []Finding {
+	var findings []Finding
+	for _, name := range slices.Sorted(slices.Values(declared)) {
+		if !slices.Contains(documented, name) {
+			findings = append(findings, Finding{Kind: kind, Name: name})
+		}
+	}
+	for _, name := range documented {
+		if !slices.Contains(declared, name) {
+			findings = append(findings, Finding{Kind: kind, Name: name, Documented: true})
+		}
+	}
+	return findings
+}
+
+// Fix writes the README with the section between the terraform-docs markers
+// replaced by tables generated from schema. A README without the markers
+// isn't changed, since motf can't tell where hand-written tables end.
+func (d *Document) Fix(dir string, schema *terraform.ModuleSchema) error {
+	begin := strings.Index(d.content, BeginMarker)
+	end := strings.Index(d.content, EndMarker)
+	if begin < 0 || end < begin {
+		return fmt.Errorf("%s has no %s and %s markers: add them around the Inputs and Outputs tables so motf can update them", File, BeginMarker, EndMarker)
+	}
+	content := d.content[:begin] + BeginMarker + "\n" + render(schema) + EndMarker + d.content[end+len(EndMarker):]
+
+	path := filepath.Join(dir, File)
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", File, err)
+	}
+	d.content = content
+	return nil
+}
+
+// render returns the Inputs and Outputs tables of schema, sorted by name, as
+// terraform-docs writes them.
+func render(schema *terraform.ModuleSchema) string {
+	var b strings.Builder
+	b.WriteString("## Inputs\n\n")
+	variables := slices.Clone(schema.Variables)
+	slices.SortFunc(variables, func(a, b terraform.VariableInfo) int { return strings.Compare(a.Name, b.Name) })
+	if len(variables) == 0 {
+		b.WriteString("No inputs.\n")
+	} else {
+		b.WriteString("| Name | Description | Type | Default | Required |\n")
+		b.WriteString("|------|-------------|------|---------|:--------:|\n")
+		for _, v := range variables {
+			def, required := "n/a", "yes"
+			if !v.Required {
+				def, required = "`"+v.FullDefaultString()+"`", "no"
+			}
+			fmt.Fprintf(&b, "| %s | %s | `%s` | %s | %s |\n", anchor("input", v.Name), cell(v.Description), valueOr(v.Type, "any"), def, required)
+		}
+	}
+
+	b.WriteString("\n## Outputs\n\n")
+	if len(schema.Outputs) == 0 {
+		b.WriteString("No outputs.\n")
+	} else {
+		b.WriteString("| Name | Description |\n")
+		b.WriteString("|------|-------------|\n")
+		for _, o := range schema.Outputs {
+			fmt.Fprintf(&b, "| %s | %s |\n", anchor("output", o.Name), cell(o.Description))
+		}
+	}
+	return b.String()
+}
+
+// anchor returns the name cell of a row, linking to itself like terraform-docs.
+func anchor(kind, name string) string {
+	return fmt.Sprintf(`<a name="%s_%s"></a> [%s](#%s\_%s)`, kind, name, name, kind, strings.ReplaceAll(name, "_", `\_`))
+}
+
+// cell returns text escaped for a table cell, or n/a when it's empty.
+func cell(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "n/a"
+	}
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// valueOr returns s, or def when s is empty.
+func valueOr(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package readme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+func writeReadme(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, File), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", File, err)
+	}
+}
+
+var schema = &terraform.ModuleSchema{
+	Variables: []terraform.VariableInfo{
+		{Name: "location", Type: "string", Required: true, Description: "Location of the account"},
+		{Name: "tags", Type: "map(string)", Default: map[string]any{}},
+		{Name: "sku", Default: "Standard", Description: "SKU | tier"},
+	},
+	Outputs: []terraform.OutputInfo{{Name: "id", Description: "ID of the account"}},
+}
+
+func TestRead_Check(t *testing.T) {
+	dir := t.TempDir()
+	writeReadme(t, dir, `# Storage account
+
+## Usage
+
+| Name | Value |
+|------|-------|
+| usage | not an input |
+
+<!-- BEGIN_TF_DOCS -->
+## Inputs
+
+| Name | Description | Type | Default | Required |
+|------|-------------|------|---------|:--------:|
+| <a name="input_location"></a> [location](#input\_location) | Location | `+"`string`"+` | n/a | yes |
+| <a name="input_replicas"></a> [replicas](#input\_replicas) | Replicas | `+"`number`"+` | `+"`1`"+` | no |
+
+## Outputs
+
+| Name | Description |
+|------|-------------|
+| `+"`id`"+` | ID |
+<!-- END_TF_DOCS -->
+`)
+
+	doc, err := Read(dir)
+	if err != nil || doc == nil {
+		t.Fatalf("Read() = %v, %v", doc, err)
+	}
+	if !doc.Documents() {
+		t.Error("Documents() = false")
+	}
+	var got []string
+	for _, f := range doc.Check(schema) {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"variable sku isn't documented",
+		"variable tags isn't documented",
+		"variable replicas is documented but doesn't exist",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRead_NoReadme(t *testing.T) {
+	doc, err := Read(t.TempDir())
+	if doc != nil || err != nil {
+		t.Errorf("Read() = %v, %v, want nil", doc, err)
+	}
+}
+
+func TestFix(t *testing.T) {
+	dir := t.TempDir()
+	writeReadme(t, dir, "# Storage account\n\n<!-- BEGIN_TF_DOCS -->\nold\n<!-- END_TF_DOCS -->\n\nLicense: MIT\n")
+	doc, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Fix(dir, schema); err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, File))
+	want := "# Storage account\n\n<!-- BEGIN_TF_DOCS -->\n## Inputs\n\n" +
+		"| Name | Description | Type | Default | Required |\n" +
+		"|------|-------------|------|---------|:--------:|\n" +
+		"| <a name=\"input_location\"></a> [location](#input\\_location) | Location of the account | `string` | n/a | yes |\n" +
+		"| <a name=\"input_sku\"></a> [sku](#input\\_sku) | SKU \\| tier | `any` | `\"Standard\"` | no |\n" +
+		"| <a name=\"input_tags\"></a> [tags](#input\\_tags) | n/a | `map(string)` | `{}` | no |\n" +
+		"\n## Outputs\n\n" +
+		"| Name | Description |\n" +
+		"|------|-------------|\n" +
+		"| <a name=\"output_id\"></a> [id](#output\\_id) | ID of the account |\n" +
+		"<!-- END_TF_DOCS -->\n\nLicense: MIT\n"
+	if string(data) != want {
+		t.Errorf("README:\n%s\nwant:\n%s", data, want)
+	}
+
+	doc, _ = Read(dir)
+	if findings := doc.Check(schema); len(findings) != 0 {
+		t.Errorf("findings after Fix() = %v", findings)
+	}
+}
+
+func TestRead_NoTables(t *testing.T) {
+	dir := t.TempDir()
+	writeReadme(t, dir, "# Storage account\n")
+	doc, _ := Read(dir)
+	if doc.Documents() {
+		t.Error("Documents() = true for a README without tables")
+	}
+}
+
+func TestFix_TablesWithoutMarkers(t *testing.T) {
+	dir := t.TempDir()
+	content := "# Storage account\n\n## Inputs\n\n| Name |\n|---|\n| location |\n"
+	writeReadme(t, dir, content)
+	doc, _ := Read(dir)
+	if err := doc.Fix(dir, schema); err == nil || !strings.Contains(err.Error(), "add them around") {
+		t.Errorf("Fix() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, File)); string(data) != content {
+		t.Error("Fix() changed a README with tables outside the markers")
+	}
+}
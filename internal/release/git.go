@@ -0,0 +1,78 @@
+package release
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Git runs git commands in a repository. The git CLI is used instead of
+// go-git so commits and tags honor the user's signing and push credentials.
+type Git struct {
+	Dir string // Repository root
+}
+
+// Tags returns the names of all tags.
+func (g Git) Tags() ([]string, error) {
+	out, err := g.output("tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// Subjects returns the subjects of the commits that changed path, newest
+// first: since the commit since, or in all history when since is empty.
+func (g Git) Subjects(since, path string) ([]string, error) {
+	args := []string{"log", "--format=%s"}
+	if since != "" {
+		args = append(args, since+"..HEAD")
+	}
+	out, err := g.output(append(args, "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+	var subjects []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			subjects = append(subjects, line)
+		}
+	}
+	return subjects, nil
+}
+
+// Commit commits files (relative to Dir) with message. Only these files are
+// committed, even if others are staged.
+func (g Git) Commit(message string, files ...string) error {
+	if _, err := g.output(append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+	_, err := g.output(append([]string{"commit", "-m", message, "--"}, files...)...)
+	return err
+}
+
+// Tag creates an annotated tag at HEAD.
+func (g Git) Tag(name, message string) error {
+	_, err := g.output("tag", "-a", name, "-m", message)
+	return err
+}
+
+// Push pushes refs (e.g. HEAD and tags) to remote.
+func (g Git) Push(remote string, refs ...string) error {
+	_, err := g.output(append([]string{"push", remote}, refs...)...)
+	return err
+}
+
+// output runs git with args and returns its stdout.
+func (g Git) output(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.Dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,105 @@
+package release
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a git repository with a commit changing each of files.
+func initRepo(t *testing.T, files ...string) (string, func(args ...string) string) {
+	t.Helper()
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\nOutput: %s", args, err, output)
+		}
+		return string(output)
+	}
+	git("init")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	git("config", "tag.gpgSign", "false")
+	git("config", "commit.gpgSign", "false")
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(file), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", file, err)
+		}
+		git("add", "-A")
+		git("commit", "-m", "change "+file)
+	}
+	return dir, git
+}
+
+func TestGit_TagsAndSubjects(t *testing.T) {
+	dir, git := initRepo(t, "components/storage/main.tf", "components/network/main.tf")
+	git("tag", "components/storage/v1.0.0")
+	if err := os.WriteFile(filepath.Join(dir, "components/storage/variables.tf"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	git("add", "-A")
+	git("commit", "-m", "feat: add variables")
+
+	g := Git{Dir: dir}
+	tags, err := g.Tags()
+	if err != nil {
+		t.Fatalf("Tags() error = %v", err)
+	}
+	if strings.Join(tags, ",") != "components/storage/v1.0.0" {
+		t.Errorf("Tags() = %v", tags)
+	}
+
+	subjects, err := g.Subjects("components/storage/v1.0.0", "components/storage")
+	if err != nil {
+		t.Fatalf("Subjects() error = %v", err)
+	}
+	if strings.Join(subjects, ",") != "feat: add variables" {
+		t.Errorf("Subjects() since tag = %v", subjects)
+	}
+
+	all, err := g.Subjects("", "components/storage")
+	if err != nil {
+		t.Fatalf("Subjects() error = %v", err)
+	}
+	if strings.Join(all, ",") != "feat: add variables,change components/storage/main.tf" {
+		t.Errorf("Subjects() without tag = %v", all)
+	}
+}
+
+func TestGit_CommitAndTag(t *testing.T) {
+	dir, git := initRepo(t, "README.md")
+	for _, file := range []string{"CHANGELOG.md", "unrelated.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("x"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", file, err)
+		}
+	}
+	git("add", "unrelated.txt")
+
+	g := Git{Dir: dir}
+	if err := g.Commit("chore(release): storage v1.0.0", "CHANGELOG.md"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	if err := g.Tag("components/storage/v1.0.0", "Release"); err != nil {
+		t.Fatalf("Tag() error = %v", err)
+	}
+
+	if got := git("show", "--name-only", "--format=%s", "HEAD"); !strings.Contains(got, "CHANGELOG.md") || strings.Contains(got, "unrelated.txt") {
+		t.Errorf("expected only CHANGELOG.md to be committed, got:\n%s", got)
+	}
+	if got := strings.TrimSpace(git("describe", "--tags")); got != "components/storage/v1.0.0" {
+		t.Errorf("tag at HEAD = %s", got)
+	}
+	if err := g.Tag("components/storage/v1.0.0", "Release"); err == nil {
+		t.Error("expected an error for an existing tag")
+	}
+}
@@ -0,0 +1,147 @@
+// Package release versions, documents, and tags releases of individual
+// modules in a monorepo.
+package release
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Steps of a release, in the order they run
+const (
+	StepBump      = "bump"      // Increment module_version in .spacelift/config.yml
+	StepChangelog = "changelog" // Add the release to the module's changelog
+	StepCommit    = "commit"    // Commit the bumped and changelog files
+	StepTag       = "tag"       // Create the module's release tag
+	StepPush      = "push"      // Push the commit and tags
+)
+
+// Steps lists the release steps in order.
+var Steps = []string{StepBump, StepChangelog, StepCommit, StepTag, StepPush}
+
+// DefaultChangelog is the changelog file name in each module
+const DefaultChangelog = "CHANGELOG.md"
+
+// versionPattern matches MAJOR.MINOR.PATCH with an optional v prefix
+var versionPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)$`)
+
+// TagName returns the release tag of a module, e.g.
+// components/azurerm/storage-account/v1.2.3 for modulePath
+// components/azurerm/storage-account (slash-separated, relative to the
+// repository root).
+func TagName(modulePath, version string) string {
+	return modulePath + "/v" + strings.TrimPrefix(version, "v")
+}
+
+// LatestTag returns the release tag of modulePath with the highest version,
+// and that version without the v prefix. It returns empty strings when the
+// module has no release tags.
+func LatestTag(tags []string, modulePath string) (tag, version string) {
+	var latest [3]int
+	prefix := modulePath + "/"
+	for _, t := range tags {
+		rest, ok := strings.CutPrefix(t, prefix)
+		if !ok {
+			continue
+		}
+		parts, ok := parseVersion(rest)
+		if !ok {
+			continue
+		}
+		if tag == "" || compare(parts, latest) > 0 {
+			tag, latest = t, parts
+			version = strings.TrimPrefix(rest, "v")
+		}
+	}
+	return tag, version
+}
+
+// IsVersion reports whether version is MAJOR.MINOR.PATCH, optionally with a
+// v prefix.
+func IsVersion(version string) bool {
+	return versionPattern.MatchString(version)
+}
+
+// CompareVersions returns -1, 0, or 1 when version a is lower than, equal
+// to, or higher than b. Versions are MAJOR.MINOR.PATCH with an optional v
+// prefix; a version that isn't is lower than any that is.
+func CompareVersions(a, b string) int {
+	pa, okA := parseVersion(a)
+	pb, okB := parseVersion(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+	return compare(pa, pb)
+}
+
+// parseVersion returns the parts of a MAJOR.MINOR.PATCH version.
+func parseVersion(version string) ([3]int, bool) {
+	m := versionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return [3]int{}, false
+	}
+	var parts [3]int
+	for i := range parts {
+		parts[i], _ = strconv.Atoi(m[i+1])
+	}
+	return parts, true
+}
+
+// compare returns -1, 0, or 1 when a is lower than, equal to, or higher than b.
+func compare(a, b [3]int) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// ChangelogSection formats the changelog entry of a release: a heading with
+// the version and date, followed by one bullet per change.
+func ChangelogSection(version string, date time.Time, changes []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## v%s (%s)\n\n", strings.TrimPrefix(version, "v"), date.Format("2006-01-02"))
+	if len(changes) == 0 {
+		b.WriteString("- No changes recorded\n")
+	}
+	for _, change := range changes {
+		fmt.Fprintf(&b, "- %s\n", change)
+	}
+	return b.String()
+}
+
+// PrependChangelog adds section to the changelog at path above the previous
+// releases, keeping a leading "# " title. The file is created if needed.
+func PrependChangelog(path, section string) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	existing := string(data)
+
+	var out string
+	switch {
+	case existing == "":
+		out = "# Changelog\n\n" + section
+	case strings.HasPrefix(existing, "# "):
+		title, rest, _ := strings.Cut(existing, "\n")
+		out = title + "\n\n" + section + "\n" + strings.TrimLeft(rest, "\n")
+	default:
+		out = section + "\n" + existing
+	}
+	return os.WriteFile(path, []byte(out), 0644)
+}
@@ -0,0 +1,109 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTagName(t *testing.T) {
+	for _, version := range []string{"1.2.3", "v1.2.3"} {
+		if got := TagName("components/azurerm/storage-account", version); got != "components/azurerm/storage-account/v1.2.3" {
+			t.Errorf("TagName(%q) = %s", version, got)
+		}
+	}
+}
+
+func TestLatestTag(t *testing.T) {
+	tags := []string{
+		"components/storage/v1.2.3",
+		"components/storage/v1.10.0",
+		"components/storage/v1.9.9",
+		"components/storage/latest",
+		"components/storage-account/v9.0.0",
+		"v3.0.0",
+	}
+
+	tag, version := LatestTag(tags, "components/storage")
+	if tag != "components/storage/v1.10.0" || version != "1.10.0" {
+		t.Errorf("LatestTag() = %s, %s; want components/storage/v1.10.0, 1.10.0", tag, version)
+	}
+
+	if tag, version := LatestTag(tags, "components/network"); tag != "" || version != "" {
+		t.Errorf("expected no tag for an unreleased module, got %s, %s", tag, version)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.10.0", "1.9.9", 1},
+		{"1.2.3", "2.0.0", -1},
+		{"latest", "0.0.1", -1},
+	}
+	if !IsVersion("v1.2.3") || IsVersion("1.2") {
+		t.Error("expected IsVersion to accept only MAJOR.MINOR.PATCH")
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestChangelogSection(t *testing.T) {
+	date := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	got := ChangelogSection("1.3.0", date, []string{"feat: add private endpoint", "fix: tag names"})
+	want := "## v1.3.0 (2026-10-16)\n\n- feat: add private endpoint\n- fix: tag names\n"
+	if got != want {
+		t.Errorf("ChangelogSection() = %q, want %q", got, want)
+	}
+
+	if got := ChangelogSection("v0.1.0", date, nil); got != "## v0.1.0 (2026-10-16)\n\n- No changes recorded\n" {
+		t.Errorf("ChangelogSection() without changes = %q", got)
+	}
+}
+
+func TestPrependChangelog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultChangelog)
+
+	if err := PrependChangelog(path, "## v1.0.0 (2026-01-01)\n\n- first\n"); err != nil {
+		t.Fatalf("PrependChangelog() error = %v", err)
+	}
+	if err := PrependChangelog(path, "## v1.1.0 (2026-02-01)\n\n- second\n"); err != nil {
+		t.Fatalf("PrependChangelog() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read changelog: %v", err)
+	}
+	want := "# Changelog\n\n## v1.1.0 (2026-02-01)\n\n- second\n\n## v1.0.0 (2026-01-01)\n\n- first\n"
+	if string(data) != want {
+		t.Errorf("changelog:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestPrependChangelog_NoTitle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultChangelog)
+	if err := os.WriteFile(path, []byte("## v1.0.0\n\n- first\n"), 0644); err != nil {
+		t.Fatalf("failed to write changelog: %v", err)
+	}
+
+	if err := PrependChangelog(path, "## v1.1.0\n\n- second\n"); err != nil {
+		t.Fatalf("PrependChangelog() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read changelog: %v", err)
+	}
+	if string(data) != "## v1.1.0\n\n- second\n\n## v1.0.0\n\n- first\n" {
+		t.Errorf("changelog = %q", data)
+	}
+}
@@ -0,0 +1,123 @@
+package scaffold
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// stateBlocks are the blocks of a terraform block that configure where the
+// module's own state is stored, which an example doesn't share
+var stateBlocks = []string{"backend", "cloud"}
+
+// Example generates the main.tf of an example that calls the module at
+// moduleDir, whose schema is given, from source. The module's terraform
+// blocks are copied without their backend or cloud block, and so are its
+// provider blocks. Providers the module uses without a provider block get an
+// empty one. The module block sets the required variables to placeholders of
+// their type. Only .tf files are copied from; .tf.json files are skipped.
+func Example(moduleDir, source string, schema *terraform.ModuleSchema) ([]byte, error) {
+	entries, err := os.ReadDir(moduleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings, providers []*hclwrite.Block
+	var configured []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".tf" {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(moduleDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		file, diags := hclwrite.ParseConfig(src, entry.Name(), hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("failed to parse %s: %w", entry.Name(), diags)
+		}
+		for _, block := range file.Body().Blocks() {
+			switch block.Type() {
+			case "terraform":
+				if settingsBlock := withoutState(block); settingsBlock != nil {
+					settings = append(settings, settingsBlock)
+				}
+			case "provider":
+				providers = append(providers, block)
+				configured = append(configured, block.Labels()[0])
+			}
+		}
+	}
+	for _, p := range schema.Providers {
+		if !slices.Contains(configured, p.Name) {
+			providers = append(providers, emptyProvider(p.Name))
+		}
+	}
+
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	for _, block := range slices.Concat(settings, providers) {
+		body.AppendBlock(block)
+		body.AppendNewline()
+	}
+	module := body.AppendNewBlock("module", []string{schema.Name}).Body()
+	module.SetAttributeValue("source", cty.StringVal(source))
+	required := false
+	for _, v := range schema.Variables {
+		if !v.Required {
+			continue
+		}
+		if !required {
+			module.AppendNewline()
+			required = true
+		}
+		// The placeholders are literals like "" and [], written as they are
+		module.SetAttributeRaw(v.Name, hclwrite.TokensForIdentifier(v.EmptyValueForType()))
+	}
+	return hclwrite.Format(f.Bytes()), nil
+}
+
+// withoutState returns a copy of the terraform block without its backend or
+// cloud block, or nil if nothing else is left. Arguments are sorted by name,
+// and comments inside the block aren't copied.
+func withoutState(block *hclwrite.Block) *hclwrite.Block {
+	settings := hclwrite.NewBlock("terraform", nil)
+	body := settings.Body()
+	attrs := block.Body().Attributes()
+	for _, name := range slices.Sorted(maps.Keys(attrs)) {
+		body.SetAttributeRaw(name, attrs[name].Expr().BuildTokens(nil))
+	}
+	empty := len(attrs) == 0
+	for _, nested := range block.Body().Blocks() {
+		if slices.Contains(stateBlocks, nested.Type()) {
+			continue
+		}
+		if !empty {
+			body.AppendNewline()
+		}
+		body.AppendBlock(nested)
+		empty = false
+	}
+	if empty {
+		return nil
+	}
+	return settings
+}
+
+// emptyProvider returns a provider block for name without settings. The
+// azurerm provider requires a features block, even an empty one.
+func emptyProvider(name string) *hclwrite.Block {
+	src := fmt.Sprintf("provider %q {}\n", name)
+	if name == "azurerm" {
+		src = fmt.Sprintf("provider %q {\n  features {}\n}\n", name)
+	}
+	f, _ := hclwrite.ParseConfig([]byte(src), "provider.tf", hcl.InitialPos)
+	return f.Body().Blocks()[0]
+}
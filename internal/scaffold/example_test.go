@@ -0,0 +1,101 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/terraform"
+)
+
+func TestExample(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"versions.tf": `terraform {
+  required_version = ">= 1.6"
+
+  backend "azurerm" {}
+
+  required_providers {
+    azurerm = {
+      source  = "hashicorp/azurerm"
+      version = "~> 4.0"
+    }
+  }
+}
+`,
+		"providers.tf": `provider "random" {
+  alias = "seeded"
+}
+`,
+		"main.tf": `resource "azurerm_resource_group" "this" {
+  name     = var.name
+  location = var.location
+}
+`,
+		"backend.tf":       "terraform {\n  backend \"local\" {}\n}\n",
+		"versions.tf.json": `{"terraform": {"required_version": ">= 1.0"}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	schema := &terraform.ModuleSchema{
+		Name:      "resource-group",
+		Providers: []terraform.ProviderInfo{{Name: "azurerm"}, {Name: "random"}},
+		Variables: []terraform.VariableInfo{
+			{Name: "location", Type: "string", Required: true},
+			{Name: "tags", Type: "map(string)", Required: true},
+			{Name: "sku", Type: "string", Default: "Standard"},
+		},
+	}
+
+	got, err := Example(dir, "../..", schema)
+	if err != nil {
+		t.Fatalf("Example() error = %v", err)
+	}
+	want := `terraform {
+  required_version = ">= 1.6"
+
+  required_providers {
+    azurerm = {
+      source  = "hashicorp/azurerm"
+      version = "~> 4.0"
+    }
+  }
+}
+
+provider "random" {
+  alias = "seeded"
+}
+
+provider "azurerm" {
+  features {}
+}
+
+module "resource-group" {
+  source = "../.."
+
+  location = ""
+  tags     = {}
+}
+`
+	if string(got) != want {
+		t.Errorf("Example() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExample_NoRequiredVariables(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(""), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Example(dir, "../..", &terraform.ModuleSchema{Name: "naming"})
+	if err != nil {
+		t.Fatalf("Example() error = %v", err)
+	}
+	if want := "module \"naming\" {\n  source = \"../..\"\n}\n"; string(got) != want {
+		t.Errorf("Example() = %q, want %q", got, want)
+	}
+}
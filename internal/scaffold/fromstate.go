@@ -0,0 +1,534 @@
+// Package scaffold generates Terraform module source files.
+package scaffold
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ResourceLabel is the name given to the resource in a generated component
+const ResourceLabel = "this"
+
+// outputAttributes are identifying attributes exposed as outputs when present
+var outputAttributes = []string{"id", "arn"}
+
+// State is the subset of `terraform show -json` output used for scaffolding.
+type State struct {
+	Values *StateValues `json:"values"`
+}
+
+// StateValues holds the resources of the root module and its descendants.
+type StateValues struct {
+	RootModule StateModule `json:"root_module"`
+}
+
+// StateModule is a module in the state, with its resources and child modules.
+type StateModule struct {
+	Resources    []StateResource `json:"resources"`
+	ChildModules []StateModule   `json:"child_modules"`
+}
+
+// StateResource is a single resource instance in the state.
+type StateResource struct {
+	Address      string         `json:"address"`
+	Mode         string         `json:"mode"`
+	Type         string         `json:"type"`
+	Name         string         `json:"name"`
+	ProviderName string         `json:"provider_name"`
+	Values       map[string]any `json:"values"`
+}
+
+// ProviderSchemas is the subset of `terraform providers schema -json` output used for scaffolding.
+type ProviderSchemas struct {
+	Schemas map[string]ProviderSchema `json:"provider_schemas"`
+}
+
+// ProviderSchema holds the resource schemas of a provider.
+type ProviderSchema struct {
+	ResourceSchemas map[string]Schema `json:"resource_schemas"`
+}
+
+// Schema is the schema of a resource type.
+type Schema struct {
+	Block Block `json:"block"`
+}
+
+// Block describes the attributes and nested blocks of a resource or block.
+type Block struct {
+	Attributes map[string]Attribute   `json:"attributes"`
+	BlockTypes map[string]NestedBlock `json:"block_types"`
+}
+
+// Attribute describes a single attribute of a block.
+type Attribute struct {
+	Required   bool `json:"required"`
+	Optional   bool `json:"optional"`
+	Computed   bool `json:"computed"`
+	Sensitive  bool `json:"sensitive"`
+	Deprecated bool `json:"deprecated"`
+}
+
+// Configurable reports whether the attribute can be set in configuration.
+func (a Attribute) Configurable() bool {
+	return (a.Required || a.Optional) && !a.Deprecated
+}
+
+// NestedBlock describes a nested block type.
+type NestedBlock struct {
+	NestingMode string `json:"nesting_mode"`
+	Block       Block  `json:"block"`
+}
+
+// ParseState parses the output of `terraform show -json`.
+func ParseState(data []byte) (*State, error) {
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state: %w", err)
+	}
+	if state.Values == nil {
+		return nil, fmt.Errorf("state has no resources")
+	}
+	return &state, nil
+}
+
+// ParseProviderSchemas parses the output of `terraform providers schema -json`.
+func ParseProviderSchemas(data []byte) (*ProviderSchemas, error) {
+	var schemas ProviderSchemas
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, fmt.Errorf("failed to parse provider schemas: %w", err)
+	}
+	return &schemas, nil
+}
+
+// FindResources returns the managed resource instances matching addr.
+// addr matches an instance exactly, or all instances of a resource when the
+// index is omitted (e.g. "aws_s3_bucket.logs" matches "aws_s3_bucket.logs[0]").
+func (s *State) FindResources(addr string) []StateResource {
+	var matches []StateResource
+	var walk func(mod StateModule)
+	walk = func(mod StateModule) {
+		for _, r := range mod.Resources {
+			if r.Mode != "" && r.Mode != "managed" {
+				continue
+			}
+			if r.Address == addr || strings.HasPrefix(r.Address, addr+"[") {
+				matches = append(matches, r)
+			}
+		}
+		for _, child := range mod.ChildModules {
+			walk(child)
+		}
+	}
+	walk(s.Values.RootModule)
+	return matches
+}
+
+// Options controls component generation.
+type Options struct {
+	Resources []StateResource  // Resource instances to lift into the component (same type)
+	Schemas   *ProviderSchemas // Provider schemas, used to skip computed attributes
+	Variables []string         // Attributes to parameterize even if identical across instances
+	JSON      bool             // Write .tf.json files in JSON syntax instead of .tf files
+}
+
+// Component is a generated component.
+type Component struct {
+	Files     map[string][]byte // File name -> content
+	Variables []string          // Names of the generated variables
+	Outputs   []string          // Names of the generated outputs
+}
+
+// FromState generates a component from one or more resource instances.
+//
+// Attributes that differ between instances (or are listed in opts.Variables)
+// become variables, sensitive attributes always become variables, and all other
+// configurable attributes are written as literals. The same goes for the
+// attributes of nested blocks, whose variables are named after their block,
+// e.g. service_principal_client_secret. Computed-only attributes are skipped.
+// Identifying attributes (id, arn) become outputs.
+func FromState(opts Options) (*Component, error) {
+	if len(opts.Resources) == 0 {
+		return nil, fmt.Errorf("no resources to generate from")
+	}
+
+	first := opts.Resources[0]
+	for _, r := range opts.Resources[1:] {
+		if r.Type != first.Type || r.ProviderName != first.ProviderName {
+			return nil, fmt.Errorf("resources must have the same type: %s is %s, %s is %s", first.Address, first.Type, r.Address, r.Type)
+		}
+	}
+
+	schema, err := opts.Schemas.resourceSchema(first.ProviderName, first.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	forced := make(map[string]bool, len(opts.Variables))
+	for _, name := range opts.Variables {
+		attr, ok := schema.Block.Attributes[name]
+		if !ok || !attr.Configurable() {
+			return nil, fmt.Errorf("'%s' is not a configurable attribute of %s", name, first.Type)
+		}
+		forced[name] = true
+	}
+
+	main := hclwrite.NewEmptyFile()
+	resource := main.Body().AppendNewBlock("resource", []string{first.Type, ResourceLabel}).Body()
+	variables := hclwrite.NewEmptyFile()
+	component := &Component{Files: make(map[string][]byte)}
+	g := &generator{variables: variables.Body(), component: component}
+
+	for _, name := range sortedKeys(schema.Block.Attributes) {
+		attr := schema.Block.Attributes[name]
+		// Legacy provider schemas mark id as optional, but it is never set in configuration
+		if !attr.Configurable() || name == "id" {
+			continue
+		}
+
+		values := make([]any, len(opts.Resources))
+		for i, r := range opts.Resources {
+			values[i] = r.Values[name]
+		}
+		if err := g.setAttribute(resource, name, name, name, values, attr, forced[name]); err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+	}
+
+	for _, name := range sortedKeys(schema.Block.BlockTypes) {
+		values := make([]any, len(opts.Resources))
+		for i, r := range opts.Resources {
+			values[i] = r.Values[name]
+		}
+		if err := g.appendNestedBlocks(resource, name, "", schema.Block.BlockTypes[name], values, opts.Resources); err != nil {
+			return nil, fmt.Errorf("block %s: %w", name, err)
+		}
+	}
+
+	outputs := hclwrite.NewEmptyFile()
+	for _, name := range outputAttributes {
+		if _, ok := first.Values[name]; !ok {
+			continue
+		}
+		if len(outputs.Body().Blocks()) > 0 {
+			outputs.Body().AppendNewline()
+		}
+		out := outputs.Body().AppendNewBlock("output", []string{name}).Body()
+		out.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("The %s of the %s", name, first.Type)))
+		out.SetAttributeTraversal("value", hcl.Traversal{
+			hcl.TraverseRoot{Name: first.Type},
+			hcl.TraverseAttr{Name: ResourceLabel},
+			hcl.TraverseAttr{Name: name},
+		})
+		component.Outputs = append(component.Outputs, name)
+	}
+
+	component.Files["main.tf"] = hclwrite.Format(main.Bytes())
+	component.Files["variables.tf"] = hclwrite.Format(variables.Bytes())
+	component.Files["outputs.tf"] = hclwrite.Format(outputs.Bytes())
+	component.Files["versions.tf"] = versionsFile(first.ProviderName)
+
+	if opts.JSON {
+		files := make(map[string][]byte, len(component.Files))
+		for name, src := range component.Files {
+			converted, err := ToJSON(src)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			files[name+".json"] = converted
+		}
+		component.Files = files
+	}
+	return component, nil
+}
+
+// resourceSchema returns the schema for a resource type.
+func (p *ProviderSchemas) resourceSchema(provider, resourceType string) (Schema, error) {
+	if p == nil {
+		return Schema{}, fmt.Errorf("provider schemas are required")
+	}
+	providerSchema, ok := p.Schemas[provider]
+	if !ok {
+		return Schema{}, fmt.Errorf("no schema for provider %s", provider)
+	}
+	schema, ok := providerSchema.ResourceSchemas[resourceType]
+	if !ok {
+		return Schema{}, fmt.Errorf("no schema for resource type %s", resourceType)
+	}
+	return schema, nil
+}
+
+// generator writes the variables of a component while its resource is generated.
+type generator struct {
+	variables *hclwrite.Body
+	component *Component
+}
+
+// setAttribute sets the attribute name of body from its value in each
+// instance: as a literal when they are the same, or else as a reference to
+// the variable varName, which is added to the component. Sensitive and forced
+// attributes always become variables. argument names the attribute in the
+// variable's description, e.g. "service_principal.client_secret".
+func (g *generator) setAttribute(body *hclwrite.Body, name, varName, argument string, values []any, attr Attribute, forced bool) error {
+	allNil := true
+	for _, v := range values {
+		if v != nil {
+			allNil = false
+		}
+	}
+	if allNil {
+		return nil
+	}
+
+	same := allEqual(values)
+	if same && !forced && !attr.Sensitive {
+		// Unset optional attributes are stored as empty values
+		if isEmpty(values[0]) {
+			return nil
+		}
+		val, err := toCty(values[0])
+		if err != nil {
+			return err
+		}
+		body.SetAttributeValue(name, val)
+		return nil
+	}
+
+	if slices.Contains(g.component.Variables, varName) {
+		return fmt.Errorf("variable %s is generated twice", varName)
+	}
+	// Only keep the value as a default when every instance agrees and it isn't a secret
+	var def any
+	if same && !attr.Sensitive {
+		def = values[0]
+	}
+	if err := appendVariable(g.variables, varName, argument, firstNonNil(values), def, attr.Sensitive); err != nil {
+		return err
+	}
+	body.SetAttributeTraversal(name, hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: varName}})
+	g.component.Variables = append(g.component.Variables, varName)
+	return nil
+}
+
+// appendVariable adds a variable block for the attribute argument.
+func appendVariable(body *hclwrite.Body, name, argument string, sample, def any, sensitive bool) error {
+	if len(body.Blocks()) > 0 {
+		body.AppendNewline()
+	}
+
+	sampleVal, err := toCty(sample)
+	if err != nil {
+		return err
+	}
+
+	block := body.AppendNewBlock("variable", []string{name}).Body()
+	block.SetAttributeRaw("type", typeTokens(sampleVal.Type()))
+	block.SetAttributeValue("description", cty.StringVal(fmt.Sprintf("Value of the %s argument", argument)))
+	if def != nil {
+		defVal, err := toCty(def)
+		if err != nil {
+			return err
+		}
+		block.SetAttributeValue("default", defVal)
+	}
+	if sensitive {
+		block.SetAttributeValue("sensitive", cty.True)
+	}
+	return nil
+}
+
+// appendNestedBlocks writes the nested blocks name of a resource from their
+// state value in each instance, which is a list of objects (or a single
+// object for nesting mode "single"). Blocks are matched by position, so every
+// instance must have the same number of them. path is the argument path of
+// the parent block, e.g. "site_config.", or "" for the resource.
+func (g *generator) appendNestedBlocks(body *hclwrite.Body, name, path string, nested NestedBlock, values []any, resources []StateResource) error {
+	items := make([][]any, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case []any:
+			items[i] = v
+		case map[string]any:
+			items[i] = []any{v}
+		default:
+			return fmt.Errorf("unexpected value of type %T", value)
+		}
+		if len(items[i]) != len(items[0]) {
+			return fmt.Errorf("%s has %d block(s) but %s has %d, generate from them separately",
+				resources[0].Address, len(items[0]), resources[i].Address, len(items[i]))
+		}
+	}
+
+	for n := range items[0] {
+		objs := make([]map[string]any, len(items))
+		for i := range items {
+			obj, ok := items[i][n].(map[string]any)
+			if !ok {
+				return fmt.Errorf("unexpected item of type %T", items[i][n])
+			}
+			objs[i] = obj
+		}
+
+		// Variables are named after the block path, with the position of repeated blocks
+		blockPath := path + name
+		if len(items[0]) > 1 {
+			blockPath += fmt.Sprintf("[%d]", n)
+		}
+		varPrefix := strings.NewReplacer(".", "_", "[", "_", "]", "").Replace(blockPath) + "_"
+
+		block := body.AppendNewBlock(name, nil).Body()
+		for _, attrName := range sortedKeys(nested.Block.Attributes) {
+			attr := nested.Block.Attributes[attrName]
+			if !attr.Configurable() {
+				continue
+			}
+			attrValues := make([]any, len(objs))
+			for i, obj := range objs {
+				attrValues[i] = obj[attrName]
+			}
+			if err := g.setAttribute(block, attrName, varPrefix+attrName, blockPath+"."+attrName, attrValues, attr, false); err != nil {
+				return fmt.Errorf("attribute %s: %w", attrName, err)
+			}
+		}
+		for _, blockName := range sortedKeys(nested.Block.BlockTypes) {
+			blockValues := make([]any, len(objs))
+			for i, obj := range objs {
+				blockValues[i] = obj[blockName]
+			}
+			if err := g.appendNestedBlocks(block, blockName, blockPath+".", nested.Block.BlockTypes[blockName], blockValues, resources); err != nil {
+				return fmt.Errorf("block %s: %w", blockName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// versionsFile renders the required_providers block for a provider address
+// such as "registry.terraform.io/hashicorp/azurerm".
+func versionsFile(providerName string) []byte {
+	parts := strings.Split(providerName, "/")
+	source := providerName
+	if len(parts) == 3 {
+		source = parts[1] + "/" + parts[2]
+	}
+	localName := parts[len(parts)-1]
+
+	f := hclwrite.NewEmptyFile()
+	terraform := f.Body().AppendNewBlock("terraform", nil).Body()
+	providers := terraform.AppendNewBlock("required_providers", nil).Body()
+	providers.SetAttributeValue(localName, cty.ObjectVal(map[string]cty.Value{
+		"source": cty.StringVal(source),
+	}))
+	return hclwrite.Format(f.Bytes())
+}
+
+// typeTokens renders a variable type constraint for a value type.
+func typeTokens(t cty.Type) hclwrite.Tokens {
+	switch {
+	case t == cty.String:
+		return hclwrite.TokensForIdentifier("string")
+	case t == cty.Number:
+		return hclwrite.TokensForIdentifier("number")
+	case t == cty.Bool:
+		return hclwrite.TokensForIdentifier("bool")
+	case t.IsTupleType():
+		if elem, ok := uniformPrimitive(t.TupleElementTypes()); ok {
+			return hclwrite.TokensForFunctionCall("list", hclwrite.TokensForIdentifier(elem))
+		}
+	case t.IsObjectType():
+		attrTypes := make([]cty.Type, 0, len(t.AttributeTypes()))
+		for _, at := range t.AttributeTypes() {
+			attrTypes = append(attrTypes, at)
+		}
+		if elem, ok := uniformPrimitive(attrTypes); ok {
+			return hclwrite.TokensForFunctionCall("map", hclwrite.TokensForIdentifier(elem))
+		}
+	}
+	return hclwrite.TokensForIdentifier("any")
+}
+
+// uniformPrimitive returns the name of the primitive type shared by all types.
+func uniformPrimitive(types []cty.Type) (string, bool) {
+	if len(types) == 0 {
+		return "", false
+	}
+	for _, t := range types[1:] {
+		if !t.Equals(types[0]) {
+			return "", false
+		}
+	}
+	switch types[0] {
+	case cty.String:
+		return "string", true
+	case cty.Number:
+		return "number", true
+	case cty.Bool:
+		return "bool", true
+	}
+	return "", false
+}
+
+// toCty converts a JSON-decoded value to a cty value.
+func toCty(v any) (cty.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	t, err := ctyjson.ImpliedType(data)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return ctyjson.Unmarshal(data, t)
+}
+
+// allEqual reports whether all values are deeply equal.
+func allEqual(values []any) bool {
+	for _, v := range values[1:] {
+		if !reflect.DeepEqual(v, values[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmpty reports whether v is an empty string, list, or map.
+func isEmpty(v any) bool {
+	switch val := v.(type) {
+	case string:
+		return val == ""
+	case []any:
+		return len(val) == 0
+	case map[string]any:
+		return len(val) == 0
+	}
+	return false
+}
+
+// firstNonNil returns the first non-nil value.
+func firstNonNil(values []any) any {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,419 @@
+package scaffold
+
+import (
+	"strings"
+	"testing"
+)
+
+const testState = `{
+  "format_version": "1.0",
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_s3_bucket.this[\"logs\"]",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "this",
+          "provider_name": "registry.terraform.io/hashicorp/aws",
+          "values": {
+            "id": "acme-logs",
+            "arn": "arn:aws:s3:::acme-logs",
+            "bucket": "acme-logs",
+            "force_destroy": false,
+            "object_lock_enabled": false,
+            "tags": {"team": "platform"},
+            "tags_all": {"team": "platform"},
+            "bucket_prefix": "",
+            "versioning": [{"enabled": true, "mfa_delete": false}]
+          }
+        },
+        {
+          "address": "aws_s3_bucket.this[\"assets\"]",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "this",
+          "provider_name": "registry.terraform.io/hashicorp/aws",
+          "values": {
+            "id": "acme-assets",
+            "arn": "arn:aws:s3:::acme-assets",
+            "bucket": "acme-assets",
+            "force_destroy": false,
+            "object_lock_enabled": false,
+            "tags": {"team": "platform"},
+            "tags_all": {"team": "platform"},
+            "bucket_prefix": "",
+            "versioning": [{"enabled": false, "mfa_delete": false}]
+          }
+        },
+        {
+          "address": "data.aws_caller_identity.current",
+          "mode": "data",
+          "type": "aws_caller_identity",
+          "name": "current",
+          "provider_name": "registry.terraform.io/hashicorp/aws",
+          "values": {"account_id": "123456789012"}
+        }
+      ],
+      "child_modules": [
+        {
+          "resources": [
+            {
+              "address": "module.db.aws_db_instance.main",
+              "mode": "managed",
+              "type": "aws_db_instance",
+              "name": "main",
+              "provider_name": "registry.terraform.io/hashicorp/aws",
+              "values": {"id": "db-1", "identifier": "main", "password": "hunter2"}
+            }
+          ]
+        }
+      ]
+    }
+  }
+}`
+
+const testSchemas = `{
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/aws": {
+      "resource_schemas": {
+        "aws_s3_bucket": {
+          "block": {
+            "attributes": {
+              "id": {"type": "string", "optional": true, "computed": true},
+              "arn": {"type": "string", "computed": true},
+              "bucket": {"type": "string", "optional": true, "computed": true},
+              "bucket_prefix": {"type": "string", "optional": true},
+              "force_destroy": {"type": "bool", "optional": true},
+              "object_lock_enabled": {"type": "bool", "optional": true, "computed": true},
+              "tags": {"type": ["map", "string"], "optional": true},
+              "tags_all": {"type": ["map", "string"], "computed": true}
+            },
+            "block_types": {
+              "versioning": {
+                "nesting_mode": "list",
+                "block": {
+                  "attributes": {
+                    "enabled": {"type": "bool", "optional": true},
+                    "mfa_delete": {"type": "bool", "optional": true}
+                  }
+                }
+              }
+            }
+          }
+        },
+        "aws_db_instance": {
+          "block": {
+            "attributes": {
+              "id": {"type": "string", "computed": true},
+              "identifier": {"type": "string", "optional": true},
+              "password": {"type": "string", "optional": true, "sensitive": true}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func parseFixtures(t *testing.T) (*State, *ProviderSchemas) {
+	t.Helper()
+	state, err := ParseState([]byte(testState))
+	if err != nil {
+		t.Fatalf("ParseState returned error: %v", err)
+	}
+	schemas, err := ParseProviderSchemas([]byte(testSchemas))
+	if err != nil {
+		t.Fatalf("ParseProviderSchemas returned error: %v", err)
+	}
+	return state, schemas
+}
+
+func TestState_FindResources(t *testing.T) {
+	state, _ := parseFixtures(t)
+
+	tests := []struct {
+		addr string
+		want int
+	}{
+		{"aws_s3_bucket.this", 2},
+		{`aws_s3_bucket.this["logs"]`, 1},
+		{"module.db.aws_db_instance.main", 1},
+		{"data.aws_caller_identity.current", 0}, // data sources are not managed
+		{"aws_s3_bucket.other", 0},
+	}
+
+	for _, tt := range tests {
+		if got := len(state.FindResources(tt.addr)); got != tt.want {
+			t.Errorf("FindResources(%s) returned %d resources, want %d", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestFromState_DifferingAttributesBecomeVariables(t *testing.T) {
+	state, schemas := parseFixtures(t)
+
+	component, err := FromState(Options{
+		Resources: state.FindResources("aws_s3_bucket.this"),
+		Schemas:   schemas,
+	})
+	if err != nil {
+		t.Fatalf("FromState returned error: %v", err)
+	}
+
+	main := string(component.Files["main.tf"])
+	for _, want := range []string{
+		`resource "aws_s3_bucket" "this" {`,
+		"bucket              = var.bucket",
+		"force_destroy       = false",
+		`team = "platform"`,
+		"versioning {",
+		"enabled    = var.versioning_enabled", // differs between the instances
+		"mfa_delete = false",
+	} {
+		if !strings.Contains(main, want) {
+			t.Errorf("main.tf missing %q:\n%s", want, main)
+		}
+	}
+	// Computed-only and empty attributes are skipped
+	for _, unwanted := range []string{"tags_all", "arn", "bucket_prefix"} {
+		if strings.Contains(main, unwanted) {
+			t.Errorf("main.tf should not contain %q:\n%s", unwanted, main)
+		}
+	}
+
+	if strings.Join(component.Variables, ",") != "bucket,versioning_enabled" {
+		t.Errorf("expected 'bucket' and 'versioning_enabled' to become variables, got %v", component.Variables)
+	}
+	variables := string(component.Files["variables.tf"])
+	if !strings.Contains(variables, `variable "bucket" {`) || !strings.Contains(variables, "type        = string") {
+		t.Errorf("unexpected variables.tf:\n%s", variables)
+	}
+	if !strings.Contains(variables, `variable "versioning_enabled" {`) || !strings.Contains(variables, `"Value of the versioning.enabled argument"`) {
+		t.Errorf("expected a variable for the nested attribute:\n%s", variables)
+	}
+	if strings.Contains(variables, "default") {
+		t.Errorf("differing attributes should not have a default:\n%s", variables)
+	}
+
+	outputs := string(component.Files["outputs.tf"])
+	if !strings.Contains(outputs, "value       = aws_s3_bucket.this.id") || !strings.Contains(outputs, `output "arn" {`) {
+		t.Errorf("unexpected outputs.tf:\n%s", outputs)
+	}
+
+	versions := string(component.Files["versions.tf"])
+	if !strings.Contains(versions, `source = "hashicorp/aws"`) {
+		t.Errorf("unexpected versions.tf:\n%s", versions)
+	}
+}
+
+func TestFromState_ForcedAndSensitiveVariables(t *testing.T) {
+	state, schemas := parseFixtures(t)
+
+	component, err := FromState(Options{
+		Resources: state.FindResources("module.db.aws_db_instance.main"),
+		Schemas:   schemas,
+		Variables: []string{"identifier"},
+	})
+	if err != nil {
+		t.Fatalf("FromState returned error: %v", err)
+	}
+
+	variables := string(component.Files["variables.tf"])
+	if !strings.Contains(variables, `default     = "main"`) {
+		t.Errorf("forced variable should default to the current value:\n%s", variables)
+	}
+	if !strings.Contains(variables, "sensitive   = true") {
+		t.Errorf("sensitive attribute should be a sensitive variable:\n%s", variables)
+	}
+	if strings.Contains(variables, "hunter2") || strings.Contains(string(component.Files["main.tf"]), "hunter2") {
+		t.Error("sensitive values must not be written to the component")
+	}
+}
+
+// clusterSchemas has a resource type with a sensitive attribute in a nested block.
+const clusterSchemas = `{
+  "provider_schemas": {
+    "registry.terraform.io/hashicorp/azurerm": {
+      "resource_schemas": {
+        "azurerm_kubernetes_cluster": {
+          "block": {
+            "attributes": {
+              "name": {"type": "string", "required": true}
+            },
+            "block_types": {
+              "service_principal": {
+                "nesting_mode": "list",
+                "block": {
+                  "attributes": {
+                    "client_id": {"type": "string", "required": true},
+                    "client_secret": {"type": "string", "required": true, "sensitive": true}
+                  }
+                }
+              },
+              "node_pool": {
+                "nesting_mode": "list",
+                "block": {
+                  "attributes": {
+                    "vm_size": {"type": "string", "required": true}
+                  }
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// cluster returns a cluster resource instance with the given values.
+func cluster(address string, values map[string]any) StateResource {
+	return StateResource{
+		Address:      address,
+		Mode:         "managed",
+		Type:         "azurerm_kubernetes_cluster",
+		Name:         "this",
+		ProviderName: "registry.terraform.io/hashicorp/azurerm",
+		Values:       values,
+	}
+}
+
+func TestFromState_NestedSensitiveAttributes(t *testing.T) {
+	schemas, err := ParseProviderSchemas([]byte(clusterSchemas))
+	if err != nil {
+		t.Fatalf("ParseProviderSchemas returned error: %v", err)
+	}
+
+	component, err := FromState(Options{
+		Resources: []StateResource{cluster("azurerm_kubernetes_cluster.this", map[string]any{
+			"name":              "aks",
+			"service_principal": []any{map[string]any{"client_id": "app-1", "client_secret": "s3cret"}},
+			"node_pool":         []any{map[string]any{"vm_size": "Standard_D2s_v3"}, map[string]any{"vm_size": "Standard_D4s_v3"}},
+		})},
+		Schemas: schemas,
+	})
+	if err != nil {
+		t.Fatalf("FromState returned error: %v", err)
+	}
+
+	main := string(component.Files["main.tf"])
+	variables := string(component.Files["variables.tf"])
+	if strings.Contains(main, "s3cret") || strings.Contains(variables, "s3cret") {
+		t.Errorf("sensitive nested values must not be written to the component:\n%s\n%s", main, variables)
+	}
+	for _, want := range []string{
+		`client_id     = "app-1"`,
+		"client_secret = var.service_principal_client_secret",
+		`vm_size = "Standard_D4s_v3"`,
+	} {
+		if !strings.Contains(main, want) {
+			t.Errorf("main.tf missing %q:\n%s", want, main)
+		}
+	}
+	if !strings.Contains(variables, `variable "service_principal_client_secret" {`) || !strings.Contains(variables, "sensitive   = true") {
+		t.Errorf("sensitive nested attribute should be a sensitive variable:\n%s", variables)
+	}
+	if strings.Join(component.Variables, ",") != "service_principal_client_secret" {
+		t.Errorf("expected only the client secret to become a variable, got %v", component.Variables)
+	}
+}
+
+func TestFromState_NestedBlocksDiffer(t *testing.T) {
+	schemas, err := ParseProviderSchemas([]byte(clusterSchemas))
+	if err != nil {
+		t.Fatalf("ParseProviderSchemas returned error: %v", err)
+	}
+	resources := []StateResource{
+		cluster(`azurerm_kubernetes_cluster.this["a"]`, map[string]any{
+			"name":      "a",
+			"node_pool": []any{map[string]any{"vm_size": "Standard_D2s_v3"}, map[string]any{"vm_size": "Standard_D4s_v3"}},
+		}),
+		cluster(`azurerm_kubernetes_cluster.this["b"]`, map[string]any{
+			"name":      "b",
+			"node_pool": []any{map[string]any{"vm_size": "Standard_D2s_v3"}, map[string]any{"vm_size": "Standard_D8s_v3"}},
+		}),
+	}
+
+	component, err := FromState(Options{Resources: resources, Schemas: schemas})
+	if err != nil {
+		t.Fatalf("FromState returned error: %v", err)
+	}
+	main := string(component.Files["main.tf"])
+	if !strings.Contains(main, `vm_size = "Standard_D2s_v3"`) || !strings.Contains(main, "vm_size = var.node_pool_1_vm_size") {
+		t.Errorf("expected the differing block attribute to become a variable:\n%s", main)
+	}
+	if strings.Join(component.Variables, ",") != "name,node_pool_1_vm_size" {
+		t.Errorf("unexpected variables %v", component.Variables)
+	}
+
+	// Blocks can't be matched when the instances have a different number of them
+	resources[1].Values["node_pool"] = []any{map[string]any{"vm_size": "Standard_D2s_v3"}}
+	_, err = FromState(Options{Resources: resources, Schemas: schemas})
+	if err == nil || !strings.Contains(err.Error(), `block node_pool: azurerm_kubernetes_cluster.this["a"] has 2 block(s) but azurerm_kubernetes_cluster.this["b"] has 1`) {
+		t.Errorf("expected an error for differing blocks, got %v", err)
+	}
+}
+
+func TestFromState_Errors(t *testing.T) {
+	state, schemas := parseFixtures(t)
+	buckets := state.FindResources("aws_s3_bucket.this")
+	db := state.FindResources("module.db.aws_db_instance.main")
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{
+		{"no resources", Options{Schemas: schemas}, "no resources"},
+		{"mixed types", Options{Resources: append(buckets, db...), Schemas: schemas}, "same type"},
+		{"no schemas", Options{Resources: buckets}, "provider schemas are required"},
+		{"computed var", Options{Resources: buckets, Schemas: schemas, Variables: []string{"arn"}}, "not a configurable attribute"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromState(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFromState_JSON(t *testing.T) {
+	state, schemas := parseFixtures(t)
+
+	component, err := FromState(Options{
+		Resources: state.FindResources("aws_s3_bucket.this"),
+		Schemas:   schemas,
+		JSON:      true,
+	})
+	if err != nil {
+		t.Fatalf("FromState returned error: %v", err)
+	}
+
+	for _, name := range []string{"main.tf.json", "variables.tf.json", "outputs.tf.json", "versions.tf.json"} {
+		if _, ok := component.Files[name]; !ok {
+			t.Errorf("expected %s in %v", name, component.Files)
+		}
+	}
+	if _, ok := component.Files["main.tf"]; ok {
+		t.Error("expected no .tf files in JSON syntax")
+	}
+
+	main := string(component.Files["main.tf.json"])
+	for _, want := range []string{`"aws_s3_bucket": {`, `"this": {`, `"bucket": "${var.bucket}"`, `"force_destroy": false`, `"versioning": {`} {
+		if !strings.Contains(main, want) {
+			t.Errorf("main.tf.json missing %s:\n%s", want, main)
+		}
+	}
+	variables := string(component.Files["variables.tf.json"])
+	if !strings.Contains(variables, `"type": "string"`) {
+		t.Errorf("variables.tf.json should have the type as a string:\n%s", variables)
+	}
+	versions := string(component.Files["versions.tf.json"])
+	if !strings.Contains(versions, `"source": "hashicorp/aws"`) {
+		t.Errorf("versions.tf.json missing the provider source:\n%s", versions)
+	}
+}
@@ -0,0 +1,111 @@
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// ToJSON converts generated native syntax to Terraform's JSON syntax.
+// Literal values are written as JSON values, other expressions as "${...}"
+// templates, and variable type constraints as strings.
+func ToJSON(src []byte) ([]byte, error) {
+	f, diags := hclsyntax.ParseConfig(src, "generated.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse generated configuration: %w", diags)
+	}
+	obj, err := bodyToJSON(f.Body.(*hclsyntax.Body), src, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(obj); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// bodyToJSON converts the attributes and nested blocks of a body of blockType.
+// Labeled blocks are nested by label; unlabeled blocks that occur more than
+// once become an array.
+func bodyToJSON(body *hclsyntax.Body, src []byte, blockType string) (map[string]any, error) {
+	obj := make(map[string]any)
+	for name, attr := range body.Attributes {
+		value, err := exprToJSON(attr.Expr, src, blockType == "variable" && name == "type")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		obj[name] = value
+	}
+
+	for _, block := range body.Blocks {
+		nested, err := bodyToJSON(block.Body, src, block.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", block.Type, err)
+		}
+		if len(block.Labels) == 0 {
+			switch existing := obj[block.Type].(type) {
+			case nil:
+				obj[block.Type] = nested
+			case []any:
+				obj[block.Type] = append(existing, nested)
+			default:
+				obj[block.Type] = []any{existing, nested}
+			}
+			continue
+		}
+
+		parent, ok := obj[block.Type].(map[string]any)
+		if !ok {
+			parent = make(map[string]any)
+			obj[block.Type] = parent
+		}
+		for _, label := range block.Labels[:len(block.Labels)-1] {
+			child, ok := parent[label].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				parent[label] = child
+			}
+			parent = child
+		}
+		parent[block.Labels[len(block.Labels)-1]] = nested
+	}
+	return obj, nil
+}
+
+// exprToJSON converts an expression to its JSON value. Literal strings are
+// escaped so they aren't evaluated as templates.
+func exprToJSON(expr hclsyntax.Expression, src []byte, typeConstraint bool) (any, error) {
+	source := strings.TrimSpace(string(expr.Range().SliceBytes(src)))
+	if typeConstraint {
+		return source, nil
+	}
+
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return "${" + source + "}", nil
+	}
+	data, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.ReplaceAll(data, []byte("${"), []byte("$${"))
+	data = bytes.ReplaceAll(data, []byte("%{"), []byte("%%{"))
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
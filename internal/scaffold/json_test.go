@@ -0,0 +1,42 @@
+package scaffold
+
+import "testing"
+
+func TestToJSON(t *testing.T) {
+	src := []byte(`resource "null_resource" "this" {
+  triggers = { template = "$${keep}" }
+  count    = length(var.names)
+}
+
+variable "names" {
+  type    = list(string)
+  default = []
+}
+`)
+	got, err := ToJSON(src)
+	if err != nil {
+		t.Fatalf("ToJSON returned error: %v", err)
+	}
+	want := `{
+  "resource": {
+    "null_resource": {
+      "this": {
+        "count": "${length(var.names)}",
+        "triggers": {
+          "template": "$${keep}"
+        }
+      }
+    }
+  },
+  "variable": {
+    "names": {
+      "default": [],
+      "type": "list(string)"
+    }
+  }
+}
+`
+	if string(got) != want {
+		t.Errorf("ToJSON() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Names of the files in a template directory that override the go.mod and
+// test file Terratest generates
+const (
+	GoModTemplateFile = "go.mod.tmpl"
+	TestTemplateFile  = "test.go.tmpl"
+)
+
+// TerratestData is the data the Terratest templates are rendered with.
+type TerratestData struct {
+	Module   string // Module directory name, e.g. storage-account
+	Path     string // Slash-separated module path relative to the root, e.g. components/storage-account
+	Example  string // Example the test targets, e.g. basic
+	GoModule string // Module path of tests/go.mod, e.g. components/storage-account/tests
+	Func     string // Name of the test function, e.g. TestStorageAccount
+}
+
+// goModTemplate is the default tests/go.mod
+const goModTemplate = `module {{.GoModule}}
+
+go 1.23
+
+require github.com/gruntwork-io/terratest v0.48.2
+`
+
+// testTemplate is the default tests/<module>_test.go
+const testTemplate = `package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// {{.Func}} initializes and validates examples/{{.Example}} of {{.Path}}, or the
+// example 'motf test --example' selects. Uncomment the apply and destroy calls to
+// deploy it for real.
+func {{.Func}}(t *testing.T) {
+	t.Parallel()
+
+	exampleDir := os.Getenv("MOTF_EXAMPLE_DIR")
+	if exampleDir == "" {
+		exampleDir = filepath.Join("..", "examples", "{{.Example}}")
+	}
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: exampleDir,
+	})
+
+	// defer terraform.Destroy(t, terraformOptions)
+	// terraform.InitAndApply(t, terraformOptions)
+	terraform.InitAndValidate(t, terraformOptions)
+}
+`
+
+// NewTerratestData returns the template data of the module at the
+// slash-separated path relative to the root, testing example.
+func NewTerratestData(path, example string) TerratestData {
+	module := path[strings.LastIndex(path, "/")+1:]
+	return TerratestData{
+		Module:   module,
+		Path:     path,
+		Example:  example,
+		GoModule: path + "/tests",
+		Func:     "Test" + goName(module),
+	}
+}
+
+// Terratest generates the files of a Terratest suite for a module: go.mod
+// and <module>_test.go, keyed by their name in the module's tests directory.
+// Templates in templateDir, if set, replace the default ones.
+func Terratest(data TerratestData, templateDir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for name, tmpl := range map[string]struct{ file, text string }{
+		"go.mod": {GoModTemplateFile, goModTemplate},
+		strings.ReplaceAll(data.Module, "-", "_") + "_test.go": {TestTemplateFile, testTemplate},
+	} {
+		text := tmpl.text
+		if templateDir != "" {
+			override, err := os.ReadFile(filepath.Join(templateDir, tmpl.file))
+			if err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			if err == nil {
+				text = string(override)
+			}
+		}
+		t, err := template.New(tmpl.file).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", tmpl.file, err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", tmpl.file, err)
+		}
+		files[name] = buf.Bytes()
+	}
+	return files, nil
+}
+
+// goName returns name as an exported Go identifier, e.g. "storage-account" -> "StorageAccount".
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
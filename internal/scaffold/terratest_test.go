@@ -0,0 +1,100 @@
+package scaffold
+
+import (
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewTerratestData(t *testing.T) {
+	got := NewTerratestData("components/azurerm/storage-account", "complete")
+	want := TerratestData{
+		Module:   "storage-account",
+		Path:     "components/azurerm/storage-account",
+		Example:  "complete",
+		GoModule: "components/azurerm/storage-account/tests",
+		Func:     "TestStorageAccount",
+	}
+	if got != want {
+		t.Errorf("NewTerratestData() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTerratest(t *testing.T) {
+	files, err := Terratest(NewTerratestData("components/storage-account", "basic"), "")
+	if err != nil {
+		t.Fatalf("Terratest() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected go.mod and a test file, got %d file(s)", len(files))
+	}
+
+	goMod := string(files["go.mod"])
+	if !strings.HasPrefix(goMod, "module components/storage-account/tests\n") || !strings.Contains(goMod, "github.com/gruntwork-io/terratest") {
+		t.Errorf("unexpected go.mod:\n%s", goMod)
+	}
+
+	test, ok := files["storage_account_test.go"]
+	if !ok {
+		t.Fatalf("expected storage_account_test.go, got %v", files)
+	}
+	formatted, err := format.Source(test)
+	if err != nil {
+		t.Fatalf("test file isn't valid Go: %v\n%s", err, test)
+	}
+	if string(formatted) != string(test) {
+		t.Errorf("test file isn't gofmt'ed:\n%s", test)
+	}
+	for _, want := range []string{
+		"func TestStorageAccount(t *testing.T) {",
+		`os.Getenv("MOTF_EXAMPLE_DIR")`,
+		`filepath.Join("..", "examples", "basic")`,
+		"terraform.InitAndValidate(t, terraformOptions)",
+	} {
+		if !strings.Contains(string(test), want) {
+			t.Errorf("test file doesn't contain %q:\n%s", want, test)
+		}
+	}
+}
+
+func TestTerratest_TemplateOverride(t *testing.T) {
+	dir := t.TempDir()
+	override := "package test\n\n// {{.Func}} tests {{.Path}} with examples/{{.Example}}\n"
+	if err := os.WriteFile(filepath.Join(dir, TestTemplateFile), []byte(override), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Terratest(NewTerratestData("bases/network", "basic"), dir)
+	if err != nil {
+		t.Fatalf("Terratest() error = %v", err)
+	}
+	if got, want := string(files["network_test.go"]), "package test\n\n// TestNetwork tests bases/network with examples/basic\n"; got != want {
+		t.Errorf("test file = %q, want %q", got, want)
+	}
+	// Without a go.mod.tmpl, the default go.mod is generated
+	if !strings.HasPrefix(string(files["go.mod"]), "module bases/network/tests\n") {
+		t.Errorf("expected the default go.mod, got:\n%s", files["go.mod"])
+	}
+}
+
+func TestTerratest_InvalidTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"module {{.GoModule", "invalid template go.mod.tmpl"},
+		{"module {{.Package}}\n", "failed to render go.mod.tmpl"},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, GoModTemplateFile), []byte(tt.template), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Terratest(NewTerratestData("components/vnet", "basic"), dir)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: expected error containing %q, got %v", tt.template, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package security
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// parseTrivy parses the output of 'trivy config --format json'.
+func parseTrivy(data []byte) ([]Finding, error) {
+	var report struct {
+		Results []struct {
+			Target            string `json:"Target"`
+			Misconfigurations []struct {
+				ID            string `json:"ID"`
+				Title         string `json:"Title"`
+				Severity      string `json:"Severity"`
+				Status        string `json:"Status"`
+				CauseMetadata struct {
+					Resource  string `json:"Resource"`
+					StartLine int    `json:"StartLine"`
+				} `json:"CauseMetadata"`
+			} `json:"Misconfigurations"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, result := range report.Results {
+		for _, m := range result.Misconfigurations {
+			if m.Status == "PASS" {
+				continue
+			}
+			findings = append(findings, Finding{
+				RuleID:   m.ID,
+				Severity: severityFromScanner(m.Severity),
+				Title:    m.Title,
+				Resource: m.CauseMetadata.Resource,
+				File:     result.Target,
+				Line:     m.CauseMetadata.StartLine,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// parseTfsec parses the output of 'tfsec --format json'.
+func parseTfsec(data []byte) ([]Finding, error) {
+	var report struct {
+		Results []struct {
+			RuleID      string `json:"rule_id"`
+			LongID      string `json:"long_id"`
+			Description string `json:"description"`
+			Severity    string `json:"severity"`
+			Resource    string `json:"resource"`
+			Location    struct {
+				Filename  string `json:"filename"`
+				StartLine int    `json:"start_line"`
+			} `json:"location"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+
+	findings := make([]Finding, 0, len(report.Results))
+	for _, r := range report.Results {
+		ruleID := r.LongID
+		if ruleID == "" {
+			ruleID = r.RuleID
+		}
+		findings = append(findings, Finding{
+			RuleID:   ruleID,
+			Severity: severityFromScanner(r.Severity),
+			Title:    r.Description,
+			Resource: r.Resource,
+			File:     r.Location.Filename,
+			Line:     r.Location.StartLine,
+		})
+	}
+	return findings, nil
+}
+
+// checkovReport is one framework report in checkov's JSON output
+type checkovReport struct {
+	Results struct {
+		FailedChecks []struct {
+			CheckID       string  `json:"check_id"`
+			CheckName     string  `json:"check_name"`
+			Severity      *string `json:"severity"`
+			Resource      string  `json:"resource"`
+			FilePath      string  `json:"file_path"`
+			FileLineRange []int   `json:"file_line_range"`
+		} `json:"failed_checks"`
+	} `json:"results"`
+}
+
+// parseCheckov parses the output of 'checkov -o json', which is a single
+// report object, or a list of reports when several frameworks ran.
+func parseCheckov(data []byte) ([]Finding, error) {
+	var reports []checkovReport
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &reports); err != nil {
+			return nil, err
+		}
+	} else {
+		var report checkovReport
+		if err := json.Unmarshal(trimmed, &report); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	var findings []Finding
+	for _, report := range reports {
+		for _, c := range report.Results.FailedChecks {
+			f := Finding{
+				RuleID:   c.CheckID,
+				Title:    c.CheckName,
+				Resource: c.Resource,
+				// checkov reports paths relative to the scanned directory with a leading slash
+				File: strings.TrimPrefix(c.FilePath, "/"),
+			}
+			if c.Severity != nil {
+				f.Severity = severityFromScanner(*c.Severity)
+			}
+			if len(c.FileLineRange) > 0 {
+				f.Line = c.FileLineRange[0]
+			}
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
@@ -0,0 +1,247 @@
+// Package security runs infrastructure-as-code security scanners (trivy, tfsec,
+// checkov) on modules and normalizes their findings.
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/procgroup"
+)
+
+// Severity is the normalized severity of a finding
+type Severity int
+
+// Severity levels, from lowest to highest
+const (
+	SeverityUnknown Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"unknown", "low", "medium", "high", "critical"}
+
+// String returns the lowercase severity name.
+func (s Severity) String() string {
+	if s < SeverityUnknown || int(s) >= len(severityNames) {
+		return severityNames[SeverityUnknown]
+	}
+	return severityNames[s]
+}
+
+// MarshalJSON encodes the severity as its name.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes a severity name; unrecognized names become unknown.
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	*s = severityFromScanner(name)
+	return nil
+}
+
+// ParseSeverity parses a severity name (case-insensitive) for filtering.
+func ParseSeverity(name string) (Severity, error) {
+	for i, n := range severityNames {
+		if i > 0 && strings.EqualFold(name, n) {
+			return Severity(i), nil
+		}
+	}
+	return SeverityUnknown, fmt.Errorf("invalid severity '%s': must be low, medium, high, or critical", name)
+}
+
+// severityFromScanner maps a scanner's severity value to a Severity.
+// Values that don't match a known level (including empty) are unknown.
+func severityFromScanner(name string) Severity {
+	for i, n := range severityNames {
+		if strings.EqualFold(name, n) {
+			return Severity(i)
+		}
+	}
+	return SeverityUnknown
+}
+
+// Finding is a single security issue reported by a scanner.
+type Finding struct {
+	Module   string   `json:"module"`
+	Scanner  string   `json:"scanner"`
+	RuleID   string   `json:"rule_id"`
+	Severity Severity `json:"severity"`
+	Title    string   `json:"title"`
+	Resource string   `json:"resource,omitempty"`
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+}
+
+// Location returns "file:line", "file", or "" for display.
+func (f Finding) Location() string {
+	if f.File == "" {
+		return ""
+	}
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// Filter returns the findings at or above min. Findings of unknown severity
+// are always kept, since some scanners (e.g. checkov without a platform API key)
+// don't report severities and hiding them would hide every finding.
+func Filter(findings []Finding, min Severity) []Finding {
+	var kept []Finding
+	for _, f := range findings {
+		if f.Severity == SeverityUnknown || f.Severity >= min {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// Sort orders findings by severity (highest first), then module, file, and line.
+func Sort(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Module != b.Module {
+			return a.Module < b.Module
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+}
+
+// Scanner describes how to run a scanner and parse its JSON output.
+type Scanner struct {
+	Name  string                               // Scanner name, also the binary name
+	Args  []string                             // Arguments to scan the working directory with JSON output and exit code 0 on findings
+	Parse func(data []byte) ([]Finding, error) // Parses the JSON output
+}
+
+// scanners is the registry of supported scanner backends
+var scanners = map[string]*Scanner{
+	"trivy": {
+		Name:  "trivy",
+		Args:  []string{"config", "--format", "json", "--quiet", "."},
+		Parse: parseTrivy,
+	},
+	"tfsec": {
+		Name:  "tfsec",
+		Args:  []string{".", "--format", "json", "--no-colour", "--soft-fail"},
+		Parse: parseTfsec,
+	},
+	"checkov": {
+		Name:  "checkov",
+		Args:  []string{"-d", ".", "--framework", "terraform", "-o", "json", "--quiet", "--soft-fail"},
+		Parse: parseCheckov,
+	},
+}
+
+// DefaultScanner is used when no scanner is configured
+const DefaultScanner = "trivy"
+
+// Names returns the supported scanner names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(scanners))
+	for name := range scanners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the scanner with the given name.
+func Lookup(name string) (*Scanner, error) {
+	s, ok := scanners[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown security scanner '%s', supported: %s", name, strings.Join(Names(), ", "))
+	}
+	return s, nil
+}
+
+// Runner runs a scanner on module directories.
+type Runner struct {
+	Scanner   *Scanner
+	ExtraArgs []string // Appended to the scanner arguments
+	Env       []string // Extra KEY=VALUE pairs added to the environment
+	DryRun    bool     // Print the command instead of running it
+
+	// Context stops the scanner when done, e.g. at the module's timeout; nil
+	// means never. Like terraform commands, it runs in its own process group.
+	Context context.Context
+}
+
+// Scan runs the scanner on dir and returns its findings, with file paths
+// relative to dir. The scanner's stderr is streamed to stderr.
+func (r *Runner) Scan(dir string, stdout, stderr io.Writer) ([]Finding, error) {
+	args := append(append([]string(nil), r.Scanner.Args...), r.ExtraArgs...)
+
+	if r.DryRun {
+		_, _ = fmt.Fprintf(stdout, "[dry-run] Would run %s %s in %s\n", r.Scanner.Name, strings.Join(args, " "), dir)
+		return nil, nil
+	}
+
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%s stopped: %w", r.Scanner.Name, context.Cause(ctx))
+	}
+
+	_, _ = fmt.Fprintf(stdout, "Running %s in %s\n", r.Scanner.Name, dir)
+
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.Scanner.Name, args...) //nolint:gosec // scanner name comes from the fixed registry
+	cmd.Dir = dir
+	cmd.Stdout = &out
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(), r.Env...)
+
+	if err := procgroup.Run(cmd, procgroup.GracePeriod); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s stopped: %w", r.Scanner.Name, context.Cause(ctx))
+		}
+		return nil, fmt.Errorf("%s failed: %w", r.Scanner.Name, err)
+	}
+
+	findings, err := r.Scanner.Parse(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s output: %w", r.Scanner.Name, err)
+	}
+
+	for i := range findings {
+		findings[i].Scanner = r.Scanner.Name
+		findings[i].File = relativeTo(dir, findings[i].File)
+	}
+	return findings, nil
+}
+
+// relativeTo makes an absolute path inside dir relative to it.
+func relativeTo(dir, path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
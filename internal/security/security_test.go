@@ -0,0 +1,243 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const trivyOutput = `{
+  "Results": [
+    {
+      "Target": "main.tf",
+      "Misconfigurations": [
+        {"ID": "AVD-AWS-0086", "Title": "S3 Access block should block public ACL", "Severity": "HIGH", "Status": "FAIL",
+         "CauseMetadata": {"Resource": "aws_s3_bucket.this", "StartLine": 3}},
+        {"ID": "AVD-AWS-0089", "Title": "S3 Bucket logging", "Severity": "LOW", "Status": "PASS",
+         "CauseMetadata": {"Resource": "aws_s3_bucket.this", "StartLine": 3}}
+      ]
+    }
+  ]
+}`
+
+const tfsecOutput = `{
+  "results": [
+    {"rule_id": "AVD-AWS-0088", "long_id": "aws-s3-enable-bucket-encryption", "description": "Bucket does not have encryption enabled",
+     "severity": "HIGH", "resource": "aws_s3_bucket.this", "location": {"filename": "/repo/components/s3/main.tf", "start_line": 1}}
+  ]
+}`
+
+const checkovOutput = `[
+  {"check_type": "terraform", "results": {"failed_checks": [
+    {"check_id": "CKV_AWS_18", "check_name": "Ensure the S3 bucket has access logging enabled", "severity": null,
+     "resource": "aws_s3_bucket.this", "file_path": "/main.tf", "file_line_range": [1, 4]},
+    {"check_id": "CKV_AWS_145", "check_name": "Ensure that S3 buckets are encrypted with KMS", "severity": "MEDIUM",
+     "resource": "aws_s3_bucket.this", "file_path": "/main.tf", "file_line_range": [1, 4]}
+  ]}}
+]`
+
+func TestParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		scanner string
+		output  string
+		want    []Finding
+	}{
+		{
+			name:    "trivy skips passed checks",
+			scanner: "trivy",
+			output:  trivyOutput,
+			want: []Finding{
+				{RuleID: "AVD-AWS-0086", Severity: SeverityHigh, Title: "S3 Access block should block public ACL", Resource: "aws_s3_bucket.this", File: "main.tf", Line: 3},
+			},
+		},
+		{
+			name:    "tfsec uses long id",
+			scanner: "tfsec",
+			output:  tfsecOutput,
+			want: []Finding{
+				{RuleID: "aws-s3-enable-bucket-encryption", Severity: SeverityHigh, Title: "Bucket does not have encryption enabled", Resource: "aws_s3_bucket.this", File: "/repo/components/s3/main.tf", Line: 1},
+			},
+		},
+		{
+			name:    "checkov list with null severity",
+			scanner: "checkov",
+			output:  checkovOutput,
+			want: []Finding{
+				{RuleID: "CKV_AWS_18", Severity: SeverityUnknown, Title: "Ensure the S3 bucket has access logging enabled", Resource: "aws_s3_bucket.this", File: "main.tf", Line: 1},
+				{RuleID: "CKV_AWS_145", Severity: SeverityMedium, Title: "Ensure that S3 buckets are encrypted with KMS", Resource: "aws_s3_bucket.this", File: "main.tf", Line: 1},
+			},
+		},
+		{
+			name:    "checkov single report without findings",
+			scanner: "checkov",
+			output:  `{"check_type": "terraform", "results": {"failed_checks": []}}`,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanner, err := Lookup(tt.scanner)
+			if err != nil {
+				t.Fatalf("Lookup returned error: %v", err)
+			}
+			got, err := scanner.Parse([]byte(tt.output))
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d findings, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("finding %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParse_InvalidJSON(t *testing.T) {
+	for _, name := range Names() {
+		scanner, _ := Lookup(name)
+		if _, err := scanner.Parse([]byte("not json")); err == nil {
+			t.Errorf("%s: expected error for invalid JSON", name)
+		}
+	}
+}
+
+func TestLookup_Unknown(t *testing.T) {
+	if _, err := Lookup("snyk"); err == nil {
+		t.Error("expected error for unknown scanner")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Severity
+		wantErr bool
+	}{
+		{input: "low", want: SeverityLow},
+		{input: "HIGH", want: SeverityHigh},
+		{input: "Critical", want: SeverityCritical},
+		{input: "unknown", wantErr: true},
+		{input: "severe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSeverity(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSeverity(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSeverity(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterAndSort(t *testing.T) {
+	findings := []Finding{
+		{Module: "b", RuleID: "low", Severity: SeverityLow},
+		{Module: "b", RuleID: "unknown", Severity: SeverityUnknown},
+		{Module: "b", RuleID: "critical", Severity: SeverityCritical},
+		{Module: "a", RuleID: "high-a", Severity: SeverityHigh},
+		{Module: "b", RuleID: "high-b", Severity: SeverityHigh},
+	}
+
+	got := Filter(findings, SeverityHigh)
+	Sort(got)
+
+	want := []string{"critical", "high-a", "high-b", "unknown"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d findings, want %d: %+v", len(got), len(want), got)
+	}
+	for i, id := range want {
+		if got[i].RuleID != id {
+			t.Errorf("finding %d = %s, want %s", i, got[i].RuleID, id)
+		}
+	}
+}
+
+func TestSeverity_JSON(t *testing.T) {
+	data, err := json.Marshal(Finding{Severity: SeverityCritical})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	var f Finding
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if f.Severity != SeverityCritical {
+		t.Errorf("round trip severity = %v, want critical", f.Severity)
+	}
+}
+
+func TestRunner_Scan(t *testing.T) {
+	dir := t.TempDir()
+	// A stand-in scanner that prints tfsec-style output with an absolute path in dir
+	output := `{"results": [{"long_id": "rule", "severity": "LOW", "location": {"filename": "` + filepath.ToSlash(filepath.Join(dir, "main.tf")) + `", "start_line": 2}}]}`
+	runner := &Runner{
+		Scanner: &Scanner{Name: "sh", Args: []string{"-c", "echo '" + output + "'"}, Parse: parseTfsec},
+	}
+
+	var stdout, stderr bytes.Buffer
+	findings, err := runner.Scan(dir, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("Scan returned error: %v (stderr: %s)", err, stderr.String())
+	}
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %+v", findings)
+	}
+	if findings[0].Scanner != "sh" || findings[0].Location() != "main.tf:2" {
+		t.Errorf("unexpected finding: %+v", findings[0])
+	}
+}
+
+func TestRunner_ScanFailure(t *testing.T) {
+	runner := &Runner{Scanner: &Scanner{Name: "sh", Args: []string{"-c", "exit 2"}, Parse: parseTfsec}}
+
+	var stdout, stderr bytes.Buffer
+	if _, err := runner.Scan(t.TempDir(), &stdout, &stderr); err == nil {
+		t.Error("expected error when the scanner exits non-zero")
+	}
+}
+
+func TestRunner_ScanContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(100*time.Millisecond, func() { cancel(errors.New("module timed out after 100ms")) })
+	runner := &Runner{Scanner: &Scanner{Name: "sh", Args: []string{"-c", "sleep 10"}, Parse: parseTfsec}, Context: ctx}
+
+	start := time.Now()
+	var stdout, stderr bytes.Buffer
+	_, err := runner.Scan(t.TempDir(), &stdout, &stderr)
+	if err == nil || err.Error() != "sh stopped: module timed out after 100ms" {
+		t.Fatalf("expected the scanner to be stopped, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the scanner to be stopped, took %s", elapsed)
+	}
+}
+
+func TestRunner_DryRun(t *testing.T) {
+	scanner, _ := Lookup("trivy")
+	runner := &Runner{Scanner: scanner, ExtraArgs: []string{"--skip-dirs", "examples"}, DryRun: true}
+
+	var stdout, stderr bytes.Buffer
+	findings, err := runner.Scan("/repo/components/s3", &stdout, &stderr)
+	if err != nil || findings != nil {
+		t.Fatalf("expected no findings and no error in dry-run, got %v, %v", findings, err)
+	}
+	want := "[dry-run] Would run trivy config --format json --quiet . --skip-dirs examples in /repo/components/s3\n"
+	if stdout.String() != want {
+		t.Errorf("dry-run output = %q, want %q", stdout.String(), want)
+	}
+}
@@ -0,0 +1,259 @@
+// Package selfupdate finds the latest motf release on GitHub and replaces
+// the running executable with it, for `motf upgrade`.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/download"
+	version "github.com/hashicorp/go-version"
+)
+
+// Repository is the GitHub repository motf is released from
+const Repository = "TechnicallyJoe/terraform-motf"
+
+// DefaultAPI is the GitHub API releases are looked up with
+const DefaultAPI = "https://api.github.com"
+
+// EnvToken is a GitHub token for API requests, to avoid the rate limit of
+// anonymous requests
+const EnvToken = "GITHUB_TOKEN"
+
+// checksumsFile is the asset with the SHA-256 of the archives of a release
+const checksumsFile = "checksums.txt"
+
+// ErrUnknownVersion is returned when the running version isn't a release
+// version, e.g. for a development build.
+var ErrUnknownVersion = errors.New("not a release version")
+
+// Release is a motf release on GitHub.
+type Release struct {
+	Version string            // Without the v prefix, e.g. 1.4.0
+	Assets  map[string]string // Download URLs by file name
+}
+
+// Updater looks up and downloads motf releases.
+type Updater struct {
+	API        string
+	Repository string
+	Token      string // GitHub token, optional
+	HTTPClient *http.Client
+	OS, Arch   string // Platform of the binary, runtime.GOOS and runtime.GOARCH by default
+}
+
+// NewUpdater returns an updater for this platform, using $GITHUB_TOKEN if set.
+func NewUpdater() *Updater {
+	return &Updater{
+		API:        DefaultAPI,
+		Repository: Repository,
+		Token:      os.Getenv(EnvToken),
+		HTTPClient: &http.Client{Timeout: 5 * time.Minute},
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+// Latest returns the latest release. GitHub skips drafts and prereleases.
+func (u *Updater) Latest(ctx context.Context) (Release, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(u.API, "/"), u.Repository)
+	data, err := u.get(ctx, url, "application/vnd.github+json")
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to find the latest release: %w", err)
+	}
+	var latest struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(data, &latest); err != nil {
+		return Release{}, fmt.Errorf("failed to parse the latest release: %w", err)
+	}
+	release := Release{Version: strings.TrimPrefix(latest.TagName, "v"), Assets: make(map[string]string, len(latest.Assets))}
+	for _, asset := range latest.Assets {
+		release.Assets[asset.Name] = asset.URL
+	}
+	return release, nil
+}
+
+// Newer reports whether latest is newer than current. current may have a v
+// prefix; the error wraps ErrUnknownVersion when it isn't a version, e.g. dev.
+func Newer(current, latest string) (bool, error) {
+	cur, err := version.NewVersion(current)
+	if err != nil {
+		return false, fmt.Errorf("%w: '%s'", ErrUnknownVersion, current)
+	}
+	lat, err := version.NewVersion(latest)
+	if err != nil {
+		return false, fmt.Errorf("invalid release version '%s': %w", latest, err)
+	}
+	return lat.GreaterThan(cur), nil
+}
+
+// ArchiveName returns the name of the release archive of v for this
+// platform, as goreleaser names it.
+func (u *Updater) ArchiveName(v string) string {
+	ext := ".tar.gz"
+	if u.OS == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("motf_%s_%s_%s%s", v, u.OS, u.Arch, ext)
+}
+
+// Download returns the motf binary of release for this platform, verified
+// against the checksums of the release.
+func (u *Updater) Download(ctx context.Context, release Release) ([]byte, error) {
+	name := u.ArchiveName(release.Version)
+	archiveURL, ok := release.Assets[name]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no archive for %s/%s (%s)", release.Version, u.OS, u.Arch, name)
+	}
+	sumsURL, ok := release.Assets[checksumsFile]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s", release.Version, checksumsFile)
+	}
+	archive, err := u.get(ctx, archiveURL, "application/octet-stream")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	sums, err := u.get(ctx, sumsURL, "application/octet-stream")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", checksumsFile, err)
+	}
+	if err := download.Verify(archive, sums, name); err != nil {
+		return nil, err
+	}
+	if u.OS == "windows" {
+		return extractZip(archive, "motf.exe")
+	}
+	return extractTarGz(archive, "motf")
+}
+
+// get returns the body of a GET request to url. The token is only sent to
+// the API.
+func (u *Updater) get(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if u.Token != "" && u.isAPI(req.URL) {
+		req.Header.Set("Authorization", "Bearer "+u.Token)
+	}
+	resp, err := u.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// isAPI reports whether target is on the scheme and host of the API, so the
+// token never goes to another host, e.g. api.github.com.evil.example.
+func (u *Updater) isAPI(target *neturl.URL) bool {
+	api, err := neturl.Parse(u.API)
+	if err != nil {
+		return false
+	}
+	return target.Scheme == api.Scheme && strings.EqualFold(target.Host, api.Host)
+}
+
+// extractTarGz returns the file name at the top of a .tar.gz archive.
+func extractTarGz(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no %s in the archive", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && header.Name == name {
+			return io.ReadAll(tr) //nolint:gosec // the archive is verified against the release checksums
+		}
+	}
+}
+
+// extractZip returns the file name at the top of a zip archive.
+func extractZip(archive []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = rc.Close() }()
+		return io.ReadAll(rc) //nolint:gosec // the archive is verified against the release checksums
+	}
+	return nil, fmt.Errorf("no %s in the archive", name)
+}
+
+// Replace replaces the executable at path with binary. The new binary is
+// written next to it and renamed into place, so an interrupted upgrade
+// leaves the old one working. Windows doesn't allow replacing a running
+// executable, so there it is moved aside to <path>.old first.
+func Replace(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".new*")
+	if err != nil {
+		return fmt.Errorf("failed to write to %s: %w", dir, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(binary); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0o111); err != nil { //nolint:gosec // the binary must be executable
+		return err
+	}
+	if runtime.GOOS != "windows" {
+		return os.Rename(tmp.Name(), path)
+	}
+	old := path + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(path, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Rename(old, path)
+		return err
+	}
+	return nil
+}
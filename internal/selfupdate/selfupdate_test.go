@@ -0,0 +1,218 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/download/downloadtest"
+)
+
+// fakeGitHub returns an updater whose requests are answered from files, by
+// URL; other URLs are not found. Authorization headers are recorded by URL.
+func fakeGitHub(t *testing.T, files map[string][]byte, auth map[string]string) *Updater {
+	t.Helper()
+	u := NewUpdater()
+	u.API = "https://api.test"
+	u.OS, u.Arch = "linux", "amd64"
+	u.HTTPClient = downloadtest.Client(files, func(req *http.Request) {
+		if auth != nil {
+			auth[req.URL.String()] = req.Header.Get("Authorization")
+		}
+	})
+	return u
+}
+
+// tarGzOf returns a .tar.gz archive with a file name holding content.
+func tarGzOf(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for file, body := range map[string]string{"README.md": "# motf", name: content} {
+		if err := tw.WriteHeader(&tar.Header{Name: file, Mode: 0o755, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// latestRelease returns the files of a 1.4.0 release with a linux/amd64 archive.
+func latestRelease(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+	sum := sha256.Sum256(archive)
+	return map[string][]byte{
+		"https://api.test/repos/TechnicallyJoe/terraform-motf/releases/latest": []byte(`{
+			"tag_name": "v1.4.0",
+			"assets": [
+				{"name": "motf_1.4.0_linux_amd64.tar.gz", "browser_download_url": "https://dl.test/motf_1.4.0_linux_amd64.tar.gz"},
+				{"name": "checksums.txt", "browser_download_url": "https://dl.test/checksums.txt"}
+			]
+		}`),
+		"https://dl.test/motf_1.4.0_linux_amd64.tar.gz": archive,
+		"https://dl.test/checksums.txt":                 []byte(hex.EncodeToString(sum[:]) + "  motf_1.4.0_linux_amd64.tar.gz\n"),
+	}
+}
+
+func TestUpdater_LatestAndDownload(t *testing.T) {
+	auth := map[string]string{}
+	u := fakeGitHub(t, latestRelease(t, tarGzOf(t, "motf", "new binary")), auth)
+	u.Token = "secret"
+
+	release, err := u.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if release.Version != "1.4.0" {
+		t.Errorf("version = %q, want 1.4.0", release.Version)
+	}
+	binary, err := u.Download(context.Background(), release)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if string(binary) != "new binary" {
+		t.Errorf("binary = %q, want %q", binary, "new binary")
+	}
+	if auth["https://api.test/repos/TechnicallyJoe/terraform-motf/releases/latest"] != "Bearer secret" {
+		t.Error("the token should be sent to the API")
+	}
+	if auth["https://dl.test/checksums.txt"] != "" {
+		t.Error("the token should not be sent to download URLs")
+	}
+}
+
+func TestUpdater_TokenOnlySentToAPIHost(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://api.test/repos/TechnicallyJoe/terraform-motf/releases/latest", true},
+		{"https://API.test/repos", true},
+		{"https://api.test.evil.example/repos", false},
+		{"https://api.testevil.example/repos", false},
+		{"http://api.test/repos", false},
+		{"https://dl.test/checksums.txt", false},
+	}
+	for _, tt := range tests {
+		auth := map[string]string{}
+		u := fakeGitHub(t, nil, auth)
+		u.Token = "secret"
+		_, _ = u.get(context.Background(), tt.url, "application/json")
+		if got := auth[tt.url] != ""; got != tt.want {
+			t.Errorf("token sent to %s = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestUpdater_DownloadChecksumMismatch(t *testing.T) {
+	files := latestRelease(t, tarGzOf(t, "motf", "new binary"))
+	files["https://dl.test/motf_1.4.0_linux_amd64.tar.gz"] = tarGzOf(t, "motf", "tampered")
+	u := fakeGitHub(t, files, nil)
+
+	release, err := u.Latest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Download(context.Background(), release); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Download() error = %v, want a checksum mismatch", err)
+	}
+}
+
+func TestUpdater_DownloadNoArchive(t *testing.T) {
+	u := fakeGitHub(t, latestRelease(t, tarGzOf(t, "motf", "")), nil)
+	u.OS, u.Arch = "freebsd", "riscv64"
+
+	release, err := u.Latest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := u.Download(context.Background(), release); err == nil || !strings.Contains(err.Error(), "no archive for freebsd/riscv64") {
+		t.Errorf("Download() error = %v, want no archive", err)
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	u := &Updater{OS: "darwin", Arch: "arm64"}
+	if got := u.ArchiveName("1.4.0"); got != "motf_1.4.0_darwin_arm64.tar.gz" {
+		t.Errorf("ArchiveName() = %q", got)
+	}
+	u.OS = "windows"
+	if got := u.ArchiveName("1.4.0"); got != "motf_1.4.0_windows_arm64.zip" {
+		t.Errorf("ArchiveName() = %q", got)
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("motf.exe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("windows binary"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	binary, err := extractZip(buf.Bytes(), "motf.exe")
+	if err != nil || string(binary) != "windows binary" {
+		t.Errorf("extractZip() = %q, %v", binary, err)
+	}
+}
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"1.3.0", "1.4.0", true},
+		{"v1.4.0", "1.4.0", false},
+		{"1.5.0", "1.4.0", false},
+		{"1.4.0-rc.1", "1.4.0", true},
+	}
+	for _, tt := range tests {
+		got, err := Newer(tt.current, tt.latest)
+		if err != nil || got != tt.want {
+			t.Errorf("Newer(%q, %q) = %t, %v, want %t", tt.current, tt.latest, got, err, tt.want)
+		}
+	}
+	if _, err := Newer("dev", "1.4.0"); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("Newer(dev) error = %v, want ErrUnknownVersion", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "motf")
+	if err := os.WriteFile(path, []byte("old binary"), 0o755); err != nil { //nolint:gosec // test executable
+		t.Fatal(err)
+	}
+	if err := Replace(path, []byte("new binary")); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new binary" {
+		t.Errorf("executable = %q, %v", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temporary files are left behind: %v", entries)
+	}
+}
@@ -0,0 +1,153 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// moduleSchema selects the module blocks of a configuration file
+var moduleSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{{Type: "module", LabelNames: []string{"name"}}},
+}
+
+// callSchema selects the source and version arguments of a module block
+var callSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "source"}, {Name: "version"}},
+}
+
+// dataSchema selects the data blocks of a configuration file
+var dataSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{{Type: "data", LabelNames: []string{"type", "name"}}},
+}
+
+// remoteStateSchema selects the backend and config arguments of a
+// terraform_remote_state data source
+var remoteStateSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "backend"}, {Name: "config"}},
+}
+
+// ModuleCall is a module block whose source is a string literal.
+type ModuleCall struct {
+	Name        string
+	Source      string
+	Line        int       // Line of the source argument
+	SourceRange hcl.Range // Range of the source value, including its quotes
+
+	// Version constraint of a registry source, empty if there is none or it
+	// isn't a string literal
+	Version      string
+	VersionRange hcl.Range // Range of the version value, including its quotes
+}
+
+// ParseFile parses a Terraform configuration file in native (.tf) or JSON
+// (.tf.json) syntax.
+func ParseFile(file string) (*hcl.File, error) {
+	src, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	var f *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(file, ".json") {
+		f, diags = hcljson.Parse(src, file)
+	} else {
+		f, diags = hclsyntax.ParseConfig(src, file, hcl.InitialPos)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return f, nil
+}
+
+// ModuleCalls returns the module blocks in a parsed file, in order. Module
+// blocks whose source isn't a string literal are skipped.
+func ModuleCalls(f *hcl.File) []ModuleCall {
+	content, _, _ := f.Body.PartialContent(moduleSchema)
+	var calls []ModuleCall
+	for _, block := range content.Blocks {
+		blockContent, _, _ := block.Body.PartialContent(callSchema)
+		attr := blockContent.Attributes["source"]
+		source, ok := stringAttribute(attr)
+		if !ok {
+			continue
+		}
+		call := ModuleCall{
+			Name:        block.Labels[0],
+			Source:      source,
+			Line:        attr.Range.Start.Line,
+			SourceRange: attr.Expr.Range(),
+		}
+		if version, ok := stringAttribute(blockContent.Attributes["version"]); ok {
+			call.Version = version
+			call.VersionRange = blockContent.Attributes["version"].Expr.Range()
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// RemoteState is a terraform_remote_state data source whose backend is a
+// string literal.
+type RemoteState struct {
+	Name    string
+	Backend string
+	Config  map[string]string // Settings of config that are string literals
+	Line    int
+}
+
+// RemoteStates returns the terraform_remote_state data sources in a parsed
+// file, in order. Data sources whose backend isn't a string literal are
+// skipped.
+func RemoteStates(f *hcl.File) []RemoteState {
+	content, _, _ := f.Body.PartialContent(dataSchema)
+	var states []RemoteState
+	for _, block := range content.Blocks {
+		if block.Labels[0] != "terraform_remote_state" {
+			continue
+		}
+		blockContent, _, _ := block.Body.PartialContent(remoteStateSchema)
+		backend, ok := stringAttribute(blockContent.Attributes["backend"])
+		if !ok {
+			continue
+		}
+		state := RemoteState{
+			Name:    block.Labels[1],
+			Backend: backend,
+			Config:  map[string]string{},
+			Line:    block.DefRange.Start.Line,
+		}
+		if attr := blockContent.Attributes["config"]; attr != nil {
+			pairs, diags := hcl.ExprMap(attr.Expr)
+			if !diags.HasErrors() {
+				for _, pair := range pairs {
+					key, keyDiags := pair.Key.Value(nil)
+					value, valueDiags := pair.Value.Value(nil)
+					if keyDiags.HasErrors() || valueDiags.HasErrors() || key.Type() != cty.String || value.IsNull() || value.Type() != cty.String {
+						continue
+					}
+					state.Config[key.AsString()] = value.AsString()
+				}
+			}
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// stringAttribute returns the value of attr if it is a string literal.
+func stringAttribute(attr *hcl.Attribute) (string, bool) {
+	if attr == nil {
+		return "", false
+	}
+	value, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || value.IsNull() || value.Type() != cty.String {
+		return "", false
+	}
+	return value.AsString(), true
+}
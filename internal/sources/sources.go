@@ -0,0 +1,131 @@
+// Package sources finds the local module sources in Terraform files and
+// checks that they resolve to modules.
+package sources
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/finder"
+)
+
+// skipDirs are never searched for Terraform files
+var skipDirs = []string{".terraform", "node_modules", "vendor"}
+
+// Reference is a module block with a local source.
+type Reference struct {
+	File   string `json:"file"` // Slash-separated, relative to the searched root
+	Line   int    `json:"line"`
+	Module string `json:"module"` // Name of the module block
+	Source string `json:"source"`
+}
+
+// Problem is a local source that doesn't resolve to a module.
+type Problem struct {
+	Reference
+	Reason string `json:"reason"`
+}
+
+// IsLocal reports whether a module source is a local path.
+func IsLocal(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+// Find returns the module blocks with local sources in all .tf and .tf.json
+// files under root, sorted by file and line. Hidden directories are skipped.
+func Find(root string) ([]Reference, error) {
+	var refs []Reference
+	err := WalkFiles(root, func(file, rel string) error {
+		fileRefs, err := findInFile(file, rel)
+		if err != nil {
+			return err
+		}
+		refs = append(refs, fileRefs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		if refs[i].File != refs[j].File {
+			return refs[i].File < refs[j].File
+		}
+		return refs[i].Line < refs[j].Line
+	})
+	return refs, nil
+}
+
+// WalkFiles calls fn for each .tf and .tf.json file under root with its path
+// and its slash-separated path relative to root. Hidden directories and
+// directories like .terraform are skipped.
+func WalkFiles(root string, fn func(file, rel string) error) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && (strings.HasPrefix(d.Name(), ".") || slices.Contains(skipDirs, d.Name())) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !finder.IsTerraformFile(d.Name()) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		return fn(p, filepath.ToSlash(rel))
+	})
+}
+
+// findInFile returns the module blocks with local sources in a Terraform file.
+// Sources that aren't string literals are skipped.
+func findInFile(file, rel string) ([]Reference, error) {
+	f, err := ParseFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", rel, err)
+	}
+
+	var refs []Reference
+	for _, call := range ModuleCalls(f) {
+		if IsLocal(call.Source) {
+			refs = append(refs, Reference{File: rel, Line: call.Line, Module: call.Name, Source: call.Source})
+		}
+	}
+	return refs, nil
+}
+
+// Target returns the directory a reference's source points to, relative to
+// the searched root.
+func (r Reference) Target() string {
+	return path.Join(path.Dir(r.File), r.Source)
+}
+
+// Check returns the references under root whose source isn't a directory
+// with .tf or .tf.json files.
+func Check(root string, refs []Reference) []Problem {
+	var problems []Problem
+	for _, ref := range refs {
+		target := filepath.Join(root, filepath.FromSlash(ref.Target()))
+		info, err := os.Stat(target)
+		switch {
+		case err != nil:
+			problems = append(problems, Problem{Reference: ref, Reason: "directory does not exist"})
+		case !info.IsDir():
+			problems = append(problems, Problem{Reference: ref, Reason: "not a directory"})
+		case !finder.HasTerraformFiles(target):
+			problems = append(problems, Problem{Reference: ref, Reason: "directory contains no .tf files"})
+		}
+	}
+	return problems
+}
@@ -0,0 +1,171 @@
+package sources
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "storage", "main.tf"), "# terraform\n")
+	writeFile(t, filepath.Join(root, "storage", "examples", "basic", "main.tf"), `module "storage" {
+  source = "../.."
+}
+
+module "registry" {
+  source = "Azure/naming/azurerm"
+}
+
+module "dynamic" {
+  source = var.source
+}
+`)
+	writeFile(t, filepath.Join(root, "storage", ".terraform", "modules", "x", "main.tf"), `module "cached" {
+  source = "./missing"
+}
+`)
+
+	refs, err := Find(root)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(refs) != 1 {
+		t.Fatalf("refs = %+v, want only the local source", refs)
+	}
+	want := Reference{File: "storage/examples/basic/main.tf", Line: 2, Module: "storage", Source: "../.."}
+	if refs[0] != want {
+		t.Errorf("refs[0] = %+v, want %+v", refs[0], want)
+	}
+	if got := refs[0].Target(); got != "storage" {
+		t.Errorf("Target() = %q, want storage", got)
+	}
+}
+
+func TestFind_JSON(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "storage", "main.tf.json"), `{"resource": {}}`)
+	writeFile(t, filepath.Join(root, "storage", "examples", "basic", "main.tf.json"), `{
+  "module": {
+    "storage": {
+      "source": "../.."
+    },
+    "registry": {
+      "source": "Azure/naming/azurerm"
+    },
+    "dynamic": {
+      "source": "${var.source}"
+    }
+  }
+}
+`)
+
+	refs, err := Find(root)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	want := Reference{File: "storage/examples/basic/main.tf.json", Line: 4, Module: "storage", Source: "../.."}
+	if len(refs) != 1 || refs[0] != want {
+		t.Fatalf("refs = %+v, want [%+v]", refs, want)
+	}
+	if problems := Check(root, refs); len(problems) != 0 {
+		t.Errorf("problems = %+v, want none for a module with only .tf.json files", problems)
+	}
+}
+
+func TestFind_InvalidHCL(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "broken", "main.tf"), "module \"x\" {\n")
+
+	if _, err := Find(root); err == nil {
+		t.Error("expected an error for invalid HCL")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "components", "vnet", "main.tf"), "# terraform\n")
+	writeFile(t, filepath.Join(root, "components", "empty", "README.md"), "# empty\n")
+	writeFile(t, filepath.Join(root, "components", "file.tf"), "# terraform\n")
+
+	refs := []Reference{
+		{File: "bases/network/main.tf", Module: "ok", Source: "../../components/vnet"},
+		{File: "bases/network/main.tf", Module: "moved", Source: "../../components/network"},
+		{File: "bases/network/main.tf", Module: "empty", Source: "../../components/empty"},
+		{File: "bases/network/main.tf", Module: "file", Source: "../../components/file.tf"},
+	}
+	problems := Check(root, refs)
+
+	want := map[string]string{
+		"moved": "directory does not exist",
+		"empty": "directory contains no .tf files",
+		"file":  "not a directory",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("problems = %+v, want %d", problems, len(want))
+	}
+	for _, p := range problems {
+		if p.Reason != want[p.Module] {
+			t.Errorf("module %s: reason = %q, want %q", p.Module, p.Reason, want[p.Module])
+		}
+	}
+}
+
+func TestIsLocal(t *testing.T) {
+	for source, want := range map[string]bool{
+		"./modules/x":                    true,
+		"../../components/x":             true,
+		"Azure/naming/azurerm":           false,
+		"git::https://example.com/x.git": false,
+	} {
+		if got := IsLocal(source); got != want {
+			t.Errorf("IsLocal(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestRemoteStates(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "data.tf")
+	writeFile(t, file, `data "terraform_remote_state" "network" {
+  backend = "azurerm"
+  config = {
+    key             = "prod/network.tfstate"
+    subscription_id = var.subscription_id
+  }
+}
+
+data "terraform_remote_state" "dynamic" {
+  backend = var.backend
+}
+
+data "azurerm_client_config" "current" {}
+`)
+
+	f, err := ParseFile(file)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	states := RemoteStates(f)
+	if len(states) != 1 {
+		t.Fatalf("states = %+v, want only the remote state with a literal backend", states)
+	}
+	got := states[0]
+	if got.Name != "network" || got.Backend != "azurerm" || got.Line != 1 {
+		t.Errorf("state = %+v", got)
+	}
+	// Settings that aren't string literals are left out
+	if len(got.Config) != 1 || got.Config["key"] != "prod/network.tfstate" {
+		t.Errorf("Config = %v, want only the key", got.Config)
+	}
+}
@@ -0,0 +1,189 @@
+package spacelift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Environment variables with the Spacelift API credentials, as used by spacectl
+const (
+	EnvEndpoint  = "SPACELIFT_API_KEY_ENDPOINT" // e.g. https://acme.app.spacelift.io
+	EnvKeyID     = "SPACELIFT_API_KEY_ID"
+	EnvKeySecret = "SPACELIFT_API_KEY_SECRET"
+	EnvToken     = "SPACELIFT_API_TOKEN" // A JWT used instead of an API key
+)
+
+// Stack is a Spacelift stack.
+type Stack struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Repository  string `json:"repository"`
+	Branch      string `json:"branch"`
+	ProjectRoot string `json:"projectRoot"` // Directory of the stack, relative to the repository root
+}
+
+// Client calls the Spacelift GraphQL API.
+type Client struct {
+	Endpoint   string
+	HTTPClient *http.Client
+
+	keyID, keySecret string
+	token            string
+}
+
+// NewClient returns a client that authenticates with an API key, which is
+// exchanged for a token on the first request.
+func NewClient(endpoint, keyID, keySecret string) *Client {
+	return &Client{
+		Endpoint:   strings.TrimSuffix(endpoint, "/"),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		keyID:      keyID,
+		keySecret:  keySecret,
+	}
+}
+
+// ClientFromEnv returns a client configured from the SPACELIFT_API_*
+// environment variables read with getenv.
+func ClientFromEnv(getenv func(string) string) (*Client, error) {
+	endpoint := getenv(EnvEndpoint)
+	if endpoint == "" {
+		return nil, fmt.Errorf("%s is not set", EnvEndpoint)
+	}
+	c := NewClient(endpoint, getenv(EnvKeyID), getenv(EnvKeySecret))
+	c.token = getenv(EnvToken)
+	if c.token == "" && (c.keyID == "" || c.keySecret == "") {
+		return nil, fmt.Errorf("set %s, or %s and %s", EnvToken, EnvKeyID, EnvKeySecret)
+	}
+	return c, nil
+}
+
+// Stacks returns the stacks visible to the client.
+func (c *Client) Stacks(ctx context.Context) ([]Stack, error) {
+	var data struct {
+		Stacks []Stack `json:"stacks"`
+	}
+	const query = `query { stacks { id name repository branch projectRoot } }`
+	if err := c.query(ctx, query, nil, &data); err != nil {
+		return nil, fmt.Errorf("failed to list stacks: %w", err)
+	}
+	return data.Stacks, nil
+}
+
+// TriggerRun triggers a tracked run of a stack and returns the run ID. An
+// empty commitSha runs the head of the stack's tracked branch.
+func (c *Client) TriggerRun(ctx context.Context, stackID, commitSha string) (string, error) {
+	var data struct {
+		RunTrigger struct {
+			ID string `json:"id"`
+		} `json:"runTrigger"`
+	}
+	const mutation = `mutation($stack: ID!, $sha: String) { runTrigger(stack: $stack, commitSha: $sha) { id } }`
+	vars := map[string]any{"stack": stackID}
+	if commitSha != "" {
+		vars["sha"] = commitSha
+	}
+	if err := c.query(ctx, mutation, vars, &data); err != nil {
+		return "", fmt.Errorf("failed to trigger a run of stack %s: %w", stackID, err)
+	}
+	return data.RunTrigger.ID, nil
+}
+
+// authenticate exchanges the API key for a token, if there is none yet.
+func (c *Client) authenticate(ctx context.Context) error {
+	if c.token != "" {
+		return nil
+	}
+	var data struct {
+		APIKeyUser *struct {
+			JWT string `json:"jwt"`
+		} `json:"apiKeyUser"`
+	}
+	const mutation = `mutation($id: ID!, $secret: String!) { apiKeyUser(id: $id, secret: $secret) { jwt } }`
+	if err := c.do(ctx, mutation, map[string]any{"id": c.keyID, "secret": c.keySecret}, &data); err != nil {
+		return fmt.Errorf("failed to authenticate: %w", err)
+	}
+	if data.APIKeyUser == nil || data.APIKeyUser.JWT == "" {
+		return errors.New("failed to authenticate: invalid API key")
+	}
+	c.token = data.APIKeyUser.JWT
+	return nil
+}
+
+// query authenticates if needed and runs a GraphQL query.
+func (c *Client) query(ctx context.Context, query string, vars map[string]any, out any) error {
+	if err := c.authenticate(ctx); err != nil {
+		return err
+	}
+	return c.do(ctx, query, vars, out)
+}
+
+// graphQLResponse is the envelope of a GraphQL response
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// do posts a GraphQL request and decodes its data into out.
+func (c *Client) do(ctx context.Context, query string, vars map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result graphQLResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return json.Unmarshal(result.Data, out)
+}
+
+// StacksForModule returns the stacks whose project root is the module
+// directory (slash-separated, relative to the repository root).
+func StacksForModule(stacks []Stack, modulePath string) []Stack {
+	modulePath = path.Clean(modulePath)
+	var matches []Stack
+	for _, s := range stacks {
+		if s.ProjectRoot != "" && path.Clean(strings.Trim(s.ProjectRoot, "/")) == modulePath {
+			matches = append(matches, s)
+		}
+	}
+	return matches
+}
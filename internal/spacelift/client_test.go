@@ -0,0 +1,190 @@
+package spacelift
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// graphQLRequest is a request received by the fake Spacelift API
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// fakeAPI starts a server that answers GraphQL requests with respond and
+// records the requests and their Authorization headers.
+func fakeAPI(t *testing.T, respond func(req graphQLRequest) string) (*httptest.Server, *[]graphQLRequest, *[]string) {
+	t.Helper()
+	var requests []graphQLRequest
+	var auths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+		auths = append(auths, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(respond(req)))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests, &auths
+}
+
+func TestClientFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"api key", map[string]string{EnvEndpoint: "https://acme.app.spacelift.io", EnvKeyID: "id", EnvKeySecret: "secret"}, ""},
+		{"token", map[string]string{EnvEndpoint: "https://acme.app.spacelift.io", EnvToken: "jwt"}, ""},
+		{"no endpoint", map[string]string{EnvToken: "jwt"}, EnvEndpoint},
+		{"no credentials", map[string]string{EnvEndpoint: "https://acme.app.spacelift.io", EnvKeyID: "id"}, EnvKeySecret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ClientFromEnv(func(key string) string { return tt.env[key] })
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ClientFromEnv() error = %v, want it to mention %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ClientFromEnv() error = %v", err)
+			}
+			if c.Endpoint != "https://acme.app.spacelift.io" {
+				t.Errorf("Endpoint = %q", c.Endpoint)
+			}
+		})
+	}
+}
+
+func TestClient_Stacks_ExchangesAPIKey(t *testing.T) {
+	server, requests, auths := fakeAPI(t, func(req graphQLRequest) string {
+		if strings.Contains(req.Query, "apiKeyUser") {
+			return `{"data":{"apiKeyUser":{"jwt":"token-1"}}}`
+		}
+		return `{"data":{"stacks":[{"id":"storage-prod","name":"Storage prod","repository":"infra","branch":"main","projectRoot":"projects/storage"}]}}`
+	})
+
+	c := NewClient(server.URL+"/", "id", "secret")
+	stacks, err := c.Stacks(context.Background())
+	if err != nil {
+		t.Fatalf("Stacks() error = %v", err)
+	}
+	if len(stacks) != 1 || stacks[0].ID != "storage-prod" || stacks[0].ProjectRoot != "projects/storage" {
+		t.Errorf("stacks = %+v", stacks)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(*requests))
+	}
+	if got := (*requests)[0].Variables; got["id"] != "id" || got["secret"] != "secret" {
+		t.Errorf("apiKeyUser variables = %v", got)
+	}
+	if (*auths)[0] != "" || (*auths)[1] != "Bearer token-1" {
+		t.Errorf("Authorization headers = %q", *auths)
+	}
+
+	// The token is reused
+	if _, err := c.Stacks(context.Background()); err != nil {
+		t.Fatalf("Stacks() error = %v", err)
+	}
+	if len(*requests) != 3 {
+		t.Errorf("expected the token to be reused, got %d requests", len(*requests))
+	}
+}
+
+func TestClient_InvalidAPIKey(t *testing.T) {
+	server, _, _ := fakeAPI(t, func(req graphQLRequest) string {
+		return `{"data":{"apiKeyUser":null}}`
+	})
+
+	_, err := NewClient(server.URL, "id", "wrong").Stacks(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid API key") {
+		t.Fatalf("expected invalid API key error, got %v", err)
+	}
+}
+
+func TestClient_TriggerRun(t *testing.T) {
+	server, requests, auths := fakeAPI(t, func(req graphQLRequest) string {
+		return `{"data":{"runTrigger":{"id":"01HRUN"}}}`
+	})
+
+	c, err := ClientFromEnv(func(key string) string {
+		return map[string]string{EnvEndpoint: server.URL, EnvToken: "jwt"}[key]
+	})
+	if err != nil {
+		t.Fatalf("ClientFromEnv() error = %v", err)
+	}
+	runID, err := c.TriggerRun(context.Background(), "storage-prod", "abc123")
+	if err != nil {
+		t.Fatalf("TriggerRun() error = %v", err)
+	}
+	if runID != "01HRUN" {
+		t.Errorf("run ID = %q, want 01HRUN", runID)
+	}
+	if got := (*requests)[0].Variables; got["stack"] != "storage-prod" || got["sha"] != "abc123" {
+		t.Errorf("runTrigger variables = %v", got)
+	}
+	if (*auths)[0] != "Bearer jwt" {
+		t.Errorf("Authorization = %q, want Bearer jwt", (*auths)[0])
+	}
+}
+
+func TestClient_GraphQLErrors(t *testing.T) {
+	server, _, _ := fakeAPI(t, func(req graphQLRequest) string {
+		return `{"data":null,"errors":[{"message":"stack not found"},{"message":"unauthorized"}]}`
+	})
+
+	c := NewClient(server.URL, "", "")
+	c.token = "jwt"
+	_, err := c.TriggerRun(context.Background(), "missing", "")
+	if err == nil || !strings.Contains(err.Error(), "stack not found; unauthorized") {
+		t.Fatalf("expected GraphQL errors, got %v", err)
+	}
+}
+
+func TestClient_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	c := NewClient(server.URL, "", "")
+	c.token = "jwt"
+	_, err := c.Stacks(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("expected 403 error, got %v", err)
+	}
+}
+
+func TestStacksForModule(t *testing.T) {
+	stacks := []Stack{
+		{ID: "storage-dev", ProjectRoot: "projects/storage"},
+		{ID: "storage-prod", ProjectRoot: "/projects/storage/"},
+		{ID: "network", ProjectRoot: "projects/network"},
+		{ID: "root"},
+	}
+
+	var ids []string
+	for _, s := range StacksForModule(stacks, "projects/storage") {
+		ids = append(ids, s.ID)
+	}
+	if strings.Join(ids, ",") != "storage-dev,storage-prod" {
+		t.Errorf("stacks = %v, want [storage-dev storage-prod]", ids)
+	}
+	if got := StacksForModule(stacks, "projects/storage-account"); len(got) != 0 {
+		t.Errorf("expected no stacks for a module with a similar name, got %v", got)
+	}
+}
@@ -0,0 +1,91 @@
+package spacelift
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Parts of a version that BumpVersion can increment
+const (
+	PartMajor = "major"
+	PartMinor = "minor"
+	PartPatch = "patch"
+)
+
+// ErrNoModuleVersion is returned by BumpModuleVersion when the module has no
+// module_version in .spacelift/config.yml.
+var ErrNoModuleVersion = errors.New("no module_version in " + DirSpacelift + "/" + FileConfig)
+
+// versionPattern matches MAJOR.MINOR.PATCH with an optional v prefix
+var versionPattern = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)$`)
+
+// moduleVersionLine matches the module_version line of config.yml, capturing
+// the key (with an opening quote) and the version
+var moduleVersionLine = regexp.MustCompile(`(?m)^(module_version:[ \t]*["']?)([^"'\s#]+)`)
+
+// BumpVersion increments part of version (major, minor, or patch) and resets
+// the parts after it, e.g. 1.2.3 -> 1.3.0 for minor. A v prefix is kept.
+func BumpVersion(version, part string) (string, error) {
+	m := versionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return "", fmt.Errorf("version '%s' is not MAJOR.MINOR.PATCH", version)
+	}
+	major, _ := strconv.Atoi(m[2])
+	minor, _ := strconv.Atoi(m[3])
+	patch, _ := strconv.Atoi(m[4])
+
+	switch part {
+	case PartMajor:
+		major, minor, patch = major+1, 0, 0
+	case PartMinor:
+		minor, patch = minor+1, 0
+	case PartPatch:
+		patch++
+	default:
+		return "", fmt.Errorf("invalid version part '%s': must be %s, %s, or %s", part, PartMajor, PartMinor, PartPatch)
+	}
+	return fmt.Sprintf("%s%d.%d.%d", m[1], major, minor, patch), nil
+}
+
+// BumpModuleVersion increments part of the module_version in the module's
+// .spacelift/config.yml and returns the old and new versions. Only the
+// version is rewritten, so comments and other settings are preserved. With
+// dryRun, the file isn't written.
+func BumpModuleVersion(modulePath, part string, dryRun bool) (oldVersion, newVersion string, err error) {
+	configPath := filepath.Join(modulePath, DirSpacelift, FileConfig)
+	data, err := os.ReadFile(configPath) //nolint:gosec // configPath is constructed from known constants
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", ErrNoModuleVersion
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	oldVersion = ReadModuleVersion(modulePath)
+	loc := moduleVersionLine.FindSubmatchIndex(data)
+	if oldVersion == "" || loc == nil || string(data[loc[4]:loc[5]]) != oldVersion {
+		return "", "", ErrNoModuleVersion
+	}
+	newVersion, err = BumpVersion(oldVersion, part)
+	if err != nil {
+		return "", "", err
+	}
+	if dryRun {
+		return oldVersion, newVersion, nil
+	}
+
+	out := strings.Join([]string{string(data[:loc[4]]), newVersion, string(data[loc[5]:])}, "")
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(configPath, []byte(out), info.Mode().Perm()); err != nil {
+		return "", "", fmt.Errorf("failed to write %s: %w", configPath, err)
+	}
+	return oldVersion, newVersion, nil
+}
@@ -0,0 +1,109 @@
+package spacelift
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		part    string
+		want    string
+		wantErr bool
+	}{
+		{"1.2.3", PartPatch, "1.2.4", false},
+		{"1.2.3", PartMinor, "1.3.0", false},
+		{"1.2.3", PartMajor, "2.0.0", false},
+		{"v0.9.9", PartMinor, "v0.10.0", false},
+		{"1.2", PartPatch, "", true},
+		{"1.2.3-rc.1", PartPatch, "", true},
+		{"1.2.3", "build", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+"/"+tt.part, func(t *testing.T) {
+			got, err := BumpVersion(tt.version, tt.part)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BumpVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BumpVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func writeSpaceliftConfig(t *testing.T, modulePath, content string) string {
+	t.Helper()
+	dir := filepath.Join(modulePath, DirSpacelift)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create .spacelift dir: %v", err)
+	}
+	path := filepath.Join(dir, FileConfig)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestBumpModuleVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := writeSpaceliftConfig(t, tmpDir, `# Released by CI
+version: 1
+module_version: "1.4.2" # Bump on every change
+tests:
+  - name: default
+`)
+
+	oldVersion, newVersion, err := BumpModuleVersion(tmpDir, PartMinor, false)
+	if err != nil {
+		t.Fatalf("BumpModuleVersion() error = %v", err)
+	}
+	if oldVersion != "1.4.2" || newVersion != "1.5.0" {
+		t.Errorf("versions = %s -> %s, want 1.4.2 -> 1.5.0", oldVersion, newVersion)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	want := `# Released by CI
+version: 1
+module_version: "1.5.0" # Bump on every change
+tests:
+  - name: default
+`
+	if string(data) != want {
+		t.Errorf("config.yml:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestBumpModuleVersion_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeSpaceliftConfig(t, tmpDir, "module_version: 0.1.0\n")
+
+	_, newVersion, err := BumpModuleVersion(tmpDir, PartPatch, true)
+	if err != nil {
+		t.Fatalf("BumpModuleVersion() error = %v", err)
+	}
+	if newVersion != "0.1.1" {
+		t.Errorf("new version = %s, want 0.1.1", newVersion)
+	}
+	if got := ReadModuleVersion(tmpDir); got != "0.1.0" {
+		t.Errorf("expected dry run not to write, version is %s", got)
+	}
+}
+
+func TestBumpModuleVersion_NoVersion(t *testing.T) {
+	if _, _, err := BumpModuleVersion(t.TempDir(), PartPatch, false); !errors.Is(err, ErrNoModuleVersion) {
+		t.Errorf("expected ErrNoModuleVersion without config, got %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	writeSpaceliftConfig(t, tmpDir, "version: 1\n")
+	if _, _, err := BumpModuleVersion(tmpDir, PartPatch, false); !errors.Is(err, ErrNoModuleVersion) {
+		t.Errorf("expected ErrNoModuleVersion without module_version, got %v", err)
+	}
+}
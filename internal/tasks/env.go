@@ -3,6 +3,7 @@ package tasks
 import (
 	"os"
 	"path/filepath"
+	"sort"
 )
 
 // Environment variable names for built-in variables
@@ -17,13 +18,15 @@ const (
 // EnvBuilder constructs environment variables for task execution.
 // It starts with the current process environment and adds MOTF_* built-in variables.
 type EnvBuilder struct {
-	vars map[string]string
+	vars  map[string]string
+	extra map[string]string // user-defined variables, overridden by built-ins
 }
 
 // NewEnvBuilder creates a new EnvBuilder with empty built-in variables.
 func NewEnvBuilder() *EnvBuilder {
 	return &EnvBuilder{
-		vars: make(map[string]string),
+		vars:  make(map[string]string),
+		extra: make(map[string]string),
 	}
 }
 
@@ -58,12 +61,25 @@ func (b *EnvBuilder) WithBinary(binary string) *EnvBuilder {
 	return b
 }
 
+// WithEnv adds user-defined variables (e.g. from .motf.module.yml).
+// Built-in MOTF_* variables take priority over variables with the same name.
+func (b *EnvBuilder) WithEnv(vars map[string]string) *EnvBuilder {
+	for key, value := range vars {
+		b.extra[key] = value
+	}
+	return b
+}
+
 // Build returns the complete environment for task execution.
-// It includes the current process environment plus all MOTF_* built-in variables.
+// It includes the current process environment, user-defined variables, and all
+// MOTF_* built-in variables. Later entries win when a name appears more than once.
 func (b *EnvBuilder) Build() []string {
 	// Start with current environment
 	env := os.Environ()
 
+	// Add user-defined variables
+	env = append(env, EnvPairs(b.extra)...)
+
 	// Add built-in variables
 	for key, value := range b.vars {
 		env = append(env, key+"="+value)
@@ -72,6 +88,21 @@ func (b *EnvBuilder) Build() []string {
 	return env
 }
 
+// EnvPairs converts variables to KEY=value pairs, sorted by key for deterministic output.
+func EnvPairs(vars map[string]string) []string {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+vars[key])
+	}
+	return pairs
+}
+
 // ModuleNameFromPath extracts the module name from an absolute module path.
 // It returns the last component of the path (e.g., "/path/to/storage-account" -> "storage-account").
 func ModuleNameFromPath(modulePath string) string {
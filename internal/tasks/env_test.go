@@ -158,3 +158,29 @@ func TestModuleNameFromPath(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvBuilder_WithEnv_BuiltInsWin(t *testing.T) {
+	env := NewEnvBuilder().
+		WithEnv(map[string]string{"ARM_SUBSCRIPTION_ID": "sub-123", EnvBinary: "custom"}).
+		WithBinary("tofu").
+		Build()
+
+	var binaryValues []string
+	foundCustom := false
+	for _, e := range env {
+		if e == "ARM_SUBSCRIPTION_ID=sub-123" {
+			foundCustom = true
+		}
+		if strings.HasPrefix(e, EnvBinary+"=") {
+			binaryValues = append(binaryValues, e)
+		}
+	}
+
+	if !foundCustom {
+		t.Error("expected user-defined variable in environment")
+	}
+	// exec uses the last value for duplicate keys, so the built-in must come last
+	if len(binaryValues) == 0 || binaryValues[len(binaryValues)-1] != EnvBinary+"=tofu" {
+		t.Errorf("expected built-in %s to take priority, got %v", EnvBinary, binaryValues)
+	}
+}
@@ -0,0 +1,75 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+)
+
+// Environment variables set for hooks, besides the built-in MOTF_* variables
+const (
+	EnvHook   = "MOTF_HOOK"   // Hook being run, e.g. pre_plan
+	EnvStatus = "MOTF_STATUS" // Outcome of the command in post_ hooks: success or failure
+	EnvTask   = "MOTF_TASK"   // Task the pre_task or post_task hook runs around
+)
+
+// Outcomes of a command, passed to post_ hooks as MOTF_STATUS
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// Hooks run around each task
+const (
+	HookPreTask  = "pre_task"
+	HookPostTask = "post_task"
+)
+
+// Status returns the MOTF_STATUS of a command that returned err.
+func Status(err error) string {
+	if err != nil {
+		return StatusFailure
+	}
+	return StatusSuccess
+}
+
+// RunHook runs command, the shell command of the hook name, in workDir with
+// the runner's environment and template data. MOTF_HOOK is set to name, and
+// vars are added to the environment.
+func (r *Runner) RunHook(name, command, workDir string, vars map[string]string, stdout, stderr io.Writer) error {
+	if command == "" {
+		return nil
+	}
+
+	env := map[string]string{EnvHook: name}
+	maps.Copy(env, vars)
+
+	if r.DryRun {
+		_, _ = fmt.Fprintf(stdout, "[dry-run] Would run %s hook in %s\n", name, workDir)
+	} else {
+		_, _ = fmt.Fprintf(stdout, "Running %s hook in %s\n", name, workDir)
+	}
+
+	if err := r.runShell(&TaskConfig{Env: env, Template: true}, "", command, workDir, stdout, stderr); err != nil {
+		return fmt.Errorf("%s hook failed: %w", name, err)
+	}
+	return nil
+}
+
+// runWithHooks runs fn between the pre_task and post_task hooks of taskName.
+// A failing pre_task hook stops the task; post_task runs whether the task
+// succeeded or not, with its outcome in MOTF_STATUS.
+func (r *Runner) runWithHooks(taskName, workDir string, stdout, stderr io.Writer, fn func() error) error {
+	vars := map[string]string{EnvTask: taskName}
+	if err := r.RunHook(HookPreTask, r.Hooks[HookPreTask], workDir, vars, stdout, stderr); err != nil {
+		return err
+	}
+
+	err := fn()
+	vars[EnvStatus] = Status(err)
+	if hookErr := r.RunHook(HookPostTask, r.Hooks[HookPostTask], workDir, vars, stdout, stderr); hookErr != nil {
+		return errors.Join(err, hookErr)
+	}
+	return err
+}
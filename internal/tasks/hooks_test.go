@@ -0,0 +1,73 @@
+package tasks
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunner_RunHook(t *testing.T) {
+	r := NewRunner(nil, NewEnvBuilder().WithModuleName("dns").Build())
+	r.Template = TemplateData{ModuleType: "component"}
+
+	var stdout, stderr bytes.Buffer
+	err := r.RunHook("post_plan", `echo "$MOTF_HOOK $MOTF_MODULE_NAME {{ .ModuleType }} $MOTF_STATUS"`, t.TempDir(), map[string]string{EnvStatus: StatusFailure}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("RunHook() error = %v (stderr: %s)", err, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "post_plan dns component failure\n") {
+		t.Errorf("expected the hook environment and template data, got %q", stdout.String())
+	}
+
+	if err := r.RunHook("pre_plan", "exit 3", t.TempDir(), nil, &stdout, &stderr); err == nil || !strings.Contains(err.Error(), "pre_plan hook failed") {
+		t.Errorf("expected a hook failure, got %v", err)
+	}
+
+	// Hooks that aren't set don't run
+	stdout.Reset()
+	if err := r.RunHook("pre_init", "", t.TempDir(), nil, &stdout, &stderr); err != nil || stdout.Len() > 0 {
+		t.Errorf("expected an unset hook to do nothing, got %v, %q", err, stdout.String())
+	}
+}
+
+func TestRunner_TaskHooks(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"fmt":  {Command: "echo fmt"},
+		"docs": {Command: "echo docs", DependsOn: []string{"fmt"}},
+		"fail": {Command: "exit 1"},
+	}, nil)
+	r.Hooks = map[string]string{
+		HookPreTask:  `echo "before $MOTF_TASK"`,
+		HookPostTask: `echo "after $MOTF_TASK $MOTF_STATUS"`,
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := r.RunWithOutput("docs", t.TempDir(), &stdout, &stderr); err != nil {
+		t.Fatalf("task failed: %v (stderr: %s)", err, stderr.String())
+	}
+	// Hooks run once around the task and its dependencies
+	out := stdout.String()
+	before, fmtIdx, after := strings.Index(out, "\nbefore docs\n"), strings.Index(out, "\nfmt\n"), strings.Index(out, "\nafter docs success\n")
+	if before < 0 || fmtIdx < before || after < fmtIdx || strings.Count(out, "\nbefore docs\n") != 1 {
+		t.Errorf("expected hooks around the pipeline, got %q", out)
+	}
+
+	stdout.Reset()
+	if err := r.RunWithOutput("fail", t.TempDir(), &stdout, &stderr); err == nil {
+		t.Error("expected the task to fail")
+	}
+	if !strings.Contains(stdout.String(), "after fail failure") {
+		t.Errorf("expected post_task to run with the failure, got %q", stdout.String())
+	}
+
+	// A failing pre_task hook stops the task
+	r.Hooks[HookPreTask] = "exit 1"
+	stdout.Reset()
+	err := r.RunWithOutput("fmt", t.TempDir(), &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "pre_task hook failed") {
+		t.Errorf("expected pre_task failure, got %v", err)
+	}
+	if strings.Contains(stdout.String(), "Running task") {
+		t.Errorf("expected the task not to run, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,122 @@
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// builtinSteps are the terraform/tofu commands a task step can run
+var builtinSteps = []string{"init", "fmt", "validate", "plan", "test"}
+
+// BuiltinSteps returns the names of the built-in task steps.
+func BuiltinSteps() []string { return append([]string(nil), builtinSteps...) }
+
+// StepRunner runs a built-in step (e.g. "validate") with extra args in dir.
+// It is provided by the caller, since the tasks package doesn't run terraform itself.
+type StepRunner func(step string, args []string, dir string, stdout, stderr io.Writer) error
+
+// Step is a single step of a task: either a built-in terraform/tofu command
+// or a shell command. In YAML, a built-in step can be written as a plain
+// string ("validate") or as a mapping with args; a shell step is a mapping
+// with run (and optionally shell):
+//
+//	steps:
+//	  - init
+//	  - terraform: validate
+//	    args: ["-no-color"]
+//	  - run: tflint --init && tflint
+//	    shell: bash
+type Step struct {
+	Terraform string   `yaml:"terraform"` // Built-in step name
+	Args      []string `yaml:"args"`      // Extra arguments for a built-in step
+	Run       string   `yaml:"run"`       // Shell command
+	Shell     string   `yaml:"shell"`     // Shell for Run (default: the task's shell)
+}
+
+// UnmarshalYAML accepts a built-in step name or a step mapping.
+func (s *Step) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = Step{Terraform: node.Value}
+	} else {
+		type plain Step // Avoids recursing into UnmarshalYAML
+		var p plain
+		if err := node.Decode(&p); err != nil {
+			return err
+		}
+		*s = Step(p)
+	}
+	return s.validate()
+}
+
+// validate checks that the step is either a known built-in or a shell command.
+func (s Step) validate() error {
+	switch {
+	case s.Terraform != "" && s.Run != "":
+		return fmt.Errorf("step cannot set both 'terraform' and 'run'")
+	case s.Run != "":
+		return nil
+	case s.Terraform == "":
+		return fmt.Errorf("step must set 'terraform' or 'run'")
+	case !slices.Contains(builtinSteps, s.Terraform):
+		return fmt.Errorf("unknown step '%s', built-in steps are %s (use 'run: %s' for shell commands)",
+			s.Terraform, strings.Join(builtinSteps, ", "), s.Terraform)
+	default:
+		return nil
+	}
+}
+
+// String describes the step for output, e.g. "validate -no-color" or "run: tflint".
+func (s Step) String() string {
+	if s.Run != "" {
+		return "run: " + s.Run
+	}
+	return strings.TrimSpace(s.Terraform + " " + strings.Join(s.Args, " "))
+}
+
+// runSteps runs the steps of a task in order, stopping at the first failure.
+func (r *Runner) runSteps(taskName string, task *TaskConfig, workDir string, stdout, stderr io.Writer) error {
+	if r.DryRun {
+		_, _ = fmt.Fprintf(stdout, "[dry-run] Would run task '%s' in %s\n", taskName, workDir)
+	} else {
+		_, _ = fmt.Fprintf(stdout, "Running task '%s' in %s\n", taskName, workDir)
+	}
+
+	for i, step := range task.Steps {
+		_, _ = fmt.Fprintf(stdout, "Step %d/%d: %s\n", i+1, len(task.Steps), step)
+
+		var err error
+		if step.Run != "" {
+			shell := step.Shell
+			if shell == "" {
+				shell = task.Shell
+			}
+			err = r.runShell(task, shell, step.Run, workDir, stdout, stderr)
+		} else {
+			err = r.runBuiltin(task, step, workDir, stdout, stderr)
+		}
+		if err != nil {
+			return fmt.Errorf("task '%s' step %d (%s) failed: %w", taskName, i+1, step, err)
+		}
+	}
+	return nil
+}
+
+// runBuiltin renders the args of a built-in step of task and runs it with the runner's StepRunner.
+func (r *Runner) runBuiltin(task *TaskConfig, step Step, workDir string, stdout, stderr io.Writer) error {
+	if r.Steps == nil {
+		return fmt.Errorf("built-in steps are not supported here")
+	}
+	args := make([]string, len(step.Args))
+	for i, arg := range step.Args {
+		rendered, err := r.render(task, arg)
+		if err != nil {
+			return err
+		}
+		args[i] = rendered
+	}
+	return r.Steps(step.Terraform, args, workDir, stdout, stderr)
+}
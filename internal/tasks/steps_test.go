@@ -0,0 +1,134 @@
+package tasks
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestStep_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    Step
+		wantErr bool
+	}{
+		{name: "builtin shorthand", yaml: `validate`, want: Step{Terraform: "validate"}},
+		{name: "builtin with args", yaml: `{terraform: init, args: ["-backend=false"]}`, want: Step{Terraform: "init", Args: []string{"-backend=false"}}},
+		{name: "shell step", yaml: `{run: tflint, shell: bash}`, want: Step{Run: "tflint", Shell: "bash"}},
+		{name: "unknown builtin", yaml: `tflint`, wantErr: true},
+		{name: "both set", yaml: `{terraform: plan, run: echo}`, wantErr: true},
+		{name: "empty mapping", yaml: `{shell: bash}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Step
+			err := yaml.Unmarshal([]byte(tt.yaml), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.String() != tt.want.String() || got.Shell != tt.want.Shell {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskConfig_StepsFromYAML(t *testing.T) {
+	var task TaskConfig
+	data := `
+steps:
+  - init
+  - terraform: validate
+    args: ["-no-color"]
+  - run: tflint --init && tflint
+`
+	if err := yaml.Unmarshal([]byte(data), &task); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	want := []string{"init", "validate -no-color", "run: tflint --init && tflint"}
+	if len(task.Steps) != len(want) {
+		t.Fatalf("got %d steps, want %d", len(task.Steps), len(want))
+	}
+	for i, w := range want {
+		if task.Steps[i].String() != w {
+			t.Errorf("step %d = %q, want %q", i, task.Steps[i], w)
+		}
+	}
+}
+
+func TestRunner_RunSteps(t *testing.T) {
+	var builtins []string
+	r := NewRunner(map[string]*TaskConfig{
+		"ci": {Steps: []Step{
+			{Terraform: "init", Args: []string{"-backend=false"}},
+			{Terraform: "validate"},
+			{Run: "echo linted"},
+		}},
+	}, nil)
+	r.Steps = func(step string, args []string, dir string, stdout, stderr io.Writer) error {
+		builtins = append(builtins, strings.TrimSpace(step+" "+strings.Join(args, " ")))
+		return nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := r.RunWithOutput("ci", t.TempDir(), &stdout, &stderr); err != nil {
+		t.Fatalf("task failed: %v (stderr: %s)", err, stderr.String())
+	}
+
+	if strings.Join(builtins, ",") != "init -backend=false,validate" {
+		t.Errorf("unexpected built-in steps: %v", builtins)
+	}
+	for _, want := range []string{"Step 1/3: init -backend=false", "Step 3/3: run: echo linted", "linted\n"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected output to contain %q, got %q", want, stdout.String())
+		}
+	}
+}
+
+func TestRunner_RunSteps_StopsOnFailure(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"ci": {Steps: []Step{{Terraform: "validate"}, {Run: "echo unreachable"}}},
+	}, nil)
+	r.Steps = func(step string, args []string, dir string, stdout, stderr io.Writer) error {
+		return errors.New("exit status 1")
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := r.RunWithOutput("ci", t.TempDir(), &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "task 'ci' step 1 (validate) failed") {
+		t.Errorf("expected step failure, got %v", err)
+	}
+	if strings.Contains(stdout.String(), "unreachable") {
+		t.Error("expected later steps not to run")
+	}
+}
+
+func TestRunner_RunSteps_NoStepRunner(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"ci": {Steps: []Step{{Terraform: "validate"}}},
+	}, nil)
+
+	var stdout, stderr bytes.Buffer
+	if err := r.RunWithOutput("ci", t.TempDir(), &stdout, &stderr); err == nil {
+		t.Error("expected error for built-in step without a step runner")
+	}
+}
+
+func TestValidateTasks_CommandAndSteps(t *testing.T) {
+	err := ValidateTasks(map[string]*TaskConfig{
+		"ci": {Command: "make ci", Steps: []Step{{Terraform: "validate"}}},
+	})
+	if err == nil {
+		t.Error("expected error for task with both command and steps")
+	}
+}
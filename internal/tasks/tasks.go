@@ -1,18 +1,27 @@
 package tasks
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/procgroup"
 )
 
 // TaskConfig represents a custom task definition
 type TaskConfig struct {
-	Description string `yaml:"description"`
-	Shell       string `yaml:"shell"`
-	Command     string `yaml:"command"`
+	Description string            `yaml:"description"`
+	Shell       string            `yaml:"shell"`
+	Command     string            `yaml:"command"`
+	Env         map[string]string `yaml:"env"`        // Task-specific environment, overrides the global env
+	DependsOn   []string          `yaml:"depends_on"` // Tasks to run before this one, in order
+	Steps       []Step            `yaml:"steps"`      // Built-in and shell steps, run instead of Command
+	Template    bool              `yaml:"template"`   // Render Command and Steps as templates, e.g. {{ .ModuleName }}
 }
 
 // ShellConfig defines how to invoke a shell
@@ -55,8 +64,21 @@ func GetShellArgs(shell, script string) (binary string, args []string, err error
 
 // Runner executes custom tasks
 type Runner struct {
-	Tasks map[string]*TaskConfig
-	Env   []string // Environment variables for task execution (includes MOTF_* built-ins)
+	Tasks  map[string]*TaskConfig
+	Env    []string   // Environment variables for task execution (includes MOTF_* built-ins)
+	DryRun bool       // Print the resolved shell command instead of executing it
+	Steps  StepRunner // Runs built-in task steps (init, validate, ...); nil disables them
+
+	// Hooks are shell commands by hook name; pre_task and post_task run
+	// around each task run, once for a task and its dependencies
+	Hooks map[string]string
+
+	// Template is the data for template variables in commands, e.g. {{ .ModuleName }}
+	Template TemplateData
+
+	// Context stops running shell commands when done; nil means never. Like
+	// terraform commands, they run in their own process group.
+	Context context.Context
 }
 
 // NewRunner creates a new task runner with the given task definitions
@@ -86,26 +108,163 @@ func (r *Runner) Run(taskName, workDir string) error {
 	return r.RunWithOutput(taskName, workDir, os.Stdout, os.Stderr)
 }
 
-// RunWithOutput executes a task with custom output writers
+// RunWithOutput executes a task with custom output writers. Dependencies
+// (depends_on) run first, each once, in dependency order; the first failure
+// stops the pipeline. The pre_task and post_task hooks run around the
+// pipeline.
 func (r *Runner) RunWithOutput(taskName, workDir string, stdout, stderr io.Writer) error {
+	order, err := r.Plan(taskName)
+	if err != nil {
+		return err
+	}
+
+	return r.runWithHooks(taskName, workDir, stdout, stderr, func() error {
+		for _, name := range order {
+			if err := r.runTask(name, workDir, stdout, stderr); err != nil {
+				if name != taskName {
+					return fmt.Errorf("dependency '%s' of task '%s' failed: %w", name, taskName, err)
+				}
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Plan returns the tasks to run for taskName in execution order: its
+// dependencies (depends_on, recursively), then the task itself. Each task
+// appears once. Returns an error for unknown tasks and dependency cycles.
+func (r *Runner) Plan(taskName string) ([]string, error) {
+	var order []string
+	done := make(map[string]bool)
+	var visiting []string // Current dependency path, for cycle detection
+
+	var visit func(name, dependent string) error
+	visit = func(name, dependent string) error {
+		if done[name] {
+			return nil
+		}
+		for i, v := range visiting {
+			if v == name {
+				cycle := append(append([]string(nil), visiting[i:]...), name)
+				return fmt.Errorf("task dependency cycle: %s", strings.Join(cycle, " -> "))
+			}
+		}
+
+		task := r.GetTask(name)
+		if task == nil {
+			if dependent != "" {
+				return fmt.Errorf("task '%s' depends on unknown task '%s'", dependent, name)
+			}
+			return fmt.Errorf("task '%s' not found", name)
+		}
+
+		visiting = append(visiting, name)
+		for _, dep := range task.DependsOn {
+			if err := visit(dep, name); err != nil {
+				return err
+			}
+		}
+		visiting = visiting[:len(visiting)-1]
+
+		done[name] = true
+		order = append(order, name)
+		return nil
+	}
+
+	if err := visit(taskName, ""); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ValidateTasks checks that no task sets both command and steps, that every
+// depends_on entry refers to a defined task, and that there are no dependency
+// cycles. Templates are checked by ValidateTemplates.
+func ValidateTasks(taskConfigs map[string]*TaskConfig) error {
+	r := NewRunner(taskConfigs, nil)
+	names := r.ListTasks()
+	sort.Strings(names)
+	for _, name := range names {
+		task := taskConfigs[name]
+		if task == nil {
+			continue
+		}
+		if task.Command != "" && len(task.Steps) > 0 {
+			return fmt.Errorf("task '%s' cannot set both command and steps", name)
+		}
+		if _, err := r.Plan(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runTask executes a single task, without its dependencies.
+func (r *Runner) runTask(taskName, workDir string, stdout, stderr io.Writer) error {
 	task := r.GetTask(taskName)
 	if task == nil {
 		return fmt.Errorf("task '%s' not found", taskName)
 	}
 
+	if len(task.Steps) > 0 {
+		return r.runSteps(taskName, task, workDir, stdout, stderr)
+	}
+
 	if task.Command == "" {
+		// A task with only dependencies groups them into a pipeline
+		if len(task.DependsOn) > 0 {
+			return nil
+		}
 		return fmt.Errorf("task '%s' has no command defined", taskName)
 	}
 
-	binary, args, err := GetShellArgs(task.Shell, task.Command)
-	if err != nil {
+	if r.DryRun {
+		_, _ = fmt.Fprintf(stdout, "[dry-run] Would run task '%s' in %s\n", taskName, workDir)
+	} else {
+		_, _ = fmt.Fprintf(stdout, "Running task '%s' in %s\n", taskName, workDir)
+	}
+
+	if err := r.runShell(task, task.Shell, task.Command, workDir, stdout, stderr); err != nil {
 		return fmt.Errorf("task '%s': %w", taskName, err)
 	}
+	return nil
+}
+
+// RunCommand runs the command of task, an ad-hoc task that isn't in the
+// runner's tasks, in workDir with the runner's environment and template data.
+func (r *Runner) RunCommand(task *TaskConfig, workDir string, stdout, stderr io.Writer) error {
+	return r.runShell(task, task.Shell, task.Command, workDir, stdout, stderr)
+}
+
+// runShell renders a shell command of task and runs it in workDir with the task environment.
+func (r *Runner) runShell(task *TaskConfig, shell, command, workDir string, stdout, stderr io.Writer) error {
+	command, err := r.render(task, command)
+	if err != nil {
+		return err
+	}
+
+	binary, args, err := GetShellArgs(shell, command)
+	if err != nil {
+		return err
+	}
+
+	if r.DryRun {
+		_, _ = fmt.Fprintf(stdout, "$ %s\n", formatCommand(binary, args))
+		return nil
+	}
+
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("stopped: %w", context.Cause(ctx))
+	}
 
-	_, _ = fmt.Fprintf(stdout, "Running task '%s' in %s\n", taskName, workDir)
-	_, _ = fmt.Fprintf(stdout, "$ %s\n", task.Command)
+	_, _ = fmt.Fprintf(stdout, "$ %s\n", command)
 
-	cmd := exec.Command(binary, args...) //nolint:gosec // binary and args are from user-defined task configuration
+	cmd := exec.CommandContext(ctx, binary, args...) //nolint:gosec // binary and args are from user-defined task configuration
 	cmd.Dir = workDir
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
@@ -114,6 +273,30 @@ func (r *Runner) RunWithOutput(taskName, workDir string, stdout, stderr io.Write
 	if len(r.Env) > 0 {
 		cmd.Env = r.Env
 	}
+	if len(task.Env) > 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, EnvPairs(task.Env)...)
+	}
 
-	return cmd.Run()
+	if err := procgroup.Run(cmd, procgroup.GracePeriod); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("stopped: %w", context.Cause(ctx))
+		}
+		return err
+	}
+	return nil
+}
+
+// formatCommand renders a command line, quoting arguments that contain whitespace.
+func formatCommand(binary string, args []string) string {
+	parts := []string{binary}
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
 }
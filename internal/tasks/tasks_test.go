@@ -1,7 +1,12 @@
 package tasks
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetShellArgs(t *testing.T) {
@@ -218,3 +223,198 @@ func TestRunner_Run_Errors(t *testing.T) {
 		}
 	})
 }
+
+func TestRunner_DryRun(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"lint": {Shell: "bash", Command: "tflint --init && tflint"},
+	}, nil)
+	r.DryRun = true
+
+	var stdout, stderr bytes.Buffer
+	if err := r.RunWithOutput("lint", "/nonexistent/module", &stdout, &stderr); err != nil {
+		t.Fatalf("dry run should not execute the task, got: %v", err)
+	}
+
+	want := "[dry-run] Would run task 'lint' in /nonexistent/module\n$ bash -c \"tflint --init && tflint\"\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestRunner_TaskEnvOverridesGlobalEnv(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"show": {
+			Command: `echo "$GREETING $TARGET"`,
+			Env:     map[string]string{"TARGET": "task"},
+		},
+	}, NewEnvBuilder().WithEnv(map[string]string{"GREETING": "hello", "TARGET": "global"}).Build())
+
+	var stdout, stderr bytes.Buffer
+	if err := r.RunWithOutput("show", t.TempDir(), &stdout, &stderr); err != nil {
+		t.Fatalf("task failed: %v (stderr: %s)", err, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "hello task") {
+		t.Errorf("expected task env to override global env, got %q", stdout.String())
+	}
+}
+
+func TestEnvPairs_Sorted(t *testing.T) {
+	got := EnvPairs(map[string]string{"B": "2", "A": "1"})
+	if len(got) != 2 || got[0] != "A=1" || got[1] != "B=2" {
+		t.Errorf("EnvPairs() = %v, want [A=1 B=2]", got)
+	}
+}
+
+func TestRunner_Plan(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"fmt":     {Command: "terraform fmt"},
+		"lint":    {Command: "tflint", DependsOn: []string{"fmt"}},
+		"docs":    {Command: "terraform-docs .", DependsOn: []string{"fmt"}},
+		"release": {DependsOn: []string{"lint", "docs"}},
+	}, nil)
+
+	got, err := r.Plan("release")
+	if err != nil {
+		t.Fatalf("Plan returned error: %v", err)
+	}
+	want := []string{"fmt", "lint", "docs", "release"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Plan() = %v, want %v", got, want)
+	}
+}
+
+func TestRunner_Plan_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   map[string]*TaskConfig
+		task    string
+		wantErr string
+	}{
+		{
+			name:    "unknown task",
+			tasks:   map[string]*TaskConfig{},
+			task:    "docs",
+			wantErr: "task 'docs' not found",
+		},
+		{
+			name: "unknown dependency",
+			tasks: map[string]*TaskConfig{
+				"docs": {Command: "terraform-docs .", DependsOn: []string{"fmtt"}},
+			},
+			task:    "docs",
+			wantErr: "task 'docs' depends on unknown task 'fmtt'",
+		},
+		{
+			name: "self dependency",
+			tasks: map[string]*TaskConfig{
+				"fmt": {Command: "terraform fmt", DependsOn: []string{"fmt"}},
+			},
+			task:    "fmt",
+			wantErr: "task dependency cycle: fmt -> fmt",
+		},
+		{
+			name: "indirect cycle",
+			tasks: map[string]*TaskConfig{
+				"a": {Command: "true", DependsOn: []string{"b"}},
+				"b": {Command: "true", DependsOn: []string{"c"}},
+				"c": {Command: "true", DependsOn: []string{"a"}},
+			},
+			task:    "a",
+			wantErr: "task dependency cycle: a -> b -> c -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRunner(tt.tasks, nil).Plan(tt.task)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Plan() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunner_RunWithDependencies(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"fmt":  {Command: "echo fmt"},
+		"docs": {Command: "echo docs", DependsOn: []string{"fmt"}},
+	}, nil)
+
+	var stdout, stderr bytes.Buffer
+	if err := r.RunWithOutput("docs", t.TempDir(), &stdout, &stderr); err != nil {
+		t.Fatalf("task failed: %v (stderr: %s)", err, stderr.String())
+	}
+
+	out := stdout.String()
+	if fmtIdx, docsIdx := strings.Index(out, "\nfmt\n"), strings.Index(out, "\ndocs\n"); fmtIdx < 0 || docsIdx < fmtIdx {
+		t.Errorf("expected fmt to run before docs, got %q", out)
+	}
+}
+
+func TestRunner_FailedDependencyStopsPipeline(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"fmt":  {Command: "exit 1"},
+		"docs": {Command: "echo docs", DependsOn: []string{"fmt"}},
+	}, nil)
+
+	var stdout, stderr bytes.Buffer
+	err := r.RunWithOutput("docs", t.TempDir(), &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "dependency 'fmt' of task 'docs' failed") {
+		t.Errorf("expected dependency failure, got %v", err)
+	}
+	if strings.Contains(stdout.String(), "Running task 'docs'") {
+		t.Error("expected docs not to run after its dependency failed")
+	}
+}
+
+func TestValidateTasks(t *testing.T) {
+	valid := map[string]*TaskConfig{
+		"fmt":  {Command: "terraform fmt"},
+		"docs": {Command: "terraform-docs .", DependsOn: []string{"fmt"}},
+		"nil":  nil,
+	}
+	if err := ValidateTasks(valid); err != nil {
+		t.Errorf("expected valid dependencies, got %v", err)
+	}
+
+	cyclic := map[string]*TaskConfig{
+		"a": {Command: "true", DependsOn: []string{"b"}},
+		"b": {Command: "true", DependsOn: []string{"a"}},
+	}
+	if err := ValidateTasks(cyclic); err == nil {
+		t.Error("expected error for dependency cycle")
+	}
+}
+
+func TestRunner_RunCommand(t *testing.T) {
+	r := NewRunner(nil, nil)
+	r.DryRun = true
+	r.Template = TemplateData{ModuleName: "vnet"}
+
+	var stdout bytes.Buffer
+	if err := r.RunCommand(&TaskConfig{Shell: "bash", Command: "echo {{ .ModuleName }}", Template: true}, "/nonexistent/module", &stdout, &stdout); err != nil {
+		t.Fatalf("RunCommand() error = %v", err)
+	}
+
+	want := "$ bash -c \"echo vnet\"\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestRunner_RunCommand_ContextCanceled(t *testing.T) {
+	r := NewRunner(nil, nil)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	r.Context = ctx
+	time.AfterFunc(100*time.Millisecond, func() { cancel(errors.New("interrupted")) })
+
+	start := time.Now()
+	var stdout bytes.Buffer
+	err := r.RunCommand(&TaskConfig{Shell: "sh", Command: "sleep 10"}, t.TempDir(), &stdout, &stdout)
+	if err == nil || err.Error() != "stopped: interrupted" {
+		t.Fatalf("expected the command to be stopped, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the command to be stopped, took %s", elapsed)
+	}
+}
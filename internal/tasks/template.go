@@ -0,0 +1,64 @@
+package tasks
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the context available to task commands as template
+// variables, e.g. {{ .ModuleName }}.
+type TemplateData struct {
+	ModuleName string // Module name, e.g. "storage-account"
+	ModulePath string // Absolute path of the module directory
+	ModuleType string // component, base, or project
+	Example    string // Example name when running on an example, otherwise empty
+}
+
+// parseTemplate parses a task command as a template.
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("command").Option("missingkey=error").Parse(text)
+}
+
+// render expands template variables in text, a command of task, with the
+// runner's template data. Text is returned unchanged unless the task sets
+// template, or when it has no template actions.
+func (r *Runner) render(task *TaskConfig, text string) (string, error) {
+	if !task.Template || !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, r.Template); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// ValidateTemplates checks that the commands of the tasks that set template
+// parse as templates. Unknown variables are only found when a task runs.
+func ValidateTemplates(taskConfigs map[string]*TaskConfig) error {
+	names := slices.Sorted(maps.Keys(taskConfigs))
+	for _, name := range names {
+		task := taskConfigs[name]
+		if task == nil || !task.Template {
+			continue
+		}
+		texts := []string{task.Command}
+		for _, step := range task.Steps {
+			texts = append(texts, step.Run)
+			texts = append(texts, step.Args...)
+		}
+		for _, text := range texts {
+			if _, err := parseTemplate(text); err != nil {
+				return fmt.Errorf("task '%s' has an invalid template: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package tasks
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+var testTemplateData = TemplateData{
+	ModuleName: "storage-account",
+	ModulePath: "/repo/components/azurerm/storage-account",
+	ModuleType: "component",
+	Example:    "basic",
+}
+
+func TestRunner_RendersTemplateVariables(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"docs": {Command: "echo {{ .ModuleType }}/{{ .ModuleName }} {{ .Example }} {{ .ModulePath }}", Template: true},
+	}, nil)
+	r.DryRun = true
+	r.Template = testTemplateData
+
+	var stdout bytes.Buffer
+	if err := r.RunWithOutput("docs", "/nonexistent/module", &stdout, io.Discard); err != nil {
+		t.Fatalf("RunWithOutput() error = %v", err)
+	}
+
+	want := `$ sh -c "echo component/storage-account basic /repo/components/azurerm/storage-account"`
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf("stdout = %q, want it to contain %q", stdout.String(), want)
+	}
+}
+
+func TestRunner_RendersStepTemplates(t *testing.T) {
+	var gotArgs []string
+	r := NewRunner(map[string]*TaskConfig{
+		"ci": {Steps: []Step{
+			{Terraform: "plan", Args: []string{"-var-file={{ .Example }}.tfvars"}},
+			{Run: "echo {{ .ModuleName }}"},
+		}, Template: true},
+	}, nil)
+	r.DryRun = true
+	r.Template = testTemplateData
+	r.Steps = func(step string, args []string, dir string, stdout, stderr io.Writer) error {
+		gotArgs = args
+		return nil
+	}
+
+	var stdout bytes.Buffer
+	if err := r.RunWithOutput("ci", "/nonexistent/module", &stdout, io.Discard); err != nil {
+		t.Fatalf("RunWithOutput() error = %v", err)
+	}
+
+	if len(gotArgs) != 1 || gotArgs[0] != "-var-file=basic.tfvars" {
+		t.Errorf("step args = %v, want [-var-file=basic.tfvars]", gotArgs)
+	}
+	if !strings.Contains(stdout.String(), `$ sh -c "echo storage-account"`) {
+		t.Errorf("stdout = %q, want rendered run step", stdout.String())
+	}
+}
+
+func TestRunner_UnknownTemplateVariable(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"bad": {Command: "echo {{ .ModuleVersion }}", Template: true},
+	}, nil)
+	r.DryRun = true
+
+	err := r.RunWithOutput("bad", "/nonexistent/module", io.Discard, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "invalid template") {
+		t.Fatalf("expected invalid template error, got %v", err)
+	}
+}
+
+func TestRunner_CommandWithoutTemplateUnchanged(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"awk": {Command: "awk '{ print $1 }' file"},
+	}, nil)
+	r.DryRun = true
+
+	var stdout bytes.Buffer
+	if err := r.RunWithOutput("awk", "/nonexistent/module", &stdout, io.Discard); err != nil {
+		t.Fatalf("RunWithOutput() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "{ print $1 }") {
+		t.Errorf("stdout = %q, want command unchanged", stdout.String())
+	}
+}
+
+func TestRunner_TemplatesAreOptIn(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"inspect": {Command: "docker inspect --format '{{.Id}}' app"},
+	}, nil)
+	r.DryRun = true
+	r.Template = testTemplateData
+
+	var stdout bytes.Buffer
+	if err := r.RunWithOutput("inspect", "/nonexistent/module", &stdout, io.Discard); err != nil {
+		t.Fatalf("RunWithOutput() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "{{.Id}}") {
+		t.Errorf("stdout = %q, want command unchanged", stdout.String())
+	}
+}
+
+func TestRunner_EscapedTemplateAction(t *testing.T) {
+	r := NewRunner(map[string]*TaskConfig{
+		"inspect": {Command: `docker inspect --format '{{"{{"}}.Id}}' {{ .ModuleName }}`, Template: true},
+	}, nil)
+	r.DryRun = true
+	r.Template = testTemplateData
+
+	var stdout bytes.Buffer
+	if err := r.RunWithOutput("inspect", "/nonexistent/module", &stdout, io.Discard); err != nil {
+		t.Fatalf("RunWithOutput() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "{{.Id}}' storage-account") {
+		t.Errorf("stdout = %q, want escaped action kept", stdout.String())
+	}
+}
+
+func TestValidateTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   map[string]*TaskConfig
+		wantErr string
+	}{
+		{name: "valid", tasks: map[string]*TaskConfig{"docs": {Command: "echo {{ .ModuleName }}", Template: true}}},
+		{name: "not a template", tasks: map[string]*TaskConfig{"broken": {Command: "echo {{ .ModuleName"}}},
+		{name: "invalid command", tasks: map[string]*TaskConfig{"broken": {Command: "echo {{ .ModuleName", Template: true}}, wantErr: "task 'broken' has an invalid template"},
+		{name: "invalid step", tasks: map[string]*TaskConfig{"broken": {Steps: []Step{{Run: "echo {{"}}, Template: true}}, wantErr: "task 'broken' has an invalid template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplates(tt.tasks)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateTemplates() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateTemplates() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTasks_IgnoresTemplates(t *testing.T) {
+	if err := ValidateTasks(map[string]*TaskConfig{
+		"broken": {Command: "echo {{ .ModuleName", Template: true},
+	}); err != nil {
+		t.Fatalf("ValidateTasks() error = %v, templates are checked by ValidateTemplates", err)
+	}
+}
@@ -0,0 +1,156 @@
+package terraform
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/logging"
+	"github.com/TechnicallyJoe/terraform-motf/internal/toolchain"
+)
+
+// fallbackWarning makes sure the warning that binary: auto fell back to tofu
+// is shown once per run, not once per module
+var fallbackWarning sync.Once
+
+// resolveKey is what the binary Resolve returns depends on
+type resolveKey struct {
+	binary, version, toolchainDir, path string
+}
+
+type resolveResult struct {
+	binary string
+	err    error
+}
+
+// resolveCache memoizes Resolve, so a run over many modules with the same
+// settings looks up the binary, and runs it for its version, only once
+var (
+	resolveCacheMu sync.Mutex
+	resolveCache   = map[resolveKey]resolveResult{}
+)
+
+// ResolveBinary returns the terraform/tofu binary to run for the binary
+// setting. terraform and tofu must be on PATH; when only the other one is,
+// the error suggests switching to it or to auto. auto runs terraform, or
+// tofu when terraform isn't on PATH, with a warning on stderr.
+func ResolveBinary(setting string) (string, error) {
+	if setting == config.BinaryAuto {
+		if _, err := exec.LookPath("terraform"); err == nil {
+			return "terraform", nil
+		}
+		if _, err := exec.LookPath("tofu"); err == nil {
+			fallbackWarning.Do(func() {
+				logging.Warnf("terraform not found on PATH, using tofu (binary: auto)")
+			})
+			return "tofu", nil
+		}
+		return "", fmt.Errorf("neither terraform nor tofu found on PATH")
+	}
+
+	if _, err := exec.LookPath(setting); err == nil {
+		return setting, nil
+	}
+	other := "tofu"
+	if setting == "tofu" {
+		other = "terraform"
+	}
+	if _, err := exec.LookPath(other); err == nil {
+		return "", fmt.Errorf("%s not found on PATH, but %s is: set 'binary: %s' in %s, or 'binary: %s' to use whichever is installed",
+			setting, other, other, config.ConfigFile, config.BinaryAuto)
+	}
+	return "", fmt.Errorf("%s not found on PATH", setting)
+}
+
+// Resolve returns the terraform/tofu binary to run for cfg. Without
+// binary_version, that's the binary of ResolveBinary. With it, it's the path
+// of the newest release installed by 'motf toolchain install' that satisfies
+// it, or else the binary on PATH when its version does. Results are
+// memoized per binary, binary_version, toolchain directory, and PATH.
+func Resolve(cfg *config.Config) (string, error) {
+	key := resolveKey{binary: cfg.Binary, version: cfg.BinaryVersion, path: os.Getenv("PATH")}
+	if cfg.BinaryVersion != "" {
+		key.toolchainDir, _ = toolchain.Dir()
+	}
+
+	resolveCacheMu.Lock()
+	defer resolveCacheMu.Unlock()
+	if r, ok := resolveCache[key]; ok {
+		return r.binary, r.err
+	}
+	binary, err := resolve(cfg)
+	resolveCache[key] = resolveResult{binary, err}
+	return binary, err
+}
+
+// resolve is Resolve without memoization.
+func resolve(cfg *config.Config) (string, error) {
+	if cfg.BinaryVersion == "" {
+		return ResolveBinary(cfg.Binary)
+	}
+
+	if dir, err := toolchain.Dir(); err == nil {
+		names := []string{cfg.Binary}
+		if cfg.Binary == config.BinaryAuto {
+			names = []string{"terraform", "tofu"}
+		}
+		for _, name := range names {
+			path, _, err := toolchain.Installed(dir, name, cfg.BinaryVersion)
+			if err != nil {
+				return "", err
+			}
+			if path != "" {
+				return path, nil
+			}
+		}
+	}
+
+	binary, err := ResolveBinary(cfg.Binary)
+	if err != nil {
+		return "", fmt.Errorf("%w: run 'motf toolchain install' to install a release matching binary_version '%s'", err, cfg.BinaryVersion)
+	}
+	v, err := toolchain.Version(binary)
+	if err != nil {
+		return "", err
+	}
+	if ok, err := toolchain.Satisfies(v, cfg.BinaryVersion); err != nil {
+		return "", err
+	} else if !ok {
+		return "", fmt.Errorf("%s %s doesn't match binary_version '%s': run 'motf toolchain install' to install a matching release",
+			binary, v, cfg.BinaryVersion)
+	}
+	return binary, nil
+}
+
+// binary returns the terraform/tofu binary the runner runs, resolved on
+// first use. In dry-run mode, a binary that isn't installed is only printed,
+// so the configured binary (terraform for auto) is returned then.
+func (r *Runner) binary() (string, error) {
+	r.binaryOnce.Do(func() {
+		r.resolved, r.resolveErr = Resolve(r.config)
+		if r.resolveErr != nil && r.DryRun {
+			r.resolved, r.resolveErr = r.config.Binary, nil
+			if r.resolved == config.BinaryAuto {
+				r.resolved = "terraform"
+			}
+		}
+		if r.resolveErr == nil {
+			logging.Debugf("using %s (binary: %s)", r.resolved, r.config.Binary)
+		}
+	})
+	return r.resolved, r.resolveErr
+}
+
+// runBinary runs the terraform/tofu binary with args in dir, like run. The
+// binary is resolved only now, so an error names the command that couldn't run.
+func (r *Runner) runBinary(args []string, dir string, stdout, stderr io.Writer) error {
+	binary, err := r.binary()
+	if err != nil {
+		return fmt.Errorf("can't run %s %s in %s: %w", r.config.Binary, strings.Join(args, " "), dir, err)
+	}
+	return r.run(binary, args, dir, stdout, stderr)
+}
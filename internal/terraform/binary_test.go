@@ -0,0 +1,177 @@
+package terraform
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// onlyOnPath makes the fake binaries names the only executables on PATH.
+func onlyOnPath(t *testing.T, names ...string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\necho \"$0 $*\"\n"), 0755); err != nil {
+			t.Fatalf("failed to write fake %s: %v", name, err)
+		}
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestResolveBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		onPath  []string
+		want    string
+		wantErr string
+	}{
+		{name: "terraform", setting: "terraform", onPath: []string{"terraform", "tofu"}, want: "terraform"},
+		{name: "tofu", setting: "tofu", onPath: []string{"tofu"}, want: "tofu"},
+		{name: "auto prefers terraform", setting: config.BinaryAuto, onPath: []string{"terraform", "tofu"}, want: "terraform"},
+		{name: "auto falls back to tofu", setting: config.BinaryAuto, onPath: []string{"tofu"}, want: "tofu"},
+		{
+			name: "terraform missing suggests tofu", setting: "terraform", onPath: []string{"tofu"},
+			wantErr: "terraform not found on PATH, but tofu is: set 'binary: tofu' in .motf.yml, or 'binary: auto'",
+		},
+		{name: "tofu missing", setting: "tofu", wantErr: "tofu not found on PATH"},
+		{name: "auto with neither", setting: config.BinaryAuto, wantErr: "neither terraform nor tofu found on PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			onlyOnPath(t, tt.onPath...)
+			got, err := ResolveBinary(tt.setting)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveBinary(%q) error = %v, want %q", tt.setting, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ResolveBinary(%q) = %q, %v, want %q", tt.setting, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_AutoBinary(t *testing.T) {
+	onlyOnPath(t, "tofu")
+	t.Cleanup(func() { fallbackWarning = sync.Once{} })
+
+	runner := NewRunner(&config.Config{Binary: config.BinaryAuto})
+	var stdout, stderr bytes.Buffer
+	if err := runner.RunValidateWithOutput(t.TempDir(), &stdout, &stderr); err != nil {
+		t.Fatalf("RunValidateWithOutput() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "tofu validate") {
+		t.Errorf("expected tofu to run, got:\n%s", stdout.String())
+	}
+	if runner.Binary() != "tofu" {
+		t.Errorf("Binary() = %q, want tofu", runner.Binary())
+	}
+}
+
+func TestRunner_MissingBinary(t *testing.T) {
+	onlyOnPath(t, "tofu")
+
+	runner := NewRunner(&config.Config{Binary: "terraform"})
+	var stdout, stderr bytes.Buffer
+	err := runner.RunPlanWithOutput(t.TempDir(), &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "set 'binary: tofu'") {
+		t.Fatalf("expected an error suggesting tofu, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "can't run terraform plan in ") {
+		t.Errorf("expected the error to name the command, got %v", err)
+	}
+
+	// A dry run only prints the command, so it doesn't need the binary
+	runner = NewRunner(&config.Config{Binary: config.BinaryAuto})
+	runner.DryRun = true
+	t.Setenv("PATH", "")
+	stdout.Reset()
+	if err := runner.RunPlanWithOutput("/tmp/mod", &stdout, &stderr); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if !strings.HasPrefix(stdout.String(), "[dry-run] Would run terraform plan") {
+		t.Errorf("dry run output = %q", stdout.String())
+	}
+}
+
+// fakeVersion writes a fake binary that reports version v to path.
+func fakeVersion(t *testing.T, path, v string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho '{\"terraform_version\": \"" + v + "\"}'\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake binary: %v", err)
+	}
+}
+
+func TestResolve_BinaryVersion(t *testing.T) {
+	onlyOnPath(t)
+	pathDir := strings.Split(os.Getenv("PATH"), string(os.PathListSeparator))[0]
+	fakeVersion(t, filepath.Join(pathDir, "terraform"), "1.5.7")
+	toolchainDir := t.TempDir()
+	t.Setenv("MOTF_TOOLCHAIN_DIR", toolchainDir)
+
+	got, err := Resolve(&config.Config{Binary: "terraform", BinaryVersion: "~> 1.5.0"})
+	if err != nil || got != "terraform" {
+		t.Errorf("Resolve() = %q, %v, want terraform on PATH", got, err)
+	}
+
+	_, err = Resolve(&config.Config{Binary: "terraform", BinaryVersion: ">= 1.9"})
+	if err == nil || !strings.Contains(err.Error(), "terraform 1.5.7 doesn't match binary_version '>= 1.9': run 'motf toolchain install'") {
+		t.Errorf("expected a version mismatch error, got %v", err)
+	}
+
+	_, err = Resolve(&config.Config{Binary: "tofu", BinaryVersion: ">= 1.9"})
+	if err == nil || !strings.Contains(err.Error(), "install a release matching binary_version '>= 1.9'") {
+		t.Errorf("expected a missing binary error suggesting an install, got %v", err)
+	}
+
+	// An installed release that matches is preferred over PATH
+	installed := filepath.Join(toolchainDir, "terraform", "1.9.5", "terraform")
+	fakeVersion(t, installed, "1.9.5")
+	got, err = Resolve(&config.Config{Binary: config.BinaryAuto, BinaryVersion: ">= 1.9"})
+	if err != nil || got != installed {
+		t.Errorf("Resolve() = %q, %v, want %q", got, err, installed)
+	}
+}
+
+func TestResolve_Memoized(t *testing.T) {
+	onlyOnPath(t)
+	pathDir := strings.Split(os.Getenv("PATH"), string(os.PathListSeparator))[0]
+	binary := filepath.Join(pathDir, "tofu")
+	fakeVersion(t, binary, "1.8.0")
+	t.Setenv("MOTF_TOOLCHAIN_DIR", t.TempDir())
+
+	cfg := &config.Config{Binary: "tofu", BinaryVersion: "~> 1.8.0"}
+	if got, err := Resolve(cfg); err != nil || got != "tofu" {
+		t.Fatalf("Resolve() = %q, %v, want tofu on PATH", got, err)
+	}
+
+	// The same settings aren't looked up again
+	if err := os.Remove(binary); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Resolve(cfg); err != nil || got != "tofu" {
+		t.Errorf("Resolve() = %q, %v, want the memoized tofu", got, err)
+	}
+
+	// Other settings are
+	if _, err := Resolve(&config.Config{Binary: "tofu", BinaryVersion: "~> 1.8.1"}); err == nil {
+		t.Error("expected an error for a tofu that isn't on PATH anymore")
+	}
+}
@@ -0,0 +1,106 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoFeatures is returned by the compliance test engine for a module
+// without feature files
+var ErrNoFeatures = errors.New("no feature files")
+
+// runComplianceWithOutput runs the compliance test engine: it plans the module,
+// converts the plan to JSON, and runs the configured BDD tool against it with
+// the module's feature files.
+func (r *Runner) runComplianceWithOutput(dir string, stdout, stderr io.Writer, extraArgs []string) error {
+	compliance := r.config.Test.Compliance
+	features := filepath.Join(dir, filepath.FromSlash(compliance.GetFeatures()))
+	files, err := featureFiles(features)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("%w in %s", ErrNoFeatures, compliance.GetFeatures())
+	}
+
+	tmpDir, err := os.MkdirTemp("", "motf-compliance-")
+	if err != nil {
+		return fmt.Errorf("failed to create plan directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	planJSON := filepath.Join(tmpDir, "plan.json")
+
+	data, err := r.PlanJSON(dir, stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("failed to plan for compliance tests: %w", err)
+	}
+	if !r.DryRun {
+		if err := os.WriteFile(planJSON, data, 0600); err != nil {
+			return fmt.Errorf("failed to write plan JSON: %w", err)
+		}
+	}
+
+	args := complianceArgs(compliance.GetCommand(), planJSON, features)
+	args = append(args, strings.Fields(r.config.Test.Args)...)
+	args = append(args, extraArgs...)
+	return r.runCommand("test", args[0], args[1:], dir, stdout, stderr)
+}
+
+// PlanJSON plans the module in dir into a temporary plan file and returns the
+// plan as JSON, as terraform/tofu show -json outputs it. In dry-run mode, the
+// plan is only printed and nil is returned.
+func (r *Runner) PlanJSON(dir string, stdout, stderr io.Writer) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "motf-plan-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plan directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	planFile := filepath.Join(tmpDir, "plan.tfplan")
+
+	if err := r.runBinary([]string{"plan", "-input=false", "-out=" + planFile}, dir, stdout, stderr); err != nil {
+		return nil, err
+	}
+	if r.DryRun {
+		_, _ = fmt.Fprintf(stdout, "[dry-run] Would run %s show -json %s in %s\n", r.Binary(), planFile, dir)
+		return nil, nil
+	}
+	return r.output(dir, "show", "-json", planFile)
+}
+
+// complianceArgs splits the compliance command into arguments, replacing
+// {plan} and {features}.
+func complianceArgs(command, planJSON, features string) []string {
+	args := strings.Fields(command)
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, "{plan}", planJSON)
+		args[i] = strings.ReplaceAll(arg, "{features}", features)
+	}
+	return args
+}
+
+// featureFiles returns the .feature files in dir and its subdirectories, or
+// none if dir doesn't exist.
+func featureFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == dir {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".feature") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find feature files: %w", err)
+	}
+	return files, nil
+}
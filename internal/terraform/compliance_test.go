@@ -0,0 +1,93 @@
+package terraform
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+// writeFeature writes a feature file to the tests/features directory of dir.
+func writeFeature(t *testing.T, dir, name string) {
+	t.Helper()
+	features := filepath.Join(dir, "tests", "features")
+	if err := os.MkdirAll(features, 0755); err != nil {
+		t.Fatalf("failed to create features directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(features, name), []byte("Feature: Tags\n"), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestRunner_DryRun_ComplianceEngine(t *testing.T) {
+	dir := t.TempDir()
+	writeFeature(t, dir, "tags.feature")
+	cfg := config.DefaultConfig()
+	cfg.Test.Engine = "compliance"
+	cfg.Test.Args = "--no-ansi"
+	runner := NewRunner(cfg)
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	if err := runner.RunTestWithOutput(dir, &stdout, &stdout, "-q"); err != nil {
+		t.Fatalf("dry run should not execute the commands, got: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected plan, show, and test lines, got:\n%s", stdout.String())
+	}
+	if !strings.HasPrefix(lines[0], "[dry-run] Would run terraform plan -input=false -out=") {
+		t.Errorf("expected the plan first, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "show -json ") {
+		t.Errorf("expected the plan JSON second, got %q", lines[1])
+	}
+	features := filepath.Join(dir, "tests", "features")
+	if !strings.HasPrefix(lines[2], "[dry-run] Would run terraform-compliance -p ") ||
+		!strings.HasSuffix(lines[2], "plan.json -f "+features+" --no-ansi -q in "+dir) {
+		t.Errorf("unexpected compliance command %q", lines[2])
+	}
+}
+
+func TestRunner_ComplianceEngine_NoFeatures(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Test.Engine = "compliance"
+	runner := NewRunner(cfg)
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	err := runner.RunTestWithOutput(t.TempDir(), &stdout, &stdout)
+	if !errors.Is(err, ErrNoFeatures) {
+		t.Fatalf("expected ErrNoFeatures, got %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected nothing to run without feature files, got %q", stdout.String())
+	}
+}
+
+func TestComplianceArgs(t *testing.T) {
+	got := complianceArgs("behave --define plan={plan} {features}", "/tmp/plan.json", "/m/tests/features")
+	want := "behave --define plan=/tmp/plan.json /m/tests/features"
+	if strings.Join(got, " ") != want {
+		t.Errorf("complianceArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestFeatureFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFeature(t, dir, "tags.feature")
+	writeFeature(t, dir, "README.md")
+
+	files, err := featureFiles(filepath.Join(dir, "tests", "features"))
+	if err != nil {
+		t.Fatalf("featureFiles() error = %v", err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != "tags.feature" {
+		t.Errorf("featureFiles() = %v, want only tags.feature", files)
+	}
+}
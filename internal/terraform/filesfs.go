@@ -0,0 +1,106 @@
+package terraform
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+)
+
+// filesFS is a read-only fs.FS of files in a single directory, ".", keyed
+// by name, e.g. the files of a module read from a git commit.
+type filesFS map[string][]byte
+
+// Open opens the file name, or the directory ".".
+func (f filesFS) Open(name string) (fs.File, error) {
+	if name == "." {
+		entries, _ := f.ReadDir(".")
+		return &filesFSDir{entries: entries}, nil
+	}
+	data, ok := f[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &filesFSFile{info: fileInfo{name: name, size: int64(len(data))}, r: bytes.NewReader(data)}, nil
+}
+
+// ReadFile returns the contents of the file name.
+func (f filesFS) ReadFile(name string) ([]byte, error) {
+	data, ok := f[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return slices.Clone(data), nil
+}
+
+// ReadDir returns the files of the directory ".", sorted by name.
+func (f filesFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(f))
+	for name, data := range f {
+		entries = append(entries, fs.FileInfoToDirEntry(fileInfo{name: name, size: int64(len(data))}))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// filesFSFile is an open file of a filesFS.
+type filesFSFile struct {
+	info fileInfo
+	r    *bytes.Reader
+}
+
+func (f *filesFSFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *filesFSFile) Read(p []byte) (int, error) { return f.r.Read(p) }
+func (f *filesFSFile) Close() error               { return nil }
+
+// filesFSDir is the open directory of a filesFS.
+type filesFSDir struct {
+	entries []fs.DirEntry // Not read yet
+}
+
+func (d *filesFSDir) Stat() (fs.FileInfo, error) { return fileInfo{name: ".", dir: true}, nil }
+func (d *filesFSDir) Close() error               { return nil }
+
+func (d *filesFSDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: ".", Err: fs.ErrInvalid}
+}
+
+// ReadDir returns the next n entries, or all remaining ones when n <= 0.
+func (d *filesFSDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 || n > len(d.entries) {
+		if n > 0 && len(d.entries) == 0 {
+			return nil, io.EOF
+		}
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+// fileInfo describes a file or the directory of a filesFS.
+type fileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) ModTime() time.Time { return time.Time{} }
+func (i fileInfo) IsDir() bool        { return i.dir }
+func (i fileInfo) Sys() any           { return nil }
+
+func (i fileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
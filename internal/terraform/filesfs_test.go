@@ -0,0 +1,16 @@
+package terraform
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestFilesFS(t *testing.T) {
+	fsys := filesFS{
+		"main.tf":      []byte("resource \"null_resource\" \"this\" {}\n"),
+		"variables.tf": []byte("variable \"name\" {}\n"),
+	}
+	if err := fstest.TestFS(fsys, "main.tf", "variables.tf"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,112 @@
+package terraform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FormatJSON returns the contents of a .tf.json file indented with two spaces
+// and ending with a newline.
+func FormatJSON(src []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(src), "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// fmtOptions are the terraform fmt flags that also apply to .tf.json files
+type fmtOptions struct {
+	check     bool // Report unformatted files as an error instead of writing them
+	write     bool
+	list      bool // Print the names of unformatted files
+	recursive bool
+}
+
+// parseFmtOptions reads the fmt flags from the extra arguments.
+func parseFmtOptions(args []string) fmtOptions {
+	opts := fmtOptions{write: true, list: true}
+	for _, arg := range args {
+		switch strings.TrimLeft(arg, "-") {
+		case "check":
+			opts.check = true
+		case "write=false":
+			opts.write = false
+		case "list=false":
+			opts.list = false
+		case "recursive":
+			opts.recursive = true
+		}
+	}
+	if opts.check {
+		opts.write = false
+	}
+	return opts
+}
+
+// formatJSONFiles formats the .tf.json files in dir, which terraform/tofu fmt
+// skips. Like fmt, it lists the files it changes and honors -check, -write,
+// -list, and -recursive in args.
+func formatJSONFiles(dir string, args []string, stdout io.Writer) error {
+	opts := parseFmtOptions(args)
+
+	var unformatted []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && (!opts.recursive || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".tf.json") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		src, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return err
+		}
+		formatted, err := FormatJSON(src)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", rel, err)
+		}
+		if bytes.Equal(src, formatted) {
+			return nil
+		}
+
+		unformatted = append(unformatted, rel)
+		if opts.list {
+			_, _ = fmt.Fprintln(stdout, rel)
+		}
+		if opts.write {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(p, formatted, info.Mode().Perm())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.check && len(unformatted) > 0 {
+		return fmt.Errorf("%d .tf.json file(s) are not formatted", len(unformatted))
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package terraform
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+const unformattedJSON = `{"variable": {"name": {"type": "string"}}}`
+
+const formattedJSON = `{
+  "variable": {
+    "name": {
+      "type": "string"
+    }
+  }
+}
+`
+
+func TestFormatJSON(t *testing.T) {
+	got, err := FormatJSON([]byte(unformattedJSON))
+	if err != nil {
+		t.Fatalf("FormatJSON() error = %v", err)
+	}
+	if string(got) != formattedJSON {
+		t.Errorf("FormatJSON() =\n%s\nwant\n%s", got, formattedJSON)
+	}
+
+	if _, err := FormatJSON([]byte(`{"variable":`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func writeJSONModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"main.tf.json":                 unformattedJSON,
+		"variables.tf.json":            formattedJSON,
+		"main.tf":                      "variable   \"x\" {}\n",
+		"examples/basic/main.tf.json":  unformattedJSON,
+		".terraform/modules/x.tf.json": unformattedJSON,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestFormatJSONFiles(t *testing.T) {
+	dir := writeJSONModule(t)
+
+	var stdout bytes.Buffer
+	if err := formatJSONFiles(dir, nil, &stdout); err != nil {
+		t.Fatalf("formatJSONFiles() error = %v", err)
+	}
+	if stdout.String() != "main.tf.json\n" {
+		t.Errorf("listed files = %q, want only main.tf.json", stdout.String())
+	}
+	if got := readFile(t, filepath.Join(dir, "main.tf.json")); got != formattedJSON {
+		t.Errorf("main.tf.json =\n%s\nwant\n%s", got, formattedJSON)
+	}
+	if got := readFile(t, filepath.Join(dir, "examples", "basic", "main.tf.json")); got != unformattedJSON {
+		t.Error("expected subdirectories to be left alone without -recursive")
+	}
+	if got := readFile(t, filepath.Join(dir, "main.tf")); got != "variable   \"x\" {}\n" {
+		t.Error("expected .tf files to be left to terraform fmt")
+	}
+}
+
+func TestFormatJSONFiles_Recursive(t *testing.T) {
+	dir := writeJSONModule(t)
+
+	var stdout bytes.Buffer
+	if err := formatJSONFiles(dir, []string{"-recursive"}, &stdout); err != nil {
+		t.Fatalf("formatJSONFiles() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "examples", "basic", "main.tf.json")); got != formattedJSON {
+		t.Errorf("examples/basic/main.tf.json =\n%s\nwant\n%s", got, formattedJSON)
+	}
+	if got := readFile(t, filepath.Join(dir, ".terraform", "modules", "x.tf.json")); got != unformattedJSON {
+		t.Error("expected hidden directories to be skipped")
+	}
+}
+
+func TestFormatJSONFiles_Check(t *testing.T) {
+	dir := writeJSONModule(t)
+
+	var stdout bytes.Buffer
+	err := formatJSONFiles(dir, []string{"-check"}, &stdout)
+	if err == nil || !strings.Contains(err.Error(), "1 .tf.json file(s) are not formatted") {
+		t.Fatalf("expected an error for unformatted files, got %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "main.tf.json")); got != unformattedJSON {
+		t.Error("expected -check not to write files")
+	}
+}
+
+func TestRunner_DryRun_SkipsJSONFormatting(t *testing.T) {
+	dir := writeJSONModule(t)
+	runner := NewRunner(config.DefaultConfig())
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	if err := runner.RunFmtWithOutput(dir, &stdout, &stdout); err != nil {
+		t.Fatalf("RunFmtWithOutput() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "main.tf.json")); got != unformattedJSON {
+		t.Error("expected dry-run not to format .tf.json files")
+	}
+}
@@ -0,0 +1,56 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoTests is returned by the terraform and tofu test engines for a module
+// without test files
+var ErrNoTests = errors.New("no test files")
+
+// defaultTestDirectory is where terraform/tofu test looks for test files
+// besides the module directory
+const defaultTestDirectory = "tests"
+
+// testFileSuffixes are the extensions of native test files
+var testFileSuffixes = []string{".tftest.hcl", ".tftest.json"}
+
+// HasNativeTests reports whether the module in dir has test files for
+// terraform/tofu test: in dir itself, or in the test directory, which args
+// can change with -test-directory.
+func HasNativeTests(dir string, args []string) (bool, error) {
+	testDir := defaultTestDirectory
+	for _, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "-test-directory="); ok {
+			testDir = value
+		}
+	}
+	for _, d := range []string{dir, filepath.Join(dir, testDir)} {
+		entries, err := os.ReadDir(d)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to find test files: %w", err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && isNativeTestFile(entry.Name()) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func isNativeTestFile(name string) bool {
+	for _, suffix := range testFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package terraform
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+)
+
+func TestHasNativeTests(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		args  []string
+		want  bool
+	}{
+		{"none", []string{"main.tf"}, nil, false},
+		{"module directory", []string{"main.tf", "main.tftest.hcl"}, nil, true},
+		{"tests directory", []string{"tests/basic.tftest.hcl"}, nil, true},
+		{"json", []string{"tests/basic.tftest.json"}, nil, true},
+		{"nested", []string{"tests/unit/basic.tftest.hcl"}, nil, false},
+		{"test directory arg", []string{"spec/basic.tftest.hcl"}, []string{"-verbose", "-test-directory=spec"}, true},
+		{"other test directory", []string{"tests/basic.tftest.hcl"}, []string{"-test-directory=spec"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				path := filepath.Join(dir, filepath.FromSlash(f))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := HasNativeTests(dir, tt.args)
+			if err != nil {
+				t.Fatalf("HasNativeTests() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HasNativeTests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_NativeEngine_NoTests(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Test.Engine = "tofu"
+	runner := NewRunner(cfg)
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	err := runner.RunTestWithOutput(t.TempDir(), &stdout, &stdout)
+	if !errors.Is(err, ErrNoTests) {
+		t.Fatalf("expected ErrNoTests, got %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected nothing to run without test files, got %q", stdout.String())
+	}
+}
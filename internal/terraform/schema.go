@@ -122,14 +122,30 @@ type OutputInfo struct {
 	Sensitive   bool   `json:"sensitive,omitempty"`
 }
 
+// ResourceInfo represents a managed resource or data source of a module
+type ResourceInfo struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// ModuleCallInfo represents a call to a child module
+type ModuleCallInfo struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
 // ModuleSchema represents the parsed Terraform module schema
 type ModuleSchema struct {
-	Name             string         `json:"name"`
-	Path             string         `json:"path"`
-	TerraformVersion string         `json:"terraform_version,omitempty"`
-	Providers        []ProviderInfo `json:"providers,omitempty"`
-	Variables        []VariableInfo `json:"variables,omitempty"`
-	Outputs          []OutputInfo   `json:"outputs,omitempty"`
+	Name             string           `json:"name"`
+	Path             string           `json:"path"`
+	TerraformVersion string           `json:"terraform_version,omitempty"`
+	Providers        []ProviderInfo   `json:"providers,omitempty"`
+	Variables        []VariableInfo   `json:"variables,omitempty"`
+	Outputs          []OutputInfo     `json:"outputs,omitempty"`
+	Resources        []ResourceInfo   `json:"resources,omitempty"`
+	DataSources      []ResourceInfo   `json:"data_sources,omitempty"`
+	ModuleCalls      []ModuleCallInfo `json:"module_calls,omitempty"`
 }
 
 // LoadModuleSchema parses a Terraform module and returns its schema.
@@ -143,6 +159,19 @@ func LoadModuleSchema(modulePath string, rootPath string) (*ModuleSchema, error)
 	return buildModuleSchema(module, modulePath, rootPath), nil
 }
 
+// LoadModuleSchemaFromFiles parses a Terraform module from the contents of
+// its files keyed by name, e.g. read from a git commit, without touching the
+// disk. The schema's name and path are derived from modulePath and rootPath
+// like LoadModuleSchema's.
+func LoadModuleSchemaFromFiles(files map[string][]byte, modulePath string, rootPath string) (*ModuleSchema, error) {
+	module, diags := tfconfig.LoadModuleFromFilesystem(tfconfig.WrapFS(filesFS(files)), ".")
+	if diags.HasErrors() {
+		return nil, diags.Err()
+	}
+
+	return buildModuleSchema(module, modulePath, rootPath), nil
+}
+
 func buildModuleSchema(module *tfconfig.Module, modulePath string, rootPath string) *ModuleSchema {
 	schema := &ModuleSchema{
 		Name: filepath.Base(modulePath),
@@ -170,6 +199,13 @@ func buildModuleSchema(module *tfconfig.Module, modulePath string, rootPath stri
 	// Outputs (sorted by name)
 	schema.Outputs = buildOutputList(module.Outputs)
 
+	// Resources and data sources (sorted by type, then name)
+	schema.Resources = buildResourceList(module.ManagedResources)
+	schema.DataSources = buildResourceList(module.DataResources)
+
+	// Child module calls (sorted by name)
+	schema.ModuleCalls = buildModuleCallList(module.ModuleCalls)
+
 	return schema
 }
 
@@ -237,3 +273,39 @@ func buildOutputList(outputs map[string]*tfconfig.Output) []OutputInfo {
 	}
 	return result
 }
+
+func buildResourceList(resources map[string]*tfconfig.Resource) []ResourceInfo {
+	result := make([]ResourceInfo, 0, len(resources))
+	for _, r := range resources {
+		result = append(result, ResourceInfo{
+			Type: r.Type,
+			Name: r.Name,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Type != result[j].Type {
+			return result[i].Type < result[j].Type
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func buildModuleCallList(calls map[string]*tfconfig.ModuleCall) []ModuleCallInfo {
+	names := make([]string, 0, len(calls))
+	for name := range calls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]ModuleCallInfo, 0, len(calls))
+	for _, name := range names {
+		c := calls[name]
+		result = append(result, ModuleCallInfo{
+			Name:    name,
+			Source:  c.Source,
+			Version: c.Version,
+		})
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package terraform
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -67,6 +68,138 @@ output "id" {
 	}
 }
 
+func TestLoadModuleSchema_ResourcesAndModuleCalls(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	tfContent := `
+resource "azurerm_storage_account" "this" {}
+
+resource "azurerm_private_endpoint" "blob" {}
+
+resource "azurerm_private_endpoint" "queue" {}
+
+data "azurerm_client_config" "current" {}
+
+module "naming" {
+  source  = "Azure/naming/azurerm"
+  version = "~> 0.4"
+}
+
+module "diagnostics" {
+  source = "../diagnostics"
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.tf"), []byte(tfContent), 0644); err != nil {
+		t.Fatalf("failed to write main.tf: %v", err)
+	}
+
+	schema, err := LoadModuleSchema(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadModuleSchema failed: %v", err)
+	}
+
+	wantResources := []ResourceInfo{
+		{Type: "azurerm_private_endpoint", Name: "blob"},
+		{Type: "azurerm_private_endpoint", Name: "queue"},
+		{Type: "azurerm_storage_account", Name: "this"},
+	}
+	if !slices.Equal(schema.Resources, wantResources) {
+		t.Errorf("Resources = %v, want %v", schema.Resources, wantResources)
+	}
+
+	wantData := []ResourceInfo{{Type: "azurerm_client_config", Name: "current"}}
+	if !slices.Equal(schema.DataSources, wantData) {
+		t.Errorf("DataSources = %v, want %v", schema.DataSources, wantData)
+	}
+
+	wantCalls := []ModuleCallInfo{
+		{Name: "diagnostics", Source: "../diagnostics"},
+		{Name: "naming", Source: "Azure/naming/azurerm", Version: "~> 0.4"},
+	}
+	if !slices.Equal(schema.ModuleCalls, wantCalls) {
+		t.Errorf("ModuleCalls = %v, want %v", schema.ModuleCalls, wantCalls)
+	}
+}
+
+func TestLoadModuleSchemaFromFiles(t *testing.T) {
+	files := map[string][]byte{
+		"variables.tf": []byte("variable \"name\" {\n  type = string\n}\n"),
+		"outputs.tf":   []byte("output \"id\" {\n  value = \"test-id\"\n}\n"),
+		"README.md":    []byte("# storage-account\n"),
+	}
+
+	schema, err := LoadModuleSchemaFromFiles(files, "/repo/components/storage-account", "/repo")
+	if err != nil {
+		t.Fatalf("LoadModuleSchemaFromFiles failed: %v", err)
+	}
+	if schema.Name != "storage-account" || schema.Path != filepath.Join("components", "storage-account") {
+		t.Errorf("unexpected name %q and path %q", schema.Name, schema.Path)
+	}
+	if len(schema.Variables) != 1 || schema.Variables[0].Name != "name" || !schema.Variables[0].Required {
+		t.Errorf("expected the required variable name, got %v", schema.Variables)
+	}
+	if len(schema.Outputs) != 1 || schema.Outputs[0].Name != "id" {
+		t.Errorf("expected the output id, got %v", schema.Outputs)
+	}
+
+	// A module without files, e.g. one that didn't exist yet, has an empty schema
+	schema, err = LoadModuleSchemaFromFiles(nil, "/repo/components/storage-account", "/repo")
+	if err != nil {
+		t.Fatalf("LoadModuleSchemaFromFiles failed without files: %v", err)
+	}
+	if len(schema.Variables) != 0 || len(schema.Outputs) != 0 {
+		t.Errorf("expected an empty schema, got %+v", schema)
+	}
+
+	if _, err := LoadModuleSchemaFromFiles(map[string][]byte{"main.tf": []byte("variable {")}, "/repo/mod", ""); err == nil {
+		t.Error("expected an error for invalid HCL")
+	}
+}
+
+func TestLoadModuleSchema_JSON(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	jsonContent := `{
+  "terraform": {
+    "required_providers": {
+      "azurerm": {"source": "hashicorp/azurerm", "version": ">= 3.0.0"}
+    }
+  },
+  "variable": {
+    "name": {"type": "string", "description": "The name of the resource"},
+    "tags": {"type": "map(string)", "default": {}}
+  },
+  "output": {
+    "id": {"value": "${azurerm_resource_group.this.id}", "description": "The resource ID"}
+  }
+}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.tf.json"), []byte(jsonContent), 0644); err != nil {
+		t.Fatalf("failed to write main.tf.json: %v", err)
+	}
+
+	schema, err := LoadModuleSchema(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadModuleSchema failed: %v", err)
+	}
+
+	if len(schema.Providers) != 1 || schema.Providers[0].Name != "azurerm" || schema.Providers[0].Version != ">= 3.0.0" {
+		t.Errorf("Providers = %+v, want azurerm >= 3.0.0", schema.Providers)
+	}
+	if len(schema.Variables) != 2 {
+		t.Fatalf("expected 2 variables, got %d", len(schema.Variables))
+	}
+	name := schema.Variables[0]
+	if name.Name != "name" || name.Type != "string" || !name.Required || name.Description != "The name of the resource" {
+		t.Errorf("Variables[0] = %+v, want required string 'name' with description", name)
+	}
+	if tags := schema.Variables[1]; tags.Type != "map(string)" || tags.Required {
+		t.Errorf("Variables[1] = %+v, want optional map(string) 'tags'", tags)
+	}
+	if len(schema.Outputs) != 1 || schema.Outputs[0].Description != "The resource ID" {
+		t.Errorf("Outputs = %+v, want 'id' with description", schema.Outputs)
+	}
+}
+
 func TestLoadModuleSchema_RelativePath(t *testing.T) {
 	tmpDir := t.TempDir()
 	moduleDir := filepath.Join(tmpDir, "components", "test-module")
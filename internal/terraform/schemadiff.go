@@ -0,0 +1,160 @@
+package terraform
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Kinds of interface changes
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// InterfaceChange is a variable or output that differs between two versions
+// of a module.
+type InterfaceChange struct {
+	Kind     string   `json:"kind"` // variable or output
+	Name     string   `json:"name"`
+	Change   string   `json:"change"`            // added, removed, or changed
+	Details  []string `json:"details,omitempty"` // What changed, e.g. "type: string -> number"
+	Breaking bool     `json:"breaking"`          // Whether callers of the old version can fail with the new one
+}
+
+// String returns e.g. "variable location changed (type: string -> number), breaking".
+func (c InterfaceChange) String() string {
+	s := fmt.Sprintf("%s %s %s", c.Kind, c.Name, c.Change)
+	if len(c.Details) > 0 {
+		s += " (" + strings.Join(c.Details, ", ") + ")"
+	}
+	if c.Breaking {
+		s += ", breaking"
+	}
+	return s
+}
+
+// DiffInterface returns the changes to the variables and outputs of a module
+// from one schema to another, variables first, each sorted by name.
+// A change is breaking when a caller of the old module can fail with the new
+// one: a variable is removed, added or made required without a default, or
+// changes type, or an output is removed or becomes sensitive.
+func DiffInterface(from, to *ModuleSchema) []InterfaceChange {
+	var changes []InterfaceChange
+
+	oldVars := make(map[string]VariableInfo, len(from.Variables))
+	for _, v := range from.Variables {
+		oldVars[v.Name] = v
+	}
+	newVars := make(map[string]VariableInfo, len(to.Variables))
+	for _, v := range to.Variables {
+		newVars[v.Name] = v
+	}
+	for _, name := range sortedNames(oldVars, newVars) {
+		o, inOld := oldVars[name]
+		n, inNew := newVars[name]
+		switch {
+		case !inNew:
+			changes = append(changes, InterfaceChange{Kind: "variable", Name: name, Change: ChangeRemoved, Breaking: true})
+		case !inOld:
+			changes = append(changes, InterfaceChange{Kind: "variable", Name: name, Change: ChangeAdded, Breaking: n.Required})
+		default:
+			if c, ok := diffVariable(o, n); ok {
+				changes = append(changes, c)
+			}
+		}
+	}
+
+	oldOutputs := make(map[string]OutputInfo, len(from.Outputs))
+	for _, o := range from.Outputs {
+		oldOutputs[o.Name] = o
+	}
+	newOutputs := make(map[string]OutputInfo, len(to.Outputs))
+	for _, o := range to.Outputs {
+		newOutputs[o.Name] = o
+	}
+	for _, name := range sortedNames(oldOutputs, newOutputs) {
+		o, inOld := oldOutputs[name]
+		n, inNew := newOutputs[name]
+		switch {
+		case !inNew:
+			changes = append(changes, InterfaceChange{Kind: "output", Name: name, Change: ChangeRemoved, Breaking: true})
+		case !inOld:
+			changes = append(changes, InterfaceChange{Kind: "output", Name: name, Change: ChangeAdded})
+		default:
+			if c, ok := diffOutput(o, n); ok {
+				changes = append(changes, c)
+			}
+		}
+	}
+	return changes
+}
+
+// diffVariable returns how a variable changed, if it did.
+func diffVariable(o, n VariableInfo) (InterfaceChange, bool) {
+	c := InterfaceChange{Kind: "variable", Name: n.Name, Change: ChangeChanged}
+	if normalizeTypeExpr(o.Type) != normalizeTypeExpr(n.Type) {
+		c.Details = append(c.Details, fmt.Sprintf("type: %s -> %s", valueOrAny(o.Type), valueOrAny(n.Type)))
+		c.Breaking = true
+	}
+	switch {
+	case !o.Required && n.Required:
+		c.Details = append(c.Details, "now required")
+		c.Breaking = true
+	case o.Required && !n.Required:
+		c.Details = append(c.Details, "now optional, default: "+n.FullDefaultString())
+	case !o.Required && !reflect.DeepEqual(o.Default, n.Default):
+		c.Details = append(c.Details, fmt.Sprintf("default: %s -> %s", o.FullDefaultString(), n.FullDefaultString()))
+	}
+	if o.Description != n.Description {
+		c.Details = append(c.Details, "description")
+	}
+	return c, len(c.Details) > 0
+}
+
+// diffOutput returns how an output changed, if it did.
+func diffOutput(o, n OutputInfo) (InterfaceChange, bool) {
+	c := InterfaceChange{Kind: "output", Name: n.Name, Change: ChangeChanged}
+	switch {
+	case !o.Sensitive && n.Sensitive:
+		c.Details = append(c.Details, "now sensitive")
+		c.Breaking = true
+	case o.Sensitive && !n.Sensitive:
+		c.Details = append(c.Details, "no longer sensitive")
+	}
+	if o.Description != n.Description {
+		c.Details = append(c.Details, "description")
+	}
+	return c, len(c.Details) > 0
+}
+
+// sortedNames returns the keys of both maps, sorted.
+func sortedNames[V any](a, b map[string]V) []string {
+	names := make([]string, 0, len(a)+len(b))
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// normalizeTypeExpr returns a type constraint without whitespace, or any when
+// there is none, so that formatting changes aren't reported as type changes.
+func normalizeTypeExpr(t string) string {
+	return strings.Join(strings.Fields(valueOrAny(t)), "")
+}
+
+// valueOrAny returns a type constraint, or any when there is none.
+func valueOrAny(t string) string {
+	if t == "" {
+		return "any"
+	}
+	return t
+}
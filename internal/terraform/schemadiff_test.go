@@ -0,0 +1,81 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffInterface(t *testing.T) {
+	from := &ModuleSchema{
+		Variables: []VariableInfo{
+			{Name: "name", Type: "string", Required: true},
+			{Name: "location", Type: "string", Default: "eastus"},
+			{Name: "sku", Type: "string", Default: "Standard"},
+			{Name: "tags", Type: "map( string )", Default: map[string]any{}},
+			{Name: "zones", Type: "list(string)", Required: true},
+			{Name: "legacy", Type: "bool", Default: false},
+		},
+		Outputs: []OutputInfo{
+			{Name: "id"},
+			{Name: "key"},
+			{Name: "old"},
+		},
+	}
+	to := &ModuleSchema{
+		Variables: []VariableInfo{
+			{Name: "name", Type: "string", Required: true, Description: "Name of the account"},
+			{Name: "location", Type: "string", Required: true},
+			{Name: "sku", Type: "string", Default: "Premium"},
+			{Name: "tags", Type: "map(string)", Default: map[string]any{}},
+			{Name: "zones", Type: "set(string)", Default: []any{}},
+			{Name: "kind", Type: "string", Required: true},
+			{Name: "replication", Type: "string", Default: "LRS"},
+		},
+		Outputs: []OutputInfo{
+			{Name: "id"},
+			{Name: "key", Sensitive: true},
+			{Name: "name"},
+		},
+	}
+
+	want := []InterfaceChange{
+		{Kind: "variable", Name: "kind", Change: ChangeAdded, Breaking: true},
+		{Kind: "variable", Name: "legacy", Change: ChangeRemoved, Breaking: true},
+		{Kind: "variable", Name: "location", Change: ChangeChanged, Details: []string{"now required"}, Breaking: true},
+		{Kind: "variable", Name: "name", Change: ChangeChanged, Details: []string{"description"}},
+		{Kind: "variable", Name: "replication", Change: ChangeAdded},
+		{Kind: "variable", Name: "sku", Change: ChangeChanged, Details: []string{`default: "Standard" -> "Premium"`}},
+		// tags only changed formatting
+		{Kind: "variable", Name: "zones", Change: ChangeChanged, Details: []string{"type: list(string) -> set(string)", "now optional, default: []"}, Breaking: true},
+		{Kind: "output", Name: "key", Change: ChangeChanged, Details: []string{"now sensitive"}, Breaking: true},
+		{Kind: "output", Name: "name", Change: ChangeAdded},
+		{Kind: "output", Name: "old", Change: ChangeRemoved, Breaking: true},
+	}
+	got := DiffInterface(from, to)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffInterface() =\n%v\nwant\n%v", got, want)
+	}
+
+	if changes := DiffInterface(to, to); len(changes) != 0 {
+		t.Errorf("expected no changes between identical schemas, got %v", changes)
+	}
+}
+
+func TestInterfaceChange_String(t *testing.T) {
+	tests := []struct {
+		change InterfaceChange
+		want   string
+	}{
+		{InterfaceChange{Kind: "output", Name: "name", Change: ChangeAdded}, "output name added"},
+		{InterfaceChange{Kind: "variable", Name: "legacy", Change: ChangeRemoved, Breaking: true}, "variable legacy removed, breaking"},
+		{
+			InterfaceChange{Kind: "variable", Name: "zones", Change: ChangeChanged, Details: []string{"type: list(string) -> set(string)", "description"}, Breaking: true},
+			"variable zones changed (type: list(string) -> set(string), description), breaking",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.change.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -1,27 +1,61 @@
 package terraform
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/procgroup"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
 )
 
 // Runner executes terraform/tofu commands using configuration
 type Runner struct {
 	config *config.Config
+
+	// DryRun prints each resolved command and its working directory instead of executing it
+	DryRun bool
+
+	// Hooks runs the pre_ and post_ hooks of init, plan, and apply; nil disables them
+	Hooks HookRunner
+
+	// Context stops running commands when done; nil means commands only stop
+	// at their timeout. Commands run in their own process group, so they
+	// don't receive the terminal's Ctrl+C: cancel Context on interrupt instead.
+	Context context.Context
+
+	binaryOnce sync.Once
+	resolved   string // The terraform/tofu binary run, see binary
+	resolveErr error
 }
 
+// HookRunner runs the hook name (e.g. "pre_plan") in dir with vars added to
+// its environment. It's a no-op for hooks that aren't set.
+type HookRunner func(name, dir string, vars map[string]string, stdout, stderr io.Writer) error
+
+// hookedCommands are the commands with pre_ and post_ hooks
+var hookedCommands = map[string]bool{"init": true, "plan": true, "apply": true}
+
 // NewRunner creates a new Runner with the given configuration
 func NewRunner(cfg *config.Config) *Runner {
 	return &Runner{config: cfg}
 }
 
-// Binary returns the configured binary name
+// Binary returns the terraform/tofu binary the runner runs: its name on
+// PATH, or the path of the toolchain release matching binary_version. It's
+// the configured binary when it can't be resolved.
 func (r *Runner) Binary() string {
+	if binary, err := r.binary(); err == nil {
+		return binary
+	}
 	return r.config.Binary
 }
 
@@ -33,13 +67,7 @@ func (r *Runner) RunInit(dir string, extraArgs ...string) error {
 // RunInitWithOutput executes terraform/tofu init with custom output writers
 func (r *Runner) RunInitWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
 	args := append([]string{"init"}, extraArgs...)
-	cmd := exec.Command(r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
-	cmd.Dir = dir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-
-	_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
-	return cmd.Run()
+	return r.runBinary(args, dir, stdout, stderr)
 }
 
 // RunFmt executes terraform/tofu fmt in the specified directory
@@ -47,16 +75,14 @@ func (r *Runner) RunFmt(dir string, extraArgs ...string) error {
 	return r.RunFmtWithOutput(dir, os.Stdout, os.Stderr, extraArgs...)
 }
 
-// RunFmtWithOutput executes terraform/tofu fmt with custom output writers
+// RunFmtWithOutput executes terraform/tofu fmt with custom output writers.
+// Since fmt skips JSON syntax, .tf.json files are then formatted by motf.
 func (r *Runner) RunFmtWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
 	args := append([]string{"fmt"}, extraArgs...)
-	cmd := exec.Command(r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
-	cmd.Dir = dir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-
-	_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
-	return cmd.Run()
+	if err := r.runBinary(args, dir, stdout, stderr); err != nil || r.DryRun {
+		return err
+	}
+	return formatJSONFiles(dir, extraArgs, stdout)
 }
 
 // RunValidate executes terraform/tofu validate in the specified directory
@@ -67,13 +93,7 @@ func (r *Runner) RunValidate(dir string, extraArgs ...string) error {
 // RunValidateWithOutput executes terraform/tofu validate with custom output writers
 func (r *Runner) RunValidateWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
 	args := append([]string{"validate"}, extraArgs...)
-	cmd := exec.Command(r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
-	cmd.Dir = dir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-
-	_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
-	return cmd.Run()
+	return r.runBinary(args, dir, stdout, stderr)
 }
 
 // RunPlan executes terraform/tofu plan in the specified directory
@@ -84,13 +104,47 @@ func (r *Runner) RunPlan(dir string, extraArgs ...string) error {
 // RunPlanWithOutput executes terraform/tofu plan with custom output writers
 func (r *Runner) RunPlanWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
 	args := append([]string{"plan"}, extraArgs...)
-	cmd := exec.Command(r.config.Binary, args...) //nolint:gosec // Binary is validated to be terraform or tofu
-	cmd.Dir = dir
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
+	return r.runBinary(args, dir, stdout, stderr)
+}
+
+// RunApplyWithOutput executes terraform/tofu apply of a saved plan file with
+// custom output writers
+func (r *Runner) RunApplyWithOutput(dir, planFile string, stdout, stderr io.Writer, extraArgs ...string) error {
+	args := append(append([]string{"apply"}, extraArgs...), planFile)
+	return r.runBinary(args, dir, stdout, stderr)
+}
+
+// RunShow executes terraform/tofu show of a saved plan file in the specified directory
+func (r *Runner) RunShow(dir, planFile string, extraArgs ...string) error {
+	args := append(append([]string{"show"}, extraArgs...), planFile)
+	return r.runBinary(args, dir, os.Stdout, os.Stderr)
+}
+
+// RunState executes a terraform/tofu state subcommand, e.g. list or mv, in the specified directory
+func (r *Runner) RunState(dir string, args ...string) error {
+	return r.RunStateWithOutput(dir, os.Stdout, os.Stderr, args...)
+}
 
-	_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", r.config.Binary, strings.Join(args, " "), dir)
-	return cmd.Run()
+// RunStateWithOutput executes a terraform/tofu state subcommand with custom output writers
+func (r *Runner) RunStateWithOutput(dir string, stdout, stderr io.Writer, args ...string) error {
+	return r.runBinary(append([]string{"state"}, args...), dir, stdout, stderr)
+}
+
+// RunImport executes terraform/tofu import in the specified directory. The
+// address and ID of the resource go last in extraArgs.
+func (r *Runner) RunImport(dir string, extraArgs ...string) error {
+	return r.runBinary(append([]string{"import"}, extraArgs...), dir, os.Stdout, os.Stderr)
+}
+
+// RunProvidersLockWithOutput executes terraform/tofu providers lock in the
+// specified directory, recording the provider checksums of each platform,
+// e.g. linux_amd64, in .terraform.lock.hcl
+func (r *Runner) RunProvidersLockWithOutput(dir string, platforms []string, stdout, stderr io.Writer, extraArgs ...string) error {
+	args := []string{"providers", "lock"}
+	for _, platform := range platforms {
+		args = append(args, "-platform="+platform)
+	}
+	return r.runBinary(append(args, extraArgs...), dir, stdout, stderr)
 }
 
 // RunTest executes tests based on the configured test engine
@@ -100,7 +154,7 @@ func (r *Runner) RunTest(dir string, extraArgs ...string) error {
 
 // RunTestWithOutput executes tests with custom output writers
 func (r *Runner) RunTestWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
-	var cmd *exec.Cmd
+	var binary string
 	var cmdArgs []string
 
 	if !config.IsValidTestEngine(r.config.Test.Engine) {
@@ -109,41 +163,158 @@ func (r *Runner) RunTestWithOutput(dir string, stdout, stderr io.Writer, extraAr
 
 	switch r.config.Test.Engine {
 	case "terratest":
-		// Terratest uses Go test
+		// Terratest uses Go test, in tests/ when it's a Go module of its own
+		// that ./... of the module directory doesn't include
+		binary = "go"
 		cmdArgs = []string{"test", "./..."}
-
-		// Add config args if present
-		if r.config.Test.Args != "" {
-			configArgs := strings.Fields(r.config.Test.Args)
-			cmdArgs = append(cmdArgs, configArgs...)
+		if _, err := os.Stat(filepath.Join(dir, defaultTestDirectory, "go.mod")); err == nil {
+			dir = filepath.Join(dir, defaultTestDirectory)
 		}
-
-		// Add extra args from command line
-		cmdArgs = append(cmdArgs, extraArgs...)
-
-		cmd = exec.Command("go", cmdArgs...) //nolint:gosec // cmdArgs are constructed from validated config
-		_, _ = fmt.Fprintf(stdout, "Running go %s in %s\n", strings.Join(cmdArgs, " "), dir)
 	case "terraform", "tofu":
 		// Terraform/Tofu native test command
+		binary = r.config.Test.Engine
 		cmdArgs = []string{"test"}
-
-		// Add config args if present
-		if r.config.Test.Args != "" {
-			configArgs := strings.Fields(r.config.Test.Args)
-			cmdArgs = append(cmdArgs, configArgs...)
+		found, err := HasNativeTests(dir, append(strings.Fields(r.config.Test.Args), extraArgs...))
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("%w (*.tftest.hcl)", ErrNoTests)
 		}
+	case "compliance":
+		return r.runComplianceWithOutput(dir, stdout, stderr, extraArgs)
+	}
+
+	// Add config args if present
+	if r.config.Test.Args != "" {
+		configArgs := strings.Fields(r.config.Test.Args)
+		cmdArgs = append(cmdArgs, configArgs...)
+	}
+
+	// Add extra args from command line
+	cmdArgs = append(cmdArgs, extraArgs...)
+
+	return r.run(binary, cmdArgs, dir, stdout, stderr)
+}
+
+// RunNativeTestWithOutput executes terraform/tofu test regardless of the
+// configured test engine, with custom output writers
+func (r *Runner) RunNativeTestWithOutput(dir string, stdout, stderr io.Writer, extraArgs ...string) error {
+	args := append([]string{"test"}, extraArgs...)
+	return r.runBinary(args, dir, stdout, stderr)
+}
+
+// ShowJSON returns the output of terraform/tofu show -json in the specified directory.
+func (r *Runner) ShowJSON(dir string) ([]byte, error) {
+	return r.output(dir, "show", "-json")
+}
 
-		// Add extra args from command line
-		cmdArgs = append(cmdArgs, extraArgs...)
+// Outputs returns the output of terraform/tofu output in the specified directory.
+func (r *Runner) Outputs(dir string, extraArgs ...string) ([]byte, error) {
+	return r.output(dir, append([]string{"output"}, extraArgs...)...)
+}
+
+// ProvidersSchemaJSON returns the output of terraform/tofu providers schema -json in the specified directory.
+func (r *Runner) ProvidersSchemaJSON(dir string) ([]byte, error) {
+	return r.output(dir, "providers", "schema", "-json")
+}
+
+// output runs a read-only terraform/tofu command and returns its stdout.
+// It runs even in dry-run mode since it doesn't change anything.
+func (r *Runner) output(dir string, args ...string) ([]byte, error) {
+	binary, err := r.binary()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(binary, args...) //nolint:gosec // binary is terraform or tofu
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	if len(r.config.Env) > 0 {
+		cmd.Env = append(os.Environ(), tasks.EnvPairs(r.config.Env)...)
+	}
 
-		binary := r.config.Test.Engine
-		cmd = exec.Command(binary, cmdArgs...) //nolint:gosec // binary is validated to be terraform or tofu
-		_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", binary, strings.Join(cmdArgs, " "), dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", binary, strings.Join(args, " "), err)
 	}
+	return out, nil
+}
+
+// run executes binary with args in dir, or only prints it when DryRun is set.
+// binary is either the validated terraform/tofu binary or go (terratest).
+func (r *Runner) run(binary string, args []string, dir string, stdout, stderr io.Writer) error {
+	// args[0] is the command, e.g. plan, or test for both go test and terraform test
+	return r.runCommand(args[0], binary, args, dir, stdout, stderr)
+}
 
+// runCommand executes binary with args in dir like run, between the pre_ and
+// post_ hooks of command if it has them. A failing pre_ hook stops the
+// command; the post_ hook runs whether the command succeeded or not.
+func (r *Runner) runCommand(command, binary string, args []string, dir string, stdout, stderr io.Writer) error {
+	if r.Hooks == nil || !hookedCommands[command] || binary != r.resolved {
+		return r.execCommand(command, binary, args, dir, stdout, stderr)
+	}
+
+	if err := r.Hooks("pre_"+command, dir, nil, stdout, stderr); err != nil {
+		return err
+	}
+	err := r.execCommand(command, binary, args, dir, stdout, stderr)
+	vars := map[string]string{tasks.EnvStatus: tasks.Status(commandError(args, err))}
+	if hookErr := r.Hooks("post_"+command, dir, vars, stdout, stderr); hookErr != nil {
+		return errors.Join(err, hookErr)
+	}
+	return err
+}
+
+// commandError returns err, or nil if it only reports changes of a plan with
+// -detailed-exitcode (exit code 2), which succeeded.
+func commandError(args []string, err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 && slices.Contains(args, "-detailed-exitcode") {
+		return nil
+	}
+	return err
+}
+
+// execCommand executes binary with args in dir, or only prints it when
+// DryRun is set, applying the timeout of command.
+func (r *Runner) execCommand(command, binary string, args []string, dir string, stdout, stderr io.Writer) error {
+	if r.DryRun {
+		_, _ = fmt.Fprintf(stdout, "[dry-run] Would run %s %s in %s\n", binary, strings.Join(args, " "), dir)
+		return nil
+	}
+
+	parent := r.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	if parent.Err() != nil {
+		return fmt.Errorf("%s %s stopped: %w", binary, command, context.Cause(parent))
+	}
+
+	ctx := parent
+	timeout := r.config.Timeout(command)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, binary, args...) //nolint:gosec // binary is terraform, tofu, go, or the configured compliance tool
 	cmd.Dir = dir
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
-
-	return cmd.Run()
+	if len(r.config.Env) > 0 {
+		cmd.Env = append(os.Environ(), tasks.EnvPairs(r.config.Env)...)
+	}
+	_, _ = fmt.Fprintf(stdout, "Running %s %s in %s\n", binary, strings.Join(args, " "), dir)
+	// Interrupt first so terraform can release state locks, then kill
+	err := procgroup.Run(cmd, procgroup.GracePeriod)
+	switch {
+	case parent.Err() != nil:
+		return fmt.Errorf("%s %s stopped: %w", binary, command, context.Cause(parent))
+	case ctx.Err() == context.DeadlineExceeded:
+		return fmt.Errorf("%s %s timed out after %s", binary, command, timeout)
+	}
+	return err
 }
@@ -1,9 +1,19 @@
 package terraform
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/TechnicallyJoe/terraform-motf/internal/config"
+	"github.com/TechnicallyJoe/terraform-motf/internal/tasks"
 )
 
 func TestNewRunner(t *testing.T) {
@@ -137,7 +147,37 @@ func TestRunner_RunTest_TerratestEngine(t *testing.T) {
 	// but we verify the config is correctly set up
 }
 
-// TestRunner_RunTest_WithConfigArgs verifies config args are included
+// TestRunner_RunTest_TerratestTestsModule verifies that terratest runs go test
+// in tests/ when it has its own go.mod, and in the module directory otherwise
+func TestRunner_RunTest_TerratestTestsModule(t *testing.T) {
+	dir := t.TempDir()
+	runner := NewRunner(config.DefaultConfig())
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	if err := runner.RunTestWithOutput(dir, &stdout, &stdout); err != nil {
+		t.Fatalf("RunTestWithOutput() error = %v", err)
+	}
+	if want := "[dry-run] Would run go test ./... in " + dir + "\n"; stdout.String() != want {
+		t.Errorf("output = %q, want %q", stdout.String(), want)
+	}
+
+	testsDir := filepath.Join(dir, "tests")
+	if err := os.MkdirAll(testsDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(testsDir, "go.mod"), []byte("module tests\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if err := runner.RunTestWithOutput(dir, &stdout, &stdout); err != nil {
+		t.Fatalf("RunTestWithOutput() error = %v", err)
+	}
+	if want := "[dry-run] Would run go test ./... in " + testsDir + "\n"; stdout.String() != want {
+		t.Errorf("output = %q, want %q", stdout.String(), want)
+	}
+}
+
 func TestRunner_RunTest_WithConfigArgs(t *testing.T) {
 	cfg := &config.Config{
 		Root:   "/test/root",
@@ -200,3 +240,258 @@ func TestRunner_RunTest_TofuEngine(t *testing.T) {
 
 	// The actual command would be: tofu test
 }
+
+func TestRunner_DryRun(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Binary = "tofu"
+	runner := NewRunner(cfg)
+	runner.DryRun = true
+
+	// The directory doesn't exist, so anything other than a dry run would fail
+	dir := "/nonexistent/module"
+
+	var stdout, stderr bytes.Buffer
+	if err := runner.RunPlanWithOutput(dir, &stdout, &stderr, "-var=env=prod"); err != nil {
+		t.Fatalf("dry run should not execute the command, got: %v", err)
+	}
+
+	want := "[dry-run] Would run tofu plan -var=env=prod in /nonexistent/module\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestRunner_DryRun_ProvidersLock(t *testing.T) {
+	runner := NewRunner(config.DefaultConfig())
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	if err := runner.RunProvidersLockWithOutput("/nonexistent/module", []string{"linux_amd64", "darwin_arm64"}, &stdout, &stdout); err != nil {
+		t.Fatalf("dry run should not execute the command, got: %v", err)
+	}
+
+	want := "[dry-run] Would run terraform providers lock -platform=linux_amd64 -platform=darwin_arm64 in /nonexistent/module\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestRunner_DryRun_TestEngine(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Test.Engine = "terratest"
+	cfg.Test.Args = "-v"
+	runner := NewRunner(cfg)
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	if err := runner.RunTestWithOutput("/nonexistent/module", &stdout, &stdout, "-run=TestBasic"); err != nil {
+		t.Fatalf("dry run should not execute the command, got: %v", err)
+	}
+
+	want := "[dry-run] Would run go test ./... -v -run=TestBasic in /nonexistent/module\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestRunner_DryRun_NativeTestIgnoresEngine(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Binary = "tofu"
+	cfg.Test.Engine = "terratest"
+	runner := NewRunner(cfg)
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	if err := runner.RunNativeTestWithOutput("/nonexistent/module", &stdout, &stdout, "-no-color"); err != nil {
+		t.Fatalf("dry run should not execute the command, got: %v", err)
+	}
+
+	want := "[dry-run] Would run tofu test -no-color in /nonexistent/module\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestRunner_DryRun_ApplyPlanFile(t *testing.T) {
+	cfg := config.DefaultConfig()
+	runner := NewRunner(cfg)
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	if err := runner.RunApplyWithOutput("/nonexistent/module", "/plans/module.tfplan", &stdout, &stdout, "-parallelism=5"); err != nil {
+		t.Fatalf("dry run should not execute the command, got: %v", err)
+	}
+
+	// The plan file must come after the options
+	want := "[dry-run] Would run terraform apply -parallelism=5 /plans/module.tfplan in /nonexistent/module\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestRunner_DryRun_State(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Binary = "tofu"
+	runner := NewRunner(cfg)
+	runner.DryRun = true
+
+	var stdout bytes.Buffer
+	if err := runner.RunStateWithOutput("/nonexistent/module", &stdout, &stdout, "mv", "aws_s3_bucket.a", "aws_s3_bucket.b"); err != nil {
+		t.Fatalf("dry run should not execute the command, got: %v", err)
+	}
+
+	want := "[dry-run] Would run tofu state mv aws_s3_bucket.a aws_s3_bucket.b in /nonexistent/module\n"
+	if stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+// fakeBinary puts an executable script named name on PATH that runs script.
+func fakeBinary(t *testing.T, name, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("failed to write fake %s: %v", name, err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	fakeBinary(t, "terraform", "exec sleep 10")
+	runner := NewRunner(&config.Config{
+		Binary:   "terraform",
+		Timeouts: map[string]string{"default": "1h", "plan": "100ms"},
+	})
+
+	start := time.Now()
+	var stdout, stderr bytes.Buffer
+	err := runner.RunPlanWithOutput(t.TempDir(), &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "terraform plan timed out after 100ms") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the command to be stopped, took %s", elapsed)
+	}
+}
+
+func TestRunner_ContextCanceled(t *testing.T) {
+	fakeBinary(t, "terraform", "exec sleep 10")
+	runner := NewRunner(&config.Config{Binary: "terraform"})
+	ctx, cancel := context.WithCancelCause(context.Background())
+	runner.Context = ctx
+	time.AfterFunc(100*time.Millisecond, func() { cancel(errors.New("interrupted")) })
+
+	start := time.Now()
+	var stdout, stderr bytes.Buffer
+	err := runner.RunPlanWithOutput(t.TempDir(), &stdout, &stderr)
+	if err == nil || err.Error() != "terraform plan stopped: interrupted" {
+		t.Fatalf("expected the plan to be stopped, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the command to be stopped, took %s", elapsed)
+	}
+
+	// Commands don't start once the context is done
+	stdout.Reset()
+	if err := runner.RunInitWithOutput(t.TempDir(), &stdout, &stderr); err == nil || stdout.Len() != 0 {
+		t.Errorf("expected init not to run, got err = %v, stdout = %q", err, stdout.String())
+	}
+}
+
+func TestRunner_WithinTimeout(t *testing.T) {
+	fakeBinary(t, "terraform", "echo planned")
+	runner := NewRunner(&config.Config{
+		Binary:   "terraform",
+		Timeouts: map[string]string{"default": "1m"},
+	})
+
+	var stdout, stderr bytes.Buffer
+	if err := runner.RunPlanWithOutput(t.TempDir(), &stdout, &stderr); err != nil {
+		t.Fatalf("RunPlanWithOutput() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "planned") {
+		t.Errorf("stdout = %q, want the command output", stdout.String())
+	}
+}
+
+func TestRunner_Outputs_RunsInDryRun(t *testing.T) {
+	fakeBinary(t, "terraform", `echo "$@"`)
+	runner := NewRunner(config.DefaultConfig())
+	runner.DryRun = true
+
+	// Reading outputs doesn't change anything, so it runs even in dry-run mode
+	out, err := runner.Outputs(t.TempDir(), "-json", "vnet_id")
+	if err != nil {
+		t.Fatalf("Outputs() error = %v", err)
+	}
+	if got, want := string(out), "output -json vnet_id\n"; got != want {
+		t.Errorf("Outputs() = %q, want %q", got, want)
+	}
+}
+
+// recordHooks returns a HookRunner that records the hooks it runs and their
+// status, failing the hook named fail.
+func recordHooks(ran *[]string, fail string) HookRunner {
+	return func(name, dir string, vars map[string]string, stdout, stderr io.Writer) error {
+		*ran = append(*ran, strings.TrimSpace(name+" "+vars[tasks.EnvStatus]))
+		if name == fail {
+			return errors.New("hook failed")
+		}
+		return nil
+	}
+}
+
+func TestRunner_Hooks(t *testing.T) {
+	fakeBinary(t, "terraform", `[ "$1" = init ] && exit 1; [ "$2" = -detailed-exitcode ] && exit 2; echo "$@"`)
+	var ran []string
+	runner := NewRunner(&config.Config{Binary: "terraform", Test: &config.TestConfig{Engine: "terraform"}})
+	runner.Hooks = recordHooks(&ran, "")
+	dir := t.TempDir()
+	var stdout, stderr bytes.Buffer
+
+	if err := runner.RunPlanWithOutput(dir, &stdout, &stderr); err != nil {
+		t.Fatalf("RunPlanWithOutput() error = %v", err)
+	}
+	// Changes of a plan with -detailed-exitcode aren't a failure
+	if err := runner.RunPlanWithOutput(dir, &stdout, &stderr, "-detailed-exitcode"); err == nil {
+		t.Fatal("expected exit code 2")
+	}
+	if err := runner.RunInitWithOutput(dir, &stdout, &stderr); err == nil {
+		t.Fatal("expected init to fail")
+	}
+	// Commands without hooks
+	_ = runner.RunValidateWithOutput(dir, &stdout, &stderr)
+	_ = runner.RunTestWithOutput(dir, &stdout, &stderr)
+
+	want := []string{"pre_plan", "post_plan success", "pre_plan", "post_plan success", "pre_init", "post_init failure"}
+	if strings.Join(ran, ",") != strings.Join(want, ",") {
+		t.Errorf("hooks = %q, want %q", ran, want)
+	}
+}
+
+func TestRunner_PreHookFailureStopsCommand(t *testing.T) {
+	fakeBinary(t, "terraform", `echo applied`)
+	var ran []string
+	runner := NewRunner(&config.Config{Binary: "terraform"})
+	runner.Hooks = recordHooks(&ran, "pre_apply")
+
+	var stdout, stderr bytes.Buffer
+	err := runner.RunApplyWithOutput(t.TempDir(), "plan.tfplan", &stdout, &stderr)
+	if err == nil || err.Error() != "hook failed" {
+		t.Errorf("expected the hook error, got %v", err)
+	}
+	if strings.Contains(stdout.String(), "applied") || len(ran) != 1 {
+		t.Errorf("expected apply and post_apply not to run, got %q, hooks %q", stdout.String(), ran)
+	}
+
+	// A failing post hook fails the command
+	ran = nil
+	runner.Hooks = recordHooks(&ran, "post_apply")
+	err = runner.RunApplyWithOutput(t.TempDir(), "plan.tfplan", &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "hook failed") {
+		t.Errorf("expected the post hook error, got %v", err)
+	}
+}
@@ -0,0 +1,310 @@
+// Package testreport aggregates the test results of the modules of a 'motf
+// test' run, parsed from go test and terraform/tofu test output, into JUnit
+// XML and JSON reports for CI systems.
+package testreport
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+)
+
+// Supported report formats
+const (
+	FormatJUnit = "junit"
+	FormatJSON  = "json"
+)
+
+// Formats are the supported report formats
+var Formats = []string{FormatJUnit, FormatJSON}
+
+// Test case status values
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// maxOutputLines is the number of trailing output lines kept per module in
+// JUnit reports
+const maxOutputLines = 500
+
+var (
+	// ansiPattern matches color escape sequences
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+	// goTestPattern matches go test results, e.g. "--- PASS: TestName (0.01s)",
+	// indented for subtests
+	goTestPattern = regexp.MustCompile(`^\s*--- (PASS|FAIL|SKIP): (\S+) \(([0-9.]+)s\)`)
+
+	// nativeFilePattern matches terraform/tofu test file lines, e.g.
+	// "tests/main.tftest.hcl... in progress"
+	nativeFilePattern = regexp.MustCompile(`^(\S+\.tftest\.(?:hcl|json))\.\.\. `)
+
+	// nativeRunPattern matches terraform/tofu test run block results, e.g.
+	// `  run "defaults"... pass`
+	nativeRunPattern = regexp.MustCompile(`^\s*run "([^"]+)"\.\.\. (pass|fail|skip|error)\b`)
+)
+
+// Case is a single test: a Go test function or a run block of a test file.
+type Case struct {
+	Name    string  `json:"name"`
+	File    string  `json:"file,omitempty"` // Test file of run blocks
+	Status  string  `json:"status"`         // passed, failed, or skipped
+	Seconds float64 `json:"seconds,omitempty"`
+}
+
+// Suite is the test results of a module.
+type Suite struct {
+	Name    string  `json:"name"`
+	Path    string  `json:"path"`
+	Status  string  `json:"status"` // Status of the module, as in chatops summaries
+	Error   string  `json:"error,omitempty"`
+	Seconds float64 `json:"seconds"`
+	Tests   int     `json:"tests"`
+	Passed  int     `json:"passed"`
+	Failed  int     `json:"failed"`
+	Skipped int     `json:"skipped"`
+	Cases   []Case  `json:"cases"`
+	Output  string  `json:"-"` // Captured output, included in JUnit reports
+}
+
+// NewSuite returns the suite of a module with the test cases parsed from its
+// output. A module without recognizable test cases gets one case for the
+// module itself, with its status. The failures of a quarantined module are
+// counted as skipped.
+func NewSuite(name, path, status, errText string, duration time.Duration, output string) Suite {
+	output = ansiPattern.ReplaceAllString(output, "")
+	s := Suite{
+		Name:    name,
+		Path:    path,
+		Status:  status,
+		Error:   errText,
+		Seconds: duration.Seconds(),
+		Cases:   Parse(output),
+		Output:  output,
+	}
+	if len(s.Cases) == 0 {
+		s.Cases = []Case{{Name: name, Status: caseStatus(status), Seconds: s.Seconds}}
+	}
+	for i, c := range s.Cases {
+		if status == chatops.StatusQuarantined && c.Status == StatusFailed {
+			s.Cases[i].Status = StatusSkipped
+		}
+		switch s.Cases[i].Status {
+		case StatusPassed:
+			s.Passed++
+		case StatusFailed:
+			s.Failed++
+		case StatusSkipped:
+			s.Skipped++
+		}
+	}
+	s.Tests = len(s.Cases)
+	return s
+}
+
+// caseStatus returns the status of a test case for the status of a module.
+func caseStatus(status string) string {
+	switch status {
+	case chatops.StatusSucceeded, chatops.StatusFlaky:
+		return StatusPassed
+	case chatops.StatusFailed:
+		return StatusFailed
+	default:
+		return StatusSkipped
+	}
+}
+
+// Parse returns the test cases in go test or terraform/tofu test output. A
+// test reported more than once, e.g. on retries, keeps its last result.
+func Parse(output string) []Case {
+	var cases []Case
+	index := make(map[string]int)
+	add := func(c Case) {
+		key := c.File + "\x00" + c.Name
+		if i, ok := index[key]; ok {
+			cases[i] = c
+			return
+		}
+		index[key] = len(cases)
+		cases = append(cases, c)
+	}
+
+	file := ""
+	for line := range strings.SplitSeq(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if m := goTestPattern.FindStringSubmatch(line); m != nil {
+			seconds, _ := strconv.ParseFloat(m[3], 64)
+			add(Case{Name: m[2], Status: goTestStatus(m[1]), Seconds: seconds})
+			continue
+		}
+		if m := nativeFilePattern.FindStringSubmatch(line); m != nil {
+			file = m[1]
+			continue
+		}
+		if m := nativeRunPattern.FindStringSubmatch(line); m != nil {
+			add(Case{Name: m[1], File: file, Status: nativeStatus(m[2])})
+		}
+	}
+	return cases
+}
+
+func goTestStatus(result string) string {
+	switch result {
+	case "PASS":
+		return StatusPassed
+	case "SKIP":
+		return StatusSkipped
+	default:
+		return StatusFailed
+	}
+}
+
+func nativeStatus(result string) string {
+	switch result {
+	case "pass":
+		return StatusPassed
+	case "skip":
+		return StatusSkipped
+	default:
+		return StatusFailed
+	}
+}
+
+// Report is the test results of a run across modules.
+type Report struct {
+	Tests   int     `json:"tests"`
+	Passed  int     `json:"passed"`
+	Failed  int     `json:"failed"`
+	Skipped int     `json:"skipped"`
+	Seconds float64 `json:"seconds"`
+	Suites  []Suite `json:"suites"`
+}
+
+// New returns the report of suites with the totals of their counts.
+func New(suites []Suite) Report {
+	r := Report{Suites: suites}
+	if r.Suites == nil {
+		r.Suites = []Suite{}
+	}
+	for _, s := range suites {
+		r.Tests += s.Tests
+		r.Passed += s.Passed
+		r.Failed += s.Failed
+		r.Skipped += s.Skipped
+		r.Seconds += s.Seconds
+	}
+	return r
+}
+
+// Format renders the report in a supported format.
+func (r Report) Format(format string) ([]byte, error) {
+	switch format {
+	case FormatJUnit:
+		return r.JUnit()
+	case FormatJSON:
+		return json.MarshalIndent(r, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported report format '%s': must be %s", format, strings.Join(Formats, " or "))
+	}
+}
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+	SystemOut *junitOutput    `xml:"system-out,omitempty"`
+}
+
+type junitOutput struct {
+	Text string `xml:",cdata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// JUnit renders the report as JUnit XML, with a test suite per module named
+// after its path.
+func (r Report) JUnit() ([]byte, error) {
+	root := junitTestSuites{
+		Name:     "motf test",
+		Tests:    r.Tests,
+		Failures: r.Failed,
+		Skipped:  r.Skipped,
+		Time:     seconds(r.Seconds),
+	}
+	for _, s := range r.Suites {
+		suite := junitTestSuite{
+			Name:     s.Path,
+			Tests:    s.Tests,
+			Failures: s.Failed,
+			Skipped:  s.Skipped,
+			Time:     seconds(s.Seconds),
+		}
+		if out := strings.TrimSpace(s.Output); out != "" {
+			suite.SystemOut = &junitOutput{Text: chatops.TruncateLines(out, maxOutputLines)}
+		}
+		for _, c := range s.Cases {
+			classname := s.Path
+			if c.File != "" {
+				classname += "/" + c.File
+			}
+			tc := junitTestCase{Name: c.Name, Classname: classname, Time: seconds(c.Seconds)}
+			switch c.Status {
+			case StatusFailed:
+				tc.Failure = &junitMessage{Message: valueOr(s.Error, "failed")}
+			case StatusSkipped:
+				tc.Skipped = &junitMessage{Message: valueOr(s.Error, "skipped")}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		root.Suites = append(root.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JUnit report: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func seconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', 3, 64)
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,133 @@
+package testreport
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/chatops"
+)
+
+const goTestOutput = `=== RUN   TestStorage
+=== RUN   TestStorage/defaults
+    --- PASS: TestStorage/defaults (1.50s)
+--- FAIL: TestStorage (2.25s)
+--- SKIP: TestSlow (0.00s)
+FAIL
+FAIL	example.com/storage/test	2.300s
+`
+
+const nativeTestOutput = "tests/main.tftest.hcl... in progress\n" +
+	"  run \"defaults\"... \x1b[32mpass\x1b[0m\n" +
+	"  run \"naming\"... fail\n" +
+	"tests/main.tftest.hcl... tearing down\n" +
+	"tests/main.tftest.hcl... fail\n" +
+	"validation.tftest.hcl... in progress\n" +
+	"  run \"bad_input\"... skip\n" +
+	"\nFailure! 1 passed, 1 failed, 1 skipped.\n"
+
+func TestParse_GoTest(t *testing.T) {
+	want := []Case{
+		{Name: "TestStorage/defaults", Status: StatusPassed, Seconds: 1.5},
+		{Name: "TestStorage", Status: StatusFailed, Seconds: 2.25},
+		{Name: "TestSlow", Status: StatusSkipped},
+	}
+	if got := Parse(goTestOutput); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParse_NativeTest(t *testing.T) {
+	output := ansiPattern.ReplaceAllString(nativeTestOutput, "")
+	want := []Case{
+		{Name: "defaults", File: "tests/main.tftest.hcl", Status: StatusPassed},
+		{Name: "naming", File: "tests/main.tftest.hcl", Status: StatusFailed},
+		{Name: "bad_input", File: "validation.tftest.hcl", Status: StatusSkipped},
+	}
+	if got := Parse(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParse_RetryKeepsLastResult(t *testing.T) {
+	output := "--- FAIL: TestA (1.00s)\n--- PASS: TestB (1.00s)\n--- PASS: TestA (2.00s)\n"
+	want := []Case{
+		{Name: "TestA", Status: StatusPassed, Seconds: 2},
+		{Name: "TestB", Status: StatusPassed, Seconds: 1},
+	}
+	if got := Parse(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewSuite(t *testing.T) {
+	s := NewSuite("storage", "components/storage", chatops.StatusFailed, "exit status 1", time.Second, nativeTestOutput)
+	if s.Tests != 3 || s.Passed != 1 || s.Failed != 1 || s.Skipped != 1 {
+		t.Errorf("counts = %d tests, %d passed, %d failed, %d skipped, want 3, 1, 1, 1", s.Tests, s.Passed, s.Failed, s.Skipped)
+	}
+	if strings.Contains(s.Output, "\x1b") {
+		t.Error("expected color codes to be stripped from the output")
+	}
+
+	// Without recognizable tests, the module is the test case
+	s = NewSuite("dns", "components/dns", chatops.StatusSkipped, "skipped: no test files", 0, "")
+	want := []Case{{Name: "dns", Status: StatusSkipped}}
+	if !reflect.DeepEqual(s.Cases, want) || s.Skipped != 1 {
+		t.Errorf("Cases = %+v, want %+v", s.Cases, want)
+	}
+
+	// Quarantined failures don't fail the report
+	s = NewSuite("storage", "components/storage", chatops.StatusQuarantined, "quarantined", 0, goTestOutput)
+	if s.Failed != 0 || s.Skipped != 2 {
+		t.Errorf("expected quarantined failures to be skipped, got %d failed, %d skipped", s.Failed, s.Skipped)
+	}
+}
+
+func TestReport_JUnit(t *testing.T) {
+	report := New([]Suite{
+		NewSuite("storage", "components/storage", chatops.StatusFailed, "exit status 1", 2*time.Second, nativeTestOutput),
+		NewSuite("vnet", "components/vnet", chatops.StatusSucceeded, "", time.Second, ""),
+	})
+	if report.Tests != 4 || report.Passed != 2 || report.Failed != 1 || report.Skipped != 1 {
+		t.Fatalf("totals = %+v", report)
+	}
+
+	data, err := report.Format(FormatJUnit)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<testsuites name="motf test" tests="4" failures="1" skipped="1" time="3.000">`,
+		`<testsuite name="components/storage" tests="3" failures="1" skipped="1" time="2.000">`,
+		`<testcase name="naming" classname="components/storage/tests/main.tftest.hcl" time="0.000">`,
+		`<failure message="exit status 1"></failure>`,
+		`<system-out><![CDATA[tests/main.tftest.hcl... in progress`,
+		`<testcase name="vnet" classname="components/vnet" time="1.000"></testcase>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("JUnit report missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestReport_JSON(t *testing.T) {
+	data, err := New(nil).Format(FormatJSON)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if suites, ok := got["suites"].([]any); !ok || len(suites) != 0 {
+		t.Errorf("expected an empty suites list, got %v", got["suites"])
+	}
+
+	if _, err := New(nil).Format("html"); err == nil || !strings.Contains(err.Error(), "unsupported report format 'html'") {
+		t.Errorf("expected an unsupported format error, got %v", err)
+	}
+}
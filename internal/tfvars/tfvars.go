@@ -0,0 +1,214 @@
+// Package tfvars resolves layered variable files of a module and merges them
+// into its effective variable values.
+package tfvars
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// CLISource is the source of values set with -var, the highest layer
+const CLISource = "-var"
+
+// placeholderPattern matches {name} placeholders in layer paths
+var placeholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// Params are the values of the placeholders in layer paths.
+type Params struct {
+	Env    string // Replaces {env}
+	Region string // Replaces {region}
+}
+
+// lookup returns the value of a placeholder.
+func (p Params) lookup(name string) string {
+	switch name {
+	case "env":
+		return p.Env
+	case "region":
+		return p.Region
+	}
+	return ""
+}
+
+// Layer is a variable file in the layering of a module.
+type Layer struct {
+	File  string `json:"file"`  // Slash-separated, relative to the module directory
+	Found bool   `json:"found"` // Missing files are skipped
+}
+
+// Value is the effective value of a variable and the layer that set it.
+type Value struct {
+	Name   string
+	Value  cty.Value
+	Source string // File of the layer, or CLISource
+}
+
+// Result is the outcome of resolving the layers of a module.
+type Result struct {
+	Layers []Layer
+	Values []Value // Sorted by name
+}
+
+// ValidatePattern checks that a layer path is relative and only uses the
+// {env} and {region} placeholders.
+func ValidatePattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return errors.New("path is required")
+	}
+	if filepath.IsAbs(pattern) || strings.HasPrefix(pattern, "/") {
+		return fmt.Errorf("'%s' must be relative to the module", pattern)
+	}
+	for _, m := range placeholderPattern.FindAllStringSubmatch(pattern, -1) {
+		if m[1] != "env" && m[1] != "region" {
+			return fmt.Errorf("'%s' has unknown placeholder {%s}: must be {env} or {region}", pattern, m[1])
+		}
+	}
+	return nil
+}
+
+// Expand replaces the placeholders in the layer patterns with params.
+// Patterns with a placeholder that has no value are left out, so e.g. a
+// {region} layer only applies when a region is given.
+func Expand(patterns []string, params Params) []string {
+	var files []string
+	for _, pattern := range patterns {
+		complete := true
+		file := placeholderPattern.ReplaceAllStringFunc(pattern, func(ph string) string {
+			value := params.lookup(ph[1 : len(ph)-1])
+			if value == "" {
+				complete = false
+			}
+			return value
+		})
+		if complete {
+			files = append(files, filepath.ToSlash(file))
+		}
+	}
+	return files
+}
+
+// Resolve reads the layer files in dir, lowest precedence first, and applies
+// the -var values in vars on top. As with -var-file, a variable set in a
+// higher layer replaces its whole value. Layer files that don't exist are skipped.
+func Resolve(dir string, patterns []string, params Params, vars []string) (*Result, error) {
+	result := &Result{}
+	values := make(map[string]Value)
+
+	for _, file := range Expand(patterns, params) {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		fileValues, err := ParseFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			result.Layers = append(result.Layers, Layer{File: file})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Layers = append(result.Layers, Layer{File: file, Found: true})
+		for name, value := range fileValues {
+			values[name] = Value{Name: name, Value: value, Source: file}
+		}
+	}
+
+	if len(vars) > 0 {
+		result.Layers = append(result.Layers, Layer{File: CLISource, Found: true})
+	}
+	for _, v := range vars {
+		name, value, err := ParseVar(v)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = Value{Name: name, Value: value, Source: CLISource}
+	}
+
+	for _, v := range values {
+		result.Values = append(result.Values, v)
+	}
+	sort.Slice(result.Values, func(i, j int) bool { return result.Values[i].Name < result.Values[j].Name })
+	return result, nil
+}
+
+// ParseFile reads the values of a .tfvars or .tfvars.json file.
+func ParseFile(path string) (map[string]cty.Value, error) {
+	src, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var f *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		f, diags = hcljson.Parse(src, path)
+	} else {
+		f, diags = hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	}
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, diags)
+	}
+	attrs, diags := f.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, diags)
+	}
+
+	values := make(map[string]cty.Value, len(attrs))
+	for name, attr := range attrs {
+		value, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("%s: variable %s: %w", path, name, diags)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// ParseVar parses a -var value of the form name=value. As in terraform, the
+// value is a string unless it is a list or object literal.
+func ParseVar(v string) (string, cty.Value, error) {
+	name, raw, ok := strings.Cut(v, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", cty.NilVal, fmt.Errorf("invalid -var '%s': must be name=value", v)
+	}
+
+	trimmed := strings.TrimSpace(raw)
+	if !strings.HasPrefix(trimmed, "[") && !strings.HasPrefix(trimmed, "{") {
+		return name, cty.StringVal(raw), nil
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(trimmed), CLISource, hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", cty.NilVal, fmt.Errorf("invalid -var '%s': %w", name, diags)
+	}
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return "", cty.NilVal, fmt.Errorf("invalid -var '%s': %w", name, diags)
+	}
+	return name, value, nil
+}
+
+// Render formats values as a .tfvars file, with a comment above each value
+// naming the layer that set it.
+func Render(values []Value) []byte {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+	for i, v := range values {
+		if i > 0 {
+			body.AppendNewline()
+		}
+		body.AppendUnstructuredTokens(hclwrite.Tokens{
+			{Type: hclsyntax.TokenComment, Bytes: []byte("# From " + v.Source + "\n")},
+		})
+		body.SetAttributeValue(v.Name, v.Value)
+	}
+	return hclwrite.Format(f.Bytes())
+}
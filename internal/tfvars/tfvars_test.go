@@ -0,0 +1,165 @@
+package tfvars
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+var layers = []string{"vars/base.tfvars", "vars/{env}.tfvars", "vars/{env}/{region}.tfvars.json"}
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr string
+	}{
+		{pattern: "vars/{env}/{region}.tfvars"},
+		{pattern: "", wantErr: "path is required"},
+		{pattern: "/etc/base.tfvars", wantErr: "must be relative"},
+		{pattern: "vars/{stage}.tfvars", wantErr: "unknown placeholder {stage}"},
+	}
+	for _, tt := range tests {
+		err := ValidatePattern(tt.pattern)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("ValidatePattern(%q) error = %v", tt.pattern, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ValidatePattern(%q) error = %v, want %q", tt.pattern, err, tt.wantErr)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		want   []string
+	}{
+		{name: "no params", want: []string{"vars/base.tfvars"}},
+		{name: "env", params: Params{Env: "prod"}, want: []string{"vars/base.tfvars", "vars/prod.tfvars"}},
+		{name: "env and region", params: Params{Env: "prod", Region: "weu"}, want: []string{"vars/base.tfvars", "vars/prod.tfvars", "vars/prod/weu.tfvars.json"}},
+		{name: "region only", params: Params{Region: "weu"}, want: []string{"vars/base.tfvars"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Expand(layers, tt.params); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "vars", "base.tfvars"), `location = "westeurope"
+sku      = "Standard"
+tags     = { team = "platform", cost = "shared" }
+`)
+	writeFile(t, filepath.Join(dir, "vars", "prod.tfvars"), `sku  = "Premium"
+tags = { team = "platform" }
+`)
+	writeFile(t, filepath.Join(dir, "vars", "prod", "weu.tfvars.json"), `{"location": "northeurope", "zones": [1, 2]}`)
+
+	result, err := Resolve(dir, layers, Params{Env: "prod", Region: "weu"}, []string{"replicas=3", "zones=[3]"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	wantLayers := []Layer{
+		{File: "vars/base.tfvars", Found: true},
+		{File: "vars/prod.tfvars", Found: true},
+		{File: "vars/prod/weu.tfvars.json", Found: true},
+		{File: CLISource, Found: true},
+	}
+	if !reflect.DeepEqual(result.Layers, wantLayers) {
+		t.Errorf("Layers = %+v, want %+v", result.Layers, wantLayers)
+	}
+
+	want := map[string]string{
+		"location": "vars/prod/weu.tfvars.json",
+		"replicas": CLISource,
+		"sku":      "vars/prod.tfvars",
+		"tags":     "vars/prod.tfvars",
+		"zones":    CLISource,
+	}
+	var names []string
+	for _, v := range result.Values {
+		names = append(names, v.Name)
+		if v.Source != want[v.Name] {
+			t.Errorf("%s: source = %s, want %s", v.Name, v.Source, want[v.Name])
+		}
+	}
+	if strings.Join(names, ",") != "location,replicas,sku,tags,zones" {
+		t.Errorf("values = %v, want sorted by name", names)
+	}
+
+	// A higher layer replaces the whole value instead of merging objects
+	tags := result.Values[3].Value
+	if tags.LengthInt() != 1 || !tags.GetAttr("team").RawEquals(cty.StringVal("platform")) {
+		t.Errorf("tags = %#v, want only team", tags)
+	}
+	if got := result.Values[1].Value; !got.RawEquals(cty.StringVal("3")) {
+		t.Errorf("replicas = %#v, want the string 3", got)
+	}
+}
+
+func TestResolve_MissingLayer(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "vars", "base.tfvars"), `sku = "Standard"`)
+
+	result, err := Resolve(dir, layers, Params{Env: "dev"}, nil)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(result.Layers) != 2 || result.Layers[1].Found {
+		t.Errorf("Layers = %+v, want vars/dev.tfvars not found", result.Layers)
+	}
+	if len(result.Values) != 1 {
+		t.Errorf("Values = %+v, want only sku", result.Values)
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "vars", "base.tfvars"), `sku = var.other`)
+
+	if _, err := Resolve(dir, layers, Params{}, nil); err == nil || !strings.Contains(err.Error(), "variable sku") {
+		t.Errorf("expected an error for a non-literal value, got %v", err)
+	}
+	if _, err := Resolve(t.TempDir(), layers, Params{}, []string{"novalue"}); err == nil || !strings.Contains(err.Error(), "must be name=value") {
+		t.Errorf("expected an error for an invalid -var, got %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	got := string(Render([]Value{
+		{Name: "location", Value: cty.StringVal("westeurope"), Source: "vars/base.tfvars"},
+		{Name: "zones", Value: cty.TupleVal([]cty.Value{cty.NumberIntVal(1)}), Source: CLISource},
+	}))
+	want := `# From vars/base.tfvars
+location = "westeurope"
+
+# From -var
+zones = [1]
+`
+	if got != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,370 @@
+// Package toolchain installs terraform and tofu releases into a cache
+// directory and checks the version of a binary against the binary_version
+// constraint of .motf.yml, like tfenv.
+package toolchain
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/download"
+	version "github.com/hashicorp/go-version"
+)
+
+// EnvDir overrides the toolchain cache directory
+const EnvDir = "MOTF_TOOLCHAIN_DIR"
+
+// Dir returns the directory releases are installed in: $MOTF_TOOLCHAIN_DIR,
+// or motf/toolchain in the user cache directory.
+func Dir() (string, error) {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir, nil
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the cache directory, set %s: %w", EnvDir, err)
+	}
+	return filepath.Join(cache, "motf", "toolchain"), nil
+}
+
+// source is where the releases of a binary are published. URLs may contain
+// {version}, {os}, and {arch} placeholders.
+type source struct {
+	index   string                              // Release index
+	parse   func(data []byte) ([]string, error) // Versions in the index
+	archive string                              // Zip archive of a release
+	sums    string                              // SHA256SUMS file of a release
+}
+
+// sources are the release sources by binary name
+var sources = map[string]source{
+	"terraform": {
+		index:   "https://releases.hashicorp.com/terraform/index.json",
+		parse:   parseHashiCorpIndex,
+		archive: "https://releases.hashicorp.com/terraform/{version}/terraform_{version}_{os}_{arch}.zip",
+		sums:    "https://releases.hashicorp.com/terraform/{version}/terraform_{version}_SHA256SUMS",
+	},
+	"tofu": {
+		index:   "https://get.opentofu.org/tofu/api.json",
+		parse:   parseOpenTofuIndex,
+		archive: "https://github.com/opentofu/opentofu/releases/download/v{version}/tofu_{version}_{os}_{arch}.zip",
+		sums:    "https://github.com/opentofu/opentofu/releases/download/v{version}/tofu_{version}_SHA256SUMS",
+	},
+}
+
+// parseHashiCorpIndex returns the versions of a releases.hashicorp.com index.
+func parseHashiCorpIndex(data []byte) ([]string, error) {
+	var index struct {
+		Versions map[string]json.RawMessage `json:"versions"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(index.Versions))
+	for v := range index.Versions {
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// parseOpenTofuIndex returns the versions of the get.opentofu.org index.
+func parseOpenTofuIndex(data []byte) ([]string, error) {
+	var index struct {
+		Versions []struct {
+			ID string `json:"id"`
+		} `json:"versions"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(index.Versions))
+	for _, v := range index.Versions {
+		result = append(result, v.ID)
+	}
+	return result, nil
+}
+
+// executable returns the file name of binary on this platform.
+func executable(binary string) string {
+	if runtime.GOOS == "windows" {
+		return binary + ".exe"
+	}
+	return binary
+}
+
+// Path returns the path of an installed release of binary in dir.
+func Path(dir, binary, v string) string {
+	return filepath.Join(dir, binary, v, executable(binary))
+}
+
+// Installed returns the path and version of the newest release of binary
+// in dir that satisfies constraint, or empty strings when none is installed.
+func Installed(dir, binary, constraint string) (string, string, error) {
+	c, err := parseConstraint(constraint)
+	if err != nil {
+		return "", "", err
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, binary))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", nil
+		}
+		return "", "", fmt.Errorf("failed to read toolchain directory: %w", err)
+	}
+	var installed []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			installed = append(installed, entry.Name())
+		}
+	}
+	for _, v := range newest(installed, c) {
+		path := Path(dir, binary, v.Original())
+		if _, err := os.Stat(path); err == nil {
+			return path, v.Original(), nil
+		}
+	}
+	return "", "", nil
+}
+
+// parseConstraint parses a version constraint; empty allows any version.
+func parseConstraint(constraint string) (version.Constraints, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return nil, nil
+	}
+	c, err := version.NewConstraint(constraint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version constraint '%s': %w", constraint, err)
+	}
+	return c, nil
+}
+
+// newest returns the stable versions satisfying c, newest first. Versions
+// that don't parse are skipped.
+func newest(versions []string, c version.Constraints) []*version.Version {
+	var result []*version.Version
+	for _, s := range versions {
+		v, err := version.NewVersion(s)
+		if err != nil || v.Prerelease() != "" || (c != nil && !c.Check(v)) {
+			continue
+		}
+		result = append(result, v)
+	}
+	sort.Sort(sort.Reverse(version.Collection(result)))
+	return result
+}
+
+// versionCache holds the versions of binaries, by path, so a run over many
+// modules runs version -json once per binary
+var versionCache sync.Map
+
+// Version returns the version a terraform/tofu binary reports with
+// version -json.
+func Version(binary string) (string, error) {
+	if v, ok := versionCache.Load(binary); ok {
+		return v.(string), nil
+	}
+	out, err := exec.Command(binary, "version", "-json").Output() //nolint:gosec // binary is terraform or tofu
+	if err != nil {
+		return "", fmt.Errorf("%s version -json failed: %w", binary, err)
+	}
+	v, err := parseVersionJSON(out)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s version -json: %w", binary, err)
+	}
+	versionCache.Store(binary, v)
+	return v, nil
+}
+
+// parseVersionJSON returns the version of version -json output, which both
+// terraform and tofu report as terraform_version.
+func parseVersionJSON(data []byte) (string, error) {
+	var out struct {
+		Version string `json:"terraform_version"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", err
+	}
+	if out.Version == "" {
+		return "", fmt.Errorf("no terraform_version")
+	}
+	return out.Version, nil
+}
+
+// Satisfies reports whether version v satisfies constraint. Empty allows
+// any version.
+func Satisfies(v, constraint string) (bool, error) {
+	c, err := parseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	if c == nil {
+		return true, nil
+	}
+	parsed, err := version.NewVersion(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid version '%s': %w", v, err)
+	}
+	return c.Check(parsed), nil
+}
+
+// Release is an installed release of a binary.
+type Release struct {
+	Binary  string
+	Version string
+	Path    string
+	Cached  bool // Already installed before Install
+}
+
+// Installer downloads releases into Dir.
+type Installer struct {
+	Dir        string
+	HTTPClient *http.Client
+	OS, Arch   string // Platform of the releases, runtime.GOOS and runtime.GOARCH by default
+}
+
+// NewInstaller returns an installer for this platform that installs into dir.
+func NewInstaller(dir string) *Installer {
+	return &Installer{
+		Dir:        dir,
+		HTTPClient: &http.Client{Timeout: 5 * time.Minute},
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+// Releases returns the stable releases of binary that satisfy constraint,
+// newest first.
+func (i *Installer) Releases(ctx context.Context, binary, constraint string) ([]string, error) {
+	src, ok := sources[binary]
+	if !ok {
+		return nil, fmt.Errorf("no releases of '%s': must be 'terraform' or 'tofu'", binary)
+	}
+	c, err := parseConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+	data, err := i.get(ctx, src.index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s releases: %w", binary, err)
+	}
+	all, err := src.parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s releases: %w", binary, err)
+	}
+	var result []string
+	for _, v := range newest(all, c) {
+		result = append(result, v.Original())
+	}
+	return result, nil
+}
+
+// Install installs the newest release of binary satisfying constraint,
+// unless one is installed already. The archive is verified against the
+// SHA256SUMS of the release.
+func (i *Installer) Install(ctx context.Context, binary, constraint string) (Release, error) {
+	releases, err := i.Releases(ctx, binary, constraint)
+	if err != nil {
+		return Release{}, err
+	}
+	if len(releases) == 0 {
+		return Release{}, fmt.Errorf("no %s release matches '%s'", binary, constraint)
+	}
+	v := releases[0]
+	release := Release{Binary: binary, Version: v, Path: Path(i.Dir, binary, v)}
+	if _, err := os.Stat(release.Path); err == nil {
+		release.Cached = true
+		return release, nil
+	}
+
+	src := sources[binary]
+	archiveURL := i.expand(src.archive, v)
+	archive, err := i.get(ctx, archiveURL)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to download %s %s: %w", binary, v, err)
+	}
+	sums, err := i.get(ctx, i.expand(src.sums, v))
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to download %s %s checksums: %w", binary, v, err)
+	}
+	if err := download.Verify(archive, sums, archiveURL[strings.LastIndex(archiveURL, "/")+1:]); err != nil {
+		return Release{}, fmt.Errorf("%s %s: %w", binary, v, err)
+	}
+	if err := extract(archive, executable(binary), release.Path); err != nil {
+		return Release{}, fmt.Errorf("failed to install %s %s: %w", binary, v, err)
+	}
+	return release, nil
+}
+
+// expand replaces the placeholders of a source URL.
+func (i *Installer) expand(url, v string) string {
+	return strings.NewReplacer("{version}", v, "{os}", i.OS, "{arch}", i.Arch).Replace(url)
+}
+
+// get returns the body of a GET request to url.
+func (i *Installer) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := i.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// extract writes the file name of the zip archive to path, through a
+// temporary file so an interrupted install leaves nothing behind.
+func extract(archive []byte, name, path string) error {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint:gosec // the cache is readable like the binaries in it
+			return err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rc.Close() }()
+		tmp, err := os.CreateTemp(filepath.Dir(path), name+".tmp*")
+		if err != nil {
+			return err
+		}
+		defer func() { _ = os.Remove(tmp.Name()) }()
+		if _, err := io.Copy(tmp, rc); err != nil { //nolint:gosec // the archive is verified against the release checksums
+			_ = tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		if err := os.Chmod(tmp.Name(), 0o755); err != nil { //nolint:gosec // the binary must be executable
+			return err
+		}
+		return os.Rename(tmp.Name(), path)
+	}
+	return fmt.Errorf("no %s in the archive", name)
+}
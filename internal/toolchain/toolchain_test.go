@@ -0,0 +1,197 @@
+package toolchain
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/download/downloadtest"
+)
+
+// fakeReleases returns an installer whose requests are answered from files,
+// by URL; other URLs are not found.
+func fakeReleases(t *testing.T, files map[string][]byte) *Installer {
+	t.Helper()
+	i := NewInstaller(t.TempDir())
+	i.OS, i.Arch = "linux", "amd64"
+	i.HTTPClient = downloadtest.Client(files, nil)
+	return i
+}
+
+// zipOf returns a zip archive with a file name holding content.
+func zipOf(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// terraformReleases returns the files of a terraform release index with
+// 1.9.5 downloadable.
+func terraformReleases(t *testing.T) map[string][]byte {
+	t.Helper()
+	archive := zipOf(t, "terraform", "#!/bin/sh\n")
+	sum := sha256.Sum256(archive)
+	base := "https://releases.hashicorp.com/terraform/"
+	return map[string][]byte{
+		base + "index.json":                            []byte(`{"name": "terraform", "versions": {"1.8.5": {}, "1.9.4": {}, "1.9.5": {}, "1.10.0-beta1": {}}}`),
+		base + "1.9.5/terraform_1.9.5_linux_amd64.zip": archive,
+		base + "1.9.5/terraform_1.9.5_SHA256SUMS":      []byte(hex.EncodeToString(sum[:]) + "  terraform_1.9.5_linux_amd64.zip\n"),
+	}
+}
+
+func TestInstaller_Releases(t *testing.T) {
+	i := fakeReleases(t, terraformReleases(t))
+	got, err := i.Releases(context.Background(), "terraform", ">= 1.9")
+	if err != nil {
+		t.Fatalf("Releases() error = %v", err)
+	}
+	if strings.Join(got, " ") != "1.9.5 1.9.4" {
+		t.Errorf("Releases() = %v, want [1.9.5 1.9.4]", got)
+	}
+
+	tofu := fakeReleases(t, map[string][]byte{
+		"https://get.opentofu.org/tofu/api.json": []byte(`{"versions": [{"id": "1.8.1"}, {"id": "1.9.0-rc1"}, {"id": "1.7.3"}]}`),
+	})
+	got, err = tofu.Releases(context.Background(), "tofu", "")
+	if err != nil {
+		t.Fatalf("Releases() error = %v", err)
+	}
+	if strings.Join(got, " ") != "1.8.1 1.7.3" {
+		t.Errorf("Releases() = %v, want [1.8.1 1.7.3]", got)
+	}
+
+	if _, err := i.Releases(context.Background(), "terragrunt", ""); err == nil {
+		t.Error("expected an error for an unknown binary")
+	}
+}
+
+func TestInstaller_Install(t *testing.T) {
+	i := fakeReleases(t, terraformReleases(t))
+	release, err := i.Install(context.Background(), "terraform", "~> 1.9.0")
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	want := filepath.Join(i.Dir, "terraform", "1.9.5", executable("terraform"))
+	if release.Version != "1.9.5" || release.Path != want || release.Cached {
+		t.Errorf("Install() = %+v, want 1.9.5 at %s", release, want)
+	}
+	info, err := os.Stat(want)
+	if err != nil {
+		t.Fatalf("binary not installed: %v", err)
+	}
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("installed binary isn't executable: %v", info.Mode())
+	}
+
+	release, err = i.Install(context.Background(), "terraform", "1.9.5")
+	if err != nil || !release.Cached {
+		t.Errorf("Install() again = %+v, %v, want cached", release, err)
+	}
+
+	path, v, err := Installed(i.Dir, "terraform", ">= 1.9")
+	if err != nil || path != want || v != "1.9.5" {
+		t.Errorf("Installed() = %q, %q, %v, want %q, 1.9.5", path, v, err, want)
+	}
+	if path, _, _ := Installed(i.Dir, "terraform", "< 1.9"); path != "" {
+		t.Errorf("Installed(< 1.9) = %q, want none", path)
+	}
+	if path, _, _ := Installed(i.Dir, "tofu", ""); path != "" {
+		t.Errorf("Installed(tofu) = %q, want none", path)
+	}
+}
+
+func TestInstaller_InstallErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		change     func(files map[string][]byte)
+		wantErr    string
+	}{
+		{name: "no matching release", constraint: "~> 2.0", wantErr: "no terraform release matches '~> 2.0'"},
+		{name: "invalid constraint", constraint: "latest", wantErr: "invalid version constraint 'latest'"},
+		{
+			name: "checksum mismatch", constraint: "1.9.5",
+			change: func(files map[string][]byte) {
+				files["https://releases.hashicorp.com/terraform/1.9.5/terraform_1.9.5_SHA256SUMS"] = []byte("0000  terraform_1.9.5_linux_amd64.zip\n")
+			},
+			wantErr: "checksum mismatch of terraform_1.9.5_linux_amd64.zip",
+		},
+		{
+			name: "missing checksum", constraint: "1.9.5",
+			change: func(files map[string][]byte) {
+				files["https://releases.hashicorp.com/terraform/1.9.5/terraform_1.9.5_SHA256SUMS"] = []byte("")
+			},
+			wantErr: "no checksum for terraform_1.9.5_linux_amd64.zip",
+		},
+		{
+			name: "missing archive", constraint: "1.9.5",
+			change: func(files map[string][]byte) {
+				delete(files, "https://releases.hashicorp.com/terraform/1.9.5/terraform_1.9.5_linux_amd64.zip")
+			},
+			wantErr: "failed to download terraform 1.9.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := terraformReleases(t)
+			if tt.change != nil {
+				tt.change(files)
+			}
+			i := fakeReleases(t, files)
+			_, err := i.Install(context.Background(), "terraform", tt.constraint)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Install() error = %v, want %q", err, tt.wantErr)
+			}
+			if path, _, _ := Installed(i.Dir, "terraform", ""); path != "" {
+				t.Errorf("failed install left %s behind", path)
+			}
+		})
+	}
+}
+
+func TestParseVersionJSON(t *testing.T) {
+	got, err := parseVersionJSON([]byte(`{"terraform_version": "1.9.5", "platform": "linux_amd64", "provider_selections": {}}`))
+	if err != nil || got != "1.9.5" {
+		t.Errorf("parseVersionJSON() = %q, %v, want 1.9.5", got, err)
+	}
+	if _, err := parseVersionJSON([]byte(`{}`)); err == nil {
+		t.Error("expected an error without terraform_version")
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.9.5", "", true},
+		{"1.9.5", "~> 1.9.0", true},
+		{"1.10.0", "~> 1.9.0", false},
+		{"1.5.7", ">= 1.6", false},
+	}
+	for _, tt := range tests {
+		got, err := Satisfies(tt.version, tt.constraint)
+		if err != nil || got != tt.want {
+			t.Errorf("Satisfies(%q, %q) = %v, %v, want %v", tt.version, tt.constraint, got, err, tt.want)
+		}
+	}
+}
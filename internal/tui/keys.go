@@ -0,0 +1,82 @@
+package tui
+
+import "unicode/utf8"
+
+// KeyCode identifies a key press.
+type KeyCode int
+
+// Keys the UI handles; printable characters are KeyRune
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyPageUp
+	KeyPageDown
+	KeyEnter
+	KeyEscape
+	KeyCtrlC
+)
+
+// Key is a key press.
+type Key struct {
+	Code KeyCode
+	Rune rune // Set for KeyRune
+}
+
+// escapeSequences are the ANSI sequences of the special keys
+var escapeSequences = map[string]KeyCode{
+	"\x1b[A":  KeyUp,
+	"\x1b[B":  KeyDown,
+	"\x1bOA":  KeyUp,
+	"\x1bOB":  KeyDown,
+	"\x1b[5~": KeyPageUp,
+	"\x1b[6~": KeyPageDown,
+}
+
+// ParseKeys returns the key presses in input read from a terminal in raw
+// mode. Unknown escape sequences are dropped.
+func ParseKeys(input []byte) []Key {
+	var keys []Key
+	for len(input) > 0 {
+		if input[0] == 0x1b {
+			if len(input) == 1 {
+				keys = append(keys, Key{Code: KeyEscape})
+				break
+			}
+			n := escapeLength(input)
+			if code, ok := escapeSequences[string(input[:n])]; ok {
+				keys = append(keys, Key{Code: code})
+			}
+			input = input[n:]
+			continue
+		}
+		switch input[0] {
+		case 3:
+			keys = append(keys, Key{Code: KeyCtrlC})
+			input = input[1:]
+			continue
+		case '\r', '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+			input = input[1:]
+			continue
+		}
+		r, size := utf8.DecodeRune(input)
+		keys = append(keys, Key{Code: KeyRune, Rune: r})
+		input = input[size:]
+	}
+	return keys
+}
+
+// escapeLength returns the length of the escape sequence at the start of
+// input: ESC [ or ESC O, parameters, and a final byte.
+func escapeLength(input []byte) int {
+	if input[1] != '[' && input[1] != 'O' {
+		return 1
+	}
+	for i := 2; i < len(input); i++ {
+		if input[i] >= 0x40 && input[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(input)
+}
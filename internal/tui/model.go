@@ -0,0 +1,361 @@
+// Package tui is the interactive terminal UI of 'motf ui': a module list with
+// a details panel, running commands on selected modules and streaming their
+// output.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Item is a module in the list.
+type Item struct {
+	Name string
+	Type string
+	Path string // Relative to the root
+}
+
+// Action is a command that can be run on the selected modules.
+type Action struct {
+	Key  rune   // Key that runs the action
+	Name string // e.g. fmt
+}
+
+// Options configure the UI.
+type Options struct {
+	Items   []Item
+	Actions []Action
+
+	// Details returns the lines shown in the side panel for an item.
+	Details func(Item) []string
+
+	// Run runs an action on items, writing its output to out. It's called
+	// outside the UI loop; output is shown as it's written.
+	Run func(action string, items []Item, out io.Writer) error
+}
+
+// maxLogLines is the number of output lines kept for the output pane
+const maxLogLines = 1000
+
+// Model is the state of the UI. Log methods are safe for concurrent use with
+// the rest, so a run can stream output while the UI is drawn.
+type Model struct {
+	opts     Options
+	cursor   int
+	offset   int // First item shown in the list
+	selected map[int]bool
+
+	details      []string
+	detailsIndex int // Item the details are for, -1 if none
+
+	mu      sync.Mutex
+	log     []string
+	running string // Action in progress, empty if idle
+	status  string
+}
+
+// NewModel returns the model of a UI with the cursor on the first item.
+func NewModel(opts Options) *Model {
+	return &Model{opts: opts, selected: map[int]bool{}, detailsIndex: -1}
+}
+
+// Effect is what the UI loop does after a key.
+type Effect int
+
+// Effects of a key
+const (
+	EffectNone Effect = iota
+	EffectQuit
+	EffectRun // Run the action returned with it
+)
+
+// HandleKey updates the model for a key press, and returns what the UI loop
+// should do next.
+func (m *Model) HandleKey(k Key) (Effect, string) {
+	switch k.Code {
+	case KeyUp:
+		m.move(-1)
+	case KeyDown:
+		m.move(1)
+	case KeyPageUp:
+		m.move(-10)
+	case KeyPageDown:
+		m.move(10)
+	case KeyCtrlC:
+		return EffectQuit, ""
+	case KeyRune:
+		return m.handleRune(k.Rune)
+	}
+	return EffectNone, ""
+}
+
+func (m *Model) handleRune(r rune) (Effect, string) {
+	switch r {
+	case 'q':
+		if m.Running() != "" {
+			m.SetStatus("Wait for " + m.Running() + " to finish, or press Ctrl+C to quit")
+			return EffectNone, ""
+		}
+		return EffectQuit, ""
+	case 'k':
+		m.move(-1)
+	case 'j':
+		m.move(1)
+	case ' ':
+		if len(m.opts.Items) > 0 {
+			m.selected[m.cursor] = !m.selected[m.cursor]
+		}
+	case 'a':
+		// Select all, or clear the selection if everything is selected
+		all := len(m.Selection()) == len(m.opts.Items)
+		for i := range m.opts.Items {
+			m.selected[i] = !all
+		}
+	case 'c':
+		m.ClearLog()
+	default:
+		for _, a := range m.opts.Actions {
+			if a.Key != r {
+				continue
+			}
+			if m.Running() != "" {
+				m.SetStatus(m.Running() + " is still running")
+				return EffectNone, ""
+			}
+			if len(m.Targets()) == 0 {
+				return EffectNone, ""
+			}
+			return EffectRun, a.Name
+		}
+	}
+	return EffectNone, ""
+}
+
+// move moves the cursor by delta items, within the list.
+func (m *Model) move(delta int) {
+	m.cursor = max(0, min(len(m.opts.Items)-1, m.cursor+delta))
+}
+
+// Cursor returns the index of the item under the cursor.
+func (m *Model) Cursor() int {
+	return m.cursor
+}
+
+// Selection returns the selected items in list order.
+func (m *Model) Selection() []Item {
+	var items []Item
+	for i, item := range m.opts.Items {
+		if m.selected[i] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Targets returns the items an action runs on: the selection, or the item
+// under the cursor when nothing is selected.
+func (m *Model) Targets() []Item {
+	if items := m.Selection(); len(items) > 0 {
+		return items
+	}
+	if len(m.opts.Items) == 0 {
+		return nil
+	}
+	return []Item{m.opts.Items[m.cursor]}
+}
+
+// Running returns the action in progress, or empty.
+func (m *Model) Running() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.running
+}
+
+// SetRunning marks action as in progress, or the run as finished if empty.
+func (m *Model) SetRunning(action string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running = action
+}
+
+// SetStatus sets the message shown in the status line.
+func (m *Model) SetStatus(status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = status
+}
+
+// AppendLog adds a line to the output pane, dropping the oldest lines past
+// maxLogLines.
+func (m *Model) AppendLog(line string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.log = append(m.log, line)
+	if len(m.log) > maxLogLines {
+		m.log = m.log[len(m.log)-maxLogLines:]
+	}
+}
+
+// ClearLog empties the output pane.
+func (m *Model) ClearLog() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.log = nil
+}
+
+// currentDetails returns the details of the item under the cursor, loading
+// them when the cursor moved.
+func (m *Model) currentDetails() []string {
+	if len(m.opts.Items) == 0 || m.opts.Details == nil {
+		return nil
+	}
+	if m.detailsIndex != m.cursor {
+		m.details = m.opts.Details(m.opts.Items[m.cursor])
+		m.detailsIndex = m.cursor
+	}
+	return m.details
+}
+
+// View renders the UI for a terminal of width by height cells, one string
+// per line.
+func (m *Model) View(width, height int) []string {
+	width, height = max(width, 20), max(height, 8)
+	logHeight := height / 3
+	listHeight := height - logHeight - 3 // Header, output separator, and status lines
+
+	// Keep the cursor visible
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+listHeight {
+		m.offset = m.cursor - listHeight + 1
+	}
+
+	lines := []string{fit(fmt.Sprintf("motf ui: %d module(s), %d selected", len(m.opts.Items), len(m.Selection())), width)}
+
+	listWidth := max(width*2/5, 10)
+	details := m.currentDetails()
+	for row := 0; row < listHeight; row++ {
+		left := ""
+		if i := m.offset + row; i < len(m.opts.Items) {
+			left = m.itemLine(i)
+		}
+		right := ""
+		if row < len(details) {
+			right = details[row]
+		}
+		line := fit(left, listWidth) + " │ " + right
+		lines = append(lines, fit(line, width))
+	}
+
+	m.mu.Lock()
+	title := "output"
+	if m.running != "" {
+		title = m.running + " running"
+	}
+	lines = append(lines, fit("── "+title+" "+strings.Repeat("─", width), width))
+	start := max(0, len(m.log)-logHeight)
+	for row := 0; row < logHeight; row++ {
+		line := ""
+		if start+row < len(m.log) {
+			line = m.log[start+row]
+		}
+		lines = append(lines, fit(line, width))
+	}
+	status := m.status
+	m.mu.Unlock()
+
+	if status == "" {
+		status = m.help()
+	}
+	return append(lines, fit(status, width))
+}
+
+// itemLine renders the list entry of item i.
+func (m *Model) itemLine(i int) string {
+	pointer, check := "  ", "[ ]"
+	if i == m.cursor {
+		pointer = "> "
+	}
+	if m.selected[i] {
+		check = "[x]"
+	}
+	item := m.opts.Items[i]
+	return fmt.Sprintf("%s%s %s (%s)", pointer, check, item.Name, item.Type)
+}
+
+// help returns the key bindings shown in the status line.
+func (m *Model) help() string {
+	parts := []string{"↑/↓ move", "space select", "a all"}
+	for _, a := range m.opts.Actions {
+		parts = append(parts, fmt.Sprintf("%c %s", a.Key, a.Name))
+	}
+	return strings.Join(append(parts, "c clear", "q quit"), "  ")
+}
+
+// fit pads or truncates s to width cells, counting runes. ANSI escape
+// sequences, like the colored module prefixes of command output, take no
+// cells and are kept.
+func fit(s string, width int) string {
+	var b strings.Builder
+	cells, escaped := 0, false
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == 0x1b {
+			n := escapeLength([]byte(string(runes[i:])))
+			b.WriteString(string(runes[i : i+n]))
+			i += n - 1
+			escaped = true
+			continue
+		}
+		if cells == width {
+			break
+		}
+		b.WriteRune(runes[i])
+		cells++
+	}
+	if escaped {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String() + strings.Repeat(" ", width-cells)
+}
+
+// logWriter writes complete lines of its output to the model's output pane.
+type logWriter struct {
+	mu      sync.Mutex
+	model   *Model
+	pending []byte
+	changed func() // Called after lines are added
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	added := false
+	for {
+		i := strings.IndexByte(string(w.pending), '\n')
+		if i < 0 {
+			break
+		}
+		w.model.AppendLog(strings.TrimRight(string(w.pending[:i]), "\r"))
+		w.pending = w.pending[i+1:]
+		added = true
+	}
+	if added && w.changed != nil {
+		w.changed()
+	}
+	return len(p), nil
+}
+
+// Flush adds the last line if it didn't end with a newline.
+func (w *logWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) > 0 {
+		w.model.AppendLog(string(w.pending))
+		w.pending = nil
+	}
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// ErrNotTerminal is returned by Run when input or output isn't a terminal.
+var ErrNotTerminal = errors.New("motf ui needs an interactive terminal")
+
+// resizeInterval is how often the terminal size is checked for changes
+const resizeInterval = 250 * time.Millisecond
+
+// Run shows the UI on the terminal until the user quits. The terminal is
+// put in raw mode on the alternate screen and restored before returning.
+func Run(in, out *os.File, opts Options) error {
+	inFd, outFd := int(in.Fd()), int(out.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return ErrNotTerminal
+	}
+
+	state, err := term.MakeRaw(inFd)
+	if err != nil {
+		return fmt.Errorf("failed to set up terminal: %w", err)
+	}
+	defer func() { _ = term.Restore(inFd, state) }()
+
+	// Alternate screen, hidden cursor
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	model := NewModel(opts)
+	redraw := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case redraw <- struct{}{}:
+		default:
+		}
+	}
+
+	keys := make(chan Key)
+	go readKeys(in, keys)
+
+	ticker := time.NewTicker(resizeInterval)
+	defer ticker.Stop()
+
+	width, height := 0, 0
+	draw := func() {
+		w, h, err := term.GetSize(outFd)
+		if err != nil {
+			w, h = 80, 24
+		}
+		width, height = w, h
+		// Lines end in \r\n as raw mode doesn't translate \n
+		fmt.Fprint(out, "\x1b[H\x1b[2J"+strings.Join(model.View(w, h), "\r\n"))
+	}
+	draw()
+
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			model.SetStatus("")
+			effect, action := model.HandleKey(k)
+			switch effect {
+			case EffectQuit:
+				return nil
+			case EffectRun:
+				start(model, action, notify)
+			}
+			draw()
+		case <-redraw:
+			draw()
+		case <-ticker.C:
+			if w, h, err := term.GetSize(outFd); err == nil && (w != width || h != height) {
+				draw()
+			}
+		}
+	}
+}
+
+// start runs action on the model's targets in the background, streaming
+// output to the output pane.
+func start(model *Model, action string, changed func()) {
+	items := model.Targets()
+	model.SetRunning(action)
+	model.AppendLog(fmt.Sprintf("$ motf %s (%d module(s))", action, len(items)))
+	go func() {
+		w := &logWriter{model: model, changed: changed}
+		err := model.opts.Run(action, items, w)
+		w.Flush()
+		if err != nil {
+			model.SetStatus(fmt.Sprintf("%s failed: %v", action, err))
+		} else {
+			model.SetStatus(action + " finished")
+		}
+		model.SetRunning("")
+		changed()
+	}()
+}
+
+// readKeys sends the keys read from in until reading fails.
+func readKeys(in *os.File, keys chan<- Key) {
+	defer close(keys)
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, k := range ParseKeys(buf[:n]) {
+			keys <- k
+		}
+	}
+}
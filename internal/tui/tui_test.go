@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func testOptions(n int) Options {
+	items := make([]Item, n)
+	for i := range items {
+		items[i] = Item{Name: fmt.Sprintf("mod%d", i), Type: "component", Path: fmt.Sprintf("components/mod%d", i)}
+	}
+	return Options{
+		Items:   items,
+		Actions: []Action{{Key: 'f', Name: "fmt"}, {Key: 'p', Name: "plan"}},
+		Details: func(item Item) []string { return []string{"Module: " + item.Name} },
+		Run:     func(string, []Item, io.Writer) error { return nil },
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys := ParseKeys([]byte("j\x1b[A\x1b[B\x1b[6~ q\r\x03\x1b[1;5C\x1b"))
+	want := []Key{
+		{Code: KeyRune, Rune: 'j'},
+		{Code: KeyUp},
+		{Code: KeyDown},
+		{Code: KeyPageDown},
+		{Code: KeyRune, Rune: ' '},
+		{Code: KeyRune, Rune: 'q'},
+		{Code: KeyEnter},
+		{Code: KeyCtrlC},
+		// Unknown ctrl+right sequence is dropped
+		{Code: KeyEscape},
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("ParseKeys() = %+v, want %+v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("key %d = %+v, want %+v", i, keys[i], want[i])
+		}
+	}
+}
+
+func TestHandleKey_Navigation(t *testing.T) {
+	m := NewModel(testOptions(3))
+
+	m.HandleKey(Key{Code: KeyUp})
+	if m.Cursor() != 0 {
+		t.Errorf("cursor = %d, want 0 at the top", m.Cursor())
+	}
+	m.HandleKey(Key{Code: KeyRune, Rune: 'j'})
+	m.HandleKey(Key{Code: KeyDown})
+	m.HandleKey(Key{Code: KeyDown})
+	if m.Cursor() != 2 {
+		t.Errorf("cursor = %d, want 2 at the bottom", m.Cursor())
+	}
+	m.HandleKey(Key{Code: KeyPageUp})
+	if m.Cursor() != 0 {
+		t.Errorf("cursor = %d, want 0 after page up", m.Cursor())
+	}
+}
+
+func TestHandleKey_Selection(t *testing.T) {
+	m := NewModel(testOptions(3))
+
+	// Without a selection, actions run on the item under the cursor
+	m.HandleKey(Key{Code: KeyDown})
+	if targets := m.Targets(); len(targets) != 1 || targets[0].Name != "mod1" {
+		t.Errorf("Targets() = %+v, want mod1", targets)
+	}
+
+	m.HandleKey(Key{Code: KeyRune, Rune: ' '})
+	m.HandleKey(Key{Code: KeyDown})
+	m.HandleKey(Key{Code: KeyRune, Rune: ' '})
+	if targets := m.Targets(); len(targets) != 2 || targets[0].Name != "mod1" || targets[1].Name != "mod2" {
+		t.Errorf("Targets() = %+v, want mod1 and mod2", targets)
+	}
+
+	m.HandleKey(Key{Code: KeyRune, Rune: 'a'})
+	if got := len(m.Selection()); got != 3 {
+		t.Errorf("selected %d items after a, want 3", got)
+	}
+	m.HandleKey(Key{Code: KeyRune, Rune: 'a'})
+	if got := len(m.Selection()); got != 0 {
+		t.Errorf("selected %d items after a twice, want 0", got)
+	}
+}
+
+func TestHandleKey_Effects(t *testing.T) {
+	m := NewModel(testOptions(2))
+
+	if effect, action := m.HandleKey(Key{Code: KeyRune, Rune: 'p'}); effect != EffectRun || action != "plan" {
+		t.Errorf("p = %v %q, want run plan", effect, action)
+	}
+	if effect, _ := m.HandleKey(Key{Code: KeyRune, Rune: 'x'}); effect != EffectNone {
+		t.Errorf("x = %v, want none", effect)
+	}
+
+	// Actions and q are blocked while running, Ctrl+C isn't
+	m.SetRunning("plan")
+	if effect, _ := m.HandleKey(Key{Code: KeyRune, Rune: 'f'}); effect != EffectNone {
+		t.Errorf("f while running = %v, want none", effect)
+	}
+	if effect, _ := m.HandleKey(Key{Code: KeyRune, Rune: 'q'}); effect != EffectNone {
+		t.Errorf("q while running = %v, want none", effect)
+	}
+	if effect, _ := m.HandleKey(Key{Code: KeyCtrlC}); effect != EffectQuit {
+		t.Errorf("ctrl+c while running = %v, want quit", effect)
+	}
+
+	m.SetRunning("")
+	if effect, _ := m.HandleKey(Key{Code: KeyRune, Rune: 'q'}); effect != EffectQuit {
+		t.Errorf("q = %v, want quit", effect)
+	}
+
+	// Nothing to run on without items
+	empty := NewModel(testOptions(0))
+	if effect, _ := empty.HandleKey(Key{Code: KeyRune, Rune: 'f'}); effect != EffectNone {
+		t.Errorf("f without items = %v, want none", effect)
+	}
+}
+
+func TestView(t *testing.T) {
+	m := NewModel(testOptions(30))
+	m.HandleKey(Key{Code: KeyRune, Rune: ' '})
+	for range 25 {
+		m.HandleKey(Key{Code: KeyDown})
+	}
+	m.AppendLog("[mod0] Success! The configuration is valid.")
+
+	lines := m.View(80, 24)
+	if len(lines) != 24 {
+		t.Fatalf("View() returned %d lines, want 24", len(lines))
+	}
+	for i, line := range lines {
+		if n := len([]rune(line)); n != 80 {
+			t.Errorf("line %d is %d cells wide, want 80", i, n)
+		}
+	}
+
+	view := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"30 module(s), 1 selected",
+		"> [ ] mod25 (component)", // Scrolled to the cursor
+		"Module: mod25",
+		"[mod0] Success!",
+		"f fmt",
+		"q quit",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected view to contain %q, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "mod0 (component)") {
+		t.Errorf("expected mod0 to be scrolled out of the list, got:\n%s", view)
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"abc", 5, "abc  "},
+		{"abcdef", 3, "abc"},
+		{"ü│é", 2, "ü│"},
+		// Escape sequences take no cells and are reset at the end
+		{"\x1b[36mdns |\x1b[0m ok", 9, "\x1b[36mdns |\x1b[0m ok\x1b[0m "},
+		{"\x1b[36mdns |\x1b[0m ok", 3, "\x1b[36mdns\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := fit(tt.in, tt.width); got != tt.want {
+			t.Errorf("fit(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestLogWriter(t *testing.T) {
+	m := NewModel(testOptions(1))
+	changes := 0
+	w := &logWriter{model: m, changed: func() { changes++ }}
+
+	_, _ = w.Write([]byte("[mod0] one\r\n[mod0] tw"))
+	_, _ = w.Write([]byte("o\n[mod0] three"))
+	w.Flush()
+
+	want := []string{"[mod0] one", "[mod0] two", "[mod0] three"}
+	if strings.Join(m.log, "|") != strings.Join(want, "|") {
+		t.Errorf("log = %q, want %q", m.log, want)
+	}
+	if changes != 2 {
+		t.Errorf("changed called %d times, want 2", changes)
+	}
+}
+
+func TestAppendLog_Limit(t *testing.T) {
+	m := NewModel(testOptions(1))
+	for i := range maxLogLines + 5 {
+		m.AppendLog(fmt.Sprint(i))
+	}
+	if len(m.log) != maxLogLines || m.log[0] != "5" {
+		t.Errorf("kept %d lines starting at %q, want %d starting at 5", len(m.log), m.log[0], maxLogLines)
+	}
+}
+
+func TestRun_NotTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if err := Run(r, w, testOptions(1)); !errors.Is(err, ErrNotTerminal) {
+		t.Errorf("Run() = %v, want ErrNotTerminal", err)
+	}
+}
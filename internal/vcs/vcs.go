@@ -0,0 +1,167 @@
+// Package vcs detects the version control system of a working copy and the
+// motf features it supports. Git repositories, and Jujutsu and Sapling
+// working copies that keep a .git directory, are read through internal/git;
+// other directories work without the features that need version control.
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/TechnicallyJoe/terraform-motf/internal/git"
+)
+
+// Kind is a version control system
+type Kind string
+
+// Version control systems motf detects
+const (
+	KindGit     Kind = "git"
+	KindJujutsu Kind = "jujutsu"
+	KindSapling Kind = "sapling"
+	KindNone    Kind = "none"
+)
+
+// Capability is a motf feature that depends on version control
+type Capability string
+
+// Capabilities, in the order motf doctor reports them
+const (
+	CapChanges Capability = "changes" // --changed and motf changed
+	CapPatch   Capability = "patch"   // motf changed --patch, which needs the git CLI
+	CapRelease Capability = "release" // motf release commits and tags, which need the git CLI
+)
+
+// Capabilities are all capabilities
+var Capabilities = []Capability{CapChanges, CapPatch, CapRelease}
+
+// ErrUnsupported is returned for features the working copy doesn't support
+var ErrUnsupported = errors.New("not supported without a git repository")
+
+// Range selects the changes ChangedFiles returns: the commits since Since,
+// the commits between From and To, or otherwise the changes compared to Base,
+// including uncommitted changes.
+type Range struct {
+	Base      string    // Ref to compare with
+	MergeBase bool      // Compare with the merge base of Base and HEAD instead
+	Since     time.Time // Commits on the current branch since this time
+	From, To  string    // Commits between these refs
+}
+
+// Repository is a working copy under version control, or a directory without
+// it.
+type Repository interface {
+	// Kind returns the version control system
+	Kind() Kind
+	// Root returns the root of the working copy, or "" without version control
+	Root() string
+	// Has reports whether the working copy supports a capability
+	Has(c Capability) bool
+	// Reason explains why capabilities are missing, or "" when all are supported
+	Reason() string
+	// ChangedFiles returns the files, slash-separated and relative to Root,
+	// changed in r
+	ChangedFiles(r Range) ([]string, error)
+}
+
+// Detect returns the working copy containing dir, looking for the .jj, .sl,
+// and .git directories of Jujutsu, Sapling, and git in dir and its parents.
+// Jujutsu and Sapling are read through git when they keep a .git directory:
+// colocated Jujutsu repositories and Sapling repositories cloned with --git.
+func Detect(dir string) Repository {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return none{reason: fmt.Sprintf("failed to resolve %s: %v", dir, err)}
+	}
+	for d := abs; ; d = filepath.Dir(d) {
+		_, hasGit := git.GitDir(d)
+		switch {
+		case exists(filepath.Join(d, ".jj")):
+			if hasGit {
+				return newGitRepository(KindJujutsu, d)
+			}
+			return none{kind: KindJujutsu, root: d, reason: "Jujutsu repository without a .git directory (run 'jj git init --colocate')"}
+		case exists(filepath.Join(d, ".sl")):
+			if hasGit {
+				return newGitRepository(KindSapling, d)
+			}
+			return none{kind: KindSapling, root: d, reason: "Sapling repository without a .git directory (clone with 'sl clone --git')"}
+		case hasGit:
+			return newGitRepository(KindGit, d)
+		}
+		if filepath.Dir(d) == d {
+			return none{reason: "no version control found in " + abs + " or its parents"}
+		}
+	}
+}
+
+// exists reports whether path exists.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// gitRepository is a working copy read through git
+type gitRepository struct {
+	kind   Kind
+	root   string
+	gitCLI bool // Whether the git CLI is on PATH
+}
+
+func newGitRepository(kind Kind, root string) gitRepository {
+	_, err := exec.LookPath("git")
+	return gitRepository{kind: kind, root: root, gitCLI: err == nil}
+}
+
+func (r gitRepository) Kind() Kind   { return r.kind }
+func (r gitRepository) Root() string { return r.root }
+
+func (r gitRepository) Has(c Capability) bool {
+	return c == CapChanges || r.gitCLI
+}
+
+func (r gitRepository) Reason() string {
+	if !r.gitCLI {
+		return "the git CLI isn't on PATH"
+	}
+	return ""
+}
+
+func (r gitRepository) ChangedFiles(rng Range) ([]string, error) {
+	switch {
+	case !rng.Since.IsZero():
+		return git.GetChangedFilesSince(r.root, rng.Since)
+	case rng.From != "":
+		to := rng.To
+		if to == "" {
+			to = "HEAD"
+		}
+		return git.GetChangedFilesBetween(r.root, rng.From, to)
+	}
+	return git.GetChangedFiles(r.root, rng.Base, rng.MergeBase)
+}
+
+// none is a directory without supported version control
+type none struct {
+	kind   Kind   // KindNone, or a version control system that can't be read
+	root   string // Root of the unsupported working copy
+	reason string
+}
+
+func (n none) Kind() Kind {
+	if n.kind == "" {
+		return KindNone
+	}
+	return n.kind
+}
+
+func (n none) Root() string        { return n.root }
+func (n none) Has(Capability) bool { return false }
+func (n none) Reason() string      { return n.reason }
+func (n none) ChangedFiles(Range) ([]string, error) {
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, n.reason)
+}
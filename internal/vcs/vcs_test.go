@@ -0,0 +1,131 @@
+package vcs
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func mkdirs(t *testing.T, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		markers []string
+		kind    Kind
+		changes bool
+	}{
+		{"git", []string{".git"}, KindGit, true},
+		{"colocated jujutsu", []string{".jj", ".git"}, KindJujutsu, true},
+		{"jujutsu without git", []string{".jj"}, KindJujutsu, false},
+		{"sapling with git", []string{".sl", ".git"}, KindSapling, true},
+		{"sapling without git", []string{".sl"}, KindSapling, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, marker := range tt.markers {
+				mkdirs(t, filepath.Join(root, marker))
+			}
+			sub := filepath.Join(root, "infra", "components")
+			mkdirs(t, sub)
+
+			repo := Detect(sub)
+			if repo.Kind() != tt.kind {
+				t.Errorf("Kind() = %s, want %s", repo.Kind(), tt.kind)
+			}
+			if repo.Root() != root {
+				t.Errorf("Root() = %s, want %s", repo.Root(), root)
+			}
+			if repo.Has(CapChanges) != tt.changes {
+				t.Errorf("Has(CapChanges) = %t, want %t", repo.Has(CapChanges), tt.changes)
+			}
+			if !tt.changes && repo.Reason() == "" {
+				t.Error("expected a reason for missing capabilities")
+			}
+		})
+	}
+}
+
+func TestDetect_None(t *testing.T) {
+	dir := t.TempDir()
+	if Detect(dir).Kind() != KindNone {
+		t.Skip("temporary directory is under version control")
+	}
+
+	repo := Detect(dir)
+	if repo.Kind() != KindNone || repo.Root() != "" {
+		t.Errorf("Detect() = %s at %q, want none", repo.Kind(), repo.Root())
+	}
+	for _, c := range Capabilities {
+		if repo.Has(c) {
+			t.Errorf("expected no %s capability", c)
+		}
+	}
+	if _, err := repo.ChangedFiles(Range{Base: "main"}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ChangedFiles() error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestGitRepository_ChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	write := func(name string) {
+		t.Helper()
+		mkdirs(t, filepath.Dir(filepath.Join(root, name)))
+		if err := os.WriteFile(filepath.Join(root, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-b", "main")
+	write("README.md")
+	git("add", ".")
+	git("commit", "-m", "initial")
+	git("tag", "base")
+	write("components/a/main.tf")
+	git("add", ".")
+	git("commit", "-m", "add a")
+	write("components/b/main.tf")
+
+	repo := Detect(root)
+	if repo.Kind() != KindGit || !repo.Has(CapPatch) {
+		t.Fatalf("Detect() = %s, patch %t", repo.Kind(), repo.Has(CapPatch))
+	}
+
+	// Compared with a ref, uncommitted changes are included
+	files, err := repo.ChangedFiles(Range{Base: "base"})
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("ChangedFiles() = %v, want both modules", files)
+	}
+
+	// A commit range only has the committed changes
+	files, err = repo.ChangedFiles(Range{From: "base"})
+	if err != nil {
+		t.Fatalf("ChangedFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0] != "components/a/main.tf" {
+		t.Errorf("ChangedFiles() = %v, want [components/a/main.tf]", files)
+	}
+}
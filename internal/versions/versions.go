@@ -0,0 +1,80 @@
+// Package versions checks Terraform version constraints, such as the
+// required_version of modules and the versions of their required_providers.
+package versions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	version "github.com/hashicorp/go-version"
+)
+
+// versionPattern matches the versions in a constraint
+var versionPattern = regexp.MustCompile(`\d+(?:\.\d+){0,2}`)
+
+// Compatible reports whether a version satisfies all of constraints, e.g.
+// ">= 3.0" and "~> 3.5" are compatible, ">= 3.0" and "~> 2.0" aren't. Empty
+// constraints allow any version. Pre-releases aren't considered.
+func Compatible(constraints ...string) (bool, error) {
+	var parsed []version.Constraints
+	candidates := []*version.Version{version.Must(version.NewVersion("0.0.0"))}
+	for _, c := range constraints {
+		if strings.TrimSpace(c) == "" {
+			continue
+		}
+		p, err := version.NewConstraint(c)
+		if err != nil {
+			return false, fmt.Errorf("invalid version constraint '%s': %w", c, err)
+		}
+		parsed = append(parsed, p)
+		for _, v := range versionPattern.FindAllString(c, -1) {
+			candidates = append(candidates, boundaries(v)...)
+		}
+	}
+
+	// Constraints bound the allowed versions by the versions they mention, so
+	// if any version satisfies them, one at or right after a bound does.
+	for _, candidate := range candidates {
+		if allow(parsed, candidate) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Validate returns an error if constraint isn't a valid version constraint.
+// An empty constraint is valid.
+func Validate(constraint string) error {
+	if strings.TrimSpace(constraint) == "" {
+		return nil
+	}
+	if _, err := version.NewConstraint(constraint); err != nil {
+		return fmt.Errorf("invalid version constraint '%s': %w", constraint, err)
+	}
+	return nil
+}
+
+// boundaries returns v and the next patch, minor, and major versions.
+func boundaries(v string) []*version.Version {
+	parsed, err := version.NewVersion(v)
+	if err != nil {
+		return nil
+	}
+	s := parsed.Segments()
+	result := []*version.Version{parsed}
+	for _, next := range [][3]int{{s[0], s[1], s[2] + 1}, {s[0], s[1] + 1, 0}, {s[0] + 1, 0, 0}} {
+		result = append(result, version.Must(version.NewVersion(fmt.Sprintf("%d.%d.%d", next[0], next[1], next[2]))))
+	}
+	return result
+}
+
+// allow reports whether v satisfies all of constraints.
+func allow(constraints []version.Constraints, v *version.Version) bool {
+	for _, c := range constraints {
+		if !c.Check(v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package versions
+
+import "testing"
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		constraints []string
+		want        bool
+	}{
+		{[]string{">= 3.0", "~> 3.5"}, true},
+		{[]string{">= 3.0", "~> 2.0"}, false},
+		{[]string{"~> 2.1", ">= 2.5, < 2.6"}, true},
+		{[]string{"> 2.1.9", "< 2.2"}, true},
+		{[]string{"> 2.0.0", "< 2.0.1"}, false},
+		{[]string{"= 1.5.0", "1.5.0"}, true},
+		{[]string{"= 1.5.0", "1.5.1"}, false},
+		{[]string{"< 1.0", "< 2.0"}, true},
+		{[]string{"", ">= 1.0"}, true},
+		{nil, true},
+	}
+	for _, tt := range tests {
+		got, err := Compatible(tt.constraints...)
+		if err != nil {
+			t.Fatalf("Compatible(%q) error = %v", tt.constraints, err)
+		}
+		if got != tt.want {
+			t.Errorf("Compatible(%q) = %t, want %t", tt.constraints, got, tt.want)
+		}
+	}
+}
+
+func TestCompatible_Invalid(t *testing.T) {
+	if _, err := Compatible(">= banana"); err == nil {
+		t.Error("expected an error for an invalid constraint")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, c := range []string{"", ">= 1.6", "~> 1.5.0, != 1.5.3"} {
+		if err := Validate(c); err != nil {
+			t.Errorf("Validate(%q) error = %v", c, err)
+		}
+	}
+	if err := Validate(">= banana"); err == nil {
+		t.Error("expected an error for an invalid constraint")
+	}
+}
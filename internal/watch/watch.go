@@ -0,0 +1,170 @@
+// Package watch watches module directories and reports which modules had
+// their files changed, batched after a quiet period, for 'motf watch'.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// skipDirs are directories whose changes never trigger a run: terraform
+// state and caches, and version control
+var skipDirs = map[string]bool{
+	".terraform": true,
+	".git":       true,
+	".jj":        true,
+	".sl":        true,
+}
+
+// extensions are the suffixes of files whose changes trigger a run
+var extensions = []string{".tf", ".tf.json", ".tfvars", ".tfvars.json", ".tftest.hcl", ".go"}
+
+// Relevant reports whether a change to the file at path should trigger a
+// run. Lock files are left out, as init rewrites them.
+func Relevant(path string) bool {
+	name := filepath.Base(path)
+	if name == ".terraform.lock.hcl" || strings.HasPrefix(name, ".#") {
+		return false
+	}
+	for _, ext := range extensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// Watcher watches the files of a set of modules.
+type Watcher struct {
+	fs       *fsnotify.Watcher
+	modules  []string // Module directories, longest first to match nested modules
+	debounce time.Duration
+}
+
+// New returns a watcher of the module directories and their subdirectories,
+// reporting changes after debounce passed without further changes.
+func New(modules []string, debounce time.Duration) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	w := &Watcher{fs: fsw, debounce: debounce}
+	for _, dir := range modules {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			_ = fsw.Close()
+			return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+		}
+		w.modules = append(w.modules, abs)
+		if err := w.addTree(abs); err != nil {
+			_ = fsw.Close()
+			return nil, err
+		}
+	}
+	sort.Slice(w.modules, func(i, j int) bool {
+		return len(w.modules[i]) > len(w.modules[j])
+	})
+	return w, nil
+}
+
+// addTree watches dir and its subdirectories, except skipDirs.
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if skipDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if err := w.fs.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+// moduleOf returns the module directory containing path, or "" if none
+// does or it's in one of skipDirs.
+func (w *Watcher) moduleOf(path string) string {
+	for _, mod := range w.modules {
+		rel, err := filepath.Rel(mod, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		for _, part := range strings.Split(filepath.Dir(rel), string(filepath.Separator)) {
+			if skipDirs[part] {
+				return ""
+			}
+		}
+		return mod
+	}
+	return ""
+}
+
+// Run calls fn with the directories of the modules whose files changed,
+// sorted, once debounce passed without further changes. fn runs on the
+// watching goroutine: changes made while it runs are reported after it
+// returns. Run returns when ctx is done, or with the watcher's error.
+func (w *Watcher) Run(ctx context.Context, fn func(modules []string)) error {
+	pending := map[string]bool{}
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return nil
+			}
+			// New directories, e.g. an added example, are watched too
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() && w.moduleOf(event.Name) != "" {
+					_ = w.addTree(event.Name)
+				}
+			}
+			if event.Op == fsnotify.Chmod || !Relevant(event.Name) {
+				continue
+			}
+			if mod := w.moduleOf(event.Name); mod != "" {
+				pending[mod] = true
+				timer.Reset(w.debounce)
+			}
+
+		case <-timer.C:
+			modules := make([]string, 0, len(pending))
+			for mod := range pending {
+				modules = append(modules, mod)
+			}
+			sort.Strings(modules)
+			clear(pending)
+			fn(modules)
+
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("file watcher failed: %w", err)
+		}
+	}
+}
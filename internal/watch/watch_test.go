@@ -0,0 +1,137 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRelevant(t *testing.T) {
+	tests := map[string]bool{
+		"main.tf":                         true,
+		"main.tf.json":                    true,
+		"prod.tfvars":                     true,
+		"tests/basic.tftest.hcl":          true,
+		"test/module_test.go":             true,
+		".terraform.lock.hcl":             false,
+		"README.md":                       false,
+		"main.tf~":                        false,
+		".#main.tf":                       false,
+		"terraform.tfstate":               false,
+		"examples/basic/variables.tf":     true,
+		"examples/basic/terraform.tfvars": true,
+	}
+	for path, want := range tests {
+		if got := Relevant(path); got != want {
+			t.Errorf("Relevant(%q) = %t, want %t", path, got, want)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("# test\n"), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestModuleOf(t *testing.T) {
+	root := t.TempDir()
+	parent := filepath.Join(root, "components", "vnet")
+	nested := filepath.Join(parent, "modules", "subnet")
+	writeFile(t, filepath.Join(parent, "main.tf"))
+	writeFile(t, filepath.Join(nested, "main.tf"))
+
+	w, err := New([]string{parent, nested}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	tests := map[string]string{
+		filepath.Join(parent, "main.tf"):                       parent,
+		filepath.Join(parent, "examples", "basic", "main.tf"):  parent,
+		filepath.Join(nested, "main.tf"):                       nested,
+		filepath.Join(parent, ".terraform", "modules", "x.tf"): "",
+		filepath.Join(root, "other", "main.tf"):                "",
+		filepath.Join(root, "components", "vnet2", "main.tf"):  "",
+	}
+	for path, want := range tests {
+		if got := w.moduleOf(path); got != want {
+			t.Errorf("moduleOf(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRun_DebouncesChanges(t *testing.T) {
+	root := t.TempDir()
+	dns := filepath.Join(root, "dns")
+	vnet := filepath.Join(root, "vnet")
+	other := filepath.Join(root, "other")
+	for _, dir := range []string{dns, vnet, other} {
+		writeFile(t, filepath.Join(dir, "main.tf"))
+	}
+
+	w, err := New([]string{dns, vnet}, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = w.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	batches := make(chan []string, 10)
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx, func(modules []string) { batches <- modules })
+	}()
+
+	// Changes to several modules in quick succession run once
+	writeFile(t, filepath.Join(vnet, "variables.tf"))
+	writeFile(t, filepath.Join(dns, "main.tf"))
+	writeFile(t, filepath.Join(dns, "README.md"))
+	writeFile(t, filepath.Join(other, "main.tf"))
+	// New directories are watched too
+	if err := os.MkdirAll(filepath.Join(vnet, "examples", "basic"), 0755); err != nil {
+		t.Fatalf("failed to create example: %v", err)
+	}
+
+	select {
+	case got := <-batches:
+		if want := []string{dns, vnet}; !reflect.DeepEqual(got, want) {
+			t.Errorf("batch = %v, want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for changes")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	writeFile(t, filepath.Join(vnet, "examples", "basic", "main.tf"))
+	select {
+	case got := <-batches:
+		if want := []string{vnet}; !reflect.DeepEqual(got, want) {
+			t.Errorf("batch = %v, want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a change in a new directory")
+	}
+
+	// Irrelevant files don't trigger a run
+	writeFile(t, filepath.Join(dns, "notes.txt"))
+	select {
+	case got := <-batches:
+		t.Errorf("unexpected batch %v", got)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+}